@@ -1,6 +1,7 @@
 package api_client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -17,6 +18,7 @@ type FetchConfig struct {
 	Method     string
 	CustomFunc func(*http.Response, *api_formatter.ClientResponse) (any, error)
 	Body       any
+	Context    context.Context
 }
 
 // WithHeaders sets custom headers for the request
@@ -47,6 +49,15 @@ func WithCustomFunc(fn func(*http.Response, *api_formatter.ClientResponse) (any,
 	}
 }
 
+// WithContext binds the request to ctx: it is cancelled with ctx and its
+// timeout is capped to ctx's remaining deadline. Pass the *request.Context
+// of the calling handler to share its budget.
+func WithContext(ctx context.Context) FetchOption {
+	return func(cfg *FetchConfig) {
+		cfg.Context = ctx
+	}
+}
+
 // WithBody sets the request body for POST, PUT, PATCH
 func WithBody(body any) FetchOption {
 	return func(cfg *FetchConfig) {
@@ -72,9 +83,13 @@ func FetchAndCast[T any](client *ClientRouter, path string, opts ...FetchOption)
 
 	var zero T
 
-	resp, err := client.Method(method, path, cfg.Body, cfg.Headers)
+	ctx := cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	resp, err := client.MethodContext(ctx, method, path, cfg.Body, cfg.Headers)
 	if err != nil {
-		return zero, fmt.Errorf("failed to fetch: %v", err)
+		return zero, fmt.Errorf("failed to fetch: %w", err)
 	}
 
 	formatter := cfg.Formatter
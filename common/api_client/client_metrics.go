@@ -0,0 +1,63 @@
+package api_client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// Outbound metric names emitted by ClientRouter
+const (
+	METRIC_OUTBOUND_REQUESTS = "outbound_requests_total"
+	METRIC_OUTBOUND_DURATION = "outbound_request_duration_seconds"
+)
+
+// Global metrics resolver set by lokstra_registry at initialization
+var metricsResolver func() serviceapi.Metrics
+
+// SetMetricsResolver sets the resolver for the registered metrics service.
+// Called by lokstra_registry during initialization to avoid circular dependency
+func SetMetricsResolver(resolver func() serviceapi.Metrics) {
+	metricsResolver = resolver
+}
+
+// metrics returns the metrics sink for this client, or nil (no-op)
+func (c *ClientRouter) metrics() serviceapi.Metrics {
+	if c.Metrics != nil {
+		return c.Metrics
+	}
+	if metricsResolver != nil {
+		return metricsResolver()
+	}
+	return nil
+}
+
+func (c *ClientRouter) serviceLabel() string {
+	if c.ServiceName != "" {
+		return c.ServiceName
+	}
+	return c.RouterName
+}
+
+// observeRequest records outbound request count and duration, labeled with
+// the correlation of ctx (see request.Correlation.Labels).
+// Status is "error" when no response was received.
+func (c *ClientRouter) observeRequest(ctx context.Context, method string, resp *http.Response, err error, dur time.Duration) {
+	m := c.metrics()
+	if m == nil {
+		return
+	}
+	status := "error"
+	if err == nil && resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	labels := request.CorrelationFromContext(ctx).Labels(serviceapi.Labels{
+		"service": c.serviceLabel(), "method": method, "status": status,
+	})
+	m.IncCounter(METRIC_OUTBOUND_REQUESTS, labels)
+	m.ObserveHistogram(METRIC_OUTBOUND_DURATION, dur.Seconds(), labels)
+}
@@ -0,0 +1,126 @@
+package api_client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/primadi/lokstra/common/api_client"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+type countingMetrics struct {
+	mu         sync.Mutex
+	counters   map[string]int
+	histograms map[string]int
+	labels     []serviceapi.Labels
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{counters: map[string]int{}, histograms: map[string]int{}}
+}
+
+func (m *countingMetrics) IncCounter(name string, labels serviceapi.Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name]++
+	m.labels = append(m.labels, labels)
+}
+
+func (m *countingMetrics) ObserveHistogram(name string, _ float64, _ serviceapi.Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.histograms[name]++
+}
+
+func (m *countingMetrics) SetGauge(string, float64, serviceapi.Labels) {}
+
+func TestClientRouter_OutboundMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+
+	metrics := newCountingMetrics()
+	client := &api_client.ClientRouter{
+		FullURL:     server.URL,
+		ServiceName: "order-service",
+		Metrics:     metrics,
+	}
+
+	resp, err := client.GET("/orders", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	resp, err = client.GET("/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := metrics.counters[api_client.METRIC_OUTBOUND_REQUESTS]; got != 2 {
+		t.Errorf("Expected 2 outbound requests, got %d", got)
+	}
+	if got := metrics.histograms[api_client.METRIC_OUTBOUND_DURATION]; got != 2 {
+		t.Errorf("Expected 2 duration observations, got %d", got)
+	}
+
+	first, second := metrics.labels[0], metrics.labels[1]
+	if first["service"] != "order-service" || first["method"] != "GET" || first["status"] != "200" {
+		t.Errorf("Unexpected labels: %v", first)
+	}
+	if second["status"] != "404" {
+		t.Errorf("Expected status 404 label, got %v", second)
+	}
+}
+
+func TestClientRouter_NoMetricsIsNoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &api_client.ClientRouter{FullURL: server.URL}
+	resp, err := client.GET("/ping", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestClientRouter_PropagatesCorrelation(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+
+	metrics := newCountingMetrics()
+	client := &api_client.ClientRouter{FullURL: server.URL, ServiceName: "order-service", Metrics: metrics}
+
+	ctx := request.WithRequestID(context.Background(), "req-1")
+	ctx = request.WithTenant(ctx, "acme")
+	ctx = request.WithTrace(ctx, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	resp, err := client.MethodContext(ctx, "GET", "/orders", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got.Get(request.HeaderRequestID) != "req-1" {
+		t.Errorf("Expected the request id forwarded, got %q", got.Get(request.HeaderRequestID))
+	}
+	if got.Get("traceparent") != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Expected the trace forwarded, got %q", got.Get("traceparent"))
+	}
+	if labels := metrics.labels[0]; labels["tenant_id"] != "acme" {
+		t.Errorf("Expected the tenant label, got %v", labels)
+	}
+}
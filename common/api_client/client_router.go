@@ -2,6 +2,7 @@ package api_client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,7 +11,9 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/primadi/lokstra/core/request"
 	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/serviceapi"
 )
 
 // default timeout for HTTP client requests
@@ -24,51 +27,80 @@ type ClientRouter struct {
 	IsLocal    bool
 	Router     router.Router
 
+	// Timeout bounds each call (DefaultHTTPTimeout when 0), capped to the
+	// caller's deadline (see MethodContext)
 	Timeout time.Duration
+
+	// Transport carries remote calls. Clients passing the same transport
+	// share its connection pool; nil uses DefaultTransport (see NewTransport).
+	Transport http.RoundTripper
+
+	// ServiceName labels outbound metrics (defaults to RouterName)
+	ServiceName string
+	// Metrics receives outbound metrics (nil uses the registered metrics service, if any)
+	Metrics serviceapi.Metrics
 }
 
 // performs a GET request to the router with optional headers
 func (c *ClientRouter) GET(path string, headers map[string]string) (*http.Response, error) {
-	return c.makeRequest("GET", path, nil, headers)
+	return c.makeRequest(context.Background(), "GET", path, nil, headers)
 }
 
 // performs a POST request to the router with optional headers
 func (c *ClientRouter) POST(path string, body any, headers map[string]string) (*http.Response, error) {
-	return c.makeRequest("POST", path, body, headers)
+	return c.makeRequest(context.Background(), "POST", path, body, headers)
 }
 
 // performs a PUT request to the router with optional headers
 func (c *ClientRouter) PUT(path string, body any, headers map[string]string) (*http.Response, error) {
-	return c.makeRequest("PUT", path, body, headers)
+	return c.makeRequest(context.Background(), "PUT", path, body, headers)
 }
 
 // performs a PATCH request to the router with optional headers
 func (c *ClientRouter) PATCH(path string, body any, headers map[string]string) (*http.Response, error) {
-	return c.makeRequest("PATCH", path, body, headers)
+	return c.makeRequest(context.Background(), "PATCH", path, body, headers)
 }
 
 // performs a DELETE request to the router with optional headers
 func (c *ClientRouter) DELETE(path string, headers map[string]string) (*http.Response, error) {
-	return c.makeRequest("DELETE", path, nil, headers)
+	return c.makeRequest(context.Background(), "DELETE", path, nil, headers)
 }
 
 func (c *ClientRouter) Method(method, path string, body any, headers map[string]string) (*http.Response, error) {
-	return c.makeRequest(method, path, body, headers)
+	return c.makeRequest(context.Background(), method, path, body, headers)
+}
+
+// MethodContext is like Method, bound to ctx: the call is cancelled with ctx
+// and its timeout is capped to ctx's remaining deadline (see EffectiveTimeout).
+// Pass the *request.Context so downstream calls share the request budget.
+func (c *ClientRouter) MethodContext(ctx context.Context, method, path string, body any,
+	headers map[string]string) (*http.Response, error) {
+	return c.makeRequest(ctx, method, path, body, headers)
 }
 
 // makeRequest handles both local (router.ServeHTTP) and remote (HTTP) calls, with headers
-func (c *ClientRouter) makeRequest(method, path string, body any, headers map[string]string) (*http.Response, error) {
-	if c.IsLocal && c.Router != nil {
+func (c *ClientRouter) makeRequest(ctx context.Context, method, path string, body any,
+	headers map[string]string) (*http.Response, error) {
+	start := time.Now()
+	var resp *http.Response
+	var err error
+	if timeout := EffectiveTimeout(ctx, c.Timeout); timeout <= 0 {
+		// No budget left: don't start work nobody will wait for
+		err = errBudgetExhausted
+	} else if c.IsLocal && c.Router != nil {
 		// Use router.ServeHTTP for same-server communication (faster than httptest)
-		return c.makeLocalRequest(method, path, body, headers)
+		resp, err = c.makeLocalRequest(ctx, timeout, method, path, body, headers)
+	} else {
+		// Use HTTP for remote communication
+		resp, err = c.makeRemoteRequest(ctx, timeout, method, path, body, headers)
 	}
-	// Use HTTP for remote communication
-	return c.makeRemoteRequest(method, path, body, headers)
+	c.observeRequest(ctx, method, resp, err, time.Since(start))
+	return resp, err
 }
 
 // makeLocalRequest uses router.ServeHTTP for zero-overhead local calls, with headers
-func (c *ClientRouter) makeLocalRequest(method, path string, body any,
-	headers map[string]string) (*http.Response, error) {
+func (c *ClientRouter) makeLocalRequest(ctx context.Context, timeout time.Duration,
+	method, path string, body any, headers map[string]string) (*http.Response, error) {
 	var bodyReader io.Reader
 
 	if body != nil {
@@ -79,11 +111,17 @@ func (c *ClientRouter) makeLocalRequest(method, path string, body any,
 		bodyReader = bytes.NewReader(jsonData)
 	}
 
-	// Create HTTP request
-	req := httptest.NewRequest(method, path, bodyReader)
+	// Create HTTP request, bounded like a remote call
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, method, path, bodyReader)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	// Forward the caller's request id and trace
+	for k, v := range request.CorrelationFromContext(ctx).Headers() {
+		req.Header.Set(k, v)
+	}
 	// Set custom headers
 	for k, v := range headers {
 		req.Header.Set(k, v)
@@ -99,8 +137,8 @@ func (c *ClientRouter) makeLocalRequest(method, path string, body any,
 }
 
 // makeRemoteRequest uses standard HTTP client for remote calls, with headers
-func (c *ClientRouter) makeRemoteRequest(method, path string, body any,
-	headers map[string]string) (*http.Response, error) {
+func (c *ClientRouter) makeRemoteRequest(ctx context.Context, timeout time.Duration,
+	method, path string, body any, headers map[string]string) (*http.Response, error) {
 	var bodyReader io.Reader
 
 	if body != nil {
@@ -116,7 +154,7 @@ func (c *ClientRouter) makeRemoteRequest(method, path string, body any,
 	if err != nil {
 		return nil, fmt.Errorf("failed to join URL path: %w", err)
 	}
-	req, err := http.NewRequest(method, urlPath, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, urlPath, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -124,18 +162,22 @@ func (c *ClientRouter) makeRemoteRequest(method, path string, body any,
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	// Forward the caller's request id and trace
+	for k, v := range request.CorrelationFromContext(ctx).Headers() {
+		req.Header.Set(k, v)
+	}
 	// Set custom headers
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
-	// Make HTTP call with timeout
-	timeout := c.Timeout
-	if timeout <= 0 {
-		timeout = DefaultHTTPTimeout
-	}
+	// Make HTTP call with timeout (already capped to the caller's budget)
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: c.Transport,
+	}
+	if client.Transport == nil {
+		client.Transport = DefaultTransport
 	}
 
 	return client.Do(req)
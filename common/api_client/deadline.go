@@ -0,0 +1,31 @@
+package api_client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DeadlineBuffer is kept from the caller's remaining budget so the caller
+// still has time to handle a downstream timeout before its own deadline
+var DeadlineBuffer = 50 * time.Millisecond
+
+// EffectiveTimeout returns timeout (DefaultHTTPTimeout when <= 0) capped to
+// the time left before ctx's deadline minus DeadlineBuffer.
+// A result <= 0 means the budget is already exhausted.
+func EffectiveTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	if ctx == nil {
+		return timeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline)-DeadlineBuffer)
+	}
+	return timeout
+}
+
+// errBudgetExhausted is returned without calling downstream when the
+// caller's deadline leaves no time for the call
+var errBudgetExhausted = fmt.Errorf("request deadline budget exhausted: %w", context.DeadlineExceeded)
@@ -0,0 +1,90 @@
+package api_client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/common/api_client"
+	"github.com/primadi/lokstra/core/request"
+)
+
+func TestEffectiveTimeout(t *testing.T) {
+	if got := api_client.EffectiveTimeout(context.Background(), 5*time.Second); got != 5*time.Second {
+		t.Errorf("No deadline: got %v, want 5s", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got := api_client.EffectiveTimeout(ctx, 5*time.Second)
+	if got > time.Second-api_client.DeadlineBuffer || got < 900*time.Millisecond-api_client.DeadlineBuffer {
+		t.Errorf("With 1s deadline: got %v, want just under %v", got, time.Second-api_client.DeadlineBuffer)
+	}
+
+	expired, cancel2 := context.WithTimeout(context.Background(), api_client.DeadlineBuffer/2)
+	defer cancel2()
+	if got := api_client.EffectiveTimeout(expired, 5*time.Second); got > 0 {
+		t.Errorf("Exhausted budget: got %v, want <= 0", got)
+	}
+}
+
+func TestClientRouter_SharesRequestBudget(t *testing.T) {
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer downstream.Close()
+
+	client := &api_client.ClientRouter{FullURL: downstream.URL, Timeout: 10 * time.Second}
+
+	var callErr error
+	var elapsed time.Duration
+	handler := request.NewHandler(func(c *request.Context) error {
+		start := time.Now()
+		_, callErr = api_client.FetchAndCast[any](client, "/slow", api_client.WithContext(c))
+		elapsed = time.Since(start)
+		return callErr
+	}, func(c *request.Context) error {
+		defer c.SetTimeout(300 * time.Millisecond)()
+		return c.Next()
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+
+	if callErr == nil {
+		t.Fatal("Expected the downstream call to time out")
+	}
+	if elapsed >= time.Second {
+		t.Errorf("Downstream call took %v, should be capped by the 300ms request budget", elapsed)
+	}
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Status = %d, want 504", w.Code)
+	}
+}
+
+func TestClientRouter_ExhaustedBudgetSkipsCall(t *testing.T) {
+	called := false
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer downstream.Close()
+
+	client := &api_client.ClientRouter{FullURL: downstream.URL}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := client.MethodContext(ctx, "GET", "/x", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if called {
+		t.Error("Downstream should not be called without budget")
+	}
+}
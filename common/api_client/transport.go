@@ -0,0 +1,102 @@
+package api_client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes the connection pool used for remote calls.
+// Zero fields take the DefaultTransportConfig values.
+type TransportConfig struct {
+	// MaxIdleConns bounds idle (keep-alive) connections across all hosts
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost bounds idle connections kept per service host.
+	// net/http keeps only 2, so bursts of service-to-service calls open and
+	// close connections, exhausting ephemeral ports (TIME_WAIT).
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost bounds all connections per host; 0 means no limit
+	MaxConnsPerHost int
+
+	// IdleConnTimeout closes idle connections after this duration
+	IdleConnTimeout time.Duration
+
+	// DialTimeout bounds establishing the TCP connection, so an unreachable
+	// host fails fast instead of consuming the whole call timeout
+	DialTimeout time.Duration
+
+	// KeepAlive is the TCP keep-alive period of connections
+	KeepAlive time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake
+	TLSHandshakeTimeout time.Duration
+
+	// TLSConfig is used for https services (e.g. private CAs, client certificates)
+	TLSConfig *tls.Config
+}
+
+func DefaultTransportConfig() *TransportConfig {
+	return &TransportConfig{
+		MaxIdleConns:        256,
+		MaxIdleConnsPerHost: 64,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         5 * time.Second,
+		KeepAlive:           30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// NewTransport creates an http.Transport from cfg. Share the transport
+// between clients calling the same hosts so they reuse connections:
+//
+//	transport := api_client.NewTransport(&api_client.TransportConfig{MaxIdleConnsPerHost: 128})
+//	orders := &api_client.ClientRouter{FullURL: ordersURL, Transport: transport}
+//	payments := &api_client.ClientRouter{FullURL: paymentsURL, Transport: transport}
+func NewTransport(cfg *TransportConfig) *http.Transport {
+	defConfig := DefaultTransportConfig()
+	if cfg == nil {
+		cfg = defConfig
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = defConfig.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = defConfig.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = defConfig.IdleConnTimeout
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defConfig.DialTimeout
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = defConfig.KeepAlive
+	}
+	if cfg.TLSHandshakeTimeout <= 0 {
+		cfg.TLSHandshakeTimeout = defConfig.TLSHandshakeTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		TLSClientConfig:       cfg.TLSConfig,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// DefaultTransport is used by clients without a Transport, so all of them
+// share one connection pool
+var DefaultTransport http.RoundTripper = NewTransport(nil)
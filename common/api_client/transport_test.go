@@ -0,0 +1,107 @@
+package api_client_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/common/api_client"
+)
+
+// countingServer counts the TCP connections opened by clients
+func countingServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv, &conns
+}
+
+func callAndDrain(t *testing.T, c *api_client.ClientRouter) {
+	t.Helper()
+	resp, err := c.GET("/ping", nil)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func TestClientRouter_TimeoutAbortsSlowCall(t *testing.T) {
+	srv, _ := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	client := &api_client.ClientRouter{
+		FullURL:   srv.URL,
+		Timeout:   100 * time.Millisecond,
+		Transport: api_client.NewTransport(nil),
+	}
+	start := time.Now()
+	_, err := client.GET("/slow", nil)
+	if err == nil {
+		t.Fatal("Expected the slow call to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Call took %v, expected it aborted after the 100ms timeout", elapsed)
+	}
+}
+
+func TestClientRouter_ReusesConnections(t *testing.T) {
+	srv, conns := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success"}`))
+	})
+
+	client := &api_client.ClientRouter{
+		FullURL:   srv.URL,
+		Transport: api_client.NewTransport(nil),
+	}
+	for range 20 {
+		callAndDrain(t, client)
+	}
+	if got := conns.Load(); got != 1 {
+		t.Errorf("Expected 20 sequential calls over 1 keep-alive connection, got %d", got)
+	}
+}
+
+func TestClientRouter_SharedTransport(t *testing.T) {
+	srv, conns := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success"}`))
+	})
+
+	transport := api_client.NewTransport(&api_client.TransportConfig{MaxIdleConnsPerHost: 4})
+	orders := &api_client.ClientRouter{FullURL: srv.URL, Transport: transport}
+	payments := &api_client.ClientRouter{FullURL: srv.URL, Transport: transport}
+	for range 5 {
+		callAndDrain(t, orders)
+		callAndDrain(t, payments)
+	}
+	if got := conns.Load(); got != 1 {
+		t.Errorf("Expected clients sharing a transport to share 1 connection, got %d", got)
+	}
+}
+
+func TestNewTransport_Defaults(t *testing.T) {
+	tr := api_client.NewTransport(&api_client.TransportConfig{MaxIdleConnsPerHost: 8})
+	def := api_client.DefaultTransportConfig()
+
+	if tr.MaxIdleConnsPerHost != 8 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want 8", tr.MaxIdleConnsPerHost)
+	}
+	if tr.MaxIdleConns != def.MaxIdleConns || tr.IdleConnTimeout != def.IdleConnTimeout {
+		t.Errorf("Expected defaults for unset fields, got %d / %v", tr.MaxIdleConns, tr.IdleConnTimeout)
+	}
+}
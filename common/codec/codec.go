@@ -0,0 +1,97 @@
+// Package codec provides pluggable value serialization for stores that
+// persist arbitrary Go values (e.g. the kvstore services).
+//
+// Built-in codecs:
+//   - Gob: fast and exact for Go types, readable only by Go programs
+//     (the default of Go-only stores, e.g. kvstore_inmemory)
+//   - JSON: portable across languages, loses some type information
+//     (e.g. numbers in map[string]any decode as float64); the default of
+//     shared stores, e.g. kvstore_redis
+//   - MsgPack: compact binary, portable across languages
+//
+// Other formats are plugged in by name, e.g. xml:
+//
+//	codec.Register(codec.New("xml", xml.Marshal, xml.Unmarshal))
+//
+// after which stores accept `codec: xml` in their config.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sync"
+
+	"github.com/primadi/lokstra/common/json"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec serializes values to bytes and back
+type Codec interface {
+	// Name identifies the codec in configuration
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Built-in codecs
+var (
+	Gob     Codec = New("gob", gobMarshal, gobUnmarshal)
+	JSON    Codec = New("json", json.Marshal, json.Unmarshal)
+	MsgPack Codec = New("msgpack", msgpack.Marshal, msgpack.Unmarshal)
+)
+
+type funcCodec struct {
+	name      string
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+}
+
+func (c *funcCodec) Name() string                       { return c.name }
+func (c *funcCodec) Marshal(v any) ([]byte, error)      { return c.marshal(v) }
+func (c *funcCodec) Unmarshal(data []byte, v any) error { return c.unmarshal(data, v) }
+
+// New creates a codec from marshal/unmarshal functions
+func New(name string, marshal func(v any) ([]byte, error),
+	unmarshal func(data []byte, v any) error) Codec {
+	return &funcCodec{name: name, marshal: marshal, unmarshal: unmarshal}
+}
+
+func gobMarshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobUnmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+var (
+	mu     sync.RWMutex
+	codecs = map[string]Codec{
+		Gob.Name():     Gob,
+		JSON.Name():    JSON,
+		MsgPack.Name(): MsgPack,
+	}
+)
+
+// Register makes a codec available by name (replacing any existing one)
+func Register(c Codec) {
+	mu.Lock()
+	codecs[c.Name()] = c
+	mu.Unlock()
+}
+
+// Get returns the codec registered under name
+func Get(name string) (Codec, error) {
+	mu.RLock()
+	c, ok := codecs[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("codec %q is not registered", name)
+	}
+	return c, nil
+}
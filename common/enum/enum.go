@@ -0,0 +1,115 @@
+package enum
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enum is implemented by typed enums that can validate themselves.
+// The validator checks every field whose type implements Enum, so
+// invalid values produce a field error without a `validate:"oneof=..."` tag.
+type Enum interface {
+	Valid() bool
+}
+
+// Parser is implemented (on the pointer receiver) by enums that can parse
+// and normalize raw input, e.g. case-insensitive values from query strings.
+type Parser interface {
+	Parse(s string) error
+}
+
+// Lister is implemented by enums that can list their allowed values
+// (used in validation error messages).
+type Lister interface {
+	Values() []string
+}
+
+// InvalidError is returned by Set.Parse for unknown values
+type InvalidError struct {
+	Value   string
+	Allowed []string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("invalid value %q, must be one of: %s", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// Set holds the allowed values of a string-based enum type.
+//
+// Example:
+//
+//	type Role string
+//
+//	const (
+//	    RoleAdmin Role = "admin"
+//	    RoleUser  Role = "user"
+//	    RoleGuest Role = "guest"
+//	)
+//
+//	var roles = enum.Define(RoleAdmin, RoleUser, RoleGuest)
+//
+//	func (r Role) Valid() bool          { return roles.Valid(r) }
+//	func (r Role) Values() []string     { return roles.Strings() }
+//	func (r *Role) Parse(s string) error { return roles.ParseInto(r, s) }
+//
+//	type CreateUserRequest struct {
+//	    Role Role `json:"role" validate:"required"` // invalid roles → field error
+//	}
+type Set[T ~string] struct {
+	values []T
+	index  map[string]T // lowercase -> value
+}
+
+// Define creates an enum set from the allowed values
+func Define[T ~string](values ...T) *Set[T] {
+	s := &Set[T]{
+		values: values,
+		index:  make(map[string]T, len(values)),
+	}
+	for _, v := range values {
+		s.index[strings.ToLower(string(v))] = v
+	}
+	return s
+}
+
+// Valid reports whether v is one of the allowed values (exact match)
+func (s *Set[T]) Valid(v T) bool {
+	canonical, ok := s.index[strings.ToLower(string(v))]
+	return ok && canonical == v
+}
+
+// Parse converts raw input (case-insensitive, trimmed) into a typed value
+func (s *Set[T]) Parse(raw string) (T, error) {
+	if v, ok := s.index[strings.ToLower(strings.TrimSpace(raw))]; ok {
+		return v, nil
+	}
+	var zero T
+	return zero, &InvalidError{Value: raw, Allowed: s.Strings()}
+}
+
+// ParseInto parses raw and stores the result in target.
+// On error target is left unchanged.
+func (s *Set[T]) ParseInto(target *T, raw string) error {
+	v, err := s.Parse(raw)
+	if err != nil {
+		return err
+	}
+	*target = v
+	return nil
+}
+
+// Values returns the allowed values in definition order
+func (s *Set[T]) Values() []T {
+	out := make([]T, len(s.values))
+	copy(out, s.values)
+	return out
+}
+
+// Strings returns the allowed values as strings
+func (s *Set[T]) Strings() []string {
+	out := make([]string, len(s.values))
+	for i, v := range s.values {
+		out[i] = string(v)
+	}
+	return out
+}
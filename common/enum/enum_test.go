@@ -0,0 +1,88 @@
+package enum_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/primadi/lokstra/common/enum"
+	"github.com/primadi/lokstra/common/validator"
+)
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+	RoleGuest Role = "guest"
+)
+
+var roles = enum.Define(RoleAdmin, RoleUser, RoleGuest)
+
+func (r Role) Valid() bool           { return roles.Valid(r) }
+func (r Role) Values() []string      { return roles.Strings() }
+func (r *Role) Parse(s string) error { return roles.ParseInto(r, s) }
+
+func TestSet_Parse(t *testing.T) {
+	r, err := roles.Parse(" Admin ")
+	if err != nil || r != RoleAdmin {
+		t.Errorf("Expected admin, got %q (%v)", r, err)
+	}
+
+	_, err = roles.Parse("root")
+	var invalid *enum.InvalidError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected *enum.InvalidError, got %v", err)
+	}
+	if len(invalid.Allowed) != 3 {
+		t.Errorf("Expected 3 allowed values, got %v", invalid.Allowed)
+	}
+
+	if !roles.Valid(RoleGuest) || roles.Valid("Guest") || roles.Valid("root") {
+		t.Error("Valid should only accept exact allowed values")
+	}
+}
+
+func TestValidator_EnumField(t *testing.T) {
+	type CreateUser struct {
+		Name string `json:"name"`
+		Role Role   `json:"role" validate:"required"`
+		Alt  *Role  `json:"alt_role"`
+	}
+
+	tests := []struct {
+		name       string
+		input      CreateUser
+		errorField string
+	}{
+		{name: "valid", input: CreateUser{Name: "a", Role: RoleUser}},
+		{name: "invalid", input: CreateUser{Name: "a", Role: "root"}, errorField: "role"},
+		{name: "missing uses required", input: CreateUser{Name: "a"}, errorField: "role"},
+		{name: "invalid pointer", input: CreateUser{Name: "a", Role: RoleAdmin, Alt: ptr(Role("x"))}, errorField: "alt_role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldErrors, err := validator.ValidateStruct(&tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.errorField == "" {
+				if len(fieldErrors) != 0 {
+					t.Errorf("expected no errors, got %v", fieldErrors)
+				}
+				return
+			}
+			if len(fieldErrors) != 1 || fieldErrors[0].Field != tt.errorField {
+				t.Errorf("expected error on %s, got %v", tt.errorField, fieldErrors)
+			}
+		})
+	}
+
+	// Message lists allowed values
+	fieldErrors, _ := validator.ValidateStruct(&CreateUser{Role: "root"})
+	if len(fieldErrors) == 0 || fieldErrors[0].Message != "role must be one of: admin, user, guest" {
+		t.Errorf("unexpected message: %v", fieldErrors)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,117 @@
+package errs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+// FieldError describes an invalid input field of a validation error
+type FieldError = api_formatter.FieldError
+
+// AppError is a domain error carrying its HTTP mapping. Services return it
+// and the response pipeline turns it into the matching status and error body,
+// so handlers can simply `return err`.
+//
+// Example:
+//
+//	func (s *UserService) Get(id string) (*User, error) {
+//	    u, err := s.repo.Find(id)
+//	    if errors.Is(err, sql.ErrNoRows) {
+//	        return nil, errs.NotFound("User not found").WithCause(err)
+//	    }
+//	    return u, err
+//	}
+type AppError struct {
+	Code       string       // Error code (e.g. "NOT_FOUND")
+	Message    string       // Human-readable message, sent to the client
+	HTTPStatus int          // HTTP status code; 0 means 500
+	Fields     []FieldError // Field errors (validation)
+	Cause      error        // Underlying error, never sent to the client
+}
+
+// Error implements the error interface
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying cause
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Status returns the HTTP status code, defaulting to 500
+func (e *AppError) Status() int {
+	if e.HTTPStatus == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.HTTPStatus
+}
+
+// WithCause returns a copy of e wrapping cause
+func (e *AppError) WithCause(cause error) *AppError {
+	cp := *e
+	cp.Cause = cause
+	return &cp
+}
+
+// New creates an AppError with the given status, code and message
+func New(status int, code, message string) *AppError {
+	return &AppError{Code: code, Message: message, HTTPStatus: status}
+}
+
+// Wrap creates an AppError with the given status, code and message wrapping cause
+func Wrap(cause error, status int, code, message string) *AppError {
+	return &AppError{Code: code, Message: message, HTTPStatus: status, Cause: cause}
+}
+
+// As returns the AppError in err's chain, if any
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// BadRequest creates a 400 error
+func BadRequest(code, message string) *AppError {
+	return New(http.StatusBadRequest, code, message)
+}
+
+// Validation creates a 400 validation error with field errors
+func Validation(message string, fields ...FieldError) *AppError {
+	return &AppError{
+		Code:       "VALIDATION_ERROR",
+		Message:    message,
+		HTTPStatus: http.StatusBadRequest,
+		Fields:     fields,
+	}
+}
+
+// Unauthorized creates a 401 error
+func Unauthorized(message string) *AppError {
+	return New(http.StatusUnauthorized, "UNAUTHORIZED", message)
+}
+
+// Forbidden creates a 403 error
+func Forbidden(message string) *AppError {
+	return New(http.StatusForbidden, "FORBIDDEN", message)
+}
+
+// NotFound creates a 404 error
+func NotFound(message string) *AppError {
+	return New(http.StatusNotFound, "NOT_FOUND", message)
+}
+
+// Conflict creates a 409 error (e.g. duplicate key, concurrent modification)
+func Conflict(message string) *AppError {
+	return New(http.StatusConflict, "CONFLICT", message)
+}
+
+// Internal creates a 500 error
+func Internal(message string) *AppError {
+	return New(http.StatusInternalServerError, "INTERNAL_ERROR", message)
+}
@@ -0,0 +1,63 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/primadi/lokstra/common/errs"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		err    *errs.AppError
+		status int
+		code   string
+	}{
+		{errs.NotFound("User not found"), http.StatusNotFound, "NOT_FOUND"},
+		{errs.Conflict("Email already used"), http.StatusConflict, "CONFLICT"},
+		{errs.Validation("Invalid input"), http.StatusBadRequest, "VALIDATION_ERROR"},
+		{errs.BadRequest("BAD_CURSOR", "Invalid cursor"), http.StatusBadRequest, "BAD_CURSOR"},
+		{errs.Unauthorized("Login required"), http.StatusUnauthorized, "UNAUTHORIZED"},
+		{errs.Forbidden("Not allowed"), http.StatusForbidden, "FORBIDDEN"},
+		{errs.Internal("Boom"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+		{&errs.AppError{Code: "X", Message: "no status"}, http.StatusInternalServerError, "X"},
+	}
+	for _, tt := range tests {
+		if tt.err.Status() != tt.status || tt.err.Code != tt.code {
+			t.Errorf("%s: expected %d %s, got %d %s", tt.err.Message, tt.status, tt.code, tt.err.Status(), tt.err.Code)
+		}
+	}
+
+	v := errs.Validation("Invalid input", errs.FieldError{Field: "email", Code: "EMAIL", Message: "email is invalid"})
+	if len(v.Fields) != 1 || v.Fields[0].Field != "email" {
+		t.Errorf("Expected email field error, got %v", v.Fields)
+	}
+}
+
+func TestWrapping(t *testing.T) {
+	cause := errors.New("sql: no rows")
+	base := errs.NotFound("User not found")
+	err := base.WithCause(cause)
+
+	if base.Cause != nil {
+		t.Error("WithCause must not modify the original error")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected errors.Is to find the cause")
+	}
+	if err.Error() != "User not found: sql: no rows" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	// Found through further wrapping
+	wrapped := fmt.Errorf("get user: %w", errs.Wrap(cause, http.StatusConflict, "STALE", "Stale"))
+	appErr, ok := errs.As(wrapped)
+	if !ok || appErr.Code != "STALE" || appErr.Status() != http.StatusConflict {
+		t.Errorf("Expected AppError in chain, got %v (%v)", appErr, ok)
+	}
+	if _, ok := errs.As(cause); ok {
+		t.Error("Expected plain error not to be an AppError")
+	}
+}
@@ -0,0 +1,155 @@
+// Package hashring implements consistent hashing: keys are mapped to nodes
+// so the same key always lands on the same node, and adding or removing a
+// node only remaps the keys of that node (about 1/n of them).
+package hashring
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// DefaultReplicas is the number of virtual nodes per node. More virtual
+// nodes spread keys more evenly at the cost of memory.
+const DefaultReplicas = 160
+
+// Ring is a consistent hash ring, safe for concurrent use
+type Ring struct {
+	replicas int
+
+	mu     sync.RWMutex
+	hashes []uint64          // sorted virtual node hashes
+	owners map[uint64]string // virtual node hash -> node
+	nodes  []string
+}
+
+// New creates a ring with the given nodes. replicas <= 0 uses DefaultReplicas.
+//
+// Example:
+//
+//	ring := hashring.New(0, "http://shard-a:8080", "http://shard-b:8080")
+//	baseURL := ring.Get(userID) // same user, same shard
+func New(replicas int, nodes ...string) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	r := &Ring{replicas: replicas, owners: make(map[uint64]string)}
+	for _, node := range nodes {
+		r.add(node)
+	}
+	r.sort()
+	return r
+}
+
+// Get returns the node owning key, or "" if the ring is empty
+func (r *Ring) Get(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i, _ := slices.BinarySearch(r.hashes, h)
+	if i == len(r.hashes) {
+		i = 0 // wrap around
+	}
+	return r.owners[r.hashes[i]]
+}
+
+// Add adds nodes to the ring, existing nodes are ignored
+func (r *Ring) Add(nodes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, node := range nodes {
+		r.add(node)
+	}
+	r.sort()
+}
+
+// Remove removes nodes from the ring
+func (r *Ring) Remove(nodes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, node := range nodes {
+		i := slices.Index(r.nodes, node)
+		if i < 0 {
+			continue
+		}
+		r.nodes = slices.Delete(r.nodes, i, i+1)
+		for v := range r.replicas {
+			h := virtualHash(node, v)
+			if r.owners[h] == node {
+				delete(r.owners, h)
+			}
+		}
+	}
+	r.hashes = r.hashes[:0]
+	for h := range r.owners {
+		r.hashes = append(r.hashes, h)
+	}
+	r.sort()
+}
+
+// Set replaces the nodes of the ring, keeping the placement of the nodes
+// that remain
+func (r *Ring) Set(nodes ...string) {
+	r.mu.RLock()
+	var removed []string
+	for _, node := range r.nodes {
+		if !slices.Contains(nodes, node) {
+			removed = append(removed, node)
+		}
+	}
+	r.mu.RUnlock()
+
+	r.Remove(removed...)
+	r.Add(nodes...)
+}
+
+// Nodes returns the nodes of the ring, in insertion order
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.nodes)
+}
+
+// add adds node's virtual nodes, the caller sorts afterwards
+func (r *Ring) add(node string) {
+	if slices.Contains(r.nodes, node) {
+		return
+	}
+	r.nodes = append(r.nodes, node)
+	for v := range r.replicas {
+		h := virtualHash(node, v)
+		// On a (rare) collision the first node keeps the point
+		if _, taken := r.owners[h]; !taken {
+			r.owners[h] = node
+			r.hashes = append(r.hashes, h)
+		}
+	}
+}
+
+func (r *Ring) sort() {
+	slices.Sort(r.hashes)
+}
+
+func virtualHash(node string, v int) uint64 {
+	return hash(node + "#" + strconv.Itoa(v))
+}
+
+// hash is FNV-1a followed by a 64-bit finalizer (splitmix64), FNV alone
+// clusters similar strings such as "node#1", "node#2"
+func hash(s string) uint64 {
+	h := uint64(14695981039346656037) // FNV-1a offset basis
+	for i := range len(s) {
+		h ^= uint64(s[i])
+		h *= 1099511628211 // FNV prime
+	}
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
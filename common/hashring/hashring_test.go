@@ -0,0 +1,129 @@
+package hashring_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/primadi/lokstra/common/hashring"
+)
+
+func keys(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("user-%d", i)
+	}
+	return out
+}
+
+func TestRing_Stable(t *testing.T) {
+	a := hashring.New(0, "shard-a", "shard-b", "shard-c")
+	// Insertion order does not matter
+	b := hashring.New(0, "shard-c", "shard-a", "shard-b")
+
+	for _, k := range keys(1000) {
+		if a.Get(k) != a.Get(k) {
+			t.Fatalf("key %s moved between calls", k)
+		}
+		if a.Get(k) != b.Get(k) {
+			t.Fatalf("key %s maps differently depending on node order", k)
+		}
+	}
+}
+
+func TestRing_Empty(t *testing.T) {
+	if got := hashring.New(0).Get("key"); got != "" {
+		t.Errorf("empty ring returned %q", got)
+	}
+}
+
+func TestRing_MinimalRemapping(t *testing.T) {
+	ks := keys(10000)
+	r := hashring.New(0, "shard-a", "shard-b", "shard-c", "shard-d")
+	before := make(map[string]string, len(ks))
+	for _, k := range ks {
+		before[k] = r.Get(k)
+	}
+
+	// Adding a shard only moves keys to the new shard, about 1/5 of them
+	r.Add("shard-e")
+	moved := 0
+	for _, k := range ks {
+		if got := r.Get(k); got != before[k] {
+			if got != "shard-e" {
+				t.Fatalf("key %s moved from %s to %s, not to the new shard", k, before[k], got)
+			}
+			moved++
+		}
+	}
+	if moved < len(ks)/10 || moved > len(ks)*3/10 {
+		t.Errorf("adding 1 of 5 shards moved %d of %d keys", moved, len(ks))
+	}
+
+	// Removing it restores the original placement
+	r.Remove("shard-e")
+	for _, k := range ks {
+		if got := r.Get(k); got != before[k] {
+			t.Fatalf("key %s maps to %s after removal, was %s", k, got, before[k])
+		}
+	}
+
+	// Removing a shard only moves that shard's keys
+	r.Remove("shard-b")
+	for _, k := range ks {
+		got := r.Get(k)
+		if before[k] != "shard-b" && got != before[k] {
+			t.Fatalf("key %s moved from %s to %s though its shard remains", k, before[k], got)
+		}
+		if got == "shard-b" {
+			t.Fatalf("key %s still maps to removed shard", k)
+		}
+	}
+}
+
+func TestRing_Set(t *testing.T) {
+	ks := keys(5000)
+	r := hashring.New(0, "shard-a", "shard-b", "shard-c")
+	before := make(map[string]string, len(ks))
+	for _, k := range ks {
+		before[k] = r.Get(k)
+	}
+
+	r.Set("shard-a", "shard-c", "shard-d")
+	if nodes := r.Nodes(); len(nodes) != 3 {
+		t.Fatalf("Nodes() = %v", nodes)
+	}
+	for _, k := range ks {
+		got := r.Get(k)
+		if before[k] != "shard-b" && got != before[k] && got != "shard-d" {
+			t.Fatalf("key %s moved from %s to %s", k, before[k], got)
+		}
+	}
+}
+
+func TestRing_Balanced(t *testing.T) {
+	nodes := []string{"http://10.0.0.1:8080", "http://10.0.0.2:8080", "http://10.0.0.3:8080",
+		"http://10.0.0.4:8080", "http://10.0.0.5:8080"}
+	r := hashring.New(0, nodes...)
+
+	ks := keys(50000)
+	counts := make(map[string]int)
+	for _, k := range ks {
+		counts[r.Get(k)]++
+	}
+
+	ideal := len(ks) / len(nodes)
+	for _, node := range nodes {
+		// within ±20% of an even split
+		if c := counts[node]; c < ideal*8/10 || c > ideal*12/10 {
+			t.Errorf("node %s got %d keys, ideal %d (%v)", node, c, ideal, counts)
+		}
+	}
+}
+
+func BenchmarkRing_Get(b *testing.B) {
+	r := hashring.New(0, "shard-a", "shard-b", "shard-c", "shard-d")
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = r.Get("user-12345")
+	}
+}
@@ -0,0 +1,45 @@
+// Package id provides identifier types that bind natively from path, query,
+// header and JSON values.
+//
+// Any type implementing encoding.TextUnmarshaler (on the pointer receiver)
+// can be used as a request field: UnmarshalText parses and validates the raw
+// value, and malformed values are rejected with a field error at bind time.
+// This covers uuid.UUID, the ULID type of this package and custom ID types:
+//
+//	type GetOrderRequest struct {
+//	    OrderID  uuid.UUID `path:"id"`
+//	    Customer id.ULID   `query:"customer"`
+//	}
+//
+//	// GET /orders/not-a-uuid -> 400, "id must be a valid UUID"
+package id
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// Kind is implemented by ID types to name their format in bind errors,
+// e.g. "ULID" in "customer must be a valid ULID". Types without it are
+// named by their Go type name (uuid.UUID -> "UUID").
+type Kind interface {
+	IDKind() string
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// IsTextType reports whether values of t are parsed by UnmarshalText
+func IsTextType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// KindOf returns the format name of ID type t used in error messages
+func KindOf(t reflect.Type) string {
+	if k, ok := reflect.New(t).Interface().(Kind); ok {
+		return k.IDKind()
+	}
+	if name := t.Name(); name != "" {
+		return name
+	}
+	return t.Kind().String()
+}
@@ -0,0 +1,119 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// ULID is a 128-bit Universally Unique Lexicographically Sortable Identifier:
+// a 48-bit millisecond timestamp followed by 80 random bits, encoded as 26
+// Crockford base32 characters (e.g. "01ARZ3NDEKTSV4RRFFQ69G5FAV").
+// The zero ULID is treated as empty by `validate:"required"`.
+type ULID [16]byte
+
+const (
+	ulidLength   = 26
+	ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var ulidDecoding = func() [256]byte {
+	var dec [256]byte
+	for i := range dec {
+		dec[i] = 0xFF
+	}
+	for i := range len(ulidAlphabet) {
+		c := ulidAlphabet[i]
+		dec[c] = byte(i)
+		dec[c|0x20] = byte(i) // lowercase
+	}
+	return dec
+}()
+
+// NewULID returns a ULID for the current time
+func NewULID() ULID {
+	var u ULID
+	binary.BigEndian.PutUint64(u[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(u[6:])
+	return u
+}
+
+// ParseULID parses a ULID in its canonical 26-character form (case-insensitive)
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != ulidLength {
+		return u, fmt.Errorf("invalid ULID %q: must be %d characters", s, ulidLength)
+	}
+	for i := range len(s) {
+		v := ulidDecoding[s[i]]
+		if v == 0xFF {
+			return u, fmt.Errorf("invalid ULID %q: invalid character %q", s, s[i])
+		}
+		// u = u*32 + v
+		carry := uint16(v)
+		for j := len(u) - 1; j >= 0; j-- {
+			x := uint16(u[j])<<5 | carry
+			u[j] = byte(x)
+			carry = x >> 8
+		}
+		if carry != 0 {
+			return u, fmt.Errorf("invalid ULID %q: overflows 128 bits", s)
+		}
+	}
+	return u, nil
+}
+
+// MustParseULID is like ParseULID but panics on invalid input
+func MustParseULID(s string) ULID {
+	u, err := ParseULID(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// String returns the canonical 26-character form
+func (u ULID) String() string {
+	var out [ulidLength]byte
+	n := u
+	for i := ulidLength - 1; i >= 0; i-- {
+		// out[i] = n % 32; n /= 32
+		var rem uint16
+		for j := range n {
+			x := rem<<8 | uint16(n[j])
+			n[j] = byte(x >> 5)
+			rem = x & 0x1F
+		}
+		out[i] = ulidAlphabet[rem]
+	}
+	return string(out[:])
+}
+
+// Time returns the timestamp part
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(u[:8]) >> 16))
+}
+
+// IsZero reports whether u is the zero ULID
+func (u ULID) IsZero() bool {
+	return u == ULID{}
+}
+
+// IDKind implements Kind
+func (ULID) IDKind() string { return "ULID" }
+
+// MarshalText implements encoding.TextMarshaler
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (u *ULID) UnmarshalText(b []byte) error {
+	parsed, err := ParseULID(string(b))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
@@ -0,0 +1,67 @@
+package id_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/primadi/lokstra/common/id"
+)
+
+func TestParseULID_RoundTrip(t *testing.T) {
+	const s = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	u, err := id.ParseULID(s)
+	if err != nil {
+		t.Fatalf("ParseULID failed: %v", err)
+	}
+	if u.String() != s {
+		t.Errorf("String() = %s, want %s", u, s)
+	}
+
+	lower, err := id.ParseULID("01arz3ndektsv4rrffq69g5fav")
+	if err != nil || lower != u {
+		t.Errorf("Lowercase parse = %s, %v", lower, err)
+	}
+}
+
+func TestParseULID_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"01ARZ3NDEK",                  // too short
+		"01ARZ3NDEKTSV4RRFFQ69G5FAU!", // too long
+		"01ARZ3NDEKTSV4RRFFQ69G5FAI",  // I is not in the alphabet
+		"81ARZ3NDEKTSV4RRFFQ69G5FAV",  // overflows 128 bits
+	} {
+		if _, err := id.ParseULID(s); err == nil {
+			t.Errorf("ParseULID(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	u := id.NewULID()
+	if u.IsZero() {
+		t.Fatal("NewULID returned zero ULID")
+	}
+	if ts := u.Time(); ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("Time() = %v, not around now", ts)
+	}
+	parsed, err := id.ParseULID(u.String())
+	if err != nil || parsed != u {
+		t.Errorf("Round trip of %s = %s, %v", u, parsed, err)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if got := id.KindOf(reflect.TypeFor[id.ULID]()); got != "ULID" {
+		t.Errorf("KindOf(ULID) = %s", got)
+	}
+	if got := id.KindOf(reflect.TypeFor[uuid.UUID]()); got != "UUID" {
+		t.Errorf("KindOf(uuid.UUID) = %s", got)
+	}
+	if id.IsTextType(reflect.TypeFor[string]()) {
+		t.Error("string should not be an ID type")
+	}
+}
@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// METRIC_LOG_DROPPED counts log entries dropped by an AsyncWriter whose
+// buffer was full (OverflowDrop)
+const METRIC_LOG_DROPPED = "log_dropped_total"
+
+// OverflowPolicy decides what an AsyncWriter does when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowDrop drops the entry (counted by Dropped), logging never blocks.
+	// Error lines of the logger are never dropped, they wait for room (see
+	// WriteBlocking).
+	OverflowDrop OverflowPolicy = iota
+	// OverflowBlock blocks the caller until there is room, no entry is lost
+	OverflowBlock
+)
+
+// AsyncWriterConfig configures NewAsyncWriter
+type AsyncWriterConfig struct {
+	// BufferSize is the maximum number of queued entries (default 8192)
+	BufferSize int
+	// BatchSize is the number of bytes written to the output at once (default 64KB)
+	BatchSize int
+	// FlushInterval is the maximum time an entry waits before it is written (default 100ms)
+	FlushInterval time.Duration
+	// Overflow is the policy when the buffer is full (default OverflowDrop)
+	Overflow OverflowPolicy
+	// Metrics receives a METRIC_LOG_DROPPED counter per dropped entry (optional)
+	Metrics serviceapi.Metrics
+}
+
+// AsyncWriter queues log entries in a bounded buffer and writes them to
+// the output in batches from a background goroutine, so logging costs a
+// channel send instead of a write syscall per line.
+//
+// Example:
+//
+//	w := logger.NewAsyncWriter(os.Stdout, nil)
+//	logger.SetOutput(w)
+//	defer logger.Flush() // or Close on shutdown, server.Shutdown flushes it
+type AsyncWriter struct {
+	out io.Writer
+	cfg AsyncWriterConfig
+
+	entries chan []byte
+	flushes chan chan error
+	stop    chan struct{}
+	done    chan struct{}
+
+	mu        sync.RWMutex // guards closed against queueing after Close
+	closed    bool
+	outMu     sync.Mutex // serializes writes to out
+	dropped   atomic.Int64
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Creates an AsyncWriter writing to out. cfg may be nil for the defaults.
+func NewAsyncWriter(out io.Writer, cfg *AsyncWriterConfig) *AsyncWriter {
+	w := &AsyncWriter{out: out}
+	if cfg != nil {
+		w.cfg = *cfg
+	}
+	if w.cfg.BufferSize <= 0 {
+		w.cfg.BufferSize = 8192
+	}
+	if w.cfg.BatchSize <= 0 {
+		w.cfg.BatchSize = 64 << 10
+	}
+	if w.cfg.FlushInterval <= 0 {
+		w.cfg.FlushInterval = 100 * time.Millisecond
+	}
+
+	w.entries = make(chan []byte, w.cfg.BufferSize)
+	w.flushes = make(chan chan error)
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p. After Close, p is written directly.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.writeOut(p)
+	}
+
+	entry := append([]byte(nil), p...)
+	if w.cfg.Overflow == OverflowBlock {
+		w.entries <- entry
+		return len(p), nil
+	}
+
+	select {
+	case w.entries <- entry:
+	default:
+		w.dropped.Add(1)
+		if w.cfg.Metrics != nil {
+			w.cfg.Metrics.IncCounter(METRIC_LOG_DROPPED, nil)
+		}
+	}
+	// A dropped entry is not an error for the caller: logging must not fail requests
+	return len(p), nil
+}
+
+// WriteBlocking queues a copy of p, waiting for room whatever the overflow
+// policy. The logger writes Error and more severe lines with it.
+func (w *AsyncWriter) WriteBlocking(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.writeOut(p)
+	}
+	w.entries <- append([]byte(nil), p...)
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full
+func (w *AsyncWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Flush writes all queued entries and syncs the output (if it has a Sync
+// method, e.g. *os.File)
+func (w *AsyncWriter) Flush() error {
+	reply := make(chan error, 1)
+	select {
+	case w.flushes <- reply:
+		return <-reply
+	case <-w.done:
+		return nil
+	}
+}
+
+// Close writes all queued entries, syncs the output and stops the
+// background goroutine. Later writes go directly to the output.
+func (w *AsyncWriter) Close() error {
+	w.closeOnce.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		w.mu.Unlock()
+
+		close(w.stop)
+		<-w.done
+	})
+	return w.closeErr
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]byte, 0, w.cfg.BatchSize)
+	write := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := w.writeOut(batch)
+		batch = batch[:0]
+		return err
+	}
+	add := func(entry []byte) {
+		if len(batch)+len(entry) > w.cfg.BatchSize {
+			_ = write()
+		}
+		batch = append(batch, entry...)
+	}
+	// drain adds every queued entry, writes and syncs
+	drain := func() error {
+		for {
+			select {
+			case entry := <-w.entries:
+				add(entry)
+			default:
+				err := write()
+				w.sync()
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case entry := <-w.entries:
+			add(entry)
+		case <-ticker.C:
+			_ = write()
+		case reply := <-w.flushes:
+			reply <- drain()
+		case <-w.stop:
+			w.closeErr = drain()
+			return
+		}
+	}
+}
+
+func (w *AsyncWriter) writeOut(p []byte) (int, error) {
+	w.outMu.Lock()
+	defer w.outMu.Unlock()
+	return w.out.Write(p)
+}
+
+// sync fsyncs the output if possible. Errors are ignored: Sync fails on
+// pipes and terminals, where there is nothing to sync.
+func (w *AsyncWriter) sync() {
+	if s, ok := w.out.(interface{ Sync() error }); ok {
+		w.outMu.Lock()
+		defer w.outMu.Unlock()
+		_ = s.Sync()
+	}
+}
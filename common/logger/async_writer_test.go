@@ -0,0 +1,264 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// syncBuffer is a thread-safe output recording writes and syncs
+type syncBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+	syncs  int
+	block  chan struct{} // if set, Write waits on it
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	if b.block != nil {
+		<-b.block
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes++
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Sync() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.syncs++
+	return nil
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAsyncWriter_FlushOnClose(t *testing.T) {
+	out := &syncBuffer{}
+	// Long interval: nothing is written until shutdown
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{FlushInterval: time.Hour})
+
+	for i := range 100 {
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+	if got := out.String(); got != "" {
+		t.Fatalf("Expected nothing written before flush, got %d bytes", len(got))
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 100 || lines[0] != "line 0" || lines[99] != "line 99" {
+		t.Fatalf("Expected 100 ordered lines after Close, got %d", len(lines))
+	}
+	if out.writes != 1 {
+		t.Errorf("Expected one batched write, got %d", out.writes)
+	}
+	if out.syncs != 1 {
+		t.Errorf("Expected output synced on Close, got %d syncs", out.syncs)
+	}
+
+	// Written directly after Close
+	fmt.Fprintln(w, "late")
+	if !strings.HasSuffix(out.String(), "late\n") {
+		t.Error("Expected write after Close to reach the output")
+	}
+}
+
+func TestAsyncWriter_FlushInterval(t *testing.T) {
+	out := &syncBuffer{}
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{FlushInterval: 10 * time.Millisecond})
+	defer w.Close()
+
+	fmt.Fprintln(w, "hello")
+	deadline := time.Now().Add(2 * time.Second)
+	for out.String() != "hello\n" {
+		if time.Now().After(deadline) {
+			t.Fatal("Entry not written after the flush interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type dropCounter struct {
+	serviceapi.Metrics
+	mu    sync.Mutex
+	count int
+}
+
+func (m *dropCounter) IncCounter(name string, labels serviceapi.Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if name == logger.METRIC_LOG_DROPPED {
+		m.count++
+	}
+}
+
+func TestAsyncWriter_OverflowDrop(t *testing.T) {
+	out := &syncBuffer{block: make(chan struct{})}
+	metrics := &dropCounter{}
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{
+		BufferSize: 4, BatchSize: 1, FlushInterval: time.Hour, Metrics: metrics,
+	})
+
+	// The writer goroutine is stuck on the first entry, 4 more fit the buffer
+	for i := range 20 {
+		fmt.Fprintf(w, "%d\n", i)
+	}
+	close(out.block)
+	w.Close()
+
+	dropped := w.Dropped()
+	if dropped == 0 {
+		t.Fatal("Expected entries to be dropped on overflow")
+	}
+	if written := strings.Count(out.String(), "\n"); int64(written)+dropped != 20 {
+		t.Errorf("Expected written (%d) + dropped (%d) = 20", written, dropped)
+	}
+	if int64(metrics.count) != dropped {
+		t.Errorf("Expected %d dropped metric increments, got %d", dropped, metrics.count)
+	}
+}
+
+func TestAsyncWriter_OverflowBlock(t *testing.T) {
+	out := &syncBuffer{}
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{
+		BufferSize: 2, Overflow: logger.OverflowBlock,
+	})
+	for i := range 1000 {
+		fmt.Fprintf(w, "%d\n", i)
+	}
+	w.Close()
+
+	if w.Dropped() != 0 || strings.Count(out.String(), "\n") != 1000 {
+		t.Errorf("Expected all 1000 entries written, dropped %d", w.Dropped())
+	}
+}
+
+func TestSetOutput_FlushWritesBufferedLogs(t *testing.T) {
+	out := &syncBuffer{}
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{FlushInterval: time.Hour})
+	logger.SetOutput(w)
+	defer logger.SetOutput(os.Stdout)
+	defer w.Close()
+
+	logger.LogInfo("shutting down %s", "app")
+	if err := logger.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "[INFO] shutting down app") {
+		t.Errorf("Expected log line after Flush, got %q", out.String())
+	}
+}
+
+func TestAsyncWriter_OverflowDropKeepsErrors(t *testing.T) {
+	out := &syncBuffer{block: make(chan struct{})}
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{
+		BufferSize: 2, BatchSize: 1, FlushInterval: time.Hour,
+	})
+	logger.SetOutput(w)
+	defer logger.SetOutput(os.Stdout)
+
+	// The writer goroutine is stuck, the buffer fills up with info lines
+	for i := range 20 {
+		logger.LogInfo("info %d", i)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 5 {
+			logger.LogError("error %d", i)
+		}
+	}()
+	close(out.block)
+	<-done
+	w.Close()
+
+	if w.Dropped() == 0 {
+		t.Fatal("Expected info lines to be dropped on overflow")
+	}
+	if n := strings.Count(out.String(), "[ERROR]"); n != 5 {
+		t.Errorf("Expected all 5 error lines written, got %d:\n%s", n, out.String())
+	}
+}
+
+func TestLogPanic_FlushesOutput(t *testing.T) {
+	out := &syncBuffer{}
+	w := logger.NewAsyncWriter(out, &logger.AsyncWriterConfig{FlushInterval: time.Hour})
+	logger.SetOutput(w)
+	defer logger.SetOutput(os.Stdout)
+	defer w.Close()
+
+	func() {
+		defer func() { recover() }()
+		logger.LogPanic("cannot start %s", "app")
+	}()
+	if !strings.Contains(out.String(), "[ERROR] cannot start app") {
+		t.Errorf("Expected the panic line written before panicking, got %q", out.String())
+	}
+}
+
+func TestSetOutput_ConcurrentWithLogging(t *testing.T) {
+	defer logger.SetOutput(os.Stdout)
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			for j := range 100 {
+				logger.LogError("line %d-%d", i, j)
+			}
+		})
+	}
+	for range 100 {
+		logger.SetOutput(&syncBuffer{})
+	}
+	wg.Wait()
+}
+
+var logLine = []byte("2024/01/02 15:04:05 [INFO] GET /api/users - Status: 200 - Duration: 1.2ms\n")
+
+func benchmarkFile(b *testing.B) *os.File {
+	f, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { f.Close() })
+	return f
+}
+
+// One write syscall per log line
+func BenchmarkLogWrite_Direct(b *testing.B) {
+	f := benchmarkFile(b)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			f.Write(logLine)
+		}
+	})
+}
+
+// Batched writes from a background goroutine
+func BenchmarkLogWrite_Async(b *testing.B) {
+	w := logger.NewAsyncWriter(benchmarkFile(b), &logger.AsyncWriterConfig{Overflow: logger.OverflowBlock})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			w.Write(logLine)
+		}
+	})
+	w.Close()
+}
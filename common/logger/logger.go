@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"io"
 	"os"
 	"strings"
+	"sync"
 )
 
 // LogLevel represents the logging level
@@ -40,6 +42,36 @@ func SetBackend(backend LoggerBackend) {
 	activeBackend = backend
 }
 
+var (
+	outputMu     sync.Mutex
+	activeOutput io.Writer
+)
+
+// SetOutput sets where the active backend writes log lines, if it
+// supports it (the default slog backend does), e.g. an AsyncWriter:
+//
+//	logger.SetOutput(logger.NewAsyncWriter(os.Stdout, nil))
+func SetOutput(w io.Writer) {
+	if b, ok := activeBackend.(interface{ SetOutput(io.Writer) }); ok {
+		outputMu.Lock()
+		defer outputMu.Unlock()
+		b.SetOutput(w)
+		activeOutput = w
+	}
+}
+
+// Flush writes log lines buffered by the output set with SetOutput (e.g.
+// an AsyncWriter). Called on graceful shutdown so no logs are lost.
+func Flush() error {
+	outputMu.Lock()
+	out := activeOutput
+	outputMu.Unlock()
+	if f, ok := out.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
 // SetLogLevel sets the global log level
 func SetLogLevel(level LogLevel) {
 	if level == LogLevelFromEnvi {
@@ -3,15 +3,15 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
-	"os"
 	"strings"
 	"time"
 )
 
 type ReadableHandler struct {
 	Level LogLevel
-	Out   *os.File
+	Out   io.Writer
 }
 
 func (h *ReadableHandler) Enabled(_ context.Context, level slog.Level) bool {
@@ -41,6 +41,11 @@ func (h *ReadableHandler) Handle(_ context.Context, r slog.Record) error {
 	// attributes → optional, currently ignored for simplicity
 	// You may add key=value printing here if needed.
 
+	// Errors must not be dropped by a full AsyncWriter buffer
+	if w, ok := h.Out.(interface{ WriteBlocking([]byte) (int, error) }); ok && r.Level >= slog.LevelError {
+		_, err := w.WriteBlocking([]byte(line + "\n"))
+		return err
+	}
 	_, err := fmt.Fprintln(h.Out, line)
 	return err
 }
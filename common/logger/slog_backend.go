@@ -2,18 +2,28 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"sync"
+	"sync/atomic"
 )
 
 type SlogBackend struct {
+	mu    sync.Mutex // serializes SetLogLevel and SetOutput
+	state atomic.Pointer[slogState]
+}
+
+// slogState is swapped as a whole, so loggers never see a half-updated backend
+type slogState struct {
 	logger *slog.Logger
 	level  LogLevel
+	out    io.Writer
 }
 
 func NewSlogBackend() *SlogBackend {
-	b := &SlogBackend{level: LogLevelInfo}
-	b.rebuildLogger()
+	b := &SlogBackend{}
+	b.rebuildLogger(LogLevelInfo, os.Stdout)
 	return b
 }
 
@@ -25,60 +35,78 @@ func (b *SlogBackend) SetLogLevel(level LogLevel) {
 		return
 	}
 
-	b.level = level
-	b.rebuildLogger()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rebuildLogger(level, b.state.Load().out)
+}
+
+// SetOutput sets where log lines are written (default os.Stdout)
+func (b *SlogBackend) SetOutput(w io.Writer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rebuildLogger(b.state.Load().level, w)
 }
 
 func (b *SlogBackend) GetLogLevel() LogLevel {
-	return b.level
+	return b.state.Load().level
 }
 
 func (b *SlogBackend) Debug(format string, args ...any) {
-	if b.level >= LogLevelDebug {
-		b.logger.Debug(fmt.Sprintf(format, args...))
+	if s := b.state.Load(); s.level >= LogLevelDebug {
+		s.logger.Debug(fmt.Sprintf(format, args...))
 	}
 }
 
 func (b *SlogBackend) Info(format string, args ...any) {
-	if b.level >= LogLevelInfo {
-		b.logger.Info(fmt.Sprintf(format, args...))
+	if s := b.state.Load(); s.level >= LogLevelInfo {
+		s.logger.Info(fmt.Sprintf(format, args...))
 	}
 }
 
 func (b *SlogBackend) Warn(format string, args ...any) {
-	if b.level >= LogLevelWarn {
-		b.logger.Warn(fmt.Sprintf(format, args...))
+	if s := b.state.Load(); s.level >= LogLevelWarn {
+		s.logger.Warn(fmt.Sprintf(format, args...))
 	}
 }
 
 func (b *SlogBackend) Error(format string, args ...any) {
-	if b.level >= LogLevelError {
-		b.logger.Error(fmt.Sprintf(format, args...))
+	if s := b.state.Load(); s.level >= LogLevelError {
+		s.logger.Error(fmt.Sprintf(format, args...))
 	}
 }
 
 func (b *SlogBackend) Panic(v ...any) {
 	msg := fmt.Sprint(v...)
-	b.logger.Error(msg)
+	b.logAndFlush(msg)
 	panic(msg)
 }
 
 func (b *SlogBackend) PanicF(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	b.logger.Error(msg)
+	b.logAndFlush(msg)
 	panic(msg)
 }
 
 func (b *SlogBackend) Fatal(format string, v ...any) {
-	b.logger.Error(fmt.Sprintf(format, v...))
+	b.logAndFlush(fmt.Sprintf(format, v...))
 	os.Exit(1)
 }
 
-func (b *SlogBackend) rebuildLogger() {
+// logAndFlush logs msg as an error and flushes a buffered output (e.g. an
+// AsyncWriter), so the last line is not lost when the process dies
+func (b *SlogBackend) logAndFlush(msg string) {
+	s := b.state.Load()
+	s.logger.Error(msg)
+	if f, ok := s.out.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+}
+
+func (b *SlogBackend) rebuildLogger(level LogLevel, out io.Writer) {
 	handler := &ReadableHandler{
-		Level: b.level,
-		Out:   os.Stdout,
+		Level: level,
+		Out:   out,
 	}
 
-	b.logger = slog.New(handler)
+	b.state.Store(&slogState{logger: slog.New(handler), level: level, out: out})
 }
@@ -0,0 +1,114 @@
+// Package result provides Result, a value or an error returned by service
+// methods, with helpers to chain calls and send the outcome as an API
+// response.
+//
+// Example:
+//
+//	func (s *UserService) Get(id string) result.Result[*User] {
+//	    return result.Of(s.repo.Find(id))
+//	}
+//
+//	func (h *UserHandler) Get(c *request.Context) error {
+//	    user := h.users.Get(c.Req.PathParam("id", ""))
+//	    return result.Map(user, toUserDTO).ToApi(c.Api)
+//	}
+package result
+
+import (
+	"github.com/primadi/lokstra/common/errs"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+// Result holds either a value or an error
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a successful result holding value
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err returns a failed result. A nil err is replaced by an internal
+// error, so a Result built with Err is never successful.
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		err = errs.Internal("result.Err called with a nil error")
+	}
+	return Result[T]{err: err}
+}
+
+// Of converts the (T, error) return of a function into a Result
+func Of[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Result[T]{value: value}
+}
+
+// IsOk reports whether r holds a value
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Value returns the value, or the zero value of T for a failed result
+func (r Result[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error, or nil for a successful result
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Get returns the value and error, to go back to (T, error)
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// OrElse returns the value, or fallback for a failed result
+func (r Result[T]) OrElse(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+	return r.value
+}
+
+// Map applies fn to the value of a successful result. A failed result is
+// returned with its error unchanged, and fn is not called.
+func Map[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Result[U]{value: fn(r.value)}
+}
+
+// Then chains a call that can fail on the value of a successful result
+func Then[T, U any](r Result[T], fn func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Of(fn(r.value))
+}
+
+// ToApi sends r through api: the value with api.Ok, an errs.AppError with
+// its status, code and message (field errors as a validation error).
+// Other errors are returned unchanged, for the response pipeline to map
+// like any handler error (500, 504 on deadline, app error handler).
+func (r Result[T]) ToApi(api *response.ApiHelper) error {
+	if r.err == nil {
+		return api.Ok(r.value)
+	}
+
+	appErr, ok := errs.As(r.err)
+	if !ok {
+		return r.err
+	}
+	if len(appErr.Fields) > 0 {
+		formatted := api_formatter.GetGlobalFormatter().ValidationError(appErr.Message, appErr.Fields)
+		return api.Resp().WithStatus(appErr.Status()).Json(formatted)
+	}
+	return api.Error(appErr.Status(), appErr.Code, appErr.Message)
+}
@@ -0,0 +1,150 @@
+package result_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/primadi/lokstra/common/errs"
+	"github.com/primadi/lokstra/common/result"
+	"github.com/primadi/lokstra/core/response"
+)
+
+func TestResult_OkAndErr(t *testing.T) {
+	ok := result.Ok(42)
+	if !ok.IsOk() || ok.Value() != 42 || ok.Err() != nil {
+		t.Errorf("Unexpected Ok result: %+v", ok)
+	}
+
+	boom := errors.New("boom")
+	failed := result.Err[int](boom)
+	if failed.IsOk() || failed.Value() != 0 || !errors.Is(failed.Err(), boom) {
+		t.Errorf("Unexpected Err result: %+v", failed)
+	}
+	if got := failed.OrElse(7); got != 7 {
+		t.Errorf("OrElse = %d, want 7", got)
+	}
+
+	if result.Err[int](nil).IsOk() {
+		t.Error("Err(nil) must not be successful")
+	}
+}
+
+func TestResult_Of(t *testing.T) {
+	if r := result.Of(strconv.Atoi("12")); !r.IsOk() || r.Value() != 12 {
+		t.Errorf("Unexpected result: %+v", r)
+	}
+	if r := result.Of(strconv.Atoi("x")); r.IsOk() {
+		t.Error("Expected a failed result")
+	}
+}
+
+func TestResult_MapPropagatesErr(t *testing.T) {
+	double := func(n int) int { return n * 2 }
+
+	if r := result.Map(result.Ok(21), double); r.Value() != 42 {
+		t.Errorf("Map = %d, want 42", r.Value())
+	}
+
+	notFound := errs.NotFound("User not found")
+	called := false
+	r := result.Map(result.Err[int](notFound), func(n int) string {
+		called = true
+		return strconv.Itoa(n)
+	})
+	if called {
+		t.Error("Map must not call fn on a failed result")
+	}
+	if !errors.Is(r.Err(), notFound) {
+		t.Errorf("Expected the original error, got %v", r.Err())
+	}
+}
+
+func TestResult_Then(t *testing.T) {
+	r := result.Then(result.Ok("12"), strconv.Atoi)
+	if r.Value() != 12 {
+		t.Errorf("Then = %d, want 12", r.Value())
+	}
+
+	r = result.Then(result.Ok("x"), strconv.Atoi)
+	if r.IsOk() {
+		t.Error("Expected the error of fn to be propagated")
+	}
+}
+
+func sendToApi[T any](t *testing.T, r result.Result[T]) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	api := response.NewApiHelper()
+	err := r.ToApi(api)
+	w := httptest.NewRecorder()
+	api.Resp().WriteHttp(w)
+	return w, err
+}
+
+func TestResult_ToApi(t *testing.T) {
+	tests := []struct {
+		name   string
+		result result.Result[map[string]any]
+		status int
+		code   string
+	}{
+		{
+			name:   "ok",
+			result: result.Ok(map[string]any{"id": 1}),
+			status: http.StatusOK,
+		},
+		{
+			name:   "app error",
+			result: result.Err[map[string]any](errs.NotFound("User not found")),
+			status: http.StatusNotFound,
+			code:   "NOT_FOUND",
+		},
+		{
+			name: "validation error",
+			result: result.Err[map[string]any](errs.Validation("Invalid input",
+				errs.FieldError{Field: "email", Message: "email is required"})),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "wrapped app error",
+			result: result.Err[map[string]any](errs.Conflict("Email taken").WithCause(errors.New("duplicate key"))),
+			status: http.StatusConflict,
+			code:   "CONFLICT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := sendToApi(t, tt.result)
+			if err != nil {
+				t.Fatalf("ToApi failed: %v", err)
+			}
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.code == "" {
+				return
+			}
+			var body struct {
+				Error struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if body.Error.Code != tt.code {
+				t.Errorf("code = %q, want %q (body %s)", body.Error.Code, tt.code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestResult_ToApiReturnsOtherErrors(t *testing.T) {
+	boom := errors.New("boom")
+	if _, err := sendToApi(t, result.Err[int](boom)); !errors.Is(err, boom) {
+		t.Errorf("Expected plain errors to be returned for the pipeline, got %v", err)
+	}
+}
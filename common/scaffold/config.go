@@ -0,0 +1,128 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigOptions describes the config generated by ConfigTemplate
+type ConfigOptions struct {
+	// Path of the generated file (default: "config/config.yaml")
+	Path string
+	// Deployment name (default: "development")
+	Deployment string
+	// Server name (default: "api-server")
+	Server string
+	// BaseURL of the server (default: "http://localhost")
+	BaseURL string
+	// Addr the server listens on (default: ":8080")
+	Addr string
+	// Services become service-definitions
+	Services []ConfigService
+	// Routers mounted on the server (e.g. "order-router")
+	Routers []string
+}
+
+// ConfigService is a service-definitions entry
+type ConfigService struct {
+	// Name of the service (e.g. "order-service")
+	Name string
+	// Type is the factory type (default: Name + "-factory", as generated by Service)
+	Type string
+	// DependsOn lists dependency service names
+	DependsOn []string
+	// Config is passed to the factory
+	Config map[string]any
+}
+
+type configData struct {
+	*ConfigOptions
+	Services []configServiceData
+}
+
+type configServiceData struct {
+	ConfigService
+	ConfigYAML string
+}
+
+var configTmpl = template.Must(template.New("config").Funcs(template.FuncMap{
+	"list": func(items []string) string { return "[" + strings.Join(items, ", ") + "]" },
+}).Parse(`# yaml-language-server: $schema=https://primadi.github.io/lokstra/schema/lokstra.schema.json
+
+configs:
+  server: {{.Deployment}}.{{.Server}}  # Default server to run
+{{- if .Services}}
+
+service-definitions:
+{{- range .Services}}
+  {{.Name}}:
+    type: {{.Type}}
+{{- if .DependsOn}}
+    depends-on: {{list .DependsOn}}
+{{- end}}
+{{- if .ConfigYAML}}
+    config:
+{{.ConfigYAML}}
+{{- end}}
+{{- end}}
+{{- end}}
+
+deployments:
+  {{.Deployment}}:
+    servers:
+      {{.Server}}:
+        base-url: "{{.BaseURL}}"
+        addr: "{{.Addr}}"
+{{- if .Routers}}
+        routers: {{list .Routers}}
+{{- end}}
+`))
+
+// ConfigTemplate generates a deployment YAML with service-definitions for
+// the given services and a single-server deployment mounting the routers.
+func ConfigTemplate(opts *ConfigOptions) (*File, error) {
+	cfg := *opts
+	if cfg.Path == "" {
+		cfg.Path = "config/config.yaml"
+	}
+	if cfg.Deployment == "" {
+		cfg.Deployment = "development"
+	}
+	if cfg.Server == "" {
+		cfg.Server = "api-server"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "http://localhost"
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = ":8080"
+	}
+
+	data := configData{ConfigOptions: &cfg}
+	for _, svc := range cfg.Services {
+		if err := checkName("service", svc.Name); err != nil {
+			return nil, err
+		}
+		if svc.Type == "" {
+			svc.Type = svc.Name + "-factory"
+		}
+
+		sd := configServiceData{ConfigService: svc}
+		if len(svc.Config) > 0 {
+			var out strings.Builder
+			enc := yaml.NewEncoder(&out)
+			enc.SetIndent(2)
+			if err := enc.Encode(svc.Config); err != nil {
+				return nil, fmt.Errorf("scaffold: config of service %s: %w", svc.Name, err)
+			}
+			lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+			sd.ConfigYAML = "      " + strings.Join(lines, "\n      ")
+		}
+		data.Services = append(data.Services, sd)
+	}
+
+	return render(cfg.Path, configTmpl, data)
+}
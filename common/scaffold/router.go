@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"path"
+	"text/template"
+)
+
+type routerData struct {
+	Name       string
+	Package    string
+	TypeName   string
+	RouterName string
+	Path       string
+}
+
+var routerTmpl = template.Must(template.New("router").Parse(`package {{.Package}}
+
+import (
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+// ROUTER_NAME is the name referenced by deployments (servers.*.routers)
+const ROUTER_NAME = "{{.RouterName}}"
+
+// {{.TypeName}}Params identifies a single {{.Name}}
+type {{.TypeName}}Params struct {
+	ID string ` + "`path:\"id\" validate:\"required\"`" + `
+}
+
+// {{.TypeName}}Request is the body for create/update
+type {{.TypeName}}Request struct {
+	ID   string ` + "`path:\"id\"`" + `
+	Name string ` + "`json:\"name\" validate:\"required\"`" + `
+}
+
+// New{{.TypeName}}Router creates the CRUD router for {{.Name}}
+func New{{.TypeName}}Router() router.Router {
+	r := router.New(ROUTER_NAME)
+	r.GET("{{.Path}}", list{{.TypeName}}, route.WithNameOption("{{.Name}}.list"))
+	r.GET("{{.Path}}/{id}", get{{.TypeName}}, route.WithNameOption("{{.Name}}.get"))
+	r.POST("{{.Path}}", create{{.TypeName}}, route.WithNameOption("{{.Name}}.create"))
+	r.PUT("{{.Path}}/{id}", update{{.TypeName}}, route.WithNameOption("{{.Name}}.update"))
+	r.DELETE("{{.Path}}/{id}", delete{{.TypeName}}, route.WithNameOption("{{.Name}}.delete"))
+	return r
+}
+
+// RegisterRouter registers the router as ROUTER_NAME
+func RegisterRouter() {
+	lokstra_registry.RegisterRouter(ROUTER_NAME, New{{.TypeName}}Router())
+}
+
+func list{{.TypeName}}(c *request.Context) error {
+	// TODO: load from service
+	return c.Api.Ok([]{{.TypeName}}Request{})
+}
+
+func get{{.TypeName}}(c *request.Context, p *{{.TypeName}}Params) error {
+	// TODO: load from service
+	return c.Api.NotFound("{{.Name}} " + p.ID + " not found")
+}
+
+func create{{.TypeName}}(c *request.Context, req *{{.TypeName}}Request) error {
+	// TODO: save via service
+	return c.Api.Created(req, "{{.Name}} created")
+}
+
+func update{{.TypeName}}(c *request.Context, req *{{.TypeName}}Request) error {
+	// TODO: save via service
+	return c.Api.OkWithMessage(req, "{{.Name}} updated")
+}
+
+func delete{{.TypeName}}(c *request.Context, p *{{.TypeName}}Params) error {
+	// TODO: delete via service
+	return c.Api.OkWithMessage(p, "{{.Name}} deleted")
+}
+`))
+
+// Router generates a CRUD router for a resource at "<package>/<name>_router.go",
+// with named routes ("<name>.list", "<name>.get", ...) so they can be tuned
+// from config, and a RegisterRouter function.
+func Router(name string) (*File, error) {
+	if err := checkName("router", name); err != nil {
+		return nil, err
+	}
+
+	data := routerData{
+		Name:       name,
+		Package:    packageName(name),
+		TypeName:   pascal(name),
+		RouterName: name + "-router",
+		Path:       "/" + name,
+	}
+	return render(path.Join(data.Package, snake(name)+"_router.go"), routerTmpl, data)
+}
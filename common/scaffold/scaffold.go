@@ -0,0 +1,88 @@
+// Package scaffold generates idiomatic Lokstra boilerplate (service types,
+// routers and YAML config) as a library, so CLIs and other tools can emit
+// code that follows the framework conventions.
+//
+// Example:
+//
+//	svc, _ := scaffold.Service("github.com/acme/shop", "order-service", "order-repository")
+//	r, _ := scaffold.Router("order")
+//	cfg, _ := scaffold.ConfigTemplate(&scaffold.ConfigOptions{
+//	    Services: []scaffold.ConfigService{{Name: "order-service", DependsOn: []string{"order-repository"}}},
+//	})
+//	for _, f := range []*scaffold.File{svc, r, cfg} {
+//	    os.MkdirAll(filepath.Dir(f.Path), 0o755)
+//	    os.WriteFile(f.Path, f.Content, 0o644)
+//	}
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// File is a generated file, Path is relative to the project root
+type File struct {
+	Path    string
+	Content []byte
+}
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9]*([-_][a-z0-9]+)*$`)
+
+// checkName ensures name is kebab/snake case (e.g. "order-service")
+func checkName(kind, name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("scaffold: invalid %s name %q (use lowercase kebab-case, e.g. \"order-service\")", kind, name)
+	}
+	return nil
+}
+
+// words splits "order-service" / "order_service" into ["order", "service"]
+func words(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' })
+}
+
+// pascal converts "order-service" to "OrderService"
+func pascal(name string) string {
+	var sb strings.Builder
+	for _, w := range words(name) {
+		if w == "id" {
+			sb.WriteString("ID")
+			continue
+		}
+		sb.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return sb.String()
+}
+
+// packageName converts "order-service" to "order" (Go package of a module)
+func packageName(name string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, "-service"), "_service")
+	return strings.Join(words(base), "")
+}
+
+// snake converts "order-service" to "order_service"
+func snake(name string) string {
+	return strings.Join(words(name), "_")
+}
+
+// render executes tmpl and gofmt-formats Go output
+func render(path string, tmpl *template.Template, data any) (*File, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("scaffold: render %s: %w", path, err)
+	}
+
+	content := buf.Bytes()
+	if strings.HasSuffix(path, ".go") {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return nil, fmt.Errorf("scaffold: generated %s does not compile: %w", path, err)
+		}
+		content = formatted
+	}
+	return &File{Path: path, Content: content}, nil
+}
@@ -0,0 +1,239 @@
+package scaffold_test
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/common/scaffold"
+	"github.com/primadi/lokstra/core/deploy/loader"
+)
+
+func assertGoFile(t *testing.T, f *scaffold.File, wantPath string) {
+	t.Helper()
+	if f.Path != wantPath {
+		t.Errorf("Expected path %s, got %s", wantPath, f.Path)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), f.Path, f.Content, 0); err != nil {
+		t.Fatalf("Generated %s does not parse: %v\n%s", f.Path, err, f.Content)
+	}
+	formatted, _ := format.Source(f.Content)
+	if string(formatted) != string(f.Content) {
+		t.Errorf("Generated %s is not gofmt-formatted", f.Path)
+	}
+}
+
+func TestService(t *testing.T) {
+	f, err := scaffold.Service("github.com/acme/shop", "order-service", "order-repository")
+	if err != nil {
+		t.Fatalf("Service failed: %v", err)
+	}
+	assertGoFile(t, f, "order/order_service.go")
+
+	src := string(f.Content)
+	for _, want := range []string{
+		"package order",
+		`const SERVICE_TYPE = "order-service-factory"`,
+		"type OrderService struct",
+		`"github.com/acme/shop/orderrepository"`,
+		"OrderRepository *service.Cached[*orderrepository.OrderRepository]",
+		`service.LazyLoad[*orderrepository.OrderRepository]("order-repository")`,
+		"lokstra_registry.RegisterServiceType(SERVICE_TYPE, OrderServiceFactory)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated service to contain %q\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"TODO", "[any]"} {
+		if strings.Contains(src, unwanted) {
+			t.Errorf("Expected generated service without %q\n%s", unwanted, src)
+		}
+	}
+}
+
+func TestService_NoDeps(t *testing.T) {
+	f, err := scaffold.Service("github.com/acme/shop", "user-repository")
+	if err != nil {
+		t.Fatalf("Service failed: %v", err)
+	}
+	assertGoFile(t, f, "userrepository/user_repository.go")
+	if strings.Contains(string(f.Content), "core/service") {
+		t.Errorf("Expected no core/service import without dependencies")
+	}
+}
+
+func TestRouter(t *testing.T) {
+	f, err := scaffold.Router("order")
+	if err != nil {
+		t.Fatalf("Router failed: %v", err)
+	}
+	assertGoFile(t, f, "order/order_router.go")
+
+	src := string(f.Content)
+	for _, want := range []string{
+		`const ROUTER_NAME = "order-router"`,
+		`r.GET("/order/{id}", getOrder, route.WithNameOption("order.get"))`,
+		`r.DELETE("/order/{id}", deleteOrder, route.WithNameOption("order.delete"))`,
+		"lokstra_registry.RegisterRouter(ROUTER_NAME, NewOrderRouter())",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated router to contain %q\n%s", want, src)
+		}
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	for _, name := range []string{"", "Order", "order service", "1order", "order-"} {
+		if _, err := scaffold.Service("github.com/acme/shop", name); err == nil {
+			t.Errorf("Expected error for service name %q", name)
+		}
+		if _, err := scaffold.Router(name); err == nil {
+			t.Errorf("Expected error for router name %q", name)
+		}
+	}
+	if _, err := scaffold.Service("github.com/acme/shop", "order-service", "Bad Dep"); err == nil {
+		t.Errorf("Expected error for invalid dependency name")
+	}
+	if _, err := scaffold.Service("", "order-service", "order-repository"); err == nil {
+		t.Errorf("Expected error for a missing module path")
+	}
+}
+
+func TestConfigTemplate(t *testing.T) {
+	f, err := scaffold.ConfigTemplate(&scaffold.ConfigOptions{
+		Services: []scaffold.ConfigService{
+			{Name: "order-repository", Config: map[string]any{"dsn": "postgres://localhost/orders"}},
+			{Name: "order-service", DependsOn: []string{"order-repository"}},
+		},
+		Routers: []string{"order-router"},
+	})
+	if err != nil {
+		t.Fatalf("ConfigTemplate failed: %v", err)
+	}
+	if f.Path != "config/config.yaml" {
+		t.Errorf("Expected path config/config.yaml, got %s", f.Path)
+	}
+	if err := loader.ValidateConfigYAML(f.Content); err != nil {
+		t.Fatalf("Generated config is invalid: %v\n%s", err, f.Content)
+	}
+
+	src := string(f.Content)
+	for _, want := range []string{
+		"type: order-service-factory",
+		"depends-on: [order-repository]",
+		"dsn: postgres://localhost/orders",
+		"routers: [order-router]",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated config to contain %q\n%s", want, src)
+		}
+	}
+}
+
+const roundTripMain = `package main
+
+import (
+	"fmt"
+
+	"github.com/primadi/lokstra/core/deploy/loader"
+	"github.com/primadi/lokstra/lokstra_registry"
+
+	"MODULE/order"
+	"MODULE/orderrepository"
+)
+
+func main() {
+	order.Register()
+	orderrepository.Register()
+
+	if _, err := loader.LoadConfig("config/config.yaml"); err != nil {
+		panic(err)
+	}
+
+	svc := lokstra_registry.GetService[*order.OrderService]("order-service")
+	fmt.Print(svc.Config["name"], " ", svc.OrderRepository.MustGet().Config["dsn"])
+}
+`
+
+// roundTripModule is the module path of the generated program
+const roundTripModule = "example.com/roundtrip"
+
+// TestRoundTrip compiles the generated service and config into a program,
+// in its own module using this checkout of lokstra, and resolves the
+// service (with its dependency) through the registry.
+func TestRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go run in short mode")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goVersion := regexp.MustCompile(`(?m)^go \S+$`).Find(rootMod)
+	dir := t.TempDir()
+
+	svc, err := scaffold.Service(roundTripModule, "order-service", "order-repository")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := scaffold.Service(roundTripModule, "order-repository")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := scaffold.ConfigTemplate(&scaffold.ConfigOptions{
+		Services: []scaffold.ConfigService{
+			{Name: "order-repository", Config: map[string]any{"dsn": "memory"}},
+			{Name: "order-service", DependsOn: []string{"order-repository"}, Config: map[string]any{"name": "orders"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same go version as lokstra, so only its pruned module graph is loaded
+	goMod := "module " + roundTripModule + "\n\n" + string(goVersion) + "\n\n" +
+		"require github.com/primadi/lokstra v0.0.0\n\n" +
+		"replace github.com/primadi/lokstra => " + root + "\n"
+	files := []*scaffold.File{svc, repo, cfg,
+		{Path: "main.go", Content: []byte(strings.ReplaceAll(roundTripMain, "MODULE", roundTripModule))},
+		{Path: "go.mod", Content: []byte(goMod)},
+		// lokstra's checksums cover the dependencies of the program
+		{Path: "go.sum", Content: goSum},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Dependencies come from the module cache filled by this module's build
+	cmd := exec.Command("go", "run", "-mod=mod", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off", "GOPROXY=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run failed: %v\n%s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); !strings.HasSuffix(got, "orders memory") {
+		t.Errorf("Expected 'orders memory', got %q", got)
+	}
+}
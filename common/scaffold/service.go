@@ -0,0 +1,101 @@
+package scaffold
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+type serviceData struct {
+	Name        string
+	Package     string
+	TypeName    string
+	ServiceType string
+	Deps        []serviceDep
+}
+
+type serviceDep struct {
+	Name   string
+	Field  string
+	Import string // package path, "" for the package of the service
+	Type   string // qualified type of the dependency, e.g. "orderrepository.OrderRepository"
+}
+
+var serviceTmpl = template.Must(template.New("service").Parse(`package {{.Package}}
+
+import (
+{{- if .Deps}}
+	"github.com/primadi/lokstra/core/service"
+{{- end}}
+	"github.com/primadi/lokstra/lokstra_registry"
+{{- range .Deps}}{{if .Import}}
+	"{{.Import}}"
+{{- end}}{{end}}
+)
+
+// SERVICE_TYPE is the factory type referenced by service-definitions
+const SERVICE_TYPE = "{{.ServiceType}}"
+
+// {{.TypeName}} holds the business logic of {{.Name}}
+type {{.TypeName}} struct {
+{{- if .Deps}}
+	// Dependencies, resolved lazily from the registry on first Get()
+{{- range .Deps}}
+	{{.Field}} *service.Cached[*{{.Type}}]
+{{- end}}
+{{end}}
+	// Config from service-definitions
+	Config map[string]any
+}
+
+// {{.TypeName}}Factory creates {{.TypeName}} (registered as SERVICE_TYPE)
+func {{.TypeName}}Factory(deps map[string]any, config map[string]any) any {
+	return &{{.TypeName}}{
+{{- range .Deps}}
+		{{.Field}}: service.LazyLoad[*{{.Type}}]("{{.Name}}"),
+{{- end}}
+		Config: config,
+	}
+}
+
+// Register registers the service type
+func Register() {
+	lokstra_registry.RegisterServiceType(SERVICE_TYPE, {{.TypeName}}Factory)
+}
+`))
+
+// Service generates a service type with its factory and Register function,
+// at "<package>/<name>.go" (e.g. "order/order_service.go" for "order-service").
+// Each dependency becomes a lazily loaded field (service.LazyLoad) typed
+// with the service type generated for it, imported from module (the Go
+// module path of the project, e.g. "github.com/acme/shop"); list the same
+// names in depends-on of the service definition (see ConfigTemplate).
+func Service(module, name string, deps ...string) (*File, error) {
+	if err := checkName("service", name); err != nil {
+		return nil, err
+	}
+	if len(deps) > 0 && (module == "" || strings.ContainsAny(module, " \t\"\\")) {
+		return nil, fmt.Errorf("scaffold: invalid module path %q", module)
+	}
+
+	data := serviceData{
+		Name:        name,
+		Package:     packageName(name),
+		TypeName:    pascal(name),
+		ServiceType: name + "-factory",
+	}
+	for _, dep := range deps {
+		if err := checkName("dependency", dep); err != nil {
+			return nil, err
+		}
+		d := serviceDep{Name: dep, Field: pascal(dep), Type: pascal(dep)}
+		if pkg := packageName(dep); pkg != data.Package {
+			d.Import = path.Join(module, pkg)
+			d.Type = pkg + "." + d.Type
+		}
+		data.Deps = append(data.Deps, d)
+	}
+
+	return render(path.Join(data.Package, snake(name)+".go"), serviceTmpl, data)
+}
@@ -1,11 +1,19 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
 )
 
+// ClientIP returns the first X-Forwarded-For address, else X-Real-IP, else
+// the remote address.
+//
+// Deprecated: the headers are trusted from any client, which can spoof its
+// address. Use request.Context.ClientIP, which honors them only from
+// trusted proxies (see request.SetTrustedProxies).
 func ClientIP(r *http.Request) string {
 	// Prioritaskan proxy header
 	if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
@@ -21,3 +29,48 @@ func ClientIP(r *http.Request) string {
 	}
 	return host
 }
+
+// ParseIPNets parses IPs and CIDRs ("10.0.0.0/8", "192.0.2.1", "::1");
+// a plain IP is a single-address network. Invalid entries are skipped and
+// reported in the returned error.
+func ParseIPNets(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	var errs []error
+	for _, entry := range entries {
+		cidr := strings.TrimSpace(entry)
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid IP or CIDR %q", entry))
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, errors.Join(errs...)
+}
+
+// IPInNets reports whether ip (with or without port) is in one of nets
+func IPInNets(ip string, nets []*net.IPNet) bool {
+	if len(nets) == 0 {
+		return false
+	}
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIPNets(t *testing.T) {
+	nets, err := ParseIPNets([]string{"10.0.0.0/8", "192.0.2.1", "::1", "not-an-ip"})
+	if len(nets) != 3 {
+		t.Errorf("len(nets) = %d, want 3", len(nets))
+	}
+	if err == nil || !strings.Contains(err.Error(), `"not-an-ip"`) {
+		t.Errorf("err = %v, want the invalid entry reported", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"10.1.2.3:4000", true},
+		{"192.0.2.1", true},
+		{"192.0.2.2", false},
+		{"[::1]:80", true},
+		{"garbage", false},
+	}
+	for _, tt := range tests {
+		if got := IPInNets(tt.ip, nets); got != tt.want {
+			t.Errorf("IPInNets(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
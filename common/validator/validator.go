@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/primadi/lokstra/common/enum"
 	"github.com/primadi/lokstra/core/response/api_formatter"
 )
 
@@ -14,10 +15,19 @@ import (
 // Returns error if validation fails, nil if valid
 type ValidatorFunc func(fieldName string, fieldValue reflect.Value, ruleValue string) error
 
+// StructValidatorFunc validates a whole struct after its fields were validated
+// (e.g. cross-field rules). It receives a pointer to the struct and returns
+// the field errors found; errors for fields that already failed are dropped.
+type StructValidatorFunc func(structPtr any) []api_formatter.FieldError
+
 var (
 	// validatorRegistry repositorys registered validator functions
 	validatorRegistry sync.Map // map[string]ValidatorFunc
 
+	// structValidatorRegistry repositorys struct-level validators per struct type
+	structValidatorRegistry sync.Map // map[reflect.Type][]StructValidatorFunc
+	structValidatorMu       sync.Mutex
+
 	validatorMetaCache sync.Map // map[reflect.Type]*validatorMeta
 )
 
@@ -28,6 +38,41 @@ func RegisterValidator(name string, fn ValidatorFunc) {
 	validatorRegistry.Store(name, fn)
 }
 
+// RegisterStructValidator registers a struct-level validator for the given struct type
+// (pointer types are dereferenced). Multiple validators per type run in registration order.
+//
+// Example:
+//
+//	validator.RegisterStructValidator(reflect.TypeFor[SignupRequest](), func(v any) []api_formatter.FieldError {
+//	    req := v.(*SignupRequest)
+//	    if req.Password != req.Confirm {
+//	        return []api_formatter.FieldError{{Field: "confirm", Message: "confirm must match password"}}
+//	    }
+//	    return nil
+//	})
+func RegisterStructValidator(structType reflect.Type, fn StructValidatorFunc) {
+	for structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	structValidatorMu.Lock()
+	defer structValidatorMu.Unlock()
+	var fns []StructValidatorFunc
+	if existing, ok := structValidatorRegistry.Load(structType); ok {
+		fns = append(fns, existing.([]StructValidatorFunc)...)
+	}
+	structValidatorRegistry.Store(structType, append(fns, fn))
+}
+
+// getStructValidators retrieves struct-level validators for a struct type
+func getStructValidators(structType reflect.Type) []StructValidatorFunc {
+	fns, ok := structValidatorRegistry.Load(structType)
+	if !ok {
+		return nil
+	}
+	return fns.([]StructValidatorFunc)
+}
+
 // getValidator retrieves a validator function by name
 func getValidator(name string) (ValidatorFunc, bool) {
 	fn, ok := validatorRegistry.Load(name)
@@ -48,6 +93,7 @@ func init() {
 	RegisterValidator("lt", validateLt)
 	RegisterValidator("lte", validateLte)
 	RegisterValidator("oneof", validateOneOf)
+	RegisterValidator("enum", validateEnum)
 }
 
 // validatorFieldMeta contains cached metadata for a single field
@@ -101,7 +147,8 @@ func getOrBuildValidatorMeta(t reflect.Type) *validatorMeta {
 
 		// Get validate tag
 		validateTag := field.Tag.Get("validate")
-		if validateTag == "" {
+		isEnum := implementsEnum(field.Type)
+		if validateTag == "" && !isEnum {
 			continue
 		}
 
@@ -116,6 +163,10 @@ func getOrBuildValidatorMeta(t reflect.Type) *validatorMeta {
 
 		// Parse validation rules
 		rules := parseValidationRules(validateTag)
+		if isEnum {
+			// Typed enums are always checked, after explicit rules (e.g. required)
+			rules = append(rules, validationRule{Name: "enum"})
+		}
 		if len(rules) == 0 {
 			continue
 		}
@@ -206,6 +257,30 @@ func ValidateStruct(structData any) ([]api_formatter.FieldError, error) {
 		}
 	}
 
+	// Run struct-level validators after field validation
+	if structFns := getStructValidators(val.Type()); len(structFns) > 0 {
+		var structPtr any
+		if val.CanAddr() {
+			structPtr = val.Addr().Interface()
+		} else {
+			ptr := reflect.New(val.Type())
+			ptr.Elem().Set(val)
+			structPtr = ptr.Interface()
+		}
+		failed := make(map[string]bool, len(fieldErrors))
+		for _, fe := range fieldErrors {
+			failed[fe.Field] = true
+		}
+		for _, fn := range structFns {
+			for _, fe := range fn(structPtr) {
+				// Field-level errors take precedence over cross-field rules
+				if !failed[fe.Field] {
+					fieldErrors = append(fieldErrors, fe)
+				}
+			}
+		}
+	}
+
 	return fieldErrors, nil
 }
 
@@ -260,10 +335,15 @@ func validateRequired(fieldName string, fieldValue reflect.Value, ruleValue stri
 		}
 	case reflect.Bool:
 		// Bool is always valid for required
-	case reflect.Slice, reflect.Map, reflect.Array:
+	case reflect.Slice, reflect.Map:
 		if fieldValue.Len() == 0 {
 			return fmt.Errorf("%s is required", fieldName)
 		}
+	case reflect.Array:
+		// Fixed-size IDs (uuid.UUID, id.ULID) are empty when zero
+		if fieldValue.IsZero() {
+			return fmt.Errorf("%s is required", fieldName)
+		}
 	}
 	return nil
 }
@@ -440,3 +520,29 @@ func validateOneOf(fieldName string, fieldValue reflect.Value, ruleValue string)
 
 	return fmt.Errorf("%s must be one of: %s", fieldName, strings.Join(validValues, ", "))
 }
+
+var enumType = reflect.TypeFor[enum.Enum]()
+
+// implementsEnum checks if a field type (or its pointer element) is a typed enum
+func implementsEnum(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Implements(enumType)
+}
+
+func validateEnum(fieldName string, fieldValue reflect.Value, ruleValue string) error {
+	if !fieldValue.IsValid() || fieldValue.IsZero() {
+		return nil // Use required tag to check for empty
+	}
+
+	e, ok := fieldValue.Interface().(enum.Enum)
+	if !ok || e.Valid() {
+		return nil
+	}
+
+	if lister, ok := e.(enum.Lister); ok {
+		return fmt.Errorf("%s must be one of: %s", fieldName, strings.Join(lister.Values(), ", "))
+	}
+	return fmt.Errorf("%s has an invalid value", fieldName)
+}
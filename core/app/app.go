@@ -2,13 +2,18 @@ package app
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/primadi/lokstra/common/logger"
 	"github.com/primadi/lokstra/core/app/listener"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
 	"github.com/primadi/lokstra/core/router"
 	"github.com/primadi/lokstra/lokstra_handler"
 )
@@ -17,8 +22,29 @@ type App struct {
 	name           string
 	mainRouter     router.Router
 	listenerConfig map[string]any
+	errorHandler   request.ErrorHandler
+	bodyHooks      request.BodyHooks
 
-	listener listener.AppListener
+	// responses for requests no route matched (404/405), and the body of
+	// framework-generated errors
+	notFound         request.HandlerFunc
+	methodNotAllowed request.HandlerFunc
+	errorBody        request.ErrorBodyFunc
+
+	// add request id, route name and response time headers to responses
+	standardHeaders bool
+
+	// router chain serving requests, set by Handler and replaced by SwapRouter
+	serving atomic.Pointer[servingRouter]
+
+	listenerMu sync.Mutex
+	listener   listener.AppListener
+	// Shutdown ran before Start created the listener
+	stopBeforeStart bool
+}
+
+type servingRouter struct {
+	router.Router
 }
 
 // Create a new App instance with default listener configuration
@@ -61,9 +87,46 @@ func (a *App) GetAddress() string {
 
 // Get the main router of the app
 func (a *App) GetRouter() router.Router {
+	if s := a.serving.Load(); s != nil {
+		return s.Router
+	}
 	return a.mainRouter
 }
 
+// SwapRouter atomically replaces the router chain served by the app, e.g.
+// after a config reload, and returns the previous one. Routers are cloned,
+// chained and built before the swap: new requests are matched against the
+// new route table while in-flight requests finish on the old one. Built
+// routers cannot be modified, so the old table stays consistent until its
+// last request completes. Pass routers created for the swap; routes are
+// not shared with the serving chain.
+//
+// Example:
+//
+//	cfgWatcher.OnChange(func() {
+//	    app.SwapRouter(buildApiRouter(loadConfig()))
+//	})
+func (a *App) SwapRouter(routers ...router.Router) router.Router {
+	var main router.Router
+	for _, rt := range routers {
+		r := rt.Clone()
+		if main == nil {
+			main = r
+		} else {
+			main.SetNextChainWithPrefix(r, "")
+		}
+	}
+	if main == nil {
+		main = router.New(a.name)
+	}
+	main.Build()
+	old := a.serving.Swap(&servingRouter{main})
+	if old == nil {
+		return a.mainRouter
+	}
+	return old.Router
+}
+
 // Add a router to the app. If there's already a router, it will be chained.
 func (a *App) AddRouter(rt router.Router) {
 	a.AddRouterWithPrefix(rt, "")
@@ -148,11 +211,11 @@ func (a *App) AddReverseProxies(proxies []*ReverseProxyConfig) {
 }
 
 func (a *App) NumRouters() int {
-	if a.mainRouter == nil {
+	curRouter := a.GetRouter()
+	if curRouter == nil {
 		return 0
 	}
 
-	curRouter := a.mainRouter
 	count := 0
 
 	for curRouter != nil {
@@ -167,22 +230,243 @@ func (a *App) PrintStartInfo() {
 	logger.LogInfo("Starting [%s] with %d router(s) on address %s",
 		a.name, a.NumRouters(), a.listenerConfig["addr"])
 
-	if a.mainRouter != nil {
-		a.mainRouter.PrintRoutes()
+	if rt := a.GetRouter(); rt != nil {
+		rt.PrintRoutes()
+	}
+}
+
+// SetErrorHandler sets the app-level error handler, a final catch-all that
+// turns errors returned by handlers (and recovered panics, as
+// *request.PanicError) into responses. The matched route is available via
+// c.Route(). Returning nil falls back to the default error response.
+//
+// Example:
+//
+//	app.SetErrorHandler(func(c *request.Context, err error) *response.Response {
+//	    if strings.HasPrefix(c.Route().Path, "/internal") {
+//	        return response.NewJsonResponse(map[string]any{"error": err.Error()}).
+//	            WithStatus(http.StatusInternalServerError)
+//	    }
+//	    return nil
+//	})
+func (a *App) SetErrorHandler(h request.ErrorHandler) {
+	a.errorHandler = h
+}
+
+// SetNotFoundHandler sets the handler answering requests no route matches.
+// The status is preset to 404; the handler can write any body (JSON shape,
+// branded HTML page, ...).
+//
+// Example:
+//
+//	app.SetNotFoundHandler(func(c *request.Context) error {
+//	    return c.Resp.Html(notFoundPage)
+//	})
+func (a *App) SetNotFoundHandler(h request.HandlerFunc) {
+	a.notFound = h
+}
+
+// SetMethodNotAllowedHandler sets the handler answering requests whose path
+// matches a route but not its method. The status is preset to 405 and the
+// Allow header is kept.
+func (a *App) SetMethodNotAllowedHandler(h request.HandlerFunc) {
+	a.methodNotAllowed = h
+}
+
+// SetDefaultErrorBody sets the builder of error bodies the framework writes
+// itself: unmatched routes (404/405, unless a handler is set above),
+// recovered panics and unhandled errors (500) and timeouts (504). Statuses
+// stay as the framework sets them; use r to negotiate the content type.
+//
+// Example:
+//
+//	app.SetDefaultErrorBody(func(r *http.Request, status int, code, message string) *response.Response {
+//	    if strings.Contains(r.Header.Get("Accept"), "text/html") {
+//	        return response.NewHtmlResponse(renderErrorPage(status, message))
+//	    }
+//	    return response.NewJsonResponse(map[string]any{"error": code, "detail": message})
+//	})
+func (a *App) SetDefaultErrorBody(f request.ErrorBodyFunc) {
+	a.errorBody = f
+}
+
+// OnRequestBody adds a hook receiving request bodies, e.g. for audit or
+// compliance logging. Bodies are copied as the handler reads them, so
+// binding and streaming are unaffected; the hook runs after the response
+// was written. Use request.WithHookRoutes and request.WithHookRedactFields
+// to limit and redact what is captured, and request.AuditTo to append to
+// an audit sink.
+//
+// Example:
+//
+//	sink, _ := request.NewFileAuditSink("/var/log/app/audit.jsonl")
+//	app.OnRequestBody(request.AuditTo(sink, request.AuditRequest),
+//	    request.WithHookRoutes("/api/payments/**"),
+//	    request.WithHookRedactFields("card_number", "cvv"))
+func (a *App) OnRequestBody(hook request.BodyHook, opts ...request.BodyHookOption) {
+	a.bodyHooks.OnRequestBody(hook, opts...)
+}
+
+// OnResponseBody adds a hook receiving response bodies. Writes (and flushes)
+// pass through unchanged; see OnRequestBody for options.
+func (a *App) OnResponseBody(hook request.BodyHook, opts ...request.BodyHookOption) {
+	a.bodyHooks.OnResponseBody(hook, opts...)
+}
+
+// WithStandardResponseHeaders makes every response, including error and
+// 404/405 responses, carry the request id (X-Request-ID), the matched route
+// name (X-Route-Name) and the server processing time (X-Response-Time).
+// Requests get an id even without the request_id middleware; with it, the
+// id it assigns is used.
+//
+// Example:
+//
+//	a := app.New("api", ":8080", r).WithStandardResponseHeaders()
+func (a *App) WithStandardResponseHeaders() *App {
+	a.standardHeaders = true
+	return a
+}
+
+// Handler returns the http.Handler served by the app:
+// the main router with app-level settings applied
+func (a *App) Handler() http.Handler {
+	a.serving.CompareAndSwap(nil, &servingRouter{a.mainRouter})
+	hasFallbacks := a.notFound != nil || a.methodNotAllowed != nil || a.errorBody != nil
+	if a.errorHandler == nil && a.bodyHooks.IsEmpty() && !hasFallbacks && !a.standardHeaders {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			a.serving.Load().ServeHTTP(w, r)
+		})
+	}
+	h := a.errorHandler
+	hooks := &a.bodyHooks
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if a.standardHeaders {
+			ctx, w = withStandardHeaders(ctx, w)
+		}
+		if h != nil {
+			ctx = request.WithErrorHandler(ctx, h)
+		}
+		if !hooks.IsEmpty() {
+			ctx = request.WithBodyHooks(ctx, hooks)
+		}
+		if a.errorBody != nil {
+			ctx = request.WithErrorBody(ctx, a.errorBody)
+		}
+		if !hasFallbacks {
+			a.serving.Load().ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		ctx, matched := request.TrackRouteMatch(ctx)
+		r = r.WithContext(ctx)
+		uw := &unmatchedWriter{ResponseWriter: w, app: a, matched: matched}
+		a.serving.Load().ServeHTTP(response.WrapWriter(w, uw), r)
+		if uw.status != 0 {
+			a.writeUnmatched(w, r, uw.status)
+		}
+	})
+}
+
+// writeUnmatched answers a request no route matched with the configured
+// handler or error body
+func (a *App) writeUnmatched(w http.ResponseWriter, r *http.Request, status int) {
+	// Drop headers of the router's own response (Allow is kept for 405)
+	w.Header().Del("Content-Type")
+	w.Header().Del("X-Content-Type-Options")
+
+	h := a.notFound
+	if status == http.StatusMethodNotAllowed {
+		h = a.methodNotAllowed
 	}
+	if h != nil {
+		request.HandlerFunc(func(c *request.Context) error {
+			c.Resp.WithStatus(status)
+			return h(c)
+		}).ServeHTTP(w, r)
+		return
+	}
+
+	code, message := "NOT_FOUND", "Not found"
+	if status == http.StatusMethodNotAllowed {
+		code, message = "METHOD_NOT_ALLOWED", "Method not allowed"
+	}
+	request.NewDefaultErrorResponse(a.errorBody, r, status, code, message).WriteHttpRequest(w, r)
+}
+
+// unmatchedWriter swallows the 404/405 the router writes when no route
+// matches, so the app can write its own response instead
+type unmatchedWriter struct {
+	http.ResponseWriter
+	app     *App
+	matched func() bool
+	status  int // swallowed status, 0 if passed through
+}
+
+func (w *unmatchedWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	if (code == http.StatusNotFound || code == http.StatusMethodNotAllowed) &&
+		!w.matched() && w.app.customizes(code) {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *unmatchedWriter) Write(b []byte) (int, error) {
+	if w.status != 0 {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *unmatchedWriter) Flush() {
+	if w.status == 0 {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// customizes reports whether the app replaces the router's response for status
+func (a *App) customizes(status int) bool {
+	if a.errorBody != nil {
+		return true
+	}
+	if status == http.StatusMethodNotAllowed {
+		return a.methodNotAllowed != nil
+	}
+	return a.notFound != nil
 }
 
 // Start the app. It blocks until the app stops or returns an error.
 // Shutdown must be called separately.
 func (a *App) Start() error {
-	a.listener = listener.CreateListener(a.listenerConfig, a.mainRouter)
-	return a.listener.ListenAndServe()
+	a.listenerMu.Lock()
+	if a.stopBeforeStart {
+		// Shut down while starting (e.g. another server of a group failed)
+		a.stopBeforeStart = false
+		a.listenerMu.Unlock()
+		return nil
+	}
+	l := listener.CreateListener(a.listenerConfig, a.Handler())
+	a.listener = l
+	a.listenerMu.Unlock()
+	return l.ListenAndServe()
 }
 
 // Shutdown gracefully shuts down the app with a timeout.
+// Called before Start, it makes the next Start return immediately.
 func (a *App) Shutdown(timeout time.Duration) error {
-	if a.listener != nil {
-		return a.listener.Shutdown(timeout)
+	a.listenerMu.Lock()
+	l := a.listener
+	if l == nil {
+		a.stopBeforeStart = true
+	}
+	a.listenerMu.Unlock()
+
+	if l != nil {
+		return l.Shutdown(timeout)
 	}
 	return nil
 }
@@ -205,7 +489,10 @@ func (a *App) Run(timeout time.Duration) error {
 	select {
 	case sig := <-stop:
 		logger.LogInfo("Received shutdown signal: %v", sig)
-		if err := a.Shutdown(timeout); err != nil {
+		err := a.Shutdown(timeout)
+		// Write buffered log lines (async log output) before the process exits
+		_ = logger.Flush()
+		if err != nil {
 			return fmt.Errorf("shutdown error: %w", err)
 		}
 		return nil
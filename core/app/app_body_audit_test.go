@@ -0,0 +1,148 @@
+package app_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/primadi/lokstra/core/app"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	records []*request.AuditRecord
+}
+
+func (s *memoryAuditSink) Append(rec *request.AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+	return nil
+}
+
+type createPaymentRequest struct {
+	Amount     int    `json:"amount"`
+	CardNumber string `json:"card_number"`
+}
+
+func newAuditApp(sink request.AuditSink) *app.App {
+	r := router.New("api")
+	r.POST("/payments", func(c *request.Context) error {
+		var req createPaymentRequest
+		if err := c.Req.BindBody(&req); err != nil {
+			return err
+		}
+		return c.Api.Created(map[string]any{"amount": req.Amount, "card_number": req.CardNumber}, "created")
+	}, route.WithNameOption("create-payment"))
+	r.GET("/health", func(c *request.Context) error {
+		return c.Api.Ok("up")
+	})
+
+	a := app.New("test-app", ":0", r)
+	a.OnRequestBody(request.AuditTo(sink, request.AuditRequest),
+		request.WithHookRoutes("api.create-payment"),
+		request.WithHookRedactFields("card_number"))
+	a.OnResponseBody(request.AuditTo(sink, request.AuditResponse),
+		request.WithHookRoutes("/payments"),
+		request.WithHookRedactFields("card_number"))
+	return a
+}
+
+func TestOnRequestBody_AuditWithRedaction(t *testing.T) {
+	sink := &memoryAuditSink{}
+	h := newAuditApp(sink).Handler()
+
+	req := httptest.NewRequest("POST", "/payments", strings.NewReader(`{"amount":100,"card_number":"4111111111111111"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	// Binding still works: the handler saw the real card number
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), "4111111111111111") {
+		t.Fatalf("Expected handler to bind the body, got %d %s", w.Code, w.Body.String())
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("Expected request and response records, got %d", len(sink.records))
+	}
+	reqRec, respRec := sink.records[0], sink.records[1]
+	if reqRec.Kind != request.AuditRequest || reqRec.Route != "api.create-payment" || reqRec.Method != "POST" {
+		t.Errorf("Unexpected request record %+v", reqRec)
+	}
+	if respRec.Kind != request.AuditResponse || respRec.Status != http.StatusCreated {
+		t.Errorf("Unexpected response record %+v", respRec)
+	}
+	for _, rec := range sink.records {
+		if strings.Contains(rec.Body, "4111") {
+			t.Errorf("%s record leaked card number: %s", rec.Kind, rec.Body)
+		}
+		if !strings.Contains(rec.Body, `"card_number":"[REDACTED]"`) || !strings.Contains(rec.Body, `"amount":100`) {
+			t.Errorf("%s record not redacted as expected: %s", rec.Kind, rec.Body)
+		}
+	}
+
+	// Other routes are not audited
+	sink.records = nil
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	if len(sink.records) != 0 {
+		t.Errorf("Expected no records for unmatched route, got %d", len(sink.records))
+	}
+}
+
+func TestOnRequestBody_UnreadBodyCaptured(t *testing.T) {
+	var captured string
+	r := router.New("api")
+	r.POST("/webhook", func(c *request.Context) error {
+		return c.Api.Ok("ignored") // body never read
+	})
+	a := app.New("test-app", ":0", r)
+	a.OnRequestBody(func(c *request.Context, body []byte) { captured = string(body) })
+
+	w := httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"event":"paid"}`)))
+
+	if w.Code != http.StatusOK || captured != `{"event":"paid"}` {
+		t.Errorf("Expected unread body to be captured, got %d %q", w.Code, captured)
+	}
+}
+
+func TestFileAuditSink_AppendOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for i := range 2 {
+		sink, err := request.NewFileAuditSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Append(&request.AuditRecord{Kind: request.AuditRequest, Path: "/p", Body: string(rune('a' + i))}); err != nil {
+			t.Fatal(err)
+		}
+		sink.Close()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var bodies []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec request.AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatal(err)
+		}
+		bodies = append(bodies, rec.Body)
+	}
+	if len(bodies) != 2 || bodies[0] != "a" || bodies[1] != "b" {
+		t.Errorf("Expected records appended across reopen, got %v", bodies)
+	}
+}
@@ -0,0 +1,116 @@
+package app_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/app"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/recovery"
+)
+
+func newErrorHandlerApp(mw ...any) *app.App {
+	r := router.New("api")
+	r.GET("/users/{id}", func(c *request.Context) error {
+		return errors.New("db down")
+	}, append(mw, route.WithNameOption("get-user"))...)
+	r.GET("/panic", func(c *request.Context) error {
+		panic("boom")
+	}, append(mw, route.WithNameOption("panic"))...)
+	r.GET("/ok", func(c *request.Context) error {
+		return c.Api.Ok("fine")
+	})
+
+	a := app.New("test-app", ":0", r)
+	a.SetErrorHandler(func(c *request.Context, err error) *response.Response {
+		body := map[string]any{"route": c.Route().Name, "pattern": c.Route().Path}
+		var pe *request.PanicError
+		if errors.As(err, &pe) {
+			body["panic"] = pe.Value
+		} else {
+			body["error"] = err.Error()
+		}
+		return response.NewJsonResponse(body).WithStatus(http.StatusTeapot)
+	})
+	return a
+}
+
+func serve(h http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestSetErrorHandler_ReturnedError(t *testing.T) {
+	w := serve(newErrorHandlerApp().Handler(), "/users/42")
+
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("Expected status 418, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{`"error":"db down"`, `"route":"api.get-user"`, `"pattern":"/users/{id}"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected body to contain %s, got %s", want, body)
+		}
+	}
+	// Written like every other response: explicit Content-Type, no sniffing
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", ct)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("Expected X-Content-Type-Options nosniff, got %q", got)
+	}
+}
+
+func TestSetErrorHandler_Panic(t *testing.T) {
+	tests := []struct {
+		name string
+		mw   []any
+	}{
+		{name: "without recovery middleware"},
+		{name: "with recovery middleware", mw: []any{recovery.Middleware(&recovery.Config{})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newErrorHandlerApp(tt.mw...).Handler(), "/panic")
+
+			if w.Code != http.StatusTeapot {
+				t.Fatalf("Expected status 418, got %d: %s", w.Code, w.Body.String())
+			}
+			body := w.Body.String()
+			if !strings.Contains(body, `"panic":"boom"`) || !strings.Contains(body, `"route":"api.panic"`) {
+				t.Errorf("Unexpected body: %s", body)
+			}
+		})
+	}
+}
+
+func TestSetErrorHandler_NotCalledOnSuccess(t *testing.T) {
+	w := serve(newErrorHandlerApp().Handler(), "/ok")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "fine") {
+		t.Errorf("Expected 200 with body, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetErrorHandler_NilFallsBackToDefault(t *testing.T) {
+	r := router.New("api")
+	r.GET("/fail", func(c *request.Context) error {
+		return errors.New("db down")
+	})
+	a := app.New("test-app", ":0", r)
+	a.SetErrorHandler(func(c *request.Context, err error) *response.Response {
+		return nil
+	})
+
+	w := serve(a.Handler(), "/fail")
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "db down") {
+		t.Errorf("Expected default 500 response, got %d: %s", w.Code, w.Body.String())
+	}
+}
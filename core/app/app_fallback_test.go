@@ -0,0 +1,122 @@
+package app_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/app"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func newFallbackApp() *app.App {
+	r := router.New("api")
+	r.GET("/users/{id}", func(c *request.Context) error {
+		if c.Req.PathParam("id", "") == "0" {
+			return c.Api.NotFound("user not found")
+		}
+		return c.Api.Ok("user")
+	})
+	r.GET("/fail", func(c *request.Context) error {
+		return errors.New("db down")
+	})
+	r.GET("/slow", func(c *request.Context) error {
+		defer c.SetTimeout(time.Millisecond)()
+		<-c.Done()
+		return c.Err()
+	})
+	return app.New("test-app", ":0", r)
+}
+
+func TestSetNotFoundHandler(t *testing.T) {
+	a := newFallbackApp()
+	a.SetNotFoundHandler(func(c *request.Context) error {
+		return c.Resp.Json(map[string]string{"error": "nothing at " + c.R.URL.Path})
+	})
+	h := a.Handler()
+
+	w := serve(h, "/missing")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"error":"nothing at /missing"`) {
+		t.Errorf("expected custom body, got %s", body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	// A 404 returned by a matched route is not replaced
+	w = serve(h, "/users/0")
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "user not found") {
+		t.Errorf("expected handler's own 404, got %d %s", w.Code, w.Body.String())
+	}
+	if w = serve(h, "/users/1"); w.Code != http.StatusOK {
+		t.Errorf("expected 200 for matched route, got %d", w.Code)
+	}
+}
+
+func TestSetMethodNotAllowedHandler(t *testing.T) {
+	a := newFallbackApp()
+	a.SetMethodNotAllowedHandler(func(c *request.Context) error {
+		return c.Resp.Text("use " + c.W.Header().Get("Allow"))
+	})
+
+	w := httptest.NewRecorder()
+	a.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/users/1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Body.String(), "use GET") {
+		t.Errorf("expected custom body with Allow header, got %q", w.Body.String())
+	}
+
+	// Not found keeps the router default when only 405 is customized
+	if w := serve(a.Handler(), "/missing"); w.Code != http.StatusNotFound ||
+		!strings.Contains(w.Body.String(), "404 page not found") {
+		t.Errorf("expected default 404, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestSetDefaultErrorBody(t *testing.T) {
+	a := newFallbackApp()
+	a.SetDefaultErrorBody(func(r *http.Request, status int, code, message string) *response.Response {
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			return response.NewHtmlResponse("<h1>" + message + "</h1>")
+		}
+		return response.NewJsonResponse(map[string]any{"error": code, "detail": message})
+	})
+	h := a.Handler()
+
+	tests := []struct {
+		path   string
+		accept string
+		status int
+		body   string
+	}{
+		{"/missing", "", http.StatusNotFound, `{"detail":"Not found","error":"NOT_FOUND"}`},
+		{"/missing", "text/html", http.StatusNotFound, `<h1>Not found</h1>`},
+		{"/fail", "", http.StatusInternalServerError, `{"detail":"db down","error":"INTERNAL_ERROR"}`},
+		{"/slow", "", http.StatusGatewayTimeout, `{"detail":"Request timed out","error":"TIMEOUT"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.body {
+				t.Errorf("expected body %s, got %s", tt.body, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package app_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/primadi/lokstra/core/app"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/request_id"
+)
+
+var responseTimePattern = regexp.MustCompile(`^\d+\.\d{3}ms$`)
+
+func newStandardHeadersApp(mw ...any) *app.App {
+	r := router.New("api")
+	r.Use(mw...)
+	r.GET("/users/{id}", func(c *request.Context) error {
+		return c.Api.Ok(map[string]any{"id": c.Req.PathParam("id", "")})
+	}, route.WithNameOption("get-user"))
+	r.GET("/fail", func(c *request.Context) error {
+		return errors.New("db down")
+	}, route.WithNameOption("fail"))
+	r.GET("/raw", func(c *request.Context) error {
+		c.W.Write([]byte("streamed"))
+		return nil
+	}, route.WithNameOption("raw"))
+	return app.New("test-app", ":0", r).WithStandardResponseHeaders()
+}
+
+func assertStandardHeaders(t *testing.T, w *httptest.ResponseRecorder, routeName string) {
+	t.Helper()
+	if w.Header().Get(app.HeaderRequestID) == "" {
+		t.Errorf("Expected %s header", app.HeaderRequestID)
+	}
+	if got := w.Header().Get(app.HeaderRouteName); got != routeName {
+		t.Errorf("%s = %q, want %q", app.HeaderRouteName, got, routeName)
+	}
+	if got := w.Header().Get(app.HeaderResponseTime); !responseTimePattern.MatchString(got) {
+		t.Errorf("Unexpected %s %q", app.HeaderResponseTime, got)
+	}
+}
+
+func TestStandardResponseHeaders(t *testing.T) {
+	h := newStandardHeadersApp().Handler()
+
+	tests := []struct {
+		path      string
+		status    int
+		routeName string
+	}{
+		{"/users/42", http.StatusOK, "api.get-user"},
+		{"/fail", http.StatusInternalServerError, "api.fail"},
+		{"/raw", http.StatusOK, "api.raw"},
+		{"/missing", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serve(h, tt.path)
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			assertStandardHeaders(t, w, tt.routeName)
+		})
+	}
+
+	a, b := serve(h, "/users/1"), serve(h, "/users/1")
+	if a.Header().Get(app.HeaderRequestID) == b.Header().Get(app.HeaderRequestID) {
+		t.Error("Expected a new request id per request")
+	}
+}
+
+func TestStandardResponseHeaders_CustomNotFound(t *testing.T) {
+	a := newStandardHeadersApp()
+	a.SetNotFoundHandler(func(c *request.Context) error {
+		return c.Resp.Html("<h1>Not here</h1>")
+	})
+
+	w := serve(a.Handler(), "/missing")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", w.Code)
+	}
+	assertStandardHeaders(t, w, "")
+}
+
+func TestStandardResponseHeaders_UsesRequestIDMiddleware(t *testing.T) {
+	h := newStandardHeadersApp(request_id.Middleware(&request_id.Config{TrustIncoming: true})).Handler()
+
+	req := httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set(request_id.HeaderRequestID, "upstream-123")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if got := w.Header().Get(app.HeaderRequestID); got != "upstream-123" {
+		t.Errorf("Expected the middleware's request id, got %q", got)
+	}
+	assertStandardHeaders(t, w, "api.fail")
+}
+
+func TestStandardResponseHeaders_IDVisibleToHandlers(t *testing.T) {
+	var seen string
+	r := router.New("api")
+	r.GET("/id", func(c *request.Context) error {
+		seen = c.RequestID()
+		return c.Api.Ok(nil)
+	})
+	w := serve(app.New("test-app", ":0", r).WithStandardResponseHeaders().Handler(), "/id")
+
+	if seen == "" || w.Header().Get(app.HeaderRequestID) != seen {
+		t.Errorf("Expected handler id %q in the response, got %q", seen, w.Header().Get(app.HeaderRequestID))
+	}
+}
+
+func TestStandardResponseHeaders_OffByDefault(t *testing.T) {
+	r := router.New("api")
+	r.GET("/ok", func(c *request.Context) error { return c.Api.Ok(nil) })
+
+	w := serve(app.New("test-app", ":0", r).Handler(), "/ok")
+	for _, h := range []string{app.HeaderRequestID, app.HeaderRouteName, app.HeaderResponseTime} {
+		if got := w.Header().Get(h); got != "" {
+			t.Errorf("Expected no %s header, got %q", h, got)
+		}
+	}
+}
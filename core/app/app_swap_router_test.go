@@ -0,0 +1,140 @@
+package app_test
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/primadi/lokstra/core/app"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func versionRouter(version string, mw ...any) router.Router {
+	r := router.New("api")
+	r.Use(mw...)
+	r.GET("/version", func(c *request.Context) error {
+		return c.Resp.WithStatus(http.StatusOK).Text(version)
+	})
+	return r
+}
+
+func TestSwapRouter(t *testing.T) {
+	a := app.New("test-app", ":0", versionRouter("v1"))
+	h := a.Handler()
+
+	if w := serve(h, "/version"); w.Body.String() != "v1" {
+		t.Fatalf("expected v1, got %q", w.Body.String())
+	}
+
+	v2 := versionRouter("v2")
+	v2.GET("/new", func(c *request.Context) error {
+		return c.Resp.WithStatus(http.StatusOK).Text("new")
+	})
+	old := a.SwapRouter(v2)
+	if old == nil || old.Name() != "api" {
+		t.Fatalf("expected previous router to be returned, got %v", old)
+	}
+
+	if w := serve(h, "/version"); w.Body.String() != "v2" {
+		t.Fatalf("expected v2 after swap, got %q", w.Body.String())
+	}
+	if w := serve(h, "/new"); w.Code != http.StatusOK {
+		t.Fatalf("expected new route to be served, got %d", w.Code)
+	}
+	if !a.GetRouter().IsBuilt() {
+		t.Fatal("expected swapped router to be built")
+	}
+}
+
+func TestSwapRouter_InFlightRequestFinishesOnOldRouter(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	block := func(c *request.Context) error {
+		close(started)
+		<-release
+		return c.Next()
+	}
+	a := app.New("test-app", ":0", versionRouter("v1", block))
+	h := a.Handler()
+
+	done := make(chan string)
+	go func() {
+		done <- serve(h, "/version").Body.String()
+	}()
+	<-started
+
+	a.SwapRouter(versionRouter("v2"))
+	if w := serve(h, "/version"); w.Body.String() != "v2" {
+		t.Fatalf("expected new request on v2, got %q", w.Body.String())
+	}
+
+	close(release)
+	if body := <-done; body != "v1" {
+		t.Fatalf("expected in-flight request to finish on v1, got %q", body)
+	}
+}
+
+func TestSwapRouter_UnderLoad(t *testing.T) {
+	a := app.New("test-app", ":0", versionRouter("v0"))
+	h := a.Handler()
+
+	var stop atomic.Bool
+	var served atomic.Int64
+	var wg sync.WaitGroup
+	errs := make(chan string, 8)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				w := serve(h, "/version")
+				if w.Code != http.StatusOK || len(w.Body.String()) < 2 {
+					select {
+					case errs <- fmt.Sprintf("status %d, body %q", w.Code, w.Body.String()):
+					default:
+					}
+					return
+				}
+				served.Add(1)
+			}
+		}()
+	}
+
+	for i := 1; i <= 50; i++ {
+		// let some requests through between swaps
+		for n := served.Load(); served.Load() == n && len(errs) == 0; {
+			runtime.Gosched()
+		}
+		a.SwapRouter(versionRouter(fmt.Sprintf("v%d", i)))
+	}
+	stop.Store(true)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("request during swap failed: %s", err)
+	}
+	if served.Load() == 0 {
+		t.Fatal("expected requests to be served during swaps")
+	}
+	if w := serve(h, "/version"); w.Body.String() != "v50" {
+		t.Fatalf("expected last swapped router, got %q", w.Body.String())
+	}
+}
+
+func TestSwapRouter_BuiltRouterIsImmutable(t *testing.T) {
+	a := app.New("test-app", ":0", versionRouter("v1"))
+	a.Handler()
+	a.SwapRouter(versionRouter("v2"))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Use on a built router to panic")
+		}
+	}()
+	a.GetRouter().Use(func(c *request.Context) error { return c.Next() })
+}
@@ -0,0 +1,39 @@
+package listener
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+const MAX_REQUESTS_PER_CONN_KEY = "max_requests_per_conn"
+
+// 0 means keep-alive connections serve any number of requests
+const DEFAULT_MAX_REQUESTS_PER_CONN = 0
+
+type connRequestsKey struct{}
+
+// withRequestCounter adds a per-connection request counter to a connection
+// context, for LimitRequestsPerConn (set from http.Server.ConnContext)
+func withRequestCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, connRequestsKey{}, new(atomic.Int64))
+}
+
+// LimitRequestsPerConn answers the maxRequests-th request of a keep-alive
+// connection with "Connection: close", so the server closes it after the
+// response and the client reconnects (e.g. to be balanced onto another
+// instance). Requires the counter set by withRequestCounter; maxRequests
+// <= 0 disables the limit. A client sending "Connection: close" itself is
+// honored by net/http regardless.
+func LimitRequestsPerConn(next http.Handler, maxRequests int) http.Handler {
+	if maxRequests <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if counter, ok := r.Context().Value(connRequestsKey{}).(*atomic.Int64); ok &&
+			counter.Add(1) >= int64(maxRequests) {
+			w.Header().Set("Connection", "close")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,94 @@
+package listener_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/app/listener"
+)
+
+// keepAliveConn sends requests over one connection and reads the responses
+type keepAliveConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialKeepAlive(t *testing.T, url string) *keepAliveConn {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &keepAliveConn{conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (k *keepAliveConn) get(t *testing.T, header string) *http.Response {
+	t.Helper()
+	if _, err := io.WriteString(k.conn, "GET / HTTP/1.1\r\nHost: test\r\n"+header+"\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(k.r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp
+}
+
+// closedByServer reports whether the server closed the connection
+func (k *keepAliveConn) closedByServer() bool {
+	k.conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err := k.r.ReadByte()
+	return errors.Is(err, io.EOF)
+}
+
+func TestNetHttp_MaxRequestsPerConn(t *testing.T) {
+	url, dispatched := startNetHttp(t, map[string]any{
+		listener.MAX_REQUESTS_PER_CONN_KEY: 3,
+	})
+
+	k := dialKeepAlive(t, url)
+	for i := 1; i <= 3; i++ {
+		resp := k.get(t, "")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Request %d: expected 200, got %d", i, resp.StatusCode)
+		}
+		if last := i == 3; resp.Close != last {
+			t.Errorf("Request %d: expected Connection: close = %v, got %v", i, last, resp.Close)
+		}
+	}
+	if !k.closedByServer() {
+		t.Error("Expected the server to close the connection after 3 requests")
+	}
+	if *dispatched != 3 {
+		t.Errorf("Expected 3 dispatched requests, got %d", *dispatched)
+	}
+
+	// A new connection gets a fresh budget
+	if resp := dialKeepAlive(t, url).get(t, ""); resp.Close {
+		t.Error("Expected a new connection to be kept alive")
+	}
+}
+
+func TestNetHttp_ClientConnectionClose(t *testing.T) {
+	url, _ := startNetHttp(t, map[string]any{})
+
+	k := dialKeepAlive(t, url)
+	if resp := k.get(t, ""); resp.Close {
+		t.Fatal("Expected keep-alive without a limit")
+	}
+	if resp := k.get(t, "Connection: close\r\n"); !resp.Close {
+		t.Error("Expected Connection: close to be echoed")
+	}
+	if !k.closedByServer() {
+		t.Error("Expected the server to close the connection the client asked to close")
+	}
+}
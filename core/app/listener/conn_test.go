@@ -0,0 +1,53 @@
+package listener_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/app/listener"
+	"github.com/primadi/lokstra/core/request"
+)
+
+func TestNetHttp_ExposesConn(t *testing.T) {
+	addr := freeAddr(t)
+	l := listener.NewNetHttp(map[string]any{"addr": addr}, request.HandlerFunc(func(c *request.Context) error {
+		conn, ok := c.Conn()
+		if !ok {
+			return c.Api.Error(500, "NO_CONN", "connection not available")
+		}
+		return c.Resp.WithStatus(200).Text(conn.RemoteAddr().String())
+	}))
+	go l.ListenAndServe()
+	t.Cleanup(func() { l.Shutdown(time.Second) })
+
+	var conn net.Conn
+	var err error
+	for range 50 {
+		if conn, err = net.Dial("tcp", addr); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: test\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if want := conn.LocalAddr().String(); resp.StatusCode != 200 || string(body) != want {
+		t.Errorf("Expected the handler to see peer %s, got %d %q", want, resp.StatusCode, body)
+	}
+}
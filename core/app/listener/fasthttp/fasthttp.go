@@ -136,6 +136,13 @@ func NewFastHttp(config map[string]any, handler http.Handler) listener.AppListen
 	readTimeout := utils.GetValueFromMap(config, listener.READ_TIMEOUT_KEY, listener.DEFAULT_READ_TIMEOUT)
 	writeTimeout := utils.GetValueFromMap(config, listener.WRITE_TIMEOUT_KEY, listener.DEFAULT_WRITE_TIMEOUT)
 	idleTimeout := utils.GetValueFromMap(config, listener.IDLE_TIMEOUT_KEY, listener.DEFAULT_IDLE_TIMEOUT)
+	// The read buffer bounds the header size (431 when exceeded);
+	// 0 keeps the fasthttp default
+	maxHeaderBytes := utils.GetValueFromMap(config, listener.MAX_HEADER_BYTES_KEY, 0)
+	maxHeaderCount := utils.GetValueFromMap(config, listener.MAX_HEADER_COUNT_KEY, listener.DEFAULT_MAX_HEADER_COUNT)
+	// fasthttp sends "Connection: close" on the last request itself
+	maxRequestsPerConn := utils.GetValueFromMap(config, listener.MAX_REQUESTS_PER_CONN_KEY,
+		listener.DEFAULT_MAX_REQUESTS_PER_CONN)
 
 	secure := utils.GetValueFromMap(config, "secure", false)
 	var certFile, keyFile, caFile string
@@ -147,7 +154,7 @@ func NewFastHttp(config map[string]any, handler http.Handler) listener.AppListen
 
 	return &FastHttp{
 		addr:     addr,
-		handler:  handler,
+		handler:  listener.LimitHeaderCount(handler, maxHeaderCount),
 		secure:   secure,
 		certFile: certFile,
 		keyFile:  keyFile,
@@ -156,6 +163,9 @@ func NewFastHttp(config map[string]any, handler http.Handler) listener.AppListen
 			ReadTimeout:  readTimeout,
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
+
+			ReadBufferSize:     maxHeaderBytes,
+			MaxRequestsPerConn: maxRequestsPerConn,
 		},
 	}
 }
@@ -0,0 +1,34 @@
+package listener
+
+import "net/http"
+
+const MAX_HEADER_BYTES_KEY = "max_header_bytes"
+const MAX_HEADER_COUNT_KEY = "max_header_count"
+
+// net/http default; fasthttp keeps its own (4KB read buffer) unless configured
+const DEFAULT_MAX_HEADER_BYTES = http.DefaultMaxHeaderBytes
+
+// 0 means any number of header fields (the count limit is opt-in)
+const DEFAULT_MAX_HEADER_COUNT = 0
+
+// LimitHeaderCount rejects requests carrying more than maxCount header
+// fields with 431 Request Header Fields Too Large, before the handler is
+// dispatched. maxCount <= 0 disables the check.
+func LimitHeaderCount(next http.Handler, maxCount int) http.Handler {
+	if maxCount <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := 0
+		for _, values := range r.Header {
+			count += len(values)
+		}
+		if count > maxCount {
+			w.Header().Set("Connection", "close")
+			http.Error(w, http.StatusText(http.StatusRequestHeaderFieldsTooLarge),
+				http.StatusRequestHeaderFieldsTooLarge)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
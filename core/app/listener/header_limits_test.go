@@ -0,0 +1,103 @@
+package listener_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/app/listener"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func startNetHttp(t *testing.T, config map[string]any) (string, *int) {
+	t.Helper()
+	addr := freeAddr(t)
+	config["addr"] = addr
+
+	dispatched := new(int)
+	l := listener.NewNetHttp(config, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*dispatched++
+		w.Write([]byte("ok"))
+	}))
+	go l.ListenAndServe()
+	t.Cleanup(func() { l.Shutdown(time.Second) })
+
+	for range 50 {
+		if c, err := net.Dial("tcp", addr); err == nil {
+			c.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return "http://" + addr, dispatched
+}
+
+func TestNetHttp_HeaderLimits(t *testing.T) {
+	url, dispatched := startNetHttp(t, map[string]any{
+		listener.MAX_HEADER_BYTES_KEY: 1024,
+		listener.MAX_HEADER_COUNT_KEY: 10,
+	})
+
+	send := func(header http.Header) int {
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := send(http.Header{"X-Small": {"1"}}); code != http.StatusOK {
+		t.Fatalf("Expected 200 for small headers, got %d", code)
+	}
+
+	// net/http allows some buffering slack above MaxHeaderBytes
+	big := http.Header{"X-Big": {strings.Repeat("a", 16<<10)}}
+	if code := send(big); code != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected 431 for oversized header, got %d", code)
+	}
+
+	many := http.Header{}
+	for i := range 20 {
+		many.Set(fmt.Sprintf("X-H%d", i), "v")
+	}
+	if code := send(many); code != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected 431 for too many headers, got %d", code)
+	}
+
+	if *dispatched != 1 {
+		t.Errorf("Expected rejected requests not to reach the handler, dispatched %d", *dispatched)
+	}
+}
+
+func TestNetHttp_HeaderCountUnlimitedByDefault(t *testing.T) {
+	url, dispatched := startNetHttp(t, map[string]any{})
+
+	req, _ := http.NewRequest("GET", url, nil)
+	for i := range 300 {
+		req.Header.Set(fmt.Sprintf("X-Custom-%d", i), "v")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || *dispatched != 1 {
+		t.Errorf("Expected many headers to pass without a configured limit, got %d", resp.StatusCode)
+	}
+}
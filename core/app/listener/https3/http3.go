@@ -94,19 +94,22 @@ var _ listener.AppListener = (*Http3)(nil)
 func NewHttp3(config map[string]any, handler http.Handler) listener.AppListener {
 	idleTimeout := utils.GetValueFromMap(config, listener.IDLE_TIMEOUT_KEY, listener.DEFAULT_IDLE_TIMEOUT)
 	addr := utils.GetValueFromMap(config, "addr", ":8080")
+	maxHeaderBytes := utils.GetValueFromMap(config, listener.MAX_HEADER_BYTES_KEY, listener.DEFAULT_MAX_HEADER_BYTES)
+	maxHeaderCount := utils.GetValueFromMap(config, listener.MAX_HEADER_COUNT_KEY, listener.DEFAULT_MAX_HEADER_COUNT)
 
 	certFile := utils.GetValueFromMap(config, listener.CERT_FILE_KEY, "")
 	keyFile := utils.GetValueFromMap(config, listener.KEY_FILE_KEY, "")
 	caFile := utils.GetValueFromMap(config, listener.CA_FILE_KEY, "")
 
 	return &Http3{
-		handler:  handler,
+		handler:  listener.LimitHeaderCount(handler, maxHeaderCount),
 		certFile: certFile,
 		keyFile:  keyFile,
 		caFile:   caFile,
 		server: &http3.Server{
-			Addr:        addr,
-			IdleTimeout: idleTimeout,
+			Addr:           addr,
+			IdleTimeout:    idleTimeout,
+			MaxHeaderBytes: maxHeaderBytes,
 		},
 	}
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/primadi/lokstra/common/logger"
 	"github.com/primadi/lokstra/common/utils"
 	listener_utils "github.com/primadi/lokstra/core/app/listener/utils"
+	"github.com/primadi/lokstra/core/request"
 )
 
 const READ_TIMEOUT_KEY = "read_timeout"
@@ -147,6 +148,10 @@ func NewNetHttp(config map[string]any, handler http.Handler) AppListener {
 	}
 	writeTimeout := utils.GetValueFromMap(config, WRITE_TIMEOUT_KEY, DEFAULT_WRITE_TIMEOUT)
 	idleTimeout := utils.GetValueFromMap(config, IDLE_TIMEOUT_KEY, DEFAULT_IDLE_TIMEOUT)
+	// Oversized headers are answered with 431 by net/http itself
+	maxHeaderBytes := utils.GetValueFromMap(config, MAX_HEADER_BYTES_KEY, DEFAULT_MAX_HEADER_BYTES)
+	maxHeaderCount := utils.GetValueFromMap(config, MAX_HEADER_COUNT_KEY, DEFAULT_MAX_HEADER_COUNT)
+	maxRequestsPerConn := utils.GetValueFromMap(config, MAX_REQUESTS_PER_CONN_KEY, DEFAULT_MAX_REQUESTS_PER_CONN)
 
 	secure := utils.GetValueFromMap(config, "secure", false)
 	var certFile, keyFile, caFile string
@@ -157,7 +162,7 @@ func NewNetHttp(config map[string]any, handler http.Handler) AppListener {
 	}
 
 	return &NetHttp{
-		handler:  handler,
+		handler:  LimitRequestsPerConn(LimitHeaderCount(handler, maxHeaderCount), maxRequestsPerConn),
 		secure:   secure,
 		certFile: certFile,
 		keyFile:  keyFile,
@@ -168,10 +173,24 @@ func NewNetHttp(config map[string]any, handler http.Handler) AppListener {
 			ReadHeaderTimeout: readHeaderTimeout,
 			WriteTimeout:      writeTimeout,
 			IdleTimeout:       idleTimeout,
+			MaxHeaderBytes:    maxHeaderBytes,
+			ConnContext:       connContext(maxRequestsPerConn),
 		},
 	}
 }
 
+// connContext exposes the connection to handlers (see request.Context.Conn)
+// and counts its requests when maxRequestsPerConn is set
+func connContext(maxRequestsPerConn int) func(context.Context, net.Conn) context.Context {
+	return func(ctx context.Context, c net.Conn) context.Context {
+		ctx = request.ConnContext(ctx, c)
+		if maxRequestsPerConn > 0 {
+			ctx = withRequestCounter(ctx)
+		}
+		return ctx
+	}
+}
+
 func init() {
 	RegisterListener("nethttp", NewNetHttp)
 	RegisterListener("default", NewNetHttp)
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+)
+
+// Headers added by WithStandardResponseHeaders
+const (
+	HeaderRequestID    = request.HeaderRequestID
+	HeaderRouteName    = "X-Route-Name"
+	HeaderResponseTime = "X-Response-Time"
+)
+
+// withStandardHeaders assigns the request an id (unless it has one) and
+// wraps w to add the standard headers when the status is written
+func withStandardHeaders(ctx context.Context, w http.ResponseWriter) (context.Context, http.ResponseWriter) {
+	if request.RequestIDFromContext(ctx) == "" {
+		ctx = request.WithRequestID(ctx, uuid.New().String())
+	}
+	ctx, trace := request.TrackResponse(ctx)
+	return ctx, response.WrapWriter(w, &standardHeadersWriter{ResponseWriter: w, trace: trace})
+}
+
+// standardHeadersWriter sets the standard headers right before the status
+// is sent, once the route matched and middleware set the request id.
+// The response time of streamed responses is the time to the first byte.
+type standardHeadersWriter struct {
+	http.ResponseWriter
+	trace   *request.ResponseTrace
+	written bool
+}
+
+func (w *standardHeadersWriter) WriteHeader(code int) {
+	if !w.written && code >= http.StatusOK {
+		w.written = true
+		h := w.ResponseWriter.Header()
+		h.Set(HeaderRequestID, w.trace.RequestID())
+		if name := w.trace.RouteName(); name != "" {
+			h.Set(HeaderRouteName, name)
+		}
+		h.Set(HeaderResponseTime, fmt.Sprintf("%.3fms", float64(w.trace.Elapsed().Microseconds())/1000))
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *standardHeadersWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *standardHeadersWriter) Flush() {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
@@ -0,0 +1,436 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/common/validator"
+	"github.com/primadi/lokstra/core/deploy"
+)
+
+// FieldError describes a single config key that failed to bind or validate
+type FieldError struct {
+	Key     string // Full config key (e.g., "payment.gateway.url")
+	Message string
+}
+
+// BindError is returned by Bind when one or more keys are invalid.
+// All problems are reported at once instead of stopping at the first one.
+type BindError struct {
+	Section string
+	Errors  []FieldError
+}
+
+func (e *BindError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Key + ": " + fe.Message
+	}
+	if e.Section == "" {
+		return "invalid config: " + strings.Join(parts, "; ")
+	}
+	return fmt.Sprintf("invalid config '%s': %s", e.Section, strings.Join(parts, "; "))
+}
+
+// Bind loads a config section into a struct.
+//
+// Field keys come from the `config` tag, then the `json` tag, then the
+// snake_case field name. Missing keys use the `default` tag, and the result
+// is validated with `validate` tags (see common/validator).
+// Nested structs bind nested sections.
+//
+// Example:
+//
+//	type PaymentConfig struct {
+//	    Gateway string        `config:"gateway" validate:"required"`
+//	    Timeout time.Duration `config:"timeout" default:"30s"`
+//	    Retry   struct {
+//	        Max int `config:"max" default:"3" validate:"min=1"`
+//	    } `config:"retry"`
+//	}
+//
+//	var cfg PaymentConfig
+//	if err := config.Bind("payment", &cfg); err != nil {
+//	    panic(err)
+//	}
+func Bind(section string, target any) error {
+	values, _ := deploy.Global().GetConfig(section)
+	m, ok := values.(map[string]any)
+	if values != nil && !ok {
+		return &BindError{Section: section, Errors: []FieldError{
+			{Key: section, Message: fmt.Sprintf("expected a map, got %T", values)},
+		}}
+	}
+	return bind(section, m, target)
+}
+
+// MustBind is like Bind but panics on error
+func MustBind(section string, target any) {
+	if err := Bind(section, target); err != nil {
+		panic(err)
+	}
+}
+
+// BindMap binds a raw config map into a struct, applying defaults and validation.
+// Useful in service factories to replace utils.GetValueFromMap boilerplate:
+//
+//	func ServiceFactory(params map[string]any) any {
+//	    cfg := &Config{}
+//	    if err := config.BindMap(params, cfg); err != nil {
+//	        panic(err)
+//	    }
+//	    return Service(cfg)
+//	}
+func BindMap(values map[string]any, target any) error {
+	return bind("", values, target)
+}
+
+func bind(section string, values map[string]any, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config bind target must be a non-nil pointer to struct, got %T", target)
+	}
+
+	b := &binder{}
+	b.bindStruct(section, values, rv.Elem())
+	if len(b.errors) > 0 {
+		return &BindError{Section: section, Errors: b.errors}
+	}
+	return nil
+}
+
+type binder struct {
+	errors []FieldError
+}
+
+func (b *binder) fail(key, format string, args ...any) {
+	b.errors = append(b.errors, FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (b *binder) bindStruct(prefix string, values map[string]any, sv reflect.Value) {
+	st := sv.Type()
+	keys := make(map[string]string, st.NumField()) // validator field name -> config key
+
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := fieldKey(field)
+		if name == "-" {
+			continue
+		}
+		key := joinKey(prefix, name)
+		keys[validatorName(field)] = key
+
+		fv := sv.Field(i)
+		raw, found := lookup(values, name)
+
+		// Nested section
+		if isStruct(field.Type) {
+			nested, _ := raw.(map[string]any)
+			if found && nested == nil && raw != nil {
+				b.fail(key, "expected a map, got %T", raw)
+				continue
+			}
+			if field.Type.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					fv.Set(reflect.New(field.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			b.bindStruct(key, nested, fv)
+			continue
+		}
+
+		if !found {
+			def, hasDefault := field.Tag.Lookup("default")
+			if !hasDefault {
+				continue
+			}
+			raw = def
+		}
+
+		if err := assign(fv, raw); err != nil {
+			b.fail(key, "%v", err)
+		}
+	}
+
+	// Validate this level; nested levels are validated in their own call
+	fieldErrors, err := validator.ValidateStruct(sv.Addr().Interface())
+	if err != nil {
+		b.fail(prefix, "%v", err)
+		return
+	}
+	for _, fe := range fieldErrors {
+		key, ok := keys[fe.Field]
+		if !ok {
+			key = joinKey(prefix, fe.Field)
+		}
+		b.fail(key, "%s", fe.Message)
+	}
+}
+
+// fieldKey returns the config key for a struct field
+func fieldKey(field reflect.StructField) string {
+	if tag := field.Tag.Get("config"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	return utils.CamelToSnake(field.Name)
+}
+
+// validatorName mirrors the name common/validator uses in FieldError.Field
+func validatorName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// lookup finds a key case-insensitively (registry keys are stored lowercase)
+func lookup(values map[string]any, name string) (any, bool) {
+	if values == nil {
+		return nil, false
+	}
+	if v, ok := values[name]; ok {
+		return v, true
+	}
+	for k, v := range values {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// assign converts raw config value into the field type
+func assign(fv reflect.Value, raw any) error {
+	if raw == nil {
+		return nil
+	}
+
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := assign(ptr.Elem(), raw); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	rv := reflect.ValueOf(raw)
+	if rv.Type().AssignableTo(fv.Type()) {
+		fv.Set(rv)
+		return nil
+	}
+
+	if s, ok := raw.(string); ok && fv.CanAddr() {
+		if tu, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return tu.UnmarshalText([]byte(s))
+		}
+	}
+
+	if fv.Type() == durationType {
+		switch v := raw.(type) {
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", v)
+			}
+			fv.SetInt(int64(d))
+			return nil
+		case int, int64, float64:
+			// Numbers are seconds (same as utils.GetDurationFromMap)
+			f, _ := toFloat(v)
+			fv.SetInt(int64(f * float64(time.Second)))
+			return nil
+		}
+		return fmt.Errorf("cannot use %T as duration", raw)
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(fmt.Sprint(raw))
+		return nil
+
+	case reflect.Bool:
+		switch v := raw.(type) {
+		case bool:
+			fv.SetBool(v)
+			return nil
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+			fv.SetBool(parsed)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s, ok := raw.(string); ok {
+			n, err := strconv.ParseInt(strings.TrimSpace(s), 10, fv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("invalid integer %q", s)
+			}
+			fv.SetInt(n)
+			return nil
+		}
+		if f, ok := toFloat(raw); ok {
+			if f != float64(int64(f)) || fv.OverflowInt(int64(f)) {
+				return fmt.Errorf("value %v out of range for %s", raw, fv.Type())
+			}
+			fv.SetInt(int64(f))
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s, ok := raw.(string); ok {
+			n, err := strconv.ParseUint(strings.TrimSpace(s), 10, fv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("invalid unsigned integer %q", s)
+			}
+			fv.SetUint(n)
+			return nil
+		}
+		if f, ok := toFloat(raw); ok {
+			if f < 0 || f != float64(uint64(f)) || fv.OverflowUint(uint64(f)) {
+				return fmt.Errorf("value %v out of range for %s", raw, fv.Type())
+			}
+			fv.SetUint(uint64(f))
+			return nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if s, ok := raw.(string); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(s), fv.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("invalid number %q", s)
+			}
+			fv.SetFloat(f)
+			return nil
+		}
+		if f, ok := toFloat(raw); ok {
+			fv.SetFloat(f)
+			return nil
+		}
+
+	case reflect.Slice:
+		var items []any
+		switch v := raw.(type) {
+		case []any:
+			items = v
+		case string:
+			// Comma separated (mainly for default tags)
+			for part := range strings.SplitSeq(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					items = append(items, part)
+				}
+			}
+		default:
+			if rv.Kind() != reflect.Slice {
+				return fmt.Errorf("cannot use %T as %s", raw, fv.Type())
+			}
+			for i := 0; i < rv.Len(); i++ {
+				items = append(items, rv.Index(i).Interface())
+			}
+		}
+		slice := reflect.MakeSlice(fv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := assign(slice.Index(i), item); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		fv.Set(slice)
+		return nil
+
+	case reflect.Map:
+		m, ok := raw.(map[string]any)
+		if !ok || fv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := reflect.MakeMapWithSize(fv.Type(), len(m))
+		for k, v := range m {
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			if err := assign(elem, v); err != nil {
+				return fmt.Errorf("key %s: %w", k, err)
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(fv.Type().Key()), elem)
+		}
+		fv.Set(out)
+		return nil
+
+	case reflect.Interface:
+		if rv.Type().Implements(fv.Type()) {
+			fv.Set(rv)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot use %T as %s", raw, fv.Type())
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,159 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/config"
+	"github.com/primadi/lokstra/core/deploy"
+)
+
+type RetryConfig struct {
+	Max     int           `config:"max" default:"3" validate:"min=1"`
+	Backoff time.Duration `config:"backoff" default:"200ms"`
+}
+
+type PaymentConfig struct {
+	Gateway    string            `config:"gateway" validate:"required"`
+	Timeout    time.Duration     `config:"timeout" default:"30s"`
+	Currencies []string          `config:"currencies" default:"USD,EUR"`
+	Sandbox    bool              `config:"sandbox"`
+	Retry      RetryConfig       `config:"retry"`
+	Webhook    *WebhookConfig    `config:"webhook"`
+	Labels     map[string]string `config:"labels"`
+	MaxAmount  float64           // snake_case key: max_amount
+}
+
+type WebhookConfig struct {
+	URL    string `config:"url" validate:"required"`
+	Secret string `config:"secret"`
+}
+
+func TestBind_NestedSections(t *testing.T) {
+	deploy.Global().SetConfig("payment", map[string]any{
+		"gateway":    "stripe",
+		"timeout":    "5s",
+		"currencies": []any{"IDR", "SGD"},
+		"sandbox":    true,
+		"max_amount": 1500.5,
+		"retry": map[string]any{
+			"max":     5,
+			"backoff": "1s",
+		},
+		"webhook": map[string]any{
+			"url":    "https://example.com/hook",
+			"secret": "s3cr3t",
+		},
+		"labels": map[string]any{"team": "billing"},
+	})
+
+	var cfg PaymentConfig
+	if err := config.Bind("payment", &cfg); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	if cfg.Gateway != "stripe" {
+		t.Errorf("Expected gateway 'stripe', got %q", cfg.Gateway)
+	}
+	if cfg.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", cfg.Timeout)
+	}
+	if len(cfg.Currencies) != 2 || cfg.Currencies[0] != "IDR" {
+		t.Errorf("Expected currencies [IDR SGD], got %v", cfg.Currencies)
+	}
+	if !cfg.Sandbox {
+		t.Error("Expected sandbox true")
+	}
+	if cfg.MaxAmount != 1500.5 {
+		t.Errorf("Expected max_amount 1500.5, got %v", cfg.MaxAmount)
+	}
+	if cfg.Retry.Max != 5 || cfg.Retry.Backoff != time.Second {
+		t.Errorf("Expected retry {5 1s}, got %+v", cfg.Retry)
+	}
+	if cfg.Webhook == nil || cfg.Webhook.URL != "https://example.com/hook" {
+		t.Errorf("Expected webhook url, got %+v", cfg.Webhook)
+	}
+	if cfg.Labels["team"] != "billing" {
+		t.Errorf("Expected label team=billing, got %v", cfg.Labels)
+	}
+}
+
+func TestBind_Defaults(t *testing.T) {
+	deploy.Global().SetConfig("payment-defaults", map[string]any{
+		"gateway": "midtrans",
+		"webhook": map[string]any{"url": "https://example.com"},
+	})
+
+	var cfg PaymentConfig
+	if err := config.Bind("payment-defaults", &cfg); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Expected default timeout 30s, got %v", cfg.Timeout)
+	}
+	if len(cfg.Currencies) != 2 || cfg.Currencies[1] != "EUR" {
+		t.Errorf("Expected default currencies [USD EUR], got %v", cfg.Currencies)
+	}
+	if cfg.Retry.Max != 3 || cfg.Retry.Backoff != 200*time.Millisecond {
+		t.Errorf("Expected default retry {3 200ms}, got %+v", cfg.Retry)
+	}
+}
+
+func TestBind_ValidationErrors(t *testing.T) {
+	deploy.Global().SetConfig("payment-invalid", map[string]any{
+		"timeout": "soon",
+		"retry":   map[string]any{"max": 0},
+		"webhook": map[string]any{},
+	})
+
+	var cfg PaymentConfig
+	err := config.Bind("payment-invalid", &cfg)
+	if err == nil {
+		t.Fatal("Expected bind error, got nil")
+	}
+
+	var bindErr *config.BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Expected *config.BindError, got %T", err)
+	}
+
+	keys := map[string]bool{}
+	for _, fe := range bindErr.Errors {
+		keys[fe.Key] = true
+	}
+	for _, expected := range []string{
+		"payment-invalid.timeout",
+		"payment-invalid.gateway",
+		"payment-invalid.retry.max",
+		"payment-invalid.webhook.url",
+	} {
+		if !keys[expected] {
+			t.Errorf("Expected error for %s, got %v", expected, bindErr.Errors)
+		}
+	}
+	if !strings.Contains(err.Error(), "payment-invalid.gateway") {
+		t.Errorf("Expected error message to mention key, got %q", err.Error())
+	}
+}
+
+func TestBindMap_FactoryParams(t *testing.T) {
+	type Config struct {
+		Namespace string `config:"namespace" default:"app"`
+		Workers   int    `config:"workers" default:"4"`
+	}
+
+	cfg := &Config{}
+	if err := config.BindMap(map[string]any{"workers": 8}, cfg); err != nil {
+		t.Fatalf("BindMap failed: %v", err)
+	}
+	if cfg.Namespace != "app" || cfg.Workers != 8 {
+		t.Errorf("Expected {app 8}, got %+v", cfg)
+	}
+
+	if err := config.BindMap(nil, "not a pointer"); err == nil {
+		t.Error("Expected error for non-pointer target")
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/primadi/lokstra/core/deploy"
+)
+
+// GetEnum reads a config value that must be one of valid. A missing key
+// returns defaultValue; a value that is not allowed (or not convertible to
+// T) is an error, so a typo fails at startup instead of silently falling
+// back to the default.
+//
+// Example:
+//
+//	type DeploymentType string
+//
+//	const (
+//	    Monolith      DeploymentType = "monolith"
+//	    Microservices DeploymentType = "microservices"
+//	)
+//
+//	mode, err := config.GetEnum("deployment-type",
+//	    []DeploymentType{Monolith, Microservices}, Monolith)
+//	if err != nil {
+//	    panic(err)
+//	}
+func GetEnum[T comparable](key string, valid []T, defaultValue T) (T, error) {
+	if !slices.Contains(valid, defaultValue) {
+		return defaultValue, enumError(key, valid, fmt.Sprintf("default %v is not an allowed value", defaultValue))
+	}
+
+	raw, ok := deploy.Global().GetConfig(key)
+	if !ok || raw == nil {
+		deploy.Global().RecordConfigDefault(key, defaultValue, nil)
+		return defaultValue, nil
+	}
+
+	var value T
+	if err := assign(reflect.ValueOf(&value).Elem(), raw); err != nil {
+		return defaultValue, enumError(key, valid, fmt.Sprintf("got %#v (%v)", raw, err))
+	}
+	if !slices.Contains(valid, value) {
+		return defaultValue, enumError(key, valid, fmt.Sprintf("got %#v", raw))
+	}
+	return value, nil
+}
+
+// MustGetEnum is like GetEnum but panics on error
+func MustGetEnum[T comparable](key string, valid []T, defaultValue T) T {
+	value, err := GetEnum(key, valid, defaultValue)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func enumError[T any](key string, valid []T, detail string) error {
+	options := make([]string, len(valid))
+	for i, v := range valid {
+		options[i] = fmt.Sprint(v)
+	}
+	return &BindError{Errors: []FieldError{{
+		Key:     key,
+		Message: fmt.Sprintf("must be one of [%s], %s", strings.Join(options, ", "), detail),
+	}}}
+}
@@ -0,0 +1,77 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/config"
+	"github.com/primadi/lokstra/core/deploy"
+)
+
+type deploymentType string
+
+const (
+	monolith      deploymentType = "monolith"
+	microservices deploymentType = "microservices"
+)
+
+var deploymentTypes = []deploymentType{monolith, microservices}
+
+func TestGetEnum_Valid(t *testing.T) {
+	deploy.Global().SetConfig("enum-deployment-type", "microservices")
+
+	got, err := config.GetEnum("enum-deployment-type", deploymentTypes, monolith)
+	if err != nil {
+		t.Fatalf("GetEnum failed: %v", err)
+	}
+	if got != microservices {
+		t.Errorf("Expected microservices, got %q", got)
+	}
+
+	deploy.Global().SetConfig("enum-retries", 3)
+	if n, err := config.GetEnum("enum-retries", []int{1, 3, 5}, 1); err != nil || n != 3 {
+		t.Errorf("Expected 3, got %d (%v)", n, err)
+	}
+}
+
+func TestGetEnum_Invalid(t *testing.T) {
+	deploy.Global().SetConfig("enum-deployment-invalid", "microservice")
+
+	got, err := config.GetEnum("enum-deployment-invalid", deploymentTypes, monolith)
+	var bindErr *config.BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Expected a BindError, got %v", err)
+	}
+	if got != monolith {
+		t.Errorf("Expected the default on error, got %q", got)
+	}
+	for _, msg := range []string{"enum-deployment-invalid", "monolith, microservices", `"microservice"`} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Error %q should mention %s", err, msg)
+		}
+	}
+
+	deploy.Global().SetConfig("enum-retries-invalid", "often")
+	if _, err := config.GetEnum("enum-retries-invalid", []int{1, 3, 5}, 1); err == nil {
+		t.Error("Expected an error for a value not convertible to int")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGetEnum should panic on an invalid value")
+		}
+	}()
+	config.MustGetEnum("enum-deployment-invalid", deploymentTypes, monolith)
+}
+
+func TestGetEnum_Default(t *testing.T) {
+	got, err := config.GetEnum("enum-deployment-missing", deploymentTypes, monolith)
+	if err != nil || got != monolith {
+		t.Errorf("Expected default monolith, got %q (%v)", got, err)
+	}
+
+	if _, err := config.GetEnum("enum-deployment-missing", deploymentTypes, "hybrid"); err == nil {
+		t.Error("Expected an error for a default that is not allowed")
+	}
+}
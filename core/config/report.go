@@ -0,0 +1,10 @@
+package config
+
+import "github.com/primadi/lokstra/lokstra_registry"
+
+// ReportUnused returns a warning per config key set but never read (often a
+// typo) and per key read with its default value. PrintServerStartInfo
+// includes these warnings; see lokstra_registry.ReportUnusedConfig.
+func ReportUnused() []string {
+	return lokstra_registry.ReportUnusedConfig()
+}
@@ -0,0 +1,19 @@
+package config_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/primadi/lokstra/core/config"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+func TestReportUnused(t *testing.T) {
+	lokstra_registry.SetConfig("report-test.databse-url", "postgres://db")
+	lokstra_registry.GetConfig("report-test.database-url", "")
+
+	want := "config key 'report-test.databse-url' set but never read (did you mean 'report-test.database-url'?)"
+	if !slices.Contains(config.ReportUnused(), want) {
+		t.Errorf("Expected warning %q, got %q", want, config.ReportUnused())
+	}
+}
@@ -0,0 +1,144 @@
+package deploy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ConfigDefault is a config key read with a default value, because it was
+// not set or its value could not be used as the requested type
+type ConfigDefault struct {
+	Key     string `json:"key"`
+	Default any    `json:"default"`
+	// SetType is the type of the unusable value, empty when not set
+	SetType string `json:"set_type,omitempty"`
+}
+
+// ConfigUsage reports config keys set but never read (often typos) and
+// keys read with their default value
+type ConfigUsage struct {
+	Unused    []string        `json:"unused"`
+	Defaulted []ConfigDefault `json:"defaulted"`
+}
+
+type configUsage struct {
+	mu        sync.RWMutex
+	reads     map[string]struct{}
+	defaulted map[string]ConfigDefault
+}
+
+func (u *configUsage) markRead(lowerKey string) {
+	u.mu.RLock()
+	_, seen := u.reads[lowerKey]
+	u.mu.RUnlock()
+	if seen {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.reads == nil {
+		u.reads = make(map[string]struct{})
+	}
+	u.reads[lowerKey] = struct{}{}
+}
+
+// MarkConfigRead marks keys as read, for configs consumed outside
+// GetConfig (e.g. ${@cfg:key} references resolved by the loader) or only
+// read after startup
+func (g *GlobalRegistry) MarkConfigRead(keys ...string) {
+	for _, key := range keys {
+		g.configUsage.markRead(strings.ToLower(key))
+	}
+}
+
+// RecordConfigDefault records that key was read with defaultValue.
+// setValue is the unusable value when the key was set, nil otherwise.
+func (g *GlobalRegistry) RecordConfigDefault(key string, defaultValue any, setValue any) {
+	lowerKey := strings.ToLower(key)
+	u := &g.configUsage
+
+	u.mu.RLock()
+	_, seen := u.defaulted[lowerKey]
+	u.mu.RUnlock()
+	if seen {
+		return
+	}
+
+	entry := ConfigDefault{Key: lowerKey, Default: defaultValue}
+	if setValue != nil {
+		entry.SetType = fmt.Sprintf("%T", setValue)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.defaulted == nil {
+		u.defaulted = make(map[string]ConfigDefault)
+	}
+	u.defaulted[lowerKey] = entry
+}
+
+// ConfigUsage reports the config keys set but not read so far and the keys
+// read with their default value, both sorted. A key counts as read when
+// it, one of its parents ("db" for "db.dsn") or one of its children was
+// read; only the outermost unused key of a map is reported.
+func (g *GlobalRegistry) ConfigUsage() *ConfigUsage {
+	g.mu.RLock()
+	keys := make([]string, 0, len(g.resolvedConfigs))
+	for key := range g.resolvedConfigs {
+		keys = append(keys, key)
+	}
+	g.mu.RUnlock()
+
+	u := &g.configUsage
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	unused := make(map[string]bool)
+	for _, key := range keys {
+		if !u.isRead(key) {
+			unused[key] = true
+		}
+	}
+
+	report := &ConfigUsage{Unused: []string{}, Defaulted: []ConfigDefault{}}
+	for key := range unused {
+		if !unused[parentKey(key)] {
+			report.Unused = append(report.Unused, key)
+		}
+	}
+	slices.Sort(report.Unused)
+
+	for _, d := range u.defaulted {
+		report.Defaulted = append(report.Defaulted, d)
+	}
+	slices.SortFunc(report.Defaulted, func(a, b ConfigDefault) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return report
+}
+
+// isRead reports whether key, a parent or a child of key was read
+func (u *configUsage) isRead(key string) bool {
+	for k := key; k != ""; k = parentKey(k) {
+		if _, ok := u.reads[k]; ok {
+			return true
+		}
+	}
+	prefix := key + "."
+	for read := range u.reads {
+		if strings.HasPrefix(read, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parentKey returns "db" for "db.dsn", "" for a top-level key
+func parentKey(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
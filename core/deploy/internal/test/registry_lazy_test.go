@@ -1,7 +1,12 @@
 package deploy_test
 
 import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/primadi/lokstra/core/deploy"
 )
@@ -171,3 +176,163 @@ func TestRegisterLazyService_MultipleInstances(t *testing.T) {
 		t.Errorf("unexpected db-cache: %v", cache)
 	}
 }
+
+// Test a transient factory failure is retried with backoff, then cached
+func TestRegisterLazyService_RetryAfterFailure(t *testing.T) {
+	g := deploy.NewGlobalRegistry()
+	g.SetServiceRetryPolicy(&deploy.ServiceRetryPolicy{
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	})
+
+	calls := 0
+	g.RegisterLazyService("db", func() any {
+		calls++
+		if calls == 1 {
+			panic(deploy.TransientError(errors.New("connection refused")))
+		}
+		return "db-connection"
+	}, nil)
+
+	// First access: factory fails, panic reaches the caller
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic from failing factory")
+			}
+		}()
+		g.GetServiceAny("db")
+	}()
+
+	// Within backoff: fail fast without calling the factory
+	if _, ok := g.GetServiceAny("db"); ok {
+		t.Error("expected service to be unavailable during backoff")
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry during backoff, got %d calls", calls)
+	}
+	if err := g.GetServiceError("db"); err == nil || err.Error() != "connection refused" {
+		t.Errorf("expected last error 'connection refused', got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	svc, ok := g.GetServiceAny("db")
+	if !ok || svc != "db-connection" {
+		t.Fatalf("expected retry to succeed, got %v, %v", svc, ok)
+	}
+	if err := g.GetServiceError("db"); err != nil {
+		t.Errorf("expected error cleared after success, got %v", err)
+	}
+
+	// Cached afterwards
+	g.GetServiceAny("db")
+	if calls != 2 {
+		t.Errorf("expected 2 factory calls, got %d", calls)
+	}
+}
+
+// Test non-transient failures fail immediately and are never retried
+func TestRegisterLazyService_PermanentFailureNotRetried(t *testing.T) {
+	g := deploy.NewGlobalRegistry()
+	g.SetServiceRetryPolicy(&deploy.ServiceRetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     1,
+	})
+
+	calls := 0
+	g.RegisterLazyService("bad-config", func() any {
+		calls++
+		panic(errors.New("invalid dsn"))
+	}, nil)
+
+	getService := func(name string) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		g.GetServiceAny(name)
+		return false
+	}
+
+	if !getService("bad-config") {
+		t.Fatal("expected panic from failing factory")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if getService("bad-config") {
+		t.Error("expected no new attempt after a permanent failure")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 factory call, got %d", calls)
+	}
+	if err := g.GetServiceError("bad-config"); err == nil || err.Error() != "invalid dsn" {
+		t.Errorf("expected last error 'invalid dsn', got %v", err)
+	}
+}
+
+// Test a circular dependency fails at once, without retry delays
+func TestRegisterLazyService_CircularDependencyNotRetried(t *testing.T) {
+	g := deploy.NewGlobalRegistry()
+	g.SetServiceRetryPolicy(&deploy.ServiceRetryPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     1,
+	})
+
+	var calls atomic.Int32
+	factory := func(deps, cfg map[string]any) any {
+		calls.Add(1)
+		return "unreachable"
+	}
+	g.RegisterLazyServiceWithDeps("svc-a", factory, map[string]string{"b": "svc-b"}, nil)
+	g.RegisterLazyServiceWithDeps("svc-b", factory, map[string]string{"a": "svc-a"}, nil)
+
+	for i := range 2 {
+		func() {
+			defer func() { recover() }()
+			g.GetServiceAny("svc-a")
+		}()
+		if i == 0 {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	err := g.GetServiceError("svc-a")
+	if err == nil || !strings.Contains(err.Error(), "circular dependency") {
+		t.Fatalf("expected circular dependency error, got %v", err)
+	}
+	if deploy.IsTransient(err) {
+		t.Error("expected circular dependency to be permanent")
+	}
+	if _, ok := g.GetServiceAny("svc-a"); ok {
+		t.Error("expected service to stay unavailable")
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected factories never called, got %d", calls.Load())
+	}
+}
+
+// Test concurrent callers share a single factory call
+func TestRegisterLazyService_ConcurrentResolution(t *testing.T) {
+	g := deploy.NewGlobalRegistry()
+
+	var calls atomic.Int32
+	g.RegisterLazyService("slow", func() any {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return "slow-service"
+	}, nil)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			if svc, ok := g.GetServiceAny("slow"); !ok || svc != "slow-service" {
+				t.Errorf("unexpected result: %v, %v", svc, ok)
+			}
+		})
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected factory to be called once, got %d", calls.Load())
+	}
+}
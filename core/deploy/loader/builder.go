@@ -456,6 +456,8 @@ func RepositoryDefinitionsToRegistry(registry *deploy.GlobalRegistry, config *sc
 	// Configs are already resolved at YAML byte level by loader (2-step resolution)
 	// Now we flatten nested maps to dot notation for easy access via GetConfig()
 	flattenAndRepositoryConfigs(registry, config.Configs, "")
+	// Keys consumed by ${@cfg:...} placeholders are used, though never read via GetConfig
+	registry.MarkConfigRead(config.ConfigRefs...)
 
 	// Repository middleware definitions to registry (no runtime registration yet)
 	// Middlewares will be registered in RegisterDefinitionsForRuntime
@@ -534,7 +536,10 @@ func collectAllServiceDependencies(config *schema.DeployConfig, publishedService
 // RegisterDefinitionsForRuntime performs runtime registration of definitions
 // This is called in RunCurrentServer AFTER normalization
 // It registers middlewares, services (with remote/local logic), and auto-generates routers for published services
-func RegisterDefinitionsForRuntime(registry *deploy.GlobalRegistry, config *schema.DeployConfig, deploymentName, serverName string, serverTopo *deploy.ServerTopology) error {
+// mwOpts apply to the middleware name registrations, e.g. deploy.WithAllowOverrideForName
+// when the definitions were already registered for another server of the process
+func RegisterDefinitionsForRuntime(registry *deploy.GlobalRegistry, config *schema.DeployConfig, deploymentName, serverName string,
+	serverTopo *deploy.ServerTopology, mwOpts ...deploy.MiddlewareNameOption) error {
 	// Case-insensitive deployment lookup
 	depDef, ok := getDeploymentDef(config, deploymentName)
 	if !ok {
@@ -550,7 +555,7 @@ func RegisterDefinitionsForRuntime(registry *deploy.GlobalRegistry, config *sche
 	// Register middlewares
 	for name, mw := range config.MiddlewareDefinitions {
 		mw.Name = name
-		registry.RegisterMiddlewareName(name, mw.Type, mw.Config)
+		registry.RegisterMiddlewareName(name, mw.Type, mw.Config, mwOpts...)
 	}
 
 	// Register service definitions
@@ -869,18 +874,34 @@ func LoadConfig(configPaths ...string) (*schema.DeployConfig, error) {
 
 	registry := deploy.Global()
 
-	// Repository original config for inline definitions normalization
-	registry.RepositoryDeployConfig(config)
+	// Apply atomically: if any stage fails, the registry is rolled back to
+	// its prior state so a half-applied config never starts a broken server
+	err = registry.ApplyTransaction(func() error {
+		// Repository original config for inline definitions normalization
+		registry.RepositoryDeployConfig(config)
 
-	// NOTE: normalizeServerDefinitions already called in LoadConfig STEP 9
-	// No need to call again here
+		// NOTE: normalizeServerDefinitions already called in LoadConfig STEP 9
+		// No need to call again here
 
-	// Repository definitions to registry (NO runtime registration, just repository data)
-	// Runtime registration will happen in RunCurrentServer
-	if err := RepositoryDefinitionsToRegistry(registry, config); err != nil {
-		return nil, fmt.Errorf("failed to repository definitions: %w", err)
+		// Repository definitions to registry (NO runtime registration, just repository data)
+		// Runtime registration will happen in RunCurrentServer
+		if err := RepositoryDefinitionsToRegistry(registry, config); err != nil {
+			return fmt.Errorf("failed to repository definitions: %w", err)
+		}
+
+		buildDeploymentTopologies(registry, config)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply config: %w", err)
 	}
 
+	logger.LogDebug("✅ Config loaded successfully from: %v", configPaths)
+	return config, nil
+}
+
+// buildDeploymentTopologies builds and repositories the topology of ALL deployments
+func buildDeploymentTopologies(registry *deploy.GlobalRegistry, config *schema.DeployConfig) {
 	// Build ALL deployments (2-Layer Architecture: YAML -> Topology only)
 	for deploymentName, depDef := range config.Deployments {
 		// Build service location registry (service-name → base-url)
@@ -972,7 +993,4 @@ func LoadConfig(configPaths ...string) (*schema.DeployConfig, error) {
 		// Repository topology in global registry
 		registry.RepositoryDeploymentTopology(deployTopo)
 	}
-
-	logger.LogDebug("✅ Config loaded successfully from: %v", configPaths)
-	return config, nil
 }
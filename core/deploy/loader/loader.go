@@ -116,6 +116,7 @@ func loadConfig(paths ...string) (*schema.DeployConfig, error) {
 	}
 
 	step2Data := resolver.ResolveYAMLBytesStep2(step1Bytes, tempConfig.Configs)
+	configRefs := resolver.ConfigReferences(step1Bytes)
 
 	// STEP 6: Final decode with all values resolved
 	var finalConfig schema.DeployConfig
@@ -124,6 +125,7 @@ func loadConfig(paths ...string) (*schema.DeployConfig, error) {
 	if err := decoder2.Decode(&finalConfig); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML (step 2): %w", err)
 	}
+	finalConfig.ConfigRefs = configRefs
 
 	// STEP 7: Normalize shorthand servers (convert top-level servers → deployments.default.servers)
 	// MUST be done after all resolution to avoid being lost during re-decoding
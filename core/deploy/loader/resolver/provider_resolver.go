@@ -161,6 +161,27 @@ func ResolveYAMLBytesStep2(data []byte, configs map[string]any) []byte {
 	return []byte(content)
 }
 
+// ConfigReferences returns the keys of the ${@cfg:KEY} and
+// ${@cfg:KEY:default} placeholders in data, in order of appearance
+func ConfigReferences(data []byte) []string {
+	var keys []string
+	content := string(data)
+	for {
+		start := strings.Index(content, "${@cfg:")
+		if start == -1 {
+			return keys
+		}
+		content = content[start+7:] // 7 = len("${@cfg:")
+		end := strings.Index(content, "}")
+		if end == -1 {
+			return keys
+		}
+		key, _, _ := strings.Cut(content[:end], ":")
+		keys = append(keys, key)
+		content = content[end+1:]
+	}
+}
+
 // getNestedConfig retrieves a value from nested config map using dot notation
 // Example: "email_smtp.host" -> configs["email_smtp"]["host"]
 func getNestedConfig(configs map[string]any, key string) any {
@@ -0,0 +1,84 @@
+package loader_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/deploy/loader"
+	"github.com/primadi/lokstra/core/deploy/schema"
+)
+
+const txConfig = `
+configs:
+  tx-test-key: applied
+  tx-prior-key: staged
+
+service-definitions:
+  tx-test-service:
+    type: tx-test-factory
+    config:
+      value: ${@cfg:tx-prior-key}
+
+router-definitions:
+  tx-test-router:
+    path-prefix: /api
+
+deployments:
+  tx-test:
+    servers:
+      api:
+        base-url: http://localhost
+        addr: ":8080"
+        routers: [tx-test-router]
+`
+
+func TestLoadConfig_RollbackOnPartialFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(txConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The registry is global: start fresh and leave nothing to other tests
+	deploy.ResetGlobalRegistryForTesting()
+	t.Cleanup(deploy.ResetGlobalRegistryForTesting)
+
+	registry := deploy.Global()
+	priorConfig := registry.GetDeployConfig()
+	registry.SetConfig("tx-prior-key", "prior")
+
+	// The router is already defined, so applying fails after configs and
+	// service definitions have been staged
+	registry.DefineRouter("tx-test-router", &schema.RouterDef{PathPrefix: "/existing"})
+
+	_, err := loader.LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "tx-test-router") {
+		t.Fatalf("Expected error about tx-test-router, got %v", err)
+	}
+
+	if _, ok := registry.GetConfig("tx-test-key"); ok {
+		t.Error("Config value must be rolled back")
+	}
+	// Checked before GetConfig, which marks the key read
+	if unused := registry.ConfigUsage().Unused; !slices.Contains(unused, "tx-prior-key") {
+		t.Errorf("Config reads must be rolled back, unused = %v", unused)
+	}
+	if v, _ := registry.GetConfig("tx-prior-key"); v != "prior" {
+		t.Errorf("Prior config value must be restored, got %v", v)
+	}
+	if entry := registry.GetLazyServiceEntry("tx-test-service"); entry != nil {
+		t.Error("Service definition must be rolled back")
+	}
+	if _, ok := registry.GetServerTopology("tx-test.api"); ok {
+		t.Error("Server topology must not be applied")
+	}
+	if registry.GetDeployConfig() != priorConfig {
+		t.Error("Deploy config must be rolled back")
+	}
+	if def := registry.GetRouterDef("tx-test-router"); def == nil || def.PathPrefix != "/existing" {
+		t.Errorf("Existing router definition must be kept, got %+v", def)
+	}
+}
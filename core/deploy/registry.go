@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
@@ -32,10 +33,12 @@ type GlobalRegistry struct {
 	routerInstances     sync.Map // map[string]router.Router
 	serviceInstances    sync.Map // map[string]any
 	middlewareInstances sync.Map // map[string]request.HandlerFunc
+	handlerInstances    sync.Map // map[string]request.HandlerFunc
 
 	// Lazy service factories (for on-demand creation)
-	lazyServiceFactories sync.Map // map[string]*LazyServiceEntry
-	lazyServiceOnce      sync.Map // map[string]*sync.Once
+	lazyServiceFactories sync.Map            // map[string]*LazyServiceEntry
+	lazyServiceStates    sync.Map            // map[string]*lazyServiceState
+	serviceRetry         *ServiceRetryPolicy // nil = DefaultServiceRetryPolicy
 
 	// Lazy router factories (for deferred router creation)
 	lazyRouterFactories sync.Map // map[string]func() router.Router
@@ -51,6 +54,9 @@ type GlobalRegistry struct {
 	// All configs are repositoryd here after loader's 2-step resolution
 	resolvedConfigs map[string]any
 
+	// Config keys read and defaulted, see ConfigUsage
+	configUsage configUsage
+
 	// Topology storage (2-Layer Architecture)
 	// Single source of truth for runtime topology
 	deploymentTopologies sync.Map // map[deploymentName]*DeploymentTopology
@@ -85,19 +91,50 @@ type LazyServiceEntry struct {
 	Config   map[string]any
 	Deps     map[string]string // Dependency mapping: key in factory -> service name in registry
 	resolved bool              // Has factory been resolved from FactoryType?
+
+	// Guards Factory and resolved: entries are resolved during config apply
+	// while requests may be resolving services
+	mu sync.RWMutex
 }
 
 // IsResolved returns true if the factory has been resolved from FactoryType
 func (e *LazyServiceEntry) IsResolved() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.resolved
 }
 
 // ResolveFactory sets the factory function and marks the entry as resolved
 func (e *LazyServiceEntry) ResolveFactory(factory func(deps, config map[string]any) any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.Factory = factory
 	e.resolved = true
 }
 
+// resolveFactory returns the factory of the entry, resolving its
+// FactoryType with lookup on first use. Returns nil if lookup finds none.
+func (e *LazyServiceEntry) resolveFactory(lookup func(factoryType string) ServiceFactory) func(deps, config map[string]any) any {
+	e.mu.RLock()
+	factory, resolved := e.Factory, e.resolved
+	e.mu.RUnlock()
+	if resolved {
+		return factory
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.resolved {
+		f := lookup(e.FactoryType)
+		if f == nil {
+			return nil
+		}
+		e.Factory = f
+		e.resolved = true
+	}
+	return e.Factory
+}
+
 // ServiceMetadata holds metadata for service auto-generation
 // ServiceMetadata holds metadata for a service type registration
 // Can be populated from ServiceTypeConfig or legacy functional options
@@ -414,6 +451,14 @@ func (g *GlobalRegistry) GetServiceFactory(serviceType string, isLocal bool) Ser
 	return entry.Remote
 }
 
+// HasServiceType checks if a service type (factory) is registered
+func (g *GlobalRegistry) HasServiceType(serviceType string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.serviceFactories[serviceType]
+	return ok
+}
+
 // GetServiceMetadata returns the service metadata for a service type
 func (g *GlobalRegistry) GetServiceMetadata(serviceType string) *ServiceMetadata {
 	g.mu.RLock()
@@ -523,6 +568,7 @@ func (g *GlobalRegistry) GetConfig(name string) (any, bool) {
 
 	// Convert to lowercase for case-insensitive lookup
 	lowerName := strings.ToLower(name)
+	g.configUsage.markRead(lowerName)
 
 	// Try direct lookup first (flat access)
 	if value, ok := g.resolvedConfigs[lowerName]; ok {
@@ -620,6 +666,7 @@ func (g *GlobalRegistry) SimpleResolver(input string) string {
 		// Look up from resolved config registry
 		value, ok := g.GetConfig(key)
 		if !ok {
+			g.RecordConfigDefault(key, defaultValue, nil)
 			return defaultValue
 		}
 
@@ -628,6 +675,7 @@ func (g *GlobalRegistry) SimpleResolver(input string) string {
 			return strValue
 		}
 
+		g.RecordConfigDefault(key, defaultValue, value)
 		return defaultValue
 	})
 }
@@ -678,6 +726,16 @@ func (g *GlobalRegistry) RegisterRouterFactory(name string, factory func() route
 	g.lazyRouterFactories.Store(name, factory)
 }
 
+// HasRouter checks if a router instance or lazy router factory is registered
+// under name, without instantiating it
+func (g *GlobalRegistry) HasRouter(name string) bool {
+	if _, ok := g.routerInstances.Load(name); ok {
+		return true
+	}
+	_, ok := g.lazyRouterFactories.Load(name)
+	return ok
+}
+
 // instantiateLazyRouters creates router instances from registered factories
 // func (g *GlobalRegistry) InstantiateLazyRouters() {
 // 	logger.LogDebug("🔧 InstantiateLazyRouters: starting lazy router instantiation")
@@ -828,6 +886,18 @@ func (g *GlobalRegistry) GetAllRouters() map[string]router.Router {
 	return result
 }
 
+// GetServiceNames returns the names of all instantiated services, sorted
+// (lazy services appear only after their first access)
+func (g *GlobalRegistry) GetServiceNames() []string {
+	var names []string
+	g.serviceInstances.Range(func(key, _ any) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	slices.Sort(names)
+	return names
+}
+
 // RegisterService registers a service instance
 func (g *GlobalRegistry) RegisterService(name string, service any) {
 	if _, exists := g.serviceInstances.Load(name); exists {
@@ -976,8 +1046,8 @@ func (g *GlobalRegistry) registerDeferredService(name, factoryType string, confi
 	}
 
 	g.lazyServiceFactories.Store(name, entry)
-	// NOTE: Do NOT create sync.Once here!
-	// sync.Once will be created in GetServiceAny when entry is resolved
+	// NOTE: Do NOT create lazyServiceState here!
+	// lazyServiceState will be created in GetServiceAny when entry is resolved
 	// This prevents premature instantiation before factory is available
 }
 
@@ -1009,6 +1079,7 @@ func WithRegistrationMode(mode LazyServiceRegistrationMode) LazyServiceOption {
 
 // RegisterLazyServiceWithDeps registers a lazy service with explicit dependency injection.
 // The factory will be called only once, and the result is cached.
+// If the factory panics, it is retried on a later call (see ServiceRetryPolicy).
 //
 // The deps parameter maps dependency keys to service names for auto-injection:
 //
@@ -1137,7 +1208,7 @@ func (g *GlobalRegistry) RegisterLazyServiceWithDeps(name string, factory any, d
 
 	logger.LogDebug("📦 RegisterLazyServiceWithDeps '%s': repositoryd with %d dependencies: %v", name, len(deps), deps)
 	g.lazyServiceFactories.Store(name, entry)
-	g.lazyServiceOnce.Store(name, &sync.Once{})
+	g.lazyServiceStates.Store(name, &lazyServiceState{})
 }
 
 // RegisterLazyServiceUnresolved repositorys an unresolved lazy service entry
@@ -1157,7 +1228,7 @@ func (g *GlobalRegistry) RegisterLazyServiceUnresolved(name, factoryType string,
 	}
 
 	g.lazyServiceFactories.Store(name, entry)
-	g.lazyServiceOnce.Store(name, &sync.Once{})
+	g.lazyServiceStates.Store(name, &lazyServiceState{})
 }
 
 // GetLazyServiceEntry retrieves a lazy service entry by name (for resolution checking)
@@ -1221,9 +1292,9 @@ func (g *GlobalRegistry) getServiceAnyWithStack(name string, resolutionStack []s
 	newStack := utils.NewSliceAndAppend(resolutionStack, name)
 
 	// Check lazy registry and create if needed
-	onceAny, hasOnce := g.lazyServiceOnce.Load(name)
-	if !hasOnce {
-		logger.LogDebug("🔍 GetServiceAny('%s'): NOT in lazyServiceOnce, checking lazyServiceFactories...", name)
+	stateAny, hasState := g.lazyServiceStates.Load(name)
+	if !hasState {
+		logger.LogDebug("🔍 GetServiceAny('%s'): NOT in lazyServiceStates, checking lazyServiceFactories...", name)
 
 		// Not in lazy registry - check if in lazyServiceFactories with unresolved entry
 		if entryAny, exists := g.lazyServiceFactories.Load(name); exists {
@@ -1231,28 +1302,12 @@ func (g *GlobalRegistry) getServiceAnyWithStack(name string, resolutionStack []s
 			entry := entryAny.(*LazyServiceEntry)
 
 			// If unresolved (Phase 1 - from registerDeferredService), resolve it now
-			if !entry.resolved {
-				logger.LogDebug("🔍 GetServiceAny('%s'): entry UNRESOLVED, resolving factory type '%s'...", name, entry.FactoryType)
-				// Get factory for the service type
-				factory := g.GetServiceFactory(entry.FactoryType, true) // true = local factory
-				if factory == nil {
-					logger.LogDebug("🔍 GetServiceAny('%s'): factory '%s' NOT FOUND!", name, entry.FactoryType)
-					panic(fmt.Sprintf("service factory '%s' not registered for service '%s'", entry.FactoryType, name))
-				}
+			g.lazyEntryFactory(name, entry)
 
-				// Resolve the factory (modifies the entry in-place since it's a pointer)
-				entry.Factory = factory
-				entry.resolved = true
-			}
-
-			// Create sync.Once if not exists (handles case where entry was resolved externally)
-			if _, hasOnceAlready := g.lazyServiceOnce.Load(name); !hasOnceAlready {
-				logger.LogDebug("🔍 GetServiceAny('%s'): creating sync.Once (entry was resolved=%v)", name, entry.resolved)
-				g.lazyServiceOnce.Store(name, &sync.Once{})
-			}
-
-			// Now proceed with instantiation below
-			onceAny, hasOnce = g.lazyServiceOnce.Load(name)
+			// Create state if not exists (handles case where entry was resolved externally)
+			logger.LogDebug("🔍 GetServiceAny('%s'): creating lazyServiceState", name)
+			stateAny, _ = g.lazyServiceStates.LoadOrStore(name, &lazyServiceState{})
+			hasState = true
 		} else {
 			// Not in lazy registry - try auto-registration from serviceFactories
 			// Convention: service name = factory type (e.g., "email-smtp" service uses "email-smtp" factory)
@@ -1272,25 +1327,25 @@ func (g *GlobalRegistry) getServiceAnyWithStack(name string, resolutionStack []s
 				}
 
 				g.lazyServiceFactories.Store(name, entry)
-				g.lazyServiceOnce.Store(name, &sync.Once{})
-
-				onceAny, hasOnce = g.lazyServiceOnce.Load(name)
+				stateAny, _ = g.lazyServiceStates.LoadOrStore(name, &lazyServiceState{})
+				hasState = true
 			}
 		}
 
-		if !hasOnce {
+		if !hasState {
 			logger.LogDebug("🔍 GetServiceAny('%s'): NOT FOUND in any registry, returning false", name)
 			return nil, false
 		}
 	} else {
-		logger.LogDebug("🔍 GetServiceAny('%s'): found in lazyServiceOnce, will instantiate", name)
+		logger.LogDebug("🔍 GetServiceAny('%s'): found in lazyServiceStates, will instantiate", name)
 	}
 
-	once := onceAny.(*sync.Once)
+	state := stateAny.(*lazyServiceState)
 
-	// Create instance once and cache it
-	// IMPORTANT: Load factory inside once.Do to avoid race condition!
-	once.Do(func() {
+	// Create instance once and cache it. Resolution is single-flight; if the
+	// factory panics, nothing is cached and a later call retries after backoff.
+	// IMPORTANT: Load factory inside resolve to avoid race condition!
+	state.resolve(name, g.getServiceRetryPolicy(), func() {
 		entryAny, ok := g.lazyServiceFactories.Load(name)
 		if !ok {
 			// Should not happen, but handle gracefully
@@ -1299,15 +1354,8 @@ func (g *GlobalRegistry) getServiceAnyWithStack(name string, resolutionStack []s
 
 		entry := entryAny.(*LazyServiceEntry)
 
-		// If unresolved inside once.Do, resolve now (handles race condition)
-		if !entry.resolved {
-			factory := g.GetServiceFactory(entry.FactoryType, true)
-			if factory == nil {
-				panic(fmt.Sprintf("service factory '%s' not registered for service '%s'", entry.FactoryType, name))
-			}
-			entry.Factory = factory
-			entry.resolved = true
-		}
+		// If unresolved inside resolve, resolve now (handles race condition)
+		factory := g.lazyEntryFactory(name, entry)
 
 		// Resolve dependencies if specified
 		var resolvedDeps map[string]any
@@ -1338,7 +1386,7 @@ func (g *GlobalRegistry) getServiceAnyWithStack(name string, resolutionStack []s
 		} else {
 			logger.LogDebug("📦 Creating service instance: '%s'", name)
 		}
-		instance := entry.Factory(resolvedDeps, entry.Config)
+		instance := factory(resolvedDeps, entry.Config)
 		logger.LogDebug("📦 Service '%s' created: instance=%p, type=%T", name, instance, instance)
 		g.serviceInstances.Store(name, instance)
 	})
@@ -1348,6 +1396,18 @@ func (g *GlobalRegistry) getServiceAnyWithStack(name string, resolutionStack []s
 	return svc, ok
 }
 
+// lazyEntryFactory returns the factory of a lazy service entry, resolving
+// its factory type (local) on first use. Panics if the type is not registered.
+func (g *GlobalRegistry) lazyEntryFactory(name string, entry *LazyServiceEntry) func(deps, config map[string]any) any {
+	factory := entry.resolveFactory(func(factoryType string) ServiceFactory {
+		return g.GetServiceFactory(factoryType, true) // true = local factory
+	})
+	if factory == nil {
+		panic(fmt.Sprintf("service factory '%s' not registered for service '%s'", entry.FactoryType, name))
+	}
+	return factory
+}
+
 // HasService checks if a service is registered in the lazy service registry
 // or instantiated in the eager registry.
 func (g *GlobalRegistry) HasService(name string) bool {
@@ -1380,7 +1440,7 @@ func (g *GlobalRegistry) MergeRegistryServicesToConfig(config *schema.DeployConf
 		// Skip if already resolved (has inline factory function)
 		// Resolved entries don't need to be merged to config because they already have
 		// the factory function ready to instantiate - no factory type lookup needed
-		if entry.resolved {
+		if entry.IsResolved() {
 			logger.LogDebug("⏭️  Skipping merge for '%s': already resolved with inline factory", serviceName)
 			return true // continue iteration
 		}
@@ -1553,6 +1613,8 @@ func (g *GlobalRegistry) AutoRegisterRemoteService(name string, def *schema.Serv
 		proxyService = proxy.NewService(remoteBaseURL, make(map[string]proxy.RouteMapping))
 	}
 
+	proxyService.WithServiceName(name)
+
 	// Build config with proxy.Service
 	remoteConfig := make(map[string]any)
 	// Copy service-level config if exists
@@ -1827,9 +1889,11 @@ func (g *GlobalRegistry) RepositoryDeploymentTopology(topology *DeploymentTopolo
 	for serverName, serverTopo := range topology.Servers {
 		compositeKey := lowerName + "." + strings.ToLower(serverName)
 		g.serverTopologies.Store(compositeKey, serverTopo)
+		g.mu.Lock()
 		if FirstServer == "" {
 			FirstServer = compositeKey
 		}
+		g.mu.Unlock()
 	}
 }
 
@@ -1883,6 +1947,8 @@ func (g *GlobalRegistry) GetDeployConfig() *schema.DeployConfig {
 // GetFirstServerCompositeKey returns the first available server composite key from server topologies
 // Returns empty string if no server topologies are found
 func (g *GlobalRegistry) GetFirstServerCompositeKey() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	return FirstServer
 }
 
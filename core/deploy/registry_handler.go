@@ -0,0 +1,48 @@
+package deploy
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+)
+
+// RegisterHandler registers a route handler by name. h can have any
+// signature accepted by router.GET & co.
+func (g *GlobalRegistry) RegisterHandler(name string, h any) {
+	if _, exists := g.handlerInstances.Load(name); exists {
+		panic(fmt.Sprintf("handler %s already registered", name))
+	}
+	g.handlerInstances.Store(name, router.AdaptHandler("handler "+name, h))
+}
+
+// GetHandler retrieves a handler by name
+func (g *GlobalRegistry) GetHandler(name string) (request.HandlerFunc, bool) {
+	if v, ok := g.handlerInstances.Load(name); ok {
+		return v.(request.HandlerFunc), true
+	}
+	return nil, false
+}
+
+// ListHandlers returns the names of all registered handlers, sorted
+func (g *GlobalRegistry) ListHandlers() []string {
+	var names []string
+	g.handlerInstances.Range(func(key, _ any) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	slices.Sort(names)
+	return names
+}
+
+// InvokeHandler runs the named handler with ctx, without routing or
+// middleware. Like a handler called by a router, it leaves the response
+// pending in ctx; call ctx.FinalizeResponse(err) to write it.
+func (g *GlobalRegistry) InvokeHandler(name string, ctx *request.Context) error {
+	h, ok := g.GetHandler(name)
+	if !ok {
+		return fmt.Errorf("handler %s not found", name)
+	}
+	return h(ctx)
+}
@@ -0,0 +1,97 @@
+package deploy_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/primadi/lokstra/core/deploy"
+)
+
+type counterService struct{ id int64 }
+
+// Run with -race: services are resolved by request goroutines while a
+// reload stages and rolls back definitions, configs and topologies.
+func TestGlobalRegistry_ConcurrentReadsDuringReload(t *testing.T) {
+	reg := deploy.NewGlobalRegistry()
+	var created atomic.Int64
+	reg.RegisterServiceType("counter-factory", func() any {
+		return &counterService{id: created.Add(1)}
+	})
+
+	const stable = 4
+	for i := range stable {
+		reg.RegisterLazyServiceUnresolved(fmt.Sprintf("svc-%d", i), "counter-factory", nil, nil)
+	}
+	reg.SetConfig("app.name", "orders")
+
+	const reloads = 200
+	var latest atomic.Int64 // last service staged by the reload
+	latest.Store(-1)
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	errCh := make(chan error, 8)
+
+	for r := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				name := fmt.Sprintf("svc-%d", (r+n)%stable)
+				if _, ok := reg.GetServiceAny(name); !ok {
+					errCh <- fmt.Errorf("service %s missing during reload", name)
+					return
+				}
+				// Services staged by the reload may or may not exist
+				reg.GetServiceAny(fmt.Sprintf("reload-%d", latest.Load()))
+				reg.HasService(name)
+				reg.GetConfig("app.name")
+				reg.GetFirstServerCompositeKey()
+				reg.GetServerTopology("prod.api")
+			}
+		}()
+	}
+
+	for i := range reloads {
+		err := reg.ApplyTransaction(func() error {
+			reg.SetConfig("app.name", fmt.Sprintf("orders-%d", i))
+			name := fmt.Sprintf("reload-%d", i)
+			reg.RegisterLazyServiceUnresolved(name, "counter-factory", nil, nil)
+			latest.Store(int64(i))
+			if entry := reg.GetLazyServiceEntry(name); entry != nil && !entry.IsResolved() {
+				factory := reg.GetServiceFactory("counter-factory", true)
+				entry.ResolveFactory(func(deps, cfg map[string]any) any { return factory(deps, cfg) })
+			}
+			reg.RepositoryDeploymentTopology(&deploy.DeploymentTopology{
+				Name:    "prod",
+				Servers: map[string]*deploy.ServerTopology{"api": {Name: "api"}},
+			})
+			if i%2 == 1 {
+				return errors.New("invalid config") // rolled back
+			}
+			return nil
+		})
+		if i%2 == 1 && err == nil {
+			t.Fatalf("reload %d: expected the rollback error", i)
+		}
+	}
+	close(done)
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		t.Error(err)
+	}
+	for i := range stable {
+		if _, ok := reg.GetServiceAny(fmt.Sprintf("svc-%d", i)); !ok {
+			t.Errorf("svc-%d missing after reloads", i)
+		}
+	}
+}
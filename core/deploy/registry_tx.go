@@ -0,0 +1,120 @@
+package deploy
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/primadi/lokstra/core/deploy/schema"
+)
+
+// registryState is a copy of the definition state mutated by config loading
+// (configs and their usage, middleware, service and router definitions,
+// topologies), used for rollback
+type registryState struct {
+	routers         map[string]*schema.RouterDef
+	resolvedConfigs map[string]any
+	deployConfig    *schema.DeployConfig
+	firstServer     string
+
+	configReads     map[string]struct{}
+	configDefaulted map[string]ConfigDefault
+
+	middlewareEntries    map[any]any
+	middlewareInstances  map[any]any
+	lazyServiceFactories map[any]any
+	lazyServiceStates    map[any]any
+	deploymentTopologies map[any]any
+	serverTopologies     map[any]any
+}
+
+func copySyncMap(m *sync.Map) map[any]any {
+	out := make(map[any]any)
+	m.Range(func(k, v any) bool {
+		out[k] = v
+		return true
+	})
+	return out
+}
+
+// restoreSyncMap makes m hold the entries of from. Entries kept by the
+// rollback are never removed, so concurrent readers do not miss them.
+func restoreSyncMap(m *sync.Map, from map[any]any) {
+	m.Range(func(k, _ any) bool {
+		if _, ok := from[k]; !ok {
+			m.Delete(k)
+		}
+		return true
+	})
+	for k, v := range from {
+		m.Store(k, v)
+	}
+}
+
+func (g *GlobalRegistry) captureState() *registryState {
+	g.mu.RLock()
+	s := &registryState{
+		routers:         maps.Clone(g.routers),
+		resolvedConfigs: maps.Clone(g.resolvedConfigs),
+		deployConfig:    g.deployConfig,
+		firstServer:     FirstServer,
+	}
+	g.mu.RUnlock()
+
+	g.configUsage.mu.RLock()
+	s.configReads = maps.Clone(g.configUsage.reads)
+	s.configDefaulted = maps.Clone(g.configUsage.defaulted)
+	g.configUsage.mu.RUnlock()
+
+	s.middlewareEntries = copySyncMap(&g.middlewareEntries)
+	s.middlewareInstances = copySyncMap(&g.middlewareInstances)
+	s.lazyServiceFactories = copySyncMap(&g.lazyServiceFactories)
+	s.lazyServiceStates = copySyncMap(&g.lazyServiceStates)
+	s.deploymentTopologies = copySyncMap(&g.deploymentTopologies)
+	s.serverTopologies = copySyncMap(&g.serverTopologies)
+	return s
+}
+
+func (g *GlobalRegistry) restoreState(s *registryState) {
+	g.mu.Lock()
+	g.routers = s.routers
+	if g.routers == nil {
+		g.routers = make(map[string]*schema.RouterDef)
+	}
+	g.resolvedConfigs = s.resolvedConfigs
+	g.deployConfig = s.deployConfig
+	FirstServer = s.firstServer
+	g.mu.Unlock()
+
+	g.configUsage.mu.Lock()
+	g.configUsage.reads = s.configReads
+	g.configUsage.defaulted = s.configDefaulted
+	g.configUsage.mu.Unlock()
+
+	restoreSyncMap(&g.middlewareEntries, s.middlewareEntries)
+	restoreSyncMap(&g.middlewareInstances, s.middlewareInstances)
+	restoreSyncMap(&g.lazyServiceFactories, s.lazyServiceFactories)
+	restoreSyncMap(&g.lazyServiceStates, s.lazyServiceStates)
+	restoreSyncMap(&g.deploymentTopologies, s.deploymentTopologies)
+	restoreSyncMap(&g.serverTopologies, s.serverTopologies)
+}
+
+// ApplyTransaction runs apply, which stages definitions into the registry
+// (configs, middleware, service and router definitions, topologies). If apply returns
+// an error or panics, the registry is rolled back to its prior state and
+// the error is returned, so a half-applied config never starts a server.
+//
+// Runtime instances (registered routers, instantiated services) and
+// factories are not part of the transaction.
+func (g *GlobalRegistry) ApplyTransaction(apply func() error) (err error) {
+	prior := g.captureState()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+		if err != nil {
+			g.restoreState(prior)
+		}
+	}()
+	return apply()
+}
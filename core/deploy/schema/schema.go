@@ -22,6 +22,10 @@ type DeployConfig struct {
 	// Shorthand: top-level servers (auto-creates 'default' deployment)
 	// Use this when you only need one deployment
 	Servers map[string]*ServerDefMap `yaml:"servers,omitempty" json:"servers,omitempty"`
+
+	// Config keys referenced by ${@cfg:...} placeholders, set by the loader
+	// so these keys count as read (see deploy.GlobalRegistry.ConfigUsage)
+	ConfigRefs []string `yaml:"-" json:"-"`
 }
 
 // RouterDef defines a router auto-generated from a service
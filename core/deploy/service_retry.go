@@ -0,0 +1,174 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+)
+
+// ServiceRetryPolicy controls how a lazy service is re-created after its
+// factory failed (panicked) with a transient error (see TransientError),
+// e.g. because the database was not up yet.
+//
+// After a transient failure the service is not cached; the next GetService
+// retries the factory once the backoff has elapsed. Any other failure
+// (circular or missing dependency, unregistered factory, type assertion,
+// plain panic) is permanent and never retried. Calls made during the
+// backoff window or after a permanent failure return "not found"
+// immediately, and GetServiceError reports why.
+type ServiceRetryPolicy struct {
+	// InitialBackoff is the wait after the first failure
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after each failure
+	Multiplier float64
+	// MaxAttempts stops retrying after N failures (0 = unlimited)
+	MaxAttempts int
+}
+
+// DefaultServiceRetryPolicy returns the policy used when none is set
+func DefaultServiceRetryPolicy() *ServiceRetryPolicy {
+	return &ServiceRetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// transientError marks a factory error as worth retrying
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string   { return e.err.Error() }
+func (e *transientError) Unwrap() error   { return e.err }
+func (e *transientError) Transient() bool { return true }
+
+// TransientError marks err as transient: a factory panicking with it is
+// retried with backoff. Errors implementing Transient() bool count too.
+//
+// Example:
+//
+//	db, err := sql.Open("pgx", dsn)
+//	if err == nil {
+//	    err = db.Ping()
+//	}
+//	if err != nil {
+//	    panic(deploy.TransientError(err))
+//	}
+func TransientError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientError{err: err}
+}
+
+// IsTransient reports whether err (or an error it wraps) is transient
+func IsTransient(err error) bool {
+	var t interface{ Transient() bool }
+	return errors.As(err, &t) && t.Transient()
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (p *ServiceRetryPolicy) backoff(attempts int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempts && d < p.MaxBackoff; i++ {
+		d = time.Duration(float64(d) * p.Multiplier)
+	}
+	return min(d, p.MaxBackoff)
+}
+
+// lazyServiceState tracks instantiation of one lazy service.
+// The mutex makes resolution single-flight: concurrent callers wait for
+// the in-progress attempt instead of calling the factory themselves.
+type lazyServiceState struct {
+	mu        sync.Mutex
+	done      bool
+	failed    bool // permanent failure, never retried
+	attempts  int
+	lastErr   error
+	nextRetry time.Time
+}
+
+// resolve runs create unless the service is already created, failed
+// permanently, is waiting for its backoff, or has exhausted its attempts.
+// A panic from create is recorded and re-raised to the caller that
+// triggered the attempt.
+func (s *lazyServiceState) resolve(name string, policy *ServiceRetryPolicy, create func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done || s.failed {
+		return
+	}
+	if s.attempts > 0 {
+		if policy.MaxAttempts > 0 && s.attempts >= policy.MaxAttempts {
+			return
+		}
+		if time.Now().Before(s.nextRetry) {
+			return
+		}
+		logger.LogInfo("🔁 Retrying service '%s' (attempt %d, last error: %v)", name, s.attempts+1, s.lastErr)
+	}
+
+	s.attempts++
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(error); ok {
+				s.lastErr = err
+			} else {
+				s.lastErr = fmt.Errorf("%v", r)
+			}
+			if !IsTransient(s.lastErr) {
+				s.failed = true
+				logger.LogWarn("service '%s' failed to start, not retried: %v", name, s.lastErr)
+				panic(r)
+			}
+			wait := policy.backoff(s.attempts)
+			s.nextRetry = time.Now().Add(wait)
+			logger.LogWarn("service '%s' failed to start (attempt %d), retry in %s: %v", name, s.attempts, wait, s.lastErr)
+			panic(r)
+		}
+	}()
+
+	create()
+	s.done = true
+	s.lastErr = nil
+}
+
+// err returns the last factory failure, or nil once created
+func (s *lazyServiceState) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// SetServiceRetryPolicy sets how failed lazy service factories are retried.
+// Pass nil to restore DefaultServiceRetryPolicy.
+func (g *GlobalRegistry) SetServiceRetryPolicy(policy *ServiceRetryPolicy) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.serviceRetry = policy
+}
+
+func (g *GlobalRegistry) getServiceRetryPolicy() *ServiceRetryPolicy {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.serviceRetry == nil {
+		return DefaultServiceRetryPolicy()
+	}
+	return g.serviceRetry
+}
+
+// GetServiceError returns the last factory error of a lazy service that
+// could not be created, or nil if it was created (or never attempted).
+func (g *GlobalRegistry) GetServiceError(name string) error {
+	if stateAny, ok := g.lazyServiceStates.Load(name); ok {
+		return stateAny.(*lazyServiceState).err()
+	}
+	return nil
+}
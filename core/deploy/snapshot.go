@@ -0,0 +1,371 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/primadi/lokstra/core/deploy/schema"
+)
+
+// Snapshot is a serializable view of what is registered: services,
+// middlewares, routers and server topologies. Snapshots of the running
+// registry and of a deploy config can be compared with DiffSnapshots.
+type Snapshot struct {
+	Services    map[string]ServiceSnapshot    `json:"services" yaml:"services"`
+	Middlewares map[string]MiddlewareSnapshot `json:"middlewares" yaml:"middlewares"`
+	Routers     []string                      `json:"routers" yaml:"routers"`
+	Servers     map[string]ServerSnapshot     `json:"servers" yaml:"servers"` // key: "deployment.server"
+}
+
+// ServiceSnapshot describes a registered service
+type ServiceSnapshot struct {
+	Type string `json:"type,omitempty" yaml:"type,omitempty"` // Factory type (empty for instances registered from code)
+}
+
+// MiddlewareSnapshot describes a registered middleware
+type MiddlewareSnapshot struct {
+	Type string `json:"type,omitempty" yaml:"type,omitempty"` // Factory type (empty for instances registered from code)
+	// Config with sensitive values redacted (see RedactConfig)
+	Config map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// ServerSnapshot describes a server topology
+type ServerSnapshot struct {
+	BaseURL string        `json:"base-url,omitempty" yaml:"base-url,omitempty"`
+	Apps    []AppSnapshot `json:"apps" yaml:"apps"`
+}
+
+// AppSnapshot describes an app (listener) of a server
+type AppSnapshot struct {
+	Addr    string   `json:"addr" yaml:"addr"`
+	Routers []string `json:"routers,omitempty" yaml:"routers,omitempty"`
+}
+
+// SnapshotChangeKind tells how an entry differs between two snapshots
+type SnapshotChangeKind string
+
+const (
+	SnapshotAdded   SnapshotChangeKind = "added"   // only in the target snapshot
+	SnapshotRemoved SnapshotChangeKind = "removed" // only in the source snapshot
+	SnapshotChanged SnapshotChangeKind = "changed" // in both, with different values
+)
+
+// SnapshotChange is one difference reported by DiffSnapshots
+type SnapshotChange struct {
+	Kind    SnapshotChangeKind `json:"kind"`
+	Section string             `json:"section"` // "service", "middleware", "router" or "server"
+	Name    string             `json:"name"`
+	Detail  string             `json:"detail,omitempty"` // for changes: what differs
+}
+
+func (c SnapshotChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Kind, c.Section, c.Name)
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// Snapshot returns the current registry contents
+func (g *GlobalRegistry) Snapshot() *Snapshot {
+	s := newSnapshot()
+
+	g.serviceInstances.Range(func(key, _ any) bool {
+		s.Services[key.(string)] = ServiceSnapshot{}
+		return true
+	})
+	g.lazyServiceFactories.Range(func(key, value any) bool {
+		s.Services[key.(string)] = ServiceSnapshot{Type: value.(*LazyServiceEntry).FactoryType}
+		return true
+	})
+
+	g.middlewareInstances.Range(func(key, _ any) bool {
+		s.Middlewares[key.(string)] = MiddlewareSnapshot{}
+		return true
+	})
+	g.middlewareEntries.Range(func(key, value any) bool {
+		entry := value.(*MiddlewareEntry)
+		s.Middlewares[key.(string)] = MiddlewareSnapshot{Type: entry.Type, Config: RedactConfig(entry.Config)}
+		return true
+	})
+
+	routers := make(map[string]bool)
+	g.mu.RLock()
+	for name := range g.routers {
+		routers[name] = true
+	}
+	g.mu.RUnlock()
+	g.routerInstances.Range(func(key, _ any) bool {
+		routers[key.(string)] = true
+		return true
+	})
+	g.lazyRouterFactories.Range(func(key, _ any) bool {
+		routers[key.(string)] = true
+		return true
+	})
+	s.Routers = slices.Sorted(maps.Keys(routers))
+
+	g.serverTopologies.Range(func(key, value any) bool {
+		topo := value.(*ServerTopology)
+		server := ServerSnapshot{BaseURL: topo.BaseURL}
+		for _, app := range topo.Apps {
+			server.Apps = append(server.Apps, AppSnapshot{Addr: app.Addr, Routers: app.Routers})
+		}
+		s.Servers[key.(string)] = server
+		return true
+	})
+
+	return s
+}
+
+// SnapshotFromConfig returns the snapshot a deploy config is expected to
+// produce. Inline definitions are named like the loader normalizes them
+// ({deployment}.{name} and {deployment}.{server}.{name}), and each published
+// service adds its auto-generated "{service}-router".
+func SnapshotFromConfig(config *schema.DeployConfig) *Snapshot {
+	s := newSnapshot()
+	routers := make(map[string]bool)
+
+	addDefs := func(prefix string, mws map[string]*schema.MiddlewareDef,
+		svcs map[string]*schema.ServiceDef, rtrs map[string]*schema.RouterDef) {
+		for name, mw := range mws {
+			s.Middlewares[strings.ToLower(prefix+name)] = MiddlewareSnapshot{Type: mw.Type, Config: RedactConfig(mw.Config)}
+		}
+		for name, svc := range svcs {
+			s.Services[strings.ToLower(prefix+name)] = ServiceSnapshot{Type: svc.Type}
+		}
+		for name := range rtrs {
+			routers[strings.ToLower(prefix+name)] = true
+		}
+	}
+	// Top-level definitions keep their names as written
+	for name, mw := range config.MiddlewareDefinitions {
+		s.Middlewares[name] = MiddlewareSnapshot{Type: mw.Type, Config: RedactConfig(mw.Config)}
+	}
+	for name, svc := range config.ServiceDefinitions {
+		s.Services[name] = ServiceSnapshot{Type: svc.Type}
+	}
+	for name := range config.RouterDefinitions {
+		routers[name] = true
+	}
+
+	deployments := config.Deployments
+	if len(config.Servers) > 0 {
+		// Shorthand top-level servers belong to the 'default' deployment
+		deployments = maps.Clone(deployments)
+		if deployments == nil {
+			deployments = make(map[string]*schema.DeploymentDefMap)
+		}
+		def := &schema.DeploymentDefMap{Servers: maps.Clone(config.Servers)}
+		if existing, ok := deployments["default"]; ok {
+			def = &schema.DeploymentDefMap{
+				InlineMiddlewares: existing.InlineMiddlewares,
+				InlineServices:    existing.InlineServices,
+				InlineRouters:     existing.InlineRouters,
+				Servers:           maps.Clone(existing.Servers),
+			}
+			maps.Copy(def.Servers, config.Servers)
+		}
+		deployments["default"] = def
+	}
+
+	for depName, dep := range deployments {
+		addDefs(depName+".", dep.InlineMiddlewares, dep.InlineServices, dep.InlineRouters)
+
+		for serverName, server := range dep.Servers {
+			addDefs(depName+"."+serverName+".", server.InlineMiddlewares,
+				server.InlineServices, server.InlineRouters)
+
+			apps := server.Apps
+			if server.HelperAddr != "" || len(server.HelperRouters) > 0 ||
+				len(server.HelperPublishedServices) > 0 {
+				apps = append([]*schema.AppDefMap{{
+					Addr:              server.HelperAddr,
+					Routers:           server.HelperRouters,
+					PublishedServices: server.HelperPublishedServices,
+				}}, apps...)
+			}
+
+			snap := ServerSnapshot{BaseURL: server.BaseURL}
+			for _, app := range apps {
+				appSnap := AppSnapshot{Addr: app.Addr, Routers: slices.Clone(app.Routers)}
+				for _, svc := range app.PublishedServices {
+					appSnap.Routers = append(appSnap.Routers, svc+"-router")
+					routers[svc+"-router"] = true
+				}
+				snap.Apps = append(snap.Apps, appSnap)
+			}
+			s.Servers[strings.ToLower(depName+"."+serverName)] = snap
+		}
+	}
+
+	s.Routers = slices.Sorted(maps.Keys(routers))
+	return s
+}
+
+func newSnapshot() *Snapshot {
+	return &Snapshot{
+		Services:    make(map[string]ServiceSnapshot),
+		Middlewares: make(map[string]MiddlewareSnapshot),
+		Routers:     []string{},
+		Servers:     make(map[string]ServerSnapshot),
+	}
+}
+
+// DiffSnapshots reports what differs from `from` to `to`, sorted by section
+// and name. Added entries are only in `to`, removed entries only in `from`.
+// Factory types are compared only when known on both sides, since instances
+// registered from code have none.
+func DiffSnapshots(from, to *Snapshot) []SnapshotChange {
+	var changes []SnapshotChange
+
+	changes = append(changes, diffMap("service", from.Services, to.Services,
+		func(a, b ServiceSnapshot) string {
+			return diffType(a.Type, b.Type)
+		})...)
+
+	changes = append(changes, diffMap("middleware", from.Middlewares, to.Middlewares,
+		func(a, b MiddlewareSnapshot) string {
+			if d := diffType(a.Type, b.Type); d != "" {
+				return d
+			}
+			if a.Type != "" && b.Type != "" && !configEqual(a.Config, b.Config) {
+				return fmt.Sprintf("config %v -> %v", a.Config, b.Config)
+			}
+			return ""
+		})...)
+
+	fromRouters := make(map[string]struct{}, len(from.Routers))
+	for _, name := range from.Routers {
+		fromRouters[name] = struct{}{}
+	}
+	toRouters := make(map[string]struct{}, len(to.Routers))
+	for _, name := range to.Routers {
+		toRouters[name] = struct{}{}
+	}
+	changes = append(changes, diffMap("router", fromRouters, toRouters,
+		func(_, _ struct{}) string { return "" })...)
+
+	changes = append(changes, diffMap("server", from.Servers, to.Servers,
+		func(a, b ServerSnapshot) string {
+			if a.BaseURL != b.BaseURL {
+				return fmt.Sprintf("base-url %q -> %q", a.BaseURL, b.BaseURL)
+			}
+			if len(a.Apps) != len(b.Apps) {
+				return fmt.Sprintf("%d apps -> %d apps", len(a.Apps), len(b.Apps))
+			}
+			for i := range a.Apps {
+				if a.Apps[i].Addr != b.Apps[i].Addr {
+					return fmt.Sprintf("app %d addr %q -> %q", i, a.Apps[i].Addr, b.Apps[i].Addr)
+				}
+				if !slices.Equal(a.Apps[i].Routers, b.Apps[i].Routers) {
+					return fmt.Sprintf("app %d routers %v -> %v", i, a.Apps[i].Routers, b.Apps[i].Routers)
+				}
+			}
+			return ""
+		})...)
+
+	return changes
+}
+
+func diffMap[V any](section string, from, to map[string]V, compare func(a, b V) string) []SnapshotChange {
+	var changes []SnapshotChange
+	for _, name := range slices.Sorted(maps.Keys(from)) {
+		b, ok := to[name]
+		if !ok {
+			changes = append(changes, SnapshotChange{Kind: SnapshotRemoved, Section: section, Name: name})
+		} else if detail := compare(from[name], b); detail != "" {
+			changes = append(changes, SnapshotChange{Kind: SnapshotChanged, Section: section, Name: name, Detail: detail})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(to)) {
+		if _, ok := from[name]; !ok {
+			changes = append(changes, SnapshotChange{Kind: SnapshotAdded, Section: section, Name: name})
+		}
+	}
+	return changes
+}
+
+func diffType(a, b string) string {
+	if a != "" && b != "" && a != b {
+		return fmt.Sprintf("type %q -> %q", a, b)
+	}
+	return ""
+}
+
+// configEqual treats nil and empty configs as equal
+func configEqual(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// sensitiveKeyParts mark config keys whose values are redacted in snapshots
+var sensitiveKeyParts = []string{
+	"password", "passwd", "secret", "token", "key", "dsn", "credential", "auth", "private", "salt",
+}
+
+// RedactConfig returns a copy of a middleware config that is safe to show:
+// values of sensitive keys (passwords, secrets, tokens, keys, DSNs, ...) at
+// any depth become "[REDACTED:<hash>]", and passwords in URL values are
+// masked. The hash is a short digest of the value, so a changed secret
+// still shows up in DiffSnapshots without being revealed.
+func RedactConfig(config map[string]any) map[string]any {
+	if config == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(config))
+	for k, v := range config {
+		if isSensitiveKey(k) {
+			redacted[k] = redactedValue(v)
+		} else {
+			redacted[k] = redactValue(v)
+		}
+	}
+	return redacted
+}
+
+func redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return RedactConfig(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = redactValue(item)
+		}
+		return out
+	case string:
+		// e.g. url: postgres://app:s3cret@db:5432/app
+		if strings.Contains(val, "://") {
+			if u, err := url.Parse(val); err == nil && u.User != nil {
+				if _, ok := u.User.Password(); ok {
+					return u.Redacted()
+				}
+			}
+		}
+	}
+	return v
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactedValue(v any) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%T:%v", v, v))
+	return "[REDACTED:" + hex.EncodeToString(sum[:4]) + "]"
+}
@@ -0,0 +1,170 @@
+package deploy_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/deploy/schema"
+)
+
+func snapshotTestConfig() *schema.DeployConfig {
+	return &schema.DeployConfig{
+		MiddlewareDefinitions: map[string]*schema.MiddlewareDef{
+			"auth": {Type: "jwt", Config: map[string]any{"issuer": "lokstra"}},
+		},
+		ServiceDefinitions: map[string]*schema.ServiceDef{
+			"user-service":  {Type: "user-factory"},
+			"order-service": {Type: "order-factory"},
+		},
+		RouterDefinitions: map[string]*schema.RouterDef{
+			"admin-router": {},
+		},
+		Servers: map[string]*schema.ServerDefMap{
+			"api": {
+				BaseURL:                 "http://localhost",
+				HelperAddr:              ":8080",
+				HelperRouters:           []string{"admin-router"},
+				HelperPublishedServices: []string{"user-service"},
+			},
+		},
+	}
+}
+
+func newSnapshotTestRegistry() *deploy.GlobalRegistry {
+	g := deploy.NewGlobalRegistry()
+	g.RegisterMiddlewareName("auth", "jwt", map[string]any{"issuer": "lokstra"})
+	g.RegisterLazyServiceUnresolved("user-service", "user-factory", nil, nil)
+	g.RegisterLazyServiceUnresolved("order-service", "order-factory", nil, nil)
+	g.DefineRouter("admin-router", &schema.RouterDef{})
+	g.DefineRouter("user-service-router", &schema.RouterDef{})
+	g.RepositoryDeploymentTopology(&deploy.DeploymentTopology{
+		Name: "default",
+		Servers: map[string]*deploy.ServerTopology{
+			"api": {
+				Name:    "api",
+				BaseURL: "http://localhost",
+				Apps: []*deploy.AppTopology{
+					{Addr: ":8080", Routers: []string{"admin-router", "user-service-router"}},
+				},
+			},
+		},
+	})
+	return g
+}
+
+func TestSnapshot_MatchesConfig(t *testing.T) {
+	snap := newSnapshotTestRegistry().Snapshot()
+
+	if changes := deploy.DiffSnapshots(snap, deploy.SnapshotFromConfig(snapshotTestConfig())); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	// Serializable for CI artifacts
+	b, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded deploy.Snapshot
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if changes := deploy.DiffSnapshots(snap, &decoded); len(changes) != 0 {
+		t.Errorf("Expected JSON round trip to be lossless, got %v", changes)
+	}
+}
+
+func TestSnapshot_DiffModifiedConfig(t *testing.T) {
+	snap := newSnapshotTestRegistry().Snapshot()
+
+	cfg := snapshotTestConfig()
+	delete(cfg.ServiceDefinitions, "order-service")
+	cfg.ServiceDefinitions["user-service"].Type = "user-factory-v2"
+	cfg.ServiceDefinitions["billing-service"] = &schema.ServiceDef{Type: "billing-factory"}
+	cfg.MiddlewareDefinitions["auth"].Config = map[string]any{"issuer": "other"}
+	cfg.Servers["api"].HelperAddr = ":9090"
+
+	got := deploy.DiffSnapshots(snap, deploy.SnapshotFromConfig(cfg))
+	expected := []deploy.SnapshotChange{
+		{Kind: deploy.SnapshotRemoved, Section: "service", Name: "order-service"},
+		{Kind: deploy.SnapshotChanged, Section: "service", Name: "user-service",
+			Detail: `type "user-factory" -> "user-factory-v2"`},
+		{Kind: deploy.SnapshotAdded, Section: "service", Name: "billing-service"},
+		{Kind: deploy.SnapshotChanged, Section: "middleware", Name: "auth",
+			Detail: "config map[issuer:lokstra] -> map[issuer:other]"},
+		{Kind: deploy.SnapshotChanged, Section: "server", Name: "default.api",
+			Detail: `app 0 addr ":8080" -> ":9090"`},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Change %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestSnapshot_InlineDefinitionsNormalized(t *testing.T) {
+	cfg := &schema.DeployConfig{
+		Deployments: map[string]*schema.DeploymentDefMap{
+			"Prod": {
+				InlineServices: map[string]*schema.ServiceDef{"cache": {Type: "redis"}},
+				Servers: map[string]*schema.ServerDefMap{
+					"api": {
+						InlineRouters: map[string]*schema.RouterDef{"health-router": {}},
+						Apps:          []*schema.AppDefMap{{Addr: ":8080"}},
+					},
+				},
+			},
+		},
+	}
+
+	snap := deploy.SnapshotFromConfig(cfg)
+	if snap.Services["prod.cache"].Type != "redis" {
+		t.Errorf("Expected normalized inline service prod.cache, got %v", snap.Services)
+	}
+	if len(snap.Routers) != 1 || snap.Routers[0] != "prod.api.health-router" {
+		t.Errorf("Expected normalized inline router, got %v", snap.Routers)
+	}
+	if _, ok := snap.Servers["prod.api"]; !ok {
+		t.Errorf("Expected server prod.api, got %v", snap.Servers)
+	}
+}
+
+func TestSnapshot_RedactsSensitiveConfig(t *testing.T) {
+	cfg := snapshotTestConfig()
+	cfg.MiddlewareDefinitions["auth"].Config = map[string]any{
+		"issuer":     "lokstra",
+		"secret_key": "hmac-s3cret",
+		"store":      map[string]any{"url": "postgres://app:db-s3cret@db:5432/app", "Password": "db-s3cret"},
+	}
+
+	snap := deploy.SnapshotFromConfig(cfg)
+	b, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "s3cret") {
+		t.Errorf("Expected secrets to be redacted, got %s", b)
+	}
+	config := snap.Middlewares["auth"].Config
+	if config["issuer"] != "lokstra" {
+		t.Errorf("Expected non-sensitive values kept, got %v", config["issuer"])
+	}
+	if url := config["store"].(map[string]any)["url"]; url != "postgres://app:xxxxx@db:5432/app" {
+		t.Errorf("Expected URL password masked, got %v", url)
+	}
+
+	// A changed secret is reported without revealing either value
+	cfg.MiddlewareDefinitions["auth"].Config["secret_key"] = "rotated-s3cret"
+	changes := deploy.DiffSnapshots(snap, deploy.SnapshotFromConfig(cfg))
+	if len(changes) != 1 || changes[0].Name != "auth" {
+		t.Fatalf("Expected the auth config change, got %v", changes)
+	}
+	if strings.Contains(changes[0].Detail, "s3cret") || !strings.Contains(changes[0].Detail, "[REDACTED:") {
+		t.Errorf("Expected a redacted diff, got %q", changes[0].Detail)
+	}
+}
@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/primadi/lokstra/common/validator"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+// Version of the OpenAPI specification generated by Generate
+const Version = "3.0.3"
+
+// Info describes the API in the generated spec
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Spec is an OpenAPI document, marshal it to JSON to serve it to Swagger UI
+type Spec struct {
+	OpenAPI string                           `json:"openapi"`
+	Info    Info                             `json:"info"`
+	Paths   map[string]map[string]*Operation `json:"paths"`
+}
+
+// Operation is one method of a path
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path parameter of an operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the body of an operation, described by its example
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is a response of an operation, described by its example
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema and example of a body
+type MediaType struct {
+	Schema  *Schema `json:"schema"`
+	Example any     `json:"example,omitempty"`
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Generate builds the OpenAPI spec of the routes of r (see Router.Routes).
+//
+// Request and response bodies are described by the route examples (see
+// route.WithExample): their Go types give the schemas, their values the
+// examples. Struct fields may declare their own example with an `example`
+// tag, parsed into the field type.
+//
+// Examples are validated at generation time, so they can't drift from the
+// schema: a route example must pass its `validate` tags, and a tag example
+// must parse into its field and pass the field's `validate` tags. Every
+// invalid example is reported in the returned error.
+//
+// ANY routes have no method to document and are left out.
+//
+// Example:
+//
+//	type CreateUserRequest struct {
+//	    Name  string `json:"name" validate:"required" example:"Ana"`
+//	    Email string `json:"email" validate:"required,email" example:"ana@example.com"`
+//	}
+//
+//	r.POST("/users", createUser, route.WithExample(
+//	    CreateUserRequest{Name: "Ana", Email: "ana@example.com"},
+//	    User{ID: 42, Name: "Ana"},
+//	))
+//	spec, err := openapi.Generate(r, openapi.Info{Title: "Users", Version: "1.0"})
+func Generate(r router.Router, info Info) (*Spec, error) {
+	spec := &Spec{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+	}
+	g := newGenerator()
+
+	var errs []error
+	for _, rt := range r.Routes() {
+		if rt.Method == "ANY" {
+			continue
+		}
+		path := specPath(rt.FullPath)
+		op, err := g.operation(rt, path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", rt.Method, rt.FullPath, err))
+		}
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = make(map[string]*Operation)
+		}
+		spec.Paths[path][strings.ToLower(rt.Method)] = op
+	}
+	errs = append(errs, g.errs...)
+	return spec, errors.Join(errs...)
+}
+
+// operation describes rt, reporting invalid route examples
+func (g *generator) operation(rt *route.Route, path string) (*Operation, error) {
+	op := &Operation{
+		OperationID: rt.FullName,
+		Summary:     rt.Description,
+		Tags:        slices.Clone(rt.Tags),
+		Responses:   make(map[string]*Response),
+	}
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	var errs []error
+	if rt.RequestExample != nil {
+		if err := validateExample(rt.RequestExample); err != nil {
+			errs = append(errs, fmt.Errorf("request example: %w", err))
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  g.content(rt.RequestExample),
+		}
+	}
+
+	resp := &Response{Description: http.StatusText(http.StatusOK)}
+	if rt.ResponseExample != nil {
+		if err := validateExample(rt.ResponseExample); err != nil {
+			errs = append(errs, fmt.Errorf("response example: %w", err))
+		}
+		resp.Content = g.content(rt.ResponseExample)
+	}
+	op.Responses["200"] = resp
+	return op, errors.Join(errs...)
+}
+
+// content describes a JSON body by its example
+func (g *generator) content(example any) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: g.schemaOf(example), Example: example},
+	}
+}
+
+// validateExample checks example against the validate tags of its type
+func validateExample(example any) error {
+	fieldErrs, err := validator.ValidateStruct(example)
+	if err != nil {
+		return err
+	}
+	errs := make([]error, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		errs[i] = errors.New(fe.Message)
+	}
+	return errors.Join(errs...)
+}
+
+// specPath turns a route path into an OpenAPI path template:
+// "/files/{path...}" and "/files/*" become "/files/{path}"
+func specPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	if before, ok := strings.CutSuffix(path, "/*"); ok {
+		path = before + "/{path}"
+	}
+	return strings.ReplaceAll(path, "...}", "}")
+}
@@ -0,0 +1,172 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/common/customtype"
+	"github.com/primadi/lokstra/core/openapi"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+type createUserRequest struct {
+	Name  string `json:"name" validate:"required,min=2" example:"Ana"`
+	Email string `json:"email" validate:"required,email" example:"ana@example.com"`
+	Age   int    `json:"age,omitempty" validate:"gte=18" example:"30"`
+}
+
+type user struct {
+	ID       int64              `json:"id" example:"42"`
+	Name     string             `json:"name"`
+	Birthday *customtype.Date   `json:"birthday,omitempty" example:"1994-05-17"`
+	Roles    []string           `json:"roles,omitempty"`
+	Meta     map[string]float64 `json:"-"`
+}
+
+func noop(c *request.Context) error { return nil }
+
+// generate returns the spec of r as generic JSON, as Swagger UI reads it
+func generate(t *testing.T, r router.Router) (map[string]any, error) {
+	t.Helper()
+	spec, err := openapi.Generate(r, openapi.Info{Title: "Users", Version: "1.0"})
+	data, merr := json.Marshal(spec)
+	if merr != nil {
+		t.Fatalf("Marshal failed: %v", merr)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc, err
+}
+
+// at walks doc along keys
+func at(t *testing.T, doc any, keys ...string) any {
+	t.Helper()
+	for _, k := range keys {
+		m, ok := doc.(map[string]any)
+		if !ok {
+			t.Fatalf("No %q in %v", k, doc)
+		}
+		doc = m[k]
+	}
+	return doc
+}
+
+func TestGenerate_Examples(t *testing.T) {
+	r := router.New("users")
+	r.POST("/users", noop, route.WithDescriptionOption("Create a user"),
+		route.WithExample(
+			createUserRequest{Name: "Budi", Email: "budi@example.com", Age: 25},
+			user{ID: 7, Name: "Budi"},
+		))
+	r.GET("/users/{id}", noop, route.WithExample(nil, user{ID: 42, Name: "Ana"}))
+	r.GETPrefix("/files", noop)
+	r.ANY("/echo", noop)
+
+	doc, err := generate(t, r)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if v := at(t, doc, "openapi"); v != openapi.Version {
+		t.Errorf("openapi = %v", v)
+	}
+
+	create := at(t, doc, "paths", "/users", "post")
+	if v := at(t, create, "summary"); v != "Create a user" {
+		t.Errorf("summary = %v", v)
+	}
+	body := at(t, create, "requestBody", "content", "application/json")
+	if v := at(t, body, "example", "name"); v != "Budi" {
+		t.Errorf("Route request example not in spec: %v", at(t, body, "example"))
+	}
+	schema := at(t, body, "schema")
+	if v := at(t, schema, "properties", "name", "example"); v != "Ana" {
+		t.Errorf("Tag example of name = %v", v)
+	}
+	if v := at(t, schema, "properties", "age", "example"); v != 30.0 {
+		t.Errorf("Tag example of age = %v", v)
+	}
+	if v := at(t, schema, "properties", "age", "minimum"); v != 18.0 {
+		t.Errorf("minimum of age = %v", v)
+	}
+	if v := at(t, schema, "properties", "email", "format"); v != "email" {
+		t.Errorf("format of email = %v", v)
+	}
+	if v, _ := at(t, schema, "required").([]any); len(v) != 2 || v[0] != "name" || v[1] != "email" {
+		t.Errorf("required = %v", v)
+	}
+
+	get := at(t, doc, "paths", "/users/{id}", "get")
+	if _, ok := at(t, get, "requestBody").(map[string]any); ok {
+		t.Error("GET without request example should have no request body")
+	}
+	params, _ := at(t, get, "parameters").([]any)
+	if len(params) != 1 || at(t, params[0], "name") != "id" || at(t, params[0], "in") != "path" {
+		t.Errorf("parameters = %v", params)
+	}
+	resp := at(t, get, "responses", "200", "content", "application/json")
+	if v := at(t, resp, "example", "id"); v != 42.0 {
+		t.Errorf("Route response example not in spec: %v", at(t, resp, "example"))
+	}
+	props := at(t, resp, "schema", "properties").(map[string]any)
+	if v := at(t, props, "birthday", "example"); v != "1994-05-17" {
+		t.Errorf("Tag example of birthday = %v", v)
+	}
+	if _, ok := props["Meta"]; ok {
+		t.Error(`Fields tagged json:"-" should be left out`)
+	}
+
+	if _, ok := at(t, doc, "paths", "/files/{path}", "get").(map[string]any); !ok {
+		t.Error("Prefix route should be documented with a path parameter")
+	}
+	if _, ok := at(t, doc, "paths").(map[string]any)["/echo"]; ok {
+		t.Error("ANY routes should be left out")
+	}
+}
+
+type badTagExample struct {
+	Age int `json:"age" example:"thirty"`
+}
+
+type tagExampleBreaksRule struct {
+	Status string `json:"status" validate:"oneof=active blocked" example:"deleted"`
+}
+
+func TestGenerate_InvalidExamples(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  route.RouteHandlerOption
+		want string
+	}{
+		{
+			name: "route example fails validate tags",
+			opt:  route.WithExample(createUserRequest{Name: "Ana", Email: "not-an-email", Age: 30}, nil),
+			want: "POST /users: request example: email",
+		},
+		{
+			name: "tag example does not parse",
+			opt:  route.WithExample(badTagExample{Age: 30}, nil),
+			want: `badTagExample.Age: example "thirty": not a valid int`,
+		},
+		{
+			name: "tag example fails validate tags",
+			opt:  route.WithExample(nil, tagExampleBreaksRule{Status: "active"}),
+			want: `tagExampleBreaksRule.status: example "deleted"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := router.New("users")
+			r.POST("/users", noop, tt.opt)
+
+			_, err := generate(t, r)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/primadi/lokstra/common/enum"
+	"github.com/primadi/lokstra/common/validator"
+)
+
+// Schema is the JSON schema of a Go type
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Example              any                `json:"example,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonRawType       = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	listerType        = reflect.TypeFor[enum.Lister]()
+)
+
+// generator builds the schemas of one spec, each struct type once
+type generator struct {
+	schemas map[reflect.Type]*Schema
+	errs    []error // invalid tag examples, reported once per type
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[reflect.Type]*Schema)}
+}
+
+// schemaOf returns the schema of the type of v
+func (g *generator) schemaOf(v any) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		s := *g.schema(t.Elem())
+		s.Nullable = true
+		return &s
+	}
+	if s, ok := g.schemas[t]; ok {
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == jsonRawType:
+		return &Schema{}
+	case t.Implements(listerType) && t.Kind() == reflect.String:
+		values := reflect.Zero(t).Interface().(enum.Lister).Values()
+		s := &Schema{Type: "string"}
+		for _, v := range values {
+			s.Enum = append(s.Enum, v)
+		}
+		return s
+	case t.Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	case t.Implements(jsonMarshalerType):
+		return &Schema{} // any JSON value
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+	return &Schema{} // interfaces and anything else: any JSON value
+}
+
+// structSchema describes the JSON fields of t, with the constraints of
+// their validate tags and the examples of their example tags
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	// Registered before the fields, so recursive types refer to it
+	g.schemas[t] = s
+
+	sample := reflect.New(t).Elem()
+	examples := make(map[string]string)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+
+		fs := g.schema(f.Type)
+		rules := f.Tag.Get("validate")
+		if rules != "" || f.Tag.Get("example") != "" {
+			copied := *fs // shared schemas of named types stay untouched
+			fs = &copied
+		}
+		required := applyRules(fs, f.Type, rules)
+		if required {
+			s.Required = append(s.Required, name)
+		}
+
+		if raw, ok := f.Tag.Lookup("example"); ok {
+			v, err := parseExample(f.Type, raw)
+			if err != nil {
+				g.errs = append(g.errs, fmt.Errorf("%s.%s: example %q: %w", t.Name(), f.Name, raw, err))
+				continue
+			}
+			fs.Example = v.Interface()
+			sample.Field(i).Set(v)
+			examples[name] = raw
+		}
+		s.Properties[name] = fs
+	}
+
+	// Tag examples must pass the validate tags of their own field
+	if len(examples) > 0 {
+		fieldErrs, _ := validator.ValidateStruct(sample.Interface())
+		for _, fe := range fieldErrs {
+			if raw, ok := examples[fe.Field]; ok {
+				g.errs = append(g.errs, fmt.Errorf("%s.%s: example %q: %s", t.Name(), fe.Field, raw, fe.Message))
+			}
+		}
+	}
+	return s
+}
+
+// jsonName returns the JSON name of f, and false for fields left out of JSON
+func jsonName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+// applyRules copies the validate rules of a field into its schema and
+// reports whether the field is required
+func applyRules(s *Schema, t reflect.Type, rules string) (required bool) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for rule := range strings.SplitSeq(rules, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "oneof":
+			s.Enum = nil
+			for v := range strings.FieldsSeq(value) {
+				s.Enum = append(s.Enum, v)
+			}
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			applyBound(s, t, name == "min" || name == "gte", n)
+		}
+	}
+	return required
+}
+
+// applyBound sets a lower or upper bound, on the length of strings, the
+// items of slices, or the value of numbers
+func applyBound(s *Schema, t reflect.Type, lower bool, n float64) {
+	switch t.Kind() {
+	case reflect.String:
+		if lower {
+			s.MinLength = ptr(int(n))
+		} else {
+			s.MaxLength = ptr(int(n))
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if lower {
+			s.MinItems = ptr(int(n))
+		} else {
+			s.MaxItems = ptr(int(n))
+		}
+	default:
+		if lower {
+			s.Minimum = ptr(n)
+		} else {
+			s.Maximum = ptr(n)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// parseExample parses the example tag raw into a value of type t: strings
+// and text types as is, numbers and booleans as literals, anything else
+// (and JSON types) as JSON
+func parseExample(t reflect.Type, raw string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	target := v
+	if t.Kind() == reflect.Pointer {
+		target = reflect.New(t.Elem())
+		v.Set(target)
+		target = target.Elem()
+	}
+
+	var err error
+	_, isJSON := target.Addr().Interface().(json.Unmarshaler)
+	text, isText := target.Addr().Interface().(encoding.TextUnmarshaler)
+	switch {
+	case isJSON:
+		err = unmarshalExample(target, raw)
+	case isText:
+		err = text.UnmarshalText([]byte(raw))
+	case target.Kind() == reflect.String:
+		target.SetString(raw)
+	case target.Kind() == reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(raw); err == nil {
+			target.SetBool(b)
+		}
+	case target.CanInt():
+		var n int64
+		if n, err = strconv.ParseInt(raw, 10, target.Type().Bits()); err == nil {
+			target.SetInt(n)
+		}
+	case target.CanUint():
+		var n uint64
+		if n, err = strconv.ParseUint(raw, 10, target.Type().Bits()); err == nil {
+			target.SetUint(n)
+		}
+	case target.CanFloat():
+		var n float64
+		if n, err = strconv.ParseFloat(raw, target.Type().Bits()); err == nil {
+			target.SetFloat(n)
+		}
+	default:
+		err = unmarshalExample(target, raw)
+	}
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = fmt.Errorf("not a valid %s", target.Type())
+		}
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// unmarshalExample decodes raw as JSON, or as a JSON string when raw is
+// not JSON, e.g. example:"2024-01-31" for a customtype.Date
+func unmarshalExample(target reflect.Value, raw string) error {
+	if !json.Valid([]byte(raw)) {
+		raw = strconv.Quote(raw)
+	}
+	return json.Unmarshal([]byte(raw), target.Addr().Interface())
+}
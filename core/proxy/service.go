@@ -2,11 +2,14 @@ package proxy
 
 import (
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/primadi/lokstra/common/api_client"
+	"github.com/primadi/lokstra/common/hashring"
 	"github.com/primadi/lokstra/common/logger"
 	"github.com/primadi/lokstra/core/request"
 )
@@ -23,6 +26,12 @@ type Service struct {
 	baseURL       string
 	routeMap      map[string]RouteMapping // methodName -> route mapping
 	hiddenMethods map[string]bool         // methods to hide
+
+	// Sharding (NewShardedService): calls go to the shard owning their key
+	shardKey ShardKeyFunc
+	ring     *hashring.Ring
+	shardsMu sync.RWMutex
+	shards   map[string]*api_client.ClientRouter // baseURL -> client
 }
 
 // NewService creates a new proxy service with explicit route mappings
@@ -35,11 +44,7 @@ type Service struct {
 //	    "CreateUser": {HTTPMethod: "POST", Path: "/users"},
 //	}
 func NewService(baseURL string, routeMap map[string]RouteMapping) *Service {
-	client := &api_client.ClientRouter{
-		FullURL: baseURL,
-		IsLocal: false,
-		Timeout: 30 * time.Second,
-	}
+	client := newClient(baseURL)
 
 	logger.LogDebug("🌐 Created remote service proxy: %s with %d routes", baseURL, len(routeMap))
 
@@ -59,6 +64,49 @@ func (s *Service) WithHiddenMethods(methods ...string) *Service {
 	return s
 }
 
+// WithServiceName sets the service name used to label outbound metrics
+func (s *Service) WithServiceName(name string) *Service {
+	s.client.ServiceName = name
+	s.shardsMu.Lock()
+	for _, c := range s.shards {
+		c.ServiceName = name
+	}
+	s.shardsMu.Unlock()
+	return s
+}
+
+// WithTimeout sets the timeout of each call to the service (default 30s)
+func (s *Service) WithTimeout(timeout time.Duration) *Service {
+	s.client.Timeout = timeout
+	s.shardsMu.Lock()
+	for _, c := range s.shards {
+		c.Timeout = timeout
+	}
+	s.shardsMu.Unlock()
+	return s
+}
+
+// WithTransport sets the transport of calls to the service, e.g. one from
+// api_client.NewTransport tuned for its traffic. Pass the same transport to
+// several services to share the connection pool.
+func (s *Service) WithTransport(transport http.RoundTripper) *Service {
+	s.client.Transport = transport
+	s.shardsMu.Lock()
+	for _, c := range s.shards {
+		c.Transport = transport
+	}
+	s.shardsMu.Unlock()
+	return s
+}
+
+func newClient(baseURL string) *api_client.ClientRouter {
+	return &api_client.ClientRouter{
+		FullURL: baseURL,
+		IsLocal: false,
+		Timeout: 30 * time.Second,
+	}
+}
+
 // Call invokes a remote service method with automatic HTTP request building
 // Supports handler signatures that return error only:
 //   - func() error
@@ -89,13 +137,17 @@ func Call(s *Service, methodName string, params ...any) error {
 	// Replace path parameters from context
 	path := s.replacePathParameters(pathTemplate, ctx, structParam)
 
-	logger.LogDebug("🌐 proxy.Call: %s → %s %s", methodName, httpMethod, s.baseURL+path)
+	client, err := s.clientFor(ctx, structParam)
+	if err != nil {
+		return fmt.Errorf("proxy.Call %s: %w", methodName, err)
+	}
+	logger.LogDebug("🌐 proxy.Call: %s → %s %s", methodName, httpMethod, client.FullURL+path)
 
 	// Build request options
 	opts := s.buildRequestOptions(httpMethod, structParam, ctx)
 
 	// Make HTTP call - use empty response type for error-only handlers
-	_, err = api_client.FetchAndCast[any](s.client, path, opts...)
+	_, err = api_client.FetchAndCast[any](client, path, opts...)
 	if err != nil {
 		logger.LogError("❌ proxy.Call error: %v", err)
 		return err
@@ -138,13 +190,17 @@ func CallWithData[T any](s *Service, methodName string, params ...any) (T, error
 	// Replace path parameters from context
 	path := s.replacePathParameters(pathTemplate, ctx, structParam)
 
-	logger.LogDebug("🌐 proxy.CallWithData: %s → %s %s", methodName, httpMethod, s.baseURL+path)
+	client, err := s.clientFor(ctx, structParam)
+	if err != nil {
+		return zero, fmt.Errorf("proxy.CallWithData %s: %w", methodName, err)
+	}
+	logger.LogDebug("🌐 proxy.CallWithData: %s → %s %s", methodName, httpMethod, client.FullURL+path)
 
 	// Build request options
 	opts := s.buildRequestOptions(httpMethod, structParam, ctx)
 
 	// Make HTTP call and get typed response
-	data, err := api_client.FetchAndCast[T](s.client, path, opts...)
+	data, err := api_client.FetchAndCast[T](client, path, opts...)
 	if err != nil {
 		logger.LogError("❌ proxy.CallWithData error: %v", err)
 		return zero, err
@@ -254,6 +310,11 @@ func (s *Service) buildRequestOptions(httpMethod string, structParam any, ctx *r
 		opts = append(opts, s.extractParamsFromStruct(structParam, httpMethod)...)
 	}
 
+	// Share the request's deadline budget with the downstream call
+	if ctx != nil {
+		opts = append(opts, api_client.WithContext(ctx))
+	}
+
 	// Copy headers from context if available
 	if ctx != nil && ctx.R != nil {
 		headers := make(map[string]string)
@@ -0,0 +1,42 @@
+package proxy_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/common/api_client"
+	"github.com/primadi/lokstra/core/proxy"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func TestService_WithTimeout(t *testing.T) {
+	r := router.New("slow")
+	r.GET("/users/{user_id}/orders", func(c *request.Context) error {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-c.Done():
+		}
+		return c.Api.Ok(shardInfo{Name: "slow"})
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	// Also applies to shards added later
+	svc := proxy.NewShardedService([]string{"http://placeholder.invalid"}, routeMap, proxy.ShardByPathParam("user_id")).
+		WithTimeout(100 * time.Millisecond).
+		WithTransport(api_client.NewTransport(nil))
+	if err := svc.SetShards(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err := proxy.CallWithData[*shardInfo](svc, "GetByUserID", &getOrdersRequest{UserID: "1"})
+	if err == nil {
+		t.Fatal("Expected the call to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Call took %v, expected it aborted after 100ms", elapsed)
+	}
+}
@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/primadi/lokstra/common/api_client"
+	"github.com/primadi/lokstra/common/hashring"
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/request"
+)
+
+var (
+	// ErrEmptyShardKey is returned for calls of a sharded service whose
+	// routing key is empty, instead of pinning them all to one shard
+	ErrEmptyShardKey = errors.New("proxy: empty shard key")
+	// ErrNoShards is returned by SetShards for an empty shard set
+	ErrNoShards = errors.New("proxy: no shards")
+)
+
+// ShardKeyFunc returns the routing key of a call (e.g. the user id), from
+// the request context and the struct parameter (either may be nil)
+type ShardKeyFunc func(ctx *request.Context, param any) string
+
+// ShardByPathParam uses path parameter name as routing key, taken like
+// path placeholders: from the struct field tagged `path:"name"`, else
+// from the request context
+func ShardByPathParam(name string) ShardKeyFunc {
+	return func(ctx *request.Context, param any) string {
+		if v, ok := structPathValue(param, name); ok {
+			return v
+		}
+		if ctx != nil {
+			return ctx.Req.PathParam(name, "")
+		}
+		return ""
+	}
+}
+
+// NewShardedService creates a proxy service calling one of several base
+// URLs, chosen by consistent hashing of the call's routing key: the same
+// key always goes to the same shard, and changing the shard set (SetShards)
+// only remaps the keys of the added or removed shards.
+// Example:
+//
+//	users := proxy.NewShardedService(
+//	    []string{"http://users-0:8080", "http://users-1:8080", "http://users-2:8080"},
+//	    routeMap, proxy.ShardByPathParam("user_id"))
+//
+//	// GET http://users-?:8080/users/42/orders, always the same shard for user 42
+//	orders, err := proxy.CallWithData[[]Order](users, "GetByUserID", &GetOrdersRequest{UserID: "42"})
+func NewShardedService(baseURLs []string, routeMap map[string]RouteMapping, shardKey ShardKeyFunc) *Service {
+	if len(baseURLs) == 0 {
+		panic("proxy.NewShardedService: at least one base URL is required")
+	}
+	if shardKey == nil {
+		panic("proxy.NewShardedService: shardKey is required")
+	}
+
+	s := NewService(baseURLs[0], routeMap)
+	s.shardKey = shardKey
+	s.ring = hashring.New(0)
+	s.shards = make(map[string]*api_client.ClientRouter, len(baseURLs))
+	_ = s.SetShards(baseURLs...) // not empty, checked above
+	return s
+}
+
+// SetShards replaces the shard base URLs of a sharded service. Keys of the
+// shards that remain keep their placement. An empty set returns
+// ErrNoShards and keeps the current shards. No-op for unsharded services.
+func (s *Service) SetShards(baseURLs ...string) error {
+	if s.ring == nil {
+		return nil
+	}
+	if len(baseURLs) == 0 {
+		return ErrNoShards
+	}
+
+	s.shardsMu.Lock()
+	shards := make(map[string]*api_client.ClientRouter, len(baseURLs))
+	for _, u := range baseURLs {
+		c, ok := s.shards[u]
+		if !ok {
+			c = newClient(u)
+			c.ServiceName = s.client.ServiceName
+			c.Timeout = s.client.Timeout
+			c.Transport = s.client.Transport
+		}
+		shards[u] = c
+	}
+	s.shards = shards
+	s.ring.Set(baseURLs...)
+	s.shardsMu.Unlock()
+
+	logger.LogDebug("🌐 Sharded service proxy: %d shards %v", len(baseURLs), baseURLs)
+	return nil
+}
+
+// Shards returns the shard base URLs of a sharded service, nil otherwise
+func (s *Service) Shards() []string {
+	if s.ring == nil {
+		return nil
+	}
+	return s.ring.Nodes()
+}
+
+// ShardFor returns the base URL serving key (the base URL for unsharded
+// services), "" for an empty key of a sharded service
+func (s *Service) ShardFor(key string) string {
+	c, err := s.clientForKey(key)
+	if err != nil {
+		return ""
+	}
+	return c.FullURL
+}
+
+// clientFor returns the client of the shard owning the call
+func (s *Service) clientFor(ctx *request.Context, param any) (*api_client.ClientRouter, error) {
+	if s.ring == nil {
+		return s.client, nil
+	}
+	return s.clientForKey(s.shardKey(ctx, param))
+}
+
+func (s *Service) clientForKey(key string) (*api_client.ClientRouter, error) {
+	if s.ring == nil {
+		return s.client, nil
+	}
+	if key == "" {
+		return nil, ErrEmptyShardKey
+	}
+
+	s.shardsMu.RLock()
+	defer s.shardsMu.RUnlock()
+	c, ok := s.shards[s.ring.Get(key)]
+	if !ok {
+		return nil, ErrNoShards
+	}
+	return c, nil
+}
+
+// structPathValue returns the value of the field of param tagged `path:"name"`
+func structPathValue(param any, name string) (string, bool) {
+	if param == nil {
+		return "", false
+	}
+	val := reflect.ValueOf(param)
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return "", false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	typ := val.Type()
+	for i := range val.NumField() {
+		if typ.Field(i).Tag.Get("path") == name {
+			return fmt.Sprintf("%v", val.Field(i).Interface()), true
+		}
+	}
+	return "", false
+}
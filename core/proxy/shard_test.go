@@ -0,0 +1,130 @@
+package proxy_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/proxy"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+)
+
+type shardInfo struct {
+	Name string `json:"name"`
+}
+
+type getOrdersRequest struct {
+	UserID string `path:"user_id"`
+}
+
+// startShards starts n servers answering with their own name
+func startShards(t *testing.T, n int) (urls []string, names map[string]string) {
+	names = make(map[string]string, n)
+	for i := range n {
+		name := fmt.Sprintf("shard-%d", i)
+		r := router.New(name)
+		r.GET("/users/{user_id}/orders", func(c *request.Context) error {
+			return c.Api.Ok(shardInfo{Name: name})
+		})
+		srv := httptest.NewServer(r)
+		t.Cleanup(srv.Close)
+		urls = append(urls, srv.URL)
+		names[srv.URL] = name
+	}
+	return urls, names
+}
+
+var routeMap = map[string]proxy.RouteMapping{
+	"GetByUserID": {HTTPMethod: "GET", Path: "/users/{user_id}/orders"},
+}
+
+func TestShardedService_RoutesByKey(t *testing.T) {
+	urls, names := startShards(t, 3)
+	svc := proxy.NewShardedService(urls, routeMap, proxy.ShardByPathParam("user_id"))
+
+	used := make(map[string]bool)
+	for i := range 30 {
+		userID := fmt.Sprintf("%d", i)
+		for range 2 {
+			got, err := proxy.CallWithData[*shardInfo](svc, "GetByUserID", &getOrdersRequest{UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := names[svc.ShardFor(userID)]; got.Name != want {
+				t.Fatalf("user %s: served by %s, expected %s", userID, got.Name, want)
+			}
+			used[got.Name] = true
+		}
+	}
+	if len(used) != 3 {
+		t.Errorf("expected keys spread over 3 shards, used %v", used)
+	}
+}
+
+func TestShardedService_SetShards(t *testing.T) {
+	urls, _ := startShards(t, 4)
+	svc := proxy.NewShardedService(urls[:3], routeMap, proxy.ShardByPathParam("user_id"))
+
+	before := make(map[string]string)
+	for i := range 1000 {
+		k := fmt.Sprintf("%d", i)
+		before[k] = svc.ShardFor(k)
+	}
+
+	if err := svc.SetShards(urls...); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.Shards(); len(got) != 4 {
+		t.Fatalf("Shards() = %v", got)
+	}
+	moved := 0
+	for k, old := range before {
+		if now := svc.ShardFor(k); now != old {
+			if now != urls[3] {
+				t.Fatalf("key %s moved between existing shards", k)
+			}
+			moved++
+		}
+	}
+	if moved == 0 || moved > 400 {
+		t.Errorf("adding a 4th shard moved %d of 1000 keys", moved)
+	}
+}
+
+func TestShardByPathParam_FromContext(t *testing.T) {
+	key := proxy.ShardByPathParam("user_id")
+
+	var got string
+	r := router.New("test")
+	r.GET("/users/{user_id}", func(c *request.Context) error {
+		got = key(c, nil)
+		return nil
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+
+	if got != "42" {
+		t.Errorf("key from context = %q", got)
+	}
+	if k := key(nil, &getOrdersRequest{UserID: "7"}); k != "7" {
+		t.Errorf("key from struct = %q", k)
+	}
+}
+
+func TestShardedService_EmptyKeyAndShards(t *testing.T) {
+	urls, _ := startShards(t, 2)
+	svc := proxy.NewShardedService(urls, routeMap, proxy.ShardByPathParam("user_id"))
+
+	_, err := proxy.CallWithData[*shardInfo](svc, "GetByUserID", &getOrdersRequest{})
+	if !errors.Is(err, proxy.ErrEmptyShardKey) {
+		t.Errorf("Expected ErrEmptyShardKey for a keyless call, got %v", err)
+	}
+
+	if err := svc.SetShards(); !errors.Is(err, proxy.ErrNoShards) {
+		t.Errorf("Expected ErrNoShards for an empty shard set, got %v", err)
+	}
+	if got := svc.Shards(); len(got) != 2 {
+		t.Errorf("An empty shard set must keep the current shards, got %v", got)
+	}
+}
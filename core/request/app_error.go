@@ -0,0 +1,25 @@
+package request
+
+import (
+	"github.com/primadi/lokstra/common/errs"
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+// writeAppError maps an *errs.AppError (anywhere in err's chain) to its
+// status and error body. The cause is never sent to the client.
+func (c *Context) writeAppError(err error) bool {
+	appErr, ok := errs.As(err)
+	if !ok {
+		return false
+	}
+
+	formatter := api_formatter.GetGlobalFormatter()
+	var formatted any
+	if len(appErr.Fields) > 0 {
+		formatted = formatter.ValidationError(appErr.Message, appErr.Fields)
+	} else {
+		formatted = formatter.Error(appErr.Code, appErr.Message)
+	}
+	c.Resp.WithStatus(appErr.Status()).Json(formatted)
+	return true
+}
@@ -0,0 +1,56 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/common/errs"
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+func TestAppError_MappedToResponse(t *testing.T) {
+	api_formatter.SetGlobalFormatter(api_formatter.NewApiResponseFormatter())
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", errs.NotFound("User not found"), 404, "NOT_FOUND"},
+		{"conflict", errs.Conflict("Email already used"), 409, "CONFLICT"},
+		{"wrapped", fmt.Errorf("update: %w", errs.Conflict("Version mismatch")), 409, "CONFLICT"},
+		{"validation", errs.Validation("Invalid input",
+			errs.FieldError{Field: "email", Code: "EMAIL", Message: "email is invalid"}), 400, "VALIDATION_ERROR"},
+		{"cause hidden", errs.Internal("Could not save").WithCause(errors.New("pq: secret dsn")), 500, "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewHandler(func(c *Context) error { return tt.err }).
+				ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, w.Code)
+			}
+			var body api_formatter.ApiResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Error == nil {
+				t.Fatalf("Expected error body, got %s", w.Body.String())
+			}
+			if body.Error.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, body.Error.Code)
+			}
+			if strings.Contains(w.Body.String(), "secret") {
+				t.Errorf("Cause leaked into response: %s", w.Body.String())
+			}
+			if tt.name == "validation" && (len(body.Error.Fields) != 1 || body.Error.Fields[0].Field != "email") {
+				t.Errorf("Expected email field error, got %v", body.Error.Fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package request
+
+import (
+	"net/url"
+	"sync"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// METRIC_DEPRECATED_PARAM counts requests using a deprecated query param
+// alias (labels: param, alias, route)
+const METRIC_DEPRECATED_PARAM = "deprecated_param_used_total"
+
+// Global metrics resolver set by lokstra_registry at initialization
+var metricsResolver func() serviceapi.Metrics
+
+// SetMetricsResolver sets the resolver for the registered metrics service.
+// Called by lokstra_registry during initialization to avoid circular dependency
+func SetMetricsResolver(resolver func() serviceapi.Metrics) {
+	metricsResolver = resolver
+}
+
+// aliases already reported in the log ("param/alias")
+var warnedAliases sync.Map
+
+// queryAliasValues returns the values of the first deprecated alias present
+// in query (alias:"q"), reporting its use: a one-time warning per alias and
+// a METRIC_DEPRECATED_PARAM counter on every use.
+func (h *RequestHelper) queryAliasValues(fieldMeta bindFieldMeta, query url.Values) []string {
+	for _, alias := range fieldMeta.Aliases {
+		values := query[alias]
+		if len(values) == 0 {
+			continue
+		}
+
+		if _, warned := warnedAliases.LoadOrStore(fieldMeta.Name+"/"+alias, true); !warned {
+			logger.LogWarn("⚠️  Deprecated query param '%s' used, use '%s' instead", alias, fieldMeta.Name)
+		}
+
+		if metricsResolver != nil {
+			if m := metricsResolver(); m != nil {
+				labels := serviceapi.Labels{"param": fieldMeta.Name, "alias": alias, "route": ""}
+				if h.ctx != nil {
+					labels["route"] = h.ctx.RouteName()
+					labels = h.ctx.Correlation().Labels(labels)
+				}
+				m.IncCounter(METRIC_DEPRECATED_PARAM, labels)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package request
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+type counterRecorder struct {
+	serviceapi.Metrics
+	counts map[string]int
+}
+
+func (m *counterRecorder) IncCounter(name string, labels serviceapi.Labels) {
+	m.counts[name+"/"+labels["param"]+"/"+labels["alias"]]++
+}
+
+type searchRequest struct {
+	Search string   `query:"search" alias:"q"`
+	Tags   []string `query:"tags" alias:"tag, t"`
+}
+
+func bindSearch(t *testing.T, target string) searchRequest {
+	t.Helper()
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil), nil)
+	var req searchRequest
+	if err := c.Req.BindQuery(&req); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	return req
+}
+
+func TestBindQuery_Alias(t *testing.T) {
+	m := &counterRecorder{counts: map[string]int{}}
+	SetMetricsResolver(func() serviceapi.Metrics { return m })
+	defer SetMetricsResolver(nil)
+
+	// Canonical name: no deprecation signal
+	req := bindSearch(t, "/items?search=go&tags=a,b")
+	if req.Search != "go" || len(req.Tags) != 2 {
+		t.Errorf("Unexpected binding via canonical names: %+v", req)
+	}
+	if len(m.counts) != 0 {
+		t.Errorf("Expected no deprecation signal, got %v", m.counts)
+	}
+
+	// Deprecated aliases populate the field and are counted
+	req = bindSearch(t, "/items?q=go&t=a&t=b")
+	if req.Search != "go" || len(req.Tags) != 2 || req.Tags[1] != "b" {
+		t.Errorf("Unexpected binding via aliases: %+v", req)
+	}
+	bindSearch(t, "/items?q=rust")
+	if got := m.counts[METRIC_DEPRECATED_PARAM+"/search/q"]; got != 2 {
+		t.Errorf("Expected alias 'q' counted twice, got %d", got)
+	}
+	if got := m.counts[METRIC_DEPRECATED_PARAM+"/tags/t"]; got != 1 {
+		t.Errorf("Expected alias 't' counted once, got %d", got)
+	}
+
+	// One-time warning per alias
+	if _, warned := warnedAliases.Load("search/q"); !warned {
+		t.Errorf("Expected deprecation warning for alias 'q'")
+	}
+
+	// Canonical name wins when both are present
+	req = bindSearch(t, "/items?search=new&q=old")
+	if req.Search != "new" {
+		t.Errorf("Expected canonical value 'new', got %q", req.Search)
+	}
+}
@@ -0,0 +1,87 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type sessionRequest struct {
+	SessionID string `cookie:"session_id" validate:"required"`
+	Theme     string `cookie:"theme"`
+	Visits    int    `cookie:"visits"`
+}
+
+func cookieContext(cookies ...*http.Cookie) *Context {
+	r := httptest.NewRequest("GET", "/profile", nil)
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	return NewContext(httptest.NewRecorder(), r, nil)
+}
+
+func TestBindCookie(t *testing.T) {
+	c := cookieContext(
+		&http.Cookie{Name: "session_id", Value: "abc123"},
+		&http.Cookie{Name: "visits", Value: "7"},
+	)
+
+	var req sessionRequest
+	if err := c.Req.BindCookie(&req); err != nil {
+		t.Fatalf("BindCookie failed: %v", err)
+	}
+	if req.SessionID != "abc123" || req.Visits != 7 || req.Theme != "" {
+		t.Errorf("Unexpected binding: %+v", req)
+	}
+}
+
+func TestBindCookie_MissingRequired(t *testing.T) {
+	c := cookieContext(&http.Cookie{Name: "theme", Value: "dark"})
+
+	var req sessionRequest
+	err := c.Req.BindCookie(&req)
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	if len(valErr.FieldErrors) != 1 || valErr.FieldErrors[0].Field != "SessionID" {
+		t.Errorf("Unexpected field errors: %+v", valErr.FieldErrors)
+	}
+}
+
+func TestBindCookie_InvalidValue(t *testing.T) {
+	c := cookieContext(
+		&http.Cookie{Name: "session_id", Value: "abc123"},
+		&http.Cookie{Name: "visits", Value: "many"},
+	)
+
+	var req sessionRequest
+	if err := c.Req.BindCookie(&req); err == nil {
+		t.Error("Expected a conversion error")
+	}
+}
+
+func TestBindAll_Cookie(t *testing.T) {
+	type updateProfile struct {
+		SessionID string `cookie:"session_id" validate:"required"`
+		Name      string `query:"name"`
+	}
+
+	c := cookieContext(&http.Cookie{Name: "session_id", Value: "abc123"})
+	c.R.URL.RawQuery = "name=ana"
+	var req updateProfile
+	if err := c.Req.BindAll(&req); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+	if req.SessionID != "abc123" || req.Name != "ana" {
+		t.Errorf("Unexpected binding: %+v", req)
+	}
+
+	var missing updateProfile
+	err := cookieContext().Req.BindAll(&missing)
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) || len(valErr.FieldErrors) != 1 {
+		t.Fatalf("Expected one field error for the missing cookie, got %v", err)
+	}
+}
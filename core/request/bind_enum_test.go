@@ -0,0 +1,80 @@
+package request
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/common/enum"
+)
+
+type testStatus string
+
+var testStatuses = enum.Define[testStatus]("active", "archived")
+
+func (s testStatus) Valid() bool           { return testStatuses.Valid(s) }
+func (s testStatus) Values() []string      { return testStatuses.Strings() }
+func (s *testStatus) Parse(v string) error { return testStatuses.ParseInto(s, v) }
+
+func TestBindQuery_EnumParsed(t *testing.T) {
+	type ListRequest struct {
+		Status testStatus `query:"status"`
+	}
+
+	req := httptest.NewRequest("GET", "/items?status=ARCHIVED", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r ListRequest
+	if err := ctx.Req.BindQuery(&r); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if r.Status != "archived" {
+		t.Errorf("Expected normalized 'archived', got %q", r.Status)
+	}
+}
+
+func TestBindQuery_EnumInvalid(t *testing.T) {
+	type ListRequest struct {
+		Status testStatus `query:"status"`
+	}
+
+	req := httptest.NewRequest("GET", "/items?status=deleted", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r ListRequest
+	err := ctx.Req.BindQuery(&r)
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T (%v)", err, err)
+	}
+	if len(valErr.FieldErrors) != 1 || valErr.FieldErrors[0].Field != "Status" {
+		t.Errorf("Expected field error on Status, got %v", valErr.FieldErrors)
+	}
+}
+
+func TestBindBody_EnumInvalid(t *testing.T) {
+	type UpdateRequest struct {
+		Status testStatus `json:"status"`
+	}
+
+	req := httptest.NewRequest("POST", "/items/1", bytes.NewBufferString(`{"status":"deleted"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r UpdateRequest
+	err := ctx.Req.BindBody(&r)
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T (%v)", err, err)
+	}
+	if valErr.FieldErrors[0].Field != "status" {
+		t.Errorf("Expected field error on status, got %v", valErr.FieldErrors)
+	}
+
+	req = httptest.NewRequest("POST", "/items/1", bytes.NewBufferString(`{"status":"active"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx = NewContext(httptest.NewRecorder(), req, nil)
+	if err := ctx.Req.BindBody(&r); err != nil || r.Status != "active" {
+		t.Errorf("Expected valid enum to bind, got %q (%v)", r.Status, err)
+	}
+}
@@ -0,0 +1,88 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/primadi/lokstra/common/id"
+)
+
+// bindErrors accumulates path/query/header/body binding and validation
+// errors of BindAll into a single ValidationError
+type bindErrors struct {
+	fields []FieldError
+	failed map[string]bool // fields that failed to bind (param and Go names)
+}
+
+// addField records a field that could not be bound (e.g. "abc" for an int)
+func (e *bindErrors) addField(fieldMeta bindFieldMeta, err error) {
+	if e.failed == nil {
+		e.failed = make(map[string]bool)
+	}
+	e.failed[fieldMeta.Name] = true
+	e.failed[fieldMeta.Field.Name] = true
+
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		e.fields = append(e.fields, valErr.FieldErrors...)
+		return
+	}
+
+	t := fieldMeta.Field.Type
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	message := fmt.Sprintf("%s must be a valid %s", fieldMeta.Name, bindTypeName(t))
+	if fieldMeta.Layout != "" {
+		message = fmt.Sprintf("%s must be a time in the %s layout", fieldMeta.Name, fieldMeta.Layout)
+	}
+	e.fields = append(e.fields, FieldError{
+		Field:   fieldMeta.Name,
+		Code:    "INVALID_" + strings.ToUpper(fieldMeta.Tag),
+		Message: message,
+	})
+}
+
+// bindTypeName names t in bind error messages, e.g. "int" or "UUID"
+func bindTypeName(t reflect.Type) string {
+	if id.IsTextType(t) {
+		return id.KindOf(t)
+	}
+	return t.Kind().String()
+}
+
+// addBody records a body error. Returns false (not accumulated) when the
+// body is unreadable or not valid JSON at all.
+func (e *bindErrors) addBody(err error, body []byte) bool {
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) || !jsonDecoder.Valid(body) {
+		return false
+	}
+	e.fields = append(e.fields, valErr.FieldErrors...)
+	return true
+}
+
+// addValidation records validation errors, except for fields that already
+// failed to bind. Returns false for system (non-validation) errors.
+func (e *bindErrors) addValidation(err error) bool {
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		return false
+	}
+	for _, fe := range valErr.FieldErrors {
+		if !e.failed[fe.Field] {
+			e.fields = append(e.fields, fe)
+		}
+	}
+	return true
+}
+
+// err returns the accumulated ValidationError, or nil
+func (e *bindErrors) err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{FieldErrors: e.fields}
+}
@@ -0,0 +1,68 @@
+package request
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/primadi/lokstra/common/id"
+)
+
+// idFieldErrors reports malformed ID values (uuid.UUID, id.ULID, ...) of
+// the top-level JSON fields of v as field errors. Used when the body failed
+// to decode, so a bad ID yields "order_id must be a valid UUID" instead of
+// a generic invalid JSON error.
+func idFieldErrors(data []byte, v any) []FieldError {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	var raw map[string]any
+	if jsonDecoder.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+
+	var fields []FieldError
+	for _, fm := range getOrBuildBindMeta(t).Fields {
+		if fm.Tag != "json" || fm.IsWildcard {
+			continue
+		}
+		name, _, _ := strings.Cut(fm.Name, ",")
+		value, ok := raw[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		ft := fm.Field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		values := []any{value}
+		if ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+			if values, ok = value.([]any); !ok {
+				continue
+			}
+		}
+		if !id.IsTextType(ft) {
+			continue
+		}
+
+		for _, v := range values {
+			s, ok := v.(string)
+			if ok && reflect.New(ft).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)) == nil {
+				continue
+			}
+			fields = append(fields, FieldError{
+				Field:   name,
+				Code:    "INVALID_JSON",
+				Message: fmt.Sprintf("%s must be a valid %s", name, id.KindOf(ft)),
+				Value:   v,
+			})
+			break
+		}
+	}
+	return fields
+}
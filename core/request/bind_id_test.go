@@ -0,0 +1,165 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/primadi/lokstra/common/id"
+)
+
+const (
+	testUUID = "3f2a9c1b-7d4e-4b8a-9c2d-1e5f6a7b8c9d"
+	testULID = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+)
+
+func TestBindAll_ValidIDs(t *testing.T) {
+	type GetOrderRequest struct {
+		OrderID  uuid.UUID  `path:"id"`
+		Customer id.ULID    `query:"customer"`
+		Related  []id.ULID  `query:"related"`
+		Parent   *uuid.UUID `query:"parent"`
+	}
+
+	req := httptest.NewRequest("GET", "/orders/"+testUUID+"?customer="+testULID+"&related="+testULID, nil)
+	req.SetPathValue("id", testUUID)
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r GetOrderRequest
+	if err := ctx.Req.BindAll(&r); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+	if r.OrderID.String() != testUUID {
+		t.Errorf("OrderID = %s, want %s", r.OrderID, testUUID)
+	}
+	if r.Customer.String() != testULID {
+		t.Errorf("Customer = %s, want %s", r.Customer, testULID)
+	}
+	if len(r.Related) != 1 || r.Related[0].String() != testULID {
+		t.Errorf("Related = %v, want [%s]", r.Related, testULID)
+	}
+	if r.Parent != nil {
+		t.Errorf("Parent = %v, want nil", r.Parent)
+	}
+}
+
+func TestBindAll_InvalidIDs(t *testing.T) {
+	type GetOrderRequest struct {
+		OrderID  uuid.UUID `path:"id"`
+		Customer id.ULID   `query:"customer"`
+	}
+
+	req := httptest.NewRequest("GET", "/orders/not-a-uuid?customer=01ARZ3NDEK", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r GetOrderRequest
+	err := ctx.Req.BindAll(&r)
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T (%v)", err, err)
+	}
+	want := map[string]string{
+		"id":       "id must be a valid UUID",
+		"customer": "customer must be a valid ULID",
+	}
+	if len(valErr.FieldErrors) != len(want) {
+		t.Fatalf("Expected %d field errors, got %v", len(want), valErr.FieldErrors)
+	}
+	for _, fe := range valErr.FieldErrors {
+		if want[fe.Field] != fe.Message {
+			t.Errorf("Field %s: message %q, want %q", fe.Field, fe.Message, want[fe.Field])
+		}
+	}
+}
+
+func TestBindAll_RequiredID(t *testing.T) {
+	type GetOrderRequest struct {
+		Customer id.ULID `query:"customer" validate:"required"`
+	}
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r GetOrderRequest
+	err := ctx.Req.BindAll(&r)
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T (%v)", err, err)
+	}
+	if len(valErr.FieldErrors) != 1 || valErr.FieldErrors[0].Field != "Customer" {
+		t.Errorf("Expected required error on Customer, got %v", valErr.FieldErrors)
+	}
+}
+
+func TestBindBody_IDs(t *testing.T) {
+	type CreateOrderRequest struct {
+		CustomerID uuid.UUID `json:"customer_id"`
+		ItemIDs    []id.ULID `json:"item_ids,omitempty"`
+	}
+
+	body := `{"customer_id":"` + testUUID + `","item_ids":["` + testULID + `"]}`
+	req := httptest.NewRequest("POST", "/orders", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var r CreateOrderRequest
+	if err := ctx.Req.BindBody(&r); err != nil {
+		t.Fatalf("BindBody failed: %v", err)
+	}
+	if r.CustomerID.String() != testUUID || len(r.ItemIDs) != 1 || r.ItemIDs[0].String() != testULID {
+		t.Errorf("Unexpected bound IDs: %+v", r)
+	}
+
+	body = `{"customer_id":"` + testUUID + `","item_ids":["bad-ulid"]}`
+	req = httptest.NewRequest("POST", "/orders", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx = NewContext(httptest.NewRecorder(), req, nil)
+
+	err := ctx.Req.BindBody(&CreateOrderRequest{})
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T (%v)", err, err)
+	}
+	if len(valErr.FieldErrors) != 1 || valErr.FieldErrors[0].Field != "item_ids" ||
+		valErr.FieldErrors[0].Message != "item_ids must be a valid ULID" {
+		t.Errorf("Expected ULID field error on item_ids, got %v", valErr.FieldErrors)
+	}
+}
+
+type testOrderID string
+
+func (o *testOrderID) UnmarshalText(b []byte) error {
+	if !bytes.HasPrefix(b, []byte("ord_")) {
+		return errInvalidOrderID
+	}
+	*o = testOrderID(b)
+	return nil
+}
+
+func (testOrderID) IDKind() string { return "order ID" }
+
+var errInvalidOrderID = errors.New("order IDs start with ord_")
+
+func TestBindQuery_CustomIDType(t *testing.T) {
+	type GetRequest struct {
+		Order testOrderID `query:"order"`
+	}
+
+	req := httptest.NewRequest("GET", "/?order=ord_42", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+	var r GetRequest
+	if err := ctx.Req.BindAll(&r); err != nil || r.Order != "ord_42" {
+		t.Fatalf("BindAll = %v, Order = %q", err, r.Order)
+	}
+
+	req = httptest.NewRequest("GET", "/?order=42", nil)
+	ctx = NewContext(httptest.NewRecorder(), req, nil)
+	err := ctx.Req.BindAll(&GetRequest{})
+	valErr, ok := err.(*ValidationError)
+	if !ok || len(valErr.FieldErrors) != 1 || valErr.FieldErrors[0].Message != "order must be a valid order ID" {
+		t.Errorf("Expected order ID field error, got %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package request
 
 import (
 	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
 type bindFieldMeta struct {
@@ -18,11 +20,20 @@ type bindFieldMeta struct {
 	IndexValue        []int
 	IsMap             bool
 	IsWildcard        bool // true if json:"*" - captures all body as map
+
+	// Deprecated alternative names (alias:"q,query"), query params only
+	Aliases []string
+
+	// Parse layout of time.Time fields (layout:"2006-01-02")
+	Layout string
 }
 
 type bindMeta struct {
 	Type   reflect.Type
 	Fields []bindFieldMeta
+
+	// Embedded DateRange fields, resolved after binding
+	DateRanges []dateRangeMeta
 }
 
 var bindMetaCache sync.Map // map[reflect.Type]*bindMeta
@@ -43,6 +54,9 @@ func getOrBuildBindMeta(t reflect.Type) *bindMeta {
 	bm := &bindMeta{
 		Type: t,
 	}
+	if t == dateRangeType {
+		bm.DateRanges = append(bm.DateRanges, newDateRangeMeta(nil))
+	}
 
 	numField := t.NumField()
 	for i := range numField {
@@ -56,6 +70,9 @@ func getOrBuildBindMeta(t reflect.Type) *bindMeta {
 			if ft.Kind() == reflect.Pointer {
 				ft = ft.Elem()
 			}
+			if field.Type == dateRangeType {
+				bm.DateRanges = append(bm.DateRanges, newDateRangeMeta(&field))
+			}
 			if ft.Kind() == reflect.Struct {
 				// iterate inner struct fields
 				innerNum := ft.NumField()
@@ -107,6 +124,8 @@ func getOrBuildBindMeta(t reflect.Type) *bindMeta {
 						IndexValue:        indexValue,
 						IsMap:             isMap,
 						IsWildcard:        isWildcard,
+						Aliases:           parseAliasTag(inner),
+						Layout:            parseLayoutTag(inner, field),
 					}
 					bm.Fields = append(bm.Fields, fieldMeta)
 				}
@@ -158,6 +177,8 @@ func getOrBuildBindMeta(t reflect.Type) *bindMeta {
 			IndexValue:        indexValue,
 			IsMap:             isMap,
 			IsWildcard:        isWildcard,
+			Aliases:           parseAliasTag(field),
+			Layout:            parseLayoutTag(field),
 		}
 
 		bm.Fields = append(bm.Fields, fieldMeta)
@@ -171,8 +192,8 @@ func getOrBuildBindMeta(t reflect.Type) *bindMeta {
 }
 
 func parseBindingTag(field reflect.StructField) (tagType, paramName string, isWildcard bool) {
-	// Check for path, query, header tags
-	for _, key := range []string{"path", "query", "header"} {
+	// Check for path, query, header, cookie tags
+	for _, key := range []string{"path", "query", "header", "cookie"} {
 		if val, ok := field.Tag.Lookup(key); ok && val != "" {
 			return key, val, false
 		}
@@ -188,7 +209,41 @@ func parseBindingTag(field reflect.StructField) (tagType, paramName string, isWi
 	}
 
 	return "", "", false
-} // unmarshalJSONType represents the interface type for json.Unmarshaler
+}
+
+// parseAliasTag returns the deprecated names of a field: alias:"q,query"
+func parseAliasTag(field reflect.StructField) []string {
+	val, ok := field.Tag.Lookup("alias")
+	if !ok || val == "" {
+		return nil
+	}
+	var aliases []string
+	for a := range strings.SplitSeq(val, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			aliases = append(aliases, a)
+		}
+	}
+	return aliases
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// parseLayoutTag returns the parse layout of a time.Time or *time.Time
+// field: layout:"2006-01-02". For fields of an embedded struct, a layout tag
+// on the embedded field overrides theirs.
+func parseLayoutTag(field reflect.StructField, embedded ...reflect.StructField) string {
+	if field.Type != timeType && field.Type != reflect.PointerTo(timeType) {
+		return ""
+	}
+	for _, e := range embedded {
+		if layout := e.Tag.Get("layout"); layout != "" {
+			return layout
+		}
+	}
+	return field.Tag.Get("layout")
+}
+
+// unmarshalJSONType represents the interface type for json.Unmarshaler
 var unmarshalJSONType = reflect.TypeOf((*interface {
 	UnmarshalJSON([]byte) error
 })(nil)).Elem()
@@ -1,12 +1,16 @@
 package request
 
 import (
+	"encoding"
 	"errors"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/primadi/lokstra/common/enum"
+	"github.com/primadi/lokstra/common/id"
 	"github.com/primadi/lokstra/common/json"
 )
 
@@ -37,6 +41,29 @@ func convertAndSetField(field reflect.Value, rawValues []string, isSlice bool, i
 	return nil
 }
 
+// setFieldValues sets a bound field, parsing time.Time fields with their
+// layout tag
+func setFieldValues(field reflect.Value, fieldMeta bindFieldMeta, rawValues []string) error {
+	if fieldMeta.Layout == "" {
+		return convertAndSetField(field, rawValues, fieldMeta.IsSlice, fieldMeta.IsUnmarshalJSON)
+	}
+
+	// Empty input leaves the zero value (or nil), like the other types
+	if len(rawValues) == 0 || rawValues[0] == "" {
+		return nil
+	}
+	t, err := time.Parse(fieldMeta.Layout, rawValues[0])
+	if err != nil {
+		return err
+	}
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.ValueOf(&t))
+	} else {
+		field.Set(reflect.ValueOf(t))
+	}
+	return nil
+}
+
 // setValue sets the value of a field based on its type and the provided raw string.
 func setValue(field reflect.Value, raw string, isUnmarshalJSON bool) error {
 	if isUnmarshalJSON {
@@ -67,6 +94,25 @@ func setValue(field reflect.Value, raw string, isUnmarshalJSON bool) error {
 		return nil
 	}
 
+	// Typed enums normalize raw input via Parse. Invalid input is kept as-is
+	// so the validator reports it as a field error instead of a bind failure.
+	if raw != "" && field.CanAddr() {
+		if p, ok := field.Addr().Interface().(enum.Parser); ok {
+			if err := p.Parse(raw); err == nil {
+				return nil
+			}
+		}
+	}
+
+	// ID types (uuid.UUID, id.ULID, ...) parse and validate via UnmarshalText.
+	// Empty input leaves the zero value for `validate:"required"` to report.
+	if field.CanAddr() && id.IsTextType(field.Type()) {
+		if raw == "" {
+			return nil
+		}
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(raw)
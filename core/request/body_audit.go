@@ -0,0 +1,428 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/response"
+)
+
+// BodyHook receives a captured request or response body (already redacted).
+// Hooks run after the response was written, so they never delay or alter
+// the exchange; binding and streaming work as usual.
+type BodyHook func(c *Context, body []byte)
+
+// RedactedValue replaces redacted JSON fields in hook bodies
+const RedactedValue = "[REDACTED]"
+
+// DefaultHookMaxBodySize is the capture limit of body hooks
+const DefaultHookMaxBodySize = 1 << 20 // 1MB
+
+// BodyHookOption configures a body hook
+type BodyHookOption func(*bodyHook)
+
+type bodyHook struct {
+	fn      BodyHook
+	routes  []string
+	redact  map[string]bool
+	maxSize int
+}
+
+// WithHookRoutes limits the hook to matching routes. A pattern matches the
+// route name (e.g. "api.create-payment"), the route pattern (e.g. "/payments/{id}")
+// or the request path, with "/prefix/**" matching everything below prefix.
+func WithHookRoutes(patterns ...string) BodyHookOption {
+	return func(h *bodyHook) {
+		h.routes = append(h.routes, patterns...)
+	}
+}
+
+// WithHookRedactFields replaces these JSON fields (case-insensitive, at any
+// depth) by RedactedValue before the hook sees the body. Bodies that are
+// not valid JSON are withheld (the hook receives a nil body).
+func WithHookRedactFields(fields ...string) BodyHookOption {
+	return func(h *bodyHook) {
+		for _, f := range fields {
+			h.redact[strings.ToLower(f)] = true
+		}
+	}
+}
+
+// WithHookMaxBodySize sets the captured size limit (default DefaultHookMaxBodySize).
+// Larger bodies are truncated; when redaction is configured they cannot be
+// redacted reliably and are withheld (the hook receives a nil body).
+func WithHookMaxBodySize(n int) BodyHookOption {
+	return func(h *bodyHook) {
+		h.maxSize = n
+	}
+}
+
+// BodyHooks holds the body hooks of an app
+type BodyHooks struct {
+	request  []*bodyHook
+	response []*bodyHook
+}
+
+// OnRequestBody adds a hook receiving request bodies
+func (h *BodyHooks) OnRequestBody(fn BodyHook, opts ...BodyHookOption) {
+	h.request = append(h.request, newBodyHook(fn, opts))
+}
+
+// OnResponseBody adds a hook receiving response bodies
+func (h *BodyHooks) OnResponseBody(fn BodyHook, opts ...BodyHookOption) {
+	h.response = append(h.response, newBodyHook(fn, opts))
+}
+
+// IsEmpty reports whether no hook was added
+func (h *BodyHooks) IsEmpty() bool {
+	return h == nil || len(h.request) == 0 && len(h.response) == 0
+}
+
+func newBodyHook(fn BodyHook, opts []BodyHookOption) *bodyHook {
+	h := &bodyHook{fn: fn, redact: map[string]bool{}, maxSize: DefaultHookMaxBodySize}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+type bodyHooksKey struct{}
+
+// WithBodyHooks returns a copy of ctx carrying the body hooks.
+// Used by app.OnRequestBody/OnResponseBody; contexts created for requests
+// carrying it capture bodies for the hooks.
+func WithBodyHooks(ctx context.Context, h *BodyHooks) context.Context {
+	return context.WithValue(ctx, bodyHooksKey{}, h)
+}
+
+func bodyHooksFromContext(ctx context.Context) *BodyHooks {
+	h, _ := ctx.Value(bodyHooksKey{}).(*BodyHooks)
+	return h
+}
+
+// bodyAudit captures the bodies of one request for its matching hooks
+type bodyAudit struct {
+	request, response []*bodyHook
+
+	reqBody  *captureReader
+	respBody *captureWriter
+}
+
+// startBodyAudit wraps the request body and response writer when hooks
+// match the route. Bodies are copied as they are read/written.
+func (c *Context) startBodyAudit() {
+	hooks := bodyHooksFromContext(c.Context)
+	if hooks.IsEmpty() || c.R == nil {
+		return
+	}
+
+	a := &bodyAudit{
+		request:  c.matchingHooks(hooks.request),
+		response: c.matchingHooks(hooks.response),
+	}
+	if len(a.request) > 0 && c.R.Body != nil && c.R.Body != http.NoBody {
+		a.reqBody = &captureReader{ReadCloser: c.R.Body, limit: maxHookSize(a.request)}
+		c.R.Body = a.reqBody
+	}
+	if len(a.response) > 0 {
+		a.respBody = &captureWriter{ResponseWriter: c.W.ResponseWriter, limit: maxHookSize(a.response)}
+		c.W.ResponseWriter = response.WrapWriter(c.W.ResponseWriter, a.respBody)
+	}
+	if a.reqBody != nil || a.respBody != nil {
+		c.audit = a
+	}
+}
+
+// completeRequestBody captures the part of the request body the handler
+// did not read. Called after the response is written, only for routes with
+// request hooks. At most unreadBodyCaptureLimit bytes are read, what
+// net/http reads anyway before sending the response; a longer remainder is
+// left unread and the capture is truncated.
+func (c *Context) completeRequestBody() {
+	if c.audit == nil || c.audit.reqBody == nil {
+		return
+	}
+	r := c.audit.reqBody
+	if !r.started && expectsContinue(c.R) {
+		// The client waits for 100 Continue before sending the body; reading
+		// it now would ask for an upload the handler never wanted
+		return
+	}
+	if remaining := r.limit - r.buf.Len(); remaining > 0 && !r.eof {
+		n := min(remaining, unreadBodyCaptureLimit)
+		if _, err := io.CopyN(io.Discard, r, int64(n)+1); err != io.EOF {
+			// Body longer than captured, or no longer readable
+			r.truncated = true
+		}
+	}
+}
+
+// unreadBodyCaptureLimit matches the unread body net/http discards after
+// the handler to keep the connection alive
+const unreadBodyCaptureLimit = 256 << 10
+
+// expectsContinue reports whether the client sent "Expect: 100-continue"
+// and waits for it before sending the body
+func expectsContinue(r *http.Request) bool {
+	return r.ProtoAtLeast(1, 1) && strings.EqualFold(r.Header.Get("Expect"), "100-continue")
+}
+
+// runBodyHooks passes the captured bodies to the hooks. A panicking hook is
+// logged and does not affect the response or other hooks.
+func (c *Context) runBodyHooks() {
+	if c.audit == nil {
+		return
+	}
+	if r := c.audit.reqBody; r != nil {
+		for _, h := range c.audit.request {
+			c.runBodyHook(h, r.buf.Bytes(), r.truncated)
+		}
+	}
+	if w := c.audit.respBody; w != nil {
+		for _, h := range c.audit.response {
+			c.runBodyHook(h, w.buf.Bytes(), w.truncated)
+		}
+	}
+}
+
+func (c *Context) runBodyHook(h *bodyHook, body []byte, truncated bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.LogError("body hook panic on %s %s: %v", c.R.Method, c.R.URL.Path, rec)
+		}
+	}()
+
+	if len(body) > h.maxSize {
+		body, truncated = body[:h.maxSize], true
+	}
+	if len(h.redact) > 0 {
+		if truncated {
+			body = nil
+		} else {
+			body = redactJSONFields(body, h.redact)
+		}
+	}
+	h.fn(c, body)
+}
+
+func (c *Context) matchingHooks(hooks []*bodyHook) []*bodyHook {
+	var matched []*bodyHook
+	for _, h := range hooks {
+		if c.matchHookRoutes(h.routes) {
+			matched = append(matched, h)
+		}
+	}
+	return matched
+}
+
+func (c *Context) matchHookRoutes(patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if c.route != nil && (p == c.route.Name || p == c.route.Path) {
+			return true
+		}
+		if p == c.R.URL.Path {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "**"); ok && strings.HasPrefix(c.R.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func maxHookSize(hooks []*bodyHook) int {
+	n := 0
+	for _, h := range hooks {
+		n = max(n, h.maxSize)
+	}
+	return n
+}
+
+// captureReader copies what is read from the request body, up to limit
+type captureReader struct {
+	io.ReadCloser
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+	eof       bool
+	started   bool // Read was called
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	r.started = true
+	n, err := r.ReadCloser.Read(p)
+	r.capture(p[:n])
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+func (r *captureReader) capture(b []byte) {
+	if room := r.limit - r.buf.Len(); len(b) > room {
+		b = b[:max(room, 0)]
+		r.truncated = true
+	}
+	r.buf.Write(b)
+}
+
+// captureWriter copies the response body, up to limit, while passing
+// writes through (installed with response.WrapWriter)
+type captureWriter struct {
+	http.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	captured := b[:n]
+	if room := w.limit - w.buf.Len(); len(captured) > room {
+		captured = captured[:max(room, 0)]
+		w.truncated = true
+	}
+	w.buf.Write(captured)
+	return n, err
+}
+
+// redactJSONFields replaces the given fields of a JSON body. Bodies that
+// are not valid JSON cannot be redacted and are withheld (nil).
+func redactJSONFields(b []byte, fields map[string]bool) []byte {
+	var v any
+	if len(b) == 0 {
+		return b
+	}
+	if json.Unmarshal(b, &v) != nil {
+		return nil
+	}
+	if !redactJSONValue(v, fields) {
+		return b
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return out
+}
+
+func redactJSONValue(v any, fields map[string]bool) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]any:
+		for k, inner := range val {
+			if fields[strings.ToLower(k)] {
+				val[k] = RedactedValue
+				changed = true
+			} else if redactJSONValue(inner, fields) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, inner := range val {
+			if redactJSONValue(inner, fields) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// AuditRecord is one audited body
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"` // AuditRequest or AuditResponse
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Route  string    `json:"route,omitempty"`
+	Status int       `json:"status,omitempty"` // response records only
+	Body   string    `json:"body"`
+
+	Correlation
+}
+
+// Audit record kinds
+const (
+	AuditRequest  = "request"
+	AuditResponse = "response"
+)
+
+// AuditSink persists audit records. Implementations must be append-only
+// and safe for concurrent use.
+type AuditSink interface {
+	Append(rec *AuditRecord) error
+}
+
+// AuditTo returns a body hook appending an AuditRecord of the given kind
+// (AuditRequest or AuditResponse) to sink.
+//
+// Example:
+//
+//	sink, _ := request.NewFileAuditSink("/var/log/app/audit.jsonl")
+//	app.OnRequestBody(request.AuditTo(sink, request.AuditRequest),
+//	    request.WithHookRoutes("/api/payments/**"),
+//	    request.WithHookRedactFields("card_number", "cvv"))
+func AuditTo(sink AuditSink, kind string) BodyHook {
+	return func(c *Context, body []byte) {
+		rec := &AuditRecord{
+			Time:   time.Now(),
+			Kind:   kind,
+			Method: c.R.Method,
+			Path:   c.R.URL.Path,
+			Body:   string(body),
+		}
+		rec.Route = c.RouteName()
+		rec.Correlation = c.Correlation()
+		if kind == AuditResponse {
+			rec.Status = c.StatusCode()
+		}
+		if err := sink.Append(rec); err != nil {
+			logger.LogError("audit: failed to append %s record for %s %s: %v",
+				kind, rec.Method, rec.Path, err)
+		}
+	}
+}
+
+// FileAuditSink appends audit records as JSON lines to a file.
+// The file is opened in append mode and never truncated.
+type FileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens (or creates) the audit file for appending
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditSink{file: f}, nil
+}
+
+// Append implements AuditSink
+func (s *FileAuditSink) Append(rec *AuditRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the audit file
+func (s *FileAuditSink) Close() error {
+	return s.file.Close()
+}
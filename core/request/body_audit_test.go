@@ -0,0 +1,84 @@
+package request_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+func serveWithHooks(t *testing.T, hooks *request.BodyHooks, fn request.HandlerFunc) *httptest.Server {
+	t.Helper()
+	handler := request.NewHandler(fn)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(request.WithBodyHooks(r.Context(), hooks)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBodyHooks_RedactionWithholdsInvalidJSON(t *testing.T) {
+	hooks := &request.BodyHooks{}
+	captured := make(chan []byte, 1)
+	hooks.OnRequestBody(func(c *request.Context, body []byte) {
+		captured <- body
+	}, request.WithHookRedactFields("password"))
+
+	srv := serveWithHooks(t, hooks, func(c *request.Context) error {
+		_, _ = c.Req.RawRequestBody()
+		return c.Api.Ok("ok")
+	})
+
+	res, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"password":"hunter2"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if body := <-captured; body != nil {
+		t.Errorf("Expected unparsable body to be withheld, got %q", body)
+	}
+}
+
+func TestBodyHooks_UnreadBodyCaptureBounded(t *testing.T) {
+	hooks := &request.BodyHooks{}
+	captured := make(chan int, 1)
+	hooks.OnRequestBody(func(c *request.Context, body []byte) {
+		captured <- len(body)
+	}, request.WithHookMaxBodySize(4<<20))
+
+	handler := request.NewHandler(func(c *request.Context) error {
+		return c.Api.Ok("ignored") // body never read
+	})
+
+	// A large unread body is not read to the hook limit before responding
+	body := &countingReader{r: strings.NewReader(strings.Repeat("x", 2<<20))}
+	req := httptest.NewRequest("POST", "/upload", body)
+	req = req.WithContext(request.WithBodyHooks(req.Context(), hooks))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if body.n > 512<<10 {
+		t.Errorf("Expected at most about 256KB of the unread body read, got %d bytes", body.n)
+	}
+	if n := <-captured; n == 0 || n > 512<<10 {
+		t.Errorf("Expected a truncated capture, got %d bytes", n)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,65 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/primadi/lokstra/common/errs"
+)
+
+// DefaultMaxBufferedBody is the default cap of request bodies buffered by
+// RawRequestBody (and binding)
+const DefaultMaxBufferedBody int64 = 32 << 20 // 32MB
+
+var maxBufferedBody = DefaultMaxBufferedBody
+
+// SetMaxBufferedBody sets the largest request body buffered for reuse.
+// Larger bodies are rejected with 413 by RawRequestBody and binding; stream
+// them from c.R.Body instead. 0 disables the cap. Call during startup.
+func SetMaxBufferedBody(n int64) {
+	maxBufferedBody = n
+}
+
+// GetMaxBufferedBody returns the current cap of buffered request bodies
+func GetMaxBufferedBody() int64 {
+	return maxBufferedBody
+}
+
+// readBufferedBody reads body up to limit bytes (0 = unlimited)
+func readBufferedBody(body io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(body)
+	}
+	b, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, errs.New(http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE",
+			fmt.Sprintf("Request body exceeds %d bytes", limit))
+	}
+	return b, nil
+}
+
+// replayBody re-exposes a buffered request body as c.R.Body. It rewinds
+// at EOF and on Close, so every later reader gets the whole body.
+type replayBody struct {
+	data []byte
+	off  int
+}
+
+func (b *replayBody) Read(p []byte) (int, error) {
+	if b.off >= len(b.data) {
+		b.off = 0
+		return 0, io.EOF
+	}
+	n := copy(p, b.data[b.off:])
+	b.off += n
+	return n, nil
+}
+
+func (b *replayBody) Close() error {
+	b.off = 0
+	return nil
+}
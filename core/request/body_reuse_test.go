@@ -0,0 +1,62 @@
+package request_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+func TestRawRequestBody_ReexposedForLaterReads(t *testing.T) {
+	body := `{"name":"alice"}`
+	c := request.NewContext(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/", strings.NewReader(body)), nil)
+
+	raw, err := c.Req.RawRequestBody()
+	if err != nil || string(raw) != body {
+		t.Fatalf("RawRequestBody = %q, %v", raw, err)
+	}
+
+	// Direct readers after it see the whole body, each time
+	for i := range 2 {
+		b, _ := io.ReadAll(c.R.Body)
+		c.R.Body.Close()
+		if string(b) != body {
+			t.Errorf("read %d: expected whole body, got %q", i, b)
+		}
+	}
+
+	var v struct {
+		Name string `json:"name"`
+	}
+	if err := c.Req.BindBody(&v); err != nil || v.Name != "alice" {
+		t.Errorf("BindBody after reads: %+v, %v", v, err)
+	}
+}
+
+func TestRawRequestBody_Cap(t *testing.T) {
+	defer request.SetMaxBufferedBody(request.GetMaxBufferedBody())
+	request.SetMaxBufferedBody(8)
+
+	handler := request.HandlerFunc(func(c *request.Context) error {
+		if _, err := c.Req.RawRequestBody(); err != nil {
+			return err
+		}
+		return c.Api.Ok(nil)
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"alice"}`)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for body over the cap, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(`{"a":1}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 for body within the cap, got %d", w.Code)
+	}
+}
@@ -0,0 +1,53 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrBodyStreamed is returned by RawRequestBody and body binding once the
+// request body was consumed through BodyReader
+var ErrBodyStreamed = errors.New("request body was streamed with BodyReader and cannot be read again")
+
+// BodyReader returns the request body for streaming, e.g. a large upload
+// copied straight to storage, without buffering it in memory:
+//
+//	r.POST("/files/{name}", func(c *request.Context) error {
+//	    f, err := storage.Create(c.Req.PathParam("name", ""))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    defer f.Close()
+//	    _, err = io.Copy(f, c.Req.BodyReader())
+//	    return err
+//	})
+//
+// The reader reads c.R.Body, so limits applied by middleware (body_limit,
+// http.MaxBytesReader) still bound it: reading past them returns their
+// error. Streaming disables re-reading: afterwards RawRequestBody and body
+// binding return ErrBodyStreamed, so use it in handlers without a bound
+// body parameter. If the body was already buffered (by middleware or an
+// earlier RawRequestBody), the buffered copy is returned instead.
+func (h *RequestHelper) BodyReader() io.Reader {
+	if h.rawRequestBody != nil {
+		return bytes.NewReader(h.rawRequestBody)
+	}
+	if h.requestBodyErr != nil {
+		return errorReader{h.requestBodyErr}
+	}
+	if h.ctx.R.Body == nil {
+		return http.NoBody
+	}
+
+	h.requestBodyErr = ErrBodyStreamed
+	return h.ctx.R.Body
+}
+
+// errorReader fails every read with err
+type errorReader struct{ err error }
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
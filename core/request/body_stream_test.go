@@ -0,0 +1,56 @@
+package request_test
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+func TestBodyReader_Streams(t *testing.T) {
+	defer request.SetMaxBufferedBody(request.GetMaxBufferedBody())
+	request.SetMaxBufferedBody(8)
+
+	// Larger than the buffering cap: streaming is not affected by it
+	body := strings.Repeat("x", 1024)
+	c := request.NewContext(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/", strings.NewReader(body)), nil)
+
+	n, err := io.Copy(io.Discard, c.Req.BodyReader())
+	if err != nil || n != int64(len(body)) {
+		t.Fatalf("streamed %d bytes, %v", n, err)
+	}
+
+	// Streaming disables re-read
+	if _, err := c.Req.RawRequestBody(); !errors.Is(err, request.ErrBodyStreamed) {
+		t.Errorf("RawRequestBody after streaming: %v", err)
+	}
+	var v map[string]any
+	if err := c.Req.BindBody(&v); !errors.Is(err, request.ErrBodyStreamed) {
+		t.Errorf("BindBody after streaming: %v", err)
+	}
+	if _, err := c.Req.BodyReader().Read(make([]byte, 1)); !errors.Is(err, request.ErrBodyStreamed) {
+		t.Errorf("second BodyReader: %v", err)
+	}
+}
+
+func TestBodyReader_AfterBuffering(t *testing.T) {
+	body := `{"name":"alice"}`
+	c := request.NewContext(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/", strings.NewReader(body)), nil)
+
+	if _, err := c.Req.RawRequestBody(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := io.ReadAll(c.Req.BodyReader())
+	if err != nil || string(b) != body {
+		t.Errorf("BodyReader after buffering = %q, %v", b, err)
+	}
+	if raw, err := c.Req.RawRequestBody(); err != nil || string(raw) != body {
+		t.Errorf("RawRequestBody still available = %q, %v", raw, err)
+	}
+}
@@ -0,0 +1,78 @@
+package request
+
+import (
+	"slices"
+	"strings"
+)
+
+// IfMatch returns the entity tags sent in the If-Match header.
+// The second return value is false when the header is absent.
+// A wildcard header returns []string{"*"}.
+//
+// Typical optimistic-concurrency update handler:
+//
+//	func (h *Handler) Update(c *request.Context, req *UpdateProductRequest) error {
+//	    product, err := h.repo.Get(req.ID)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if !c.Req.IfMatchSatisfied(product.ETag()) {
+//	        return c.PreconditionFailed()
+//	    }
+//	    ...
+//	}
+func (h *RequestHelper) IfMatch() ([]string, bool) {
+	values := h.ctx.R.Header.Values("If-Match")
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	var tags []string
+	for _, v := range values {
+		for tag := range strings.SplitSeq(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, true
+}
+
+// IfMatchSatisfied reports whether the current entity tag satisfies If-Match.
+// It returns true when the header is absent (unconditional request) or "*".
+// Comparison is strong (RFC 9110): weak tags (W/"...") never match.
+// currentETag may be given with or without quotes.
+func (h *RequestHelper) IfMatchSatisfied(currentETag string) bool {
+	tags, ok := h.IfMatch()
+	if !ok {
+		return true
+	}
+	if slices.Contains(tags, "*") {
+		return currentETag != ""
+	}
+
+	current := quoteETag(currentETag)
+	if strings.HasPrefix(current, "W/") {
+		return false
+	}
+	return slices.Contains(tags, current)
+}
+
+// PreconditionFailed sends 412 Precondition Failed (PRECONDITION_FAILED),
+// e.g. when If-Match is stale. message defaults to a generic one.
+func (c *Context) PreconditionFailed(message ...string) error {
+	msg := "Resource was modified by another request"
+	if len(message) > 0 && message[0] != "" {
+		msg = message[0]
+	}
+	return c.Api.PreconditionFailed(msg)
+}
+
+// quoteETag normalizes an entity tag to its quoted form
+func quoteETag(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || strings.HasPrefix(tag, "W/") || strings.HasPrefix(tag, `"`) {
+		return tag
+	}
+	return `"` + tag + `"`
+}
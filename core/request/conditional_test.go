@@ -0,0 +1,78 @@
+package request
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		current   string
+		satisfied bool
+	}{
+		{name: "no header", header: "", current: `"v1"`, satisfied: true},
+		{name: "matching", header: `"v1"`, current: `"v1"`, satisfied: true},
+		{name: "matching unquoted current", header: `"v1"`, current: "v1", satisfied: true},
+		{name: "matching in list", header: `"v0", "v1"`, current: "v1", satisfied: true},
+		{name: "stale", header: `"v1"`, current: `"v2"`, satisfied: false},
+		{name: "weak never matches", header: `W/"v1"`, current: `W/"v1"`, satisfied: false},
+		{name: "wildcard", header: "*", current: `"v7"`, satisfied: true},
+		{name: "wildcard missing resource", header: "*", current: "", satisfied: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/products/1", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+			ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+			if got := ctx.Req.IfMatchSatisfied(tt.current); got != tt.satisfied {
+				t.Errorf("IfMatchSatisfied(%q) with If-Match %q = %v, want %v",
+					tt.current, tt.header, got, tt.satisfied)
+			}
+		})
+	}
+}
+
+func TestIfMatch_PreconditionFailedResponse(t *testing.T) {
+	currentVersion := `"v2"`
+	update := func(c *Context) error {
+		if !c.Req.IfMatchSatisfied(currentVersion) {
+			return c.PreconditionFailed()
+		}
+		return c.Api.Ok("updated")
+	}
+
+	for _, tc := range []struct {
+		ifMatch string
+		status  int
+	}{
+		{ifMatch: `"v2"`, status: 200},
+		{ifMatch: `"v1"`, status: 412},
+	} {
+		req := httptest.NewRequest("PUT", "/products/1", nil)
+		req.Header.Set("If-Match", tc.ifMatch)
+		w := httptest.NewRecorder()
+
+		NewHandler(update).ServeHTTP(w, req)
+
+		if w.Code != tc.status {
+			t.Errorf("If-Match %s: expected %d, got %d", tc.ifMatch, tc.status, w.Code)
+		}
+		if tc.status == 412 && !strings.Contains(w.Body.String(), "PRECONDITION_FAILED") {
+			t.Errorf("Expected PRECONDITION_FAILED error code, got %s", w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("PUT", "/products/1", nil)
+	req.Header.Set("If-Match", `"v1"`)
+	tags, ok := NewContext(httptest.NewRecorder(), req, nil).Req.IfMatch()
+	if !ok || len(tags) != 1 || tags[0] != `"v1"` {
+		t.Errorf("Expected IfMatch [\"v1\"], got %v (present=%v)", tags, ok)
+	}
+}
@@ -0,0 +1,159 @@
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+)
+
+type connKey struct{}
+
+// ConnContext stores the connection in the context of its requests.
+// The signature matches http.Server.ConnContext; the built-in net/http
+// listener sets it, custom servers can do the same:
+//
+//	srv := &http.Server{Handler: app.Handler(), ConnContext: request.ConnContext}
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// ConnFromContext returns the connection stored by ConnContext
+func ConnFromContext(ctx context.Context) (net.Conn, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	conn, ok := ctx.Value(connKey{}).(net.Conn)
+	return conn, ok && conn != nil
+}
+
+// Conn returns the underlying connection of the request, for connection
+// level information (peer address, TLS state, keepalive). It is only
+// available when the server stored it (see ConnContext): false for the
+// fasthttp and HTTP/3 listeners, for httptest requests, and for
+// connections without a net.Conn.
+//
+// The connection is shared by all requests of a keep-alive client and is
+// managed by the server: do not read from, write to or close it.
+func (c *Context) Conn() (net.Conn, bool) {
+	if c.R != nil {
+		if conn, ok := ConnFromContext(c.R.Context()); ok {
+			return conn, true
+		}
+	}
+	return ConnFromContext(c.Context)
+}
+
+// TLSState returns the TLS state of the request's connection, or nil for
+// plain connections
+func (c *Context) TLSState() *tls.ConnectionState {
+	if c.R != nil && c.R.TLS != nil {
+		return c.R.TLS
+	}
+	if conn, ok := c.Conn(); ok {
+		if tc, ok := conn.(*tls.Conn); ok {
+			state := tc.ConnectionState()
+			return &state
+		}
+	}
+	return nil
+}
+
+var trustedProxies atomic.Pointer[[]*net.IPNet]
+
+// SetTrustedProxies sets the proxies (IPs or CIDRs) whose forwarding
+// headers are honored: X-Forwarded-For and X-Real-IP by Context.ClientIP,
+// X-Forwarded-Proto by the secure_headers middleware. Invalid entries are
+// logged and ignored. Call during startup.
+func SetTrustedProxies(proxies ...string) {
+	nets, err := utils.ParseIPNets(proxies)
+	if err != nil {
+		logger.LogWarning("request: ignoring trusted proxies: %v", err)
+	}
+	trustedProxies.Store(&nets)
+}
+
+// AddTrustedProxies adds proxies (IPs or CIDRs) to the list set by
+// SetTrustedProxies
+func AddTrustedProxies(proxies ...string) {
+	nets, err := utils.ParseIPNets(proxies)
+	if err != nil {
+		logger.LogWarning("request: ignoring trusted proxies: %v", err)
+	}
+	for {
+		old := trustedProxies.Load()
+		merged := nets
+		if old != nil {
+			merged = append(slices.Clone(*old), nets...)
+		}
+		if trustedProxies.CompareAndSwap(old, &merged) {
+			return
+		}
+	}
+}
+
+// IsTrustedProxy reports whether ip (with or without port) is a trusted
+// proxy (see SetTrustedProxies)
+func IsTrustedProxy(ip string) bool {
+	nets := trustedProxies.Load()
+	return nets != nil && utils.IPInNets(ip, *nets)
+}
+
+// PeerIP returns the IP of the directly connected peer (the last proxy
+// when behind one), from the connection if available, else R.RemoteAddr
+func (c *Context) PeerIP() string {
+	addr := ""
+	if conn, ok := c.Conn(); ok && conn.RemoteAddr() != nil {
+		addr = conn.RemoteAddr().String()
+	} else if c.R != nil {
+		addr = c.R.RemoteAddr
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// ClientIP returns the IP of the client. Forwarding headers are honored
+// only when the peer is a trusted proxy (see SetTrustedProxies): the
+// X-Forwarded-For chain is walked from the right, skipping trusted
+// proxies, so a client cannot spoof its address by sending the header.
+func (c *Context) ClientIP() string {
+	peer := c.PeerIP()
+	if !IsTrustedProxy(peer) || c.R == nil {
+		return peer
+	}
+
+	var hops []string
+	for _, v := range c.R.Header.Values("X-Forwarded-For") {
+		for hop := range strings.SplitSeq(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			// Malformed hop: stop at the last address a trusted proxy added
+			if i+1 < len(hops) {
+				return hops[i+1]
+			}
+			return peer
+		}
+		if !IsTrustedProxy(hops[i]) {
+			return hops[i]
+		}
+	}
+	if len(hops) > 0 {
+		return hops[0] // all hops trusted
+	}
+	if ip := strings.TrimSpace(c.R.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return peer
+}
@@ -0,0 +1,92 @@
+package request
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConn_PeerAddress(t *testing.T) {
+	srv := httptest.NewUnstartedServer(HandlerFunc(func(c *Context) error {
+		conn, ok := c.Conn()
+		if !ok {
+			return c.Api.Error(500, "NO_CONN", "connection not available")
+		}
+		return c.Resp.WithStatus(200).Text(conn.RemoteAddr().String())
+	}))
+	srv.Config.ConnContext = ConnContext
+	srv.Start()
+	defer srv.Close()
+
+	var local string
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err == nil {
+				local = conn.LocalAddr().String()
+			}
+			return conn, err
+		},
+	}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 || string(body) != local {
+		t.Errorf("Expected peer address %s, got %d %s", local, resp.StatusCode, body)
+	}
+}
+
+func TestConn_NotAvailable(t *testing.T) {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	if conn, ok := c.Conn(); ok || conn != nil {
+		t.Errorf("Expected no connection for an httptest request, got %v", conn)
+	}
+	if c.TLSState() != nil {
+		t.Error("Expected no TLS state for a plain request")
+	}
+	if ip := c.PeerIP(); ip != "192.0.2.1" {
+		t.Errorf("Expected PeerIP to fall back to RemoteAddr, got %s", ip)
+	}
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	SetTrustedProxies("10.0.0.0/8", "192.0.2.1", "not-an-ip")
+	defer SetTrustedProxies()
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		realIP string
+		want   string
+	}{
+		{"direct client ignores headers", "203.0.113.7:5000", "1.2.3.4", "", "203.0.113.7"},
+		{"trusted proxy", "192.0.2.1:5000", "198.51.100.9", "", "198.51.100.9"},
+		{"spoofed first hop", "192.0.2.1:5000", "6.6.6.6, 198.51.100.9, 10.1.2.3", "", "198.51.100.9"},
+		{"all hops trusted", "10.0.0.1:5000", "10.0.0.5, 10.0.0.6", "", "10.0.0.5"},
+		{"malformed hop", "192.0.2.1:5000", "garbage, 10.0.0.5", "", "10.0.0.5"},
+		{"x-real-ip", "192.0.2.1:5000", "", "198.51.100.10", "198.51.100.10"},
+		{"no headers", "192.0.2.1:5000", "", "", "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.remote
+		if tt.xff != "" {
+			r.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		if tt.realIP != "" {
+			r.Header.Set("X-Real-IP", tt.realIP)
+		}
+		c := NewContext(httptest.NewRecorder(), r, nil)
+		if got := c.ClientIP(); got != tt.want {
+			t.Errorf("%s: ClientIP() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package request
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/primadi/lokstra/core/response"
 	"github.com/primadi/lokstra/serviceapi"
@@ -34,7 +36,8 @@ func (e *StatusCodeError) Error() string {
 }
 
 type Context struct {
-	// Embedding standard context for easy access
+	// Embedding standard context for easy access.
+	// Derived from R.Context(), so it is cancelled when the client disconnects.
 	context.Context
 
 	// Helper to access request methods and fields
@@ -54,6 +57,36 @@ type Context struct {
 
 	value map[string]any
 
+	// Request-scoped memoized results (see Once)
+	memo onceStore
+
+	// Matched route (set by the router) and app-level error handler
+	route        *RouteInfo
+	errorHandler ErrorHandler
+
+	// Body capture for app-level body hooks (nil when none match)
+	audit *bodyAudit
+
+	// Set once WriteResponse ran; later calls are no-ops
+	responseDone bool
+
+	// Set by RespondNow; Next no longer runs handlers
+	respondedEarly bool
+
+	// Per-request JSON decoding mode (nil: package default, see SetStrictJSON)
+	strictJSON *bool
+
+	// Named spans recorded with Timing (nil until first use); timingOnce
+	// guards its creation, spans may be started from goroutines
+	timing     *Timing
+	timingOnce sync.Once
+
+	// Cancel funcs of contexts returned by Detach
+	detached []context.CancelCauseFunc
+
+	// Funcs registered with OnFinalize
+	finalizers []func()
+
 	// Transaction finalizers to be called automatically in FinalizeResponse
 	// Map of poolName -> finalizer function
 	txFinalizers map[string]func(*error)
@@ -64,8 +97,15 @@ type Context struct {
 func NewContext(w http.ResponseWriter, r *http.Request, handlers []HandlerFunc) *Context {
 	api := response.NewApiHelper()
 
+	// Use the request context so handlers (and DB calls made with ctx)
+	// are cancelled when the client disconnects
+	baseCtx := context.Background()
+	if r != nil {
+		baseCtx = r.Context()
+	}
+
 	ctx := &Context{
-		Context:  context.Background(),
+		Context:  baseCtx,
 		W:        newWriterWrapper(w),
 		R:        r,
 		handlers: handlers,
@@ -75,13 +115,15 @@ func NewContext(w http.ResponseWriter, r *http.Request, handlers []HandlerFunc)
 
 	// Initialize request helper
 	ctx.Req = newRequestHelper(ctx)
+	ctx.errorHandler = errorHandlerFromContext(baseCtx)
+	trackContext(ctx)
 
 	return ctx
 }
 
 // Call inside middleware
 func (c *Context) Next() error {
-	if c.index >= len(c.handlers) {
+	if c.respondedEarly || c.index >= len(c.handlers) {
 		return nil
 	}
 	h := c.handlers[c.index]
@@ -200,6 +242,10 @@ func (c *Context) FinalizeResponse(err error) {
 	// IMPORTANT: Always finalize transactions, even if response was manually written
 	// Use defer to ensure transactions are finalized in all code paths
 	defer func() {
+		// Stop goroutines still using detached contexts before
+		// their transactions are finalized
+		c.cancelDetached()
+
 		// Finalize all remaining transactions (in reverse order - LIFO)
 		// Skip transactions that were manually committed/rolled back
 		// Determine if transaction should commit or rollback based on:
@@ -221,11 +267,54 @@ func (c *Context) FinalizeResponse(err error) {
 				finalizer(&txErr)
 			}
 		}
+
+		// Remove temp files of multipart uploads
+		c.Req.removeUploads()
+
+		for i := len(c.finalizers) - 1; i >= 0; i-- {
+			c.finalizers[i]()
+		}
+		c.finalizers = nil
 	}()
 
+	c.WriteResponse(err)
+	c.completeRequestBody()
+	c.runBodyHooks()
+}
+
+// OnFinalize registers fn to run when the request is finalized, after the
+// response (including streamed ones) was written and transactions were
+// finalized. Funcs run in reverse registration order.
+func (c *Context) OnFinalize(fn func()) {
+	c.finalizers = append(c.finalizers, fn)
+}
+
+// Writes the pending response (mapping err to an error body) unless
+// the ResponseWriter was already written. Middleware that needs to observe
+// the final response can call this after Next(); FinalizeResponse then
+// only finalizes transactions.
+func (c *Context) WriteResponse(err error) {
+	if c.responseDone {
+		// Already handled by an earlier call (middleware materializing the response)
+		return
+	}
+	c.responseDone = true
+
 	if c.W.ManualWritten() {
 		// User already wrote directly to ResponseWriter, skip response writing
-		// but still finalize transactions (via defer above)
+		// (writing the returned value too would corrupt the response)
+		c.warnDroppedResponse(err)
+		return
+	}
+
+	if errors.Is(err, ErrClientGone) && !errors.Is(err, context.DeadlineExceeded) {
+		// Nobody is listening, skip response writing
+		// (a timed-out request may still have a client waiting for 504)
+		return
+	}
+
+	if err != nil && c.handleError(err) {
+		// Formatted by the app-level error handler
 		return
 	}
 
@@ -234,21 +323,27 @@ func (c *Context) FinalizeResponse(err error) {
 		if valErr, ok := err.(*ValidationError); ok {
 			// Use Api helper to format validation error properly
 			c.Api.ValidationError("Validation failed", valErr.FieldErrors)
-		} else {
-			// Handle other errors
+		} else if !c.writeAppError(err) && !c.writeContextError(err) {
+			// Handle other errors (errs.AppError carries its own status,
+			// context errors are mapped to 499/504)
 			st := c.Resp.RespStatusCode
-			if st == 0 || st < http.StatusBadRequest {
-				c.Api.InternalError(err.Error())
+			if pe, ok := err.(*PanicError); ok {
+				c.defaultError(http.StatusInternalServerError, "INTERNAL_ERROR",
+					fmt.Sprintf("Internal server error: %v", pe.Value))
+			} else if st == 0 || st < http.StatusBadRequest {
+				c.defaultError(http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
 				// c.Resp.WithStatus(http.StatusInternalServerError).
 				//   Json(map[string]string{"error": err.Error()})
 			}
 		}
 	}
 
-	c.Resp.WriteHttp(c.W)
+	c.Resp.WriteHttpRequest(c.W, c.R)
 }
 
-func (c *Context) executeHandler() error {
+func (c *Context) executeHandler() (err error) {
+	c.startBodyAudit()
+	defer c.recoverPanic(&err)
 	return c.Next()
 }
 
@@ -0,0 +1,141 @@
+package request
+
+import (
+	"context"
+	"strings"
+
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// Correlation holds the ids that tie logs, metrics and traces of one
+// request together. Fields not set by their middleware are "".
+type Correlation struct {
+	RequestID string `json:"request_id,omitempty"` // middleware/request_id
+	TraceID   string `json:"trace_id,omitempty"`   // tracing middleware, via SetTrace
+	SpanID    string `json:"span_id,omitempty"`    // tracing middleware, via SetTrace
+	TenantID  string `json:"tenant_id,omitempty"`  // middleware/tenant
+	UserID    string `json:"user_id,omitempty"`    // authentication, via SetPrincipal
+}
+
+// CorrelationFromContext returns the correlation ids carried by ctx.
+// It reads the values set by the middlewares, so it is always in sync with
+// RequestIDFromContext, TenantFromContext and PrincipalFromContext.
+func CorrelationFromContext(ctx context.Context) Correlation {
+	if ctx == nil {
+		return Correlation{}
+	}
+	corr := Correlation{
+		RequestID: RequestIDFromContext(ctx),
+		TenantID:  TenantFromContext(ctx),
+	}
+	if t, ok := ctx.Value(traceKey{}).(trace); ok {
+		corr.TraceID, corr.SpanID = t.traceID, t.spanID
+	}
+	if p := PrincipalFromContext(ctx); p != nil {
+		corr.UserID = p.ID
+	}
+	return corr
+}
+
+// AppendTo appends the ids that are set to a log line, e.g.
+// "GET /users - 200 request_id=0b5c8a9e tenant_id=acme". Built-in loggers
+// use it, so every log line of a request can be joined the same way.
+func (corr Correlation) AppendTo(msg string) string {
+	if s := corr.String(); s != "" {
+		return msg + " " + s
+	}
+	return msg
+}
+
+// Labels adds the ids that are fit for metric labels to labels (allocated
+// if nil) and returns it. Only tenant_id is added: request, trace, span and
+// user ids are unbounded and belong in logs and traces, not in series.
+func (corr Correlation) Labels(labels serviceapi.Labels) serviceapi.Labels {
+	if corr.TenantID == "" {
+		return labels
+	}
+	if labels == nil {
+		labels = serviceapi.Labels{}
+	}
+	labels["tenant_id"] = corr.TenantID
+	return labels
+}
+
+// Headers returns the headers that carry the correlation to a downstream
+// service: HeaderRequestID, and a W3C traceparent when the trace and span
+// ids are valid. Outbound clients (api_client) set them on every call.
+func (corr Correlation) Headers() map[string]string {
+	headers := make(map[string]string, 2)
+	if corr.RequestID != "" {
+		headers[HeaderRequestID] = corr.RequestID
+	}
+	if isHexID(corr.TraceID, 32) && isHexID(corr.SpanID, 16) {
+		headers["traceparent"] = "00-" + corr.TraceID + "-" + corr.SpanID + "-01"
+	}
+	return headers
+}
+
+func isHexID(id string, length int) bool {
+	if len(id) != length || strings.Trim(id, "0") == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Correlation returns the correlation ids of this request
+func (c *Context) Correlation() Correlation {
+	return CorrelationFromContext(c.Context)
+}
+
+// IsZero reports whether no correlation id is set
+func (corr Correlation) IsZero() bool {
+	return corr == Correlation{}
+}
+
+// String formats the ids that are set as key=value pairs for log lines,
+// e.g. "request_id=0b5c8a9e tenant_id=acme". Returns "" if none is set.
+func (corr Correlation) String() string {
+	var sb strings.Builder
+	for _, kv := range [...][2]string{
+		{"request_id", corr.RequestID},
+		{"trace_id", corr.TraceID},
+		{"span_id", corr.SpanID},
+		{"tenant_id", corr.TenantID},
+		{"user_id", corr.UserID},
+	} {
+		if kv[1] == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(kv[0])
+		sb.WriteByte('=')
+		sb.WriteString(kv[1])
+	}
+	return sb.String()
+}
+
+type traceKey struct{}
+
+type trace struct {
+	traceID string
+	spanID  string
+}
+
+// WithTrace returns a copy of ctx carrying the trace and span id
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace{traceID: traceID, spanID: spanID})
+}
+
+// SetTrace sets the trace and span id of this request.
+// Tracing middleware calls this after starting the request span, so logs
+// and metrics can be joined with the trace.
+func (c *Context) SetTrace(traceID, spanID string) {
+	c.Context = WithTrace(c.Context, traceID, spanID)
+}
@@ -0,0 +1,94 @@
+package request
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCorrelation_ZeroValues(t *testing.T) {
+	if corr := CorrelationFromContext(nil); !corr.IsZero() {
+		t.Errorf("Expected zero correlation for nil ctx, got %+v", corr)
+	}
+
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	corr := c.Correlation()
+	if !corr.IsZero() {
+		t.Errorf("Expected zero correlation without middleware, got %+v", corr)
+	}
+	if s := corr.String(); s != "" {
+		t.Errorf("Expected empty String(), got %q", s)
+	}
+
+	// A principal without id leaves UserID empty
+	c.SetPrincipal(&Principal{})
+	if corr := c.Correlation(); !corr.IsZero() {
+		t.Errorf("Expected zero correlation, got %+v", corr)
+	}
+}
+
+func TestCorrelation_FromContext(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithTenant(ctx, "acme")
+	ctx = WithTrace(ctx, "trace-1", "span-1")
+	ctx = WithPrincipal(ctx, &Principal{ID: "user-7"})
+
+	want := Correlation{
+		RequestID: "req-1",
+		TraceID:   "trace-1",
+		SpanID:    "span-1",
+		TenantID:  "acme",
+		UserID:    "user-7",
+	}
+	if got := CorrelationFromContext(ctx); got != want {
+		t.Errorf("CorrelationFromContext() = %+v, want %+v", got, want)
+	}
+
+	// Later values win, like the individual accessors
+	ctx = WithTenant(ctx, "globex")
+	if got := CorrelationFromContext(ctx).TenantID; got != "globex" {
+		t.Errorf("TenantID = %q, want globex", got)
+	}
+}
+
+func TestCorrelation_String(t *testing.T) {
+	corr := Correlation{RequestID: "req-1", UserID: "user-7"}
+	if got, want := corr.String(), "request_id=req-1 user_id=user-7"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestCorrelation_Propagation(t *testing.T) {
+	corr := Correlation{
+		RequestID: "req-1",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:    "00f067aa0ba902b7",
+		TenantID:  "acme",
+		UserID:    "user-7",
+	}
+
+	if got, want := corr.AppendTo("GET /users"), "GET /users "+corr.String(); got != want {
+		t.Errorf("AppendTo() = %q, want %q", got, want)
+	}
+	if got := (Correlation{}).AppendTo("GET /users"); got != "GET /users" {
+		t.Errorf("AppendTo() without ids = %q", got)
+	}
+
+	labels := corr.Labels(map[string]string{"route": "users"})
+	if len(labels) != 2 || labels["tenant_id"] != "acme" {
+		t.Errorf("Labels() = %v, want route and tenant_id only", labels)
+	}
+	if labels := (Correlation{RequestID: "req-1"}).Labels(nil); labels != nil {
+		t.Errorf("Labels() without tenant = %v, want nil", labels)
+	}
+
+	headers := corr.Headers()
+	if headers[HeaderRequestID] != "req-1" ||
+		headers["traceparent"] != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Headers() = %v", headers)
+	}
+	// Trace ids that are not W3C ids are not forwarded
+	if headers := (Correlation{TraceID: "trace-1", SpanID: "span-1"}).Headers(); len(headers) != 0 {
+		t.Errorf("Headers() = %v, want none", headers)
+	}
+}
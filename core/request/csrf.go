@@ -0,0 +1,30 @@
+package request
+
+import "context"
+
+type csrfTokenKey struct{}
+
+// WithCSRFToken returns a copy of ctx carrying the CSRF token
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfTokenKey{}, token)
+}
+
+// CSRFTokenFromContext returns the CSRF token carried by ctx, or "" if none
+func CSRFTokenFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	token, _ := ctx.Value(csrfTokenKey{}).(string)
+	return token
+}
+
+// CSRFToken returns the CSRF token of this request (see middleware/csrf),
+// to render in forms or an HTMX hx-headers attribute
+func (c *Context) CSRFToken() string {
+	return CSRFTokenFromContext(c.Context)
+}
+
+// SetCSRFToken sets the CSRF token of this request
+func (c *Context) SetCSRFToken(token string) {
+	c.Context = WithCSRFToken(c.Context, token)
+}
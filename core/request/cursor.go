@@ -0,0 +1,181 @@
+package request
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Cursor is a position in a keyset-paginated list: the sort-key values of
+// the item to continue after (or before, when Backward is set). Clients
+// receive it as an opaque base64 string and send it back unchanged.
+//
+// Unlike offsets, keyset pages stay fast on large tables and do not skip
+// or repeat items when rows are inserted between requests.
+//
+// Example:
+//
+//	type ListOrdersParams struct {
+//	    request.CursorRequest
+//	    Status string `query:"status"`
+//	}
+//
+//	func (h *OrderHandler) List(p *ListOrdersParams) (*response.ApiHelper, error) {
+//	    p.SetDefaults()
+//	    var createdAt time.Time
+//	    var id int64
+//	    if err := p.Cursor.Scan(&createdAt, &id); err != nil {
+//	        return nil, err
+//	    }
+//	    orders, err := h.repo.ListAfter(p.Status, createdAt, id, p.Limit+1)
+//	    ...
+//	    next := ""
+//	    if len(orders) > p.Limit {
+//	        orders = orders[:p.Limit]
+//	        last := orders[len(orders)-1]
+//	        next = request.EncodeCursor(last.CreatedAt, last.ID)
+//	    }
+//	    return response.NewApiCursorPage(orders, next, ""), nil
+//	}
+type Cursor struct {
+	// Values are the sort-key values, in ORDER BY order
+	Values []any
+
+	// Backward marks a cursor to the previous page
+	Backward bool
+}
+
+// cursorPayload is the JSON encoded in a cursor string
+type cursorPayload struct {
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// ErrInvalidCursor is returned for cursors not created by Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor returns the cursor string of the given sort-key values
+func EncodeCursor(values ...any) string {
+	return Cursor{Values: values}.Encode()
+}
+
+// DecodeCursor parses a cursor string. An empty string is the zero Cursor
+// (the first page).
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	err := c.UnmarshalText([]byte(s))
+	return c, err
+}
+
+// Encode returns the opaque cursor string, "" for the zero Cursor
+func (c Cursor) Encode() string {
+	if c.IsZero() {
+		return ""
+	}
+	values := make([]json.RawMessage, len(c.Values))
+	for i, v := range c.Values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			b = []byte("null")
+		}
+		values[i] = b
+	}
+	data, _ := json.Marshal(cursorPayload{Values: values, Backward: c.Backward})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// IsZero reports whether c holds no position, i.e. the first page
+func (c Cursor) IsZero() bool {
+	return len(c.Values) == 0
+}
+
+// Scan decodes the sort-key values into dest, in order, e.g.
+// c.Scan(&createdAt, &id). The zero Cursor leaves dest unchanged.
+func (c Cursor) Scan(dest ...any) error {
+	if c.IsZero() {
+		return nil
+	}
+	if len(dest) != len(c.Values) {
+		return fmt.Errorf("%w: expected %d values, got %d", ErrInvalidCursor, len(dest), len(c.Values))
+	}
+	for i, v := range c.Values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, dest[i]); err != nil {
+			return fmt.Errorf("%w: value %d: %v", ErrInvalidCursor, i, err)
+		}
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (c Cursor) MarshalText() ([]byte, error) {
+	return []byte(c.Encode()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so Cursor fields bind
+// from query params; malformed cursors are rejected with a field error
+func (c *Cursor) UnmarshalText(text []byte) error {
+	*c = Cursor{}
+	if len(text) == 0 {
+		return nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(string(text))
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(data, &p); err != nil || len(p.Values) == 0 {
+		return ErrInvalidCursor
+	}
+
+	c.Values = make([]any, len(p.Values))
+	for i, raw := range p.Values {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return ErrInvalidCursor
+		}
+		c.Values[i] = normalizeNumber(v)
+	}
+	c.Backward = p.Backward
+	return nil
+}
+
+// IDKind names the format in bind errors ("cursor must be a valid cursor")
+func (Cursor) IDKind() string { return "cursor" }
+
+// normalizeNumber converts JSON numbers to int64 when integral, else float64,
+// so values can be passed to queries as is
+func normalizeNumber(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// CursorRequest standardizes keyset pagination params for list APIs
+type CursorRequest struct {
+	Cursor Cursor `query:"cursor"` // empty: first page
+	Limit  int    `query:"limit"`  // default: 20, max: 100
+}
+
+// SetDefaults applies default values for CursorRequest
+func (p *CursorRequest) SetDefaults() {
+	if p.Limit <= 0 {
+		p.Limit = 20
+	}
+	if p.Limit > 100 {
+		p.Limit = 100
+	}
+}
@@ -0,0 +1,84 @@
+package request
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	s := EncodeCursor(createdAt, int64(42), "ord_7")
+
+	c, err := DecodeCursor(s)
+	if err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if len(c.Values) != 3 || c.Values[1] != int64(42) || c.Values[2] != "ord_7" || c.Backward {
+		t.Errorf("Unexpected values: %#v", c)
+	}
+
+	var gotTime time.Time
+	var gotID int64
+	var gotRef string
+	if err := c.Scan(&gotTime, &gotID, &gotRef); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !gotTime.Equal(createdAt) || gotID != 42 || gotRef != "ord_7" {
+		t.Errorf("Unexpected scan: %v %d %s", gotTime, gotID, gotRef)
+	}
+
+	back, _ := DecodeCursor(Cursor{Values: []any{1.5}, Backward: true}.Encode())
+	if !back.Backward || back.Values[0] != 1.5 {
+		t.Errorf("Unexpected backward cursor: %#v", back)
+	}
+}
+
+func TestCursor_ZeroAndInvalid(t *testing.T) {
+	c, err := DecodeCursor("")
+	if err != nil || !c.IsZero() || c.Encode() != "" {
+		t.Errorf("Expected the zero cursor for an empty string, got %#v, %v", c, err)
+	}
+	var id int64 = 7
+	if err := c.Scan(&id); err != nil || id != 7 {
+		t.Errorf("Expected Scan of the zero cursor to leave dest, got %d, %v", id, err)
+	}
+
+	for _, s := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", s, err)
+		}
+	}
+
+	c, _ = DecodeCursor(EncodeCursor(int64(1), "a"))
+	if err := c.Scan(&id); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected a value count mismatch error, got %v", err)
+	}
+}
+
+func TestCursorRequest_Bind(t *testing.T) {
+	bind := func(target string) (CursorRequest, error) {
+		c := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil), nil)
+		var req CursorRequest
+		err := c.Req.BindQuery(&req)
+		req.SetDefaults()
+		return req, err
+	}
+
+	req, err := bind("/orders?cursor=" + EncodeCursor(int64(9)) + "&limit=500")
+	if err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if req.Cursor.Values[0] != int64(9) || req.Limit != 100 {
+		t.Errorf("Unexpected binding: %#v", req)
+	}
+
+	if req, _ := bind("/orders"); !req.Cursor.IsZero() || req.Limit != 20 {
+		t.Errorf("Expected the first page, got %#v", req)
+	}
+
+	if _, err := bind("/orders?cursor=garbage!"); err == nil {
+		t.Error("Expected a malformed cursor to be rejected")
+	}
+}
@@ -0,0 +1,113 @@
+package request
+
+import (
+	"reflect"
+	"time"
+)
+
+// DefaultDateRangeDays is the window DateRange covers when from is omitted
+const DefaultDateRangeDays = 7
+
+// DateRange standardizes time range filters for list and report APIs.
+// Embed it in a handler param struct to bind the from and to query params:
+//
+//	type ListOrdersParams struct {
+//	    request.DateRange
+//	    Status string `query:"status"`
+//	}
+//
+//	// GET /orders?from=2025-01-01&to=2025-01-31
+//
+// Params use the 2006-01-02 layout; set another one with a layout tag on
+// the embedded field, e.g. request.DateRange `layout:"2006-01-02T15:04:05Z07:00"`.
+//
+// After binding, SetDefaults fills omitted params (the last 7 days by
+// default) and a from after to is reported as a validation error on from.
+// With a date-only layout, to includes its whole day: to=2025-01-31 binds
+// To to 2025-01-31 23:59:59.999999999, so `created_at <= To` (or BETWEEN)
+// keeps the rows of the 31st.
+type DateRange struct {
+	From time.Time `query:"from" layout:"2006-01-02"` // default: To - 7 days
+	To   time.Time `query:"to" layout:"2006-01-02"`   // default: now
+}
+
+// SetDefaults applies default values for DateRange
+func (r *DateRange) SetDefaults() {
+	if r.To.IsZero() {
+		r.To = time.Now()
+	}
+	if r.From.IsZero() {
+		r.From = r.To.AddDate(0, 0, -DefaultDateRangeDays)
+	}
+}
+
+// Validate returns a ValidationError if From is after To
+func (r *DateRange) Validate() error {
+	if fieldErrors := r.fieldErrors(); len(fieldErrors) > 0 {
+		return &ValidationError{FieldErrors: fieldErrors}
+	}
+	return nil
+}
+
+// Duration returns the length of the range
+func (r *DateRange) Duration() time.Duration {
+	return r.To.Sub(r.From)
+}
+
+func (r *DateRange) fieldErrors() []FieldError {
+	if r.From.After(r.To) {
+		return []FieldError{{
+			Field:   "from",
+			Code:    "INVALID_RANGE",
+			Message: "from must not be after to",
+		}}
+	}
+	return nil
+}
+
+var dateRangeType = reflect.TypeFor[DateRange]()
+
+// dateRangeMeta is a DateRange field of a bound struct
+type dateRangeMeta struct {
+	Index    []int // nil for a DateRange bound directly
+	DateOnly bool  // its layout has no time of day
+}
+
+// newDateRangeMeta describes the embedded DateRange field (nil for a
+// DateRange bound directly), whose layout tag may override the default
+func newDateRangeMeta(field *reflect.StructField) dateRangeMeta {
+	to, _ := dateRangeType.FieldByName("To")
+	if field == nil {
+		return dateRangeMeta{DateOnly: isDateOnlyLayout(parseLayoutTag(to))}
+	}
+	return dateRangeMeta{Index: field.Index, DateOnly: isDateOnlyLayout(parseLayoutTag(to, *field))}
+}
+
+// isDateOnlyLayout reports whether layout drops the time of day
+func isDateOnlyLayout(layout string) bool {
+	probe := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	parsed, err := time.Parse(layout, probe.Format(layout))
+	return err == nil && parsed.Hour() == 0 && parsed.Minute() == 0 && parsed.Second() == 0
+}
+
+// resolveDateRanges applies the defaults of the DateRange fields of a bound
+// struct, extends date-only To values to the end of their day, and returns
+// their range errors
+func resolveDateRanges(bm *bindMeta, rv reflect.Value) []FieldError {
+	var fieldErrors []FieldError
+	for _, dr := range bm.DateRanges {
+		r := rv.FieldByIndex(dr.Index).Addr().Interface().(*DateRange)
+		bound := !r.To.IsZero()
+		r.SetDefaults()
+		if dr.DateOnly && bound && isMidnight(r.To) {
+			r.To = r.To.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		fieldErrors = append(fieldErrors, r.fieldErrors()...)
+	}
+	return fieldErrors
+}
+
+func isMidnight(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h == 0 && m == 0 && s == 0 && t.Nanosecond() == 0
+}
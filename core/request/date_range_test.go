@@ -0,0 +1,163 @@
+package request
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type ordersRequest struct {
+	DateRange
+	Status string `query:"status"`
+}
+
+type eventsRequest struct {
+	DateRange `layout:"2006-01-02T15:04"`
+}
+
+func newQueryContext(target string) *Context {
+	return NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil), nil)
+}
+
+func TestDateRange_Valid(t *testing.T) {
+	var req ordersRequest
+	c := newQueryContext("/orders?from=2025-01-01&to=2025-01-31&status=paid")
+	if err := c.Req.BindAll(&req); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !req.From.Equal(want) {
+		t.Errorf("From = %v, want %v", req.From, want)
+	}
+	// A date-only to includes its whole day
+	if want := time.Date(2025, 1, 31, 23, 59, 59, 999999999, time.UTC); !req.To.Equal(want) {
+		t.Errorf("To = %v, want %v", req.To, want)
+	}
+	if req.Status != "paid" {
+		t.Errorf("Status = %q", req.Status)
+	}
+}
+
+func TestDateRange_Layout(t *testing.T) {
+	var req eventsRequest
+	c := newQueryContext("/events?from=2025-01-01T08:00&to=2025-01-01T17:30")
+	if err := c.Req.BindQuery(&req); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if got := req.Duration(); got != 9*time.Hour+30*time.Minute {
+		t.Errorf("Duration = %v", got)
+	}
+
+	// The default layout no longer applies
+	c = newQueryContext("/events?from=2025-01-01")
+	var valErr *ValidationError
+	if err := c.Req.BindAll(&req); !errors.As(err, &valErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+	if fe := valErr.FieldErrors[0]; fe.Field != "from" || fe.Code != "INVALID_QUERY" {
+		t.Errorf("Unexpected field error: %+v", fe)
+	}
+}
+
+func TestDateRange_Inverted(t *testing.T) {
+	var req ordersRequest
+	c := newQueryContext("/orders?from=2025-02-01&to=2025-01-01")
+
+	err := c.Req.BindAll(&req)
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+	if len(valErr.FieldErrors) != 1 {
+		t.Fatalf("Expected 1 field error, got %+v", valErr.FieldErrors)
+	}
+	if fe := valErr.FieldErrors[0]; fe.Field != "from" || fe.Code != "INVALID_RANGE" {
+		t.Errorf("Unexpected field error: %+v", fe)
+	}
+
+	// Same range through BindQuery
+	if err := c.Req.BindQuery(&req); !errors.As(err, &valErr) {
+		t.Errorf("Expected ValidationError from BindQuery, got %v", err)
+	}
+}
+
+func TestDateRange_Defaults(t *testing.T) {
+	week := DefaultDateRangeDays * 24 * time.Hour
+
+	var req ordersRequest
+	before := time.Now()
+	if err := newQueryContext("/orders").Req.BindAll(&req); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+	if req.To.Before(before) || req.To.After(time.Now()) {
+		t.Errorf("Expected To to default to now, got %v", req.To)
+	}
+	if got := req.Duration(); got != week {
+		t.Errorf("Expected the last %d days, got %v", DefaultDateRangeDays, got)
+	}
+
+	// Only to: from is 7 days before it
+	req = ordersRequest{}
+	if err := newQueryContext("/orders?to=2025-01-31").Req.BindAll(&req); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+	if want := time.Date(2025, 1, 24, 0, 0, 0, 0, time.UTC); !req.From.Equal(want) {
+		t.Errorf("From = %v, want %v", req.From, want)
+	}
+
+	// Only from: to is now
+	req = ordersRequest{}
+	if err := newQueryContext("/orders?from=2025-01-01").Req.BindAll(&req); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+	if req.To.Before(before) {
+		t.Errorf("Expected To to default to now, got %v", req.To)
+	}
+}
+
+func TestDateRange_Standalone(t *testing.T) {
+	var r DateRange
+	if err := newQueryContext("/?from=2025-03-01&to=2025-03-02").Req.BindQuery(&r); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if got := r.Duration(); got != 48*time.Hour-time.Nanosecond {
+		t.Errorf("Duration = %v", got)
+	}
+
+	r = DateRange{From: r.To, To: r.From}
+	if err := r.Validate(); err == nil {
+		t.Error("Expected Validate to reject an inverted range")
+	}
+}
+
+func TestDateRange_InclusiveTo(t *testing.T) {
+	lastOrder := time.Date(2025, 1, 31, 18, 30, 0, 0, time.UTC)
+
+	var req ordersRequest
+	c := newQueryContext("/orders?from=2025-01-31&to=2025-01-31")
+	if err := c.Req.BindAll(&req); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+	if lastOrder.Before(req.From) || lastOrder.After(req.To) {
+		t.Errorf("Expected %v within [%v, %v]", lastOrder, req.From, req.To)
+	}
+
+	// Binding again keeps the end of the day
+	if err := c.Req.BindQuery(&req); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if want := time.Date(2025, 1, 31, 23, 59, 59, 999999999, time.UTC); !req.To.Equal(want) {
+		t.Errorf("To = %v, want %v", req.To, want)
+	}
+
+	// A layout with a time of day keeps the exact instant
+	var events eventsRequest
+	c = newQueryContext("/events?from=2025-01-01T08:00&to=2025-01-01T17:30")
+	if err := c.Req.BindQuery(&events); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if want := time.Date(2025, 1, 1, 17, 30, 0, 0, time.UTC); !events.To.Equal(want) {
+		t.Errorf("To = %v, want %v", events.To, want)
+	}
+}
@@ -0,0 +1,37 @@
+package request
+
+import (
+	"context"
+	"time"
+)
+
+// SetTimeout gives the rest of the request a deadline of d from now (kept if
+// an earlier deadline is already set). c.Deadline() and c.R.Context() carry
+// it, so downstream calls made with the context share the remaining budget;
+// an expired deadline is answered with 504. Call the returned cancel once
+// the response is written, typically with OnFinalize from a per-route
+// middleware (cancelling when Next returns would end streamed responses,
+// which are written after the handler chain):
+//
+//	r.GET("/reports", h, func(c *request.Context) error {
+//	    c.OnFinalize(c.SetTimeout(2 * time.Second))
+//	    return c.Next()
+//	})
+func (c *Context) SetTimeout(d time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(c.Context, d)
+	c.Context = ctx
+	if c.R != nil {
+		c.R = c.R.WithContext(ctx)
+	}
+	return cancel
+}
+
+// Remaining returns the time left before the request deadline (see
+// c.Deadline), and false when the request has no deadline
+func (c *Context) Remaining() (time.Duration, bool) {
+	deadline, ok := c.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
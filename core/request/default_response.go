@@ -0,0 +1,62 @@
+package request
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+// ErrorBodyFunc builds the body of error responses the framework writes
+// itself: unmatched routes (404/405), recovered panics and unhandled errors
+// (500) and timeouts (504). The status is always set by the framework, so
+// the function only decides the shape, e.g. by r's Accept header.
+type ErrorBodyFunc func(r *http.Request, status int, code, message string) *response.Response
+
+type errorBodyKey struct{}
+
+// WithErrorBody returns a copy of ctx carrying the error body builder.
+// Used by app.SetDefaultErrorBody.
+func WithErrorBody(ctx context.Context, f ErrorBodyFunc) context.Context {
+	return context.WithValue(ctx, errorBodyKey{}, f)
+}
+
+// ErrorBodyFromContext returns the error body builder carried by ctx, if any
+func ErrorBodyFromContext(ctx context.Context) ErrorBodyFunc {
+	f, _ := ctx.Value(errorBodyKey{}).(ErrorBodyFunc)
+	return f
+}
+
+// NewDefaultErrorResponse returns the response for a framework-generated
+// error: built by f when set, else the standard API error body
+func NewDefaultErrorResponse(f ErrorBodyFunc, r *http.Request, status int, code, message string) *response.Response {
+	if f != nil {
+		if resp := f(r, status, code, message); resp != nil {
+			return resp.WithStatus(status)
+		}
+	}
+	api := response.NewApiHelper()
+	api.Error(status, code, message)
+	return api.Resp()
+}
+
+// defaultError sets the framework's error response on c.Resp
+func (c *Context) defaultError(status int, code, message string) {
+	*c.Resp = *NewDefaultErrorResponse(ErrorBodyFromContext(c), c.R, status, code, message)
+}
+
+type routeMatchKey struct{}
+
+// TrackRouteMatch returns a copy of ctx recording whether a route handler
+// served the request, and a function reporting it. A 404/405 written
+// without a match comes from the router itself.
+func TrackRouteMatch(ctx context.Context) (context.Context, func() bool) {
+	matched := new(bool)
+	return context.WithValue(ctx, routeMatchKey{}, matched), func() bool { return *matched }
+}
+
+func markRouteMatched(r *http.Request) {
+	if matched, ok := r.Context().Value(routeMatchKey{}).(*bool); ok {
+		*matched = true
+	}
+}
@@ -0,0 +1,42 @@
+package request
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRequestDone is the cancel cause of contexts returned by Detach once the
+// request has been finalized
+var ErrRequestDone = errors.New("request completed")
+
+// Detach returns a context.Context for goroutines spawned by the handler
+// (e.g. parallel service calls). It carries the current request values
+// (request ID, tenant, transactions, deadline) and is cancelled with cause
+// ErrRequestDone when the request is finalized, or earlier when the client
+// disconnects.
+//
+// *Context itself is not safe for concurrent use: its embedded context,
+// response helpers and values change while the handler runs. Pass the
+// detached context to goroutines instead of c, and read anything else they
+// need (params, bound structs) before starting them:
+//
+//	ctx := c.Detach()
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(func() error { return users.Load(ctx, id) })
+//	g.Go(func() error { return orders.Load(ctx, id) })
+//	if err := g.Wait(); err != nil {
+//	    return err
+//	}
+func (c *Context) Detach() context.Context {
+	ctx, cancel := context.WithCancelCause(c.Context)
+	c.detached = append(c.detached, cancel)
+	return ctx
+}
+
+// cancelDetached cancels all contexts returned by Detach
+func (c *Context) cancelDetached() {
+	for _, cancel := range c.detached {
+		cancel(ErrRequestDone)
+	}
+	c.detached = nil
+}
@@ -0,0 +1,81 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type detachKey struct{}
+
+func TestDetach_CancelledOnFinalize(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), detachKey{}, "req-1"))
+	c := NewContext(httptest.NewRecorder(), r, nil)
+
+	ctx := c.Detach()
+	const workers = 8
+	var (
+		wg      sync.WaitGroup
+		started sync.WaitGroup
+		results = make([]error, workers)
+	)
+	wg.Add(workers)
+	started.Add(workers)
+	for i := range workers {
+		go func() {
+			defer wg.Done()
+			if ctx.Value(detachKey{}) != "req-1" {
+				results[i] = errors.New("missing request value")
+			}
+			started.Done()
+			<-ctx.Done()
+			results[i] = context.Cause(ctx)
+		}()
+	}
+	started.Wait()
+
+	// The handler keeps using c while the goroutines run
+	c.SetContextValue("step", "done")
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Detached context cancelled too early: %v", err)
+	}
+
+	c.FinalizeResponse(nil)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Goroutines not cancelled after FinalizeResponse")
+	}
+	for i, err := range results {
+		if !errors.Is(err, ErrRequestDone) {
+			t.Errorf("Worker %d: expected ErrRequestDone, got %v", i, err)
+		}
+	}
+}
+
+func TestDetach_CancelledWithRequest(t *testing.T) {
+	base, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest("GET", "/", nil).WithContext(base)
+	c := NewContext(httptest.NewRecorder(), r, nil)
+
+	ctx := c.Detach()
+	cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Detached context not cancelled with the request")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", ctx.Err())
+	}
+}
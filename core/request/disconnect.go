@@ -0,0 +1,79 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/primadi/lokstra/common/logger"
+)
+
+// StatusClientClosedRequest is the (non-standard, nginx) status recorded when
+// the request context was cancelled before the handler finished
+const StatusClientClosedRequest = 499
+
+// Config key for the status written for context.Canceled errors
+// (default StatusClientClosedRequest; 408 is a common alternative)
+const CONFIG_CANCELED_STATUS_CODE = "canceled_status_code"
+
+// ErrClientGone is returned by CheckDisconnect when the client has disconnected.
+// Returning it from a handler skips response writing (transactions still roll back).
+var ErrClientGone = errors.New("client disconnected")
+
+// IsClientGone reports whether the client has disconnected (or the request
+// was otherwise cancelled). Long-running and streaming handlers should check
+// this between units of work / chunks and stop early.
+func (c *Context) IsClientGone() bool {
+	return c.R != nil && c.R.Context().Err() != nil
+}
+
+// CheckDisconnect returns ErrClientGone (wrapping the context cause)
+// if the client has disconnected, nil otherwise.
+//
+// Example:
+//
+//	for _, item := range items {
+//	    if err := c.CheckDisconnect(); err != nil {
+//	        return err
+//	    }
+//	    process(item)
+//	}
+func (c *Context) CheckDisconnect() error {
+	if !c.IsClientGone() {
+		return nil
+	}
+	cause := context.Cause(c.R.Context())
+	if errors.Is(cause, context.Canceled) || cause == nil {
+		return ErrClientGone
+	}
+	return errors.Join(ErrClientGone, cause)
+}
+
+// writeContextError writes the response for context.Canceled and
+// context.DeadlineExceeded errors instead of a generic 500.
+// context.Canceled only means the client went away when the request context
+// itself is done; a downstream context cancelled by the handler is a plain
+// error. Returns false if err is not handled here.
+func (c *Context) writeContextError(err error) bool {
+	var status int
+	var code, message string
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		status, code, message = http.StatusGatewayTimeout, "TIMEOUT", "Request timed out"
+	case errors.Is(err, context.Canceled) && c.IsClientGone():
+		status = StatusClientClosedRequest
+		if globalConfigResolver != nil {
+			if st, ok := globalConfigResolver(CONFIG_CANCELED_STATUS_CODE, status).(int); ok {
+				status = st
+			}
+		}
+		code, message = "REQUEST_CANCELED", "Request was canceled"
+	default:
+		return false
+	}
+
+	// Normal under disconnects/timeouts: debug log only
+	logger.LogDebug("request %s %s ended with %d: %v", c.R.Method, c.R.URL.Path, status, err)
+	c.defaultError(status, code, message)
+	return true
+}
@@ -0,0 +1,140 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientDisconnect_HandlerObservesCancellation(t *testing.T) {
+	started := make(chan struct{})
+	observed := make(chan error, 1)
+
+	handler := NewHandler(func(c *Context) error {
+		close(started)
+		for {
+			if err := c.CheckDisconnect(); err != nil {
+				// Embedded context is cancelled too
+				if c.Err() == nil {
+					observed <- errors.New("embedded context not cancelled")
+				} else {
+					observed <- err
+				}
+				return err
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	go func() {
+		<-started
+		cancel() // client hangs up
+	}()
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Fatal("Expected client request to be cancelled")
+	}
+
+	select {
+	case err := <-observed:
+		if !errors.Is(err, ErrClientGone) {
+			t.Errorf("Expected ErrClientGone, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler did not observe client disconnect")
+	}
+}
+
+func TestClientDisconnect_NotGone(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+	if ctx.IsClientGone() || ctx.CheckDisconnect() != nil {
+		t.Error("Active request should not be reported as gone")
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	ctx = NewContext(w, req.WithContext(cancelCtx), nil)
+	if !ctx.IsClientGone() {
+		t.Error("Cancelled request should be reported as gone")
+	}
+
+	// Returning ErrClientGone skips response writing
+	ctx.FinalizeResponse(ctx.CheckDisconnect())
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no response body, got %q", w.Body.String())
+	}
+}
+
+func TestContextErrors_MappedToStatus(t *testing.T) {
+	defer SetConfigResolver(globalConfigResolver)
+
+	serve := func(handler HandlerFunc) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewHandler(handler).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		return w
+	}
+	// serveCanceled serves a request whose client already hung up
+	serveCanceled := func(handler HandlerFunc) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := httptest.NewRecorder()
+		NewHandler(handler).ServeHTTP(w, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+		return w
+	}
+
+	// Downstream call cancelled with the request (e.g. DB driver returning context.Canceled)
+	cancelled := func(c *Context) error {
+		return fmt.Errorf("query users: %w", c.Err())
+	}
+	// Downstream call cancelled by the handler itself, the client is still there
+	cancelledInternally := func(c *Context) error {
+		ctx, cancel := context.WithCancel(c)
+		cancel()
+		<-ctx.Done()
+		return fmt.Errorf("query users: %w", ctx.Err())
+	}
+	// Downstream call hitting its deadline
+	timedOut := func(c *Context) error {
+		ctx, cancel := context.WithTimeout(c, time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		return fmt.Errorf("call billing: %w", ctx.Err())
+	}
+
+	SetConfigResolver(nil)
+	if w := serveCanceled(cancelled); w.Code != StatusClientClosedRequest {
+		t.Errorf("Expected 499 for cancellation, got %d", w.Code)
+	}
+	if w := serve(cancelledInternally); w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 for cancellation while the client is connected, got %d", w.Code)
+	}
+	if w := serve(timedOut); w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 for deadline exceeded, got %d", w.Code)
+	}
+
+	// Configurable status for cancellation
+	SetConfigResolver(func(key string, def any) any {
+		if key == CONFIG_CANCELED_STATUS_CODE {
+			return http.StatusRequestTimeout
+		}
+		return def
+	})
+	if w := serveCanceled(cancelled); w.Code != http.StatusRequestTimeout {
+		t.Errorf("Expected configured 408, got %d", w.Code)
+	}
+
+	// Other errors are still 500
+	if w := serve(func(*Context) error { return errors.New("boom") }); w.Code != 500 {
+		t.Errorf("Expected 500 for generic error, got %d", w.Code)
+	}
+}
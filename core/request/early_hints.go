@@ -0,0 +1,49 @@
+package request
+
+import (
+	"net/http"
+	"time"
+)
+
+// EarlyHints sends a 103 Early Hints informational response with one Link
+// header per link, so browsers start loading critical assets while the
+// page is still being rendered:
+//
+//	c.EarlyHints("</css/app.css>; rel=preload; as=style",
+//	    "</js/app.js>; rel=preload; as=script")
+//
+// The links are kept for the final response. EarlyHints is a no-op once
+// the response started, for HTTP/1.0 clients, and for writers that are not
+// server connections (e.g. httptest.ResponseRecorder).
+func (c *Context) EarlyHints(links ...string) {
+	if len(links) == 0 || c.R == nil || !c.R.ProtoAtLeast(1, 1) || c.ResponseStarted() {
+		return
+	}
+	w := connWriter(c.W.ResponseWriter)
+	if w == nil {
+		return
+	}
+
+	h := c.W.Header()
+	for _, link := range links {
+		h.Add("Link", link)
+	}
+	// Written to the connection directly: middleware wrappers only
+	// expect the final status
+	w.WriteHeader(http.StatusEarlyHints)
+}
+
+// connWriter returns the server connection writer below w (HTTP/1.1 or
+// HTTP/2, both support informational responses), or nil
+func connWriter(w http.ResponseWriter) http.ResponseWriter {
+	for {
+		if _, ok := w.(interface{ SetWriteDeadline(time.Time) error }); ok {
+			return w
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+}
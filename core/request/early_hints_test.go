@@ -0,0 +1,95 @@
+package request_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+type informational struct {
+	code int
+	link []string
+}
+
+func earlyHintsServer(http2 bool) *httptest.Server {
+	handler := request.NewHandler(func(c *request.Context) error {
+		c.EarlyHints("</app.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script")
+		return c.Resp.Html("<html>page</html>")
+	})
+	srv := httptest.NewUnstartedServer(handler)
+	if http2 {
+		srv.EnableHTTP2 = true
+		srv.StartTLS()
+	} else {
+		srv.Start()
+	}
+	return srv
+}
+
+func getWithHints(t *testing.T, srv *httptest.Server) (*http.Response, []informational) {
+	t.Helper()
+	var got []informational
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			got = append(got, informational{code, header.Values("Link")})
+			return nil
+		},
+	}
+	req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), "GET", srv.URL, nil)
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	res.Body.Close()
+	return res, got
+}
+
+func TestEarlyHints(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		http2 bool
+		proto int
+	}{
+		{"HTTP/2", true, 2},
+		{"HTTP/1.1", false, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := earlyHintsServer(tt.http2)
+			defer srv.Close()
+
+			res, got := getWithHints(t, srv)
+			if res.ProtoMajor != tt.proto {
+				t.Fatalf("Expected HTTP/%d, got %s", tt.proto, res.Proto)
+			}
+
+			// 103 arrives before the final 200
+			if len(got) != 1 || got[0].code != http.StatusEarlyHints {
+				t.Fatalf("Expected one 103 response, got %+v", got)
+			}
+			if len(got[0].link) != 2 || got[0].link[0] != "</app.css>; rel=preload; as=style" {
+				t.Errorf("Unexpected Link headers on 103: %v", got[0].link)
+			}
+			if res.StatusCode != http.StatusOK {
+				t.Errorf("Expected final 200, got %d", res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestEarlyHints_NoopWithoutConnection(t *testing.T) {
+	handler := request.NewHandler(func(c *request.Context) error {
+		c.EarlyHints("</app.css>; rel=preload; as=style")
+		return c.Resp.Html("<html>page</html>")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,109 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/response"
+)
+
+// ErrorHandler turns an unhandled handler error (or recovered panic, as
+// *PanicError) into a response. The matched route is available via c.Route().
+// Returning nil falls back to the default error mapping.
+type ErrorHandler func(c *Context, err error) *response.Response
+
+type errorHandlerKey struct{}
+
+// WithErrorHandler returns a copy of ctx carrying the error handler.
+// Used by app.SetErrorHandler; contexts created for requests carrying it
+// use h for error responses.
+func WithErrorHandler(ctx context.Context, h ErrorHandler) context.Context {
+	return context.WithValue(ctx, errorHandlerKey{}, h)
+}
+
+func errorHandlerFromContext(ctx context.Context) ErrorHandler {
+	h, _ := ctx.Value(errorHandlerKey{}).(ErrorHandler)
+	return h
+}
+
+// PanicError is the error passed on for a recovered panic
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// RouteInfo describes the route matched for a request
+type RouteInfo struct {
+	Name    string // Full route name (e.g. "api.get-user")
+	Method  string
+	Path    string   // Route pattern (e.g. "/users/{id}"), not the concrete path
+	Methods []string // All methods registered for Path (e.g. GET, PUT, DELETE)
+	Tags    []string
+}
+
+// Route returns the matched route, or nil if the context was not created by a router.
+// Available to middleware as well as handlers; use the pattern and name
+// instead of the concrete path for metric labels and cache keys.
+func (c *Context) Route() *RouteInfo {
+	return c.route
+}
+
+// RouteName returns the full name of the matched route, or "" if none
+func (c *Context) RouteName() string {
+	if c.route == nil {
+		return ""
+	}
+	return c.route.Name
+}
+
+// RoutePattern returns the pattern of the matched route (e.g. "/users/{id}"),
+// or "" if none
+func (c *Context) RoutePattern() string {
+	if c.route == nil {
+		return ""
+	}
+	return c.route.Path
+}
+
+// handleError writes the response of the app-level error handler, if any
+func (c *Context) handleError(err error) bool {
+	if c.errorHandler == nil {
+		return false
+	}
+	resp := c.errorHandler(c, err)
+	if resp == nil {
+		return false
+	}
+	resp.WriteHttpRequest(c.W, c.R)
+	return true
+}
+
+// recoverPanic converts a panic into *PanicError when an app-level error
+// handler is set, so it can format the response. Without one, panics
+// propagate as before (see middleware/recovery).
+func (c *Context) recoverPanic(err *error) {
+	if c.errorHandler == nil {
+		return
+	}
+	if r := recover(); r != nil {
+		if r == http.ErrAbortHandler {
+			panic(r)
+		}
+		stack := debug.Stack()
+		logger.LogError("[PANIC] %v\n%s", r, stack)
+		*err = &PanicError{Value: r, Stack: stack}
+	}
+}
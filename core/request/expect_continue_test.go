@@ -0,0 +1,60 @@
+package request_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+func TestBodyHooks_ExpectContinueRejectionSkipsUpload(t *testing.T) {
+	hooks := &request.BodyHooks{}
+	hookCalled := make(chan int, 1)
+	hooks.OnRequestBody(func(c *request.Context, body []byte) {
+		hookCalled <- len(body)
+	})
+
+	// Refused before the handler reads the body
+	handler := request.NewHandler(func(c *request.Context) error {
+		return c.Api.Error(http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE", "too large")
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(request.WithBodyHooks(r.Context(), hooks)))
+	}))
+	defer srv.Close()
+
+	var sent atomic.Bool
+	req, _ := http.NewRequest("POST", srv.URL, readerFunc(func(p []byte) (int, error) {
+		sent.Store(true)
+		return 0, io.EOF
+	}))
+	req.ContentLength = 1 << 20
+	req.Header.Set("Expect", "100-continue")
+
+	client := &http.Client{Transport: &http.Transport{ExpectContinueTimeout: 5 * time.Second}}
+	defer client.CloseIdleConnections()
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413, got %d", res.StatusCode)
+	}
+	// Capturing the unread body for the hook must not send 100 Continue
+	if sent.Load() {
+		t.Error("Client was asked to send the body of a rejected upload")
+	}
+	if n := <-hookCalled; n != 0 {
+		t.Errorf("Expected empty captured body, got %d bytes", n)
+	}
+}
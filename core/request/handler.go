@@ -8,12 +8,14 @@ type HandlerFunc func(c *Context) error
 
 // ServeHTTP implements http.Handler.
 func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	markRouteMatched(r)
 	c := NewContext(w, r, []HandlerFunc{h})
 	c.FinalizeResponse(c.executeHandler())
 }
 
 type Handler struct {
 	handlers []HandlerFunc
+	route    *RouteInfo
 }
 
 func NewHandler(h HandlerFunc, mw ...HandlerFunc) *Handler {
@@ -27,9 +29,19 @@ func NewHandler(h HandlerFunc, mw ...HandlerFunc) *Handler {
 	return &Handler{
 		handlers: handlers,
 	}
-} // ServeHTTP implements http.Handler.
+}
+
+// WithRoute sets the route info exposed by Context.Route()
+func (h *Handler) WithRoute(route *RouteInfo) *Handler {
+	h.route = route
+	return h
+}
+
+// ServeHTTP implements http.Handler.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	markRouteMatched(r)
 	c := NewContext(w, r, h.handlers)
+	c.route = h.route
 	c.FinalizeResponse(c.executeHandler())
 }
 
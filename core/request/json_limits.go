@@ -0,0 +1,126 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+// JSONLimits bounds the shape of JSON request bodies accepted by the
+// binders, protecting handlers from pathological (deeply nested or huge)
+// inputs. A zero value disables the corresponding limit.
+type JSONLimits struct {
+	MaxDepth    int // maximum nesting of objects/arrays
+	MaxKeys     int // maximum number of keys in a single object
+	MaxArrayLen int // maximum number of elements in a single array
+}
+
+// DefaultJSONLimits are enforced when SetJSONLimits was not called
+var DefaultJSONLimits = JSONLimits{
+	MaxDepth:    64,
+	MaxKeys:     1000,
+	MaxArrayLen: 10000,
+}
+
+var jsonLimits = DefaultJSONLimits
+
+// SetJSONLimits sets the limits enforced on JSON request bodies during
+// BindBody/BindAll. Use JSONLimits{} to disable all limits. Call during startup.
+func SetJSONLimits(limits JSONLimits) {
+	jsonLimits = limits
+}
+
+// GetJSONLimits returns the limits currently enforced on JSON request bodies
+func GetJSONLimits() JSONLimits {
+	return jsonLimits
+}
+
+// jsonContainer tracks one open object or array while scanning
+type jsonContainer struct {
+	object bool
+	count  int
+}
+
+// checkJSONLimits scans data without decoding it and returns a
+// ValidationError when a limit is exceeded. Malformed JSON is not reported
+// here; it is left to the decoder.
+func checkJSONLimits(data []byte, limits JSONLimits) error {
+	if limits == (JSONLimits{}) {
+		return nil
+	}
+
+	var stack []jsonContainer
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case ' ', '\t', '\r', '\n', ':':
+			continue
+		case '"':
+			// skip string content, honoring escapes
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		case ',':
+			if len(stack) > 0 {
+				top := &stack[len(stack)-1]
+				top.count++
+				if err := checkJSONCount(top, limits); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// The first token inside an empty container starts its first element
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.count == 0 {
+				top.count = 1
+				if err := checkJSONCount(top, limits); err != nil {
+					return err
+				}
+			}
+		}
+
+		if c == '{' || c == '[' {
+			stack = append(stack, jsonContainer{object: c == '{'})
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				return jsonLimitError("JSON_TOO_DEEP",
+					fmt.Sprintf("Request body exceeds maximum nesting depth of %d", limits.MaxDepth))
+			}
+		}
+	}
+	return nil
+}
+
+func checkJSONCount(c *jsonContainer, limits JSONLimits) error {
+	if c.object {
+		if limits.MaxKeys > 0 && c.count > limits.MaxKeys {
+			return jsonLimitError("JSON_TOO_MANY_KEYS",
+				fmt.Sprintf("Request body object exceeds maximum of %d keys", limits.MaxKeys))
+		}
+	} else if limits.MaxArrayLen > 0 && c.count > limits.MaxArrayLen {
+		return jsonLimitError("JSON_ARRAY_TOO_LONG",
+			fmt.Sprintf("Request body array exceeds maximum length of %d", limits.MaxArrayLen))
+	}
+	return nil
+}
+
+func jsonLimitError(code, message string) error {
+	return &ValidationError{
+		FieldErrors: []api_formatter.FieldError{
+			{
+				Field:   "body",
+				Code:    code,
+				Message: message,
+			},
+		},
+	}
+}
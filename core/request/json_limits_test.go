@@ -0,0 +1,103 @@
+package request
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type importRequest struct {
+	Name  string         `json:"name"`
+	Items []int          `json:"items"`
+	Attrs map[string]any `json:"attrs"`
+}
+
+func bindWithLimits(t *testing.T, limits JSONLimits, body string) error {
+	t.Helper()
+	prev := GetJSONLimits()
+	SetJSONLimits(limits)
+	defer SetJSONLimits(prev)
+
+	req := httptest.NewRequest("POST", "/import", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	var v importRequest
+	return NewContext(httptest.NewRecorder(), req, nil).Req.BindBody(&v)
+}
+
+func expectLimitCode(t *testing.T, err error, code string) {
+	t.Helper()
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T (%v)", err, err)
+	}
+	if len(valErr.FieldErrors) != 1 || valErr.FieldErrors[0].Code != code {
+		t.Errorf("Expected %s, got %v", code, valErr.FieldErrors)
+	}
+}
+
+func TestJSONLimits_DeepNesting(t *testing.T) {
+	deep := `{"name":"x","attrs":` + strings.Repeat(`{"a":`, 10) + `1` + strings.Repeat(`}`, 10) + `}`
+
+	err := bindWithLimits(t, JSONLimits{MaxDepth: 5}, deep)
+	expectLimitCode(t, err, "JSON_TOO_DEEP")
+
+	if err := bindWithLimits(t, JSONLimits{MaxDepth: 11}, deep); err != nil {
+		t.Errorf("Expected nesting within limit to bind, got %v", err)
+	}
+
+	// Unbalanced brackets are rejected before the decoder allocates anything
+	err = bindWithLimits(t, DefaultJSONLimits, strings.Repeat(`[`, 100000))
+	expectLimitCode(t, err, "JSON_TOO_DEEP")
+}
+
+func TestJSONLimits_OversizedArray(t *testing.T) {
+	items := strings.TrimSuffix(strings.Repeat(`1,`, 11), ",")
+
+	err := bindWithLimits(t, JSONLimits{MaxArrayLen: 10}, `{"items":[`+items+`]}`)
+	expectLimitCode(t, err, "JSON_ARRAY_TOO_LONG")
+
+	if err := bindWithLimits(t, JSONLimits{MaxArrayLen: 11}, `{"items":[`+items+`]}`); err != nil {
+		t.Errorf("Expected array within limit to bind, got %v", err)
+	}
+}
+
+func TestJSONLimits_TooManyKeys(t *testing.T) {
+	err := bindWithLimits(t, JSONLimits{MaxKeys: 2}, `{"name":"x","items":[],"attrs":{}}`)
+	expectLimitCode(t, err, "JSON_TOO_MANY_KEYS")
+}
+
+func TestJSONLimits_NormalPayload(t *testing.T) {
+	// Brackets and commas inside strings are not structure
+	body := `{"name":"[a, b], {c}, \"d\"","items":[1,2,3],"attrs":{"k":[{},[]],"e":{}}}`
+
+	if err := bindWithLimits(t, JSONLimits{MaxDepth: 4, MaxKeys: 3, MaxArrayLen: 3}, body); err != nil {
+		t.Errorf("Expected normal payload to bind, got %v", err)
+	}
+}
+
+func TestJSONLimits_BadRequestResponse(t *testing.T) {
+	prev := GetJSONLimits()
+	SetJSONLimits(JSONLimits{MaxArrayLen: 2})
+	defer SetJSONLimits(prev)
+
+	handler := NewHandler(func(c *Context) error {
+		var v importRequest
+		if err := c.Req.BindBody(&v); err != nil {
+			return err
+		}
+		return c.Api.Ok(v)
+	})
+
+	req := httptest.NewRequest("POST", "/import", bytes.NewBufferString(`{"items":[1,2,3]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != 400 {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "maximum length of 2") {
+		t.Errorf("Expected limit reason in body, got %s", w.Body.String())
+	}
+}
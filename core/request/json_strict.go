@@ -0,0 +1,74 @@
+package request
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+// Decoder rejecting unknown fields, used in strict JSON mode
+var strictJSONDecoder = jsoniter.Config{
+	EscapeHTML:             true,
+	SortMapKeys:            true,
+	ValidateJsonRawMessage: true,
+	DisallowUnknownFields:  true,
+}.Froze()
+
+var strictJSON bool
+
+// SetStrictJSON sets the default JSON body decoding mode: strict rejects
+// unknown fields with a validation error (catching client typos), lenient
+// (the default) ignores them (forward compatibility). Routes override it
+// with route.WithStrictJSON / route.WithLenientJSON. Call during startup.
+func SetStrictJSON(strict bool) {
+	strictJSON = strict
+}
+
+// IsStrictJSON reports the default JSON body decoding mode
+func IsStrictJSON() bool {
+	return strictJSON
+}
+
+// SetStrictJSON overrides the JSON body decoding mode for this request
+// (see the package-level SetStrictJSON). Call before binding the body.
+func (c *Context) SetStrictJSON(strict bool) {
+	c.strictJSON = &strict
+}
+
+// StrictJSON reports whether unknown fields in the JSON body of this
+// request are rejected
+func (c *Context) StrictJSON() bool {
+	if c.strictJSON != nil {
+		return *c.strictJSON
+	}
+	return strictJSON
+}
+
+// bodyDecoder returns the JSON decoder for the request body
+func (h *RequestHelper) bodyDecoder() jsoniter.API {
+	if h.ctx.StrictJSON() {
+		return strictJSONDecoder
+	}
+	return jsonDecoder
+}
+
+// unknownFieldError converts a strict decoder error into a ValidationError,
+// or returns nil if err is not about an unknown field. jsoniter has no typed
+// error for it, so its message is parsed (pinned by TestUnknownFieldError_JsoniterMessage).
+func unknownFieldError(err error) error {
+	_, rest, ok := strings.Cut(err.Error(), "found unknown field: ")
+	if !ok {
+		return nil
+	}
+	name, _, _ := strings.Cut(rest, ",")
+	return &ValidationError{
+		FieldErrors: []api_formatter.FieldError{
+			{
+				Field:   name,
+				Code:    "UNKNOWN_FIELD",
+				Message: "Unknown field '" + name + "' in request body",
+			},
+		},
+	}
+}
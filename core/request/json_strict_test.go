@@ -0,0 +1,78 @@
+package request
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// jsoniter (v1.1.12) reports unknown fields only in its error text:
+// "...: found unknown field: <name>, error found in #10 byte of ...".
+// This test fails if an upgrade changes the text unknownFieldError parses.
+func TestUnknownFieldError_JsoniterMessage(t *testing.T) {
+	var v struct {
+		Amount int `json:"amount"`
+	}
+	err := strictJSONDecoder.Unmarshal([]byte(`{"amount":1,"amonut":2}`), &v)
+	if err == nil {
+		t.Fatal("Expected the strict decoder to reject an unknown field")
+	}
+
+	var valErr *ValidationError
+	if !errors.As(unknownFieldError(err), &valErr) {
+		t.Fatalf("Expected a ValidationError from jsoniter error %q", err)
+	}
+	if f := valErr.FieldErrors[0]; f.Field != "amonut" || f.Code != "UNKNOWN_FIELD" {
+		t.Errorf("Unexpected field error %+v from jsoniter error %q", f, err)
+	}
+}
+
+func TestStrictJSON_WildcardAndForm(t *testing.T) {
+	type createResource struct {
+		Name     string         `json:"name"`
+		Metadata map[string]any `json:"*"`
+	}
+	type anyBody struct {
+		Data map[string]any `json:"*"`
+	}
+
+	bind := func(contentType, body string, v any) error {
+		req := httptest.NewRequest("POST", "/resources", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		c := NewContext(httptest.NewRecorder(), req, nil)
+		c.SetStrictJSON(true)
+		if contentType == "application/json" {
+			return c.Req.BindBody(v)
+		}
+		return c.Req.BindBodyAuto(v)
+	}
+	isUnknown := func(err error, field string) bool {
+		var valErr *ValidationError
+		return errors.As(err, &valErr) && valErr.FieldErrors[0].Code == "UNKNOWN_FIELD" &&
+			valErr.FieldErrors[0].Field == field
+	}
+
+	var res createResource
+	if err := bind("application/json", `{"name":"doc","nmae":"typo"}`, &res); !isUnknown(err, "nmae") {
+		t.Errorf("Wildcard struct: expected UNKNOWN_FIELD nmae, got %v", err)
+	}
+	res = createResource{}
+	if err := bind("application/json", `{"name":"doc"}`, &res); err != nil || res.Name != "doc" || res.Metadata["name"] != "doc" {
+		t.Errorf("Wildcard struct with known fields: got %v %+v", err, res)
+	}
+
+	// Only a wildcard: any body is expected
+	var all anyBody
+	if err := bind("application/json", `{"anything":1}`, &all); err != nil || all.Data["anything"] != 1.0 {
+		t.Errorf("Wildcard-only struct: got %v %+v", err, all)
+	}
+
+	// Form bodies bound to a struct
+	var form struct {
+		Name string `json:"name"`
+	}
+	if err := bind("application/x-www-form-urlencoded", "name=doc&nmae=typo", &form); !isUnknown(err, "nmae") {
+		t.Errorf("Form: expected UNKNOWN_FIELD nmae, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package request
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Config keys for locale resolution (used when SetLocales was not called)
+const (
+	CONFIG_LOCALE_DEFAULT   = "locale.default"   // e.g. "en"
+	CONFIG_LOCALE_SUPPORTED = "locale.supported" // e.g. [en, en-US, id]
+)
+
+// DefaultLocale is used when no default locale is configured
+const DefaultLocale = "en"
+
+var (
+	localeDefault   string
+	localeSupported []string
+)
+
+// SetLocales sets the default locale and the locales supported by the
+// application, in order of preference. Call during startup.
+func SetLocales(defaultLocale string, supported ...string) {
+	localeDefault = defaultLocale
+	localeSupported = supported
+}
+
+// LocalePreference is one language range of an Accept-Language header
+type LocalePreference struct {
+	Tag     string  // language tag, e.g. "en-US" or "*"
+	Quality float64 // q value, 0 < q <= 1
+}
+
+// ParseAcceptLanguage parses an Accept-Language header into preferences
+// ordered by quality (highest first, header order for ties).
+// Ranges with q=0 (not acceptable) and malformed q values are dropped.
+func ParseAcceptLanguage(header string) []LocalePreference {
+	var prefs []LocalePreference
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || v < 0 || v > 1 {
+				v = 0
+			}
+			q = v
+		}
+		if q == 0 {
+			continue
+		}
+		prefs = append(prefs, LocalePreference{Tag: tag, Quality: q})
+	}
+
+	slices.SortStableFunc(prefs, func(a, b LocalePreference) int {
+		switch {
+		case a.Quality > b.Quality:
+			return -1
+		case a.Quality < b.Quality:
+			return 1
+		}
+		return 0
+	})
+	return prefs
+}
+
+// MatchLocale returns the first supported locale matching prefs, falling
+// back to defaultLocale. Tags match case-insensitively, exactly first, then
+// by primary language ("en-GB" matches "en", "en" matches "en-US").
+// With no supported list, the most preferred well-formed tag is returned
+// in canonical form ("EN-us" is "en-US"); client input is never returned
+// unvalidated.
+func MatchLocale(prefs []LocalePreference, supported []string, defaultLocale string) string {
+	if len(supported) == 0 {
+		for _, p := range prefs {
+			if p.Tag == "*" {
+				continue
+			}
+			if tag, err := language.Parse(p.Tag); err == nil {
+				return tag.String()
+			}
+		}
+		return defaultLocale
+	}
+
+	for _, p := range prefs {
+		if p.Tag == "*" {
+			return defaultLocale
+		}
+		for _, s := range supported {
+			if strings.EqualFold(p.Tag, s) {
+				return s
+			}
+		}
+		lang := primaryLanguage(p.Tag)
+		for _, s := range supported {
+			if strings.EqualFold(lang, primaryLanguage(s)) {
+				return s
+			}
+		}
+	}
+	return defaultLocale
+}
+
+func primaryLanguage(tag string) string {
+	lang, _, _ := strings.Cut(tag, "-")
+	return lang
+}
+
+// resolves the configured default and supported locales
+func configuredLocales() (string, []string) {
+	def, supported := localeDefault, localeSupported
+	if def == "" && globalConfigResolver != nil {
+		def, _ = globalConfigResolver(CONFIG_LOCALE_DEFAULT, "").(string)
+	}
+	if supported == nil && globalConfigResolver != nil {
+		switch v := globalConfigResolver(CONFIG_LOCALE_SUPPORTED, nil).(type) {
+		case []string:
+			supported = v
+		case []any:
+			for _, s := range v {
+				if tag, ok := s.(string); ok {
+					supported = append(supported, tag)
+				}
+			}
+		case string:
+			for tag := range strings.SplitSeq(v, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					supported = append(supported, tag)
+				}
+			}
+		}
+	}
+	if def == "" {
+		def = DefaultLocale
+	}
+	return def, supported
+}
+
+// AcceptLanguages returns the Accept-Language preferences, ordered by quality
+func (h *RequestHelper) AcceptLanguages() []LocalePreference {
+	return ParseAcceptLanguage(h.ctx.R.Header.Get("Accept-Language"))
+}
+
+// Locale returns the locale for this request: the best match of the
+// Accept-Language header against the supported locales (see SetLocales),
+// or the default locale.
+func (c *Context) Locale() string {
+	def, supported := configuredLocales()
+	if c.R == nil {
+		return def
+	}
+	return MatchLocale(c.Req.AcceptLanguages(), supported, def)
+}
@@ -0,0 +1,84 @@
+package request
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	prefs := ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5, es;q=0, it;q=abc,, en-US ; q=0.9")
+
+	expected := []LocalePreference{
+		{Tag: "fr-CH", Quality: 1},
+		{Tag: "fr", Quality: 0.9},
+		{Tag: "en-US", Quality: 0.9},
+		{Tag: "en", Quality: 0.8},
+		{Tag: "de", Quality: 0.7},
+		{Tag: "*", Quality: 0.5},
+	}
+	if !reflect.DeepEqual(prefs, expected) {
+		t.Errorf("Expected %v, got %v", expected, prefs)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	supported := []string{"en-US", "id", "de"}
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{header: "fr-CH, fr;q=0.9, de;q=0.7, en;q=0.8", expected: "en-US"}, // en-US via primary language, before de
+		{header: "ID", expected: "id"},                                     // case-insensitive
+		{header: "id-ID;q=0.5, de-AT", expected: "de"},                     // quality order, region fallback
+		{header: "ja, *;q=0.1", expected: "en"},                            // wildcard → default
+		{header: "ja", expected: "en"},                                     // no match → default
+		{header: "", expected: "en"},
+	}
+	for _, tt := range tests {
+		got := MatchLocale(ParseAcceptLanguage(tt.header), supported, "en")
+		if got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestMatchLocale_NoSupportedList(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{header: "EN-us, id;q=0.5", expected: "en-US"},          // canonical form
+		{header: "x<script>, id;q=0.5", expected: "id"},         // malformed tags skipped
+		{header: "*, de;q=0.5", expected: "de"},                 // wildcard skipped
+		{header: "not a tag, %0d%0aSet-Cookie", expected: "en"}, // nothing valid → default
+	}
+	for _, tt := range tests {
+		got := MatchLocale(ParseAcceptLanguage(tt.header), nil, "en")
+		if got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestContext_Locale(t *testing.T) {
+	SetLocales("id", "id", "en-GB")
+	defer SetLocales("")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "fr;q=0.9, en-US;q=0.8")
+	c := NewContext(httptest.NewRecorder(), req, nil)
+
+	if got := c.Locale(); got != "en-GB" {
+		t.Errorf("Expected en-GB, got %q", got)
+	}
+	if prefs := c.Req.AcceptLanguages(); len(prefs) != 2 || prefs[0].Tag != "fr" {
+		t.Errorf("Expected ordered preferences, got %v", prefs)
+	}
+
+	c = NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	if got := c.Locale(); got != "id" {
+		t.Errorf("Expected default id, got %q", got)
+	}
+}
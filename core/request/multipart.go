@@ -0,0 +1,291 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+
+	"github.com/primadi/lokstra/common/logger"
+)
+
+// MultipartLimits controls how uploaded files of multipart/form-data
+// requests are buffered
+type MultipartLimits struct {
+	// MaxMemory is the number of bytes of file content kept in memory per
+	// request. The file crossing it, and every file after it, is written
+	// to a temp file instead.
+	MaxMemory int64
+
+	// TempDir is the directory for temp files ("" = os.TempDir()).
+	// Temp files are removed when the request ends, even on handler error.
+	TempDir string
+
+	// MaxValueBytes caps the total size of the non-file fields of a
+	// request, which are always held in memory (0 = default 10MB)
+	MaxValueBytes int64
+
+	// MaxParts caps the number of parts of a request (0 = default 1000)
+	MaxParts int
+}
+
+// DefaultMultipartLimits are used when SetMultipartLimits was not called
+var DefaultMultipartLimits = MultipartLimits{
+	MaxMemory:     32 << 20, // 32 MB
+	MaxValueBytes: 10 << 20, // 10 MB, as multipart.Reader.ReadForm
+	MaxParts:      1000,     // as multipart.Reader.ReadForm
+}
+
+var multipartLimits = DefaultMultipartLimits
+
+// SetMultipartLimits sets how multipart uploads are buffered. Call during startup.
+func SetMultipartLimits(limits MultipartLimits) {
+	multipartLimits = limits
+}
+
+// GetMultipartLimits returns how multipart uploads are buffered
+func GetMultipartLimits() MultipartLimits {
+	return multipartLimits
+}
+
+// ErrNotMultipart is returned by MultipartForm for requests whose content
+// type is not multipart/form-data
+var ErrNotMultipart = errors.New("request content type is not multipart/form-data")
+
+// MultipartForm is a parsed multipart/form-data request body
+type MultipartForm struct {
+	Values url.Values
+	Files  map[string][]*FileUpload
+}
+
+// FileUpload is an uploaded file, held in memory or in a temp file
+// depending on MultipartLimits. Read it with Open or move it with SaveTo;
+// either way the temp file is removed when the request ends.
+type FileUpload struct {
+	Field    string // form field name
+	Filename string // file name sent by the client, do not use as a path as is
+	Header   textproto.MIMEHeader
+	Size     int64
+
+	data  []byte // content, when held in memory
+	path  string // temp file, when spilled to disk
+	moved bool   // path was moved by SaveTo and is no longer temporary
+}
+
+// ContentType returns the content type sent by the client
+func (f *FileUpload) ContentType() string {
+	return f.Header.Get("Content-Type")
+}
+
+// InMemory reports whether the content is held in memory rather than in
+// a temp file
+func (f *FileUpload) InMemory() bool {
+	return f.path == ""
+}
+
+// Open returns a reader over the content. Close it when done.
+func (f *FileUpload) Open() (io.ReadSeekCloser, error) {
+	if f.InMemory() {
+		return nopSeekCloser{bytes.NewReader(f.data)}, nil
+	}
+	return os.Open(f.path)
+}
+
+// SaveTo writes the content to path. A temp file is moved when possible
+// instead of copied.
+func (f *FileUpload) SaveTo(path string) error {
+	if f.InMemory() {
+		return os.WriteFile(path, f.data, 0o644)
+	}
+	if !f.moved && os.Rename(f.path, path) == nil {
+		f.path = path
+		f.moved = true
+		return nil
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+type nopSeekCloser struct{ *bytes.Reader }
+
+func (nopSeekCloser) Close() error { return nil }
+
+// MultipartForm parses the multipart/form-data body once, keeping files in
+// memory up to MultipartLimits.MaxMemory and spilling the rest to temp
+// files. A body over MaxValueBytes or MaxParts fails with
+// multipart.ErrMessageTooLarge. Like BodyReader, it streams the body:
+// afterwards RawRequestBody and body binding return ErrBodyStreamed.
+func (h *RequestHelper) MultipartForm() (*MultipartForm, error) {
+	if h.multipartForm != nil || h.multipartErr != nil {
+		return h.multipartForm, h.multipartErr
+	}
+
+	mediaType, params, err := mime.ParseMediaType(h.ctx.R.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		h.multipartErr = ErrNotMultipart
+		return nil, h.multipartErr
+	}
+
+	h.multipartForm = &MultipartForm{
+		Values: url.Values{},
+		Files:  map[string][]*FileUpload{},
+	}
+	if err := h.readMultipart(multipart.NewReader(h.BodyReader(), params["boundary"])); err != nil {
+		h.removeUploads()
+		h.multipartForm, h.multipartErr = nil, err
+	}
+	return h.multipartForm, h.multipartErr
+}
+
+// FormFile returns the first file uploaded in the named field, or
+// http.ErrMissingFile
+func (h *RequestHelper) FormFile(name string) (*FileUpload, error) {
+	files, err := h.FormFiles(name)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+// FormFiles returns the files uploaded in the named field, or
+// http.ErrMissingFile
+func (h *RequestHelper) FormFiles(name string) ([]*FileUpload, error) {
+	form, err := h.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if len(form.Files[name]) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	return form.Files[name], nil
+}
+
+func (h *RequestHelper) readMultipart(mr *multipart.Reader) error {
+	limits := multipartLimits
+	memory := limits.MaxMemory
+	valueBytes := limits.MaxValueBytes
+	if valueBytes <= 0 {
+		valueBytes = DefaultMultipartLimits.MaxValueBytes
+	}
+	parts := limits.MaxParts
+	if parts <= 0 {
+		parts = DefaultMultipartLimits.MaxParts
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if parts--; parts < 0 {
+			part.Close()
+			return multipart.ErrMessageTooLarge
+		}
+
+		name := part.FormName()
+		if name == "" {
+			part.Close()
+			continue
+		}
+
+		var buf bytes.Buffer
+		if part.FileName() == "" {
+			// Non-file fields are always held in memory, within MaxValueBytes
+			n, err := io.CopyN(&buf, part, valueBytes+1)
+			if err != nil && err != io.EOF {
+				part.Close()
+				return err
+			}
+			if valueBytes -= n; valueBytes < 0 {
+				part.Close()
+				return multipart.ErrMessageTooLarge
+			}
+			h.multipartForm.Values.Add(name, buf.String())
+			part.Close()
+			continue
+		}
+
+		f := &FileUpload{
+			Field:    name,
+			Filename: part.FileName(),
+			Header:   part.Header,
+		}
+		// Read one byte past the remaining memory to detect spillover
+		n, err := io.CopyN(&buf, part, max(memory, 0)+1)
+		if err != nil && err != io.EOF {
+			part.Close()
+			return err
+		}
+		if n <= memory {
+			f.data = buf.Bytes()
+			f.Size = n
+			memory -= n
+		} else {
+			// Register the temp file before writing, so it is removed even
+			// if writing fails
+			h.multipartForm.Files[name] = append(h.multipartForm.Files[name], f)
+			err := f.spill(limits.TempDir, io.MultiReader(&buf, part))
+			part.Close()
+			if err != nil {
+				return err
+			}
+			memory = 0
+			continue
+		}
+		part.Close()
+		h.multipartForm.Files[name] = append(h.multipartForm.Files[name], f)
+	}
+}
+
+// spill writes the content to a new temp file in dir
+func (f *FileUpload) spill(dir string, r io.Reader) error {
+	tmp, err := os.CreateTemp(dir, "lokstra-upload-*")
+	if err != nil {
+		return err
+	}
+	f.path = tmp.Name()
+	f.Size, err = io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// removeUploads removes the temp files of the request, called when the
+// request ends
+func (h *RequestHelper) removeUploads() {
+	if h.multipartForm == nil {
+		return
+	}
+	for _, files := range h.multipartForm.Files {
+		for _, f := range files {
+			if f.path == "" || f.moved {
+				continue
+			}
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				logger.LogWarn("multipart: failed to remove temp file %s: %v", f.path, err)
+			}
+		}
+	}
+}
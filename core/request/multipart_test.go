@@ -0,0 +1,218 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newUploadContext builds a multipart request with one text field and the
+// given files (field name → content)
+func newUploadContext(t *testing.T, files map[string]string) *Context {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "report")
+	for field, content := range files {
+		fw, err := mw.CreateFormFile(field, field+".bin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	mw.Close()
+
+	r := httptest.NewRequest("POST", "/upload", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return NewContext(httptest.NewRecorder(), r, nil)
+}
+
+func withMultipartLimits(t *testing.T, limits MultipartLimits) {
+	t.Helper()
+	prev := GetMultipartLimits()
+	SetMultipartLimits(limits)
+	t.Cleanup(func() { SetMultipartLimits(prev) })
+}
+
+func readUpload(t *testing.T, f *FileUpload) string {
+	t.Helper()
+	r, err := f.Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+	data, _ := io.ReadAll(r)
+	return string(data)
+}
+
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestMultipart_InMemory(t *testing.T) {
+	dir := t.TempDir()
+	withMultipartLimits(t, MultipartLimits{MaxMemory: 1024, TempDir: dir})
+
+	c := newUploadContext(t, map[string]string{"avatar": "small"})
+	f, err := c.Req.FormFile("avatar")
+	if err != nil {
+		t.Fatalf("FormFile failed: %v", err)
+	}
+
+	if !f.InMemory() || f.Size != 5 || f.Filename != "avatar.bin" {
+		t.Errorf("Unexpected upload: %+v", f)
+	}
+	if got := readUpload(t, f); got != "small" {
+		t.Errorf("content = %q", got)
+	}
+	if got := c.Req.FormParam("title", ""); got != "report" {
+		t.Errorf("FormParam = %q", got)
+	}
+	if files := tempFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected no temp files, got %v", files)
+	}
+}
+
+func TestMultipart_SpillsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	withMultipartLimits(t, MultipartLimits{MaxMemory: 1024, TempDir: dir})
+
+	large := strings.Repeat("x", 4096)
+	c := newUploadContext(t, map[string]string{"archive": large})
+	f, err := c.Req.FormFile("archive")
+	if err != nil {
+		t.Fatalf("FormFile failed: %v", err)
+	}
+
+	if f.InMemory() {
+		t.Fatal("Expected upload above MaxMemory to spill to disk")
+	}
+	if f.Size != int64(len(large)) {
+		t.Errorf("Size = %d, want %d", f.Size, len(large))
+	}
+	if files := tempFiles(t, dir); len(files) != 1 {
+		t.Fatalf("Expected 1 temp file in TempDir, got %v", files)
+	}
+	if got := readUpload(t, f); got != large {
+		t.Errorf("Unexpected content of %d bytes", len(got))
+	}
+
+	// Cleaned up when the request ends, even on handler error
+	c.FinalizeResponse(errors.New("handler failed"))
+	if files := tempFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected temp files removed, got %v", files)
+	}
+}
+
+func TestMultipart_MemoryIsPerRequest(t *testing.T) {
+	dir := t.TempDir()
+	withMultipartLimits(t, MultipartLimits{MaxMemory: 1024, TempDir: dir})
+
+	c := newUploadContext(t, map[string]string{
+		"a": strings.Repeat("a", 800),
+		"b": strings.Repeat("b", 800),
+	})
+	form, err := c.Req.MultipartForm()
+	if err != nil {
+		t.Fatalf("MultipartForm failed: %v", err)
+	}
+
+	// The two files fit the threshold alone but not together
+	inMemory := form.Files["a"][0].InMemory()
+	if inMemory == form.Files["b"][0].InMemory() {
+		t.Errorf("Expected exactly one file spilled to disk")
+	}
+	c.FinalizeResponse(nil)
+	if files := tempFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected temp files removed, got %v", files)
+	}
+}
+
+func TestMultipart_SaveTo(t *testing.T) {
+	dir := t.TempDir()
+	withMultipartLimits(t, MultipartLimits{MaxMemory: 16, TempDir: dir})
+
+	content := strings.Repeat("z", 64)
+	c := newUploadContext(t, map[string]string{"doc": content})
+	f, err := c.Req.FormFile("doc")
+	if err != nil {
+		t.Fatalf("FormFile failed: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "saved.bin")
+	if err := f.SaveTo(dst); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	c.FinalizeResponse(nil)
+
+	// The saved file survives the request
+	data, err := os.ReadFile(dst)
+	if err != nil || string(data) != content {
+		t.Errorf("Saved file: %q, %v", data, err)
+	}
+	if files := tempFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected no temp files left, got %v", files)
+	}
+}
+
+func TestMultipart_Errors(t *testing.T) {
+	c := newUploadContext(t, nil)
+	if _, err := c.Req.FormFile("missing"); !errors.Is(err, http.ErrMissingFile) {
+		t.Errorf("Expected ErrMissingFile, got %v", err)
+	}
+
+	c = NewContext(httptest.NewRecorder(),
+		httptest.NewRequest("POST", "/upload", strings.NewReader(`{}`)), nil)
+	c.R.Header.Set("Content-Type", "application/json")
+	if _, err := c.Req.MultipartForm(); !errors.Is(err, ErrNotMultipart) {
+		t.Errorf("Expected ErrNotMultipart, got %v", err)
+	}
+}
+
+func TestMultipart_ValueAndPartLimits(t *testing.T) {
+	newForm := func(fields int, size int) *Context {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for range fields {
+			mw.WriteField("note", strings.Repeat("x", size))
+		}
+		mw.Close()
+		r := httptest.NewRequest("POST", "/upload", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		return NewContext(httptest.NewRecorder(), r, nil)
+	}
+
+	withMultipartLimits(t, MultipartLimits{MaxMemory: 1 << 20, MaxValueBytes: 100, MaxParts: 5})
+
+	if _, err := newForm(2, 50).Req.MultipartForm(); err != nil {
+		t.Errorf("Expected fields within the limits to parse, got %v", err)
+	}
+	// One huge field
+	if _, err := newForm(1, 1<<20).Req.MultipartForm(); !errors.Is(err, multipart.ErrMessageTooLarge) {
+		t.Errorf("Expected ErrMessageTooLarge for oversized field, got %v", err)
+	}
+	// Many small fields adding up
+	if _, err := newForm(3, 40).Req.MultipartForm(); !errors.Is(err, multipart.ErrMessageTooLarge) {
+		t.Errorf("Expected ErrMessageTooLarge for total field size, got %v", err)
+	}
+	// Too many parts
+	if _, err := newForm(6, 1).Req.MultipartForm(); !errors.Is(err, multipart.ErrMessageTooLarge) {
+		t.Errorf("Expected ErrMessageTooLarge for too many parts, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package request
+
+import (
+	"context"
+	"sync"
+)
+
+// onceStore memoizes results for the lifetime of one request
+type onceStore struct {
+	mu      sync.Mutex
+	entries map[string]*onceEntry
+}
+
+type onceEntry struct {
+	once  sync.Once
+	value any
+	err   error
+}
+
+func (s *onceStore) entry(key string) *onceEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]*onceEntry)
+	}
+	e, ok := s.entries[key]
+	if !ok {
+		e = &onceEntry{}
+		s.entries[key] = e
+	}
+	return e
+}
+
+// Once runs fn at most once per key for the duration of this request and
+// returns the memoized result (including the error) on subsequent calls.
+// Safe for concurrent use by goroutines spawned by the request; concurrent
+// callers with the same key wait for the first call to finish.
+//
+// This is request-scoped memoization, not a cross-request cache.
+//
+// Example:
+//
+//	user, err := c.Once("user:"+id, func() (any, error) {
+//	    return userService.ValidateUser(c, id)
+//	})
+func (c *Context) Once(key string, fn func() (any, error)) (any, error) {
+	e := c.memo.entry(key)
+	e.once.Do(func() {
+		e.value, e.err = fn()
+	})
+	return e.value, e.err
+}
+
+// Once memoizes fn per key within the request carried by ctx.
+// Services receiving the request context can use this instead of c.Once;
+// if ctx is not a request context, fn is simply called.
+func Once(ctx context.Context, key string, fn func() (any, error)) (any, error) {
+	if c, ok := ctx.(*Context); ok {
+		return c.Once(key, fn)
+	}
+	return fn()
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnce_RunsOncePerRequest(t *testing.T) {
+	var calls atomic.Int32
+	validateUser := func(ctx context.Context) (any, error) {
+		return Once(ctx, "validate-user", func() (any, error) {
+			calls.Add(1)
+			return "user-1", nil
+		})
+	}
+
+	handler := NewHandler(func(c *Context) error {
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				if v, _ := validateUser(c); v != "user-1" {
+					t.Errorf("Expected memoized user-1, got %v", v)
+				}
+			})
+		}
+		wg.Wait()
+
+		if v, _ := c.Once("validate-user", nil); v != "user-1" {
+			t.Errorf("Expected memoized user-1, got %v", v)
+		}
+		return c.Resp.Text("ok")
+	})
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected function to run once within a request, ran %d times", n)
+	}
+
+	// A new request does not share results
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected function to run again for a new request, ran %d times", n)
+	}
+}
@@ -0,0 +1,49 @@
+package request
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal is the authenticated subject of a request, set by an
+// authentication middleware (JWT, session, API key, ...)
+type Principal struct {
+	// ID of the user or client
+	ID string
+	// Scopes, roles or permissions granted to the principal
+	Scopes []string
+	// Claims holds additional attributes (e.g. JWT claims)
+	Claims map[string]any
+}
+
+// HasScope reports whether the principal was granted scope
+func (p *Principal) HasScope(scope string) bool {
+	return p != nil && slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, or nil if none
+func PrincipalFromContext(ctx context.Context) *Principal {
+	if ctx == nil {
+		return nil
+	}
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// Principal returns the authenticated principal of this request, or nil
+func (c *Context) Principal() *Principal {
+	return PrincipalFromContext(c.Context)
+}
+
+// SetPrincipal sets the authenticated principal of this request.
+// Authentication middleware calls this before c.Next().
+func (c *Context) SetPrincipal(p *Principal) {
+	c.Context = WithPrincipal(c.Context, p)
+}
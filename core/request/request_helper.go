@@ -1,11 +1,12 @@
 package request
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -18,11 +19,18 @@ var (
 	jsonDecoder = jsoniter.ConfigCompatibleWithStandardLibrary
 )
 
-func unmarshalBody(data []byte, v any) error {
-	err := jsonDecoder.Unmarshal(data, v)
+func decodeBody(decoder jsoniter.API, data []byte, v any) error {
+	err := decoder.Unmarshal(data, v)
 	if err == nil {
 		return nil
 	}
+	if unknownErr := unknownFieldError(err); unknownErr != nil {
+		return unknownErr
+	}
+
+	if fields := idFieldErrors(data, v); len(fields) > 0 {
+		return &ValidationError{FieldErrors: fields}
+	}
 
 	// Create a more user-friendly error message for JSON parsing errors
 	errMsg := err.Error()
@@ -56,6 +64,10 @@ type RequestHelper struct {
 	// Request body caching
 	rawRequestBody []byte
 	requestBodyErr error
+
+	// Parsed multipart body, see MultipartForm
+	multipartForm *MultipartForm
+	multipartErr  error
 }
 
 func newRequestHelper(ctx *Context) *RequestHelper {
@@ -71,9 +83,19 @@ func (h *RequestHelper) QueryParam(name string, defaultValue string) string {
 	return v
 }
 
-// FormParam retrieves a form parameter by name, returning defaultValue if not present
+// FormParam retrieves a form parameter by name, returning defaultValue if not present.
+// Multipart bodies are parsed with MultipartForm, so uploads honor
+// MultipartLimits.
 func (h *RequestHelper) FormParam(name string, defaultValue string) string {
-	v := h.ctx.R.FormValue(name)
+	var v string
+	if form, err := h.MultipartForm(); err == nil {
+		v = form.Values.Get(name)
+		if v == "" {
+			v = h.ctx.R.URL.Query().Get(name)
+		}
+	} else {
+		v = h.ctx.R.FormValue(name)
+	}
 	if v == "" {
 		return defaultValue
 	}
@@ -107,6 +129,9 @@ func (h *RequestHelper) QueryParams(name string) []string {
 
 // FormParams retrieves all form parameter values by name
 func (h *RequestHelper) FormParams(name string) []string {
+	if form, err := h.MultipartForm(); err == nil {
+		return slices.Concat(form.Values[name], h.ctx.R.URL.Query()[name])
+	}
 	if err := h.ctx.R.ParseForm(); err != nil {
 		return nil
 	}
@@ -128,7 +153,10 @@ func (h *RequestHelper) AllHeaders() map[string][]string {
 	return h.ctx.R.Header
 }
 
-// RawRequestBody returns the cached request body
+// RawRequestBody returns the request body. The first call reads and
+// buffers it (up to SetMaxBufferedBody) and re-exposes it as c.R.Body, so
+// every later reader (other middleware, binding, handlers reading c.R.Body)
+// sees the whole body, regardless of middleware order.
 func (h *RequestHelper) RawRequestBody() ([]byte, error) {
 	h.cacheRequestBody()
 	return h.rawRequestBody, h.requestBodyErr
@@ -144,12 +172,13 @@ func (h *RequestHelper) cacheRequestBody() {
 		return
 	}
 
-	body, err := io.ReadAll(h.ctx.R.Body)
+	body, err := readBufferedBody(h.ctx.R.Body, maxBufferedBody)
 	if err != nil {
 		h.requestBodyErr = err
-	} else {
-		h.rawRequestBody = body
+		return
 	}
+	h.rawRequestBody = body
+	h.ctx.R.Body = &replayBody{data: body}
 }
 
 // Helper methods for binding fields (moved from Context)
@@ -157,8 +186,7 @@ func (h *RequestHelper) cacheRequestBody() {
 func (h *RequestHelper) bindPathField(fieldMeta bindFieldMeta, rv reflect.Value) error {
 	rawValue := h.PathParam(fieldMeta.Name, "")
 	rawValues := []string{rawValue}
-	return convertAndSetField(rv.FieldByIndex(fieldMeta.Index), rawValues,
-		fieldMeta.IsSlice, fieldMeta.IsUnmarshalJSON)
+	return setFieldValues(rv.FieldByIndex(fieldMeta.Index), fieldMeta, rawValues)
 }
 
 func (h *RequestHelper) bindQueryField(fieldMeta bindFieldMeta, rv reflect.Value, query url.Values) error {
@@ -193,6 +221,9 @@ func (h *RequestHelper) bindQueryField(fieldMeta bindFieldMeta, rv reflect.Value
 
 	// Normal slice
 	values := query[fieldMeta.Name]
+	if len(values) == 0 {
+		values = h.queryAliasValues(fieldMeta, query)
+	}
 	var rawValues []string
 
 	if fieldMeta.IsSlice {
@@ -211,7 +242,7 @@ func (h *RequestHelper) bindQueryField(fieldMeta bindFieldMeta, rv reflect.Value
 		}
 	}
 
-	return convertAndSetField(field, rawValues, fieldMeta.IsSlice, fieldMeta.IsUnmarshalJSON)
+	return setFieldValues(field, fieldMeta, rawValues)
 }
 
 func (h *RequestHelper) bindHeaderField(fieldMeta bindFieldMeta, rv reflect.Value, header http.Header) error {
@@ -225,8 +256,24 @@ func (h *RequestHelper) bindHeaderField(fieldMeta bindFieldMeta, rv reflect.Valu
 		rawValues = []string{values[0]}
 	}
 
-	return convertAndSetField(rv.FieldByIndex(fieldMeta.Index), rawValues,
-		fieldMeta.IsSlice, fieldMeta.IsUnmarshalJSON)
+	return setFieldValues(rv.FieldByIndex(fieldMeta.Index), fieldMeta, rawValues)
+}
+
+func (h *RequestHelper) bindCookieField(fieldMeta bindFieldMeta, rv reflect.Value) error {
+	cookies := h.ctx.R.CookiesNamed(fieldMeta.Name)
+	if len(cookies) == 0 && !fieldMeta.IsSlice {
+		return nil
+	}
+
+	rawValues := make([]string, len(cookies))
+	for i, c := range cookies {
+		rawValues[i] = c.Value
+	}
+	if !fieldMeta.IsSlice && len(rawValues) > 0 {
+		rawValues = rawValues[:1]
+	}
+
+	return setFieldValues(rv.FieldByIndex(fieldMeta.Index), fieldMeta, rawValues)
 }
 
 // bindFormURLEncoded binds URL-encoded form data to struct
@@ -276,8 +323,8 @@ func (h *RequestHelper) bindFormURLEncoded(v any) error {
 			if err != nil {
 				return err
 			}
-			// Unmarshal into v
-			return unmarshalBody(b, v)
+			// Unmarshal into v, rejecting unknown fields in strict mode
+			return decodeBody(h.bodyDecoder(), b, v)
 		}
 	}
 
@@ -357,7 +404,7 @@ func (h *RequestHelper) BindQuery(v any) error {
 	}
 
 	// Validate after binding
-	return h.validateStruct(v)
+	return h.validateBound(bm, rv, v)
 }
 
 // BindHeader binds header values to struct
@@ -412,14 +459,54 @@ func (h *RequestHelper) BindHeader(v any) error {
 	return h.validateStruct(v)
 }
 
+// BindCookie binds cookie values to struct fields tagged cookie:"name".
+// A missing cookie leaves the zero value, so use validate:"required" for
+// cookies the handler needs:
+//
+//	type SessionParams struct {
+//	    SessionID string `cookie:"session_id" validate:"required"`
+//	}
+func (h *RequestHelper) BindCookie(v any) error {
+	bm := getOrBuildBindMeta(reflect.TypeOf(v))
+	rv := reflect.ValueOf(v).Elem()
+
+	for _, fieldMeta := range bm.Fields {
+		if fieldMeta.Tag != "cookie" {
+			continue
+		}
+
+		if err := h.bindCookieField(fieldMeta, rv); err != nil {
+			return err
+		}
+	}
+
+	// Validate after binding
+	return h.validateStruct(v)
+}
+
 // BindBody binds request body to struct
 func (h *RequestHelper) BindBody(v any) error {
+	bound, err := h.bindBody(v)
+	if err != nil || !bound {
+		return err
+	}
+
+	// Validate after binding
+	return h.validateStruct(v)
+}
+
+// bindBody binds request body to v without validation.
+// Returns false if there is no body to bind.
+func (h *RequestHelper) bindBody(v any) (bool, error) {
 	h.cacheRequestBody()
 	if h.requestBodyErr != nil {
-		return h.requestBodyErr
+		return false, h.requestBodyErr
 	}
 	if len(h.rawRequestBody) == 0 {
-		return nil // No body to bind
+		return false, nil // No body to bind
+	}
+	if err := checkJSONLimits(h.rawRequestBody, jsonLimits); err != nil {
+		return false, err
 	}
 
 	// Check if v is a struct with wildcard fields
@@ -428,13 +515,19 @@ func (h *RequestHelper) BindBody(v any) error {
 		bm := getOrBuildBindMeta(t)
 
 		// Check if any field has wildcard binding
-		hasWildcard := false
+		hasWildcard, hasNamed := false, false
 		var wildcardField *bindFieldMeta
 		for i := range bm.Fields {
-			if bm.Fields[i].IsWildcard && bm.Fields[i].Tag == "json" {
-				hasWildcard = true
-				wildcardField = &bm.Fields[i]
-				break
+			if bm.Fields[i].Tag != "json" {
+				continue
+			}
+			if bm.Fields[i].IsWildcard {
+				if !hasWildcard {
+					hasWildcard = true
+					wildcardField = &bm.Fields[i]
+				}
+			} else {
+				hasNamed = true
 			}
 		}
 
@@ -448,7 +541,7 @@ func (h *RequestHelper) BindBody(v any) error {
 				// Unmarshal body directly into the map
 				mapPtr := reflect.New(mapField.Type())
 				if err := jsonDecoder.Unmarshal(h.rawRequestBody, mapPtr.Interface()); err != nil {
-					return &ValidationError{
+					return false, &ValidationError{
 						FieldErrors: []api_formatter.FieldError{
 							{
 								Field:   wildcardField.Field.Name,
@@ -462,26 +555,20 @@ func (h *RequestHelper) BindBody(v any) error {
 				mapField.Set(mapPtr.Elem())
 			}
 
-			// Also bind other json/body fields normally
-			if err := unmarshalBody(h.rawRequestBody, v); err != nil {
-				return err
+			// Also bind other json/body fields normally. In strict mode keys
+			// must be named fields; a struct with only a wildcard takes any body.
+			if !hasNamed {
+				return true, nil
 			}
-
-			// Validate after binding
-			return h.validateStruct(v)
+			return true, decodeBody(h.bodyDecoder(), h.rawRequestBody, v)
 		}
 	}
 
 	// Normal struct binding (no wildcard)
-	if err := unmarshalBody(h.rawRequestBody, v); err != nil {
-		return err
-	}
-
-	// Validate after binding
-	return h.validateStruct(v)
+	return true, decodeBody(h.bodyDecoder(), h.rawRequestBody, v)
 }
 
-// binds all request data (path, query, header, body) to struct
+// binds all request data (path, query, header, cookie, body) to struct
 func (h *RequestHelper) BindAll(v any) error {
 	// If v is pointer to map[string]any, perform map-merge binding
 	t := reflect.TypeOf(v)
@@ -534,43 +621,53 @@ func (h *RequestHelper) BindAll(v any) error {
 		}
 	}
 
-	// Default: struct-based binding (existing behavior)
+	// Default: struct-based binding.
+	// Errors are accumulated so the client sees everything wrong at once;
+	// only an unreadable or malformed body stops binding early.
 	bm := getOrBuildBindMeta(reflect.TypeOf(v))
 	rv := reflect.ValueOf(v).Elem()
 	header := h.ctx.R.Header
 	query := h.ctx.R.URL.Query()
 
+	var errs bindErrors
 	for _, fieldMeta := range bm.Fields {
-		// Skip wildcard fields - they will be handled by BindBody
+		// Skip wildcard fields - they will be handled by bindBody
 		if fieldMeta.IsWildcard {
 			continue
 		}
 
+		var err error
 		switch fieldMeta.Tag {
 		case "query":
-			if err := h.bindQueryField(fieldMeta, rv, query); err != nil {
-				return err
-			}
+			err = h.bindQueryField(fieldMeta, rv, query)
 		case "header":
-			if err := h.bindHeaderField(fieldMeta, rv, header); err != nil {
-				return err
-			}
+			err = h.bindHeaderField(fieldMeta, rv, header)
+		case "cookie":
+			err = h.bindCookieField(fieldMeta, rv)
 		case "path":
-			if err := h.bindPathField(fieldMeta, rv); err != nil {
-				return err
-			}
-		// Skip json fields - they will be handled by BindBody
+			err = h.bindPathField(fieldMeta, rv)
+		// Skip json fields - they will be handled by bindBody
 		case "json":
 			continue
 		}
+		if err != nil {
+			errs.addField(fieldMeta, err)
+		}
 	}
 
-	if err := h.BindBody(v); err != nil {
-		return err
+	if _, err := h.bindBody(v); err != nil {
+		if !errs.addBody(err, h.rawRequestBody) {
+			return err
+		}
 	}
 
 	// Validate after binding
-	return h.validateStruct(v)
+	if err := h.validateBound(bm, rv, v); err != nil {
+		if !errs.addValidation(err) {
+			return err
+		}
+	}
+	return errs.err()
 }
 
 // binds request body with auto content-type detection
@@ -591,7 +688,10 @@ func (h *RequestHelper) BindBodyAuto(v any) error {
 	}
 
 	// Default to JSON binding
-	return unmarshalBody(h.rawRequestBody, v)
+	if err := checkJSONLimits(h.rawRequestBody, jsonLimits); err != nil {
+		return err
+	}
+	return decodeBody(h.bodyDecoder(), h.rawRequestBody, v)
 }
 
 // binds all request data with auto content-type detection
@@ -663,6 +763,10 @@ func (h *RequestHelper) BindAllAuto(v any) error {
 			if err := h.bindHeaderField(fieldMeta, rv, header); err != nil {
 				return err
 			}
+		case "cookie":
+			if err := h.bindCookieField(fieldMeta, rv); err != nil {
+				return err
+			}
 		default: //case "path":
 			if err := h.bindPathField(fieldMeta, rv); err != nil {
 				return err
@@ -675,7 +779,7 @@ func (h *RequestHelper) BindAllAuto(v any) error {
 	}
 
 	// Validate after binding
-	return h.validateStruct(v)
+	return h.validateBound(bindMeta, rv, v)
 }
 
 // validateStruct validates a struct using validator.ValidateStruct
@@ -697,6 +801,26 @@ func (h *RequestHelper) validateStruct(v any) error {
 	return nil
 }
 
+// validateBound resolves the DateRange fields of a bound struct (defaults,
+// from <= to), then validates it. Range errors are reported with the
+// field errors.
+func (h *RequestHelper) validateBound(bm *bindMeta, rv reflect.Value, v any) error {
+	rangeErrors := resolveDateRanges(bm, rv)
+	err := h.validateStruct(v)
+	if len(rangeErrors) == 0 {
+		return err
+	}
+
+	if err == nil {
+		return &ValidationError{FieldErrors: rangeErrors}
+	}
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		valErr.FieldErrors = append(valErr.FieldErrors, rangeErrors...)
+	}
+	return err
+}
+
 // ValidationError represents validation errors from struct validation
 type ValidationError struct {
 	FieldErrors []api_formatter.FieldError
@@ -0,0 +1,35 @@
+package request
+
+import "context"
+
+// HeaderRequestID carries the request id in requests and responses
+const HeaderRequestID = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id carried by ctx, or "" if none.
+// Services receiving the request context can use it to correlate logs and
+// outgoing calls.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID returns the id assigned to this request (see middleware/request_id)
+func (c *Context) RequestID() string {
+	return RequestIDFromContext(c.Context)
+}
+
+// SetRequestID sets the id of this request.
+// The id is carried by the embedded context, like the tenant id.
+func (c *Context) SetRequestID(id string) {
+	c.Context = WithRequestID(c.Context, id)
+}
@@ -0,0 +1,27 @@
+package request
+
+// RespondNow ends the handler chain with the response set on c.Resp (or
+// written to c.W) as a success: handlers after the calling middleware do not
+// run, later Next calls are no-ops and the response is written as usual.
+// It returns nil, so middleware can `return c.RespondNow()`; unlike returning
+// an error, outer middleware and the error handler see a normal response.
+//
+// Example (cache hit):
+//
+//	func(c *request.Context) error {
+//	    if body, ok := cache.Get(c.R.URL.Path); ok {
+//	        c.Resp.WithStatus(http.StatusOK).Json(body)
+//	        return c.RespondNow()
+//	    }
+//	    return c.Next()
+//	}
+func (c *Context) RespondNow() error {
+	c.respondedEarly = true
+	return nil
+}
+
+// RespondedEarly reports whether a middleware ended the chain with
+// RespondNow, e.g. for outer middleware that must not store a cache hit again
+func (c *Context) RespondedEarly() bool {
+	return c.respondedEarly
+}
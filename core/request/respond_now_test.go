@@ -0,0 +1,92 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRespondNow_SkipsHandler(t *testing.T) {
+	handlerRan := false
+	cache := func(c *Context) error {
+		c.Resp.WithStatus(http.StatusOK).Json(map[string]string{"source": "cache"})
+		return c.RespondNow()
+	}
+	handler := func(c *Context) error {
+		handlerRan = true
+		return c.Api.Ok("fresh")
+	}
+
+	w := httptest.NewRecorder()
+	c := NewContext(w, httptest.NewRequest("GET", "/items", nil), []HandlerFunc{cache, handler})
+	err := c.executeHandler()
+	c.FinalizeResponse(err)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if handlerRan {
+		t.Error("Handler should not run after RespondNow")
+	}
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"cache"`) {
+		t.Errorf("Expected cached 200 response, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRespondNow_NotModified(t *testing.T) {
+	handlerRan := false
+	conditional := func(c *Context) error {
+		if c.R.Header.Get("If-None-Match") == `"v1"` {
+			c.Resp.WithStatus(http.StatusNotModified)
+			return c.RespondNow()
+		}
+		return c.Next()
+	}
+	handler := func(c *Context) error {
+		handlerRan = true
+		return c.Api.Ok("body")
+	}
+
+	r := httptest.NewRequest("GET", "/items", nil)
+	r.Header.Set("If-None-Match", `"v1"`)
+	w := httptest.NewRecorder()
+	c := NewContext(w, r, []HandlerFunc{conditional, handler})
+	err := c.executeHandler()
+	c.FinalizeResponse(err)
+
+	if handlerRan {
+		t.Error("Handler should not run after RespondNow")
+	}
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected empty 304, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRespondNow_VisibleToOuterMiddleware(t *testing.T) {
+	var sawEarly bool
+	outer := func(c *Context) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		sawEarly = c.RespondedEarly()
+		// Calling Next again must not resume the chain
+		return c.Next()
+	}
+	inner := func(c *Context) error {
+		return c.RespondNow()
+	}
+	handler := func(c *Context) error {
+		t.Error("Handler should not run after RespondNow")
+		return nil
+	}
+
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil),
+		[]HandlerFunc{outer, inner, handler})
+	if err := c.executeHandler(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !sawEarly {
+		t.Error("Outer middleware should see RespondedEarly")
+	}
+}
@@ -0,0 +1,45 @@
+package request
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/primadi/lokstra/common/logger"
+)
+
+// routes already reported for returning a value after a direct write
+var warnedDroppedResponses sync.Map
+
+// ResponseStarted reports whether the status or body was already written,
+// e.g. directly to c.W. Values and errors returned afterwards are not
+// written (and are logged), so the client gets a single coherent response.
+func (c *Context) ResponseStarted() bool {
+	return c.W.ManualWritten()
+}
+
+// warnDroppedResponse logs what the handler returned after the response
+// had started: errors every time, values once per route.
+func (c *Context) warnDroppedResponse(err error) {
+	// Keyed by route pattern, not the request path, to keep the set bounded
+	route := c.R.Method
+	if c.route != nil {
+		route = c.route.Name
+		if route == "" {
+			route = c.R.Method + " " + c.route.Path
+		}
+	}
+
+	if err != nil {
+		if !errors.Is(err, ErrClientGone) {
+			logger.LogWarn("⚠️  %s %s: response already started, dropping returned error: %v",
+				c.R.Method, c.R.URL.Path, err)
+		}
+		return
+	}
+	if !c.Resp.HasOutput() {
+		return
+	}
+	if _, warned := warnedDroppedResponses.LoadOrStore(route, true); !warned {
+		logger.LogWarn("⚠️  %s: response already started by a direct write, dropping returned value", route)
+	}
+}
@@ -0,0 +1,57 @@
+package request
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type responseTraceKey struct{}
+
+// ResponseTrace exposes the request id and matched route of a request to
+// code wrapping the router (see app.WithStandardResponseHeaders), which
+// runs before the request context exists.
+type ResponseTrace struct {
+	start time.Time
+	base  context.Context
+	ctx   atomic.Pointer[Context]
+}
+
+// TrackResponse returns a copy of ctx recording the context created for
+// the request, and the trace reading it. The request id carried by ctx is
+// used until a middleware sets another one.
+func TrackResponse(ctx context.Context) (context.Context, *ResponseTrace) {
+	t := &ResponseTrace{start: time.Now()}
+	t.base = context.WithValue(ctx, responseTraceKey{}, t)
+	return t.base, t
+}
+
+// trackContext records c as the context of the traced request, if any
+func trackContext(c *Context) {
+	if t, ok := c.Context.Value(responseTraceKey{}).(*ResponseTrace); ok {
+		t.ctx.Store(c)
+	}
+}
+
+// RequestID returns the current id of the request
+func (t *ResponseTrace) RequestID() string {
+	if c := t.ctx.Load(); c != nil {
+		if id := c.RequestID(); id != "" {
+			return id
+		}
+	}
+	return RequestIDFromContext(t.base)
+}
+
+// RouteName returns the full name of the matched route, or "" if none
+func (t *ResponseTrace) RouteName() string {
+	if c := t.ctx.Load(); c != nil {
+		return c.RouteName()
+	}
+	return ""
+}
+
+// Elapsed returns the time since the request was tracked
+func (t *ResponseTrace) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
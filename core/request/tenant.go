@@ -0,0 +1,33 @@
+package request
+
+import "context"
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant id
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant id carried by ctx, or "" if none.
+// Repositories receiving the request context (or one derived from it)
+// can use this to operate within the current tenant.
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Tenant returns the tenant id resolved for this request (see middleware/tenant)
+func (c *Context) Tenant() string {
+	return TenantFromContext(c.Context)
+}
+
+// SetTenant sets the tenant id for this request.
+// The id is carried by the embedded context, so it reaches services
+// and repositories that are passed the request context.
+func (c *Context) SetTenant(tenant string) {
+	c.Context = WithTenant(c.Context, tenant)
+}
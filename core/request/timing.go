@@ -0,0 +1,110 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimingSpan is a named phase of request handling
+type TimingSpan struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Timing records named spans of a request for latency debugging. Spans are
+// emitted as a Server-Timing header and/or logged by the server_timing
+// middleware; without it they are only recorded.
+type Timing struct {
+	mu    sync.Mutex
+	start time.Time
+	last  time.Time
+	spans []TimingSpan
+}
+
+// Timing returns the timing recorder of the request, created on first use
+// (the server_timing middleware creates it when the request starts).
+// Safe for concurrent use.
+//
+// Example:
+//
+//	users, err := repo.FindAll(c)
+//	c.Timing().Mark("db") // time since request start (or the previous mark)
+//
+//	err = c.Timing().Measure("cache", func() error {
+//	    return cache.Set(c, "users", users)
+//	})
+func (c *Context) Timing() *Timing {
+	c.timingOnce.Do(func() {
+		now := time.Now()
+		c.timing = &Timing{start: now, last: now}
+	})
+	return c.timing
+}
+
+// Mark records a span named name, from the previous mark (or the start of
+// the request) until now
+func (t *Timing) Mark(name string) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, TimingSpan{Name: name, Duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// Measure runs fn and records its duration as a span named name.
+// The error of fn is returned.
+func (t *Timing) Measure(name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, TimingSpan{Name: name, Duration: now.Sub(start)})
+	t.last = now
+	return err
+}
+
+// Spans returns a copy of the recorded spans, in recording order
+func (t *Timing) Spans() []TimingSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TimingSpan(nil), t.spans...)
+}
+
+// Total returns the time since the start of the request
+func (t *Timing) Total() time.Duration {
+	return time.Since(t.start)
+}
+
+// ServerTiming formats the spans (and a final "total" span) as a
+// Server-Timing header value, e.g. `db;dur=12.4, cache;dur=0.8, total;dur=15.1`
+func (t *Timing) ServerTiming() string {
+	spans := append(t.Spans(), TimingSpan{Name: "total", Duration: t.Total()})
+	parts := make([]string, len(spans))
+	for i, s := range spans {
+		parts[i] = fmt.Sprintf("%s;dur=%.1f", timingToken(s.Name), float64(s.Duration.Microseconds())/1000)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String formats the spans for logs, e.g. "db=12.4ms cache=812µs total=15.1ms"
+func (t *Timing) String() string {
+	spans := append(t.Spans(), TimingSpan{Name: "total", Duration: t.Total()})
+	parts := make([]string, len(spans))
+	for i, s := range spans {
+		parts[i] = s.Name + "=" + s.Duration.Round(time.Microsecond).String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// timingToken replaces characters not allowed in a Server-Timing metric name
+func timingToken(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r > ' ' && r < 0x7f && !strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
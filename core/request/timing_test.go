@@ -0,0 +1,25 @@
+package request_test
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+func TestTiming_ConcurrentFirstUse(t *testing.T) {
+	c := request.NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			c.Timing().Mark("worker")
+		})
+	}
+	wg.Wait()
+
+	if spans := c.Timing().Spans(); len(spans) != 8 {
+		t.Errorf("Expected 8 spans on one recorder, got %d", len(spans))
+	}
+}
@@ -0,0 +1,10 @@
+package request
+
+// Trailer returns the value of a request trailer, or "" if the client did
+// not send it. Trailers arrive after the body, so they are only known once
+// the body was read to the end: Trailer buffers the body if no handler or
+// binder read it yet. After BodyReader, read the body to EOF first.
+func (h *RequestHelper) Trailer(name string) string {
+	h.cacheRequestBody()
+	return h.ctx.R.Trailer.Get(name)
+}
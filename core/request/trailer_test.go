@@ -0,0 +1,81 @@
+package request
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// chunkedWithTrailer is a request body of unknown length, so the client
+// sends it chunked, that sets the trailer once the body is read
+type chunkedWithTrailer struct {
+	io.Reader
+	req *http.Request
+}
+
+func (b *chunkedWithTrailer) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.req.Trailer.Set("X-Checksum", "abc123")
+	}
+	return n, err
+}
+
+func sendWithTrailer(t *testing.T, handler func(c *Context) string) string {
+	t.Helper()
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = handler(NewContext(w, r, nil))
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest("POST", srv.URL, nil)
+	req.Body = io.NopCloser(&chunkedWithTrailer{Reader: strings.NewReader("payload"), req: req})
+	req.Trailer = http.Header{"X-Checksum": nil}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return got
+}
+
+func TestTrailer_UnreadBody(t *testing.T) {
+	got := sendWithTrailer(t, func(c *Context) string {
+		// Trailer reads the body first
+		return c.Req.Trailer("X-Checksum")
+	})
+	if got != "abc123" {
+		t.Errorf("Trailer = %q, want abc123", got)
+	}
+}
+
+func TestTrailer_AfterBodyRead(t *testing.T) {
+	got := sendWithTrailer(t, func(c *Context) string {
+		body, _ := c.Req.RawRequestBody()
+		return string(body) + ":" + c.Req.Trailer("x-checksum")
+	})
+	if got != "payload:abc123" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestTrailer_StreamedBody(t *testing.T) {
+	got := sendWithTrailer(t, func(c *Context) string {
+		before := c.R.Trailer.Get("X-Checksum")
+		io.Copy(io.Discard, c.Req.BodyReader())
+		return before + ":" + c.Req.Trailer("X-Checksum")
+	})
+	if got != ":abc123" {
+		t.Errorf("got %q, want the trailer only after the body was read", got)
+	}
+}
+
+func TestTrailer_Missing(t *testing.T) {
+	c := NewContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader("x")), nil)
+	if got := c.Req.Trailer("X-Checksum"); got != "" {
+		t.Errorf("Trailer = %q, want empty", got)
+	}
+}
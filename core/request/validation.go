@@ -0,0 +1,65 @@
+package request
+
+import (
+	"reflect"
+
+	"github.com/primadi/lokstra/common/validator"
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+// FieldError is a validation error attached to a field
+type FieldError = api_formatter.FieldError
+
+// ValidatorFunc validates a single field value for a custom `validate` tag.
+// Return an error (its message becomes the field error) if the value is invalid.
+type ValidatorFunc = validator.ValidatorFunc
+
+// StructValidatorFunc validates a whole struct after field validation.
+// It receives a pointer to the struct.
+type StructValidatorFunc = validator.StructValidatorFunc
+
+// RegisterValidator registers a custom validation tag, enforced by
+// smart binding and by manual Bind* calls.
+//
+// Example:
+//
+//	request.RegisterValidator("notblank", func(field string, v reflect.Value, _ string) error {
+//	    if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" {
+//	        return fmt.Errorf("%s must not be blank", field)
+//	    }
+//	    return nil
+//	})
+//
+//	type CreateTagRequest struct {
+//	    Name string `json:"name" validate:"notblank"`
+//	}
+func RegisterValidator(tag string, fn ValidatorFunc) {
+	validator.RegisterValidator(tag, fn)
+}
+
+// RegisterStructValidator registers a struct-level validator for the type of sample
+// (a value or pointer of the struct type), run after field validation.
+func RegisterStructValidator(sample any, fn StructValidatorFunc) {
+	validator.RegisterStructValidator(reflect.TypeOf(sample), fn)
+}
+
+// RegisterStructRule registers a cross-field rule for struct type T, run after
+// field validation. Returned errors are added to the ValidationError; a rule
+// error is dropped for a field that already failed field validation.
+// A rule may attach the same problem to several fields.
+//
+// Example:
+//
+//	request.RegisterStructRule(func(r *SearchProductsRequest) []request.FieldError {
+//	    if r.MinPrice > 0 && r.MaxPrice > 0 && r.MaxPrice < r.MinPrice {
+//	        return []request.FieldError{{
+//	            Field: "max_price", Code: "RANGE", Message: "max_price must be >= min_price",
+//	        }}
+//	    }
+//	    return nil
+//	})
+func RegisterStructRule[T any](rule func(v *T) []FieldError) {
+	validator.RegisterStructValidator(reflect.TypeFor[T](), func(v any) []FieldError {
+		return rule(v.(*T))
+	})
+}
@@ -0,0 +1,137 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response/api_formatter"
+)
+
+func init() {
+	RegisterValidator("notblank", func(field string, v reflect.Value, _ string) error {
+		if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" {
+			return fmt.Errorf("%s must not be blank", field)
+		}
+		return nil
+	})
+}
+
+type createTagRequest struct {
+	Name  string `json:"name" validate:"notblank"`
+	Color string `query:"color" validate:"notblank"`
+}
+
+func TestRegisterValidator_EnforcedOnBind(t *testing.T) {
+	req := httptest.NewRequest("POST", "/tags?color=%20", bytes.NewBufferString(`{"name":"   "}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+	var body createTagRequest
+	err := ctx.Req.BindBody(&body)
+	valErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError from BindBody, got %T (%v)", err, err)
+	}
+	if valErr.FieldErrors[0].Field != "name" || valErr.FieldErrors[0].Message != "name must not be blank" {
+		t.Errorf("Unexpected field errors: %v", valErr.FieldErrors)
+	}
+
+	var all createTagRequest
+	req = httptest.NewRequest("POST", "/tags?color=red", bytes.NewBufferString(`{"name":"urgent"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx = NewContext(httptest.NewRecorder(), req, nil)
+	if err := ctx.Req.BindAll(&all); err != nil {
+		t.Errorf("Expected valid request to bind, got %v", err)
+	}
+}
+
+type signupRequest struct {
+	Password string `json:"password"`
+	Confirm  string `json:"confirm"`
+}
+
+func TestRegisterStructValidator(t *testing.T) {
+	RegisterStructValidator(signupRequest{}, func(v any) []api_formatter.FieldError {
+		s := v.(*signupRequest)
+		if s.Password != s.Confirm {
+			return []api_formatter.FieldError{{Field: "confirm", Message: "confirm must match password"}}
+		}
+		return nil
+	})
+
+	for _, tc := range []struct {
+		body  string
+		valid bool
+	}{
+		{body: `{"password":"a","confirm":"a"}`, valid: true},
+		{body: `{"password":"a","confirm":"b"}`, valid: false},
+	} {
+		req := httptest.NewRequest("POST", "/signup", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+		var s signupRequest
+		err := ctx.Req.BindBody(&s)
+		if tc.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.body, err)
+		}
+		if !tc.valid {
+			valErr, ok := err.(*ValidationError)
+			if !ok || valErr.FieldErrors[0].Field != "confirm" {
+				t.Errorf("%s: expected confirm field error, got %v", tc.body, err)
+			}
+		}
+	}
+}
+
+type priceRangeRequest struct {
+	MinPrice float64 `query:"min_price"`
+	MaxPrice float64 `query:"max_price" validate:"gte=0"`
+}
+
+func TestRegisterStructRule_CrossField(t *testing.T) {
+	RegisterStructRule(func(r *priceRangeRequest) []FieldError {
+		if r.MaxPrice != 0 && r.MaxPrice < r.MinPrice {
+			return []FieldError{{Field: "MaxPrice", Code: "RANGE", Message: "MaxPrice must be >= MinPrice"}}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{query: "min_price=10&max_price=20"},
+		{query: "min_price=10"},
+		{query: "min_price=30&max_price=20", message: "MaxPrice must be >= MinPrice"},
+		// Field error wins over the cross-field rule for the same field
+		{query: "min_price=30&max_price=-1", message: "MaxPrice must be greater than or equal to 0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/products?"+tt.query, nil)
+			ctx := NewContext(httptest.NewRecorder(), req, nil)
+
+			var r priceRangeRequest
+			err := ctx.Req.BindQuery(&r)
+			if tt.message == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			valErr, ok := err.(*ValidationError)
+			if !ok || len(valErr.FieldErrors) != 1 {
+				t.Fatalf("Expected one field error, got %v", err)
+			}
+			if fe := valErr.FieldErrors[0]; fe.Field != "MaxPrice" || fe.Message != tt.message {
+				t.Errorf("Unexpected field error: %+v", fe)
+			}
+		})
+	}
+}
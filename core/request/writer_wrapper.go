@@ -1,6 +1,8 @@
 package request
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 )
 
@@ -10,6 +12,7 @@ type writerWrapper struct {
 	statusCode  int
 	wroteHeader bool
 	wroteBody   bool
+	written     int64
 }
 
 func newWriterWrapper(w http.ResponseWriter) *writerWrapper {
@@ -21,6 +24,11 @@ func (lw *writerWrapper) WriteHeader(code int) {
 		// status code already written → ignore subsequent calls
 		return
 	}
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		// Informational (e.g. 103 Early Hints), the final status follows
+		lw.ResponseWriter.WriteHeader(code)
+		return
+	}
 	lw.statusCode = code
 	lw.wroteHeader = true
 	lw.ResponseWriter.WriteHeader(code)
@@ -32,7 +40,9 @@ func (lw *writerWrapper) Write(b []byte) (int, error) {
 		lw.WriteHeader(http.StatusOK)
 	}
 	lw.wroteBody = true
-	return lw.ResponseWriter.Write(b)
+	n, err := lw.ResponseWriter.Write(b)
+	lw.written += int64(n)
+	return n, err
 }
 
 // Check if user wrote manually
@@ -44,3 +54,31 @@ func (lw *writerWrapper) ManualWritten() bool {
 func (lw *writerWrapper) StatusCode() int {
 	return lw.statusCode
 }
+
+// BytesWritten returns the number of body bytes written
+func (lw *writerWrapper) BytesWritten() int64 {
+	return lw.written
+}
+
+// Flush sends buffered data to the client (http.Flusher)
+func (lw *writerWrapper) Flush() {
+	if !lw.wroteHeader {
+		lw.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(lw.ResponseWriter).Flush()
+}
+
+// Hijack takes over the connection, e.g. for WebSocket upgrades (http.Hijacker).
+// The response counts as written, so nothing is sent after the handler returns.
+func (lw *writerWrapper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(lw.ResponseWriter).Hijack()
+	if err == nil {
+		lw.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController
+func (lw *writerWrapper) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
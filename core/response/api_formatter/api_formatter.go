@@ -80,6 +80,19 @@ func (f *ApiResponseFormatter) List(data any, meta *ListMeta) any {
 	return resp
 }
 
+func (f *ApiResponseFormatter) CursorList(data any, meta *CursorMeta) any {
+	resp := &ApiResponse{
+		Status: "success",
+		Data:   data,
+	}
+	if meta != nil {
+		resp.Meta = &Meta{
+			CursorMeta: meta,
+		}
+	}
+	return resp
+}
+
 func (f *ApiResponseFormatter) ParseClientResponse(resp *http.Response, cr *ClientResponse) error {
 	defer resp.Body.Close()
 
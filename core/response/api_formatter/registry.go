@@ -26,6 +26,25 @@ type ResponseFormatter interface {
 	ParseClientResponse(resp *http.Response, cr *ClientResponse) error
 }
 
+// CursorListFormatter is implemented by formatters with their own shape
+// for cursor-paginated lists (see FormatCursorList)
+type CursorListFormatter interface {
+	// Formats cursor-paginated list response
+	CursorList(data any, meta *CursorMeta) any
+}
+
+// FormatCursorList formats a cursor page with f, or as {"data", "meta"}
+// when f does not implement CursorListFormatter
+func FormatCursorList(f ResponseFormatter, data any, meta *CursorMeta) any {
+	if cf, ok := f.(CursorListFormatter); ok {
+		return cf.CursorList(data, meta)
+	}
+	return map[string]any{
+		"data": data,
+		"meta": meta,
+	}
+}
+
 // Registry for response formatters
 var formatterRegistry = make(map[string]func() ResponseFormatter)
 
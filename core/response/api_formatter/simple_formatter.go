@@ -62,6 +62,13 @@ func (f *SimpleResponseFormatter) List(data any, meta *ListMeta) any {
 	return data
 }
 
+func (f *SimpleResponseFormatter) CursorList(data any, meta *CursorMeta) any {
+	return map[string]any{
+		"data": data,
+		"meta": meta,
+	}
+}
+
 func (f *SimpleResponseFormatter) ParseClientResponse(resp *http.Response, cr *ClientResponse) error {
 	defer resp.Body.Close()
 
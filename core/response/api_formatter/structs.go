@@ -28,6 +28,7 @@ type FieldError struct {
 // Meta contains pagination and other metadata
 type Meta struct {
 	*ListMeta     `json:",omitempty"`
+	*CursorMeta   `json:",omitempty"`
 	*RequestMeta  `json:",omitempty"`
 	*ResponseMeta `json:",omitempty"`
 }
@@ -42,6 +43,22 @@ type ListMeta struct {
 	HasPrev    bool `json:"has_prev"`    // Has previous page
 }
 
+// CursorMeta contains keyset pagination information
+type CursorMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor of the next page, "" on the last page
+	PrevCursor string `json:"prev_cursor,omitempty"` // Cursor of the previous page, "" on the first page
+	HasMore    bool   `json:"has_more"`              // Has next page
+}
+
+// NewCursorMeta returns the metadata of a cursor page
+func NewCursorMeta(nextCursor, prevCursor string) *CursorMeta {
+	return &CursorMeta{
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		HasMore:    nextCursor != "",
+	}
+}
+
 // RequestMeta contains request-related metadata
 type RequestMeta struct {
 	Filters  map[string]string `json:"filters,omitempty"`   // Applied filters
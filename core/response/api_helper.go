@@ -51,6 +51,25 @@ func (a *ApiHelper) Created(data any, message string) error {
 	return a.resp.WithStatus(http.StatusCreated).Json(formatted)
 }
 
+// Accepted sends a 202 Accepted response for work that completes asynchronously.
+// If location is not empty it is sent as the Location header, typically
+// pointing at a status resource the client can poll.
+func (a *ApiHelper) Accepted(location string, data any) error {
+	setLocation(a.resp, location)
+	formatted := api_formatter.GetGlobalFormatter().Success(data)
+	return a.resp.WithStatus(http.StatusAccepted).Json(formatted)
+}
+
+// NoContent sends a 204 No Content response without a body
+func (a *ApiHelper) NoContent() error {
+	a.resp.RespData = nil
+	a.resp.RespContentType = ""
+	a.resp.WriterFunc = nil
+	a.resp.stream = nil
+	a.resp.WithStatus(http.StatusNoContent)
+	return nil
+}
+
 // OkList sends a paginated list response using configured formatter
 func (a *ApiHelper) OkList(data any, meta *api_formatter.ListMeta) error {
 	formatted := api_formatter.GetGlobalFormatter().List(data, meta)
@@ -68,6 +87,17 @@ func (a *ApiHelper) OkListWithMeta(data any, meta *api_formatter.Meta) error {
 	return a.resp.WithStatus(http.StatusOK).Json(formatted)
 }
 
+// OkCursorPage sends a keyset-paginated list response. nextCursor is ""
+// on the last page and prevCursor "" on the first (see request.Cursor).
+func (a *ApiHelper) OkCursorPage(items any, nextCursor, prevCursor string) error {
+	if items == nil {
+		items = []any{}
+	}
+	formatted := api_formatter.FormatCursorList(api_formatter.GetGlobalFormatter(), items,
+		api_formatter.NewCursorMeta(nextCursor, prevCursor))
+	return a.resp.WithStatus(http.StatusOK).Json(formatted)
+}
+
 // Error sends an error response with code and message
 func (a *ApiHelper) Error(statusCode int, code, message string) error {
 	formatted := api_formatter.GetGlobalFormatter().Error(code, message)
@@ -107,7 +137,23 @@ func (a *ApiHelper) NotFound(message string) error {
 	return a.resp.WithStatus(http.StatusNotFound).Json(formatted)
 }
 
+// PreconditionFailed sends a 412 precondition failed error
+// (e.g. If-Match does not match the current resource version)
+func (a *ApiHelper) PreconditionFailed(message string) error {
+	return a.Error(http.StatusPreconditionFailed, "PRECONDITION_FAILED", message)
+}
+
 // InternalError sends a 500 internal server error
 func (a *ApiHelper) InternalError(message string) error {
 	return a.Error(http.StatusInternalServerError, "INTERNAL_ERROR", message)
 }
+
+func setLocation(r *Response, location string) {
+	if location == "" {
+		return
+	}
+	if r.RespHeaders == nil {
+		r.RespHeaders = make(map[string][]string)
+	}
+	r.RespHeaders["Location"] = []string{location}
+}
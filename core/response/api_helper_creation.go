@@ -30,6 +30,20 @@ func NewApiCreated(data any, message string) *ApiHelper {
 	return a
 }
 
+// sends a 202 Accepted response with optional Location header
+func NewApiAccepted(location string, data any) *ApiHelper {
+	a := NewApiHelper()
+	a.Accepted(location, data)
+	return a
+}
+
+// sends a 204 No Content response without a body
+func NewApiNoContent() *ApiHelper {
+	a := NewApiHelper()
+	a.NoContent()
+	return a
+}
+
 // sends a paginated list response
 func NewApiOkList(data any, meta *api_formatter.ListMeta) *ApiHelper {
 	a := NewApiHelper()
@@ -51,6 +65,13 @@ func NewApiOkListWithMeta(data any, meta *api_formatter.Meta) *ApiHelper {
 	return a
 }
 
+// sends a keyset-paginated list response
+func NewApiCursorPage(items any, nextCursor, prevCursor string) *ApiHelper {
+	a := NewApiHelper()
+	a.OkCursorPage(items, nextCursor, prevCursor)
+	return a
+}
+
 // sends an error response with code and message
 func NewApiError(statusCode int, code, message string) *ApiHelper {
 	a := NewApiHelper()
@@ -105,6 +126,13 @@ func NewApiNotFound(message string) *ApiHelper {
 	return a
 }
 
+// sends a 412 precondition failed error
+func NewApiPreconditionFailed(message string) *ApiHelper {
+	a := NewApiHelper()
+	a.Error(http.StatusPreconditionFailed, "PRECONDITION_FAILED", message)
+	return a
+}
+
 // InternalError sends a 500 internal server error
 func NewApiInternalError(message string) *ApiHelper {
 	a := NewApiHelper()
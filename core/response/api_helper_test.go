@@ -0,0 +1,106 @@
+package response_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+func TestApiHelper_Accepted(t *testing.T) {
+	api := response.NewApiHelper()
+	if err := api.Accepted("/jobs/42", map[string]any{"job_id": 42}); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	api.Resp().WriteHttp(w)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/jobs/42" {
+		t.Errorf("Expected Location /jobs/42, got %q", loc)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got %s", w.Body.String())
+	}
+	if data, _ := body["data"].(map[string]any); data["job_id"] != float64(42) {
+		t.Errorf("Expected job_id in data, got %s", w.Body.String())
+	}
+}
+
+func TestApiHelper_AcceptedWithoutLocation(t *testing.T) {
+	w := httptest.NewRecorder()
+	response.NewApiAccepted("", nil).Resp().WriteHttp(w)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", w.Code)
+	}
+	if _, ok := w.Header()["Location"]; ok {
+		t.Errorf("Expected no Location header, got %q", w.Header().Get("Location"))
+	}
+}
+
+func TestApiHelper_NoContent(t *testing.T) {
+	api := response.NewApiHelper()
+	// A body set earlier must not leak into the 204 response
+	_ = api.Ok(map[string]any{"id": 1})
+	if err := api.NoContent(); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	api.Resp().WriteHttp(w)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "" {
+		t.Errorf("Expected no Content-Type, got %q", ct)
+	}
+
+	w = httptest.NewRecorder()
+	response.NewApiNoContent().Resp().WriteHttp(w)
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("NewApiNoContent: expected empty 204, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestNewApiCursorPage(t *testing.T) {
+	decode := func(api *response.ApiHelper) map[string]any {
+		w := httptest.NewRecorder()
+		api.Resp().WriteHttp(w)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected JSON body, got %s", w.Body.String())
+		}
+		return body
+	}
+
+	body := decode(response.NewApiCursorPage([]int{1, 2}, "next-c", "prev-c"))
+	if data, _ := body["data"].([]any); len(data) != 2 {
+		t.Errorf("Expected 2 items, got %v", body["data"])
+	}
+	meta, _ := body["meta"].(map[string]any)
+	if meta["next_cursor"] != "next-c" || meta["prev_cursor"] != "prev-c" || meta["has_more"] != true {
+		t.Errorf("Unexpected meta: %v", meta)
+	}
+
+	// Last page: no next cursor
+	body = decode(response.NewApiCursorPage(nil, "", "prev-c"))
+	if data, ok := body["data"].([]any); !ok || len(data) != 0 {
+		t.Errorf("Expected an empty list, got %v", body["data"])
+	}
+	meta, _ = body["meta"].(map[string]any)
+	if _, ok := meta["next_cursor"]; ok || meta["has_more"] != false {
+		t.Errorf("Expected no next cursor, got %v", meta)
+	}
+}
@@ -2,8 +2,6 @@ package response
 
 import (
 	"net/http"
-
-	"github.com/primadi/lokstra/common/json"
 )
 
 // set status code for the response
@@ -18,7 +16,7 @@ func (r *Response) Json(data any) error {
 	if data == nil {
 		data = map[string]any{}
 	}
-	b, err := json.Marshal(data)
+	b, err := jsonEncoder.Marshal(data)
 	if err != nil {
 		return err
 	}
@@ -35,9 +33,15 @@ func (r *Response) Text(text string) error {
 	return r.Raw("text/plain; charset=utf-8", []byte(text))
 }
 
-// return raw response with specified content type
+// return raw response with specified content type.
+// An empty contentType is inferred from the content (http.DetectContentType),
+// so the response never depends on client-side sniffing.
 func (r *Response) Raw(contentType string, b []byte) error {
+	if contentType == "" {
+		contentType = http.DetectContentType(b)
+	}
 	r.RespContentType = contentType
+	r.stream = nil
 	r.WriterFunc = func(w http.ResponseWriter) error {
 		_, err := w.Write(b)
 		return err
@@ -45,9 +49,15 @@ func (r *Response) Raw(contentType string, b []byte) error {
 	return nil
 }
 
-// return stream response with specified content type
+// return stream response with specified content type.
+// Long streams should stop when the client disconnects, e.g. by checking
+// the request context (ctx.IsClientGone / ctx.CheckDisconnect) between chunks.
+// An empty contentType is sent as DefaultContentType.
+// fn gets the raw writer; use StreamTo to flush explicitly and fail clearly
+// when the writer cannot flush.
 func (r *Response) Stream(contentType string, fn func(w http.ResponseWriter) error) error {
 	r.RespContentType = contentType
+	r.stream = nil
 	r.WriterFunc = func(w http.ResponseWriter) error {
 		return fn(w)
 	}
@@ -0,0 +1,74 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+func TestResponse_ExplicitContentTypes(t *testing.T) {
+	cases := []struct {
+		name string
+		resp *response.Response
+		want string
+	}{
+		{"json", response.NewJsonResponse(map[string]any{"ok": true}), "application/json"},
+		{"html", response.NewHtmlResponse("<p>hi</p>"), "text/html; charset=utf-8"},
+		{"text", response.NewTextResponse("hi"), "text/plain; charset=utf-8"},
+		{"raw", response.NewRawResponse("image/png", []byte{0x89}), "image/png"},
+		{"raw inferred", response.NewRawResponse("", []byte("%PDF-1.4")), "application/pdf"},
+		{"raw inferred text", response.NewRawResponse("", []byte("plain words")), "text/plain; charset=utf-8"},
+		{"stream", response.NewStreamResponse("text/csv", func(w http.ResponseWriter) error {
+			_, err := w.Write([]byte("a,b\n"))
+			return err
+		}), "text/csv"},
+		{"stream untyped", response.NewStreamResponse("", func(w http.ResponseWriter) error {
+			_, err := w.Write([]byte("<html>"))
+			return err
+		}), response.DefaultContentType},
+		{"json array stream", response.NewJsonArrayStreamResponse(func(s *response.JSONArrayStream) error {
+			return s.Write(1)
+		}), "application/json"},
+		{"file", response.NewFileResponse(strings.NewReader("a,b"), "x.csv", "text/csv"), "text/csv"},
+		{"data", &response.Response{RespData: []int{1}}, "application/json"},
+		{"api", response.NewApiOk("x").Resp(), "application/json"},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		tc.resp.WriteHttp(w)
+		if got := w.Header().Get("Content-Type"); got != tc.want {
+			t.Errorf("%s: Content-Type %q, want %q", tc.name, got, tc.want)
+		}
+		if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Errorf("%s: X-Content-Type-Options %q, want nosniff", tc.name, got)
+		}
+	}
+}
+
+func TestResponse_FileWithRequestIsTypedAndNoSniff(t *testing.T) {
+	w := httptest.NewRecorder()
+	resp := response.NewFileResponse(strings.NewReader("a,b"), "orders.csv", "")
+	resp.WriteHttpRequest(w, httptest.NewRequest("GET", "/export", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type %q, want text/csv inferred from the name", ct)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options %q, want nosniff", got)
+	}
+}
+
+func TestResponse_NoSniffOptOut(t *testing.T) {
+	response.SetNoSniff(false)
+	defer response.SetNoSniff(true)
+
+	w := httptest.NewRecorder()
+	response.NewTextResponse("hi").WriteHttp(w)
+	if got := w.Header().Get("X-Content-Type-Options"); got != "" {
+		t.Errorf("X-Content-Type-Options %q, want none when disabled", got)
+	}
+}
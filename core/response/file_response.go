@@ -0,0 +1,88 @@
+package response
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// fileContent is a seekable body served honoring Range requests
+type fileContent struct {
+	content io.ReadSeeker
+	name    string
+}
+
+// return a downloadable file response for dynamic content (exports,
+// reports) that clients can resume: Accept-Ranges: bytes, single and
+// multi-range requests answered with 206 Partial Content, unsatisfiable
+// ranges with 416. name is sent as the attachment filename and, with an
+// empty contentType, used to infer the type from its extension.
+//
+// Example:
+//
+//	f, err := os.Open(exportPath)
+//	if err != nil {
+//	    return err
+//	}
+//	return c.Resp.File(f, "orders-2024.csv", "text/csv")
+//
+// The response is written after the handler returns, so the content must
+// stay open; it is closed once written if it is an io.Closer.
+func (r *Response) File(content io.ReadSeeker, name, contentType string) error {
+	r.RespContentType = contentType
+	r.file = &fileContent{content: content, name: name}
+	r.stream = nil
+	// Without the request (WriteHttp) the full content is written
+	r.WriterFunc = func(w http.ResponseWriter) error {
+		defer closeContent(content)
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, content)
+		return err
+	}
+	return nil
+}
+
+func NewFileResponse(content io.ReadSeeker, name, contentType string) *Response {
+	r := NewResponse()
+	r.File(content, name, contentType)
+	return r
+}
+
+// WriteHttpRequest writes the response to w like WriteHttp. Responses that
+// depend on the request (File honoring Range and If-Range) use req.
+func (r *Response) WriteHttpRequest(w http.ResponseWriter, req *http.Request) {
+	if r.stream != nil && req != nil {
+		// Stream with the request context, done when the client disconnects
+		r.applyHeaders(w)
+		defer r.writeTrailers(w)
+		_ = r.writeStream(w, req.Context())
+		return
+	}
+	if r.file == nil || req == nil {
+		r.WriteHttp(w)
+		return
+	}
+
+	r.applyHeaders(w)
+	defer r.writeTrailers(w)
+	if r.RespContentType != "" {
+		w.Header().Set("Content-Type", r.RespContentType)
+	}
+	if r.file.name != "" && w.Header().Get("Content-Disposition") == "" {
+		w.Header().Set("Content-Disposition",
+			mime.FormatMediaType("attachment", map[string]string{"filename": r.file.name}))
+	}
+	defer closeContent(r.file.content)
+	// Sets Accept-Ranges, Content-Length and Content-Range, and writes
+	// 200, 206 (multipart/byteranges for several ranges) or 416
+	http.ServeContent(w, req, r.file.name, time.Time{}, r.file.content)
+}
+
+func closeContent(content io.ReadSeeker) {
+	if c, ok := content.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
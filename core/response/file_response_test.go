@@ -0,0 +1,128 @@
+package response_test
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+const report = "id,amount\n1,100\n2,250\n3,75\n"
+
+func serveFile(rangeHeader string) *httptest.ResponseRecorder {
+	resp := response.NewFileResponse(strings.NewReader(report), "report.csv", "text/csv")
+	req := httptest.NewRequest("GET", "/export", nil)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	w := httptest.NewRecorder()
+	resp.WriteHttpRequest(w, req)
+	return w
+}
+
+func TestFileResponse_Full(t *testing.T) {
+	w := serveFile("")
+
+	if w.Code != http.StatusOK || w.Body.String() != report {
+		t.Fatalf("Expected 200 with the full content, got %d: %q", w.Code, w.Body.String())
+	}
+	h := w.Header()
+	if h.Get("Accept-Ranges") != "bytes" {
+		t.Errorf("Expected Accept-Ranges: bytes, got %q", h.Get("Accept-Ranges"))
+	}
+	if h.Get("Content-Length") != "27" || h.Get("Content-Type") != "text/csv" {
+		t.Errorf("Unexpected headers %v", h)
+	}
+	if h.Get("Content-Disposition") != `attachment; filename=report.csv` {
+		t.Errorf("Expected attachment filename, got %q", h.Get("Content-Disposition"))
+	}
+}
+
+func TestFileResponse_SingleRange(t *testing.T) {
+	tests := []struct {
+		rangeHeader  string
+		body         string
+		contentRange string
+	}{
+		{rangeHeader: "bytes=0-8", body: "id,amount", contentRange: "bytes 0-8/27"},
+		{rangeHeader: "bytes=16-", body: "2,250\n3,75\n", contentRange: "bytes 16-26/27"},
+		{rangeHeader: "bytes=-5", body: "3,75\n", contentRange: "bytes 22-26/27"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rangeHeader, func(t *testing.T) {
+			w := serveFile(tt.rangeHeader)
+			if w.Code != http.StatusPartialContent {
+				t.Fatalf("Expected 206, got %d", w.Code)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("Expected %q, got %q", tt.body, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Range"); got != tt.contentRange {
+				t.Errorf("Expected Content-Range %q, got %q", tt.contentRange, got)
+			}
+			if got := w.Header().Get("Content-Length"); got != strconv.Itoa(len(tt.body)) {
+				t.Errorf("Expected Content-Length %d, got %s", len(tt.body), got)
+			}
+		})
+	}
+}
+
+func TestFileResponse_MultiRange(t *testing.T) {
+	w := serveFile("bytes=0-1,10-14")
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("Expected 206, got %d", w.Code)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/byteranges" {
+		t.Fatalf("Expected multipart/byteranges, got %q", w.Header().Get("Content-Type"))
+	}
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	want := []struct{ body, contentRange string }{
+		{body: "id", contentRange: "bytes 0-1/27"},
+		{body: "1,100", contentRange: "bytes 10-14/27"},
+	}
+	for _, part := range want {
+		p, err := mr.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p)
+		if string(body) != part.body || p.Header.Get("Content-Range") != part.contentRange {
+			t.Errorf("Expected part %q (%s), got %q (%s)",
+				part.body, part.contentRange, body, p.Header.Get("Content-Range"))
+		}
+		if p.Header.Get("Content-Type") != "text/csv" {
+			t.Errorf("Expected part Content-Type text/csv, got %q", p.Header.Get("Content-Type"))
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("Expected 2 parts, got more (%v)", err)
+	}
+}
+
+func TestFileResponse_InvalidRange(t *testing.T) {
+	w := serveFile("bytes=100-200")
+	if w.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("Expected 416, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes */27" {
+		t.Errorf("Expected Content-Range bytes */27, got %q", got)
+	}
+}
+
+func TestFileResponse_WithoutRequest(t *testing.T) {
+	// WriteHttp has no request: the full content is written
+	w := httptest.NewRecorder()
+	response.NewFileResponse(strings.NewReader(report), "report.csv", "text/csv").WriteHttp(w)
+	if w.Code != http.StatusOK || w.Body.String() != report {
+		t.Errorf("Expected 200 with the full content, got %d: %q", w.Code, w.Body.String())
+	}
+}
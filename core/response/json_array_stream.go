@@ -0,0 +1,135 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+)
+
+// default number of items between flushes
+const DefaultJSONArrayFlushEvery = 100
+
+var errStreamClosed = errors.New("json array stream already closed")
+
+// JSONArrayStream writes a top-level JSON array element by element,
+// so large lists never have to be buffered in memory.
+//
+// Example:
+//
+//	return c.Resp.JsonArrayStream(func(s *response.JSONArrayStream) error {
+//	    for rows.Next() {
+//	        var p Product
+//	        if err := rows.Scan(&p.ID, &p.Name); err != nil {
+//	            return err // connection is aborted, no invalid JSON is sent
+//	        }
+//	        if err := s.Write(p); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return rows.Err()
+//	})
+type JSONArrayStream struct {
+	w          http.ResponseWriter
+	flushEvery int
+	count      int
+	started    bool
+	closed     bool
+}
+
+// creates a JSON array stream on top of w
+func NewJSONArrayStream(w http.ResponseWriter) *JSONArrayStream {
+	return &JSONArrayStream{
+		w:          w,
+		flushEvery: DefaultJSONArrayFlushEvery,
+	}
+}
+
+// sets how many items are written between flushes (0 disables periodic flush)
+func (s *JSONArrayStream) FlushEvery(n int) *JSONArrayStream {
+	s.flushEvery = n
+	return s
+}
+
+// returns the number of items written so far
+func (s *JSONArrayStream) Count() int {
+	return s.count
+}
+
+// encodes one item and writes it as the next array element
+func (s *JSONArrayStream) Write(item any) error {
+	if s.closed {
+		return errStreamClosed
+	}
+
+	// Encode first, so a marshal failure never leaves a partial element
+	b, err := jsonEncoder.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	prefix := byte(',')
+	if !s.started {
+		prefix = '['
+		s.started = true
+	}
+	if _, err := s.w.Write([]byte{prefix}); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+
+	s.count++
+	if s.flushEvery > 0 && s.count%s.flushEvery == 0 {
+		s.Flush()
+	}
+	return nil
+}
+
+// flushes buffered data to the client if the writer supports it
+func (s *JSONArrayStream) Flush() {
+	_ = http.NewResponseController(s.w).Flush()
+}
+
+// writes the closing bracket (or "[]" for an empty stream) and flushes
+func (s *JSONArrayStream) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	closing := "]"
+	if !s.started {
+		closing = "[]"
+	}
+	if _, err := s.w.Write([]byte(closing)); err != nil {
+		return err
+	}
+	s.Flush()
+	return nil
+}
+
+// aborts the connection so the client sees a broken response
+// instead of a syntactically valid but truncated array.
+// Must be called from the handler goroutine (it panics with http.ErrAbortHandler).
+func (s *JSONArrayStream) Abort() {
+	s.closed = true
+	panic(http.ErrAbortHandler)
+}
+
+// return streaming JSON array response, items are written by fn
+// if fn returns an error, the connection is aborted
+func (r *Response) JsonArrayStream(fn func(s *JSONArrayStream) error) error {
+	return r.Stream("application/json", func(w http.ResponseWriter) error {
+		s := NewJSONArrayStream(w)
+		if err := fn(s); err != nil {
+			s.Abort()
+		}
+		return s.Close()
+	})
+}
+
+func NewJsonArrayStreamResponse(fn func(s *JSONArrayStream) error) *Response {
+	r := NewResponse()
+	r.JsonArrayStream(fn)
+	return r
+}
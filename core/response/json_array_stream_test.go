@@ -0,0 +1,108 @@
+package response_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+type product struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSONArrayStream_Decode(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{name: "empty", count: 0},
+		{name: "single", count: 1},
+		{name: "many with periodic flush", count: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := response.NewJsonArrayStreamResponse(func(s *response.JSONArrayStream) error {
+				for i := range tt.count {
+					if err := s.Write(product{ID: i, Name: "item"}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+			w := httptest.NewRecorder()
+			resp.WriteHttp(w)
+
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected application/json, got %q", ct)
+			}
+
+			var items []product
+			if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+				t.Fatalf("Streamed body is not valid JSON: %v (%s)", err, w.Body.String())
+			}
+			if len(items) != tt.count {
+				t.Fatalf("Expected %d items, got %d", tt.count, len(items))
+			}
+			if tt.count > 0 && items[tt.count-1].ID != tt.count-1 {
+				t.Errorf("Expected last item ID %d, got %d", tt.count-1, items[tt.count-1].ID)
+			}
+		})
+	}
+}
+
+func TestJSONArrayStream_MidStreamErrorAbortsConnection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := response.NewJsonArrayStreamResponse(func(s *response.JSONArrayStream) error {
+			s.FlushEvery(1)
+			for i := range 3 {
+				if err := s.Write(product{ID: i}); err != nil {
+					return err
+				}
+			}
+			return errors.New("database connection lost")
+		})
+		resp.WriteHttp(w)
+	}))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, readErr := io.ReadAll(res.Body)
+	if readErr == nil {
+		t.Fatalf("Expected aborted connection, got complete body %q", body)
+	}
+
+	var items []product
+	if json.Unmarshal(body, &items) == nil {
+		t.Errorf("Expected partial body to be invalid JSON, got %q", body)
+	}
+}
+
+func TestJSONArrayStream_WriteAfterClose(t *testing.T) {
+	w := httptest.NewRecorder()
+	s := response.NewJSONArrayStream(w)
+	if err := s.Write(1); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := s.Write(2); err == nil {
+		t.Error("Expected error writing to closed stream")
+	}
+	if w.Body.String() != "[1]" {
+		t.Errorf("Expected [1], got %q", w.Body.String())
+	}
+}
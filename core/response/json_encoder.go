@@ -0,0 +1,208 @@
+package response
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+	"github.com/primadi/lokstra/common/json"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// JSONEncoder marshals response bodies (Json, Api helpers, RespData and
+// JSONArrayStream items). Any JSON library can be plugged in, e.g.
+// jsoniter.API or a JSONEncoderFunc wrapping another Marshal function.
+type JSONEncoder interface {
+	Marshal(v any) ([]byte, error)
+}
+
+// JSONEncoderFunc adapts a Marshal function to JSONEncoder
+type JSONEncoderFunc func(v any) ([]byte, error)
+
+func (f JSONEncoderFunc) Marshal(v any) ([]byte, error) {
+	return f(v)
+}
+
+// JSONConfig customizes response JSON to match existing API contracts
+type JSONConfig struct {
+	// TimeFormat is the layout used for time.Time values
+	// (e.g. time.DateTime). Empty keeps RFC 3339.
+	TimeFormat string
+
+	// OmitEmpty omits empty struct fields (nil, zero, empty) as if every
+	// field had the omitempty tag. False emits them (e.g. as null).
+	OmitEmpty bool
+
+	// BigIntAsString encodes 64-bit integers outside the range JavaScript
+	// can represent exactly (±2^53-1) as strings
+	BigIntAsString bool
+}
+
+// encoder used for all response bodies
+var jsonEncoder JSONEncoder = JSONEncoderFunc(json.Marshal)
+
+// SetJSONEncoder replaces the encoder used for response bodies.
+// Call during startup, before serving requests. Nil restores the default.
+func SetJSONEncoder(enc JSONEncoder) {
+	if enc == nil {
+		enc = JSONEncoderFunc(json.Marshal)
+	}
+	jsonEncoder = enc
+}
+
+// GetJSONEncoder returns the encoder used for response bodies
+func GetJSONEncoder() JSONEncoder {
+	return jsonEncoder
+}
+
+// SetJSONConfig sets the response encoder to one built from cfg
+func SetJSONConfig(cfg *JSONConfig) {
+	SetJSONEncoder(NewJSONEncoder(cfg))
+}
+
+// NewJSONEncoder creates an encoder (compatible with encoding/json)
+// applying cfg
+func NewJSONEncoder(cfg *JSONConfig) JSONEncoder {
+	api := jsoniter.Config{
+		EscapeHTML:             true,
+		SortMapKeys:            true,
+		ValidateJsonRawMessage: true,
+	}.Froze()
+	if cfg != nil {
+		api.RegisterExtension(&jsonExtension{cfg: *cfg})
+	}
+	return api
+}
+
+const maxSafeInt = 1<<53 - 1
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	timePtrType   = reflect.TypeFor[*time.Time]()
+	marshalerType = reflect.TypeFor[interface{ MarshalJSON() ([]byte, error) }]()
+)
+
+type jsonExtension struct {
+	jsoniter.DummyExtension
+	cfg JSONConfig
+}
+
+func (e *jsonExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	t := typ.Type1()
+	if e.cfg.TimeFormat != "" {
+		// *time.Time is matched too, it would otherwise use MarshalJSON
+		switch t {
+		case timeType:
+			return &timeEncoder{layout: e.cfg.TimeFormat}
+		case timePtrType:
+			return &timePtrEncoder{timeEncoder{layout: e.cfg.TimeFormat}}
+		}
+	}
+	if e.cfg.BigIntAsString && !t.Implements(marshalerType) &&
+		!reflect.PointerTo(t).Implements(marshalerType) {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+			return &bigIntEncoder{kind: t.Kind()}
+		}
+	}
+	return nil
+}
+
+func (e *jsonExtension) UpdateStructDescriptor(desc *jsoniter.StructDescriptor) {
+	if !e.cfg.OmitEmpty {
+		return
+	}
+	for _, binding := range desc.Fields {
+		binding.Field = omitEmptyField{binding.Field}
+	}
+}
+
+// omitEmptyField adds omitempty to the json tag of a struct field
+type omitEmptyField struct {
+	reflect2.StructField
+}
+
+func (f omitEmptyField) Tag() reflect.StructTag {
+	tag := f.StructField.Tag()
+	name, _ := tag.Lookup("json")
+	// The first json key wins, so the original tag stays readable for others
+	return reflect.StructTag(`json:"`+name+`,omitempty" `) + tag
+}
+
+type timeEncoder struct {
+	layout string
+}
+
+func (enc *timeEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*time.Time)(ptr).IsZero()
+}
+
+func (enc *timeEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString((*time.Time)(ptr).Format(enc.layout))
+}
+
+type timePtrEncoder struct {
+	timeEncoder
+}
+
+func (enc *timePtrEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(**time.Time)(ptr) == nil
+}
+
+func (enc *timePtrEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	p := *(**time.Time)(ptr)
+	if p == nil {
+		stream.WriteNil()
+		return
+	}
+	enc.timeEncoder.Encode(unsafe.Pointer(p), stream)
+}
+
+type bigIntEncoder struct {
+	kind reflect.Kind
+}
+
+func (enc *bigIntEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	switch enc.kind {
+	case reflect.Int:
+		return *(*int)(ptr) == 0
+	case reflect.Int64:
+		return *(*int64)(ptr) == 0
+	case reflect.Uint:
+		return *(*uint)(ptr) == 0
+	default:
+		return *(*uint64)(ptr) == 0
+	}
+}
+
+func (enc *bigIntEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	switch enc.kind {
+	case reflect.Int, reflect.Int64:
+		var v int64
+		if enc.kind == reflect.Int {
+			v = int64(*(*int)(ptr))
+		} else {
+			v = *(*int64)(ptr)
+		}
+		if v > maxSafeInt || v < -maxSafeInt {
+			stream.WriteString(strconv.FormatInt(v, 10))
+			return
+		}
+		stream.WriteInt64(v)
+	default:
+		var v uint64
+		if enc.kind == reflect.Uint {
+			v = uint64(*(*uint)(ptr))
+		} else {
+			v = *(*uint64)(ptr)
+		}
+		if v > maxSafeInt {
+			stream.WriteString(strconv.FormatUint(v, 10))
+			return
+		}
+		stream.WriteUint64(v)
+	}
+}
@@ -0,0 +1,100 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+type order struct {
+	ID        int64     `json:"id"`
+	Note      string    `json:"note"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	Customer  *product  `json:"customer"`
+}
+
+func encode(t *testing.T, cfg *response.JSONConfig, v any) string {
+	t.Helper()
+	b, err := response.NewJSONEncoder(cfg).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestJSONEncoder_TimeFormat(t *testing.T) {
+	o := order{ID: 1, CreatedAt: time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)}
+
+	got := encode(t, &response.JSONConfig{TimeFormat: time.DateTime}, o)
+	expected := `{"id":1,"note":"","tags":null,"created_at":"2025-03-04 05:06:07","customer":null}`
+	if got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	// Pointers and maps use the same format
+	got = encode(t, &response.JSONConfig{TimeFormat: time.DateOnly}, map[string]any{"at": &o.CreatedAt, "none": (*time.Time)(nil)})
+	if got != `{"at":"2025-03-04","none":null}` {
+		t.Errorf("Expected date-only time, got %s", got)
+	}
+}
+
+func TestJSONEncoder_OmitEmpty(t *testing.T) {
+	o := order{ID: 1}
+
+	if got := encode(t, &response.JSONConfig{OmitEmpty: true, TimeFormat: time.DateTime}, o); got != `{"id":1}` {
+		t.Errorf("Expected empty fields omitted, got %s", got)
+	}
+	if got := encode(t, &response.JSONConfig{}, o); got !=
+		`{"id":1,"note":"","tags":null,"created_at":"0001-01-01T00:00:00Z","customer":null}` {
+		t.Errorf("Expected empty fields emitted, got %s", got)
+	}
+}
+
+func TestJSONEncoder_BigIntAsString(t *testing.T) {
+	got := encode(t, &response.JSONConfig{BigIntAsString: true}, map[string]any{
+		"small": int64(42),
+		"big":   int64(1) << 60,
+		"ubig":  uint64(1) << 63,
+	})
+	expected := `{"big":"1152921504606846976","small":42,"ubig":"9223372036854775808"}`
+	if got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestSetJSONEncoder_UsedByResponses(t *testing.T) {
+	response.SetJSONConfig(&response.JSONConfig{TimeFormat: time.DateOnly})
+	defer response.SetJSONEncoder(nil)
+
+	at := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	resp := response.NewResponse()
+	_ = resp.Json(map[string]any{"at": at})
+	w := httptest.NewRecorder()
+	resp.WriteHttp(w)
+	if w.Body.String() != `{"at":"2025-03-04"}` {
+		t.Errorf("Json: expected configured time format, got %s", w.Body.String())
+	}
+
+	resp = response.NewResponse()
+	_ = resp.JsonArrayStream(func(s *response.JSONArrayStream) error {
+		return s.Write(map[string]any{"at": at})
+	})
+	w = httptest.NewRecorder()
+	resp.WriteHttp(w)
+	if w.Body.String() != `[{"at":"2025-03-04"}]` {
+		t.Errorf("Stream: expected configured time format, got %s", w.Body.String())
+	}
+
+	resp = response.NewResponse()
+	resp.RespData = map[string]any{"at": at}
+	w = httptest.NewRecorder()
+	resp.WriteHttp(w)
+	if w.Code != http.StatusOK || w.Body.String() != "{\"at\":\"2025-03-04\"}\n" {
+		t.Errorf("RespData: expected configured time format, got %s", w.Body.String())
+	}
+}
@@ -11,6 +11,16 @@ type Response struct {
 	RespStatusCode  int                             // HTTP status code
 	RespContentType string                          // MIME type (default: application/json)
 	WriterFunc      func(http.ResponseWriter) error // custom writer (streaming/file)
+
+	file   *fileContent                // served honoring Range (see File)
+	stream func(s *StreamWriter) error // flushed stream (see StreamTo)
+
+	trailers []trailer // sent after the body (see WithTrailer)
+}
+
+// HasOutput reports whether a status, body, writer or file was set
+func (r *Response) HasOutput() bool {
+	return r.RespStatusCode != 0 || r.RespData != nil || r.WriterFunc != nil || r.file != nil
 }
 
 func NewResponse() *Response {
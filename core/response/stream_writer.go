@@ -0,0 +1,159 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrFlushNotSupported is returned when a streamed response cannot be
+// flushed to the client: the data would be buffered until the handler
+// returns instead of being streamed.
+var ErrFlushNotSupported = errors.New("streaming not supported: response writer cannot flush")
+
+// CanFlush reports whether writes to w can be flushed to the client. Writers
+// with an Unwrap method (middleware wrappers) are followed to the writer
+// they wrap, so a wrapper's own Flush does not hide an unflushable writer.
+func CanFlush(w http.ResponseWriter) bool {
+	for {
+		u, ok := w.(unwrapper)
+		if !ok {
+			_, ok := w.(http.Flusher)
+			return ok
+		}
+		w = u.Unwrap()
+	}
+}
+
+// StreamWriter writes a streamed response (e.g. Server-Sent Events) that is
+// flushed explicitly, and exposes the request context so streams stop when
+// the client disconnects.
+//
+// Example:
+//
+//	return c.Resp.StreamTo("text/event-stream", func(s *response.StreamWriter) error {
+//	    for {
+//	        select {
+//	        case <-s.Context().Done():
+//	            return nil // client gone
+//	        case ev := <-events:
+//	            if err := s.SendEvent(ev.Type, ev.Data); err != nil {
+//	                return err
+//	            }
+//	        }
+//	    }
+//	})
+type StreamWriter struct {
+	w   http.ResponseWriter
+	ctx context.Context
+}
+
+// creates a stream writer on top of w, or ErrFlushNotSupported if w
+// cannot flush
+func NewStreamWriter(ctx context.Context, w http.ResponseWriter) (*StreamWriter, error) {
+	if !CanFlush(w) {
+		return nil, ErrFlushNotSupported
+	}
+	return &StreamWriter{w: w, ctx: ctx}, nil
+}
+
+// returns the request context, done when the client disconnects
+func (s *StreamWriter) Context() context.Context {
+	return s.ctx
+}
+
+// writes p, or returns the context error once the request is done
+func (s *StreamWriter) Write(p []byte) (int, error) {
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return s.w.Write(p)
+}
+
+// writes str, see Write
+func (s *StreamWriter) WriteString(str string) (int, error) {
+	return s.Write([]byte(str))
+}
+
+// sends buffered data to the client
+func (s *StreamWriter) Flush() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	return http.NewResponseController(s.w).Flush()
+}
+
+// writes a Server-Sent Event and flushes it. event may be empty (default
+// "message" event); multi-line data is sent as several data lines.
+func (s *StreamWriter) SendEvent(event, data string) error {
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	for line := range strings.SplitSeq(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	if _, err := s.WriteString(b.String()); err != nil {
+		return err
+	}
+	return s.Flush()
+}
+
+// return streaming response written through a StreamWriter. Headers are
+// flushed right away; proxy buffering is disabled with X-Accel-Buffering
+// and text/event-stream responses are sent with Cache-Control: no-cache.
+// If the writer cannot flush, a 500 naming ErrFlushNotSupported is sent
+// instead of silently buffering. If fn returns an error while the client
+// is still connected, the connection is aborted.
+func (r *Response) StreamTo(contentType string, fn func(s *StreamWriter) error) error {
+	r.RespContentType = contentType
+	r.stream = fn
+	r.WriterFunc = func(w http.ResponseWriter) error {
+		return r.writeStream(w, context.Background())
+	}
+	return nil
+}
+
+func NewStreamToResponse(contentType string, fn func(s *StreamWriter) error) *Response {
+	r := NewResponse()
+	r.StreamTo(contentType, fn)
+	return r
+}
+
+// writeStream writes the headers and runs the stream function
+func (r *Response) writeStream(w http.ResponseWriter, ctx context.Context) error {
+	s, err := NewStreamWriter(ctx, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	ct := r.RespContentType
+	if ct == "" {
+		ct = DefaultContentType
+	}
+	h := w.Header()
+	h.Set("Content-Type", ct)
+	if h.Get("X-Accel-Buffering") == "" {
+		h.Set("X-Accel-Buffering", "no")
+	}
+	if strings.HasPrefix(ct, "text/event-stream") && h.Get("Cache-Control") == "" {
+		h.Set("Cache-Control", "no-cache")
+	}
+
+	status := r.RespStatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_ = s.Flush()
+
+	if err := r.stream(s); err != nil && ctx.Err() == nil && !errors.Is(err, io.ErrClosedPipe) {
+		// Abort so the client sees a broken stream rather than a clean end
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package response_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+// plainWriter implements only http.ResponseWriter (no Flush)
+type plainWriter struct {
+	header http.Header
+	status int
+	body   strings.Builder
+}
+
+func (w *plainWriter) Header() http.Header         { return w.header }
+func (w *plainWriter) WriteHeader(status int)      { w.status = status }
+func (w *plainWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func TestStreamTo_NotFlushableFailsClearly(t *testing.T) {
+	w := &plainWriter{header: http.Header{}}
+	if response.CanFlush(w) {
+		t.Fatal("Expected plain writer not to be flushable")
+	}
+	if _, err := response.NewStreamWriter(context.Background(), w); !errors.Is(err, response.ErrFlushNotSupported) {
+		t.Fatalf("Expected ErrFlushNotSupported, got %v", err)
+	}
+
+	called := false
+	resp := response.NewStreamToResponse("text/event-stream", func(s *response.StreamWriter) error {
+		called = true
+		return nil
+	})
+	resp.WriteHttp(w)
+
+	if called {
+		t.Error("Stream function must not run on an unflushable writer")
+	}
+	if w.status != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", w.status)
+	}
+	if !strings.Contains(w.body.String(), response.ErrFlushNotSupported.Error()) {
+		t.Errorf("Expected flush error in body, got %q", w.body.String())
+	}
+}
+
+func TestStreamTo_WrappedUnflushableWriter(t *testing.T) {
+	// A wrapper's own Flush must not hide an unflushable writer below it
+	base := &plainWriter{header: http.Header{}}
+	w := response.WrapWriter(base, &flushingWrapper{ResponseWriter: base})
+	if response.CanFlush(w) {
+		t.Error("Expected wrapped plain writer not to be flushable")
+	}
+	if !response.CanFlush(response.WrapWriter(httptest.NewRecorder(), &flushingWrapper{})) {
+		t.Error("Expected wrapped recorder to be flushable")
+	}
+}
+
+type flushingWrapper struct{ http.ResponseWriter }
+
+func (w *flushingWrapper) Flush() {}
+
+func TestStreamTo_ServerSentEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := response.NewStreamToResponse("text/event-stream", func(s *response.StreamWriter) error {
+			if err := s.SendEvent("", "hello"); err != nil {
+				return err
+			}
+			return s.SendEvent("update", "line1\nline2")
+		})
+		resp.WriteHttpRequest(w, r)
+	}))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %q", ct)
+	}
+	if cc := res.Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Expected Cache-Control no-cache, got %q", cc)
+	}
+	if xb := res.Header.Get("X-Accel-Buffering"); xb != "no" {
+		t.Errorf("Expected X-Accel-Buffering no, got %q", xb)
+	}
+
+	var buf strings.Builder
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() {
+		buf.WriteString(sc.Text() + "\n")
+	}
+	expected := "data: hello\n\nevent: update\ndata: line1\ndata: line2\n\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestStreamTo_EventsArriveBeforeHandlerReturns(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := response.NewStreamToResponse("text/event-stream", func(s *response.StreamWriter) error {
+			if err := s.SendEvent("", "first"); err != nil {
+				return err
+			}
+			<-release
+			return nil
+		})
+		resp.WriteHttpRequest(w, r)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	line := make(chan string, 1)
+	go func() {
+		l, _ := bufio.NewReader(res.Body).ReadString('\n')
+		line <- l
+	}()
+	select {
+	case l := <-line:
+		if l != "data: first\n" {
+			t.Errorf("Unexpected first line %q", l)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Event was not flushed before the handler returned")
+	}
+}
+
+func TestStreamTo_ContextDoneOnClientDisconnect(t *testing.T) {
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := response.NewStreamToResponse("text/event-stream", func(s *response.StreamWriter) error {
+			if err := s.SendEvent("", "ready"); err != nil {
+				return err
+			}
+			<-s.Context().Done()
+			_, err := s.WriteString("data: late\n\n")
+			done <- err
+			return err
+		})
+		resp.WriteHttpRequest(w, r)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_, _ = bufio.NewReader(res.Body).ReadString('\n')
+	cancel()
+	res.Body.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled after disconnect, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stream context was not cancelled on client disconnect")
+	}
+}
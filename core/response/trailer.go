@@ -0,0 +1,43 @@
+package response
+
+import "net/http"
+
+// trailer is a response trailer whose value is computed after the body
+type trailer struct {
+	name  string
+	value func() string
+}
+
+// WithTrailer sends an HTTP trailer after the body, e.g. a checksum of a
+// streamed body or a gRPC-style status. The trailer is declared in the
+// Trailer header before the body is written, and value is called once the
+// body is complete. Clients receive trailers only over chunked HTTP/1.1 or
+// HTTP/2 responses, which net/http uses when a trailer is declared.
+//
+//	hash := sha256.New()
+//	c.Resp.WithTrailer("X-Checksum", func() string {
+//	    return hex.EncodeToString(hash.Sum(nil))
+//	})
+//	return c.Resp.Stream("application/octet-stream", func(w http.ResponseWriter) error {
+//	    _, err := io.Copy(io.MultiWriter(w, hash), src)
+//	    return err
+//	})
+func (r *Response) WithTrailer(name string, value func() string) *Response {
+	r.trailers = append(r.trailers, trailer{name: http.CanonicalHeaderKey(name), value: value})
+	return r
+}
+
+// declareTrailers announces the trailers in the Trailer header, which must
+// be set before the status is written
+func (r *Response) declareTrailers(w http.ResponseWriter) {
+	for _, t := range r.trailers {
+		w.Header().Add("Trailer", t.name)
+	}
+}
+
+// writeTrailers sets the trailer values once the body was written
+func (r *Response) writeTrailers(w http.ResponseWriter) {
+	for _, t := range r.trailers {
+		w.Header().Set(t.name, t.value())
+	}
+}
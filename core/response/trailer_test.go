@@ -0,0 +1,73 @@
+package response_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+func serveResponse(t *testing.T, build func() *response.Response) *http.Response {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		build().WriteHttpRequest(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestResponse_WithTrailerStream(t *testing.T) {
+	var h hash.Hash
+	resp := serveResponse(t, func() *response.Response {
+		h = sha256.New()
+		r := response.NewStreamResponse("text/plain", func(w http.ResponseWriter) error {
+			_, err := io.Copy(io.MultiWriter(w, h), strings.NewReader("streamed body"))
+			return err
+		})
+		return r.WithTrailer("x-checksum", func() string {
+			return hex.EncodeToString(h.Sum(nil))
+		})
+	})
+
+	// The client moves the declared (Trailer header) names to resp.Trailer
+	if _, declared := resp.Trailer["X-Checksum"]; !declared {
+		t.Errorf("Expected X-Checksum to be declared, got %v", resp.Trailer)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "streamed body" {
+		t.Errorf("body = %q", body)
+	}
+
+	sum := sha256.Sum256([]byte("streamed body"))
+	if got := resp.Trailer.Get("X-Checksum"); got != hex.EncodeToString(sum[:]) {
+		t.Errorf("X-Checksum trailer = %q", got)
+	}
+}
+
+func TestResponse_WithTrailerJson(t *testing.T) {
+	resp := serveResponse(t, func() *response.Response {
+		r := response.NewJsonResponse(map[string]any{"ok": true})
+		return r.WithTrailer("Grpc-Status", func() string { return "0" }).
+			WithTrailer("Grpc-Message", func() string { return "" })
+	})
+
+	if len(resp.Trailer) != 2 {
+		t.Errorf("Expected 2 declared trailers, got %v", resp.Trailer)
+	}
+	io.Copy(io.Discard, resp.Body)
+	if got := resp.Trailer.Get("Grpc-Status"); got != "0" {
+		t.Errorf("Grpc-Status trailer = %q, want 0", got)
+	}
+}
@@ -0,0 +1,187 @@
+package response
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// WrapWriter returns a ResponseWriter for middleware that wraps w: Header,
+// WriteHeader and Write go to wrapper (typically a struct embedding w that
+// overrides Write), while the optional interfaces of w are kept, so
+// streaming and WebSocket upgrades work however many middleware are stacked:
+//
+//   - http.Flusher: wrapper's Flush if it has one, else w's
+//   - http.Hijacker: wrapper's Hijack if it has one, else w's
+//   - io.ReaderFrom: wrapper's ReadFrom if it has one, else copied through
+//     wrapper's Write (so the wrapper still sees the body)
+//
+// An interface is only implemented when wrapper or w (or a writer below it,
+// see Unwrap) implements it. Unwrap returns w, for http.ResponseController.
+//
+// Example:
+//
+//	type countingWriter struct {
+//	    http.ResponseWriter
+//	    n int
+//	}
+//
+//	func (w *countingWriter) Write(b []byte) (int, error) {
+//	    n, err := w.ResponseWriter.Write(b)
+//	    w.n += n
+//	    return n, err
+//	}
+//
+//	orig := c.W.ResponseWriter
+//	c.W.ResponseWriter = response.WrapWriter(orig, &countingWriter{ResponseWriter: orig})
+func WrapWriter(w http.ResponseWriter, wrapper http.ResponseWriter) http.ResponseWriter {
+	ww := &wrappedWriter{ResponseWriter: wrapper, base: w}
+
+	_, canFlush := wrapper.(http.Flusher)
+	canFlush = canFlush || findFlusher(w) != nil
+	_, canHijack := wrapper.(http.Hijacker)
+	canHijack = canHijack || findHijacker(w) != nil
+	_, canReadFrom := wrapper.(io.ReaderFrom)
+	canReadFrom = canReadFrom || findReaderFrom(w) != nil
+
+	f, h, r := flusher{ww}, hijacker{ww}, readerFrom{ww}
+	switch {
+	case canFlush && canHijack && canReadFrom:
+		return struct {
+			*wrappedWriter
+			flusher
+			hijacker
+			readerFrom
+		}{ww, f, h, r}
+	case canFlush && canHijack:
+		return struct {
+			*wrappedWriter
+			flusher
+			hijacker
+		}{ww, f, h}
+	case canFlush && canReadFrom:
+		return struct {
+			*wrappedWriter
+			flusher
+			readerFrom
+		}{ww, f, r}
+	case canHijack && canReadFrom:
+		return struct {
+			*wrappedWriter
+			hijacker
+			readerFrom
+		}{ww, h, r}
+	case canFlush:
+		return struct {
+			*wrappedWriter
+			flusher
+		}{ww, f}
+	case canHijack:
+		return struct {
+			*wrappedWriter
+			hijacker
+		}{ww, h}
+	case canReadFrom:
+		return struct {
+			*wrappedWriter
+			readerFrom
+		}{ww, r}
+	}
+	return ww
+}
+
+// wrappedWriter sends the core methods to the wrapper and keeps the
+// wrapped writer for optional interfaces
+type wrappedWriter struct {
+	http.ResponseWriter
+	base http.ResponseWriter
+}
+
+func (w *wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.base
+}
+
+type flusher struct{ w *wrappedWriter }
+
+func (f flusher) Flush() {
+	if fl, ok := f.w.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+		return
+	}
+	if fl := findFlusher(f.w.base); fl != nil {
+		fl.Flush()
+	}
+}
+
+type hijacker struct{ w *wrappedWriter }
+
+func (h hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := h.w.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	if hj := findHijacker(h.w.base); hj != nil {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+type readerFrom struct{ w *wrappedWriter }
+
+func (r readerFrom) ReadFrom(src io.Reader) (int64, error) {
+	if rf, ok := r.w.ResponseWriter.(io.ReaderFrom); ok {
+		return rf.ReadFrom(src)
+	}
+	// writerOnly hides ReadFrom so io.Copy does not recurse
+	return io.Copy(writerOnly{r.w.ResponseWriter}, src)
+}
+
+type writerOnly struct{ io.Writer }
+
+type unwrapper interface {
+	Unwrap() http.ResponseWriter
+}
+
+// findFlusher, findHijacker and findReaderFrom look for the interface on w
+// and the writers it wraps
+func findFlusher(w http.ResponseWriter) http.Flusher {
+	for w != nil {
+		if f, ok := w.(http.Flusher); ok {
+			return f
+		}
+		u, ok := w.(unwrapper)
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
+
+func findHijacker(w http.ResponseWriter) http.Hijacker {
+	for w != nil {
+		if h, ok := w.(http.Hijacker); ok {
+			return h
+		}
+		u, ok := w.(unwrapper)
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
+
+func findReaderFrom(w http.ResponseWriter) io.ReaderFrom {
+	for w != nil {
+		if r, ok := w.(io.ReaderFrom); ok {
+			return r
+		}
+		u, ok := w.(unwrapper)
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
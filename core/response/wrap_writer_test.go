@@ -0,0 +1,116 @@
+package response_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+// hijackRecorder is a ResponseRecorder that can also be hijacked
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	server, client := net.Pipe()
+	client.Close()
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
+
+// upperWriter is a typical middleware writer: it only overrides Write
+type upperWriter struct {
+	http.ResponseWriter
+}
+
+func (w *upperWriter) Write(b []byte) (int, error) {
+	return w.ResponseWriter.Write(bytes.ToUpper(b))
+}
+
+func TestWrapWriter_KeepsFlusherAndHijacker(t *testing.T) {
+	base := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	// Stack three middleware writers
+	var w http.ResponseWriter = base
+	for range 3 {
+		w = response.WrapWriter(w, &upperWriter{ResponseWriter: w})
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		t.Fatal("expected wrapped writer to implement http.Flusher")
+	}
+	if _, ok := w.(http.Hijacker); !ok {
+		t.Fatal("expected wrapped writer to implement http.Hijacker")
+	}
+
+	w.Write([]byte("hello"))
+	flusher.Flush()
+	if !base.Flushed {
+		t.Error("expected Flush to reach the underlying writer")
+	}
+	if base.Body.String() != "HELLO" {
+		t.Errorf("expected writes to go through the wrappers, got %q", base.Body.String())
+	}
+
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		t.Fatalf("hijack failed: %v", err)
+	}
+	conn.Close()
+	if !base.hijacked {
+		t.Error("expected Hijack to reach the underlying writer")
+	}
+}
+
+func TestWrapWriter_OnlyExposesSupportedInterfaces(t *testing.T) {
+	// ResponseRecorder flushes but cannot be hijacked
+	base := httptest.NewRecorder()
+	w := response.WrapWriter(base, &upperWriter{ResponseWriter: base})
+
+	if _, ok := w.(http.Flusher); !ok {
+		t.Error("expected http.Flusher")
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		t.Error("expected no http.Hijacker when the underlying writer has none")
+	}
+}
+
+// flushingWriter buffers writes until Flush
+type flushingWriter struct {
+	http.ResponseWriter
+	buf     bytes.Buffer
+	flushed bool
+}
+
+func (w *flushingWriter) Write(b []byte) (int, error) { return w.buf.Write(b) }
+
+func (w *flushingWriter) Flush() {
+	w.flushed = true
+	w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+}
+
+func TestWrapWriter_PrefersWrapperMethods(t *testing.T) {
+	base := httptest.NewRecorder()
+	fw := &flushingWriter{ResponseWriter: base}
+	w := response.WrapWriter(base, fw)
+
+	io.Copy(w, strings.NewReader("streamed"))
+	if base.Body.Len() != 0 {
+		t.Fatalf("expected copies to go through the wrapper's Write, got %q", base.Body.String())
+	}
+
+	w.(http.Flusher).Flush()
+	if !fw.flushed || base.Body.String() != "streamed" {
+		t.Errorf("expected the wrapper's Flush to run, got %q", base.Body.String())
+	}
+}
@@ -1,19 +1,46 @@
 package response
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 )
 
-// WriteHttp writes the response to http.ResponseWriter.
-// Priority: WriterFunc > Data > empty.
-func (r *Response) WriteHttp(w http.ResponseWriter) {
-	// apply headers
+// DefaultContentType is sent for streamed/custom bodies without a content type
+const DefaultContentType = "application/octet-stream"
+
+var noSniff = true
+
+// SetNoSniff enables (default) or disables the X-Content-Type-Options: nosniff
+// header on every response. Responses always carry an explicit Content-Type,
+// so clients never need to sniff; disable only when a proxy sets the header.
+func SetNoSniff(enabled bool) {
+	noSniff = enabled
+}
+
+// applyHeaders copies the custom headers, adds X-Content-Type-Options and
+// declares the trailers
+func (r *Response) applyHeaders(w http.ResponseWriter) {
 	for k, values := range r.RespHeaders {
 		for _, v := range values {
 			w.Header().Add(k, v)
 		}
 	}
+	if noSniff && w.Header().Get("X-Content-Type-Options") == "" {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}
+	r.declareTrailers(w)
+}
+
+// WriteHttp writes the response to http.ResponseWriter.
+// Priority: WriterFunc > Data > empty.
+func (r *Response) WriteHttp(w http.ResponseWriter) {
+	r.applyHeaders(w)
+	defer r.writeTrailers(w)
+
+	if r.stream != nil {
+		_ = r.writeStream(w, context.Background())
+		return
+	}
 
 	// determine status code
 	status := r.RespStatusCode
@@ -23,9 +50,14 @@ func (r *Response) WriteHttp(w http.ResponseWriter) {
 
 	// 1. Custom writer
 	if r.WriterFunc != nil {
-		if r.RespContentType != "" {
-			w.Header().Set("Content-Type", r.RespContentType)
+		ct := r.RespContentType
+		if ct == "" {
+			ct = w.Header().Get("Content-Type")
+		}
+		if ct == "" {
+			ct = DefaultContentType
 		}
+		w.Header().Set("Content-Type", ct)
 		w.WriteHeader(status)
 		_ = r.WriterFunc(w)
 		return
@@ -37,9 +69,14 @@ func (r *Response) WriteHttp(w http.ResponseWriter) {
 		if ct == "" {
 			ct = "application/json"
 		}
+		b, err := jsonEncoder.Marshal(r.RespData)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		w.Header().Set("Content-Type", ct)
 		w.WriteHeader(status)
-		_ = json.NewEncoder(w).Encode(r.RespData)
+		_, _ = w.Write(append(b, '\n'))
 		return
 	}
 
@@ -0,0 +1,131 @@
+package response
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+var errZipStreamClosed = errors.New("zip stream already closed")
+
+// ZipStream writes a zip archive entry by entry straight to the client,
+// so "download all" exports never buffer the whole archive in memory.
+// Entries are compressed with Deflate.
+//
+// Example:
+//
+//	return c.Resp.ZipStream("reports.zip", func(z *response.ZipStream) error {
+//	    for _, rep := range reports {
+//	        f, err := os.Open(rep.Path)
+//	        if err != nil {
+//	            return err // connection is aborted, no truncated archive is sent
+//	        }
+//	        err = z.AddFile(rep.Name+".csv", f)
+//	        f.Close()
+//	        if err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	})
+type ZipStream struct {
+	w      http.ResponseWriter
+	zw     *zip.Writer
+	count  int
+	closed bool
+}
+
+// creates a zip stream on top of w
+func NewZipStream(w http.ResponseWriter) *ZipStream {
+	return &ZipStream{w: w, zw: zip.NewWriter(w)}
+}
+
+// returns the number of entries added so far
+func (z *ZipStream) Count() int {
+	return z.count
+}
+
+// adds an entry named name with the content of r, and flushes it
+func (z *ZipStream) AddFile(name string, r io.Reader) error {
+	return z.AddFileWithTime(name, r, time.Now())
+}
+
+// adds an entry like AddFile, with the given modification time
+func (z *ZipStream) AddFileWithTime(name string, r io.Reader, modified time.Time) error {
+	if z.closed {
+		return errZipStreamClosed
+	}
+
+	fw, err := z.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, r); err != nil {
+		return err
+	}
+
+	z.count++
+	z.Flush()
+	return nil
+}
+
+// flushes buffered data to the client if the writer supports it
+func (z *ZipStream) Flush() {
+	if z.zw.Flush() == nil {
+		_ = http.NewResponseController(z.w).Flush()
+	}
+}
+
+// writes the central directory, completing the archive, and flushes
+func (z *ZipStream) Close() error {
+	if z.closed {
+		return nil
+	}
+	z.closed = true
+
+	if err := z.zw.Close(); err != nil {
+		return err
+	}
+	_ = http.NewResponseController(z.w).Flush()
+	return nil
+}
+
+// aborts the connection so the client sees a broken download
+// instead of a truncated archive.
+// Must be called from the handler goroutine (it panics with http.ErrAbortHandler).
+func (z *ZipStream) Abort() {
+	z.closed = true
+	panic(http.ErrAbortHandler)
+}
+
+// return streaming zip response sent as attachment filename, entries are
+// added by fn. If fn returns an error, the connection is aborted.
+func (r *Response) ZipStream(filename string, fn func(z *ZipStream) error) error {
+	if filename != "" {
+		if r.RespHeaders == nil {
+			r.RespHeaders = make(map[string][]string)
+		}
+		r.RespHeaders["Content-Disposition"] = []string{
+			mime.FormatMediaType("attachment", map[string]string{"filename": filename})}
+	}
+	return r.Stream("application/zip", func(w http.ResponseWriter) error {
+		z := NewZipStream(w)
+		if err := fn(z); err != nil {
+			z.Abort()
+		}
+		return z.Close()
+	})
+}
+
+func NewZipStreamResponse(filename string, fn func(z *ZipStream) error) *Response {
+	r := NewResponse()
+	r.ZipStream(filename, fn)
+	return r
+}
@@ -0,0 +1,96 @@
+package response_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/response"
+)
+
+func TestZipStream_Decode(t *testing.T) {
+	files := map[string]string{
+		"sales.csv":     "month,total\njan,100\nfeb,120\n",
+		"customers.csv": strings.Repeat("id,name\n", 1000),
+	}
+
+	resp := response.NewZipStreamResponse("reports.zip", func(z *response.ZipStream) error {
+		for _, name := range []string{"sales.csv", "customers.csv"} {
+			if err := z.AddFile(name, strings.NewReader(files[name])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	resp.WriteHttp(w)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Expected application/zip, got %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != "attachment; filename=reports.zip" {
+		t.Errorf("Expected attachment filename, got %q", cd)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Streamed body is not a valid zip: %v", err)
+	}
+	if len(zr.File) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(zr.File))
+	}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(content) != files[f.Name] {
+			t.Errorf("Unexpected content for %s: %q", f.Name, content)
+		}
+	}
+}
+
+func TestZipStream_MidStreamErrorAbortsConnection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := response.NewZipStreamResponse("reports.zip", func(z *response.ZipStream) error {
+			if err := z.AddFile("first.csv", strings.NewReader("a,b\n")); err != nil {
+				return err
+			}
+			return errors.New("report generation failed")
+		})
+		resp.WriteHttp(w)
+	}))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, readErr := io.ReadAll(res.Body)
+	if readErr == nil {
+		t.Fatalf("Expected aborted connection, got complete body of %d bytes", len(body))
+	}
+	if _, err := zip.NewReader(bytes.NewReader(body), int64(len(body))); err == nil {
+		t.Error("Expected partial body to be an invalid zip")
+	}
+}
+
+func TestZipStream_AddAfterClose(t *testing.T) {
+	z := response.NewZipStream(httptest.NewRecorder())
+	if err := z.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := z.AddFile("late.txt", strings.NewReader("x")); err == nil {
+		t.Error("Expected error adding to closed stream")
+	}
+}
@@ -0,0 +1,168 @@
+package route
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+// ConcurrencyLimitConfig configures WithConcurrencyLimitConfigOption
+type ConcurrencyLimitConfig struct {
+	// Limit is the maximum number of concurrent executions of the handler
+	Limit int
+	// QueueSize is the maximum number of requests waiting for a slot.
+	// 0 means requests over the limit are rejected immediately.
+	QueueSize int
+	// QueueTimeout is the maximum time a request waits in the queue
+	// 0 means wait until a slot frees or the client goes away
+	QueueTimeout time.Duration
+	// RejectStatus is returned when the queue is full (default: 429)
+	RejectStatus int
+	// RetryAfter is sent in the Retry-After header of rejected requests (default: 1s)
+	RetryAfter time.Duration
+}
+
+// Limits how many requests may execute the route handler at the same time.
+// Up to queueSize extra requests wait for a slot, the rest get 429 Too Many Requests.
+func WithConcurrencyLimitOption(limit int, queueSize int) RouteHandlerOption {
+	return WithConcurrencyLimitConfigOption(&ConcurrencyLimitConfig{
+		Limit:     limit,
+		QueueSize: queueSize,
+	})
+}
+
+// Same as WithConcurrencyLimitOption, with full control over queueing and rejection.
+// Each route the option is applied to gets its own slots and queue.
+func WithConcurrencyLimitConfigOption(cfg *ConcurrencyLimitConfig) RouteHandlerOption {
+	return &withConcurrencyLimitOption{cfg: *cfg}
+}
+
+type withConcurrencyLimitOption struct {
+	cfg ConcurrencyLimitConfig
+}
+
+// Apply implements RouteHandlerOption.
+func (o *withConcurrencyLimitOption) Apply(rt *Route) {
+	l := &concurrencyLimiter{rt: rt}
+	l.configure(o.cfg)
+	rt.limiter = l
+	rt.Middleware = append(rt.Middleware, request.HandlerFunc(l.handle))
+}
+
+var _ RouteHandlerOption = (*withConcurrencyLimitOption)(nil)
+
+// concurrencyLimiter holds the slots and queue of one route
+type concurrencyLimiter struct {
+	cfg      ConcurrencyLimitConfig
+	sem      chan struct{}
+	rt       *Route
+	current  atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Int64
+}
+
+// configure sets the limits; must be called before the route serves requests
+func (l *concurrencyLimiter) configure(cfg ConcurrencyLimitConfig) {
+	if cfg.Limit <= 0 {
+		cfg.Limit = 1
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+	if cfg.RejectStatus == 0 {
+		cfg.RejectStatus = http.StatusTooManyRequests
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = time.Second
+	}
+	l.cfg = cfg
+	l.sem = make(chan struct{}, cfg.Limit)
+}
+
+func (l *concurrencyLimiter) handle(c *request.Context) error {
+	select {
+	case l.sem <- struct{}{}:
+	default:
+		if !l.wait(c) {
+			l.rejected.Add(1)
+			c.W.Header().Set("Retry-After", strconv.Itoa(int((l.cfg.RetryAfter+time.Second-1)/time.Second)))
+			return c.Api.Error(l.cfg.RejectStatus, "CONCURRENCY_LIMIT_EXCEEDED",
+				"Too many concurrent requests, please retry later")
+		}
+	}
+
+	l.current.Add(1)
+	// Deferred so the slot is released even if the handler panics
+	defer func() {
+		l.current.Add(-1)
+		<-l.sem
+	}()
+	return c.Next()
+}
+
+// wait queues the request until a slot frees; returns false if rejected
+func (l *concurrencyLimiter) wait(c *request.Context) bool {
+	if l.queued.Add(1) > int64(l.cfg.QueueSize) {
+		l.queued.Add(-1)
+		return false
+	}
+	defer l.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.cfg.QueueTimeout > 0 {
+		timer := time.NewTimer(l.cfg.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	case <-timeout:
+		return false
+	case <-c.R.Context().Done():
+		return false
+	}
+}
+
+// ConcurrencyStat is a snapshot of a concurrency-limited route
+type ConcurrencyStat struct {
+	Route    string // Route full name (available after router Build)
+	Path     string
+	Limit    int
+	Current  int64 // Requests currently executing
+	Queued   int64 // Requests waiting for a slot
+	Rejected int64 // Total rejected requests
+}
+
+// ConcurrencyStats returns current/queued counts for every route of routes
+// using a concurrency limit, e.g. to export them as gauges.
+//
+// Example:
+//
+//	stats := route.ConcurrencyStats(r.Routes()...)
+func ConcurrencyStats(routes ...*Route) []ConcurrencyStat {
+	var stats []ConcurrencyStat
+	for _, rt := range routes {
+		l := rt.limiter
+		if l == nil {
+			continue
+		}
+		stat := ConcurrencyStat{
+			Route:    rt.FullName,
+			Path:     rt.FullPath,
+			Limit:    l.cfg.Limit,
+			Current:  l.current.Load(),
+			Queued:   l.queued.Load(),
+			Rejected: l.rejected.Load(),
+		}
+		if stat.Path == "" {
+			stat.Path = rt.Path
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
@@ -0,0 +1,168 @@
+package route_test
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func findStat(t *testing.T, r router.Router, path string) route.ConcurrencyStat {
+	t.Helper()
+	for _, s := range route.ConcurrencyStats(r.Routes()...) {
+		if s.Path == path {
+			return s
+		}
+	}
+	t.Fatalf("no concurrency stat for %s", path)
+	return route.ConcurrencyStat{}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConcurrencyLimit_RejectsAndQueues(t *testing.T) {
+	const limit = 2
+	release := make(chan struct{})
+	var started sync.WaitGroup
+
+	r := router.New("limit-router")
+	r.GET("/report", func(c *request.Context) error {
+		started.Done()
+		<-release
+		return c.Api.Ok("report")
+	}, route.WithConcurrencyLimitOption(limit, 1))
+
+	codes := make(chan int, limit+1)
+	serve := func() {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+		codes <- w.Code
+	}
+
+	// First N proceed
+	started.Add(limit)
+	for range limit {
+		go serve()
+	}
+	started.Wait()
+
+	// N+1th waits in the queue
+	go serve()
+	waitFor(t, func() bool { return findStat(t, r, "/report").Queued == 1 })
+
+	stat := findStat(t, r, "/report")
+	if stat.Current != limit {
+		t.Errorf("Expected %d running, got %d", limit, stat.Current)
+	}
+
+	// Queue is full: rejected immediately
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+	if w.Code != 429 {
+		t.Errorf("Expected 429 when queue is full, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on rejection")
+	}
+
+	// Free the slots: queued request runs
+	started.Add(1)
+	close(release)
+	for range limit + 1 {
+		if code := <-codes; code != 200 {
+			t.Errorf("Expected 200, got %d", code)
+		}
+	}
+
+	stat = findStat(t, r, "/report")
+	if stat.Current != 0 || stat.Queued != 0 || stat.Rejected != 1 {
+		t.Errorf("Unexpected final stats: %+v", stat)
+	}
+}
+
+func TestConcurrencyLimit_ReleasesOnPanic(t *testing.T) {
+	r := router.New("panic-router")
+	r.GET("/boom", func(c *request.Context) error {
+		panic("boom")
+	}, route.WithConcurrencyLimitOption(1, 0))
+
+	for range 2 {
+		func() {
+			defer func() { _ = recover() }()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+		}()
+	}
+
+	if stat := findStat(t, r, "/boom"); stat.Current != 0 || stat.Rejected != 0 {
+		t.Errorf("Expected slot released after panic, got %+v", stat)
+	}
+}
+
+func TestConcurrencyLimit_SharedOptionLimitsEachRoute(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	block := func(c *request.Context) error {
+		started <- struct{}{}
+		<-release
+		return c.Api.Ok("done")
+	}
+
+	limit := route.WithConcurrencyLimitOption(1, 0)
+	r := router.New("shared-limit-router")
+	r.GET("/a", block, limit)
+	r.GET("/b", block, limit)
+
+	codes := make(chan int, 2)
+	for _, path := range []string{"/a", "/b"} {
+		go func() {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			codes <- w.Code
+		}()
+	}
+	// Each route has its own slot, so both run at once
+	for range 2 {
+		select {
+		case <-started:
+		case code := <-codes:
+			t.Fatalf("Expected both routes to run, one returned %d", code)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Routes sharing an option should not share a slot")
+		}
+	}
+	close(release)
+	for range 2 {
+		if code := <-codes; code != 200 {
+			t.Errorf("Expected 200, got %d", code)
+		}
+	}
+
+	if stats := route.ConcurrencyStats(r.Routes()...); len(stats) != 2 {
+		t.Errorf("Expected a stat per route, got %+v", stats)
+	}
+}
+
+func TestConcurrencyLimit_DoesNotMutateConfig(t *testing.T) {
+	cfg := &route.ConcurrencyLimitConfig{QueueSize: -1}
+	r := router.New("config-copy-router")
+	r.GET("/a", func(c *request.Context) error { return nil }, route.WithConcurrencyLimitConfigOption(cfg))
+	r.Build()
+
+	if *cfg != (route.ConcurrencyLimitConfig{QueueSize: -1}) {
+		t.Errorf("Caller's config was changed: %+v", *cfg)
+	}
+}
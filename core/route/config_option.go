@@ -0,0 +1,126 @@
+package route
+
+import (
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/middleware/rate_limit/limiter"
+)
+
+// Config keys under "routes.<route-name>", where route-name is the name
+// given with WithNameOption:
+//
+//	configs:
+//	  routes:
+//	    create-order:
+//	      description: Create a new order
+//	      middlewares: [audit-log]
+//	      concurrency_limit: 10
+//	      queue_size: 20
+//	      queue_timeout: 2s
+//	      rate_limit: 100
+//	      rate_limit_window: 1m
+//
+// Precedence: config overrides code defaults. Description and the
+// concurrency and rate limits replace the values set by route options in
+// code (unset keys keep the code value); a limit set only in config is
+// added to the route. Middlewares are appended after the route-level
+// middlewares declared in code.
+const (
+	CONFIG_ROUTES_PREFIX = "routes."
+
+	CONFIG_DESCRIPTION       = "description"
+	CONFIG_MIDDLEWARES       = "middlewares"
+	CONFIG_CONCURRENCY_LIMIT = "concurrency_limit"
+	CONFIG_QUEUE_SIZE        = "queue_size"
+	CONFIG_QUEUE_TIMEOUT     = "queue_timeout"
+	CONFIG_RATE_LIMIT        = "rate_limit"
+	CONFIG_RATE_LIMIT_WINDOW = "rate_limit_window"
+)
+
+// Global config resolver set by lokstra_registry at initialization
+var globalConfigResolver request.ConfigResolver
+
+// SetConfigResolver sets the config resolver used by ApplyConfig
+// Called by lokstra_registry during initialization to avoid circular dependency
+func SetConfigResolver(resolver request.ConfigResolver) {
+	globalConfigResolver = resolver
+}
+
+// ApplyConfig applies the "routes.<name>" config of a named route.
+// Called by the router when it is built; unnamed routes are left untouched.
+func ApplyConfig(rt *Route) {
+	if rt.configApplied || rt.Name == "" || globalConfigResolver == nil {
+		return
+	}
+	rt.configApplied = true
+
+	cfg, ok := globalConfigResolver(CONFIG_ROUTES_PREFIX+rt.Name, nil).(map[string]any)
+	if !ok {
+		return
+	}
+
+	rt.Description = utils.GetValueFromMap(cfg, CONFIG_DESCRIPTION, rt.Description)
+
+	switch mws := cfg[CONFIG_MIDDLEWARES].(type) {
+	case []string:
+		for _, mw := range mws {
+			rt.Middleware = append(rt.Middleware, mw)
+		}
+	case []any:
+		for _, mw := range mws {
+			if name, ok := mw.(string); ok {
+				rt.Middleware = append(rt.Middleware, name)
+			}
+		}
+	}
+
+	applyConcurrencyConfig(rt, cfg)
+	applyRateLimitConfig(rt, cfg)
+
+}
+
+func applyConcurrencyConfig(rt *Route, cfg map[string]any) {
+	_, hasLimit := cfg[CONFIG_CONCURRENCY_LIMIT]
+	_, hasQueue := cfg[CONFIG_QUEUE_SIZE]
+	_, hasTimeout := cfg[CONFIG_QUEUE_TIMEOUT]
+	if !hasLimit && !hasQueue && !hasTimeout {
+		return
+	}
+
+	// A copy, the route keeps its code config until configure
+	limitCfg := ConcurrencyLimitConfig{}
+	if rt.limiter != nil {
+		limitCfg = rt.limiter.cfg
+	}
+	limitCfg.Limit = utils.GetValueFromMap(cfg, CONFIG_CONCURRENCY_LIMIT, limitCfg.Limit)
+	limitCfg.QueueSize = utils.GetValueFromMap(cfg, CONFIG_QUEUE_SIZE, limitCfg.QueueSize)
+	limitCfg.QueueTimeout = utils.GetValueFromMap(cfg, CONFIG_QUEUE_TIMEOUT, limitCfg.QueueTimeout)
+
+	if rt.limiter != nil {
+		rt.limiter.configure(limitCfg)
+	} else {
+		WithConcurrencyLimitConfigOption(&limitCfg).Apply(rt)
+	}
+}
+
+func applyRateLimitConfig(rt *Route, cfg map[string]any) {
+	_, hasLimit := cfg[CONFIG_RATE_LIMIT]
+	_, hasWindow := cfg[CONFIG_RATE_LIMIT_WINDOW]
+	if !hasLimit && !hasWindow {
+		return
+	}
+
+	// A copy, the route keeps its code config until configure
+	limitCfg := limiter.Config{}
+	if rt.rateLimit != nil {
+		limitCfg = rt.rateLimit.cfg
+	}
+	limitCfg.Limit = utils.GetValueFromMap(cfg, CONFIG_RATE_LIMIT, limitCfg.Limit)
+	limitCfg.Window = utils.GetValueFromMap(cfg, CONFIG_RATE_LIMIT_WINDOW, limitCfg.Window)
+
+	if rt.rateLimit != nil {
+		rt.rateLimit.configure(limitCfg)
+	} else {
+		WithRateLimitConfigOption(&limitCfg).Apply(rt)
+	}
+}
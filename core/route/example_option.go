@@ -0,0 +1,32 @@
+package route
+
+// Attaches sample request and response values to the route. openapi.Generate
+// uses their types as the body schemas and their values as the examples.
+// Either value may be nil.
+//
+// Example:
+//
+//	r.POST("/users", createUser, route.WithExample(
+//		CreateUserRequest{Name: "Ana", Email: "ana@example.com"},
+//		User{ID: 42, Name: "Ana", Email: "ana@example.com"},
+//	))
+func WithExample(req, resp any) RouteHandlerOption {
+	return &withExampleOption{req: req, resp: resp}
+}
+
+type withExampleOption struct {
+	req  any
+	resp any
+}
+
+// Apply implements RouteHandlerOption.
+func (o *withExampleOption) Apply(rt *Route) {
+	if o.req != nil {
+		rt.RequestExample = o.req
+	}
+	if o.resp != nil {
+		rt.ResponseExample = o.resp
+	}
+}
+
+var _ RouteHandlerOption = (*withExampleOption)(nil)
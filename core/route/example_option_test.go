@@ -0,0 +1,47 @@
+package route_test
+
+import (
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+type createUserRequest struct {
+	Name string `json:"name"`
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestWithExample(t *testing.T) {
+	r := router.New("users")
+	r.POST("/users", func(c *request.Context) error { return nil },
+		route.WithExample(createUserRequest{Name: "Ana"}, user{ID: 42, Name: "Ana"}))
+	r.GET("/users/{id}", func(c *request.Context) error { return nil },
+		route.WithExample(nil, user{ID: 42, Name: "Ana"}))
+
+	routes := r.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+
+	create := routes[0]
+	if req, ok := create.RequestExample.(createUserRequest); !ok || req.Name != "Ana" {
+		t.Errorf("Unexpected request example: %#v", create.RequestExample)
+	}
+	if resp, ok := create.ResponseExample.(user); !ok || resp.ID != 42 {
+		t.Errorf("Unexpected response example: %#v", create.ResponseExample)
+	}
+
+	get := routes[1]
+	if get.RequestExample != nil {
+		t.Errorf("Expected no request example, got %#v", get.RequestExample)
+	}
+	if _, ok := get.ResponseExample.(user); !ok {
+		t.Errorf("Unexpected response example: %#v", get.ResponseExample)
+	}
+}
@@ -0,0 +1,37 @@
+package route
+
+import "github.com/primadi/lokstra/core/request"
+
+// Rejects unknown fields in the JSON body of the route's requests with a
+// validation error (400), overriding the default set by
+// request.SetStrictJSON. Helps catch client typos.
+//
+// Example:
+//
+//	r.POST("/payments", createPayment, route.WithStrictJSON())
+func WithStrictJSON() RouteHandlerOption {
+	return &withStrictJSONOption{strict: true}
+}
+
+// Ignores unknown fields in the JSON body of the route's requests,
+// overriding the default set by request.SetStrictJSON. Helps forward
+// compatibility, e.g. for webhooks whose payloads grow new fields.
+func WithLenientJSON() RouteHandlerOption {
+	return &withStrictJSONOption{strict: false}
+}
+
+type withStrictJSONOption struct {
+	strict bool
+}
+
+// Apply implements RouteHandlerOption.
+func (o *withStrictJSONOption) Apply(rt *Route) {
+	rt.Middleware = append(rt.Middleware, request.HandlerFunc(o.handle))
+}
+
+func (o *withStrictJSONOption) handle(c *request.Context) error {
+	c.SetStrictJSON(o.strict)
+	return c.Next()
+}
+
+var _ RouteHandlerOption = (*withStrictJSONOption)(nil)
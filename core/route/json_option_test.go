@@ -0,0 +1,72 @@
+package route_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+type createPayment struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func postJSON(r router.Router, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func newPaymentRouter() router.Router {
+	r := router.New("payments")
+	handler := func(c *request.Context, p *createPayment) error {
+		return c.Api.Ok(p)
+	}
+	r.POST("/strict", handler, route.WithStrictJSON())
+	r.POST("/lenient", handler, route.WithLenientJSON())
+	r.POST("/default", handler)
+	return r
+}
+
+func TestWithStrictJSON_UnknownField(t *testing.T) {
+	r := newPaymentRouter()
+	body := `{"amount":100,"currency":"USD","curency":"EUR"}`
+
+	w := postJSON(r, "/strict", body)
+	if w.Code != 400 || !strings.Contains(w.Body.String(), "UNKNOWN_FIELD") ||
+		!strings.Contains(w.Body.String(), "curency") {
+		t.Errorf("Strict: expected 400 UNKNOWN_FIELD naming the field, got %d %s", w.Code, w.Body.String())
+	}
+
+	for _, path := range []string{"/lenient", "/default"} {
+		w := postJSON(r, path, body)
+		if w.Code != 200 || !strings.Contains(w.Body.String(), `"currency":"USD"`) {
+			t.Errorf("%s: expected 200, got %d %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	if w := postJSON(r, "/strict", `{"amount":100,"currency":"USD"}`); w.Code != 200 {
+		t.Errorf("Strict with known fields: expected 200, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWithLenientJSON_OverridesStrictDefault(t *testing.T) {
+	request.SetStrictJSON(true)
+	defer request.SetStrictJSON(false)
+
+	r := newPaymentRouter()
+	body := `{"amount":100,"currency":"USD","note":"added in v2"}`
+
+	if w := postJSON(r, "/lenient", body); w.Code != 200 {
+		t.Errorf("Lenient: expected 200 under a strict default, got %d %s", w.Code, w.Body.String())
+	}
+	if w := postJSON(r, "/default", body); w.Code != 400 {
+		t.Errorf("Default: expected 400 under a strict default, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package route
+
+import (
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/middleware/rate_limit/limiter"
+)
+
+// Limits each client (see request.Context.ClientIP) to limit requests of
+// the route per window, like the rate_limit middleware: requests over the
+// limit get 429 Too Many Requests (RATE_LIMITED) with a Retry-After header.
+// Each route the option is applied to counts its own requests.
+//
+// Example:
+//
+//	r.POST("/login", login, route.WithRateLimitOption(5, time.Minute))
+func WithRateLimitOption(limit int, window time.Duration) RouteHandlerOption {
+	return WithRateLimitConfigOption(&limiter.Config{Limit: limit, Window: window})
+}
+
+// Limits the route's requests with a full rate limit config (key func,
+// X-RateLimit-* headers, message); unset fields use the rate_limit defaults
+func WithRateLimitConfigOption(cfg *limiter.Config) RouteHandlerOption {
+	return &withRateLimitOption{cfg: *cfg}
+}
+
+type withRateLimitOption struct {
+	cfg limiter.Config
+}
+
+// Apply implements RouteHandlerOption.
+func (o *withRateLimitOption) Apply(rt *Route) {
+	p := &rateLimitPolicy{}
+	p.configure(o.cfg)
+	rt.rateLimit = p
+	rt.Middleware = append(rt.Middleware, request.HandlerFunc(p.handle))
+}
+
+var _ RouteHandlerOption = (*withRateLimitOption)(nil)
+
+// rateLimitPolicy holds the rate limiter of one route
+type rateLimitPolicy struct {
+	cfg     limiter.Config
+	handler request.HandlerFunc
+}
+
+// configure (re)creates the limiter; must be called before the route
+// serves requests
+func (p *rateLimitPolicy) configure(cfg limiter.Config) {
+	// limiter.New fills the defaults into its own copy
+	p.cfg = cfg
+	p.handler = limiter.New(&cfg).Handler()
+}
+
+func (p *rateLimitPolicy) handle(c *request.Context) error {
+	return p.handler(c)
+}
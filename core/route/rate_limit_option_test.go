@@ -0,0 +1,75 @@
+package route_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/rate_limit/limiter"
+)
+
+func TestRateLimit_PerClientAndRoute(t *testing.T) {
+	limit := route.WithRateLimitOption(2, time.Minute)
+	r := router.New("rate-limit-router")
+	ok := func(c *request.Context) error { return c.Api.Ok("ok") }
+	r.POST("/login", ok, limit)
+	r.POST("/reset", ok, limit)
+
+	send := func(path, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, nil)
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for range 2 {
+		if w := send("/login", "10.0.0.1:1234"); w.Code != 200 {
+			t.Fatalf("Expected 200 within the limit, got %d", w.Code)
+		}
+	}
+	w := send("/login", "10.0.0.1:1234")
+	if w.Code != 429 || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After over the limit, got %d %v", w.Code, w.Header())
+	}
+	// Same error contract as the rate_limit middleware
+	if !strings.Contains(w.Body.String(), "RATE_LIMITED") {
+		t.Errorf("Expected RATE_LIMITED error code, got %s", w.Body.String())
+	}
+
+	// Other clients and other routes sharing the option count on their own
+	if w := send("/login", "10.0.0.2:1234"); w.Code != 200 {
+		t.Errorf("Expected 200 for another client, got %d", w.Code)
+	}
+	if w := send("/reset", "10.0.0.1:1234"); w.Code != 200 {
+		t.Errorf("Expected 200 on another route, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_ConfigOptionHeaders(t *testing.T) {
+	r := router.New("rate-limit-headers-router")
+	r.GET("/search", func(c *request.Context) error {
+		return c.Api.Ok("ok")
+	}, route.WithRateLimitConfigOption(&limiter.Config{
+		Limit:   1,
+		Window:  time.Minute,
+		Headers: true,
+		Message: "Slow down",
+	}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/search", nil))
+	if w.Header().Get(limiter.HeaderLimit) != "1" || w.Header().Get(limiter.HeaderRemaining) != "0" {
+		t.Errorf("Expected X-RateLimit headers, got %v", w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/search", nil))
+	if w.Code != 429 || !strings.Contains(w.Body.String(), "Slow down") {
+		t.Errorf("Expected 429 with the configured message, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -10,12 +10,20 @@ type Route struct {
 	Handler          request.HandlerFunc
 	Middleware       []any // Mixed: request.HandlerFunc or string (lazy)
 	OverrideParentMw bool
+	Versions         map[string]any // media-type version -> handler (see Version)
+	Tags             []string       // see WithTags
+	RequestExample   any            // see WithExample
+	ResponseExample  any            // see WithExample
 
 	// populated during Build()
 	RouterName     string // Name of the router this route belongs to
 	FullPath       string
 	FullName       string
 	FullMiddleware []request.HandlerFunc
+
+	limiter       *concurrencyLimiter // set by WithConcurrencyLimitOption
+	rateLimit     *rateLimitPolicy    // set by WithRateLimitOption
+	configApplied bool                // ApplyConfig already ran
 }
 
 type RouteHandlerOption interface {
@@ -0,0 +1,34 @@
+package route
+
+import "slices"
+
+// Tags the route, for grouping and filtering in introspection (see
+// Router.Routes) and for middleware targeting tagged routes (see
+// Router.UseForTag).
+//
+// Example:
+//
+//	r.POST("/invoices", createInvoice, route.WithTags("public", "billing"))
+func WithTags(tags ...string) RouteHandlerOption {
+	return &withTagsOption{tags: tags}
+}
+
+type withTagsOption struct {
+	tags []string
+}
+
+// Apply implements RouteHandlerOption.
+func (o *withTagsOption) Apply(rt *Route) {
+	for _, tag := range o.tags {
+		if tag != "" && !rt.HasTag(tag) {
+			rt.Tags = append(rt.Tags, tag)
+		}
+	}
+}
+
+var _ RouteHandlerOption = (*withTagsOption)(nil)
+
+// HasTag reports whether the route is tagged with tag
+func (rt *Route) HasTag(tag string) bool {
+	return slices.Contains(rt.Tags, tag)
+}
@@ -0,0 +1,115 @@
+package route
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+// Version registers h (any handler form accepted by the router) for
+// media-type version v of the route, as an alternative to URL-path versioning.
+// The version is read from the Accept header ("application/vnd.myapp.v2+json"),
+// then from Content-Type; requests without a known version get the route's
+// main handler.
+//
+// Example:
+//
+//	r.GET("/users/{id}", getUserV1,
+//	    route.Version("2", getUserV2)) // Accept: application/vnd.myapp.v2+json
+func Version(v string, h any) RouteHandlerOption {
+	return &versionOption{version: normalizeVersion(v), handler: h}
+}
+
+type versionOption struct {
+	version string
+	handler any
+}
+
+// Apply implements RouteHandlerOption.
+func (o *versionOption) Apply(rt *Route) {
+	if rt.Versions == nil {
+		rt.Versions = make(map[string]any)
+	}
+	rt.Versions[o.version] = o.handler
+}
+
+var _ RouteHandlerOption = (*versionOption)(nil)
+
+// MediaTypeVersion returns the version of a vendor media type list such as
+// "application/vnd.myapp.v2+json" ("2") or "application/vnd.myapp.v2.1+json"
+// ("2.1"), normalized like Version, or "" when none carries a version
+func MediaTypeVersion(header string) string {
+	for part := range strings.SplitSeq(header, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		_, subtype, _ := strings.Cut(mediaType, "/")
+		vendor, ok := strings.CutPrefix(subtype, "vnd.")
+		if !ok {
+			continue
+		}
+		vendor, _, _ = strings.Cut(vendor, "+")
+		// The version is the trailing "v<n>[.<n>...]" after the vendor
+		// name: vnd.myapp.v2, vnd.myapp.v2.1
+		segments := strings.Split(vendor, ".")
+		for i := 1; i < len(segments); i++ {
+			major, ok := strings.CutPrefix(segments[i], "v")
+			if ok && isDigits(major) && allDigits(segments[i+1:]) {
+				return normalizeVersion(strings.Join(segments[i:], "."))
+			}
+		}
+	}
+	return ""
+}
+
+// VersionDispatch returns a handler that runs the handler registered for
+// the requested media-type version, or def when there is none
+func VersionDispatch(def request.HandlerFunc, versions map[string]request.HandlerFunc) request.HandlerFunc {
+	return func(c *request.Context) error {
+		c.W.Header().Add("Vary", "Accept")
+		v := MediaTypeVersion(c.R.Header.Get("Accept"))
+		if v == "" {
+			v = MediaTypeVersion(c.R.Header.Get("Content-Type"))
+		}
+		if h, ok := versions[v]; ok {
+			return h(c)
+		}
+		return def(c)
+	}
+}
+
+// normalizeVersion returns v without its "v" prefix and trailing ".0"
+// segments: "V2", "2.0" and "v2" are all "2", "v2.1" is "2.1"
+func normalizeVersion(v string) string {
+	v = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "v")
+	for {
+		trimmed, ok := strings.CutSuffix(v, ".0")
+		if !ok || trimmed == "" {
+			return v
+		}
+		v = trimmed
+	}
+}
+
+func allDigits(segments []string) bool {
+	for _, s := range segments {
+		if !isDigits(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package route_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func TestVersion_DispatchByAccept(t *testing.T) {
+	r := router.New("versioned")
+	r.GET("/users", func(c *request.Context) error {
+		return c.Api.Ok("v1")
+	}, route.Version("v2", func(c *request.Context) error {
+		return c.Api.Ok("v2")
+	}))
+
+	cases := map[string]string{
+		"application/vnd.myapp.v2+json":                  `"v2"`,
+		"application/vnd.myapp.v1+json":                  `"v1"`,
+		"text/html, application/vnd.myapp.v2+json;q=0.9": `"v2"`,
+		"application/json":                               `"v1"`,
+		"":                                               `"v1"`,
+		"application/vnd.myapp.v9+json":                  `"v1"`,
+	}
+	for accept, want := range cases {
+		req := httptest.NewRequest("GET", "/users", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Accept %q: body %s, want data %s", accept, w.Body.String(), want)
+		}
+		if w.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: missing Vary: Accept", accept)
+		}
+	}
+}
+
+func TestMediaTypeVersion(t *testing.T) {
+	cases := map[string]string{
+		"application/vnd.myapp.v2+json":   "2",
+		"application/vnd.github.v3":       "3",
+		"application/vnd.myapp.v2.1+json": "2.1",
+		"application/vnd.myapp.v2.0+json": "2",
+		"application/vnd.myapp.v2.x+json": "",
+		"application/vnd.api+json":        "",
+		"application/vnd.x.vendor+json":   "",
+		"application/json":                "",
+		"not a media type":                "",
+	}
+	for header, want := range cases {
+		if got := route.MediaTypeVersion(header); got != want {
+			t.Errorf("MediaTypeVersion(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestVersion_MinorVersion(t *testing.T) {
+	r := router.New("versioned")
+	r.GET("/users", func(c *request.Context) error {
+		return c.Api.Ok("v1")
+	}, route.Version("2.1", func(c *request.Context) error {
+		return c.Api.Ok("v2.1")
+	}), route.Version("V3.0", func(c *request.Context) error {
+		return c.Api.Ok("v3")
+	}))
+
+	cases := map[string]string{
+		"application/vnd.myapp.v2.1+json": `"v2.1"`,
+		"application/vnd.myapp.v2+json":   `"v1"`,
+		"application/vnd.myapp.v3+json":   `"v3"`,
+		"application/vnd.myapp.v3.0+json": `"v3"`,
+	}
+	for accept, want := range cases {
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Accept %q: body %s, want data %s", accept, w.Body.String(), want)
+		}
+	}
+}
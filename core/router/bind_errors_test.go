@@ -0,0 +1,66 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+)
+
+type updateItemRequest struct {
+	ID    int    `path:"id"`
+	Limit int    `query:"limit"`
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"email"`
+}
+
+func TestSmartBinding_AggregatesAllErrors(t *testing.T) {
+	r := router.New("bind-router")
+	r.PUT("/items/{id}", func(c *request.Context, req *updateItemRequest) error {
+		return c.Api.Ok(req)
+	})
+
+	req := httptest.NewRequest("PUT", "/items/abc?limit=ten",
+		strings.NewReader(`{"email":"not-an-email"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`"field":"id"`,    // invalid path param
+		`"field":"limit"`, // invalid query param
+		`"field":"name"`,  // required body field
+		`"field":"email"`, // invalid body field
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected response to contain %s, got %s", want, body)
+		}
+	}
+}
+
+func TestSmartBinding_MalformedBodyShortCircuits(t *testing.T) {
+	r := router.New("bind-router")
+	r.PUT("/items/{id}", func(c *request.Context, req *updateItemRequest) error {
+		return c.Api.Ok(req)
+	})
+
+	req := httptest.NewRequest("PUT", "/items/abc?limit=ten", strings.NewReader(`{"name":`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "INVALID_JSON") || strings.Contains(body, `"field":"limit"`) {
+		t.Errorf("Expected only the body error, got %s", body)
+	}
+}
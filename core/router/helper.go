@@ -106,12 +106,6 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 
 			// Case 1: Returns *response.Response or response.Response
 			if meta.returnsResponse {
-				// Check for nil pointer (only if it's a pointer type)
-				if meta.isResponsePtr && firstResult.IsNil() {
-					// Nil response pointer - send default success
-					return ctx.Api.Ok(nil)
-				}
-
 				var resp *response.Response
 				if meta.isResponsePtr {
 					resp = firstResult.Interface().(*response.Response)
@@ -120,20 +114,11 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 					respVal := firstResult.Interface().(response.Response)
 					resp = &respVal
 				}
-
-				// Use the Response directly by copying it to ctx.Resp
-				*ctx.Resp = *resp
-				return nil
+				return setReturnedResponse(ctx, resp)
 			}
 
 			// Case 2: Returns *response.ApiHelper or response.ApiHelper
 			if meta.returnsApiHelper {
-				// Check for nil pointer (only if it's a pointer type)
-				if meta.isApiHelperPtr && firstResult.IsNil() {
-					// Nil ApiHelper pointer - send default success
-					return ctx.Api.Ok(nil)
-				}
-
 				var apiHelper *response.ApiHelper
 				if meta.isApiHelperPtr {
 					apiHelper = firstResult.Interface().(*response.ApiHelper)
@@ -142,10 +127,7 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 					apiHelperVal := firstResult.Interface().(response.ApiHelper)
 					apiHelper = &apiHelperVal
 				}
-
-				// Extract Response from ApiHelper and copy to ctx.Resp
-				*ctx.Resp = *apiHelper.Resp()
-				return nil
+				return setReturnedApiHelper(ctx, apiHelper)
 			}
 
 			// Case 3: Regular data return - wrap in API response
@@ -173,12 +155,6 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 
 		// Case 1: Returns *response.Response or response.Response
 		if meta.returnsResponse {
-			// Check for nil pointer (only if it's a pointer type)
-			if meta.isResponsePtr && firstResult.IsNil() {
-				// Nil response pointer - send default success
-				return ctx.Api.Ok(nil)
-			}
-
 			var resp *response.Response
 			if meta.isResponsePtr {
 				resp = firstResult.Interface().(*response.Response)
@@ -187,20 +163,11 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 				respVal := firstResult.Interface().(response.Response)
 				resp = &respVal
 			}
-
-			// Use the Response directly by copying it to ctx.Resp
-			*ctx.Resp = *resp
-			return nil
+			return setReturnedResponse(ctx, resp)
 		}
 
 		// Case 2: Returns *response.ApiHelper or response.ApiHelper
 		if meta.returnsApiHelper {
-			// Check for nil pointer (only if it's a pointer type)
-			if meta.isApiHelperPtr && firstResult.IsNil() {
-				// Nil ApiHelper pointer - send default success
-				return ctx.Api.Ok(nil)
-			}
-
 			var apiHelper *response.ApiHelper
 			if meta.isApiHelperPtr {
 				apiHelper = firstResult.Interface().(*response.ApiHelper)
@@ -209,10 +176,7 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 				apiHelperVal := firstResult.Interface().(response.ApiHelper)
 				apiHelper = &apiHelperVal
 			}
-
-			// Extract Response from ApiHelper and copy to ctx.Resp
-			*ctx.Resp = *apiHelper.Resp()
-			return nil
+			return setReturnedApiHelper(ctx, apiHelper)
 		}
 
 		// Case 3: Regular data return - wrap in API response
@@ -220,6 +184,26 @@ func adaptSmart(path string, v any) request.HandlerFunc {
 	}
 }
 
+// setReturnedResponse copies a *Response returned by a handler to c.Resp.
+// A nil response sends the default success response.
+func setReturnedResponse(c *request.Context, resp *response.Response) error {
+	if resp == nil {
+		return c.Api.Ok(nil)
+	}
+	*c.Resp = *resp
+	return nil
+}
+
+// setReturnedApiHelper copies the response of an *ApiHelper returned by a
+// handler to c.Resp. A nil or zero-value helper (no response attached)
+// sends the default success response.
+func setReturnedApiHelper(c *request.Context, api *response.ApiHelper) error {
+	if api == nil {
+		return c.Api.Ok(nil)
+	}
+	return setReturnedResponse(c, api.Resp())
+}
+
 // buildHandlerMetadata analyzes function signature and builds parameter extractors
 // buildHandlerMetadata extracts metadata about handler function signature
 // OPTIMIZATION: Only builds metadata, doesn't create extractors (they need pathParamNames)
@@ -389,6 +373,12 @@ func invalidHandlerMsg(path string) string {
 		"Note: *Response and *ApiHelper returns allow full control over response (status, headers, body)."
 }
 
+// AdaptHandler converts any handler signature accepted by Router.GET & co.
+// to request.HandlerFunc; name is used in the panic of an unsupported one.
+func AdaptHandler(name string, h any) request.HandlerFunc {
+	return adaptHandler(name, h)
+}
+
 // adaptHandler converts various handler types to request.HandlerFunc.
 // OPTIMIZATION: Fast paths for common signatures avoid reflection overhead.
 // Performance tiers:
@@ -429,11 +419,7 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 			if err != nil {
 				return err
 			}
-			if resp == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *resp
-			return nil
+			return setReturnedResponse(c, resp)
 		}
 
 	// Pattern: func(*Context) (*ApiHelper, error)
@@ -444,11 +430,7 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 			if err != nil {
 				return err
 			}
-			if api == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *api.Resp()
-			return nil
+			return setReturnedApiHelper(c, api)
 		}
 
 	// Pattern: func(*Context) any
@@ -464,11 +446,7 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 	case func(*request.Context) *response.Response:
 		return func(c *request.Context) error {
 			resp := v(c)
-			if resp == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *resp
-			return nil
+			return setReturnedResponse(c, resp)
 		}
 
 	// Pattern: func(*Context) *ApiHelper
@@ -476,11 +454,7 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 	case func(*request.Context) *response.ApiHelper:
 		return func(c *request.Context) error {
 			api := v(c)
-			if api == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *api.Resp()
-			return nil
+			return setReturnedApiHelper(c, api)
 		}
 
 	// ========================================================================
@@ -505,11 +479,7 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 			if err != nil {
 				return err
 			}
-			if resp == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *resp
-			return nil
+			return setReturnedResponse(c, resp)
 		}
 
 	// Pattern: func() (*ApiHelper, error)
@@ -519,11 +489,7 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 			if err != nil {
 				return err
 			}
-			if api == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *api.Resp()
-			return nil
+			return setReturnedApiHelper(c, api)
 		}
 
 	// Pattern: func() any
@@ -537,22 +503,14 @@ func adaptHandler(path string, h any) request.HandlerFunc {
 	case func() *response.Response:
 		return func(c *request.Context) error {
 			resp := v()
-			if resp == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *resp
-			return nil
+			return setReturnedResponse(c, resp)
 		}
 
 	// Pattern: func() *ApiHelper
 	case func() *response.ApiHelper:
 		return func(c *request.Context) error {
 			api := v()
-			if api == nil {
-				return c.Api.Ok(nil)
-			}
-			*c.Resp = *api.Resp()
-			return nil
+			return setReturnedApiHelper(c, api)
 		}
 
 	// Pattern: func() error
@@ -615,12 +573,31 @@ func adaptMiddlewares(mw []any) []any {
 	return adapted
 }
 
+// conditionalMiddleware runs mw (a HandlerFunc or lazy name) only when
+// predicate holds for the request; see Router.UseWhen
+type conditionalMiddleware struct {
+	predicate func(*request.Context) bool
+	mw        any
+}
+
+func (m *conditionalMiddleware) resolve() request.HandlerFunc {
+	mw := resolveMiddlewares([]any{m.mw})[0]
+	return func(c *request.Context) error {
+		if !m.predicate(c) {
+			return c.Next()
+		}
+		return mw(c)
+	}
+}
+
 // resolveMiddlewares converts all string names to HandlerFunc
 // Called during Build() to resolve lazy middleware names
 func resolveMiddlewares(mw []any) []request.HandlerFunc {
 	var resolved []request.HandlerFunc
 	for _, m := range mw {
-		if name, ok := m.(string); ok {
+		if cond, ok := m.(*conditionalMiddleware); ok {
+			resolved = append(resolved, cond.resolve())
+		} else if name, ok := m.(string); ok {
 			// Lazy resolve string name to HandlerFunc
 			if MiddlewareResolver == nil {
 				panic("MiddlewareResolver not set - cannot resolve middleware names")
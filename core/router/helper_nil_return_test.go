@@ -0,0 +1,86 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+)
+
+type nilReturnParams struct {
+	ID string `query:"id"`
+}
+
+// Every handler form returning *Response or *ApiHelper must treat a nil
+// (or zero-value helper) return as the default success response, never panic
+func TestAdaptHandler_NilPointerReturns(t *testing.T) {
+	var (
+		nilResp *response.Response
+		nilApi  *response.ApiHelper
+	)
+
+	handlers := map[string]any{
+		// Tier 1: with context
+		"ctx (*Response, error)":  func(c *request.Context) (*response.Response, error) { return nilResp, nil },
+		"ctx (*ApiHelper, error)": func(c *request.Context) (*response.ApiHelper, error) { return nilApi, nil },
+		"ctx *Response":           func(c *request.Context) *response.Response { return nilResp },
+		"ctx *ApiHelper":          func(c *request.Context) *response.ApiHelper { return nilApi },
+
+		// Tier 1: without context
+		"(*Response, error)":  func() (*response.Response, error) { return nilResp, nil },
+		"(*ApiHelper, error)": func() (*response.ApiHelper, error) { return nilApi, nil },
+		"*Response":           func() *response.Response { return nilResp },
+		"*ApiHelper":          func() *response.ApiHelper { return nilApi },
+
+		// Tier 2: struct parameter, with and without context
+		"ctx params (*Response, error)": func(c *request.Context, p *nilReturnParams) (*response.Response, error) {
+			return nilResp, nil
+		},
+		"ctx params (*ApiHelper, error)": func(c *request.Context, p *nilReturnParams) (*response.ApiHelper, error) {
+			return nilApi, nil
+		},
+		"ctx params *Response":  func(c *request.Context, p *nilReturnParams) *response.Response { return nilResp },
+		"ctx params *ApiHelper": func(c *request.Context, p *nilReturnParams) *response.ApiHelper { return nilApi },
+		"params (*Response, error)": func(p *nilReturnParams) (*response.Response, error) {
+			return nilResp, nil
+		},
+		"params (*ApiHelper, error)": func(p *nilReturnParams) (*response.ApiHelper, error) {
+			return nilApi, nil
+		},
+		"params *Response":  func(p *nilReturnParams) *response.Response { return nilResp },
+		"params *ApiHelper": func(p *nilReturnParams) *response.ApiHelper { return nilApi },
+
+		// Zero-value helpers have no response attached
+		"ctx zero &ApiHelper{}": func(c *request.Context) *response.ApiHelper { return &response.ApiHelper{} },
+		"zero &ApiHelper{}":     func() (*response.ApiHelper, error) { return &response.ApiHelper{}, nil },
+		"params zero &ApiHelper{}": func(p *nilReturnParams) (*response.ApiHelper, error) {
+			return &response.ApiHelper{}, nil
+		},
+		"zero ApiHelper value": func(c *request.Context, p *nilReturnParams) response.ApiHelper {
+			return response.ApiHelper{}
+		},
+		"zero (ApiHelper, error) value": func(p *nilReturnParams) (response.ApiHelper, error) {
+			return response.ApiHelper{}, nil
+		},
+	}
+
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			r := New("test")
+			r.GET("/test", h)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/test?id=1", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), `"status":"success"`) {
+				t.Errorf("expected default success body, got %s", w.Body.String())
+			}
+		})
+	}
+}
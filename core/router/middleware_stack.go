@@ -0,0 +1,156 @@
+package router
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MiddlewareStackBuilder assembles an ordered list of named middleware.
+// Entries are positioned relative to each other by name, so a shared base
+// stack can be adjusted per router without rewriting the whole list.
+// The first error (unknown or duplicate name) is kept and returned by
+// Order/Apply. Builder methods modify the builder they are called on:
+// Clone a shared base before adjusting it.
+type MiddlewareStackBuilder struct {
+	entries []stackEntry
+	err     error
+}
+
+type stackEntry struct {
+	name string
+	mw   any
+}
+
+// MiddlewareStack creates an empty middleware stack builder.
+//
+// Middleware accepts anything Router.Use accepts; nil uses the name as the
+// registered middleware name.
+//
+// Example:
+//
+//	order, err := router.MiddlewareStack().
+//	    Append("request_logger", nil).
+//	    Append("recovery", nil).
+//	    Append("auth", authMiddleware).
+//	    Before("auth", "cors", nil).
+//	    After("auth", "rate_limit", rateLimiter).
+//	    Apply(r)
+//	// order: [request_logger recovery cors auth rate_limit]
+func MiddlewareStack() *MiddlewareStackBuilder {
+	return &MiddlewareStackBuilder{}
+}
+
+// Clone returns an independent copy of the stack (and its error), so
+// stacks derived from one base never change the base or each other.
+//
+// Example:
+//
+//	base := router.MiddlewareStack().Append("request_logger", nil).Append("recovery", nil)
+//	public := base.Clone().Append("cors", nil)
+//	admin := base.Clone().Append("auth", authMiddleware)
+func (s *MiddlewareStackBuilder) Clone() *MiddlewareStackBuilder {
+	return &MiddlewareStackBuilder{entries: slices.Clone(s.entries), err: s.err}
+}
+
+// Append adds middleware at the end of the stack
+func (s *MiddlewareStackBuilder) Append(name string, mw any) *MiddlewareStackBuilder {
+	return s.insert(len(s.entries), name, mw)
+}
+
+// Prepend adds middleware at the start of the stack
+func (s *MiddlewareStackBuilder) Prepend(name string, mw any) *MiddlewareStackBuilder {
+	return s.insert(0, name, mw)
+}
+
+// Before adds middleware right before target
+func (s *MiddlewareStackBuilder) Before(target, name string, mw any) *MiddlewareStackBuilder {
+	if i := s.indexOf(target, "Before"); i >= 0 {
+		s.insert(i, name, mw)
+	}
+	return s
+}
+
+// After adds middleware right after target
+func (s *MiddlewareStackBuilder) After(target, name string, mw any) *MiddlewareStackBuilder {
+	if i := s.indexOf(target, "After"); i >= 0 {
+		s.insert(i+1, name, mw)
+	}
+	return s
+}
+
+// Replace swaps the middleware of target, keeping its name and position
+func (s *MiddlewareStackBuilder) Replace(target string, mw any) *MiddlewareStackBuilder {
+	if i := s.indexOf(target, "Replace"); i >= 0 {
+		s.entries[i].mw = mw
+	}
+	return s
+}
+
+// Remove drops target from the stack
+func (s *MiddlewareStackBuilder) Remove(target string) *MiddlewareStackBuilder {
+	if i := s.indexOf(target, "Remove"); i >= 0 {
+		s.entries = slices.Delete(s.entries, i, i+1)
+	}
+	return s
+}
+
+// Order returns the resolved middleware names, in execution order
+func (s *MiddlewareStackBuilder) Order() ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	names := make([]string, len(s.entries))
+	for i, e := range s.entries {
+		names[i] = e.name
+	}
+	return names, nil
+}
+
+// Middlewares returns the resolved middleware, ready for Router.Use
+func (s *MiddlewareStackBuilder) Middlewares() ([]any, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	mws := make([]any, len(s.entries))
+	for i, e := range s.entries {
+		mws[i] = e.mw
+		if e.mw == nil {
+			mws[i] = e.name
+		}
+	}
+	return mws, nil
+}
+
+// Apply adds the resolved stack to r (a router or group) with r.Use and
+// returns the resolved order. Nothing is applied if the builder has an error.
+func (s *MiddlewareStackBuilder) Apply(r Router) ([]string, error) {
+	mws, err := s.Middlewares()
+	if err != nil {
+		return nil, err
+	}
+	r.Use(mws...)
+	return s.Order()
+}
+
+func (s *MiddlewareStackBuilder) insert(i int, name string, mw any) *MiddlewareStackBuilder {
+	if s.err != nil {
+		return s
+	}
+	if slices.ContainsFunc(s.entries, func(e stackEntry) bool { return e.name == name }) {
+		s.err = fmt.Errorf("middleware stack: duplicate middleware %q", name)
+		return s
+	}
+	s.entries = slices.Insert(s.entries, i, stackEntry{name: name, mw: mw})
+	return s
+}
+
+func (s *MiddlewareStackBuilder) indexOf(target, op string) int {
+	if s.err != nil {
+		return -1
+	}
+	i := slices.IndexFunc(s.entries, func(e stackEntry) bool { return e.name == target })
+	if i < 0 {
+		s.err = fmt.Errorf("middleware stack: %s: middleware %q not found", op, target)
+	}
+	return i
+}
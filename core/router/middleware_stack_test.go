@@ -0,0 +1,142 @@
+package router_test
+
+import (
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func TestMiddlewareStack_Order(t *testing.T) {
+	base := func() *router.MiddlewareStackBuilder {
+		return router.MiddlewareStack().
+			Append("logging", nil).
+			Append("recover", nil).
+			Append("auth", nil)
+	}
+
+	tests := []struct {
+		name     string
+		stack    *router.MiddlewareStackBuilder
+		expected []string
+	}{
+		{
+			name:     "append",
+			stack:    base(),
+			expected: []string{"logging", "recover", "auth"},
+		},
+		{
+			name:     "before and after",
+			stack:    base().Before("auth", "cors", nil).After("auth", "ratelimit", nil),
+			expected: []string{"logging", "recover", "cors", "auth", "ratelimit"},
+		},
+		{
+			name:     "prepend and remove",
+			stack:    base().Prepend("request_id", nil).Remove("recover"),
+			expected: []string{"request_id", "logging", "auth"},
+		},
+		{
+			name:     "replace keeps position",
+			stack:    base().Replace("recover", func(c *request.Context) error { return c.Next() }),
+			expected: []string{"logging", "recover", "auth"},
+		},
+		{
+			name:     "after last",
+			stack:    base().After("auth", "ratelimit", nil).After("auth", "tenant", nil),
+			expected: []string{"logging", "recover", "auth", "tenant", "ratelimit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := tt.stack.Order()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(order, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, order)
+			}
+		})
+	}
+}
+
+func TestMiddlewareStack_Clone(t *testing.T) {
+	// Spare capacity, so an in-place insert would show through a shared slice
+	base := router.MiddlewareStack().
+		Append("logging", nil).
+		Append("recover", nil).
+		Append("auth", nil).
+		Remove("auth")
+
+	public := base.Clone().Append("cors", nil)
+	admin := base.Clone().Append("auth", nil).Before("recover", "audit", nil)
+
+	for name, tt := range map[string]struct {
+		stack    *router.MiddlewareStackBuilder
+		expected []string
+	}{
+		"base":   {base, []string{"logging", "recover"}},
+		"public": {public, []string{"logging", "recover", "cors"}},
+		"admin":  {admin, []string{"logging", "audit", "recover", "auth"}},
+	} {
+		order, err := tt.stack.Order()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(order, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", name, tt.expected, order)
+		}
+	}
+}
+
+func TestMiddlewareStack_Errors(t *testing.T) {
+	_, err := router.MiddlewareStack().Append("auth", nil).Before("cors", "x", nil).Append("y", nil).Order()
+	if err == nil || !strings.Contains(err.Error(), `"cors" not found`) {
+		t.Errorf("Expected unknown target error, got %v", err)
+	}
+
+	_, err = router.MiddlewareStack().Append("auth", nil).Append("auth", nil).Order()
+	if err == nil || !strings.Contains(err.Error(), `duplicate middleware "auth"`) {
+		t.Errorf("Expected duplicate error, got %v", err)
+	}
+
+	r := router.New("test-router")
+	if _, err := router.MiddlewareStack().Replace("auth", nil).Apply(r); err == nil {
+		t.Error("Expected Apply to return the builder error")
+	}
+}
+
+func TestMiddlewareStack_Apply(t *testing.T) {
+	var trace []string
+	mark := func(name string) func(c *request.Context) error {
+		return func(c *request.Context) error {
+			trace = append(trace, name)
+			return c.Next()
+		}
+	}
+
+	r := router.New("test-router")
+	api := r.AddGroup("/api")
+	order, err := router.MiddlewareStack().
+		Append("logging", mark("logging")).
+		Append("auth", mark("auth")).
+		Before("auth", "cors", mark("cors")).
+		Replace("logging", mark("logging-v2")).
+		Apply(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.GET("/ping", func() string { return "pong" })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/ping", nil))
+
+	if expected := []string{"logging", "cors", "auth"}; !slices.Equal(order, expected) {
+		t.Errorf("Expected resolved order %v, got %v", expected, order)
+	}
+	if expected := []string{"logging-v2", "cors", "auth"}; !slices.Equal(trace, expected) {
+		t.Errorf("Expected execution order %v, got %v", expected, trace)
+	}
+}
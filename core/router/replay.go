@@ -0,0 +1,254 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+	"unicode/utf8"
+)
+
+// REDACTED replaces sensitive values in recordings.
+// Replay treats recorded REDACTED values as wildcards when comparing bodies.
+const REDACTED = "[REDACTED]"
+
+// Recording is one recorded request/response exchange (one JSON line in a record file)
+type Recording struct {
+	Time     time.Time         `json:"time"`
+	Request  RecordedRequest   `json:"request"`
+	Response RecordedResponse  `json:"response"`
+	Meta     map[string]string `json:"meta,omitempty"`
+}
+
+// RecordedRequest is the recorded part of an incoming request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"` // path + query
+	Header http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedResponse is the recorded part of the response
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedBody holds a body as text, or base64 for binary content
+type RecordedBody struct {
+	Body         string `json:"body,omitempty"`
+	BodyEncoding string `json:"body_encoding,omitempty"` // "" or "base64"
+	Truncated    bool   `json:"truncated,omitempty"`
+}
+
+// SetBody stores b as text when it is valid UTF-8, otherwise as base64
+func (b *RecordedBody) SetBody(data []byte) {
+	if utf8.Valid(data) {
+		b.Body, b.BodyEncoding = string(data), ""
+		return
+	}
+	b.Body, b.BodyEncoding = base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+// Bytes returns the decoded body
+func (b *RecordedBody) Bytes() []byte {
+	if b.BodyEncoding == "base64" {
+		data, _ := base64.StdEncoding.DecodeString(b.Body)
+		return data
+	}
+	return []byte(b.Body)
+}
+
+// ReplayResult compares a recorded response with the response of the replayed request
+type ReplayResult struct {
+	Recording *Recording
+	Status    int
+	Header    http.Header
+	Body      []byte
+	Match     bool
+	Mismatch  string // reason when Match is false
+}
+
+// LoadRecordings reads a record file (JSON lines) written by the request recorder middleware
+func LoadRecordings(recordFile string) ([]*Recording, error) {
+	f, err := os.Open(recordFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var recs []*Recording
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		rec := &Recording{}
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", recordFile, line, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs, scanner.Err()
+}
+
+// Replay re-runs every recording in recordFile through r in-memory
+// and reports whether each response is equivalent to the recorded one.
+//
+// Example:
+//
+//	results, err := router.Replay(r, "testdata/orders.rec")
+//	for _, res := range results {
+//	    if !res.Match {
+//	        t.Errorf("%s %s: %s", res.Recording.Request.Method, res.Recording.Request.URL, res.Mismatch)
+//	    }
+//	}
+func Replay(r http.Handler, recordFile string) ([]*ReplayResult, error) {
+	recs, err := LoadRecordings(recordFile)
+	if err != nil {
+		return nil, err
+	}
+	return ReplayRecordings(r, recs), nil
+}
+
+// ReplayRecordings re-runs recordings through r in-memory
+func ReplayRecordings(r http.Handler, recs []*Recording) []*ReplayResult {
+	results := make([]*ReplayResult, 0, len(recs))
+	for _, rec := range recs {
+		res := &ReplayResult{Recording: rec}
+		req, err := newReplayRequest(&rec.Request)
+		if err != nil {
+			res.Mismatch = "request: " + err.Error()
+			results = append(results, res)
+			continue
+		}
+
+		w := &replayRecorder{header: make(http.Header)}
+		r.ServeHTTP(w, req)
+
+		res.Status = w.statusCode()
+		res.Header = w.header
+		res.Body = w.body.Bytes()
+		res.Match, res.Mismatch = compareReplay(rec, res)
+		results = append(results, res)
+	}
+	return results
+}
+
+// newReplayRequest builds the in-memory request of a recording, as a
+// server would receive it
+func newReplayRequest(rr *RecordedRequest) (*http.Request, error) {
+	req, err := http.NewRequest(rr.Method, rr.URL, bytes.NewReader(rr.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	req.RequestURI = rr.URL
+	req.RemoteAddr = "192.0.2.1:1234"
+	if req.Host == "" {
+		req.Host = "example.com"
+	}
+	for k, vals := range rr.Header {
+		for _, v := range vals {
+			req.Header.Add(k, v)
+		}
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+	return req, nil
+}
+
+// replayRecorder is the http.ResponseWriter of replayed requests
+type replayRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *replayRecorder) Header() http.Header { return w.header }
+
+func (w *replayRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *replayRecorder) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush lets streamed responses be replayed
+func (w *replayRecorder) Flush() { w.WriteHeader(http.StatusOK) }
+
+func (w *replayRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func compareReplay(rec *Recording, res *ReplayResult) (bool, string) {
+	if rec.Response.Status != res.Status {
+		return false, fmt.Sprintf("status: recorded %d, replayed %d", rec.Response.Status, res.Status)
+	}
+	if rec.Response.Truncated {
+		// Body was not fully recorded, only the status can be compared
+		return true, ""
+	}
+
+	expected := rec.Response.Bytes()
+	if bytes.Equal(expected, res.Body) {
+		return true, ""
+	}
+
+	var expectedJSON, actualJSON any
+	if json.Unmarshal(expected, &expectedJSON) == nil && json.Unmarshal(res.Body, &actualJSON) == nil {
+		if equivalentJSON(expectedJSON, actualJSON) {
+			return true, ""
+		}
+	}
+	return false, fmt.Sprintf("body: recorded %q, replayed %q", expected, res.Body)
+}
+
+// equivalentJSON compares decoded JSON values, treating REDACTED as a wildcard
+func equivalentJSON(expected, actual any) bool {
+	if s, ok := expected.(string); ok && s == REDACTED {
+		return true
+	}
+	switch exp := expected.(type) {
+	case map[string]any:
+		act, ok := actual.(map[string]any)
+		if !ok || len(exp) != len(act) {
+			return false
+		}
+		for k, v := range exp {
+			av, ok := act[k]
+			if !ok || !equivalentJSON(v, av) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		act, ok := actual.([]any)
+		if !ok || len(exp) != len(act) {
+			return false
+		}
+		for i := range exp {
+			if !equivalentJSON(exp[i], act[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
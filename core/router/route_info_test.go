@@ -0,0 +1,54 @@
+package router_test
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func TestRouteInfo_AvailableToMiddleware(t *testing.T) {
+	var name, pattern string
+	var methods []string
+
+	r := router.New("api")
+	users := r.AddGroup("/users")
+	users.Use(func(c *request.Context) error {
+		name, pattern = c.RouteName(), c.RoutePattern()
+		methods = c.Route().Methods
+		return c.Next()
+	})
+	users.GET("/{id}", func(c *request.Context) error {
+		return c.Api.Ok(c.Req.PathParam("id", ""))
+	}, route.WithNameOption("get-user"))
+	users.PUT("/{id}", func(c *request.Context) error {
+		return c.Api.Ok(nil)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/42", nil))
+	if w.Code != 200 {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+
+	// Pattern and name, not the concrete path
+	if pattern != "/users/{id}" {
+		t.Errorf("Expected pattern /users/{id}, got %q", pattern)
+	}
+	if name != "api.get-user" {
+		t.Errorf("Expected name api.get-user, got %q", name)
+	}
+	if !slices.Equal(methods, []string{"GET", "PUT"}) {
+		t.Errorf("Expected methods [GET PUT], got %v", methods)
+	}
+}
+
+func TestRouteInfo_NilSafeAccessors(t *testing.T) {
+	c := request.NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil), nil)
+	if c.Route() != nil || c.RouteName() != "" || c.RoutePattern() != "" {
+		t.Error("Expected no route info outside a router")
+	}
+}
@@ -0,0 +1,109 @@
+package router_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+)
+
+func okHandler(c *request.Context) error { return c.Api.Ok(nil) }
+
+func routePaths(routes []*route.Route) []string {
+	var paths []string
+	for _, rt := range routes {
+		paths = append(paths, rt.Method+" "+rt.FullPath)
+	}
+	return paths
+}
+
+func TestRoutes_FilterByTag(t *testing.T) {
+	r := router.New("api")
+	r.GET("/health", okHandler)
+	r.GET("/products", okHandler, route.WithTags("public"))
+	billing := r.AddGroup("/billing")
+	billing.POST("/invoices", okHandler, route.WithTags("public", "billing"))
+	billing.GET("/ledger", okHandler, route.WithTags("billing"))
+
+	if all := r.Routes(); len(all) != 4 {
+		t.Errorf("Expected 4 routes, got %v", routePaths(all))
+	}
+
+	got := routePaths(r.Routes("billing"))
+	if len(got) != 2 || got[0] != "POST /billing/invoices" || got[1] != "GET /billing/ledger" {
+		t.Errorf("Unexpected billing routes %v", got)
+	}
+
+	got = routePaths(r.Routes("public"))
+	if len(got) != 2 || got[0] != "GET /products" || got[1] != "POST /billing/invoices" {
+		t.Errorf("Unexpected public routes %v", got)
+	}
+
+	// Any of the given tags matches
+	if got := r.Routes("public", "billing"); len(got) != 3 {
+		t.Errorf("Expected 3 routes tagged public or billing, got %v", routePaths(got))
+	}
+	if got := r.Routes("unknown"); len(got) != 0 {
+		t.Errorf("Expected no routes, got %v", routePaths(got))
+	}
+}
+
+func TestWithTags_Deduplicates(t *testing.T) {
+	r := router.New("api")
+	r.GET("/a", okHandler, route.WithTags("x", "y", "x"), route.WithNameOption("a"))
+	if err := r.UpdateRoute("a", route.WithTags("y", "z")); err != nil {
+		t.Fatal(err)
+	}
+
+	rt := r.Routes("x")[0]
+	if len(rt.Tags) != 3 || rt.Tags[0] != "x" || rt.Tags[1] != "y" || rt.Tags[2] != "z" {
+		t.Errorf("Unexpected tags %v", rt.Tags)
+	}
+}
+
+func TestUseForTag(t *testing.T) {
+	var audited []string
+	audit := func(c *request.Context) error {
+		audited = append(audited, c.Route().Path)
+		return c.Next()
+	}
+
+	r := router.New("api")
+	r.UseForTag("billing", audit)
+	r.GET("/products", okHandler, route.WithTags("public"))
+	billing := r.AddGroup("/billing")
+	billing.POST("/invoices", okHandler, route.WithTags("billing"))
+	billing.GET("/summary", okHandler)
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/products"},
+		{"POST", "/billing/invoices"},
+		{"GET", "/billing/summary"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(req.method, req.path, nil))
+		if w.Code != 200 {
+			t.Errorf("%s %s: expected 200, got %d", req.method, req.path, w.Code)
+		}
+	}
+
+	if len(audited) != 1 || audited[0] != "/billing/invoices" {
+		t.Errorf("Expected only /billing/invoices audited, got %v", audited)
+	}
+}
+
+func TestRouteInfo_Tags(t *testing.T) {
+	var tags []string
+	r := router.New("api")
+	r.GET("/x", func(c *request.Context) error {
+		tags = c.Route().Tags
+		return nil
+	}, route.WithTags("internal"))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil))
+	if len(tags) != 1 || tags[0] != "internal" {
+		t.Errorf("Expected route info tags [internal], got %v", tags)
+	}
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 
+	"github.com/primadi/lokstra/core/request"
 	"github.com/primadi/lokstra/core/route"
 )
 
@@ -297,6 +298,16 @@ type Router interface {
 	//  - string (middleware name from config or registry)
 	// e.g. r.Use(middleware...) or r.Use("cors", "recovery")
 	Use(middleware ...any) Router
+	// add middleware(s) that run only when predicate returns true for the
+	// request; otherwise they are skipped and the chain continues.
+	// e.g. r.UseWhen(func(c *request.Context) bool {
+	//          return c.R.Method == "POST" && c.R.URL.Path == "/webhook"
+	//      }, "webhook_signature")
+	UseWhen(predicate func(*request.Context) bool, middleware ...any) Router
+	// add middleware(s) that run only for routes tagged with tag
+	// (see route.WithTags), in this router and its child groups.
+	// e.g. r.UseForTag("billing", "audit_log")
+	UseForTag(tag string, middleware ...any) Router
 
 	// UpdateRoute updates an existing route's configuration by route name.
 	// This can update method, path, and append additional middlewares.
@@ -331,6 +342,10 @@ type Router interface {
 	// fullPath is the complete path including all parent group prefixes
 	// e.g. /v1/admin/stats
 	Walk(fn func(rt *route.Route))
+	// return all routes (including in child groups and chained routers),
+	// or only those tagged with any of tags when given.
+	// e.g. r.Routes("billing")
+	Routes(tags ...string) []*route.Route
 	// Print all routes to stdout for introspection
 	PrintRoutes()
 
@@ -339,6 +354,12 @@ type Router interface {
 	// check if the router has been built
 	IsBuilt() bool
 
+	// collect per-route stats, returned by Stats; call before Build
+	EnableStats() Router
+	// aggregate stats (count, latency quantiles, average body size, status
+	// distribution) of every route, empty unless EnableStats was called
+	Stats() []RouteStats
+
 	// check if the router is part of a chain
 	IsChained() bool
 	// get the next router in the chain, or nil if none
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
@@ -34,6 +35,11 @@ type routerImpl struct {
 
 	// Path rewrite rules (pattern, replacement)
 	pathRewrites []pathRewrite
+
+	// Per-route stats, see EnableStats
+	statsEnabled    bool
+	statsMu         sync.Mutex
+	statsCollectors []*routeStatsCollector
 }
 
 type pathRewrite struct {
@@ -80,7 +86,7 @@ func (r *routerImpl) IsBuilt() bool {
 // Guard: forbid adding routes after build
 func (r *routerImpl) assertNotBuilt() {
 	if r.isBuilt {
-		panic("router: cannot register routes after Build()")
+		panic("router: cannot modify router [" + r.name + "] after Build()")
 	}
 }
 
@@ -94,11 +100,16 @@ func (r *routerImpl) Build() {
 	}
 
 	r.routerEngine = engine.CreateEngine(r.engineType)
+	// Methods registered per pattern, set on RouteInfo once all routes are known
+	methods := map[string][]string{}
+	var routeInfos []*request.RouteInfo
 	r.walkBuildRecursive("", "", nil, r.name,
 		func(rt *route.Route, fullName, fullPath string, fullMiddlewares []request.HandlerFunc, routerName string) {
 			rt.RouterName = routerName // Set the router name for this route
 			rt.FullName = fullName
 			rt.FullPath = fullPath
+			// Config "routes.<name>" overrides code defaults of named routes
+			route.ApplyConfig(rt)
 			if rt.Name == "" {
 				pref := ""
 				if strings.HasSuffix(rt.FullPath, "/") {
@@ -138,9 +149,25 @@ func (r *routerImpl) Build() {
 				rt.FullPath = rewrittenPath
 			}
 
+			info := &request.RouteInfo{Name: rt.FullName, Method: rt.Method, Path: rt.FullPath, Tags: rt.Tags}
+			methods[info.Path] = append(methods[info.Path], rt.Method)
+			routeInfos = append(routeInfos, info)
+
+			chain := fullMw
+			if r.statsEnabled {
+				// Stats collector runs first so it times the whole chain
+				stats := newRouteStatsCollector(rt)
+				r.statsMu.Lock()
+				r.statsCollectors = append(r.statsCollectors, stats)
+				r.statsMu.Unlock()
+				chain = append([]request.HandlerFunc{stats.handle}, fullMw...)
+			}
 			r.routerEngine.Handle(rt.Method+" "+rewrittenPath, request.NewHandler(
-				rt.Handler, fullMw...))
+				rt.Handler, chain...).WithRoute(info))
 		})
+	for _, info := range routeInfos {
+		info.Methods = methods[info.Path]
+	}
 }
 
 // ServeHTTP implements Router.
@@ -170,8 +197,16 @@ func (r *routerImpl) handle(method string, path string, h any, middleware []any)
 		mws = append(mws, mw)
 	}
 
-	rt.Middleware = adaptMiddlewares(mws)
+	// Middlewares added by route options run closest to the handler
+	rt.Middleware = append(adaptMiddlewares(mws), rt.Middleware...)
 	rt.Handler = adaptHandler(path, h)
+	if len(rt.Versions) > 0 {
+		versions := make(map[string]request.HandlerFunc, len(rt.Versions))
+		for v, vh := range rt.Versions {
+			versions[v] = adaptHandler(path, vh)
+		}
+		rt.Handler = route.VersionDispatch(rt.Handler, versions)
+	}
 	r.routes = append(r.routes, rt)
 	return r
 }
@@ -209,6 +244,7 @@ func (r *routerImpl) Clone() Router {
 		middlewares:      r.middlewares,
 		overrideParentMw: r.overrideParentMw,
 		children:         r.children,
+		statsEnabled:     r.statsEnabled,
 		isRoot:           true,
 	}
 }
@@ -286,12 +322,14 @@ func (r *routerImpl) PathPrefix() string {
 
 // SetPathPrefix implements Router.
 func (r *routerImpl) SetPathPrefix(prefix string) Router {
+	r.assertNotBuilt()
 	r.pathPrefix = cleanPath(prefix)
 	return r
 }
 
 // SetPathRewrites sets regex-based path rewrite rules
 func (r *routerImpl) SetPathRewrites(rewrites map[string]string) Router {
+	r.assertNotBuilt()
 	r.pathRewrites = make([]pathRewrite, 0, len(rewrites))
 	for pattern, replacement := range rewrites {
 		r.pathRewrites = append(r.pathRewrites, pathRewrite{
@@ -309,6 +347,7 @@ func (r *routerImpl) SetNextChain(next Router) Router {
 
 // SetNextChain implements Router.
 func (r *routerImpl) SetNextChainWithPrefix(next Router, prefix string) Router {
+	r.assertNotBuilt()
 	curr := r
 	for curr.nextChain != nil {
 		curr = curr.nextChain
@@ -325,10 +364,28 @@ func (r *routerImpl) SetNextChainWithPrefix(next Router, prefix string) Router {
 
 // Use implements Router.
 func (r *routerImpl) Use(middleware ...any) Router {
+	r.assertNotBuilt()
 	r.middlewares = append(r.middlewares, adaptMiddlewares(middleware)...)
 	return r
 }
 
+// UseWhen implements Router.
+func (r *routerImpl) UseWhen(predicate func(*request.Context) bool, middleware ...any) Router {
+	r.assertNotBuilt()
+	for _, mw := range adaptMiddlewares(middleware) {
+		r.middlewares = append(r.middlewares, &conditionalMiddleware{predicate: predicate, mw: mw})
+	}
+	return r
+}
+
+// UseForTag implements Router.
+func (r *routerImpl) UseForTag(tag string, middleware ...any) Router {
+	return r.UseWhen(func(c *request.Context) bool {
+		rt := c.Route()
+		return rt != nil && slices.Contains(rt.Tags, tag)
+	}, middleware...)
+}
+
 // UpdateRoute implements Router.
 func (r *routerImpl) UpdateRoute(name string, options ...any) error {
 	r.assertNotBuilt()
@@ -379,6 +436,7 @@ func (r *routerImpl) UpdateRoute(name string, options ...any) error {
 
 // WithOverrideParentMiddleware implements Router.
 func (r *routerImpl) WithOverrideParentMiddleware(override bool) Router {
+	r.assertNotBuilt()
 	r.overrideParentMw = override
 	return r
 }
@@ -433,6 +491,17 @@ func (r *routerImpl) Walk(fn func(rt *route.Route)) {
 	}
 }
 
+// Routes implements Router.
+func (r *routerImpl) Routes(tags ...string) []*route.Route {
+	var routes []*route.Route
+	r.Walk(func(rt *route.Route) {
+		if len(tags) == 0 || slices.ContainsFunc(tags, rt.HasTag) {
+			routes = append(routes, rt)
+		}
+	})
+	return routes
+}
+
 func (r *routerImpl) PrintRoutes() {
 	r.Build()
 	r.Walk(func(rt *route.Route) {
@@ -449,7 +518,11 @@ func (r *routerImpl) PrintRoutes() {
 		if routerNameDisplay == "" {
 			routerNameDisplay = r.name
 		}
-		logger.LogInfo("[%s] %s %s -> %s%s", routerNameDisplay, rt.Method, rt.FullPath, rt.Name, mwDescr)
+		var tagsDescr string
+		if len(rt.Tags) > 0 {
+			tagsDescr = " {" + strings.Join(rt.Tags, ",") + "}"
+		}
+		logger.LogInfo("[%s] %s %s -> %s%s%s", routerNameDisplay, rt.Method, rt.FullPath, rt.Name, mwDescr, tagsDescr)
 	})
 }
 
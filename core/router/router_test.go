@@ -1,7 +1,10 @@
 package router_test
 
 import (
+	"fmt"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/primadi/lokstra/core/request"
@@ -112,3 +115,133 @@ func TestMiddlewareOrder(t *testing.T) {
 		t.Errorf("Middleware/handler order incorrect: %v", calls)
 	}
 }
+
+func TestSmartBinding_CustomValidator(t *testing.T) {
+	request.RegisterValidator("notblank", func(field string, v reflect.Value, _ string) error {
+		if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" {
+			return fmt.Errorf("%s must not be blank", field)
+		}
+		return nil
+	})
+
+	type createTagRequest struct {
+		Name string `json:"name" validate:"notblank"`
+	}
+
+	r := router.New("root")
+	r.POST("/tags", func(c *request.Context, req *createTagRequest) error {
+		return c.Api.Created(req, "created")
+	})
+
+	for body, status := range map[string]int{`{"name":"  "}`: 400, `{"name":"urgent"}`: 201} {
+		req := httptest.NewRequest("POST", "/tags", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != status {
+			t.Errorf("%s: expected %d, got %d (%s)", body, status, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestUseWhen(t *testing.T) {
+	var checked []string
+	r := router.New("root")
+	r.UseWhen(func(c *request.Context) bool {
+		return c.R.Method == "POST" && c.R.URL.Path == "/webhook"
+	}, func(c *request.Context) error {
+		checked = append(checked, c.R.Method+" "+c.R.URL.Path)
+		if c.R.Header.Get("X-Signature") == "" {
+			return c.Api.Unauthorized("missing signature")
+		}
+		return c.Next()
+	})
+
+	r.POST("/webhook", func(c *request.Context) error { return nil })
+	r.GET("/webhook", func(c *request.Context) error { return nil })
+	r.POST("/orders", func(c *request.Context) error { return nil })
+
+	cases := []struct {
+		method, path string
+		signed       bool
+		wantStatus   int
+	}{
+		{"POST", "/webhook", false, 401},
+		{"POST", "/webhook", true, 200},
+		{"GET", "/webhook", false, 200},
+		{"POST", "/orders", false, 200},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.signed {
+			req.Header.Set("X-Signature", "sig")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s %s (signed=%v): status = %d, want %d",
+				tc.method, tc.path, tc.signed, w.Code, tc.wantStatus)
+		}
+	}
+
+	want := []string{"POST /webhook", "POST /webhook"}
+	if !reflect.DeepEqual(checked, want) {
+		t.Errorf("middleware ran for %v, want %v", checked, want)
+	}
+}
+
+func TestUseWhen_LazyName(t *testing.T) {
+	prev := router.MiddlewareResolver
+	defer func() { router.MiddlewareResolver = prev }()
+
+	var calls []string
+	router.MiddlewareResolver = func(name string) request.HandlerFunc {
+		return func(c *request.Context) error {
+			calls = append(calls, name)
+			return c.Next()
+		}
+	}
+
+	r := router.New("root")
+	r.Use("always")
+	r.UseWhen(func(c *request.Context) bool {
+		return c.R.Header.Get("X-Debug") != ""
+	}, "debug")
+	r.GET("/x", func(c *request.Context) error { return nil })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil))
+	req := httptest.NewRequest("GET", "/x", nil)
+	req.Header.Set("X-Debug", "1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := []string{"always", "always", "debug"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestDirectWriteThenReturnValue(t *testing.T) {
+	r := router.New("root")
+	r.GET("/value", func(c *request.Context) (any, error) {
+		c.W.WriteHeader(201)
+		_, _ = c.W.Write([]byte("direct"))
+		return map[string]any{"ignored": true}, nil
+	})
+	r.GET("/api", func(c *request.Context) error {
+		c.W.Header().Set("Content-Type", "text/plain")
+		_, _ = c.W.Write([]byte("direct"))
+		return c.Api.Ok("ignored")
+	})
+	r.GET("/error", func(c *request.Context) error {
+		_, _ = c.W.Write([]byte("direct"))
+		return fmt.Errorf("too late")
+	})
+
+	for path, wantStatus := range map[string]int{"/value": 201, "/api": 200, "/error": 200} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != wantStatus || w.Body.String() != "direct" {
+			t.Errorf("%s: got %d %q, want %d %q", path, w.Code, w.Body.String(), wantStatus, "direct")
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package router
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// Latency samples kept per route. Quantiles are estimated from a uniform
+// reservoir sample, so memory stays flat regardless of traffic.
+const statsReservoirSize = 1024
+
+// Metric names set by ExportStats
+const (
+	METRIC_ROUTE_REQUESTS      = "route_requests"
+	METRIC_ROUTE_LATENCY_P50   = "route_latency_p50_seconds"
+	METRIC_ROUTE_LATENCY_P95   = "route_latency_p95_seconds"
+	METRIC_ROUTE_LATENCY_P99   = "route_latency_p99_seconds"
+	METRIC_ROUTE_AVG_BODY_SIZE = "route_avg_body_bytes"
+)
+
+// RouteStats is a snapshot of aggregate stats of one route
+type RouteStats struct {
+	Route  string // Route full name
+	Method string
+	Path   string
+
+	Count       int64
+	P50         time.Duration
+	P95         time.Duration
+	P99         time.Duration
+	AvgBodySize float64       // Average response body bytes
+	Status      map[int]int64 // Response count per status code
+}
+
+type routeStatsCollector struct {
+	rt *route.Route
+
+	mu        sync.Mutex
+	count     int64
+	bodyBytes int64
+	status    map[int]int64
+	samples   []time.Duration
+}
+
+func newRouteStatsCollector(rt *route.Route) *routeStatsCollector {
+	return &routeStatsCollector{
+		rt:      rt,
+		status:  make(map[int]int64),
+		samples: make([]time.Duration, 0, 16),
+	}
+}
+
+// handle runs first in the route chain and observes the response once the
+// request is finalized, so the status and size are the ones sent
+func (s *routeStatsCollector) handle(c *request.Context) error {
+	start := time.Now()
+	var err error
+	c.OnFinalize(func() {
+		status := c.StatusCode()
+		if errors.Is(err, request.ErrClientGone) && !c.W.ManualWritten() {
+			status = request.StatusClientClosedRequest
+		}
+		s.observe(status, c.W.BytesWritten(), time.Since(start))
+	})
+	err = c.Next()
+	return err
+}
+
+func (s *routeStatsCollector) observe(status int, bodySize int64, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	s.bodyBytes += bodySize
+	s.status[status]++
+
+	// Reservoir sampling (Algorithm R)
+	if len(s.samples) < statsReservoirSize {
+		s.samples = append(s.samples, latency)
+	} else if i := rand.Int64N(s.count); i < statsReservoirSize {
+		s.samples[i] = latency
+	}
+}
+
+func (s *routeStatsCollector) snapshot() RouteStats {
+	s.mu.Lock()
+	stat := RouteStats{
+		Route:  s.rt.FullName,
+		Method: s.rt.Method,
+		Path:   s.rt.FullPath,
+		Count:  s.count,
+		Status: make(map[int]int64, len(s.status)),
+	}
+	for code, n := range s.status {
+		stat.Status[code] = n
+	}
+	if s.count > 0 {
+		stat.AvgBodySize = float64(s.bodyBytes) / float64(s.count)
+	}
+	samples := slices.Clone(s.samples)
+	s.mu.Unlock()
+
+	slices.Sort(samples)
+	stat.P50 = quantile(samples, 0.50)
+	stat.P95 = quantile(samples, 0.95)
+	stat.P99 = quantile(samples, 0.99)
+	return stat
+}
+
+// quantile returns the q-quantile of sorted samples (nearest rank)
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(q*float64(len(sorted)) + 0.5)
+	return sorted[min(max(idx-1, 0), len(sorted)-1)]
+}
+
+// EnableStats implements Router.
+func (r *routerImpl) EnableStats() Router {
+	r.assertNotBuilt()
+	r.statsEnabled = true
+	return r
+}
+
+// Stats implements Router.
+func (r *routerImpl) Stats() []RouteStats {
+	r.statsMu.Lock()
+	collectors := r.statsCollectors
+	r.statsMu.Unlock()
+
+	stats := make([]RouteStats, 0, len(collectors))
+	for _, s := range collectors {
+		stats = append(stats, s.snapshot())
+	}
+	return stats
+}
+
+// ExportStats sets the current stats of r (see Router.Stats) as gauges on
+// the metrics service, labelled by route, method and path (request counts
+// also by status). Call it periodically, e.g. from a scheduler job.
+func ExportStats(m serviceapi.Metrics, r Router) {
+	for _, stat := range r.Stats() {
+		if stat.Count == 0 {
+			continue
+		}
+		labels := serviceapi.Labels{"route": stat.Route, "method": stat.Method, "path": stat.Path}
+		m.SetGauge(METRIC_ROUTE_LATENCY_P50, stat.P50.Seconds(), labels)
+		m.SetGauge(METRIC_ROUTE_LATENCY_P95, stat.P95.Seconds(), labels)
+		m.SetGauge(METRIC_ROUTE_LATENCY_P99, stat.P99.Seconds(), labels)
+		m.SetGauge(METRIC_ROUTE_AVG_BODY_SIZE, stat.AvgBodySize, labels)
+		for code, n := range stat.Status {
+			statusLabels := serviceapi.Labels{"route": stat.Route, "method": stat.Method,
+				"path": stat.Path, "status": strconv.Itoa(code)}
+			m.SetGauge(METRIC_ROUTE_REQUESTS, float64(n), statusLabels)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package router
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+func findRouteStats(t *testing.T, r Router, name string) RouteStats {
+	t.Helper()
+	for _, s := range r.Stats() {
+		if s.Route == name {
+			return s
+		}
+	}
+	t.Fatalf("no stats for route %s", name)
+	return RouteStats{}
+}
+
+func TestStats_Aggregates(t *testing.T) {
+	r := New("stats-router").EnableStats()
+	r.GET("/items/{id}", func(c *request.Context) error {
+		if c.Req.PathParam("id", "") == "missing" {
+			return c.Api.NotFound("not found")
+		}
+		return c.Resp.Text(strings.Repeat("x", 100))
+	}, route.WithNameOption("get-item"))
+
+	for i := range 10 {
+		id := "1"
+		if i%5 == 0 {
+			id = "missing"
+		}
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items/"+id, nil))
+	}
+
+	stat := findRouteStats(t, r, "stats-router.get-item")
+	if stat.Count != 10 {
+		t.Errorf("Expected count 10, got %d", stat.Count)
+	}
+	if stat.Status[200] != 8 || stat.Status[404] != 2 {
+		t.Errorf("Unexpected status distribution: %v", stat.Status)
+	}
+	// 8 x 100 bytes + 2 small JSON errors
+	if stat.AvgBodySize < 80 || stat.AvgBodySize > 120 {
+		t.Errorf("Unexpected average body size: %.1f", stat.AvgBodySize)
+	}
+	if stat.P50 <= 0 || stat.P50 > stat.P95 || stat.P95 > stat.P99 {
+		t.Errorf("Unexpected quantiles: p50=%s p95=%s p99=%s", stat.P50, stat.P95, stat.P99)
+	}
+}
+
+func TestStats_QuantilesBoundedMemory(t *testing.T) {
+	s := newRouteStatsCollector(&route.Route{Name: "synthetic"})
+
+	// 100k synthetic requests, latency uniformly 1..1000 ms
+	for i := range 100_000 {
+		s.observe(200, 10, time.Duration(i%1000+1)*time.Millisecond)
+	}
+
+	if len(s.samples) != statsReservoirSize {
+		t.Fatalf("Expected reservoir bounded at %d, got %d", statsReservoirSize, len(s.samples))
+	}
+
+	stat := s.snapshot()
+	expect := map[string][2]time.Duration{
+		"p50": {stat.P50, 500 * time.Millisecond},
+		"p95": {stat.P95, 950 * time.Millisecond},
+		"p99": {stat.P99, 990 * time.Millisecond},
+	}
+	for name, v := range expect {
+		got, want := v[0], v[1]
+		if diff := got - want; diff < -60*time.Millisecond || diff > 60*time.Millisecond {
+			t.Errorf("%s: expected ~%s, got %s", name, want, got)
+		}
+	}
+	if stat.AvgBodySize != 10 {
+		t.Errorf("Expected avg body size 10, got %.1f", stat.AvgBodySize)
+	}
+}
+
+type gaugeRecorder struct {
+	serviceapi.Metrics
+	gauges map[string]float64
+}
+
+func (g *gaugeRecorder) SetGauge(name string, value float64, labels serviceapi.Labels) {
+	if labels["route"] == "export-router.ping" {
+		g.gauges[name+"/"+labels["status"]] = value
+	}
+}
+
+func TestStats_Export(t *testing.T) {
+	r := New("export-router").EnableStats()
+	r.GET("/ping", func(c *request.Context) error {
+		return c.Api.Ok("pong")
+	}, route.WithNameOption("ping"))
+	for range 3 {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ping", nil))
+	}
+
+	m := &gaugeRecorder{gauges: map[string]float64{}}
+	ExportStats(m, r)
+
+	if m.gauges[METRIC_ROUTE_REQUESTS+"/200"] != 3 {
+		t.Errorf("Expected 3 requests with status 200, got %v", m.gauges)
+	}
+	if _, ok := m.gauges[METRIC_ROUTE_LATENCY_P99+"/"]; !ok {
+		t.Errorf("Expected p99 gauge, got %v", m.gauges)
+	}
+}
+
+func TestStats_OptIn(t *testing.T) {
+	r := New("plain-router")
+	r.GET("/ping", func(c *request.Context) error {
+		return c.Api.Ok("pong")
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ping", nil))
+
+	if stats := r.Stats(); len(stats) != 0 {
+		t.Errorf("Expected no stats without EnableStats, got %v", stats)
+	}
+	for _, rt := range r.Routes() {
+		if len(rt.FullMiddleware) != 0 {
+			t.Errorf("Expected no middleware on %s, got %d", rt.FullName, len(rt.FullMiddleware))
+		}
+	}
+}
+
+func TestStats_ObservesFinalizedResponse(t *testing.T) {
+	r := New("final-router").EnableStats()
+	// Outer middleware changes the status after the handler returned
+	r.Use(func(c *request.Context) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		return c.Api.Error(503, "UNAVAILABLE", "replaced")
+	})
+	r.GET("/ping", func(c *request.Context) error {
+		return c.Api.Ok("pong")
+	}, route.WithNameOption("ping"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	if w.Code != 503 {
+		t.Fatalf("Expected the outer middleware's 503, got %d", w.Code)
+	}
+	if stat := findRouteStats(t, r, "final-router.ping"); stat.Status[503] != 1 {
+		t.Errorf("Expected the sent status recorded, got %v", stat.Status)
+	}
+}
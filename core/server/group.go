@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/app"
+)
+
+// Group runs several servers in one process, e.g. to simulate a
+// microservice deployment locally or in tests, with a single graceful
+// shutdown path for all of them.
+type Group struct {
+	Servers []*Server
+}
+
+// Create a new Group of the given servers
+func NewGroup(servers ...*Server) *Group {
+	return &Group{Servers: servers}
+}
+
+// Start starts all servers and blocks until they all stop.
+// Two apps of the group listening on the same address is an error
+// returned before any server starts.
+func (g *Group) Start() error {
+	errCh, err := g.start()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// start checks addresses and starts each server in its own goroutine.
+// The returned channel yields the error of each server that failed and
+// is closed once all servers stopped.
+func (g *Group) start() (<-chan error, error) {
+	if err := g.checkAddresses(); err != nil {
+		return nil, err
+	}
+
+	errCh := make(chan error, len(g.Servers))
+	var wg sync.WaitGroup
+	for _, s := range g.Servers {
+		wg.Go(func() {
+			if err := s.Start(); err != nil {
+				errCh <- fmt.Errorf("server '%s': %w", s.Name, err)
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(errCh)
+	}()
+	return errCh, nil
+}
+
+// checkAddresses reports apps of different servers listening on the same
+// address (apps of one server sharing an address are merged by build)
+func (g *Group) checkAddresses() error {
+	type owner struct {
+		server string
+		app    *app.App
+	}
+	owners := make(map[string]owner)
+	for _, s := range g.Servers {
+		s.build()
+		for _, a := range s.Apps {
+			addr := a.GetAddress()
+			if _, port, err := net.SplitHostPort(addr); err == nil && port == "0" {
+				continue // random port
+			}
+			if prev, ok := owners[addr]; ok {
+				return fmt.Errorf("address conflict: app '%s' of server '%s' and app '%s' of server '%s' both listen on %s",
+					prev.app.GetName(), prev.server, a.GetName(), s.Name, addr)
+			}
+			owners[addr] = owner{server: s.Name, app: a}
+		}
+	}
+	return nil
+}
+
+// Shutdown gracefully shuts down all servers concurrently within the given
+// timeout, then the registered services once.
+func (g *Group) Shutdown(timeout time.Duration) error {
+	startShutdown()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(g.Servers))
+	for _, s := range g.Servers {
+		wg.Go(func() {
+			if err := s.shutdownApps(timeout); err != nil {
+				errCh <- fmt.Errorf("server '%s': %w", s.Name, err)
+			}
+		})
+	}
+	wg.Wait()
+	close(errCh)
+
+	finishShutdown()
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Starts all servers and blocks until a termination signal is received or
+// a server fails (e.g. its port is in use), then shuts all of them down
+// gracefully with the given timeout.
+func (g *Group) Run(timeout time.Duration) error {
+	errCh, err := g.start()
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	select {
+	case sig := <-stop:
+		logger.LogInfo("Received shutdown signal: %v", sig)
+		if err := g.Shutdown(timeout); err != nil {
+			return fmt.Errorf("shutdown error: %w", err)
+		}
+		return nil
+	case err, ok := <-errCh:
+		if !ok {
+			return nil // all servers stopped
+		}
+		if shutdownErr := g.Shutdown(timeout); shutdownErr != nil {
+			return errors.Join(err, fmt.Errorf("shutdown error: %w", shutdownErr))
+		}
+		return err
+	}
+}
@@ -16,11 +16,20 @@ import (
 // Callback to shutdown services - set by registry to avoid circular dependency
 var shutdownServicesCallback func()
 
+// Callback run before apps stop accepting traffic - set by registry
+var beforeShutdownCallback func()
+
 // SetShutdownServicesCallback allows registry to set the callback function
 func SetShutdownServicesCallback(callback func()) {
 	shutdownServicesCallback = callback
 }
 
+// SetBeforeShutdownCallback allows registry to set a callback run before
+// the apps are shut down (e.g. failing readiness checks)
+func SetBeforeShutdownCallback(callback func()) {
+	beforeShutdownCallback = callback
+}
+
 type Server struct {
 	Name         string
 	BaseUrl      string // Base URL of the server
@@ -140,6 +149,14 @@ func (s *Server) Shutdown(timeout any) error {
 
 // Internal shutdown method with time.Duration
 func (s *Server) shutdown(timeout time.Duration) error {
+	startShutdown()
+	err := s.shutdownApps(timeout)
+	finishShutdown()
+	return err
+}
+
+// shutdownApps gracefully shuts down all apps concurrently
+func (s *Server) shutdownApps(timeout time.Duration) error {
 	var wg sync.WaitGroup
 
 	errCh := make(chan error, len(s.Apps))
@@ -159,11 +176,6 @@ func (s *Server) shutdown(timeout time.Duration) error {
 	wg.Wait()
 	close(errCh)
 
-	// Shutdown any remaining services via callback to avoid circular dependency
-	if shutdownServicesCallback != nil {
-		shutdownServicesCallback()
-	}
-
 	var errs []error
 	for err := range errCh {
 		if err != nil {
@@ -176,24 +188,42 @@ func (s *Server) shutdown(timeout time.Duration) error {
 	return nil
 }
 
-// Starts the server and blocks until a termination signal is received.
-// It shuts down gracefully with the given timeout.
+// startShutdown runs before the apps are shut down
+func startShutdown() {
+	if beforeShutdownCallback != nil {
+		beforeShutdownCallback()
+	}
+}
+
+// finishShutdown runs once all apps stopped
+func finishShutdown() {
+	// Shutdown any remaining services via callback to avoid circular dependency
+	if shutdownServicesCallback != nil {
+		shutdownServicesCallback()
+	}
+
+	// Write buffered log lines (async log output) before the process exits
+	_ = logger.Flush()
+}
+
+// Starts the server and blocks until a termination signal is received,
+// then shuts down gracefully with the given timeout. It also returns once
+// the server was shut down by a call to Shutdown.
 func (s *Server) Run(timeout time.Duration) error {
 	// Run server in background
 	errCh := make(chan error, 1)
 	go func() {
-		if err := s.Start(); err != nil {
-			errCh <- err
-		}
+		errCh <- s.Start()
 	}()
 
-	// Wait for signal or server error
+	// Wait for signal, server error, or the server being shut down elsewhere
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
 
 	select {
 	case sig := <-stop:
-		logger.LogInfo("Received shutdown signal:", sig)
+		logger.LogInfo("Received shutdown signal: %v", sig)
 		if err := s.shutdown(timeout); err != nil {
 			return fmt.Errorf("shutdown error: %w", err)
 		}
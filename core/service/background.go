@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/internal/registry"
+)
+
+// Context is a minimal execution context for code that runs outside an HTTP
+// request (scheduler jobs, CLI tasks, queue consumers). It provides service
+// resolution and a correlation-id-bearing logger, so job handlers can call
+// repositories and services the same way handlers do, without a nil
+// *request.Context.
+//
+// Example usage:
+//
+//	func CleanupJob() error {
+//	    ctx := service.BackgroundContext()
+//	    repo, _ := service.GetService[UserRepository](ctx, "user-repository")
+//	    ctx.Log.Info("removing inactive users")
+//	    return repo.DeleteInactive(ctx)
+//	}
+type Context struct {
+	// Embedding standard context, carries the correlation id as its
+	// request id (see request.Correlation)
+	context.Context
+
+	// CorrelationID identifies this execution in logs
+	CorrelationID string
+
+	// Log prefixes every message with the correlation id
+	Log *Logger
+}
+
+// BackgroundContext creates a Context with a new correlation id
+func BackgroundContext() *Context {
+	return NewContext(context.Background(), "")
+}
+
+// NewContext creates a Context derived from parent (e.g. for cancellation).
+// If correlationID is empty, the request id of parent is reused (so work
+// started from a handler logs with the id of its request) or a new one is
+// generated.
+func NewContext(parent context.Context, correlationID string) *Context {
+	if correlationID == "" {
+		correlationID = CorrelationID(parent)
+	}
+	if correlationID == "" {
+		correlationID = uuid.NewString()
+	}
+
+	return &Context{
+		Context:       request.WithRequestID(parent, correlationID),
+		CorrelationID: correlationID,
+		Log:           &Logger{id: correlationID},
+	}
+}
+
+// CorrelationID returns the correlation id carried by ctx, or "" if none.
+// It is the request id of request.Correlation, so HTTP requests and
+// background work share one id.
+func CorrelationID(ctx context.Context) string {
+	return request.RequestIDFromContext(ctx)
+}
+
+// GetServiceAny resolves a service from the global registry
+func (c *Context) GetServiceAny(name string) (any, bool) {
+	reg := registry.Global()
+	if reg == nil {
+		return nil, false
+	}
+	return reg.GetServiceAny(name)
+}
+
+// GetService resolves a service with type assertion.
+// Returns (zero, false) if not found or type mismatch.
+func GetService[T any](c *Context, name string) (T, bool) {
+	var zero T
+	svc, ok := c.GetServiceAny(name)
+	if !ok {
+		c.Log.Debug("service '%s' not found", name)
+		return zero, false
+	}
+	typed, ok := svc.(T)
+	if !ok {
+		c.Log.Warn("service '%s' is %T, not the requested type", name, svc)
+		return zero, false
+	}
+	return typed, true
+}
+
+// Logger writes to the global logger with a correlation id prefix
+type Logger struct {
+	id string
+}
+
+// The id is an argument, not part of the format: ids may contain '%'
+func (l *Logger) Debug(format string, args ...any) { logger.LogDebug("[%s] "+format, l.with(args)...) }
+func (l *Logger) Info(format string, args ...any)  { logger.LogInfo("[%s] "+format, l.with(args)...) }
+func (l *Logger) Warn(format string, args ...any)  { logger.LogWarn("[%s] "+format, l.with(args)...) }
+func (l *Logger) Error(format string, args ...any) { logger.LogError("[%s] "+format, l.with(args)...) }
+
+func (l *Logger) with(args []any) []any {
+	return append([]any{l.id}, args...)
+}
@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/service"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+// captureBackend records log messages
+type captureBackend struct {
+	logger.LoggerBackend
+	messages []string
+}
+
+func (b *captureBackend) Info(format string, args ...any) {
+	b.messages = append(b.messages, fmt.Sprintf(format, args...))
+}
+
+func TestBackgroundContext_ResolvesService(t *testing.T) {
+	_ = deploy.Global()
+	lokstra_registry.RegisterService("bg-test-service", &TestService{Name: "bg"})
+
+	ctx := service.BackgroundContext()
+
+	svc, ok := service.GetService[*TestService](ctx, "bg-test-service")
+	if !ok || svc.Name != "bg" {
+		t.Fatalf("expected service to resolve, got %v, %v", svc, ok)
+	}
+
+	if _, ok := service.GetService[*TestService](ctx, "bg-missing-service"); ok {
+		t.Error("expected missing service not to resolve")
+	}
+}
+
+func TestBackgroundContext_CorrelationLogger(t *testing.T) {
+	capture := &captureBackend{LoggerBackend: logger.NewSlogBackend()}
+	logger.SetBackend(capture)
+	defer logger.SetBackend(capture.LoggerBackend)
+
+	ctx := service.BackgroundContext()
+	if ctx.CorrelationID == "" {
+		t.Fatal("expected generated correlation id")
+	}
+	if service.CorrelationID(ctx) != ctx.CorrelationID {
+		t.Error("expected correlation id to be carried by the context")
+	}
+
+	ctx.Log.Info("cleanup %d users", 3)
+	if len(capture.messages) != 1 ||
+		!strings.Contains(capture.messages[0], ctx.CorrelationID) ||
+		!strings.HasSuffix(capture.messages[0], "cleanup 3 users") {
+		t.Errorf("expected correlation-id-bearing log, got %v", capture.messages)
+	}
+
+	// Child context keeps the id; a fresh background context gets a new one
+	child := service.NewContext(ctx, "")
+	if child.CorrelationID != ctx.CorrelationID {
+		t.Errorf("expected child to inherit %q, got %q", ctx.CorrelationID, child.CorrelationID)
+	}
+	if service.BackgroundContext().CorrelationID == ctx.CorrelationID {
+		t.Error("expected unique correlation ids")
+	}
+	if id := service.NewContext(context.Background(), "job-42").CorrelationID; id != "job-42" {
+		t.Errorf("expected explicit correlation id, got %q", id)
+	}
+
+	// Ids are arguments, never part of the format
+	capture.messages = nil
+	service.NewContext(context.Background(), "job-%d%s").Log.Info("done %d", 7)
+	if len(capture.messages) != 1 || capture.messages[0] != "[job-%d%s] done 7" {
+		t.Errorf("expected the id logged verbatim, got %v", capture.messages)
+	}
+
+	// Work started from a request logs with the request id
+	reqCtx := request.WithRequestID(context.Background(), "req-7")
+	job := service.NewContext(reqCtx, "")
+	if job.CorrelationID != "req-7" || request.CorrelationFromContext(job).RequestID != "req-7" {
+		t.Errorf("expected the request id reused, got %q", job.CorrelationID)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/request"
+)
+
+// Handle is a typed reference to a service registered with Define. It
+// replaces service-name strings and type assertions at call sites.
+type Handle[T any] struct {
+	name string
+}
+
+// Define registers a service type named name with its local and remote
+// factories (remote may be nil) and returns a typed handle to it.
+// The service name equals the type name, so in YAML the definition is
+// "name: {type: name}"; whether the local or the remote factory is used
+// follows the deployment topology, as with RegisterRouterServiceType.
+// Panics if name is already registered. The untyped registry API keeps
+// working for the same service.
+//
+// Example:
+//
+//	var UserService = service.Define[contract.UserService]("user-service",
+//	    UserServiceFactory, UserServiceRemoteFactory)
+//
+//	// at the call site: no name string, no type assertion
+//	user, err := UserService.Get(ctx).GetByID(id)
+func Define[T any](name string, local, remote any) *Handle[T] {
+	deploy.Global().RegisterRouterServiceType(name, local, remote, nil)
+	return &Handle[T]{name: name}
+}
+
+// Name returns the registry name of the service
+func (h *Handle[T]) Name() string {
+	return h.name
+}
+
+// Get returns the service instance, or the zero value if it is not
+// available. If ctx carries a tenant (see middleware/tenant) and
+// "<name>@<tenant>" is registered, that instance is returned instead.
+func (h *Handle[T]) Get(ctx context.Context) T {
+	svc, _ := h.TryGet(ctx)
+	return svc
+}
+
+// TryGet returns the service instance and whether it is available
+func (h *Handle[T]) TryGet(ctx context.Context) (T, bool) {
+	if tenant := request.TenantFromContext(ctx); tenant != "" {
+		if svc, ok := h.lookup(h.name + "@" + tenant); ok {
+			return svc, true
+		}
+	}
+	return h.lookup(h.name)
+}
+
+// MustGet returns the service instance or panics if it is not available
+func (h *Handle[T]) MustGet(ctx context.Context) T {
+	svc, ok := h.TryGet(ctx)
+	if !ok {
+		if err := deploy.Global().GetServiceError(h.name); err != nil {
+			panic("service " + h.name + " not available: " + err.Error())
+		}
+		panic("service " + h.name + " not found or type mismatch")
+	}
+	return svc
+}
+
+// Lazy returns a Cached loader of the service, for struct fields that are
+// resolved on first use
+func (h *Handle[T]) Lazy() *Cached[T] {
+	return LazyLoad[T](h.name)
+}
+
+func (h *Handle[T]) lookup(name string) (T, bool) {
+	if svc, ok := deploy.Global().GetServiceAny(name); ok {
+		if typed, ok := svc.(T); ok {
+			return typed, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/deploy/schema"
+	"github.com/primadi/lokstra/core/proxy"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/service"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type localGreeter struct{ name string }
+
+func (g *localGreeter) Greet() string { return "local " + g.name }
+
+type remoteGreeter struct{ proxy *proxy.Service }
+
+func (g *remoteGreeter) Greet() string { return "remote" }
+
+func newLocalGreeter(cfg map[string]any) any {
+	name, _ := cfg["name"].(string)
+	return &localGreeter{name: name}
+}
+
+func newRemoteGreeter(_, cfg map[string]any) any {
+	return &remoteGreeter{proxy: cfg["remote"].(*proxy.Service)}
+}
+
+func TestDefine_ResolvesLocal(t *testing.T) {
+	h := service.Define[greeter]("define-local-greeter", newLocalGreeter, newRemoteGreeter)
+	if h.Name() != "define-local-greeter" {
+		t.Fatalf("name = %q", h.Name())
+	}
+
+	g := h.Get(context.Background())
+	if _, ok := g.(*localGreeter); !ok {
+		t.Fatalf("expected local variant, got %T", g)
+	}
+	if got := g.Greet(); got != "local " {
+		t.Errorf("Greet() = %q", got)
+	}
+
+	// The untyped API resolves the same instance
+	if untyped := lokstra_registry.GetService[greeter]("define-local-greeter"); untyped != g {
+		t.Error("untyped GetService returned a different instance")
+	}
+}
+
+func TestDefine_ResolvesLocalWithConfig(t *testing.T) {
+	h := service.Define[greeter]("define-config-greeter", newLocalGreeter, nil)
+	lokstra_registry.RegisterLazyService("define-config-greeter", "define-config-greeter",
+		map[string]any{"name": "bob"})
+
+	if got := h.MustGet(context.Background()).Greet(); got != "local bob" {
+		t.Errorf("Greet() = %q", got)
+	}
+}
+
+func TestDefine_ResolvesRemote(t *testing.T) {
+	h := service.Define[greeter]("define-remote-greeter", newLocalGreeter, newRemoteGreeter)
+
+	// What the deployment does for a service published on another server
+	deploy.Global().AutoRegisterRemoteService("define-remote-greeter",
+		&schema.ServiceDef{Type: "define-remote-greeter"}, "http://orders.internal:8080")
+
+	g := h.Get(context.Background())
+	rg, ok := g.(*remoteGreeter)
+	if !ok {
+		t.Fatalf("expected remote variant, got %T", g)
+	}
+	if rg.proxy == nil {
+		t.Error("remote variant did not receive the proxy")
+	}
+}
+
+func TestDefine_TenantVariant(t *testing.T) {
+	h := service.Define[greeter]("define-tenant-greeter", newLocalGreeter, nil)
+	acme := &localGreeter{name: "acme"}
+	lokstra_registry.RegisterService(lokstra_registry.TenantServiceName("define-tenant-greeter", "acme"), acme)
+
+	ctx := request.WithTenant(context.Background(), "acme")
+	if g := h.Get(ctx); g != acme {
+		t.Errorf("expected tenant instance, got %v", g)
+	}
+
+	other := request.WithTenant(context.Background(), "other")
+	if g := h.Get(other); g == acme || g == nil {
+		t.Errorf("expected shared instance for unknown tenant, got %v", g)
+	}
+}
+
+func TestDefine_TypeMismatch(t *testing.T) {
+	h := service.Define[*remoteGreeter]("define-mismatch-greeter", newLocalGreeter, nil)
+
+	if _, ok := h.TryGet(context.Background()); ok {
+		t.Error("TryGet succeeded for a mismatched type")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGet did not panic")
+		}
+	}()
+	h.MustGet(context.Background())
+}
+
+func TestDefine_DuplicatePanics(t *testing.T) {
+	service.Define[greeter]("define-dup-greeter", newLocalGreeter, nil)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate Define")
+		}
+	}()
+	service.Define[greeter]("define-dup-greeter", newLocalGreeter, nil)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/primadi/lokstra"
+	"github.com/primadi/lokstra/core/request"
 )
 
 type User struct {
@@ -130,6 +131,20 @@ type SearchProductsRequest struct {
 	Limit    int     `query:"limit" default:"10"` // With default value
 }
 
+// Cross-field rule: max_price must not be lower than min_price
+func init() {
+	request.RegisterStructRule(func(r *SearchProductsRequest) []request.FieldError {
+		if r.MinPrice > 0 && r.MaxPrice > 0 && r.MaxPrice < r.MinPrice {
+			return []request.FieldError{{
+				Field:   "max_price",
+				Code:    "RANGE",
+				Message: "max_price must be greater than or equal to min_price",
+			}}
+		}
+		return nil
+	})
+}
+
 func searchProducts(req *SearchProductsRequest) ([]Product, error) {
 	result := []Product{}
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/primadi/lokstra"
 	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/middleware/scopes"
 )
 
 // Simple auth middleware - checks for API key in header
@@ -20,22 +21,18 @@ func authMiddleware(ctx *request.Context) error {
 		return ctx.Api.Forbidden("Invalid API key")
 	}
 
-	// Authentication successful - continue to handler
-	return ctx.Next()
-}
-
-// Admin-only middleware - checks for admin role
-func adminMiddleware(ctx *request.Context) error {
-	role := ctx.Req.HeaderParam("X-User-Role", "")
-
-	if role != "admin" {
-		return ctx.Api.Forbidden("Admin access required")
+	// Authentication successful - set principal and continue to handler
+	var roles []string
+	if role := ctx.Req.HeaderParam("X-User-Role", ""); role != "" {
+		roles = append(roles, role)
 	}
-
-	// Authorization successful - continue to handler
+	ctx.SetPrincipal(&request.Principal{ID: apiKey, Scopes: roles})
 	return ctx.Next()
 }
 
+// Admin-only middleware - requires the admin scope on the principal
+var adminMiddleware = scopes.RequireScopes("admin")
+
 func main() {
 	router := lokstra.NewRouter("api")
 
@@ -0,0 +1,157 @@
+package lokstra_handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// FingerprintLength is the number of hex characters of the content hash
+// inserted into fingerprinted asset names
+const FingerprintLength = 12
+
+// ImmutableCacheControl is sent for fingerprinted assets: their URL changes
+// whenever their content changes, so they can be cached forever.
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// FingerprintedAssets serves static files under content-addressed URLs
+// for cache-busting. Behavior:
+//
+//	asset("app.js")          -> "/static/app.3f2a9c1b7d4e.js"
+//	/static/app.3f2a9c1b7d4e.js -> serve "app.js" with far-future Cache-Control
+//	/static/app.js           -> serve "app.js" with Cache-Control: no-cache
+//	not found                -> 404
+//
+// Fingerprints are computed once from fsys (typically an embed.FS);
+// call Reload after the files changed on disk.
+type FingerprintedAssets struct {
+	prefix string
+	fsys   fs.FS
+
+	mu       sync.RWMutex
+	urls     map[string]string // logical name -> fingerprinted name
+	original map[string]string // fingerprinted name -> logical name
+}
+
+var _ http.Handler = (*FingerprintedAssets)(nil)
+
+// MountFingerprinted creates fingerprinted asset serving for all files in fsys,
+// mounted under stripPrefix.
+func MountFingerprinted(stripPrefix string, fsys fs.FS) (*FingerprintedAssets, error) {
+	if stripPrefix != "" {
+		stripPrefix = "/" + strings.Trim(stripPrefix, "/")
+	}
+	a := &FingerprintedAssets{prefix: stripPrefix, fsys: fsys}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Fingerprint returns the content hash used in fingerprinted names
+func Fingerprint(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:FingerprintLength]
+}
+
+// FingerprintedName inserts fingerprint before the extension of name,
+// e.g. "js/app.js" -> "js/app.<fingerprint>.js"
+func FingerprintedName(name, fingerprint string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + fingerprint + ext
+}
+
+// Reload recomputes the fingerprints of all files
+func (a *FingerprintedAssets) Reload() error {
+	urls := make(map[string]string)
+	original := make(map[string]string)
+
+	err := fs.WalkDir(a.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		fp, err := fingerprintFile(a.fsys, name)
+		if err != nil {
+			return err
+		}
+		hashed := FingerprintedName(name, fp)
+		urls[name] = hashed
+		original[hashed] = name
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.urls, a.original = urls, original
+	a.mu.Unlock()
+	return nil
+}
+
+func fingerprintFile(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:FingerprintLength], nil
+}
+
+// URL resolves a logical asset name (e.g. "app.js") to its fingerprinted URL.
+// Unknown names resolve to their plain URL.
+func (a *FingerprintedAssets) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	a.mu.RLock()
+	hashed, ok := a.urls[name]
+	a.mu.RUnlock()
+	if !ok {
+		hashed = name
+	}
+	return a.prefix + "/" + hashed
+}
+
+// FuncMap returns template functions for server-rendered pages:
+//
+//	<script src="{{ asset "app.js" }}"></script>
+func (a *FingerprintedAssets) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": a.URL}
+}
+
+func (a *FingerprintedAssets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutPrefix(r.URL.Path, a.prefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	name = strings.TrimPrefix(name, "/")
+
+	a.mu.RLock()
+	logical, fingerprinted := a.original[name]
+	_, known := a.urls[name]
+	a.mu.RUnlock()
+
+	switch {
+	case fingerprinted:
+		w.Header().Set("Cache-Control", ImmutableCacheControl)
+		name = logical
+	case known:
+		// Plain URLs still work but must be revalidated
+		w.Header().Set("Cache-Control", "no-cache")
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFileFS(w, r, a.fsys, name)
+}
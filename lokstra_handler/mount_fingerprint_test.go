@@ -0,0 +1,101 @@
+package lokstra_handler
+
+import (
+	"bytes"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFingerprint_StableForUnchangedContent(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("console.log(1)")}}
+
+	a1, err := MountFingerprinted("/static", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, err := MountFingerprinted("/static/", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1.URL("app.js") != a2.URL("app.js") {
+		t.Errorf("Expected stable URL, got %s and %s", a1.URL("app.js"), a2.URL("app.js"))
+	}
+
+	expected := "/static/app." + Fingerprint([]byte("console.log(1)")) + ".js"
+	if got := a1.URL("app.js"); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestFingerprint_ChangesWhenContentChanges(t *testing.T) {
+	fsys := fstest.MapFS{"js/app.js": {Data: []byte("v1")}}
+	a, err := MountFingerprinted("/static", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := a.URL("js/app.js")
+
+	fsys["js/app.js"] = &fstest.MapFile{Data: []byte("v2")}
+	if err := a.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	after := a.URL("js/app.js")
+
+	if before == after {
+		t.Errorf("Expected URL to change with content, got %s twice", after)
+	}
+	if !strings.HasPrefix(after, "/static/js/app.") || !strings.HasSuffix(after, ".js") {
+		t.Errorf("Unexpected fingerprinted URL %s", after)
+	}
+}
+
+func TestFingerprintedAssets_Serve(t *testing.T) {
+	fsys := fstest.MapFS{"app.css": {Data: []byte("body{}")}}
+	a, err := MountFingerprinted("/static", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest("GET", a.URL("app.css"), nil))
+	if w.Code != 200 || w.Body.String() != "body{}" {
+		t.Fatalf("Expected asset content, got %d %q", w.Code, w.Body.String())
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != ImmutableCacheControl {
+		t.Errorf("Expected far-future Cache-Control, got %q", cc)
+	}
+
+	w = httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest("GET", "/static/app.css", nil))
+	if w.Code != 200 || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("Expected plain URL served with no-cache, got %d %q", w.Code, w.Header().Get("Cache-Control"))
+	}
+
+	w = httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest("GET", "/static/app.000000000000.css", nil))
+	if w.Code != 404 {
+		t.Errorf("Expected 404 for stale fingerprint, got %d", w.Code)
+	}
+}
+
+func TestFingerprintedAssets_TemplateHelper(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("x")}}
+	a, err := MountFingerprinted("/assets", fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := template.Must(template.New("page").Funcs(a.FuncMap()).
+		Parse(`<script src="{{ asset "app.js" }}"></script>`))
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<script src="` + a.URL("app.js") + `"></script>`
+	if buf.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, buf.String())
+	}
+}
@@ -0,0 +1,247 @@
+// Package webhook receives signed webhook deliveries (Stripe, GitHub, ...)
+// exactly once: the signature is verified, replays are rejected by
+// timestamp and by event ID, and the body is parsed into a typed event
+// before the handler runs.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/common/json"
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/middleware/signature"
+)
+
+// Default header carrying the event ID, when Config.EventID is not set
+const DefaultIDHeader = "X-Webhook-ID"
+
+// Default time an event ID is remembered
+const DefaultTTL = 24 * time.Hour
+
+// Default time a claimed event may be processing before another delivery
+// can claim it again (e.g. after the processing instance crashed)
+const DefaultProcessingTTL = 10 * time.Minute
+
+// Retry-After (seconds) of deliveries of an event still processing
+const processingRetryAfter = "30"
+
+// ClaimState is the result of Store.Claim
+type ClaimState int
+
+const (
+	// Claimed: the caller owns the event and must Complete or Release it
+	Claimed ClaimState = iota
+	// Processing: another delivery of the event is being processed
+	Processing
+	// Done: the event was already processed
+	Done
+)
+
+// Store remembers claimed and processed event IDs. Implementations must
+// make Claim atomic, so concurrent deliveries of one event are processed once.
+type Store interface {
+	// Claim records id as processing for at most lease, reporting
+	// Processing or Done if it is already recorded
+	Claim(ctx context.Context, id string, lease time.Duration) (ClaimState, error)
+	// Complete records id as processed for ttl
+	Complete(ctx context.Context, id string, ttl time.Duration) error
+	// Release forgets id, so a failed delivery is processed when retried
+	Release(ctx context.Context, id string) error
+}
+
+type Config[T any] struct {
+	// Signature verification, see middleware/signature. Set TimestampHeader
+	// to also reject deliveries outside the tolerance window.
+	Signature *signature.Config
+
+	// Store of processed event IDs (default: NewMemoryStore)
+	Store Store
+
+	// How long event IDs are remembered (default: DefaultTTL). Keep it
+	// longer than the provider retries deliveries.
+	TTL time.Duration
+
+	// How long a delivery may be processing before its claim expires
+	// (default: DefaultProcessingTTL). Keep it longer than the handler runs.
+	ProcessingTTL time.Duration
+
+	// Header carrying the event ID (default: DefaultIDHeader),
+	// used when EventID is nil
+	IDHeader string
+
+	// EventID returns the ID of a parsed event, for providers sending it in
+	// the body (e.g. Stripe's "id")
+	EventID func(event *T) string
+}
+
+// Receiver handles deliveries of one webhook endpoint, see NewReceiver
+type Receiver[T any] struct {
+	cfg    Config[T]
+	handle func(c *request.Context, event *T) error
+}
+
+// NewReceiver creates a Receiver calling handle once per event. Responses:
+//
+//   - 401 for a missing, invalid or stale signature
+//   - 400 for a body that is not a T, or a missing event ID
+//   - 409 with Retry-After, without calling handle, while another
+//     delivery of the event is processing
+//   - 200 without calling handle for an already processed event
+//   - handle's response, or 200 if it sets none
+//
+// When handle returns an error the event ID is released, so the
+// provider's retry is processed again.
+//
+// Example:
+//
+//	type PushEvent struct {
+//	    Ref        string          `json:"ref"`
+//	    Repository json.RawMessage `json:"repository"`
+//	}
+//
+//	github := webhook.NewReceiver(&webhook.Config[PushEvent]{
+//	    Signature: &signature.Config{
+//	        Secrets: []string{secret},
+//	        Header:  "X-Hub-Signature-256",
+//	        Prefix:  "sha256=",
+//	    },
+//	    IDHeader: "X-GitHub-Delivery",
+//	}, func(c *request.Context, e *PushEvent) error {
+//	    return builds.Trigger(c, e)
+//	})
+//	r.POST("/webhooks/github", github.Handle)
+func NewReceiver[T any](cfg *Config[T], handle func(c *request.Context, event *T) error) *Receiver[T] {
+	rc := &Receiver[T]{handle: handle}
+	if cfg != nil {
+		rc.cfg = *cfg
+	}
+	if rc.cfg.Store == nil {
+		rc.cfg.Store = NewMemoryStore()
+	}
+	if rc.cfg.TTL <= 0 {
+		rc.cfg.TTL = DefaultTTL
+	}
+	if rc.cfg.ProcessingTTL <= 0 {
+		rc.cfg.ProcessingTTL = DefaultProcessingTTL
+	}
+	if rc.cfg.IDHeader == "" {
+		rc.cfg.IDHeader = DefaultIDHeader
+	}
+	return rc
+}
+
+// Handle is the request handler of the webhook endpoint
+func (rc *Receiver[T]) Handle(c *request.Context) error {
+	body, err := c.Req.RawRequestBody()
+	if err != nil {
+		return c.Api.BadRequest("INVALID_BODY", "Failed to read request body")
+	}
+	if err := signature.Verify(rc.cfg.Signature, c.R.Header, body); err != nil {
+		return signature.WriteError(c, err)
+	}
+
+	event := new(T)
+	if err := json.Unmarshal(body, event); err != nil {
+		return c.Api.BadRequest("INVALID_PAYLOAD", "Webhook payload is not valid: "+err.Error())
+	}
+
+	id := c.R.Header.Get(rc.cfg.IDHeader)
+	if rc.cfg.EventID != nil {
+		id = rc.cfg.EventID(event)
+	}
+	if id == "" {
+		return c.Api.BadRequest("MISSING_EVENT_ID", "Webhook event ID is missing")
+	}
+
+	state, err := rc.cfg.Store.Claim(c, id, rc.cfg.ProcessingTTL)
+	if err != nil {
+		return err
+	}
+	switch state {
+	case Processing:
+		// Not acknowledged: the first delivery may still fail and release
+		// the event, so the provider must retry
+		c.W.Header().Set("Retry-After", processingRetryAfter)
+		return c.Api.Error(http.StatusConflict, "EVENT_PROCESSING", "Webhook event is being processed")
+	case Done:
+		// Acknowledged, so the provider stops retrying
+		return c.Api.Ok(map[string]any{"id": id, "duplicate": true})
+	}
+
+	if err := rc.handle(c, event); err != nil {
+		if relErr := rc.cfg.Store.Release(c, id); relErr != nil {
+			err = errors.Join(err, relErr)
+		}
+		return err
+	}
+	if err := rc.cfg.Store.Complete(c, id, rc.cfg.TTL); err != nil {
+		// Processed: acknowledge anyway, the claim expires after ProcessingTTL
+		logger.LogWarn("webhook: failed to complete event %s: %v", id, err)
+	}
+	if !c.Resp.HasOutput() && !c.ResponseStarted() {
+		return c.Api.Ok(map[string]any{"id": id, "duplicate": false})
+	}
+	return nil
+}
+
+// NewMemoryStore returns a Store keeping event IDs in process memory,
+// for single-instance deployments and tests
+func NewMemoryStore() Store {
+	return &memoryStore{seen: make(map[string]memoryEntry), now: time.Now}
+}
+
+type memoryEntry struct {
+	state  ClaimState // Processing or Done
+	expiry time.Time
+}
+
+type memoryStore struct {
+	mu   sync.Mutex
+	seen map[string]memoryEntry
+	now  func() time.Time
+}
+
+// Claim implements Store.
+func (s *memoryStore) Claim(_ context.Context, id string, lease time.Duration) (ClaimState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if e, ok := s.seen[id]; ok && now.Before(e.expiry) {
+		return e.state, nil
+	}
+	s.seen[id] = memoryEntry{state: Processing, expiry: now.Add(lease)}
+
+	// Drop expired IDs now and then, keeping the map bounded
+	if len(s.seen)%1024 == 0 {
+		for k, e := range s.seen {
+			if !now.Before(e.expiry) {
+				delete(s.seen, k)
+			}
+		}
+	}
+	return Claimed, nil
+}
+
+// Complete implements Store.
+func (s *memoryStore) Complete(_ context.Context, id string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[id] = memoryEntry{state: Done, expiry: s.now().Add(ttl)}
+	return nil
+}
+
+// Release implements Store.
+func (s *memoryStore) Release(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.seen, id)
+	return nil
+}
+
+var _ Store = (*memoryStore)(nil)
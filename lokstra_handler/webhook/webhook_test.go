@@ -0,0 +1,191 @@
+package webhook_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_handler/webhook"
+	"github.com/primadi/lokstra/middleware/signature"
+)
+
+const secret = "whsec_test"
+
+type paymentEvent struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Amount int    `json:"amount"`
+}
+
+func newRouter(cfg *webhook.Config[paymentEvent], handle func(*request.Context, *paymentEvent) error) router.Router {
+	r := router.New("webhook-router")
+	r.POST("/webhooks/payments", webhook.NewReceiver(cfg, handle).Handle)
+	return r
+}
+
+func deliver(r router.Router, body, sig, id string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/webhooks/payments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", sig)
+	if id != "" {
+		req.Header.Set(webhook.DefaultIDHeader, id)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestReceiver_FirstAndDuplicateDelivery(t *testing.T) {
+	var received []paymentEvent
+	r := newRouter(&webhook.Config[paymentEvent]{
+		Signature: &signature.Config{Secrets: []string{secret}},
+	}, func(c *request.Context, e *paymentEvent) error {
+		received = append(received, *e)
+		return nil
+	})
+
+	body := `{"type":"payment.succeeded","amount":1500}`
+	sig := signature.Sign(secret, "", []byte(body))
+
+	w := deliver(r, body, sig, "evt_1")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"duplicate":false`) {
+		t.Fatalf("First delivery: expected 200, got %d %s", w.Code, w.Body.String())
+	}
+	if len(received) != 1 || received[0].Type != "payment.succeeded" || received[0].Amount != 1500 {
+		t.Fatalf("Unexpected parsed events: %+v", received)
+	}
+
+	w = deliver(r, body, sig, "evt_1")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"duplicate":true`) {
+		t.Errorf("Duplicate delivery: expected acknowledged 200, got %d %s", w.Code, w.Body.String())
+	}
+	if len(received) != 1 {
+		t.Errorf("Duplicate delivery must not be processed again, got %d events", len(received))
+	}
+}
+
+func TestReceiver_BadSignature(t *testing.T) {
+	called := false
+	r := newRouter(&webhook.Config[paymentEvent]{
+		Signature: &signature.Config{Secrets: []string{secret}},
+	}, func(c *request.Context, e *paymentEvent) error {
+		called = true
+		return nil
+	})
+
+	body := `{"type":"payment.succeeded"}`
+	for name, sig := range map[string]string{
+		"wrong secret": signature.Sign("other", "", []byte(body)),
+		"missing":      "",
+	} {
+		w := deliver(r, body, sig, "evt_2")
+		if w.Code != 401 {
+			t.Errorf("%s: expected 401, got %d %s", name, w.Code, w.Body.String())
+		}
+	}
+	if called {
+		t.Error("Handler must not run for an unverified delivery")
+	}
+
+	// A rejected delivery does not claim the event ID
+	w := deliver(r, body, signature.Sign(secret, "", []byte(body)), "evt_2")
+	if w.Code != 200 || !called {
+		t.Errorf("Valid delivery after rejections: expected processed 200, got %d", w.Code)
+	}
+}
+
+func TestReceiver_StaleTimestamp(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	r := newRouter(&webhook.Config[paymentEvent]{
+		Signature: &signature.Config{
+			Secrets:         []string{secret},
+			TimestampHeader: "X-Timestamp",
+			Now:             func() time.Time { return now },
+		},
+	}, func(c *request.Context, e *paymentEvent) error { return nil })
+
+	body := `{"type":"payment.succeeded"}`
+	old := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	req := httptest.NewRequest("POST", "/webhooks/payments", strings.NewReader(body))
+	req.Header.Set("X-Signature", signature.Sign(secret, old, []byte(body)))
+	req.Header.Set("X-Timestamp", old)
+	req.Header.Set(webhook.DefaultIDHeader, "evt_3")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 401 || !strings.Contains(w.Body.String(), "STALE_SIGNATURE") {
+		t.Errorf("Expected 401 STALE_SIGNATURE, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReceiver_FailedDeliveryIsRetried(t *testing.T) {
+	attempts := 0
+	r := newRouter(&webhook.Config[paymentEvent]{
+		Signature: &signature.Config{Secrets: []string{secret}},
+		EventID:   func(e *paymentEvent) string { return e.ID },
+	}, func(c *request.Context, e *paymentEvent) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("ledger unavailable")
+		}
+		return nil
+	})
+
+	body := `{"id":"evt_4","type":"payment.succeeded"}`
+	sig := signature.Sign(secret, "", []byte(body))
+
+	if w := deliver(r, body, sig, ""); w.Code != 500 {
+		t.Fatalf("Failed processing: expected 500, got %d", w.Code)
+	}
+	if w := deliver(r, body, sig, ""); w.Code != 200 || attempts != 2 {
+		t.Errorf("Retry: expected processed 200, got %d after %d attempts", w.Code, attempts)
+	}
+
+	if w := deliver(r, `{"type":"payment.succeeded"}`,
+		signature.Sign(secret, "", []byte(`{"type":"payment.succeeded"}`)), ""); w.Code != 400 {
+		t.Errorf("Missing event ID: expected 400, got %d", w.Code)
+	}
+}
+
+func TestReceiver_RetryWhileProcessing(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	r := newRouter(&webhook.Config[paymentEvent]{
+		Signature: &signature.Config{Secrets: []string{secret}},
+	}, func(c *request.Context, e *paymentEvent) error {
+		calls++
+		close(started)
+		<-release
+		return nil
+	})
+
+	body := `{"type":"payment.succeeded"}`
+	sig := signature.Sign(secret, "", []byte(body))
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- deliver(r, body, sig, "evt_5") }()
+	<-started
+
+	// Not acknowledged while the first delivery may still fail
+	w := deliver(r, body, sig, "evt_5")
+	if w.Code != 409 || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Retry while processing: expected 409 with Retry-After, got %d %s", w.Code, w.Body.String())
+	}
+
+	close(release)
+	if w := <-first; w.Code != 200 {
+		t.Fatalf("First delivery: expected 200, got %d", w.Code)
+	}
+	w = deliver(r, body, sig, "evt_5")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"duplicate":true`) {
+		t.Errorf("Retry after processing: expected acknowledged 200, got %d %s", w.Code, w.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("Expected the event processed once, got %d", calls)
+	}
+}
@@ -0,0 +1,110 @@
+package lokstra_registry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/route"
+)
+
+// frameworkConfigKeys are optional keys (and their children) read by the
+// framework, some only after startup or per request (e.g. shutdown_timeout,
+// maintenance.enabled), so they are never reported
+var frameworkConfigKeys = []string{
+	"server", "shutdown_timeout", CONFIG_START_INFO_FORMAT, CONFIG_START_INFO_ROUTES, "metrics_service",
+	"runtime.mode", strings.TrimSuffix(route.CONFIG_ROUTES_PREFIX, "."), "maintenance",
+}
+
+// isFrameworkConfigKey reports whether key is or is under a framework key
+func isFrameworkConfigKey(key string) bool {
+	for _, fk := range frameworkConfigKeys {
+		if key == fk || strings.HasPrefix(key, fk+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConfigUsage returns the config keys set but not read so far and the
+// keys read with their default value, without the framework's own keys
+func GetConfigUsage() *deploy.ConfigUsage {
+	usage := deploy.Global().ConfigUsage()
+	usage.Unused = slices.DeleteFunc(usage.Unused, isFrameworkConfigKey)
+	usage.Defaulted = slices.DeleteFunc(usage.Defaulted, func(d deploy.ConfigDefault) bool {
+		return isFrameworkConfigKey(d.Key)
+	})
+	return usage
+}
+
+// ReportUnusedConfig returns a warning per config key set but not read so
+// far, which is often a typo, and per key read with its default value:
+//
+//	config key 'databse-url' set but never read (did you mean 'database-url'?)
+//	config key 'database-url' not set, using default ""
+//
+// Keys read lazily (e.g. in a handler) are reported until first read, so
+// the report is most useful right after startup; PrintServerStartInfo
+// includes it. Use deploy.Global().MarkConfigRead for such keys.
+func ReportUnusedConfig() []string {
+	usage := GetConfigUsage()
+	warnings := []string{}
+	for _, key := range usage.Unused {
+		msg := fmt.Sprintf("config key '%s' set but never read", key)
+		if similar := similarConfigKey(key, usage.Defaulted); similar != "" {
+			msg += fmt.Sprintf(" (did you mean '%s'?)", similar)
+		}
+		warnings = append(warnings, msg)
+	}
+	for _, d := range usage.Defaulted {
+		if d.SetType != "" {
+			warnings = append(warnings, fmt.Sprintf("config key '%s' is a %s, using default %s (%T)",
+				d.Key, d.SetType, formatConfigDefault(d.Default), d.Default))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("config key '%s' not set, using default %s",
+			d.Key, formatConfigDefault(d.Default)))
+	}
+	return warnings
+}
+
+func formatConfigDefault(v any) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// similarConfigKey returns the defaulted key closest to key, within an
+// edit distance of 2, or "" if none
+func similarConfigKey(key string, defaulted []deploy.ConfigDefault) string {
+	best, bestDist := "", 3
+	for _, d := range defaulted {
+		if dist := editDistance(key, d.Key); dist < bestDist {
+			best, bestDist = d.Key, dist
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance of a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,81 @@
+package lokstra_registry_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+// reportFor returns the warnings mentioning a key with prefix
+func reportFor(prefix string) []string {
+	var warnings []string
+	for _, w := range lokstra_registry.ReportUnusedConfig() {
+		if strings.Contains(w, "'"+prefix) {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+func TestReportUnusedConfig(t *testing.T) {
+	lokstra_registry.SetConfig("usage-test.databse-url", "postgres://db")
+	lokstra_registry.SetConfig("usage-test.port", "8080")
+	lokstra_registry.SetConfig("usage-test.cache", map[string]any{"ttl": "5m", "size": 100})
+
+	lokstra_registry.GetConfig("usage-test.database-url", "")
+	lokstra_registry.GetConfig("usage-test.port", 9090)
+	lokstra_registry.GetConfig("usage-test.cache.ttl", "1m")
+
+	want := []string{
+		"config key 'usage-test.cache.size' set but never read",
+		"config key 'usage-test.databse-url' set but never read (did you mean 'usage-test.database-url'?)",
+		`config key 'usage-test.database-url' not set, using default ""`,
+		"config key 'usage-test.port' is a string, using default 9090 (int)",
+	}
+	if got := reportFor("usage-test."); !slices.Equal(got, want) {
+		t.Errorf("Unexpected warnings:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestReportUnusedConfig_ParentAndChildReads(t *testing.T) {
+	lokstra_registry.SetConfig("usage-parent.db", map[string]any{"dsn": "x", "schema": "public"})
+	lokstra_registry.SetConfig("usage-child.db", map[string]any{"dsn": "x"})
+	lokstra_registry.SetConfig("usage-unread.db", map[string]any{"dsn": "x", "schema": "public"})
+
+	// Reading a map uses its keys, reading a key uses its map
+	lokstra_registry.GetConfig[map[string]any]("usage-parent.db", nil)
+	lokstra_registry.GetConfig("usage-child.db.dsn", "")
+
+	if got := reportFor("usage-parent."); len(got) != 0 {
+		t.Errorf("Expected no warnings for a map read as a whole, got %q", got)
+	}
+	if got := reportFor("usage-child."); len(got) != 0 {
+		t.Errorf("Expected no warnings for a map with a read key, got %q", got)
+	}
+	// Only the outermost unused key is reported
+	want := []string{"config key 'usage-unread.db' set but never read"}
+	if got := reportFor("usage-unread"); !slices.Equal(got, want) {
+		t.Errorf("Unexpected warnings:\n got %q\nwant %q", got, want)
+	}
+
+	lokstra_registry.Global().MarkConfigRead("usage-unread")
+	if got := reportFor("usage-unread"); len(got) != 0 {
+		t.Errorf("Expected no warnings after MarkConfigRead, got %q", got)
+	}
+}
+
+func TestReportUnusedConfig_OptionalAndFrameworkKeys(t *testing.T) {
+	// Optional lookups (nil default) and framework keys are not reported
+	lokstra_registry.GetConfig[map[string]any]("usage-optional.db", nil)
+	lokstra_registry.GetConfig[any]("routes.usage-route", nil)
+	lokstra_registry.GetConfig("maintenance.enabled", false)
+
+	for _, w := range lokstra_registry.ReportUnusedConfig() {
+		if strings.Contains(w, "'usage-optional.") || strings.Contains(w, "'routes.") ||
+			strings.Contains(w, "'maintenance.") {
+			t.Errorf("Unexpected warning: %s", w)
+		}
+	}
+}
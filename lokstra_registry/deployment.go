@@ -158,15 +158,34 @@ func PrintCurrentServerInfo() error {
 
 // runCurrentServer builds and runs the current server based on deployment config
 func runCurrentServer(timeout time.Duration) error {
+	coreServer, err := buildCurrentServer(nil)
+	if err != nil {
+		return err
+	}
+	printStartInfo(coreServer)
+	logger.LogInfo("Press CTRL+C to stop the server...")
+
+	// Delegate to coreServer.Run() - no code duplication!
+	return coreServer.Run(timeout)
+}
+
+// buildCurrentServer builds the current server based on deployment config.
+// Services in inProcess are registered local even if the topology marks
+// them remote, as their server runs in this process (see StartServers).
+// mwOpts apply to the middleware definitions registered for the server.
+func buildCurrentServer(inProcess map[string]bool, mwOpts ...deploy.MiddlewareNameOption) (*server.Server, error) {
 	if currentCompositeKey == "" {
-		return fmt.Errorf("no server set - call SetCurrentServer first")
+		return nil, fmt.Errorf("no server set - call SetCurrentServer first")
 	}
 
 	// Get server topology from Global registry
 	registry := deploy.Global()
 	serverTopo, ok := registry.GetServerTopology(currentCompositeKey)
 	if !ok {
-		return fmt.Errorf("server topology '%s' not found in global registry", currentCompositeKey)
+		return nil, fmt.Errorf("server topology '%s' not found in global registry", currentCompositeKey)
+	}
+	if len(inProcess) > 0 {
+		serverTopo = withInProcessServices(serverTopo, inProcess)
 	}
 
 	// Extract deployment and server names from composite key
@@ -183,14 +202,14 @@ func runCurrentServer(timeout time.Duration) error {
 		// This updates the config structure (moves inline definitions to global with normalized names)
 		err := loader.NormalizeInlineDefinitionsForServer(config, deploymentName, serverName)
 		if err != nil {
-			return fmt.Errorf("failed to normalize inline definitions: %w", err)
+			return nil, fmt.Errorf("failed to normalize inline definitions: %w", err)
 		}
 
 		// Perform runtime registration of all definitions (global + normalized inline)
 		// This registers middlewares, services (with remote/local logic), and auto-generates routers
-		err = loader.RegisterDefinitionsForRuntime(registry, config, deploymentName, serverName, serverTopo)
+		err = loader.RegisterDefinitionsForRuntime(registry, config, deploymentName, serverName, serverTopo, mwOpts...)
 		if err != nil {
-			return fmt.Errorf("failed to register definitions for runtime: %w", err)
+			return nil, fmt.Errorf("failed to register definitions for runtime: %w", err)
 		}
 
 		logger.LogDebug("📝 Normalized and registered definitions for server %s.%s", deploymentName, serverName)
@@ -198,7 +217,7 @@ func runCurrentServer(timeout time.Duration) error {
 
 	// Get apps from topology
 	if len(serverTopo.Apps) == 0 {
-		return fmt.Errorf("server '%s' has no apps configured", serverName)
+		return nil, fmt.Errorf("server '%s' has no apps configured", serverName)
 	}
 
 	// Build one core app per AppTopology and collect them
@@ -206,7 +225,7 @@ func runCurrentServer(timeout time.Duration) error {
 	for i, appTopo := range serverTopo.Apps {
 		// Build routers for this app
 		if len(appTopo.Routers) == 0 {
-			return fmt.Errorf("app %d has no routers configured", i+1)
+			return nil, fmt.Errorf("app %d has no routers configured", i+1)
 		}
 
 		var routers []router.Router
@@ -214,7 +233,7 @@ func runCurrentServer(timeout time.Duration) error {
 			// Get router from registry (must be explicitly registered)
 			r := GetRouter(routerName)
 			if r == nil {
-				return fmt.Errorf("router '%s' not found in registry - routers must be explicitly registered via code or annotation", routerName)
+				return nil, fmt.Errorf("router '%s' not found in registry - routers must be explicitly registered via code or annotation", routerName)
 			}
 
 			// Apply overrides from router-definitions (if exists)
@@ -289,18 +308,28 @@ func runCurrentServer(timeout time.Duration) error {
 
 		// Apply handler configurations from YAML (reverse-proxies, mount-spa, mount-static)
 		if err := applyAppHandlerConfigurations(coreApp, config, deploymentName, serverName, i); err != nil {
-			return fmt.Errorf("failed to apply handler configurations to app %d: %w", i+1, err)
+			return nil, fmt.Errorf("failed to apply handler configurations to app %d: %w", i+1, err)
 		}
 
 		coreApps = append(coreApps, coreApp)
 	}
 
-	// Create core Server and run (delegates to core/server/server.go)
 	coreServer := server.New(serverName, coreApps...)
-	coreServer.PrintStartInfo()
+	trackServer(currentCompositeKey, coreServer)
+	return coreServer, nil
+}
 
-	// Delegate to coreServer.Run() - no code duplication!
-	return coreServer.Run(timeout)
+// withInProcessServices returns a copy of topo without the remote services
+// in inProcess
+func withInProcessServices(topo *deploy.ServerTopology, inProcess map[string]bool) *deploy.ServerTopology {
+	filtered := *topo
+	filtered.RemoteServices = make(map[string]string, len(topo.RemoteServices))
+	for name, url := range topo.RemoteServices {
+		if !inProcess[name] {
+			filtered.RemoteServices[name] = url
+		}
+	}
+	return &filtered
 }
 
 // applyAppHandlerConfigurations applies handler configurations (reverse-proxies, mount-spa, mount-static) to an app
@@ -413,3 +442,73 @@ func RunServer(compositeKey string, timeout time.Duration) error {
 	// Run the server
 	return runCurrentServer(timeout)
 }
+
+// StartServers runs several servers of the deployment config in this
+// process, e.g. to simulate a microservice deployment locally or in tests,
+// and blocks until a termination signal is received or a server fails.
+// All servers are then shut down gracefully (shutdown_timeout config,
+// default 30s), followed by the services once.
+//
+// Names use the RunServer format ("deployment.server" or "server").
+// Services are shared by all servers: a service published by one of them
+// is called in-process by the others, instead of through HTTP.
+// Two apps listening on the same address are reported before any
+// server starts.
+//
+// Example:
+//
+//	lokstra_registry.StartServers("microservice.user-server", "microservice.order-server")
+func StartServers(names ...string) error {
+	timeout, err := time.ParseDuration(GetConfig("shutdown_timeout", "30s"))
+	if err != nil {
+		timeout = 30 * time.Second
+	}
+	group, err := BuildServers(names...)
+	if err != nil {
+		return err
+	}
+	logger.LogInfo("Press CTRL+C to stop the servers...")
+	return group.Run(timeout)
+}
+
+// BuildServers builds the named servers as a server.Group without starting
+// them, see StartServers. Use group.Start and group.Shutdown to control
+// the servers, e.g. in tests.
+func BuildServers(names ...string) (*server.Group, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no servers to start")
+	}
+
+	keys := make([]string, len(names))
+	inProcess := make(map[string]bool)
+	for i, name := range names {
+		if err := SetCurrentServer(name); err != nil {
+			return nil, err
+		}
+		keys[i] = currentCompositeKey
+		topo, _ := deploy.Global().GetServerTopology(currentCompositeKey)
+		for _, svc := range topo.Services {
+			inProcess[svc] = true
+		}
+	}
+
+	group := server.NewGroup()
+	for i, key := range keys {
+		if err := SetCurrentServer(key); err != nil {
+			return nil, err
+		}
+		// The first server registers the middleware definitions; the others
+		// register the same definitions again
+		var mwOpts []deploy.MiddlewareNameOption
+		if i > 0 {
+			mwOpts = append(mwOpts, deploy.WithAllowOverrideForName(true))
+		}
+		coreServer, err := buildCurrentServer(inProcess, mwOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("server '%s': %w", key, err)
+		}
+		printStartInfo(coreServer)
+		group.Servers = append(group.Servers, coreServer)
+	}
+	return group, nil
+}
@@ -15,6 +15,8 @@ import (
 // Config keys used:
 //   - server: Server composite key "deployment.server" (optional, uses first if not specified)
 //   - shutdown_timeout: Graceful shutdown timeout duration (optional, default: 30s)
+//   - start_info_format: Startup summary format, "text" or "json" (optional, default: text)
+//   - start_info_routes: List every route in the startup summary (optional, default: false)
 //
 // Example:
 //
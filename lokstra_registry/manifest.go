@@ -0,0 +1,107 @@
+package lokstra_registry
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/router"
+)
+
+// Manifest lists everything a feature module registers, so the module
+// exposes one value instead of a setupFactories function.
+//
+// Example:
+//
+//	// modules/billing/module.go
+//	var Manifest = lokstra_registry.Manifest{
+//	    Name: "billing",
+//	    Services: map[string]any{
+//	        "invoice-service-factory": NewInvoiceService,
+//	        "payment-gateway-factory": NewPaymentGateway,
+//	    },
+//	    Middleware: map[string]any{
+//	        "billing-auth": NewBillingAuthMiddleware,
+//	    },
+//	    Routers: map[string]func() router.Router{
+//	        "billing-router": NewBillingRouter,
+//	    },
+//	}
+//
+//	// main.go
+//	lokstra_registry.MustRegisterModuleManifest(billing.Manifest, shipping.Manifest)
+type Manifest struct {
+	// Name identifies the module in collision errors
+	Name string
+	// Services maps service types to factories (see RegisterServiceType)
+	Services map[string]any
+	// Middleware maps middleware types to factories (see RegisterMiddlewareFactory)
+	Middleware map[string]any
+	// Routers maps router names to lazy factories (see RegisterRouterFactory)
+	Routers map[string]func() router.Router
+}
+
+// RegisterModuleManifest registers the services, middleware and routers of
+// the given manifests. Names are validated first, against the registry and
+// across the manifests; on any collision nothing is registered and the
+// error lists every collision.
+func RegisterModuleManifest(manifests ...Manifest) error {
+	reg := deploy.Global()
+	owners := map[string]string{} // "kind name" -> module
+	var collisions []string
+
+	check := func(m Manifest, kind string, names []string, exists func(string) bool) {
+		for _, name := range names {
+			key := kind + " " + name
+			if owner, ok := owners[key]; ok {
+				collisions = append(collisions, fmt.Sprintf("%s %q registered by both %s and %s",
+					kind, name, owner, moduleName(m)))
+				continue
+			}
+			owners[key] = moduleName(m)
+			if exists(name) {
+				collisions = append(collisions, fmt.Sprintf("%s %q of %s is already registered",
+					kind, name, moduleName(m)))
+			}
+		}
+	}
+	for _, m := range manifests {
+		check(m, "service type", slices.Sorted(maps.Keys(m.Services)), reg.HasServiceType)
+		check(m, "middleware type", slices.Sorted(maps.Keys(m.Middleware)), func(name string) bool {
+			return reg.GetMiddlewareFactory(name) != nil
+		})
+		check(m, "router", slices.Sorted(maps.Keys(m.Routers)), reg.HasRouter)
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("module manifest name collision:\n  - %s", strings.Join(collisions, "\n  - "))
+	}
+
+	for _, m := range manifests {
+		for name, factory := range m.Services {
+			RegisterServiceType(name, factory)
+		}
+		for name, factory := range m.Middleware {
+			RegisterMiddlewareFactory(name, factory)
+		}
+		for name, factory := range m.Routers {
+			RegisterRouterFactory(name, factory)
+		}
+	}
+	return nil
+}
+
+// MustRegisterModuleManifest is like RegisterModuleManifest but panics on collision
+func MustRegisterModuleManifest(manifests ...Manifest) {
+	if err := RegisterModuleManifest(manifests...); err != nil {
+		panic(err)
+	}
+}
+
+func moduleName(m Manifest) string {
+	if m.Name == "" {
+		return "unnamed module"
+	}
+	return "module " + m.Name
+}
@@ -0,0 +1,97 @@
+package lokstra_registry_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+func billingManifest() lokstra_registry.Manifest {
+	return lokstra_registry.Manifest{
+		Name: "billing",
+		Services: map[string]any{
+			"invoice-service-factory": func() any { return &MockUserService{Name: "invoice"} },
+		},
+		Middleware: map[string]any{
+			"billing-auth": func() request.HandlerFunc {
+				return func(c *request.Context) error { return c.Next() }
+			},
+		},
+		Routers: map[string]func() router.Router{
+			"billing-router": func() router.Router { return router.New("billing-router") },
+		},
+	}
+}
+
+func TestRegisterModuleManifest(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	shipping := lokstra_registry.Manifest{
+		Name: "shipping",
+		Services: map[string]any{
+			"shipment-service-factory": func(cfg map[string]any) any { return &MockUserService{Name: "shipment"} },
+		},
+		Routers: map[string]func() router.Router{
+			"shipping-router": func() router.Router { return router.New("shipping-router") },
+		},
+	}
+
+	if err := lokstra_registry.RegisterModuleManifest(billingManifest(), shipping); err != nil {
+		t.Fatalf("RegisterModuleManifest failed: %v", err)
+	}
+
+	reg := lokstra_registry.Global()
+	for _, svcType := range []string{"invoice-service-factory", "shipment-service-factory"} {
+		if !reg.HasServiceType(svcType) {
+			t.Errorf("Service type %s not registered", svcType)
+		}
+	}
+	if reg.GetMiddlewareFactory("billing-auth") == nil {
+		t.Error("Middleware type billing-auth not registered")
+	}
+	for _, name := range []string{"billing-router", "shipping-router"} {
+		if lokstra_registry.GetRouter(name) == nil {
+			t.Errorf("Router %s not registered", name)
+		}
+	}
+}
+
+func TestRegisterModuleManifest_Collision(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	// Two modules claiming the same router name: nothing is registered
+	payments := lokstra_registry.Manifest{
+		Name: "payments",
+		Services: map[string]any{
+			"payment-service-factory": func() any { return &MockUserService{Name: "payment"} },
+		},
+		Routers: map[string]func() router.Router{
+			"billing-router": func() router.Router { return router.New("billing-router") },
+		},
+	}
+
+	err := lokstra_registry.RegisterModuleManifest(billingManifest(), payments)
+	if err == nil {
+		t.Fatal("Expected a collision error")
+	}
+	if !strings.Contains(err.Error(), `router "billing-router" registered by both module billing and module payments`) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if lokstra_registry.Global().HasServiceType("payment-service-factory") ||
+		lokstra_registry.Global().HasServiceType("invoice-service-factory") {
+		t.Error("No service should be registered when manifests collide")
+	}
+
+	// Collision with what is already registered
+	if err := lokstra_registry.RegisterModuleManifest(billingManifest()); err != nil {
+		t.Fatalf("RegisterModuleManifest failed: %v", err)
+	}
+	err = lokstra_registry.RegisterModuleManifest(billingManifest())
+	if err == nil || !strings.Contains(err.Error(), `service type "invoice-service-factory" of module billing is already registered`) {
+		t.Errorf("Expected already-registered collision, got %v", err)
+	}
+}
@@ -17,12 +17,16 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/primadi/lokstra/common/api_client"
 	"github.com/primadi/lokstra/common/cast"
+	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/core/deploy"
 	"github.com/primadi/lokstra/core/deploy/loader/resolver"
 	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
 	"github.com/primadi/lokstra/core/router"
 	"github.com/primadi/lokstra/core/service"
+	"github.com/primadi/lokstra/serviceapi"
 )
 
 // Register path resolver for router package
@@ -31,6 +35,16 @@ func init() {
 
 	// Wire up config resolver for request.Context to avoid circular dependency
 	request.SetConfigResolver(GetConfig)
+	route.SetConfigResolver(GetConfig)
+
+	// Outbound client and request binding metrics use the registered
+	// metrics service (no-op if absent)
+	metrics := func() serviceapi.Metrics {
+		m, _ := TryGetService[serviceapi.Metrics](GetConfig("metrics_service", "metrics"))
+		return m
+	}
+	api_client.SetMetricsResolver(metrics)
+	request.SetMetricsResolver(metrics)
 }
 
 // ===== TYPE ALIASES FOR CLEANER API =====
@@ -383,6 +397,33 @@ func RegisterLazyServiceWithDeps(name string, factory any, deps map[string]strin
 	deploy.Global().RegisterLazyServiceWithDeps(name, factory, deps, config, opts...)
 }
 
+// SetServiceRetryPolicy sets how lazy services whose factory failed with a
+// transient error (see deploy.TransientError) are retried. Other failures
+// are never retried.
+//
+// Example:
+//
+//	lokstra_registry.SetServiceRetryPolicy(&deploy.ServiceRetryPolicy{
+//	    InitialBackoff: 500 * time.Millisecond,
+//	    MaxBackoff:     time.Minute,
+//	    Multiplier:     2,
+//	})
+func SetServiceRetryPolicy(policy *deploy.ServiceRetryPolicy) {
+	deploy.Global().SetServiceRetryPolicy(policy)
+}
+
+// GetServiceError returns the last factory error of a service that could not
+// be created yet, or nil
+func GetServiceError(name string) error {
+	return deploy.Global().GetServiceError(name)
+}
+
+// GetServiceNames returns the names of all instantiated services, sorted
+// (lazy services appear only after their first access)
+func GetServiceNames() []string {
+	return deploy.Global().GetServiceNames()
+}
+
 // GetServiceAny retrieves a service instance (non-generic version)
 func GetServiceAny(name string) (any, bool) {
 	return deploy.Global().GetServiceAny(name)
@@ -410,6 +451,9 @@ func GetService[T any](name string) T {
 func MustGetService[T any](name string) T {
 	svc, ok := TryGetService[T](name)
 	if !ok {
+		if err := GetServiceError(name); err != nil {
+			panic("service " + name + " not available: " + err.Error())
+		}
 		panic("service " + name + " not found or type mismatch")
 	}
 	return svc
@@ -469,6 +513,49 @@ func CreateMiddleware(name string) request.HandlerFunc {
 	return deploy.Global().CreateMiddleware(name)
 }
 
+// ===== HANDLERS =====
+
+// RegisterHandler registers a route handler by name, so tooling and tests
+// can list and invoke it without HTTP. h can have any signature accepted
+// by router.GET & co.
+//
+//	lokstra_registry.RegisterHandler("health.check", healthCheck)
+//	r.GET("/health", lokstra_registry.MustGetHandler("health.check"))
+func RegisterHandler(name string, h any) {
+	deploy.Global().RegisterHandler(name, h)
+}
+
+// GetHandler retrieves a handler registered by name
+func GetHandler(name string) (request.HandlerFunc, bool) {
+	return deploy.Global().GetHandler(name)
+}
+
+// MustGetHandler retrieves a handler registered by name, panicking if absent
+func MustGetHandler(name string) request.HandlerFunc {
+	h, ok := deploy.Global().GetHandler(name)
+	if !ok {
+		panic(fmt.Sprintf("handler %s not found", name))
+	}
+	return h
+}
+
+// ListHandlers returns the names of all registered handlers, sorted
+func ListHandlers() []string {
+	return deploy.Global().ListHandlers()
+}
+
+// InvokeHandler runs the named handler with ctx, without routing or
+// middleware. The response is left pending in ctx, as for a routed
+// handler; call ctx.FinalizeResponse(err) to write it:
+//
+//	w := httptest.NewRecorder()
+//	ctx := request.NewContext(w, httptest.NewRequest("GET", "/health", nil), nil)
+//	err := lokstra_registry.InvokeHandler("health.check", ctx)
+//	ctx.FinalizeResponse(err)
+func InvokeHandler(name string, ctx *request.Context) error {
+	return deploy.Global().InvokeHandler(name, ctx)
+}
+
 // ===== CONFIGURATION =====
 
 // SetConfig sets a runtime configuration value.
@@ -515,6 +602,10 @@ func SetConfig(key string, value any) {
 func GetConfig[T any](name string, defaultValue T) T {
 	value, ok := deploy.Global().GetConfig(name)
 	if !ok {
+		// A nil default marks an optional key (e.g. "routes.<name>")
+		if !utils.IsNil(defaultValue) {
+			deploy.Global().RecordConfigDefault(name, defaultValue, nil)
+		}
 		return defaultValue
 	}
 
@@ -548,6 +639,7 @@ func GetConfig[T any](name string, defaultValue T) T {
 		}
 	}
 
+	deploy.Global().RecordConfigDefault(name, defaultValue, value)
 	return defaultValue
 }
 
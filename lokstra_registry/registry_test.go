@@ -1,6 +1,9 @@
 package lokstra_registry_test
 
 import (
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/primadi/lokstra/core/request"
@@ -125,6 +128,41 @@ func TestRegisterAndGetMiddleware(t *testing.T) {
 	}
 }
 
+func TestListAndInvokeHandlers(t *testing.T) {
+	lokstra_registry.RegisterHandler("test-handlers.health", func(ctx *request.Context) error {
+		return ctx.Api.Ok(map[string]string{"status": "up"})
+	})
+	lokstra_registry.RegisterHandler("test-handlers.echo", func(ctx *request.Context) (any, error) {
+		return ctx.Req.QueryParam("msg", ""), nil
+	})
+
+	var names []string
+	for _, name := range lokstra_registry.ListHandlers() {
+		if strings.HasPrefix(name, "test-handlers.") {
+			names = append(names, name)
+		}
+	}
+	if want := []string{"test-handlers.echo", "test-handlers.health"}; !slices.Equal(names, want) {
+		t.Errorf("expected sorted handlers %v, got %v", want, names)
+	}
+
+	// Invoke through a synthesized context, without HTTP routing
+	w := httptest.NewRecorder()
+	ctx := request.NewContext(w, httptest.NewRequest("GET", "/echo?msg=hello", nil), nil)
+	err := lokstra_registry.InvokeHandler("test-handlers.echo", ctx)
+	ctx.FinalizeResponse(err)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "hello") {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body.String())
+	}
+
+	if err := lokstra_registry.InvokeHandler("test-handlers.missing", ctx); err == nil {
+		t.Error("expected an error for an unknown handler")
+	}
+}
+
 func TestConfigDefineAndGet(t *testing.T) {
 	// Define config
 	lokstra_registry.SetConfig("test-config", "test-value")
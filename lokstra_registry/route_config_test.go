@@ -0,0 +1,137 @@
+package lokstra_registry_test
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+func TestRouteConfig_OverridesCodeDefaults(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	// Ops tunes the limit without touching code: code allows 5, config allows 1
+	lokstra_registry.SetConfig("routes.create-order", map[string]any{
+		"description":       "Create order (config)",
+		"concurrency_limit": 1,
+		"queue_size":        0,
+	})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+
+	r := router.New("orders")
+	r.POST("/orders", func(c *request.Context) error {
+		once.Do(func() { close(started) })
+		<-release
+		return c.Api.Ok("created")
+	}, route.WithNameOption("create-order"),
+		route.WithDescriptionOption("Create order"),
+		route.WithConcurrencyLimitOption(5, 5))
+	r.Build()
+
+	var desc string
+	r.Walk(func(rt *route.Route) {
+		if rt.Name == "create-order" {
+			desc = rt.Description
+		}
+	})
+	if desc != "Create order (config)" {
+		t.Errorf("Expected description from config, got %q", desc)
+	}
+
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/orders", nil))
+		done <- w.Code
+	}()
+	<-started
+
+	// Second concurrent request exceeds the config-defined limit
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/orders", nil))
+	if w.Code != 429 {
+		t.Errorf("Expected 429 from config-defined limit, got %d", w.Code)
+	}
+
+	close(release)
+	if code := <-done; code != 200 {
+		t.Errorf("Expected first request to succeed, got %d", code)
+	}
+}
+
+func TestRouteConfig_AddsLimitToUnlimitedRoute(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	lokstra_registry.SetConfig("routes.export-report", map[string]any{
+		"concurrency_limit": 1,
+	})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+
+	r := router.New("reports")
+	r.GET("/export", func(c *request.Context) error {
+		once.Do(func() { close(started) })
+		<-release
+		return c.Api.Ok("ok")
+	}, route.WithNameOption("export-report"))
+
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/export", nil))
+		close(done)
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/export", nil))
+	if w.Code != 429 {
+		t.Errorf("Expected 429, got %d", w.Code)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestRouteConfig_RateLimit(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	// Code allows 100 requests a minute, config tightens it to 1
+	lokstra_registry.SetConfig("routes.create-payment", map[string]any{
+		"rate_limit":        1,
+		"rate_limit_window": "1m",
+	})
+	// A rate limit set only in config is added to the route
+	lokstra_registry.SetConfig("routes.list-payments", map[string]any{
+		"rate_limit": 1,
+	})
+
+	r := router.New("payments")
+	r.POST("/payments", func(c *request.Context) error {
+		return c.Api.Ok("created")
+	}, route.WithNameOption("create-payment"),
+		route.WithRateLimitOption(100, time.Minute))
+	r.GET("/payments", func(c *request.Context) error {
+		return c.Api.Ok("list")
+	}, route.WithNameOption("list-payments"))
+
+	for _, method := range []string{"POST", "GET"} {
+		for i, want := range []int{200, 429} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(method, "/payments", nil))
+			if w.Code != want {
+				t.Errorf("%s #%d: expected %d, got %d", method, i+1, want, w.Code)
+			}
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package lokstra_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/server"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// ShutdownPhase is a step of the coordinated shutdown (see Shutdown)
+type ShutdownPhase string
+
+const (
+	// Stop accepting new work, e.g. fail readiness checks so load
+	// balancers stop routing traffic here
+	PhaseStopAccepting ShutdownPhase = "stop_accepting"
+	// Let in-flight HTTP requests finish; servers built by RunServer /
+	// BuildServers are drained after the hooks of this phase
+	PhaseDrainHTTP ShutdownPhase = "drain_http"
+	// Stop schedulers and background jobs from starting new runs. Lokstra
+	// has no built-in scheduler, register the hooks of your own jobs here.
+	PhaseStopScheduler ShutdownPhase = "stop_scheduler"
+	// Deliver queued events (event bus, outbox); registered services
+	// implementing serviceapi.EventBus and Shutdownable are shut down after
+	// the hooks of this phase
+	PhaseFlushEvents ShutdownPhase = "flush_events"
+	// Close services; registered services implementing Shutdownable are
+	// shut down last (see ShutdownServices). It waits for hooks of earlier
+	// phases that outlived their timeout, and is skipped if they don't
+	// finish within its own timeout.
+	PhaseCloseServices ShutdownPhase = "close_services"
+)
+
+// ShutdownPhases lists the phases in the order Shutdown runs them
+var ShutdownPhases = []ShutdownPhase{
+	PhaseStopAccepting,
+	PhaseDrainHTTP,
+	PhaseStopScheduler,
+	PhaseFlushEvents,
+	PhaseCloseServices,
+}
+
+// Config key for the default per-phase timeout (default 10s)
+const CONFIG_SHUTDOWN_PHASE_TIMEOUT = "shutdown_phase_timeout"
+
+type shutdownHook struct {
+	id   uint64
+	name string
+	fn   func(ctx context.Context) error
+}
+
+var (
+	shutdownMu       sync.Mutex
+	shutdownHooks    = make(map[ShutdownPhase][]shutdownHook)
+	shutdownTimeouts = make(map[ShutdownPhase]time.Duration)
+	shutdownHookSeq  uint64
+
+	// Set while Shutdown runs, so servers drained by its hooks don't run
+	// the phases again from their own shutdown path
+	shutdownActive atomic.Bool
+
+	// Closed by the hooks of timed-out phases once they return
+	pendingHooks []<-chan struct{}
+
+	// Servers built by buildCurrentServer by composite key, drained by
+	// Shutdown in the drain_http phase
+	serversMu sync.Mutex
+	servers   = make(map[string]*server.Server)
+)
+
+func init() {
+	// Servers run by RunServer / StartServers follow the same order:
+	// stop_accepting before the apps drain, the remaining phases after
+	server.SetBeforeShutdownCallback(func() {
+		if !shutdownActive.Load() {
+			// The servers shut down on their own, Shutdown has none to drain
+			serversMu.Lock()
+			clear(servers)
+			serversMu.Unlock()
+			_ = runShutdownPhases(context.Background(), false, PhaseStopAccepting)
+		}
+	})
+	server.SetShutdownServicesCallback(func() {
+		if !shutdownActive.Load() {
+			_ = runShutdownPhases(context.Background(), false, ShutdownPhases[1:]...)
+		}
+	})
+}
+
+// OnShutdown registers fn to run in the given phase of Shutdown. Hooks of a
+// phase run in registration order; ctx expires with the phase timeout.
+// The returned func unregisters the hook.
+//
+// Example:
+//
+//	lokstra_registry.OnShutdown(lokstra_registry.PhaseStopAccepting, "readiness",
+//	    func(ctx context.Context) error {
+//	        health.SetReady(false)
+//	        return nil
+//	    })
+//	lokstra_registry.OnShutdown(lokstra_registry.PhaseStopScheduler, "reports",
+//	    func(ctx context.Context) error { return reportJob.Stop(ctx) })
+func OnShutdown(phase ShutdownPhase, name string, fn func(ctx context.Context) error) (remove func()) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	shutdownHookSeq++
+	id := shutdownHookSeq
+	shutdownHooks[phase] = append(shutdownHooks[phase], shutdownHook{id: id, name: name, fn: fn})
+
+	return func() {
+		shutdownMu.Lock()
+		defer shutdownMu.Unlock()
+		shutdownHooks[phase] = slices.DeleteFunc(shutdownHooks[phase], func(h shutdownHook) bool {
+			return h.id == id
+		})
+	}
+}
+
+// SetShutdownPhaseTimeout sets how long Shutdown waits for the hooks of
+// phase before moving on to the next one (0 restores the default, see
+// CONFIG_SHUTDOWN_PHASE_TIMEOUT)
+func SetShutdownPhaseTimeout(phase ShutdownPhase, timeout time.Duration) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	if timeout <= 0 {
+		delete(shutdownTimeouts, phase)
+		return
+	}
+	shutdownTimeouts[phase] = timeout
+}
+
+// Shutdown runs the shutdown phases in order (see ShutdownPhases), each
+// bounded by its timeout and by ctx. A failing or timed-out phase is logged
+// and the next phase still runs; the returned error joins all failures.
+//
+// Servers run by RunServer / StartServers call the phases themselves on
+// SIGINT/SIGTERM: stop_accepting before their apps drain, the others after.
+// When Shutdown is called instead, it drains the servers built by
+// RunServer / BuildServers in the drain_http phase, within its timeout.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := lokstra_registry.Shutdown(ctx); err != nil {
+//	    log.Println(err)
+//	}
+func Shutdown(ctx context.Context) error {
+	shutdownActive.Store(true)
+	defer shutdownActive.Store(false)
+	return runShutdownPhases(ctx, true, ShutdownPhases...)
+}
+
+// runShutdownPhases runs the given phases in order. drainServers is false
+// when called from a server's own shutdown path, whose apps drain anyway.
+func runShutdownPhases(ctx context.Context, drainServers bool, phases ...ShutdownPhase) error {
+	var errs []error
+	for _, phase := range phases {
+		shutdownMu.Lock()
+		hooks := slices.Clone(shutdownHooks[phase])
+		timeout, ok := shutdownTimeouts[phase]
+		shutdownMu.Unlock()
+
+		hooks = append(hooks, builtinShutdownHooks(phase, drainServers)...)
+		if len(hooks) == 0 {
+			continue
+		}
+		if !ok {
+			timeout = phaseTimeoutFromConfig()
+		}
+
+		// Don't close services still used by hooks of timed-out phases
+		if phase == PhaseCloseServices && !waitShutdownHooks(ctx, timeout) {
+			logger.LogError("[Shutdown] phase %s: skipped, hooks of earlier phases still running after %s",
+				phase, timeout)
+			errs = append(errs, fmt.Errorf("shutdown phase %s: skipped, hooks of earlier phases still running", phase))
+			continue
+		}
+
+		if err := runShutdownPhase(ctx, phase, hooks, timeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// builtinShutdownHooks returns the hooks run after the registered ones of phase
+func builtinShutdownHooks(phase ShutdownPhase, drainServers bool) []shutdownHook {
+	switch phase {
+	case PhaseDrainHTTP:
+		if drainServers {
+			return []shutdownHook{{name: "servers", fn: drainServersHook}}
+		}
+	case PhaseFlushEvents:
+		return []shutdownHook{{name: "event buses", fn: flushEventBusesHook}}
+	case PhaseCloseServices:
+		return []shutdownHook{{name: "services", fn: func(context.Context) error {
+			ShutdownServices()
+			return nil
+		}}}
+	}
+	return nil
+}
+
+// trackServer records the server built for compositeKey, replacing the
+// one built before, so Shutdown can drain it
+func trackServer(compositeKey string, s *server.Server) {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	servers[compositeKey] = s
+}
+
+// drainServersHook shuts down the tracked servers concurrently, within the
+// phase deadline
+func drainServersHook(ctx context.Context) error {
+	serversMu.Lock()
+	drain := make([]*server.Server, 0, len(servers))
+	for key, s := range servers {
+		drain = append(drain, s)
+		delete(servers, key)
+	}
+	serversMu.Unlock()
+
+	timeout := 5 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(drain))
+	for _, s := range drain {
+		wg.Go(func() {
+			if err := s.Shutdown(timeout); err != nil {
+				errCh <- fmt.Errorf("server '%s': %w", s.GetName(), err)
+			}
+		})
+	}
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// flushEventBusesHook shuts down the registered event buses, delivering
+// their queued events before close_services closes what handlers depend on
+func flushEventBusesHook(context.Context) error {
+	var errs []error
+	for _, name := range GetServiceNames() {
+		svc, _ := GetServiceAny(name)
+		if _, ok := svc.(serviceapi.EventBus); !ok {
+			continue
+		}
+		if bus, ok := svc.(Shutdownable); ok {
+			if err := bus.Shutdown(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitShutdownHooks waits up to timeout for the hooks of timed-out phases
+// and reports whether they all returned
+func waitShutdownHooks(ctx context.Context, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	shutdownMu.Lock()
+	pending := slices.Clone(pendingHooks)
+	shutdownMu.Unlock()
+	for _, finished := range pending {
+		select {
+		case <-finished:
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	shutdownMu.Lock()
+	pendingHooks = slices.DeleteFunc(pendingHooks, func(finished <-chan struct{}) bool {
+		return slices.Contains(pending, finished)
+	})
+	shutdownMu.Unlock()
+	return true
+}
+
+// runShutdownPhase runs the hooks of one phase within timeout
+func runShutdownPhase(ctx context.Context, phase ShutdownPhase, hooks []shutdownHook,
+	timeout time.Duration) error {
+	start := time.Now()
+	logger.LogInfo("[Shutdown] phase %s: running %d hook(s)", phase, len(hooks))
+
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		var errs []error
+		for _, h := range hooks {
+			if err := h.fn(phaseCtx); err != nil {
+				logger.LogError("[Shutdown] phase %s: hook %s failed: %v", phase, h.name, err)
+				errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			}
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		logger.LogInfo("[Shutdown] phase %s: done in %s", phase, time.Since(start).Round(time.Millisecond))
+		if err != nil {
+			return fmt.Errorf("shutdown phase %s: %w", phase, err)
+		}
+		return nil
+	case <-phaseCtx.Done():
+		logger.LogError("[Shutdown] phase %s: not finished within %s", phase, timeout)
+		shutdownMu.Lock()
+		pendingHooks = append(pendingHooks, finished)
+		shutdownMu.Unlock()
+		return fmt.Errorf("shutdown phase %s: %w", phase, context.Cause(phaseCtx))
+	}
+}
+
+func phaseTimeoutFromConfig() time.Duration {
+	timeout, err := time.ParseDuration(GetConfig(CONFIG_SHUTDOWN_PHASE_TIMEOUT, "10s"))
+	if err != nil || timeout <= 0 {
+		return 10 * time.Second
+	}
+	return timeout
+}
@@ -0,0 +1,181 @@
+package lokstra_registry_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/eventbus"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) hook(step string) func(context.Context) error {
+	return func(context.Context) error {
+		r.add(step)
+		return nil
+	}
+}
+
+func onShutdown(t *testing.T, phase lokstra_registry.ShutdownPhase, name string,
+	fn func(context.Context) error) {
+	t.Helper()
+	t.Cleanup(lokstra_registry.OnShutdown(phase, name, fn))
+}
+
+type shutdownRecordingService struct {
+	rec *recorder
+}
+
+func (s *shutdownRecordingService) Shutdown() error {
+	s.rec.add("service")
+	return nil
+}
+
+func TestShutdown_PhaseOrder(t *testing.T) {
+	rec := &recorder{}
+	lokstra_registry.RegisterService("shutdown-order-service", &shutdownRecordingService{rec: rec})
+
+	// Registered out of order on purpose: phases decide the order
+	onShutdown(t, lokstra_registry.PhaseCloseServices, "close", rec.hook("close_services"))
+	onShutdown(t, lokstra_registry.PhaseFlushEvents, "flush", rec.hook("flush_events"))
+	onShutdown(t, lokstra_registry.PhaseStopScheduler, "scheduler", rec.hook("stop_scheduler"))
+	onShutdown(t, lokstra_registry.PhaseDrainHTTP, "drain", rec.hook("drain_http"))
+	onShutdown(t, lokstra_registry.PhaseStopAccepting, "readiness", rec.hook("stop_accepting"))
+	onShutdown(t, lokstra_registry.PhaseStopAccepting, "readiness-2", rec.hook("stop_accepting-2"))
+
+	if err := lokstra_registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	want := []string{
+		"stop_accepting", "stop_accepting-2", "drain_http", "stop_scheduler",
+		"flush_events", "close_services", "service",
+	}
+	if !slices.Equal(rec.steps, want) {
+		t.Errorf("Phase order = %v, want %v", rec.steps, want)
+	}
+}
+
+func TestShutdown_FailedPhaseDoesNotStopLaterPhases(t *testing.T) {
+	rec := &recorder{}
+	lokstra_registry.SetShutdownPhaseTimeout(lokstra_registry.PhaseStopScheduler, 50*time.Millisecond)
+	t.Cleanup(func() { lokstra_registry.SetShutdownPhaseTimeout(lokstra_registry.PhaseStopScheduler, 0) })
+	onShutdown(t, lokstra_registry.PhaseStopScheduler, "stuck-job",
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	onShutdown(t, lokstra_registry.PhaseFlushEvents, "broken-bus",
+		func(context.Context) error { return errors.New("broker unreachable") })
+	onShutdown(t, lokstra_registry.PhaseCloseServices, "after-failures", rec.hook("close_services"))
+
+	start := time.Now()
+	err := lokstra_registry.Shutdown(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error from the failing phases")
+	}
+	for _, msg := range []string{"stop_scheduler", "broker unreachable"} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Error %q should mention %q", err, msg)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Phase timeout not applied, took %v", elapsed)
+	}
+	if !slices.Contains(rec.steps, "close_services") {
+		t.Error("close_services should run after earlier phases failed")
+	}
+}
+
+func TestShutdown_CloseServicesWaitsForTimedOutHooks(t *testing.T) {
+	rec := &recorder{}
+	lokstra_registry.SetShutdownPhaseTimeout(lokstra_registry.PhaseStopScheduler, 20*time.Millisecond)
+	t.Cleanup(func() { lokstra_registry.SetShutdownPhaseTimeout(lokstra_registry.PhaseStopScheduler, 0) })
+	// Ignores ctx, outliving its phase timeout
+	onShutdown(t, lokstra_registry.PhaseStopScheduler, "slow-job",
+		func(context.Context) error {
+			time.Sleep(150 * time.Millisecond)
+			rec.add("job stopped")
+			return nil
+		})
+	onShutdown(t, lokstra_registry.PhaseCloseServices, "close", rec.hook("close_services"))
+
+	err := lokstra_registry.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "stop_scheduler") {
+		t.Errorf("Expected the stop_scheduler timeout, got %v", err)
+	}
+	want := []string{"job stopped", "close_services"}
+	if !slices.Equal(rec.steps, want) {
+		t.Errorf("Steps = %v, want %v", rec.steps, want)
+	}
+}
+
+func TestShutdown_CloseServicesSkippedWhileHooksRun(t *testing.T) {
+	rec := &recorder{}
+	for phase, timeout := range map[lokstra_registry.ShutdownPhase]time.Duration{
+		lokstra_registry.PhaseStopScheduler: 20 * time.Millisecond,
+		lokstra_registry.PhaseCloseServices: 50 * time.Millisecond,
+	} {
+		lokstra_registry.SetShutdownPhaseTimeout(phase, timeout)
+		t.Cleanup(func() { lokstra_registry.SetShutdownPhaseTimeout(phase, 0) })
+	}
+	release := make(chan struct{})
+	stopped := make(chan struct{})
+	// Wait for the stuck hook, so it doesn't hold up later tests
+	t.Cleanup(func() {
+		close(release)
+		<-stopped
+	})
+	onShutdown(t, lokstra_registry.PhaseStopScheduler, "stuck-job",
+		func(context.Context) error {
+			defer close(stopped)
+			<-release
+			return nil
+		})
+	onShutdown(t, lokstra_registry.PhaseCloseServices, "close", rec.hook("close_services"))
+
+	err := lokstra_registry.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "close_services: skipped") {
+		t.Errorf("Expected close_services to be skipped, got %v", err)
+	}
+	if len(rec.steps) != 0 {
+		t.Errorf("close_services ran while a hook was still running: %v", rec.steps)
+	}
+}
+
+func TestShutdown_FlushesEventBuses(t *testing.T) {
+	rec := &recorder{}
+	bus := eventbus.NewBus()
+	bus.Subscribe("order.created", func(context.Context, serviceapi.Event) error {
+		time.Sleep(20 * time.Millisecond)
+		rec.add("event delivered")
+		return nil
+	})
+	lokstra_registry.RegisterService("shutdown-event-bus", bus)
+	bus.PublishAsync(context.Background(), serviceapi.Event{Type: "order.created"})
+	onShutdown(t, lokstra_registry.PhaseCloseServices, "close", rec.hook("close_services"))
+
+	if err := lokstra_registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	want := []string{"event delivered", "close_services"}
+	if !slices.Equal(rec.steps, want) {
+		t.Errorf("Steps = %v, want %v", rec.steps, want)
+	}
+}
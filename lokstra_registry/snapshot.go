@@ -0,0 +1,30 @@
+package lokstra_registry
+
+import (
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/deploy/schema"
+)
+
+// Snapshot returns a serializable view of the registered services,
+// middlewares, routers and servers, e.g. to dump as JSON for deployment
+// verification
+func Snapshot() *deploy.Snapshot {
+	return deploy.Global().Snapshot()
+}
+
+// Diff compares the current registry against what config expects.
+// Added entries are expected by config but not registered, removed entries
+// are registered but not in config. An empty result means they match.
+//
+// Example (CI check):
+//
+//	cfg, _ := loader.LoadConfig("config/production.yaml")
+//	if changes := lokstra_registry.Diff(cfg); len(changes) > 0 {
+//	    for _, c := range changes {
+//	        fmt.Println(c)
+//	    }
+//	    os.Exit(1)
+//	}
+func Diff(config *schema.DeployConfig) []deploy.SnapshotChange {
+	return deploy.DiffSnapshots(Snapshot(), deploy.SnapshotFromConfig(config))
+}
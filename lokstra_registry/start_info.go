@@ -0,0 +1,219 @@
+package lokstra_registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/route"
+	"github.com/primadi/lokstra/core/server"
+)
+
+const (
+	START_INFO_TEXT = "text"
+	START_INFO_JSON = "json"
+)
+
+// Config keys of the startup summary logged by RunServer / StartServers
+const (
+	CONFIG_START_INFO_FORMAT = "start_info_format" // START_INFO_TEXT (default) or START_INFO_JSON
+	CONFIG_START_INFO_ROUTES = "start_info_routes" // list every route (default false)
+)
+
+// ServerStartInfo is a structured startup summary derived from the
+// running server and the global registry
+type ServerStartInfo struct {
+	Deployment  string         `json:"deployment,omitempty"`
+	Server      string         `json:"server"`
+	Apps        []AppStartInfo `json:"apps"`
+	RouteCount  int            `json:"route_count"`
+	Services    []string       `json:"services"`
+	Middlewares []string       `json:"middlewares"`
+	// ConfigWarnings are config keys set but unused or defaulted,
+	// see ReportUnusedConfig
+	ConfigWarnings []string `json:"config_warnings,omitempty"`
+}
+
+// AppStartInfo describes one app (listener) of the server
+type AppStartInfo struct {
+	Name       string           `json:"name"`
+	Addr       string           `json:"addr"`
+	Routers    []string         `json:"routers"`
+	RouteCount int              `json:"route_count"`
+	Routes     []RouteStartInfo `json:"routes,omitempty"`
+}
+
+// RouteStartInfo describes one registered route
+type RouteStartInfo struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Router      string `json:"router,omitempty"`
+	Middlewares int    `json:"middlewares"`
+}
+
+// GetServerStartInfo collects the startup summary of a server:
+// bound addresses, routers, routes, instantiated services, active middleware
+// and config warnings
+func GetServerStartInfo(s *server.Server) *ServerStartInfo {
+	info := &ServerStartInfo{
+		Deployment:  GetCurrentDeploymentName(),
+		Server:      s.Name,
+		Apps:        []AppStartInfo{},
+		Services:    deploy.Global().GetServiceNames(),
+		Middlewares: []string{},
+	}
+	if info.Services == nil {
+		info.Services = []string{}
+	}
+
+	seenMw := make(map[string]bool)
+	for _, a := range s.Apps {
+		appInfo := AppStartInfo{
+			Name:    a.GetName(),
+			Addr:    a.GetAddress(),
+			Routers: []string{},
+			Routes:  []RouteStartInfo{},
+		}
+
+		mainRouter := a.GetRouter()
+		for r := mainRouter; r != nil; r = r.GetNextChain() {
+			appInfo.Routers = append(appInfo.Routers, r.Name())
+		}
+		if mainRouter != nil {
+			mainRouter.Walk(func(rt *route.Route) {
+				appInfo.Routes = append(appInfo.Routes, RouteStartInfo{
+					Method:      rt.Method,
+					Path:        rt.FullPath,
+					Name:        rt.Name,
+					Router:      rt.RouterName,
+					Middlewares: len(rt.FullMiddleware),
+				})
+				for _, mw := range rt.FullMiddleware {
+					if name := funcName(mw); name != "" && !seenMw[name] {
+						seenMw[name] = true
+						info.Middlewares = append(info.Middlewares, name)
+					}
+				}
+			})
+		}
+
+		appInfo.RouteCount = len(appInfo.Routes)
+		info.RouteCount += appInfo.RouteCount
+		info.Apps = append(info.Apps, appInfo)
+	}
+	info.ConfigWarnings = ReportUnusedConfig()
+	return info
+}
+
+// WriteServerStartInfo writes the startup summary to w in the given format
+// (START_INFO_TEXT or START_INFO_JSON)
+func WriteServerStartInfo(w io.Writer, info *ServerStartInfo, format string) error {
+	switch strings.ToLower(format) {
+	case START_INFO_JSON:
+		return json.NewEncoder(w).Encode(info)
+	case START_INFO_TEXT, "":
+		var sb strings.Builder
+		if info.Deployment != "" {
+			fmt.Fprintf(&sb, "Server '%s' (deployment '%s')\n", info.Server, info.Deployment)
+		} else {
+			fmt.Fprintf(&sb, "Server '%s'\n", info.Server)
+		}
+		for _, a := range info.Apps {
+			fmt.Fprintf(&sb, "  App %s on %s: %d route(s), routers: %s\n",
+				a.Name, a.Addr, a.RouteCount, strings.Join(a.Routers, ", "))
+			for _, rt := range a.Routes {
+				fmt.Fprintf(&sb, "    %-7s %s -> %s", rt.Method, rt.Path, rt.Name)
+				if rt.Middlewares > 0 {
+					fmt.Fprintf(&sb, " [%d mw(s)]", rt.Middlewares)
+				}
+				sb.WriteString("\n")
+			}
+		}
+		fmt.Fprintf(&sb, "  Routes: %d\n", info.RouteCount)
+		fmt.Fprintf(&sb, "  Services: %s\n", joinOrNone(info.Services))
+		fmt.Fprintf(&sb, "  Middlewares: %s\n", joinOrNone(info.Middlewares))
+		for _, warning := range info.ConfigWarnings {
+			fmt.Fprintf(&sb, "  WARNING: %s\n", warning)
+		}
+		_, err := io.WriteString(w, sb.String())
+		return err
+	default:
+		return fmt.Errorf("unknown start info format %q (use %q or %q)",
+			format, START_INFO_TEXT, START_INFO_JSON)
+	}
+}
+
+// PrintServerStartInfo logs the startup summary of a server. Format is
+// START_INFO_TEXT or START_INFO_JSON (single line, for log aggregation).
+// Routes are listed only when withRoutes is set; the summary always has
+// their count.
+func PrintServerStartInfo(s *server.Server, format string, withRoutes bool) error {
+	info := GetServerStartInfo(s)
+	if !withRoutes {
+		for i := range info.Apps {
+			info.Apps[i].Routes = nil
+		}
+	}
+
+	var sb strings.Builder
+	if err := WriteServerStartInfo(&sb, info, format); err != nil {
+		return err
+	}
+	for line := range strings.Lines(sb.String()) {
+		logger.LogInfo("%s", strings.TrimSuffix(line, "\n"))
+	}
+	return nil
+}
+
+// printStartInfo logs the startup summary as configured, see
+// CONFIG_START_INFO_FORMAT and CONFIG_START_INFO_ROUTES
+func printStartInfo(s *server.Server) {
+	format := GetConfig(CONFIG_START_INFO_FORMAT, START_INFO_TEXT)
+	if err := PrintServerStartInfo(s, format, GetConfig(CONFIG_START_INFO_ROUTES, false)); err != nil {
+		logger.LogWarning("⚠️  %v", err)
+		s.PrintStartInfo()
+	}
+}
+
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, ", ")
+}
+
+// funcName returns a readable name for a middleware function,
+// e.g. "cors.Middleware" for a closure created in middleware/cors
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// strip closure suffixes: ".func1", ".func1.2"
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		suffix := strings.TrimPrefix(name[i+1:], "func")
+		if suffix == "" || strings.Trim(suffix, "0123456789") != "" {
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,118 @@
+package lokstra_registry_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/core/app"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/core/server"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/middleware/cors"
+)
+
+func newStartInfoServer() *server.Server {
+	api := router.New("api-router")
+	api.Use(cors.Middleware("*"))
+	api.GET("/users", func(c *request.Context) error { return c.Api.Ok(nil) })
+	api.POST("/users", func(c *request.Context) error { return c.Api.Created(nil, "") })
+
+	admin := router.New("admin-router")
+	admin.GET("/stats", func(c *request.Context) error { return c.Api.Ok(nil) })
+
+	return server.New("start-info-server",
+		app.New("api", ":18080", api),
+		app.New("admin", ":18081", admin),
+	)
+}
+
+func TestGetServerStartInfo(t *testing.T) {
+	lokstra_registry.RegisterService("start-info-svc", &MockUserService{Name: "x"})
+	defer lokstra_registry.UnregisterService("start-info-svc")
+
+	info := lokstra_registry.GetServerStartInfo(newStartInfoServer())
+
+	if info.Server != "start-info-server" {
+		t.Errorf("Expected server name, got %q", info.Server)
+	}
+	if info.RouteCount != 3 {
+		t.Errorf("Expected 3 routes, got %d", info.RouteCount)
+	}
+	if len(info.Apps) != 2 || info.Apps[0].Addr != ":18080" || info.Apps[1].Addr != ":18081" {
+		t.Fatalf("Unexpected apps: %+v", info.Apps)
+	}
+	if !slices.Equal(info.Apps[0].Routers, []string{"api-router"}) {
+		t.Errorf("Unexpected routers: %v", info.Apps[0].Routers)
+	}
+	if len(info.Apps[0].Routes) != 2 || info.Apps[0].Routes[0].Middlewares != 1 {
+		t.Errorf("Unexpected api routes: %+v", info.Apps[0].Routes)
+	}
+	if !slices.Contains(info.Services, "start-info-svc") {
+		t.Errorf("Expected registered service in summary, got %v", info.Services)
+	}
+	if !slices.Contains(info.Middlewares, "cors.Middleware") {
+		t.Errorf("Expected cors middleware in summary, got %v", info.Middlewares)
+	}
+}
+
+func TestWriteServerStartInfo_Formats(t *testing.T) {
+	info := lokstra_registry.GetServerStartInfo(newStartInfoServer())
+
+	var buf bytes.Buffer
+	if err := lokstra_registry.WriteServerStartInfo(&buf, info, lokstra_registry.START_INFO_JSON); err != nil {
+		t.Fatalf("JSON output failed: %v", err)
+	}
+	var decoded lokstra_registry.ServerStartInfo
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.RouteCount != 3 || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("Expected single-line JSON with 3 routes, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := lokstra_registry.WriteServerStartInfo(&buf, info, lokstra_registry.START_INFO_TEXT); err != nil {
+		t.Fatalf("Text output failed: %v", err)
+	}
+	for _, want := range []string{"start-info-server", ":18080", "/users", "/stats", "Routes: 3"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Text output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := lokstra_registry.WriteServerStartInfo(&buf, info, "xml"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestPrintServerStartInfo_RoutesOptIn(t *testing.T) {
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	defer logger.SetOutput(os.Stdout)
+
+	s := newStartInfoServer()
+	if err := lokstra_registry.PrintServerStartInfo(s, lokstra_registry.START_INFO_TEXT, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "[INFO]") || !strings.Contains(out, "App api on :18080: 2 route(s)") {
+		t.Errorf("Expected the summary through the logger, got:\n%s", out)
+	}
+	if strings.Contains(out, "/users") {
+		t.Errorf("Routes should not be listed by default:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := lokstra_registry.PrintServerStartInfo(s, lokstra_registry.START_INFO_TEXT, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "/users") {
+		t.Errorf("Expected routes listed on request:\n%s", buf.String())
+	}
+}
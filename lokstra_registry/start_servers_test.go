@@ -0,0 +1,251 @@
+package lokstra_registry_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/deploy"
+	"github.com/primadi/lokstra/core/deploy/loader"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func registerTextRouter(name, path, text string) {
+	r := router.New(name)
+	r.GET(path, func(c *request.Context) error { return c.Resp.Text(text) })
+	lokstra_registry.RegisterRouter(name, r)
+}
+
+// waitGet retries until the server listens
+func waitGet(t *testing.T, url string) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := http.Get(url)
+		if err == nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return string(body)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET %s failed: %v", url, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStartServers_TwoServers(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	userAddr, orderAddr := freeAddr(t), freeAddr(t)
+	registerTextRouter("multi-user-router", "/users", "users")
+	registerTextRouter("multi-order-router", "/orders", "orders")
+	err := lokstra_registry.RegisterDeployment("multi", &lokstra_registry.DeploymentConfig{
+		Servers: map[string]*lokstra_registry.ServerConfig{
+			"user-server":  {Addr: userAddr, Routers: []string{"multi-user-router"}},
+			"order-server": {Addr: orderAddr, Routers: []string{"multi-order-router"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	group, err := lokstra_registry.BuildServers("multi.user-server", "multi.order-server")
+	if err != nil {
+		t.Fatalf("BuildServers failed: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- group.Start() }()
+
+	if body := waitGet(t, "http://"+userAddr+"/users"); body != "users" {
+		t.Errorf("Unexpected user-server body %q", body)
+	}
+	if body := waitGet(t, "http://"+orderAddr+"/orders"); body != "orders" {
+		t.Errorf("Unexpected order-server body %q", body)
+	}
+
+	if err := group.Shutdown(time.Second); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean stop, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Servers did not stop")
+	}
+	if _, err := http.Get("http://" + userAddr + "/users"); err == nil {
+		t.Error("Expected user-server to be stopped")
+	}
+}
+
+func TestStartServers_AddressConflict(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	addr := freeAddr(t)
+	registerTextRouter("conflict-a-router", "/a", "a")
+	registerTextRouter("conflict-b-router", "/b", "b")
+	err := lokstra_registry.RegisterDeployment("conflict", &lokstra_registry.DeploymentConfig{
+		Servers: map[string]*lokstra_registry.ServerConfig{
+			"a": {Addr: addr, Routers: []string{"conflict-a-router"}},
+			"b": {Addr: addr, Routers: []string{"conflict-b-router"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lokstra_registry.StartServers("conflict.a", "conflict.b")
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("both listen on %s", addr)) {
+		t.Errorf("Expected an address conflict error, got %v", err)
+	}
+}
+
+func TestStartServers_PortInUse(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	freeAddr := freeAddr(t)
+	registerTextRouter("busy-a-router", "/a", "a")
+	registerTextRouter("busy-b-router", "/b", "b")
+	err = lokstra_registry.RegisterDeployment("busy", &lokstra_registry.DeploymentConfig{
+		Servers: map[string]*lokstra_registry.ServerConfig{
+			"a": {Addr: freeAddr, Routers: []string{"busy-a-router"}},
+			"b": {Addr: busy.Addr().String(), Routers: []string{"busy-b-router"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The failing server stops the others
+	done := make(chan error, 1)
+	go func() { done <- lokstra_registry.StartServers("busy.a", "busy.b") }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "server 'b'") {
+			t.Errorf("Expected the error of server 'b', got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("StartServers did not return after a server failed")
+	}
+}
+
+func TestShutdown_DrainsBuiltServers(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+
+	addr := freeAddr(t)
+	registerTextRouter("drain-router", "/ping", "pong")
+	err := lokstra_registry.RegisterDeployment("drain", &lokstra_registry.DeploymentConfig{
+		Servers: map[string]*lokstra_registry.ServerConfig{
+			"api": {Addr: addr, Routers: []string{"drain-router"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	group, err := lokstra_registry.BuildServers("drain.api")
+	if err != nil {
+		t.Fatalf("BuildServers failed: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- group.Start() }()
+	if body := waitGet(t, "http://"+addr+"/ping"); body != "pong" {
+		t.Errorf("Unexpected body %q", body)
+	}
+
+	if err := lokstra_registry.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean stop, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Server was not drained by Shutdown")
+	}
+}
+
+// loadMiddlewareConfig loads a deployment of two servers sharing the
+// middleware definition "shared-mw"
+func loadMiddlewareConfig(t *testing.T) {
+	t.Helper()
+	registerTextRouter("mwdef-a-router", "/a", "a")
+	registerTextRouter("mwdef-b-router", "/b", "b")
+	yaml := fmt.Sprintf(`
+middleware-definitions:
+  shared-mw:
+    type: mwdef-type
+deployments:
+  mwdef:
+    servers:
+      a:
+        base-url: http://localhost
+        addr: "%s"
+        routers: [mwdef-a-router]
+      b:
+        base-url: http://localhost
+        addr: "%s"
+        routers: [mwdef-b-router]
+`, freeAddr(t), freeAddr(t))
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loader.LoadConfig(path); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+}
+
+func TestBuildServers_SharedMiddlewareDefinitions(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+	loadMiddlewareConfig(t)
+
+	group, err := lokstra_registry.BuildServers("mwdef.a", "mwdef.b")
+	if err != nil {
+		t.Fatalf("BuildServers failed: %v", err)
+	}
+	if len(group.Servers) != 2 {
+		t.Errorf("Expected 2 servers, got %d", len(group.Servers))
+	}
+}
+
+func TestBuildServers_MiddlewareDefinitionDoesNotReplaceCode(t *testing.T) {
+	deploy.ResetGlobalRegistryForTesting()
+	loadMiddlewareConfig(t)
+	lokstra_registry.RegisterMiddlewareName("shared-mw", "code-type", nil)
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "shared-mw already registered") {
+			t.Errorf("Expected a duplicate middleware name panic, got %v", r)
+		}
+	}()
+	_, _ = lokstra_registry.BuildServers("mwdef.a")
+}
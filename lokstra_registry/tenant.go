@@ -0,0 +1,34 @@
+package lokstra_registry
+
+import (
+	"context"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+// TenantServiceName returns the registry name of a tenant-specific service,
+// e.g. TenantServiceName("db-pool", "acme") → "db-pool@acme"
+func TenantServiceName(name, tenant string) string {
+	return name + "@" + tenant
+}
+
+// GetTenantService resolves a service within the tenant carried by ctx
+// (set by the tenant middleware). If "<name>@<tenant>" is registered it is
+// returned, otherwise the shared service "<name>" is used.
+//
+// Example:
+//
+//	// one pool per tenant, plus a shared default
+//	lokstra_registry.RegisterService(lokstra_registry.TenantServiceName("db-pool", "acme"), acmePool)
+//	lokstra_registry.RegisterService("db-pool", sharedPool)
+//
+//	// in a repository
+//	pool, _ := lokstra_registry.GetTenantService[serviceapi.DbPool](ctx, "db-pool")
+func GetTenantService[T any](ctx context.Context, name string) (T, bool) {
+	if tenant := request.TenantFromContext(ctx); tenant != "" {
+		if svc, ok := TryGetService[T](TenantServiceName(name, tenant)); ok {
+			return svc, true
+		}
+	}
+	return TryGetService[T](name)
+}
@@ -3,6 +3,7 @@ package body_limit
 import (
 	"net/http"
 	"path"
+	"strconv"
 
 	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/core/request"
@@ -16,6 +17,10 @@ const PARAMS_SKIP_LARGE_PAYLOADS = "skip_large_payloads"
 const PARAMS_MESSAGE = "message"
 const PARAMS_STATUS_CODE = "status_code"
 const PARAMS_SKIP_ON_PATH = "skip_on_path"
+const PARAMS_HEADER = "header"
+
+// HeaderBodyLimit carries the applied body limit in bytes (Config.Header)
+const HeaderBodyLimit = "X-Body-Limit"
 
 type Config = internal.Config
 
@@ -51,11 +56,21 @@ func Middleware(cfg *Config) request.HandlerFunc {
 			}
 		}
 
+		if cfg.Header {
+			// Nested limits (app and route): report the tightest one
+			h := c.W.Header()
+			if prev, err := strconv.ParseInt(h.Get(HeaderBodyLimit), 10, 64); err != nil || cfg.MaxSize < prev {
+				h.Set(HeaderBodyLimit, strconv.FormatInt(cfg.MaxSize, 10))
+			}
+		}
+
 		// Optional: Early validation using ContentLength header if available
 		// Note: This only works if:
 		// - Client sets ContentLength in HTTP request
 		// - OR previous middleware sets ContentLength
 		// If ContentLength is -1 (unknown), this check is skipped
+		// Rejecting here, before the body is read, also spares clients sending
+		// "Expect: 100-continue" the upload (no 100 Continue is sent)
 		if c.R.ContentLength > 0 && c.R.ContentLength > cfg.MaxSize {
 			if !cfg.SkipLargePayloads {
 				// Reject immediately based on declared size
@@ -92,6 +107,7 @@ func MiddlewareFactory(params map[string]any) request.HandlerFunc {
 		Message:           utils.GetValueFromMap(params, PARAMS_MESSAGE, defConfig.Message),
 		StatusCode:        utils.GetValueFromMap(params, PARAMS_STATUS_CODE, defConfig.StatusCode),
 		SkipOnPath:        utils.GetValueFromMap(params, PARAMS_SKIP_ON_PATH, defConfig.SkipOnPath),
+		Header:            utils.GetValueFromMap(params, PARAMS_HEADER, defConfig.Header),
 	}
 	return Middleware(cfg)
 }
@@ -0,0 +1,94 @@
+package body_limit_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/body_limit"
+)
+
+// uploadBody records whether the client sent the body
+type uploadBody struct {
+	r    io.Reader
+	sent atomic.Bool
+}
+
+func (b *uploadBody) Read(p []byte) (int, error) {
+	b.sent.Store(true)
+	return b.r.Read(p)
+}
+
+func expectContinueUpload(t *testing.T, url string, size int) (*http.Response, *uploadBody) {
+	t.Helper()
+	body := &uploadBody{r: bytes.NewReader(bytes.Repeat([]byte("a"), size))}
+	req, _ := http.NewRequest("POST", url, body)
+	req.ContentLength = int64(size)
+	req.Header.Set("Expect", "100-continue")
+
+	client := &http.Client{Transport: &http.Transport{ExpectContinueTimeout: 5 * time.Second}}
+	defer client.CloseIdleConnections()
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	return res, body
+}
+
+func TestBodyLimit_ExpectContinue(t *testing.T) {
+	r := router.New("upload")
+	r.Use(body_limit.Middleware(&body_limit.Config{MaxSize: 1024}))
+	r.POST("/upload", func(c *request.Context) error {
+		data, err := io.ReadAll(c.R.Body)
+		if err != nil {
+			return err
+		}
+		return c.Api.Ok(len(data))
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	t.Run("oversized upload is rejected before the body is sent", func(t *testing.T) {
+		res, body := expectContinueUpload(t, srv.URL+"/upload", 1<<20)
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413, got %d", res.StatusCode)
+		}
+		if body.sent.Load() {
+			t.Error("Client sent the body of a rejected upload")
+		}
+	})
+
+	t.Run("accepted upload continues", func(t *testing.T) {
+		res, body := expectContinueUpload(t, srv.URL+"/upload", 512)
+		data, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200, got %d: %s", res.StatusCode, data)
+		}
+		if !body.sent.Load() || !bytes.Contains(data, []byte("512")) {
+			t.Errorf("Expected body to be sent after 100 Continue, got %s", data)
+		}
+	})
+
+	t.Run("unsupported expectation gets 417", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", srv.URL+"/upload", bytes.NewReader([]byte("a")))
+		req.Header.Set("Expect", "something-else")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusExpectationFailed {
+			t.Errorf("Expected 417, got %d", res.StatusCode)
+		}
+	})
+}
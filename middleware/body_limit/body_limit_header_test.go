@@ -0,0 +1,36 @@
+package body_limit_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/body_limit"
+)
+
+func TestBodyLimit_Header(t *testing.T) {
+	handler := func(c *request.Context) error { return c.Api.Ok(nil) }
+
+	r := router.New("test-router")
+	app := body_limit.Middleware(&body_limit.Config{MaxSize: 10 << 20, Header: true})
+	r.POST("/upload", handler, app)
+	r.POST("/avatar", handler, app, body_limit.Middleware(&body_limit.Config{MaxSize: 1 << 20, Header: true}))
+	r.POST("/quiet", handler, body_limit.Middleware(&body_limit.Config{MaxSize: 1 << 20}))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/upload", "10485760"},
+		{"/avatar", "1048576"}, // tightest of app and route limits
+		{"/quiet", ""},         // off by default
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", tt.path, nil))
+		if got := w.Header().Get(body_limit.HeaderBodyLimit); got != tt.want {
+			t.Errorf("%s: %s = %q, want %q", tt.path, body_limit.HeaderBodyLimit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package body_limit_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/body_limit"
+)
+
+func TestBodyLimit_StreamedBody(t *testing.T) {
+	const limit = 1 << 20 // 1MB
+
+	var streamed int64
+	r := router.New("test-router")
+	r.Use(body_limit.Middleware(&body_limit.Config{MaxSize: limit}))
+	r.POST("/upload", func(c *request.Context) error {
+		n, err := io.Copy(io.Discard, c.Req.BodyReader())
+		streamed = n
+		if err != nil {
+			return err
+		}
+		return c.Api.Ok(map[string]int64{"bytes": n})
+	})
+
+	upload := func(size int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/upload", strings.NewReader(strings.Repeat("x", size)))
+		// Unknown length, so the limit is enforced while streaming
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		w := upload(limit)
+		if w.Code != http.StatusOK || streamed != limit {
+			t.Errorf("expected 200 with %d bytes streamed, got %d with %d: %s",
+				limit, w.Code, streamed, w.Body.String())
+		}
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		w := upload(limit + 1)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected 413, got %d: %s", w.Code, w.Body.String())
+		}
+		if streamed > limit {
+			t.Errorf("streamed %d bytes past the %d byte limit", streamed, limit)
+		}
+	})
+}
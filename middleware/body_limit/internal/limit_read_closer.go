@@ -3,6 +3,8 @@ package internal
 import (
 	"fmt"
 	"io"
+
+	"github.com/primadi/lokstra/common/errs"
 )
 
 type Config struct {
@@ -24,6 +26,10 @@ type Config struct {
 	// Example: ["/api/*", "/public/**"]
 	// will skip all paths under /api/ and /public/ (including subpaths)
 	SkipOnPath []string
+
+	// Header sends the applied limit in bytes in the X-Body-Limit response
+	// header, so clients can size uploads. Off by default to not expose limits.
+	Header bool
 }
 
 // LimitedReadCloser wraps the request body to enforce size limits during reading
@@ -48,8 +54,17 @@ func (l *LimitedReadCloser) Read(p []byte) (int, error) {
 			return 0, io.EOF
 		}
 
-		// Body size exceeded during reading
-		return 0, fmt.Errorf("request body exceeds limit of %d bytes", l.config.MaxSize)
+		// A body of exactly MaxSize bytes is within the limit: probe for
+		// more data before failing
+		var probe [1]byte
+		if n, err := l.reader.Read(probe[:]); n == 0 {
+			return 0, err
+		}
+
+		// Body size exceeded during reading. Returned as is by handlers
+		// streaming the body, it maps to the configured status.
+		return 0, errs.New(l.config.StatusCode, "BODY_TOO_LARGE", l.config.Message).
+			WithCause(fmt.Errorf("request body exceeds limit of %d bytes", l.config.MaxSize))
 	}
 
 	// Limit read to remaining bytes
@@ -3,6 +3,8 @@ package cors
 import (
 	"net/http"
 	"slices"
+	"strconv"
+	"time"
 
 	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/core/request"
@@ -11,36 +13,89 @@ import (
 
 const CORS_TYPE = "cors"
 const PARAMS_ALLOW_ORIGINS = "allow_origins"
+const PARAMS_MAX_AGE = "max_age"
+
+const defaultAllowMethods = "GET, POST, PUT, DELETE, OPTIONS"
+
+type Config struct {
+	// AllowOrigins is a list of allowed origins or ["*"] to allow all
+	AllowOrigins []string
+
+	// MaxAge is sent as Access-Control-Max-Age on preflight responses
+	// so browsers can reuse them. 0 omits the header.
+	MaxAge time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		AllowOrigins: []string{"*"},
+		MaxAge:       0,
+	}
+}
 
 // CORS middleware to handle CORS requests
 // allowOrigins can be a list of allowed origins or ["*"] to allow all
 func Middleware(allowOrigins ...string) request.HandlerFunc {
-	AllOrigins := slices.Contains(allowOrigins, "*")
+	if allowOrigins == nil {
+		// No origins allows none (not the "*" default)
+		allowOrigins = []string{}
+	}
+	h := MiddlewareWithConfig(&Config{AllowOrigins: allowOrigins})
+	// Own closure, so the start info lists it as cors.Middleware
+	return request.HandlerFunc(func(c *request.Context) error {
+		return h(c)
+	})
+}
+
+// MiddlewareWithConfig is like Middleware but also sets
+// Access-Control-Max-Age on preflight responses. Preflight requests are
+// answered directly (the handler chain is not executed).
+func MiddlewareWithConfig(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.AllowOrigins == nil {
+		cfg.AllowOrigins = defConfig.AllowOrigins
+	}
+
+	allOrigins := slices.Contains(cfg.AllowOrigins, "*")
+	maxAge := ""
+	if cfg.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(cfg.MaxAge / time.Second))
+	}
+
 	return request.HandlerFunc(func(c *request.Context) error {
 		origin := c.R.Header.Get("Origin")
 		// only set CORS headers if Origin header is present
-		if origin != "" {
-			// if not allowing all origins, check if origin is in the allowed list
-			if !AllOrigins && !slices.Contains(allowOrigins, origin) {
-				c.W.WriteHeader(http.StatusForbidden)
-				return nil
-			}
-
-			// Set CORS headers
-			c.W.Header().Set("Access-Control-Allow-Origin", origin)
-			c.W.Header().Set("Access-Control-Allow-Credentials", "true")
-
-			// Handle preflight requests
-			if c.R.Method == http.MethodOptions {
-				if reqHeaders := c.R.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
-					c.W.Header().Set("Access-Control-Allow-Headers", reqHeaders)
-				}
-				// Sets commonly used methods
-				c.W.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				c.W.WriteHeader(http.StatusNoContent)
-			}
+		if origin == "" {
+			return c.Next()
+		}
+		// if not allowing all origins, check if origin is in the allowed list
+		if !allOrigins && !slices.Contains(cfg.AllowOrigins, origin) {
+			c.W.WriteHeader(http.StatusForbidden)
+			return nil
+		}
+
+		// Set CORS headers; the response depends on the origin
+		h := c.W.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+
+		if c.R.Method != http.MethodOptions {
+			h.Add("Vary", "Origin")
+			return c.Next()
+		}
+
+		// Handle preflight requests
+		if reqHeaders := c.R.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", reqHeaders)
+		}
+		// Sets commonly used methods
+		h.Set("Access-Control-Allow-Methods", defaultAllowMethods)
+		if maxAge != "" {
+			h.Set("Access-Control-Max-Age", maxAge)
 		}
-		return c.Next()
+		h.Set("Vary", "Origin, Access-Control-Request-Method, Access-Control-Request-Headers")
+		c.W.WriteHeader(http.StatusNoContent)
+		return c.RespondNow()
 	})
 }
 
@@ -49,8 +104,11 @@ func MiddlewareFactory(params map[string]any) request.HandlerFunc {
 		return Middleware("*")
 	}
 
-	allowOrigins := utils.GetValueFromMap(params, PARAMS_ALLOW_ORIGINS, []string{})
-	return Middleware(allowOrigins...)
+	defConfig := DefaultConfig()
+	return MiddlewareWithConfig(&Config{
+		AllowOrigins: utils.GetValueFromMap(params, PARAMS_ALLOW_ORIGINS, []string{}),
+		MaxAge:       utils.GetValueFromMap(params, PARAMS_MAX_AGE, defConfig.MaxAge),
+	})
 }
 
 func Register() {
@@ -3,7 +3,9 @@ package cors_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/primadi/lokstra/core/request"
 	"github.com/primadi/lokstra/middleware/cors"
@@ -78,3 +80,66 @@ func TestCorsMiddleware_OPTIONS(t *testing.T) {
 		t.Errorf("Expected 204 for OPTIONS, got %d", w.Code)
 	}
 }
+
+func TestCorsMiddleware_PreflightMaxAge(t *testing.T) {
+	h := cors.MiddlewareWithConfig(&cors.Config{
+		AllowOrigins: []string{"*"},
+		MaxAge:       24 * time.Hour,
+	})
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("OPTIONS", "/api/items", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+		w := httptest.NewRecorder()
+		ctx := request.NewContext(w, req, nil)
+		h(ctx)
+		return w
+	}
+
+	first := preflight("http://spa.example.com")
+	second := preflight("http://spa.example.com")
+
+	if first.Code != http.StatusNoContent || second.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204 for preflights, got %d and %d", first.Code, second.Code)
+	}
+	if !reflect.DeepEqual(first.Header(), second.Header()) {
+		t.Errorf("Expected identical preflight headers:\n%v\n%v", first.Header(), second.Header())
+	}
+	if first.Header().Get("Access-Control-Max-Age") != "86400" {
+		t.Errorf("Expected Max-Age 86400, got %s", first.Header().Get("Access-Control-Max-Age"))
+	}
+
+	// Each origin gets its own Allow-Origin
+	other := preflight("http://admin.example.com")
+	if other.Header().Get("Access-Control-Allow-Origin") != "http://admin.example.com" {
+		t.Errorf("Expected per-origin Allow-Origin, got %s", other.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCorsMiddleware_PreflightSkipsHandler(t *testing.T) {
+	handlerRan := false
+	handler := func(c *request.Context) error {
+		handlerRan = true
+		return c.Api.Ok("should not run")
+	}
+
+	req := httptest.NewRequest("OPTIONS", "/", nil)
+	req.Header.Set("Origin", "http://example.com")
+	w := httptest.NewRecorder()
+	ctx := request.NewContext(w, req, []request.HandlerFunc{cors.Middleware("*"), handler})
+	if err := ctx.Next(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if handlerRan {
+		t.Error("Handler should not run for a preflight request")
+	}
+	if !ctx.RespondedEarly() {
+		t.Error("Preflight should end the chain with RespondNow")
+	}
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 for OPTIONS, got %d", w.Code)
+	}
+}
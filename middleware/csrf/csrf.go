@@ -0,0 +1,292 @@
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const CSRF_TYPE = "csrf"
+const PARAMS_COOKIE_NAME = "cookie_name"
+const PARAMS_HEADER_NAME = "header_name"
+const PARAMS_FORM_FIELD = "form_field"
+const PARAMS_COOKIE_PATH = "cookie_path"
+const PARAMS_SECURE = "secure"
+const PARAMS_SAME_SITE = "same_site"
+const PARAMS_MAX_AGE = "max_age"
+const PARAMS_EXEMPT_PATHS = "exempt_paths"
+const PARAMS_SECRET = "secret"
+
+// tokenSize is the number of random bytes of a token
+const tokenSize = 32
+
+// hostCookiePrefix locks a cookie to the host that set it (Secure, Path=/,
+// no Domain), so sibling subdomains cannot overwrite it
+const hostCookiePrefix = "__Host-"
+
+type Config struct {
+	// Secret signs tokens with HMAC-SHA256, so only tokens issued by the
+	// app are accepted. Share it between instances; when empty a random
+	// per-process key is used, and tokens do not survive restarts.
+	Secret string
+
+	// SessionID, if set, binds tokens to the session of the request (e.g.
+	// the session cookie or principal id), so a token planted by a sibling
+	// subdomain (cookie tossing) is rejected for another session
+	SessionID func(c *request.Context) string
+
+	// CookieName is the cookie holding the token (default "csrf_token", or
+	// "__Host-csrf_token" when Secure is set and CookiePath is "/")
+	CookieName string
+
+	// HeaderName carries the token on unsafe requests, e.g. sent by HTMX
+	// with hx-headers (default "X-CSRF-Token")
+	HeaderName string
+
+	// FormField carries the token in urlencoded form posts (default
+	// "csrf_token"), checked when the header is absent. Multipart bodies
+	// are not parsed: send the header with file uploads.
+	FormField string
+
+	// CookiePath is the path of the token cookie (default "/")
+	CookiePath string
+
+	// Secure sends the cookie over HTTPS only
+	Secure bool
+
+	// SameSite of the cookie (default http.SameSiteLaxMode)
+	SameSite http.SameSite
+
+	// MaxAge of the cookie; 0 keeps it for the browser session
+	MaxAge time.Duration
+
+	// ExemptPaths are not checked (supports * and ** wildcards), e.g.
+	// webhooks and APIs authenticated with bearer tokens
+	// Example: ["/webhooks/**", "/api/**"]
+	ExemptPaths []string
+
+	// Exempt marks additional requests that are not checked
+	Exempt func(c *request.Context) bool
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		CookieName:  "csrf_token",
+		HeaderName:  "X-CSRF-Token",
+		FormField:   "csrf_token",
+		CookiePath:  "/",
+		SameSite:    http.SameSiteLaxMode,
+		ExemptPaths: []string{},
+	}
+}
+
+// middleware protecting cookie/session apps against cross-site request
+// forgery with the signed double-submit cookie pattern. Each client gets a
+// random HMAC-signed token in an HttpOnly cookie; pages render it with
+// ctx.CSRFToken(), and unsafe requests (POST, PUT, PATCH, DELETE, ...) must
+// send it back in the header or form field, else they get 403.
+//
+//	r.Use(csrf.Middleware(&csrf.Config{
+//	    Secret:      os.Getenv("CSRF_SECRET"),
+//	    Secure:      true,
+//	    ExemptPaths: []string{"/webhooks/**"},
+//	}))
+//
+//	<form method="post"><input type="hidden" name="csrf_token" value="{{.CSRFToken}}"></form>
+//	<body hx-headers='{"X-CSRF-Token": "{{.CSRFToken}}"}'>
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.CookiePath == "" {
+		cfg.CookiePath = defConfig.CookiePath
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = defConfig.CookieName
+		if cfg.Secure && cfg.CookiePath == "/" {
+			cfg.CookieName = hostCookiePrefix + cfg.CookieName
+		}
+	}
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = defConfig.HeaderName
+	}
+	if cfg.FormField == "" {
+		cfg.FormField = defConfig.FormField
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = defConfig.SameSite
+	}
+
+	key := []byte(cfg.Secret)
+	if len(key) == 0 {
+		logger.LogWarn("csrf: no secret configured, tokens are signed with a random per-process key")
+		key = make([]byte, sha256.Size)
+		rand.Read(key)
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		sessionID := ""
+		if cfg.SessionID != nil {
+			sessionID = cfg.SessionID(c)
+		}
+
+		token := ""
+		if cookie, err := c.R.Cookie(cfg.CookieName); err == nil && validToken(key, sessionID, cookie.Value) {
+			token = cookie.Value
+		}
+
+		if !isSafeMethod(c.R.Method) && !isExempt(cfg, c) {
+			if token == "" || !tokensEqual(token, submittedToken(cfg, c)) {
+				return c.Api.Error(http.StatusForbidden, "CSRF_TOKEN_INVALID",
+					"CSRF token missing or invalid")
+			}
+		}
+
+		if token == "" {
+			token = newToken(key, sessionID)
+			http.SetCookie(c.W, &http.Cookie{
+				Name:     cfg.CookieName,
+				Value:    token,
+				Path:     cfg.CookiePath,
+				MaxAge:   int(cfg.MaxAge / time.Second),
+				Secure:   cfg.Secure,
+				HttpOnly: true,
+				SameSite: cfg.SameSite,
+			})
+		}
+		c.SetCSRFToken(token)
+		return c.Next()
+	})
+}
+
+// isSafeMethod reports whether method does not change state (RFC 9110)
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func isExempt(cfg *Config, c *request.Context) bool {
+	requestPath := path.Clean(c.R.URL.Path)
+	for _, pattern := range cfg.ExemptPaths {
+		if matchPath(requestPath, pattern) {
+			return true
+		}
+	}
+	return cfg.Exempt != nil && cfg.Exempt(c)
+}
+
+// matchPath supports exact paths, "/prefix/**" and single-segment "*"
+func matchPath(requestPath, pattern string) bool {
+	if requestPath == pattern {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "**"); ok {
+		return strings.HasPrefix(requestPath, prefix)
+	}
+	if !strings.Contains(pattern, "*") {
+		return false
+	}
+	matched, err := path.Match(pattern, requestPath)
+	return err == nil && matched
+}
+
+// submittedToken returns the token sent with the request, from the header,
+// else from the form field of an urlencoded body. Multipart bodies are not
+// parsed here, that would buffer their files in memory.
+func submittedToken(cfg *Config, c *request.Context) string {
+	if token := c.R.Header.Get(cfg.HeaderName); token != "" {
+		return token
+	}
+	if !strings.HasPrefix(c.R.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return ""
+	}
+	// Buffer the body first, so the handler can still bind the form
+	if _, err := c.Req.RawRequestBody(); err != nil {
+		return ""
+	}
+	return c.Req.FormParam(cfg.FormField, "")
+}
+
+// newToken returns "<nonce>.<mac>": a random nonce and its HMAC, bound to
+// sessionID
+func newToken(key []byte, sessionID string) string {
+	nonce := make([]byte, tokenSize)
+	rand.Read(nonce)
+	return base64.RawURLEncoding.EncodeToString(nonce) + "." +
+		base64.RawURLEncoding.EncodeToString(tokenMAC(key, sessionID, nonce))
+}
+
+// validToken rejects cookies not created by newToken with key for sessionID
+func validToken(key []byte, sessionID, token string) bool {
+	encNonce, encMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	nonce, err := base64.RawURLEncoding.DecodeString(encNonce)
+	if err != nil || len(nonce) != tokenSize {
+		return false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encMAC)
+	return err == nil && hmac.Equal(mac, tokenMAC(key, sessionID, nonce))
+}
+
+func tokenMAC(key []byte, sessionID string, nonce []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	// Length-prefixed, so session ids cannot shift into the nonce
+	h.Write([]byte{byte(len(sessionID) >> 8), byte(len(sessionID))})
+	h.Write([]byte(sessionID))
+	h.Write(nonce)
+	return h.Sum(nil)
+}
+
+func tokensEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Secret:      utils.GetValueFromMap(params, PARAMS_SECRET, ""),
+		CookieName:  utils.GetValueFromMap(params, PARAMS_COOKIE_NAME, ""),
+		HeaderName:  utils.GetValueFromMap(params, PARAMS_HEADER_NAME, defConfig.HeaderName),
+		FormField:   utils.GetValueFromMap(params, PARAMS_FORM_FIELD, defConfig.FormField),
+		CookiePath:  utils.GetValueFromMap(params, PARAMS_COOKIE_PATH, defConfig.CookiePath),
+		Secure:      utils.GetValueFromMap(params, PARAMS_SECURE, defConfig.Secure),
+		SameSite:    parseSameSite(utils.GetValueFromMap(params, PARAMS_SAME_SITE, "lax")),
+		MaxAge:      utils.GetValueFromMap(params, PARAMS_MAX_AGE, defConfig.MaxAge),
+		ExemptPaths: utils.GetValueFromMap(params, PARAMS_EXEMPT_PATHS, defConfig.ExemptPaths),
+	}
+	return Middleware(cfg)
+}
+
+// parseSameSite converts "lax", "strict" or "none" (default lax)
+func parseSameSite(s string) http.SameSite {
+	switch strings.ToLower(s) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	}
+	return http.SameSiteLaxMode
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(CSRF_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,209 @@
+package csrf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/csrf"
+)
+
+func newRouter(cfg *csrf.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(csrf.Middleware(cfg))
+	r.GET("/form", func(c *request.Context) error {
+		return c.Api.Ok(c.CSRFToken())
+	})
+	r.POST("/orders", func(c *request.Context) error {
+		var form map[string]any
+		if err := c.Req.BindBodyAuto(&form); err != nil {
+			return err
+		}
+		return c.Api.Ok(form["item"])
+	})
+	r.POST("/webhooks/stripe", func(c *request.Context) error {
+		return c.Api.Ok("received")
+	})
+	return r
+}
+
+// fetchToken loads a page, returning the cookie set and the token exposed
+// to the handler
+func fetchToken(t *testing.T, r router.Router) (*http.Cookie, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/form", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "csrf_token" || !cookies[0].HttpOnly {
+		t.Fatalf("Expected an HttpOnly csrf_token cookie, got %v", cookies)
+	}
+	if !strings.Contains(w.Body.String(), cookies[0].Value) {
+		t.Fatalf("Expected the handler to see token %q, got %s", cookies[0].Value, w.Body.String())
+	}
+	return cookies[0], cookies[0].Value
+}
+
+func post(r router.Router, target string, cookie *http.Cookie, header string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	if header != "" {
+		req.Header.Set("X-CSRF-Token", header)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCSRF_ValidToken(t *testing.T) {
+	r := newRouter(&csrf.Config{})
+	cookie, token := fetchToken(t, r)
+
+	if w := post(r, "/orders", cookie, token, url.Values{"item": {"book"}}); w.Code != http.StatusOK {
+		t.Errorf("Expected header token to pass, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := post(r, "/orders", cookie, "", url.Values{"csrf_token": {token}, "item": {"book"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected form token to pass, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "book") {
+		t.Errorf("Expected the handler to still bind the form, got %s", w.Body.String())
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected an existing token to be kept")
+	}
+}
+
+func TestCSRF_RejectsMissingOrInvalidToken(t *testing.T) {
+	r := newRouter(&csrf.Config{})
+	cookie, token := fetchToken(t, r)
+	_, otherToken := fetchToken(t, r)
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		header string
+	}{
+		{"no token", cookie, ""},
+		{"wrong token", cookie, otherToken},
+		{"no cookie", nil, token},
+		{"forged cookie", &http.Cookie{Name: "csrf_token", Value: "forged"}, "forged"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(r, "/orders", tt.cookie, tt.header, url.Values{"item": {"book"}})
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("Expected 403, got %d", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), "CSRF_TOKEN_INVALID") {
+				t.Errorf("Unexpected body: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCSRF_ExemptPaths(t *testing.T) {
+	r := newRouter(&csrf.Config{ExemptPaths: []string{"/webhooks/**"}})
+
+	if w := post(r, "/webhooks/stripe", nil, "", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected exempt path to pass, got %d", w.Code)
+	}
+	if w := post(r, "/orders", nil, "", nil); w.Code != http.StatusForbidden {
+		t.Errorf("Expected other paths to be checked, got %d", w.Code)
+	}
+}
+
+func TestCSRF_Factory(t *testing.T) {
+	mw := csrf.MiddlewareFactory(map[string]any{
+		"cookie_name":  "xsrf",
+		"same_site":    "strict",
+		"exempt_paths": []string{"/api/**"},
+	})
+	r := router.New("test-router")
+	r.Use(mw)
+	r.GET("/", func(c *request.Context) error { return c.Api.Ok(nil) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "xsrf" || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Errorf("Unexpected cookie: %v", cookies)
+	}
+}
+
+func TestCSRF_RejectsUnsignedTokens(t *testing.T) {
+	r := newRouter(&csrf.Config{Secret: "app-secret"})
+	_, foreign := fetchToken(t, newRouter(&csrf.Config{Secret: "other-secret"}))
+
+	for name, token := range map[string]string{
+		"unsigned random": strings.Repeat("A", 43),
+		"other secret":    foreign,
+	} {
+		cookie := &http.Cookie{Name: "csrf_token", Value: token}
+		if w := post(r, "/orders", cookie, token, url.Values{"item": {"book"}}); w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", name, w.Code)
+		}
+	}
+}
+
+func TestCSRF_SessionBoundTokens(t *testing.T) {
+	r := newRouter(&csrf.Config{
+		Secret:    "app-secret",
+		SessionID: func(c *request.Context) string { return c.R.Header.Get("X-Session") },
+	})
+
+	req := httptest.NewRequest("GET", "/form", nil)
+	req.Header.Set("X-Session", "attacker")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	tossed := w.Result().Cookies()[0]
+
+	for session, want := range map[string]int{"attacker": http.StatusOK, "victim": http.StatusForbidden} {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader("item=book"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Session", session)
+		req.Header.Set("X-CSRF-Token", tossed.Value)
+		req.AddCookie(tossed)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Session %s: expected %d, got %d", session, want, w.Code)
+		}
+	}
+}
+
+func TestCSRF_MultipartNeedsHeader(t *testing.T) {
+	r := newRouter(&csrf.Config{})
+	cookie, token := fetchToken(t, r)
+
+	body := "--b\r\nContent-Disposition: form-data; name=\"csrf_token\"\r\n\r\n" + token + "\r\n--b--\r\n"
+	for header, want := range map[string]int{"": http.StatusForbidden, token: http.StatusOK} {
+		req := httptest.NewRequest("POST", "/webhooks/stripe", strings.NewReader(body))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+		req.AddCookie(cookie)
+		if header != "" {
+			req.Header.Set("X-CSRF-Token", header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Header %q: expected %d, got %d", header, want, w.Code)
+		}
+	}
+}
+
+func TestCSRF_SecureUsesHostPrefix(t *testing.T) {
+	r := newRouter(&csrf.Config{Secure: true})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/form", nil))
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Name != "__Host-csrf_token" {
+		t.Errorf("Expected a __Host-csrf_token cookie, got %v", cookies)
+	}
+}
@@ -0,0 +1,261 @@
+package etag
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const ETAG_TYPE = "etag"
+const PARAMS_WEAK = "weak"
+const PARAMS_MAX_BODY_SIZE = "max_body_size"
+
+type Config struct {
+	// Weak generates weak ETags (W/"...")
+	Weak bool
+
+	// MaxBodySize skips ETag generation for larger bodies (in bytes), which
+	// are passed through instead of buffered
+	MaxBodySize int
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Weak:        false,
+		MaxBodySize: 4 * 1024 * 1024, // 4MB
+	}
+}
+
+// middleware that sets ETag on successful GET/HEAD responses and answers
+// If-None-Match with 304 Not Modified.
+//
+// The ETag is computed from the uncompressed body, so it is the same with or
+// without Accept-Encoding, in either order relative to gzip_compression.
+// Vary: Accept-Encoding is always set.
+//
+// Flushed (streaming, SSE) and hijacked responses, and bodies over
+// MaxBodySize, are passed through without an ETag.
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defConfig.MaxBodySize
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		if c.R.Method != http.MethodGet && c.R.Method != http.MethodHead {
+			return c.Next()
+		}
+
+		orig := c.W.ResponseWriter
+		rec := &recorder{orig: orig, header: orig.Header().Clone(), maxBody: cfg.MaxBodySize}
+		c.W.ResponseWriter = response.WrapWriter(orig, rec)
+		err := c.Next()
+		// Materialize the response so its body can be hashed
+		c.WriteResponse(err)
+		c.W.ResponseWriter = orig
+
+		if rec.passthrough {
+			// Streamed or too large: already sent without an ETag
+			return err
+		}
+		rec.copyHeader()
+
+		header := orig.Header()
+		status := rec.statusCode()
+		if status == http.StatusOK && header.Get("ETag") == "" {
+			if tag, ok := computeETag(header, rec.body.Bytes(), cfg.Weak); ok {
+				header.Set("ETag", tag)
+			}
+		}
+
+		if status == http.StatusOK && header.Get("ETag") != "" &&
+			noneMatch(c.R.Header.Get("If-None-Match"), header.Get("ETag")) {
+			for _, h := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+				header.Del(h)
+			}
+			orig.WriteHeader(http.StatusNotModified)
+			return err
+		}
+
+		orig.WriteHeader(status)
+		if c.R.Method != http.MethodHead {
+			orig.Write(rec.body.Bytes())
+		}
+		return err
+	})
+}
+
+// computeETag hashes the uncompressed body. If an inner middleware already
+// gzip-compressed the body, it is decompressed first.
+func computeETag(header http.Header, body []byte, weak bool) (string, bool) {
+	switch strings.ToLower(header.Get("Content-Encoding")) {
+	case "":
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return "", false
+		}
+		plain, err := io.ReadAll(gr)
+		if err != nil {
+			return "", false
+		}
+		body = plain
+	default:
+		// Unknown encoding, cannot compute a representation-independent tag
+		return "", false
+	}
+
+	sum := sha256.Sum256(body)
+	tag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	if weak {
+		tag = "W/" + tag
+	}
+	return tag, true
+}
+
+// noneMatch reports whether If-None-Match matches the ETag (weak comparison)
+func noneMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	current := strings.TrimPrefix(etag, "W/")
+	for tag := range strings.SplitSeq(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary adds a value to the Vary header if not already present
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values("Vary") {
+		for existing := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), value) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", value)
+}
+
+// recorder buffers the response produced by inner handlers. Responses
+// that are flushed (streaming, SSE), hijacked or larger than maxBody are
+// passed through unbuffered instead, without an ETag.
+type recorder struct {
+	orig        http.ResponseWriter
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	maxBody     int
+	passthrough bool
+}
+
+func (r *recorder) Header() http.Header {
+	if r.passthrough {
+		return r.orig.Header()
+	}
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.passthrough {
+		r.orig.WriteHeader(code)
+		return
+	}
+	if r.status == 0 {
+		r.status = code
+	}
+	if strings.HasPrefix(r.header.Get("Content-Type"), "text/event-stream") {
+		r.startPassthrough()
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.passthrough {
+		return r.orig.Write(b)
+	}
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if r.body.Len()+len(b) > r.maxBody {
+		r.startPassthrough()
+		return r.orig.Write(b)
+	}
+	return r.body.Write(b)
+}
+
+// Flush sends what was buffered and passes the rest of the response through
+func (r *recorder) Flush() {
+	r.startPassthrough()
+	_ = http.NewResponseController(r.orig).Flush()
+}
+
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.passthrough = true
+	return http.NewResponseController(r.orig).Hijack()
+}
+
+// startPassthrough writes the buffered response to orig; later writes go
+// to orig directly
+func (r *recorder) startPassthrough() {
+	if r.passthrough {
+		return
+	}
+	r.passthrough = true
+	r.copyHeader()
+	r.orig.WriteHeader(r.statusCode())
+	if r.body.Len() > 0 {
+		r.orig.Write(r.body.Bytes())
+		r.body.Reset()
+	}
+}
+
+// copyHeader replaces the header of orig with the recorded one
+func (r *recorder) copyHeader() {
+	header := r.orig.Header()
+	for k := range header {
+		delete(header, k)
+	}
+	for k, v := range r.header {
+		header[k] = v
+	}
+	addVary(header, "Accept-Encoding")
+}
+
+func (r *recorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Weak:        utils.GetValueFromMap(params, PARAMS_WEAK, defConfig.Weak),
+		MaxBodySize: utils.GetValueFromMap(params, PARAMS_MAX_BODY_SIZE, defConfig.MaxBodySize),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(ETAG_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
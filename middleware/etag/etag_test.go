@@ -0,0 +1,160 @@
+package etag_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/etag"
+	"github.com/primadi/lokstra/middleware/gzipcompression"
+)
+
+var largeText = strings.Repeat("lokstra ", 300)
+
+func newRouter(middlewares ...any) router.Router {
+	r := router.New("test-router")
+	r.Use(middlewares...)
+	r.GET("/doc", func(c *request.Context) error {
+		return c.Api.Ok(map[string]string{"text": largeText})
+	})
+	return r
+}
+
+func get(r router.Router, acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/doc", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestETag_WithGzip(t *testing.T) {
+	orders := map[string][]any{
+		"gzip outer": {
+			gzipcompression.Middleware(&gzipcompression.Config{MinSize: 100}),
+			etag.Middleware(&etag.Config{}),
+		},
+		"etag outer": {
+			etag.Middleware(&etag.Config{}),
+			gzipcompression.Middleware(&gzipcompression.Config{MinSize: 100}),
+		},
+	}
+
+	var tags []string
+	for name, mws := range orders {
+		t.Run(name, func(t *testing.T) {
+			r := newRouter(mws...)
+
+			plain := get(r, "", "")
+			compressed := get(r, "gzip", "")
+
+			if plain.Code != 200 || compressed.Code != 200 {
+				t.Fatalf("Expected 200, got %d / %d", plain.Code, compressed.Code)
+			}
+			if compressed.Header().Get("Content-Encoding") != "gzip" {
+				t.Fatalf("Expected gzip response, got headers %v", compressed.Header())
+			}
+			if plain.Header().Get("Content-Encoding") != "" {
+				t.Errorf("Expected identity response without Accept-Encoding")
+			}
+
+			tag := plain.Header().Get("ETag")
+			if tag == "" || tag != compressed.Header().Get("ETag") {
+				t.Errorf("ETag must be computed pre-compression: %q vs %q",
+					tag, compressed.Header().Get("ETag"))
+			}
+			for _, w := range []*httptest.ResponseRecorder{plain, compressed} {
+				if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+					t.Errorf("Expected Vary: Accept-Encoding, got %q", w.Header().Get("Vary"))
+				}
+			}
+
+			gr, err := gzip.NewReader(compressed.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(gr)
+			if string(body) != plain.Body.String() {
+				t.Error("Decompressed body differs from identity body")
+			}
+
+			// 304 with and without compression, using the tag from either representation
+			for _, ae := range []string{"", "gzip"} {
+				w := get(r, ae, tag)
+				if w.Code != 304 {
+					t.Errorf("Accept-Encoding %q: expected 304, got %d", ae, w.Code)
+				}
+				if w.Body.Len() != 0 || w.Header().Get("Content-Encoding") != "" {
+					t.Errorf("304 must not carry a body or Content-Encoding")
+				}
+				if w.Header().Get("ETag") != tag {
+					t.Errorf("304 must repeat the ETag")
+				}
+			}
+
+			if w := get(r, "gzip", `"stale"`); w.Code != 200 {
+				t.Errorf("Stale If-None-Match: expected 200, got %d", w.Code)
+			}
+			tags = append(tags, tag)
+		})
+	}
+
+	if len(tags) == 2 && tags[0] != tags[1] {
+		t.Errorf("ETag must not depend on middleware order: %v", tags)
+	}
+}
+
+func TestETag_Weak(t *testing.T) {
+	r := newRouter(etag.Middleware(&etag.Config{Weak: true}))
+
+	w := get(r, "", "")
+	tag := w.Header().Get("ETag")
+	if !strings.HasPrefix(tag, `W/"`) {
+		t.Fatalf("Expected weak ETag, got %q", tag)
+	}
+	// If-None-Match uses weak comparison
+	if w := get(r, "", strings.TrimPrefix(tag, "W/")); w.Code != 304 {
+		t.Errorf("Expected 304 for weak match, got %d", w.Code)
+	}
+}
+
+func TestETag_PassesThroughStreamsAndLargeBodies(t *testing.T) {
+	r := router.New("test-router")
+	r.Use(etag.Middleware(&etag.Config{MaxBodySize: 1024}))
+	r.GET("/events", func(c *request.Context) error {
+		return c.Resp.StreamTo("text/event-stream", func(s *response.StreamWriter) error {
+			return s.SendEvent("tick", "1")
+		})
+	})
+	r.GET("/download", func(c *request.Context) error {
+		return c.Api.Ok(map[string]string{"text": largeText})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	if !strings.Contains(w.Body.String(), "data: 1") || !w.Flushed {
+		t.Errorf("Expected the stream flushed through, got flushed=%v body %q", w.Flushed, w.Body.String())
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Error("Expected no ETag on a streamed response")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/download", nil))
+	if w.Code != 200 || !strings.Contains(w.Body.String(), largeText) {
+		t.Fatalf("Expected the large body passed through, got %d (%d bytes)", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Error("Expected no ETag on a body over MaxBodySize")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
 	"github.com/primadi/lokstra/lokstra_registry"
 )
 
@@ -61,6 +62,9 @@ func Middleware(cfg *Config) request.HandlerFunc {
 	}
 
 	return request.HandlerFunc(func(c *request.Context) error {
+		// The response depends on Accept-Encoding, tell caches to key on it
+		addVary(c.W.Header(), "Accept-Encoding")
+
 		// Check if client accepts gzip encoding
 		acceptEncoding := c.R.Header.Get("Accept-Encoding")
 		if !strings.Contains(acceptEncoding, "gzip") {
@@ -77,14 +81,19 @@ func Middleware(cfg *Config) request.HandlerFunc {
 		}
 
 		// Replace the underlying response writer
-		c.W.ResponseWriter = gzipWriter
+		c.W.ResponseWriter = response.WrapWriter(originalWriter, gzipWriter)
 
-		// Call next handler
+		// Call next handler, then write the response while the gzip writer is
+		// still installed (otherwise it would be written after we return)
 		err := c.Next()
+		c.WriteResponse(err)
 
 		// Close gzip writer if it was used
 		if gzipWriter.gzipWriter != nil {
 			gzipWriter.gzipWriter.Close()
+		} else if gzipWriter.statusCode > 0 && !gzipWriter.written {
+			// Status set but no body written (e.g. 304), flush the deferred header
+			originalWriter.WriteHeader(gzipWriter.statusCode)
 		}
 
 		// Rerepository original writer
@@ -126,6 +135,13 @@ type gzipResponseWriter struct {
 func (w *gzipResponseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 
+	// Responses without a body are never compressed
+	if !bodyAllowed(statusCode) || w.context.R.Method == http.MethodHead {
+		w.ResponseWriter.WriteHeader(statusCode)
+		w.written = true
+		return
+	}
+
 	// Check if content type should be excluded
 	contentType := w.Header().Get("Content-Type")
 	for _, excluded := range w.config.ExcludedContentTypes {
@@ -187,6 +203,23 @@ func (w *gzipResponseWriter) Flush() {
 	}
 }
 
+// bodyAllowed reports whether a response with the given status can have a body
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// addVary adds a value to the Vary header if not already present
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values("Vary") {
+		for existing := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), value) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", value)
+}
+
 // Ensure gzipResponseWriter implements http.Flusher
 var _ http.Flusher = (*gzipResponseWriter)(nil)
 var _ io.WriteCloser = (*gzipResponseWriter)(nil)
@@ -0,0 +1,246 @@
+package loadshed
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+const LOAD_SHED_TYPE = "load_shed"
+const PARAMS_MAX_IN_FLIGHT = "max_in_flight"
+const PARAMS_MAX_LATENCY = "max_latency"
+const PARAMS_PROBE_INTERVAL = "probe_interval"
+const PARAMS_RETRY_AFTER = "retry_after"
+const PARAMS_EXEMPT_PATHS = "exempt_paths"
+const PARAMS_MESSAGE = "message"
+const PARAMS_METRICS_SERVICE = "metrics_service"
+const PARAMS_GAUGE_NAME = "gauge_name"
+
+type Config struct {
+	// MaxInFlight is the maximum number of concurrent requests.
+	// Requests above this threshold are shed with 503.
+	MaxInFlight int
+
+	// MaxLatency sheds requests while the moving average latency is above it
+	// 0 disables the latency signal
+	MaxLatency time.Duration
+
+	// ProbeInterval lets one request through per interval while shedding on
+	// latency, so the average can recover once the slow period ends
+	ProbeInterval time.Duration
+
+	// RetryAfter is sent in the Retry-After header of shed responses
+	RetryAfter time.Duration
+
+	// ExemptPaths are never shed (supports * and ** wildcards)
+	// Example: ["/health", "/ready", "/api/payments/**"]
+	ExemptPaths []string
+
+	// IsPriority marks additional requests that must never be shed
+	IsPriority func(c *request.Context) bool
+
+	// Message is the error message of shed responses
+	Message string
+
+	// Metrics receives the current in-flight count as a gauge (optional)
+	Metrics serviceapi.Metrics
+
+	// GaugeName is the gauge name used with Metrics
+	GaugeName string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		MaxInFlight:   1000,
+		MaxLatency:    0,
+		ProbeInterval: time.Second,
+		RetryAfter:    5 * time.Second,
+		ExemptPaths:   []string{"/health", "/healthz", "/ready"},
+		Message:       "Server is overloaded, please retry later",
+		GaugeName:     "http_requests_in_flight",
+	}
+}
+
+// Shedder tracks load and rejects requests above the configured thresholds
+type Shedder struct {
+	cfg      *Config
+	inFlight atomic.Int64
+	latency  atomic.Int64 // moving average in nanoseconds
+	shed     atomic.Int64
+
+	lastProbe atomic.Int64 // unix nanos of the last latency probe
+}
+
+// NewShedder creates a load shedder with the given config
+func NewShedder(cfg *Config) *Shedder {
+	defConfig := DefaultConfig()
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = defConfig.MaxInFlight
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = defConfig.ProbeInterval
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = defConfig.RetryAfter
+	}
+	if cfg.ExemptPaths == nil {
+		cfg.ExemptPaths = defConfig.ExemptPaths
+	}
+	if cfg.Message == "" {
+		cfg.Message = defConfig.Message
+	}
+	if cfg.GaugeName == "" {
+		cfg.GaugeName = defConfig.GaugeName
+	}
+	return &Shedder{cfg: cfg}
+}
+
+// InFlight returns the current number of in-flight requests
+func (s *Shedder) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// Latency returns the moving average request latency
+func (s *Shedder) Latency() time.Duration {
+	return time.Duration(s.latency.Load())
+}
+
+// ShedCount returns the total number of shed requests
+func (s *Shedder) ShedCount() int64 {
+	return s.shed.Load()
+}
+
+// Handler returns the load shedding middleware
+func (s *Shedder) Handler() request.HandlerFunc {
+	retryAfter := strconv.Itoa(int((s.cfg.RetryAfter + time.Second - 1) / time.Second))
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		current := s.inFlight.Add(1)
+		s.reportGauge(current)
+		defer func() {
+			s.reportGauge(s.inFlight.Add(-1))
+		}()
+
+		probe := false
+		if current > int64(s.cfg.MaxInFlight) || s.slow() {
+			if !s.isExempt(c) {
+				probe = current <= int64(s.cfg.MaxInFlight) && s.claimProbe()
+				if !probe {
+					s.shed.Add(1)
+					c.W.Header().Set("Retry-After", retryAfter)
+					return c.Api.Error(http.StatusServiceUnavailable, "SERVICE_OVERLOADED", s.cfg.Message)
+				}
+			}
+		}
+
+		start := time.Now()
+		err := c.Next()
+		s.observeLatency(time.Since(start), probe)
+		return err
+	})
+}
+
+func (s *Shedder) slow() bool {
+	return s.cfg.MaxLatency > 0 && s.Latency() > s.cfg.MaxLatency
+}
+
+// claimProbe reports whether this request may probe the latency, at most
+// once per ProbeInterval
+func (s *Shedder) claimProbe() bool {
+	now := time.Now().UnixNano()
+	last := s.lastProbe.Load()
+	if now-last < int64(s.cfg.ProbeInterval) {
+		return false
+	}
+	return s.lastProbe.CompareAndSwap(last, now)
+}
+
+func (s *Shedder) isExempt(c *request.Context) bool {
+	requestPath := path.Clean(c.R.URL.Path)
+	for _, pattern := range s.cfg.ExemptPaths {
+		if matchPath(requestPath, pattern) {
+			return true
+		}
+	}
+	return s.cfg.IsPriority != nil && s.cfg.IsPriority(c)
+}
+
+// observeLatency updates the exponential moving average (alpha = 1/8).
+// A fast probe resets the average: only probes pass while shedding on
+// latency, so the average would otherwise take many intervals to recover.
+func (s *Shedder) observeLatency(d time.Duration, probe bool) {
+	for {
+		old := s.latency.Load()
+		next := int64(d)
+		if old != 0 && (!probe || d > s.cfg.MaxLatency) {
+			next = old + (int64(d)-old)/8
+		}
+		if s.latency.CompareAndSwap(old, next) {
+			if s.cfg.MaxLatency > 0 && old <= int64(s.cfg.MaxLatency) && next > int64(s.cfg.MaxLatency) {
+				// Just turned slow: first probe after one interval
+				s.lastProbe.Store(time.Now().UnixNano())
+			}
+			return
+		}
+	}
+}
+
+func (s *Shedder) reportGauge(current int64) {
+	if s.cfg.Metrics != nil {
+		s.cfg.Metrics.SetGauge(s.cfg.GaugeName, float64(current), nil)
+	}
+}
+
+// matchPath supports exact paths, "/prefix/**" and single-segment "*"
+func matchPath(requestPath, pattern string) bool {
+	if requestPath == pattern {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "**"); ok {
+		return strings.HasPrefix(requestPath, prefix)
+	}
+	if !strings.Contains(pattern, "*") {
+		return false
+	}
+	matched, err := path.Match(pattern, requestPath)
+	return err == nil && matched
+}
+
+// sheds requests with 503 + Retry-After when the server is overloaded
+func Middleware(cfg *Config) request.HandlerFunc {
+	return NewShedder(cfg).Handler()
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		MaxInFlight:   utils.GetValueFromMap(params, PARAMS_MAX_IN_FLIGHT, defConfig.MaxInFlight),
+		MaxLatency:    utils.GetValueFromMap(params, PARAMS_MAX_LATENCY, defConfig.MaxLatency),
+		ProbeInterval: utils.GetValueFromMap(params, PARAMS_PROBE_INTERVAL, defConfig.ProbeInterval),
+		RetryAfter:    utils.GetValueFromMap(params, PARAMS_RETRY_AFTER, defConfig.RetryAfter),
+		ExemptPaths:   utils.GetValueFromMap(params, PARAMS_EXEMPT_PATHS, defConfig.ExemptPaths),
+		Message:       utils.GetValueFromMap(params, PARAMS_MESSAGE, defConfig.Message),
+		GaugeName:     utils.GetValueFromMap(params, PARAMS_GAUGE_NAME, defConfig.GaugeName),
+	}
+	if metricsName := utils.GetValueFromMap(params, PARAMS_METRICS_SERVICE, ""); metricsName != "" {
+		cfg.Metrics = lokstra_registry.GetService[serviceapi.Metrics](metricsName)
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(LOAD_SHED_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
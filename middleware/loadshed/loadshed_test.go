@@ -0,0 +1,187 @@
+package loadshed_test
+
+import (
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/loadshed"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+type gaugeRecorder struct {
+	mu  sync.Mutex
+	max float64
+}
+
+func (g *gaugeRecorder) IncCounter(string, serviceapi.Labels)                {}
+func (g *gaugeRecorder) ObserveHistogram(string, float64, serviceapi.Labels) {}
+func (g *gaugeRecorder) SetGauge(_ string, value float64, _ serviceapi.Labels) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if value > g.max {
+		g.max = value
+	}
+}
+
+func TestLoadShed_ShedsOverThreshold(t *testing.T) {
+	gauge := &gaugeRecorder{}
+	shedder := loadshed.NewShedder(&loadshed.Config{
+		MaxInFlight: 2,
+		RetryAfter:  3 * time.Second,
+		ExemptPaths: []string{"/health"},
+		IsPriority: func(c *request.Context) bool {
+			return c.R.Header.Get("X-Priority") == "high"
+		},
+		Metrics: gauge,
+	})
+
+	release := make(chan struct{})
+	r := router.New("test-router")
+	r.Use(shedder.Handler())
+	r.GET("/slow", func(c *request.Context) error {
+		<-release
+		return c.Api.Ok("done")
+	})
+	r.GET("/health", func(c *request.Context) error {
+		return c.Api.Ok("healthy")
+	})
+
+	// Occupy all slots
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for shedder.InFlight() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for in-flight requests")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Over the threshold: shed
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	if w.Code != 503 {
+		t.Errorf("Expected 503 for shed request, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "3" {
+		t.Errorf("Expected Retry-After: 3, got %q", w.Header().Get("Retry-After"))
+	}
+
+	// Exempt health route still succeeds
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	if w.Code != 200 {
+		t.Errorf("Expected 200 for health route, got %d", w.Code)
+	}
+
+	// Priority request is never shed
+	done := make(chan int)
+	go func() {
+		req := httptest.NewRequest("GET", "/slow", nil)
+		req.Header.Set("X-Priority", "high")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		done <- w.Code
+	}()
+
+	close(release)
+	if code := <-done; code != 200 {
+		t.Errorf("Expected 200 for priority request, got %d", code)
+	}
+	wg.Wait()
+
+	if shedder.ShedCount() != 1 {
+		t.Errorf("Expected 1 shed request, got %d", shedder.ShedCount())
+	}
+	if shedder.InFlight() != 0 {
+		t.Errorf("Expected 0 in-flight requests after completion, got %d", shedder.InFlight())
+	}
+	if gauge.max < 3 {
+		t.Errorf("Expected gauge to report load >= 3, got %v", gauge.max)
+	}
+}
+
+func TestLoadShed_LatencySignal(t *testing.T) {
+	shedder := loadshed.NewShedder(&loadshed.Config{
+		MaxInFlight: 100,
+		MaxLatency:  5 * time.Millisecond,
+	})
+
+	r := router.New("test-router")
+	r.Use(shedder.Handler())
+	r.GET("/slow", func(c *request.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return c.Api.Ok("done")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	if w.Code != 200 {
+		t.Fatalf("Expected first request to succeed, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	if w.Code != 503 {
+		t.Errorf("Expected 503 while latency is above threshold, got %d", w.Code)
+	}
+}
+
+func TestLoadShed_LatencyRecovers(t *testing.T) {
+	shedder := loadshed.NewShedder(&loadshed.Config{
+		MaxInFlight:   100,
+		MaxLatency:    5 * time.Millisecond,
+		ProbeInterval: 20 * time.Millisecond,
+	})
+
+	var slow atomic.Bool
+	slow.Store(true)
+	r := router.New("test-router")
+	r.Use(shedder.Handler())
+	r.GET("/work", func(c *request.Context) error {
+		if slow.Load() {
+			time.Sleep(20 * time.Millisecond)
+		}
+		return c.Api.Ok("done")
+	})
+	get := func() int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/work", nil))
+		return w.Code
+	}
+
+	if code := get(); code != 200 {
+		t.Fatalf("Expected first request to succeed, got %d", code)
+	}
+	if code := get(); code != 503 {
+		t.Fatalf("Expected 503 while latency is above threshold, got %d", code)
+	}
+
+	// Slow period ends: after one probe interval a probe passes and
+	// resets the average, so normal traffic resumes
+	slow.Store(false)
+	time.Sleep(30 * time.Millisecond)
+	if code := get(); code != 200 {
+		t.Fatalf("Expected probe request to pass, got %d", code)
+	}
+	for i := range 5 {
+		if code := get(); code != 200 {
+			t.Fatalf("Expected request %d after recovery to succeed, got %d", i, code)
+		}
+	}
+	if shedder.Latency() > 5*time.Millisecond {
+		t.Errorf("Expected latency below threshold after recovery, got %v", shedder.Latency())
+	}
+}
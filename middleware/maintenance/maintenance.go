@@ -0,0 +1,154 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+const MAINTENANCE_TYPE = "maintenance"
+const PARAMS_CONFIG_KEY = "config_key"
+const PARAMS_FEATURE_FLAG_SERVICE = "feature_flag_service"
+const PARAMS_FEATURE_FLAG = "feature_flag"
+const PARAMS_MESSAGE = "message"
+const PARAMS_HTML = "html"
+const PARAMS_RETRY_AFTER = "retry_after"
+const PARAMS_ALLOW_PATHS = "allow_paths"
+const PARAMS_ALLOW_IPS = "allow_ips"
+
+type Config struct {
+	// IsEnabled reports whether maintenance mode is on.
+	// Evaluated on every request, so it can be toggled at runtime.
+	// Use FlagFromConfig or FlagFromFeatureFlag for common sources.
+	IsEnabled func() bool
+
+	// Message is returned in the JSON error body
+	Message string
+
+	// HTML, if set, is returned instead of JSON to clients accepting text/html
+	HTML string
+
+	// RetryAfter is sent in the Retry-After header (0 omits the header)
+	RetryAfter time.Duration
+
+	// AllowPaths always pass through (supports "/prefix/**")
+	// Example: ["/health", "/admin/**"]
+	AllowPaths []string
+
+	// AllowIPs always pass through (IPs or CIDRs, e.g. "10.0.0.0/8").
+	// Matched against c.ClientIP(): forwarding headers count only when sent
+	// by a trusted proxy (see request.SetTrustedProxies).
+	AllowIPs []string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		IsEnabled:  FlagFromConfig("maintenance.enabled"),
+		Message:    "Service is under maintenance, please try again later",
+		RetryAfter: 5 * time.Minute,
+		AllowPaths: []string{"/health", "/healthz", "/ready"},
+		AllowIPs:   []string{},
+	}
+}
+
+// FlagFromConfig reads maintenance mode from a runtime config key
+// (toggle with lokstra_registry.SetConfig(key, true))
+func FlagFromConfig(key string) func() bool {
+	return func() bool {
+		return lokstra_registry.GetConfig(key, false)
+	}
+}
+
+// FlagFromFeatureFlag reads maintenance mode from a feature flag
+func FlagFromFeatureFlag(ff serviceapi.FeatureFlag, flag string) func() bool {
+	return func() bool {
+		return ff.IsEnabled(flag, context.Background())
+	}
+}
+
+// middleware that returns 503 while maintenance mode is on
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.IsEnabled == nil {
+		cfg.IsEnabled = defConfig.IsEnabled
+	}
+	if cfg.Message == "" {
+		cfg.Message = defConfig.Message
+	}
+	if cfg.AllowPaths == nil {
+		cfg.AllowPaths = defConfig.AllowPaths
+	}
+
+	allowNets, err := utils.ParseIPNets(cfg.AllowIPs)
+	if err != nil {
+		logger.LogWarning("maintenance: ignoring allow IPs: %v", err)
+	}
+	retryAfter := ""
+	if cfg.RetryAfter > 0 {
+		retryAfter = strconv.Itoa(int((cfg.RetryAfter + time.Second - 1) / time.Second))
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		if !cfg.IsEnabled() || isAllowedPath(c.R.URL.Path, cfg.AllowPaths) ||
+			utils.IPInNets(c.ClientIP(), allowNets) {
+			return c.Next()
+		}
+
+		if retryAfter != "" {
+			c.W.Header().Set("Retry-After", retryAfter)
+		}
+		if cfg.HTML != "" && strings.Contains(c.R.Header.Get("Accept"), "text/html") {
+			c.Resp.WithStatus(http.StatusServiceUnavailable)
+			return c.Resp.Html(cfg.HTML)
+		}
+		return c.Api.Error(http.StatusServiceUnavailable, "MAINTENANCE", cfg.Message)
+	})
+}
+
+func isAllowedPath(requestPath string, patterns []string) bool {
+	requestPath = path.Clean(requestPath)
+	for _, pattern := range patterns {
+		if requestPath == pattern {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "**"); ok && strings.HasPrefix(requestPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		IsEnabled:  FlagFromConfig(utils.GetValueFromMap(params, PARAMS_CONFIG_KEY, "maintenance.enabled")),
+		Message:    utils.GetValueFromMap(params, PARAMS_MESSAGE, defConfig.Message),
+		HTML:       utils.GetValueFromMap(params, PARAMS_HTML, ""),
+		RetryAfter: utils.GetValueFromMap(params, PARAMS_RETRY_AFTER, defConfig.RetryAfter),
+		AllowPaths: utils.GetValueFromMap(params, PARAMS_ALLOW_PATHS, defConfig.AllowPaths),
+		AllowIPs:   utils.GetValueFromMap(params, PARAMS_ALLOW_IPS, defConfig.AllowIPs),
+	}
+	if ffName := utils.GetValueFromMap(params, PARAMS_FEATURE_FLAG_SERVICE, ""); ffName != "" {
+		ff := lokstra_registry.GetService[serviceapi.FeatureFlag](ffName)
+		cfg.IsEnabled = FlagFromFeatureFlag(ff, utils.GetValueFromMap(params, PARAMS_FEATURE_FLAG, "maintenance"))
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(MAINTENANCE_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
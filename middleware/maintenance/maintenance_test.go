@@ -0,0 +1,147 @@
+package maintenance_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/middleware/maintenance"
+)
+
+func newTestRouter(cfg *maintenance.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(maintenance.Middleware(cfg))
+	r.GET("/users", func(c *request.Context) error {
+		return c.Api.Ok("users")
+	})
+	r.GET("/health", func(c *request.Context) error {
+		return c.Api.Ok("healthy")
+	})
+	return r
+}
+
+func TestMaintenance_OnOff(t *testing.T) {
+	var enabled atomic.Bool
+	r := newTestRouter(&maintenance.Config{
+		IsEnabled:  enabled.Load,
+		Message:    "Back soon",
+		RetryAfter: 90 * time.Second,
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != 200 {
+		t.Fatalf("Expected 200 while off, got %d", w.Code)
+	}
+
+	enabled.Store(true)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != 503 {
+		t.Fatalf("Expected 503 while on, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("Expected Retry-After 90, got %q", got)
+	}
+	if !strings.Contains(w.Body.String(), "MAINTENANCE") || !strings.Contains(w.Body.String(), "Back soon") {
+		t.Errorf("Unexpected body: %s", w.Body.String())
+	}
+
+	enabled.Store(false)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != 200 {
+		t.Errorf("Expected 200 after turning off, got %d", w.Code)
+	}
+}
+
+func TestMaintenance_HTMLBody(t *testing.T) {
+	r := newTestRouter(&maintenance.Config{
+		IsEnabled: func() bool { return true },
+		HTML:      "<h1>Down for maintenance</h1>",
+	})
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 503 {
+		t.Fatalf("Expected 503, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("Expected HTML content type, got %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "Down for maintenance") {
+		t.Errorf("Unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestMaintenance_AllowlistBypass(t *testing.T) {
+	r := newTestRouter(&maintenance.Config{
+		IsEnabled: func() bool { return true },
+		AllowIPs:  []string{"10.0.0.0/8", "192.168.1.5"},
+	})
+
+	request.SetTrustedProxies("172.16.0.1")
+	t.Cleanup(func() { request.SetTrustedProxies() })
+
+	tests := []struct {
+		name         string
+		path         string
+		remoteAddr   string
+		forwardedFor string
+		status       int
+	}{
+		{name: "health path", path: "/health", remoteAddr: "203.0.113.1:1234", status: 200},
+		{name: "admin CIDR", path: "/users", remoteAddr: "10.1.2.3:1234", status: 200},
+		{name: "admin IP", path: "/users", remoteAddr: "192.168.1.5:1234", status: 200},
+		{name: "other IP", path: "/users", remoteAddr: "192.168.1.6:1234", status: 503},
+		{name: "spoofed forwarded header", path: "/users", remoteAddr: "203.0.113.1:1234",
+			forwardedFor: "10.1.2.3", status: 503},
+		{name: "admin IP via trusted proxy", path: "/users", remoteAddr: "172.16.0.1:1234",
+			forwardedFor: "10.1.2.3", status: 200},
+		{name: "other IP via trusted proxy", path: "/users", remoteAddr: "172.16.0.1:1234",
+			forwardedFor: "203.0.113.1", status: 503},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestMaintenance_FlagFromConfig(t *testing.T) {
+	r := newTestRouter(&maintenance.Config{
+		IsEnabled: maintenance.FlagFromConfig("test.maintenance.enabled"),
+	})
+
+	lokstra_registry.SetConfig("test.maintenance.enabled", true)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != 503 {
+		t.Errorf("Expected 503 when config is on, got %d", w.Code)
+	}
+
+	lokstra_registry.SetConfig("test.maintenance.enabled", false)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != 200 {
+		t.Errorf("Expected 200 when config is off, got %d", w.Code)
+	}
+}
@@ -0,0 +1,155 @@
+package limiter
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+)
+
+const (
+	HeaderLimit     = "X-RateLimit-Limit"
+	HeaderRemaining = "X-RateLimit-Remaining"
+	HeaderReset     = "X-RateLimit-Reset"
+)
+
+type Config struct {
+	// Limit is the number of requests allowed per key and window
+	Limit int
+
+	// Window is the length of a rate limit window
+	Window time.Duration
+
+	// KeyFunc returns the key requests are counted by (default: ClientIP,
+	// the remote address unless it is a trusted proxy)
+	KeyFunc func(c *request.Context) string
+
+	// Headers sends X-RateLimit-Limit, X-RateLimit-Remaining and
+	// X-RateLimit-Reset (seconds until the window resets) so clients can
+	// self-regulate. Off by default to not expose limits.
+	Headers bool
+
+	// Message is the error message of limited responses
+	Message string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Limit:   100,
+		Window:  time.Minute,
+		KeyFunc: ClientIP,
+		Headers: false,
+		Message: "Too many requests, please retry later",
+	}
+}
+
+// ClientIP keys requests by the client IP, read from forwarding headers
+// only when sent by a trusted proxy (see request.SetTrustedProxies)
+func ClientIP(c *request.Context) string {
+	return c.ClientIP()
+}
+
+// RemoteAddr keys requests by the host of the connection's remote address
+func RemoteAddr(c *request.Context) string {
+	host, _, err := net.SplitHostPort(c.R.RemoteAddr)
+	if err != nil {
+		return c.R.RemoteAddr
+	}
+	return host
+}
+
+// window counts the requests of one key in the current window
+type window struct {
+	start time.Time
+	count int
+}
+
+// Limiter counts requests per key in fixed windows
+type Limiter struct {
+	cfg *Config
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+// New creates a rate limiter with the given config
+func New(cfg *Config) *Limiter {
+	defConfig := DefaultConfig()
+	if cfg.Limit <= 0 {
+		cfg.Limit = defConfig.Limit
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defConfig.Window
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = defConfig.KeyFunc
+	}
+	if cfg.Message == "" {
+		cfg.Message = defConfig.Message
+	}
+	return &Limiter{cfg: cfg, windows: make(map[string]*window)}
+}
+
+// Allow counts a request of key. It returns whether the request is
+// allowed, the requests left in the window and the time until it resets.
+func (l *Limiter) Allow(key string) (ok bool, remaining int, reset time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	w := l.windows[key]
+	if w == nil || now.Sub(w.start) >= l.cfg.Window {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+	reset = w.start.Add(l.cfg.Window).Sub(now)
+	if w.count >= l.cfg.Limit {
+		return false, 0, reset
+	}
+	w.count++
+	return true, l.cfg.Limit - w.count, reset
+}
+
+// sweep drops expired windows, at most once per window
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.cfg.Window {
+		return
+	}
+	l.lastSweep = now
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.cfg.Window {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// Handler returns the rate limiting middleware
+func (l *Limiter) Handler() request.HandlerFunc {
+	limit := strconv.Itoa(l.cfg.Limit)
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		ok, remaining, reset := l.Allow(l.cfg.KeyFunc(c))
+		resetSeconds := strconv.Itoa(int((reset + time.Second - 1) / time.Second))
+
+		if l.cfg.Headers {
+			h := c.W.Header()
+			// Nested limiters (app and route): report the tightest one
+			if prev, err := strconv.Atoi(h.Get(HeaderRemaining)); err != nil || remaining <= prev {
+				h.Set(HeaderLimit, limit)
+				h.Set(HeaderRemaining, strconv.Itoa(remaining))
+				h.Set(HeaderReset, resetSeconds)
+			}
+		}
+		if !ok {
+			c.W.Header().Set("Retry-After", resetSeconds)
+			return c.Api.Error(http.StatusTooManyRequests, "RATE_LIMITED", l.cfg.Message)
+		}
+		return c.Next()
+	})
+}
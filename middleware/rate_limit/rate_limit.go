@@ -0,0 +1,71 @@
+package rate_limit
+
+import (
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/middleware/rate_limit/limiter"
+)
+
+const RATE_LIMIT_TYPE = "rate_limit"
+const PARAMS_LIMIT = "limit"
+const PARAMS_WINDOW = "window"
+const PARAMS_HEADERS = "headers"
+const PARAMS_MESSAGE = "message"
+
+const (
+	HeaderLimit     = limiter.HeaderLimit
+	HeaderRemaining = limiter.HeaderRemaining
+	HeaderReset     = limiter.HeaderReset
+)
+
+// Config and Limiter live in the limiter package, shared with
+// route.WithRateLimitOption
+type Config = limiter.Config
+type Limiter = limiter.Limiter
+
+func DefaultConfig() *Config {
+	return limiter.DefaultConfig()
+}
+
+// ClientIP keys requests by the client IP, read from forwarding headers
+// only when sent by a trusted proxy (see request.SetTrustedProxies)
+func ClientIP(c *request.Context) string {
+	return limiter.ClientIP(c)
+}
+
+// RemoteAddr keys requests by the host of the connection's remote address
+func RemoteAddr(c *request.Context) string {
+	return limiter.RemoteAddr(c)
+}
+
+// NewLimiter creates a rate limiter with the given config
+func NewLimiter(cfg *Config) *Limiter {
+	return limiter.New(cfg)
+}
+
+// limits each key (default: client address) to Limit requests per Window,
+// answering 429 + Retry-After above it
+func Middleware(cfg *Config) request.HandlerFunc {
+	return NewLimiter(cfg).Handler()
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Limit:   utils.GetValueFromMap(params, PARAMS_LIMIT, defConfig.Limit),
+		Window:  utils.GetValueFromMap(params, PARAMS_WINDOW, defConfig.Window),
+		Headers: utils.GetValueFromMap(params, PARAMS_HEADERS, defConfig.Headers),
+		Message: utils.GetValueFromMap(params, PARAMS_MESSAGE, defConfig.Message),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(RATE_LIMIT_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
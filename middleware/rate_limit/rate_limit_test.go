@@ -0,0 +1,119 @@
+package rate_limit_test
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/rate_limit"
+)
+
+func newRouter(mw ...any) router.Router {
+	r := router.New("test-router")
+	r.GET("/ping", func(c *request.Context) error { return c.Api.Ok("pong") }, mw...)
+	return r
+}
+
+func get(r router.Router, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_HeadersDecrement(t *testing.T) {
+	r := newRouter(rate_limit.Middleware(&rate_limit.Config{
+		Limit:   3,
+		Window:  time.Minute,
+		Headers: true,
+	}))
+
+	for i, want := range []string{"2", "1", "0"} {
+		w := get(r, "10.0.0.1:1234")
+		if w.Code != 200 {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+		if got := w.Header().Get(rate_limit.HeaderLimit); got != "3" {
+			t.Errorf("request %d: limit = %q", i+1, got)
+		}
+		if got := w.Header().Get(rate_limit.HeaderRemaining); got != want {
+			t.Errorf("request %d: remaining = %q, want %s", i+1, got, want)
+		}
+		reset, _ := strconv.Atoi(w.Header().Get(rate_limit.HeaderReset))
+		if reset < 1 || reset > 60 {
+			t.Errorf("request %d: reset = %d", i+1, reset)
+		}
+	}
+
+	w := get(r, "10.0.0.1:1234")
+	if w.Code != 429 {
+		t.Fatalf("expected 429 over the limit, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get(rate_limit.HeaderRemaining) != "0" {
+		t.Errorf("unexpected headers on 429: %v", w.Header())
+	}
+
+	// Other clients have their own budget
+	if w := get(r, "10.0.0.2:1234"); w.Code != 200 || w.Header().Get(rate_limit.HeaderRemaining) != "2" {
+		t.Errorf("other client: %d, remaining %q", w.Code, w.Header().Get(rate_limit.HeaderRemaining))
+	}
+}
+
+func TestRateLimit_WindowResets(t *testing.T) {
+	r := newRouter(rate_limit.Middleware(&rate_limit.Config{Limit: 1, Window: 50 * time.Millisecond}))
+
+	if w := get(r, "10.0.0.1:1"); w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if w := get(r, "10.0.0.1:1"); w.Code != 429 {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if w := get(r, "10.0.0.1:1"); w.Code != 200 {
+		t.Errorf("expected 200 after the window reset, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_HeadersOffByDefault(t *testing.T) {
+	r := newRouter(rate_limit.Middleware(&rate_limit.Config{Limit: 1}))
+
+	w := get(r, "10.0.0.1:1")
+	for _, h := range []string{rate_limit.HeaderLimit, rate_limit.HeaderRemaining, rate_limit.HeaderReset} {
+		if w.Header().Get(h) != "" {
+			t.Errorf("%s sent with headers disabled", h)
+		}
+	}
+	// Retry-After is still sent on 429
+	if w := get(r, "10.0.0.1:1"); w.Code != 429 || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 429 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestRateLimit_NestedReportsTightest(t *testing.T) {
+	app := rate_limit.Middleware(&rate_limit.Config{Limit: 100, Headers: true})
+	route := rate_limit.Middleware(&rate_limit.Config{Limit: 5, Headers: true})
+	r := newRouter(app, route)
+
+	w := get(r, "10.0.0.1:1")
+	if got := w.Header().Get(rate_limit.HeaderLimit); got != "5" {
+		t.Errorf("limit = %q, want the route limit 5", got)
+	}
+	if got := w.Header().Get(rate_limit.HeaderRemaining); got != "4" {
+		t.Errorf("remaining = %q, want 4", got)
+	}
+}
+
+func TestRateLimit_Factory(t *testing.T) {
+	r := newRouter(rate_limit.MiddlewareFactory(map[string]any{
+		rate_limit.PARAMS_LIMIT:   2,
+		rate_limit.PARAMS_HEADERS: true,
+	}))
+
+	if got := get(r, "10.0.0.1:1").Header().Get(rate_limit.HeaderLimit); got != "2" {
+		t.Errorf("limit = %q", got)
+	}
+}
@@ -8,11 +8,16 @@ import (
 	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/core/request"
 	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
 )
 
 const RECOVERY_TYPE = "recovery"
 const PARAMS_ENABLE_STACK_TRACE = "enable_stack_trace"
 const PARAMS_ENABLE_LOGGING = "enable_logging"
+const PARAMS_METRICS_SERVICE = "metrics_service"
+
+// METRIC_PANICS counts recovered panics (label: route)
+const METRIC_PANICS = "panics_total"
 
 type Config struct {
 	// EnableStackTrace includes stack trace in error response (for debugging)
@@ -25,6 +30,17 @@ type Config struct {
 	// CustomHandler is a custom function to handle recovered panics
 	// If nil, uses default error response
 	CustomHandler func(c *request.Context, recovered any, stack []byte) error
+
+	// Metrics receives a METRIC_PANICS counter per recovered panic (optional)
+	Metrics serviceapi.Metrics
+
+	// OnPanic is called in its own goroutine once the error response is
+	// written, e.g. to report the panic to an alerting service, so a slow
+	// hook never delays the client. The request may be finished when it
+	// runs: read c.R and c.Correlation(), and use context.WithoutCancel(c)
+	// for calls. A panicking OnPanic is logged and does not affect the
+	// request.
+	OnPanic func(c *request.Context, recovered any, stack []byte)
 }
 
 func DefaultConfig() *Config {
@@ -46,7 +62,7 @@ func Middleware(cfg *Config) request.HandlerFunc {
 		}
 	}
 
-	return request.HandlerFunc(func(c *request.Context) error {
+	return request.HandlerFunc(func(c *request.Context) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				// Capture stack trace
@@ -54,21 +70,31 @@ func Middleware(cfg *Config) request.HandlerFunc {
 
 				// Log panic if enabled
 				if cfg.EnableLogging {
-					logger.LogError("[PANIC RECOVERY] %v\n%s", r, stack)
+					logger.LogError("%s\n%s", c.Correlation().AppendTo(fmt.Sprintf("[PANIC RECOVERY] %v", r)), stack)
+				}
+
+				if cfg.Metrics != nil {
+					cfg.Metrics.IncCounter(METRIC_PANICS, c.Correlation().Labels(serviceapi.Labels{"route": c.RouteName()}))
 				}
 
 				// Use custom handler if provided
 				if cfg.CustomHandler != nil {
-					err := cfg.CustomHandler(c, r, stack)
-					if err != nil {
-						logger.LogError("[RECOVERY] Custom handler error: %v", err)
+					if herr := cfg.CustomHandler(c, r, stack); herr != nil {
+						logger.LogError("[RECOVERY] Custom handler error: %v", herr)
 					}
-					return
+				} else {
+					// Default error response: returned as *request.PanicError so an
+					// app-level error handler (app.SetErrorHandler) can format it,
+					// otherwise it is written as an internal error
+					err = &request.PanicError{Value: r, Stack: stack}
 				}
 
-				// Default error response - use InternalError which properly writes response
-				message := fmt.Sprintf("Internal server error: %v", r)
-				c.Api.InternalError(message)
+				if cfg.OnPanic != nil {
+					// net/http only completes the response when the handler
+					// returns, so the hook runs beside it, not before it
+					c.WriteResponse(err)
+					go runOnPanic(cfg.OnPanic, c, r, stack)
+				}
 			}
 		}()
 
@@ -77,6 +103,16 @@ func Middleware(cfg *Config) request.HandlerFunc {
 	})
 }
 
+// runOnPanic calls fn, logging (not propagating) a panic inside it
+func runOnPanic(fn func(*request.Context, any, []byte), c *request.Context, recovered any, stack []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.LogError("[RECOVERY] OnPanic hook panic: %v", r)
+		}
+	}()
+	fn(c, recovered, stack)
+}
+
 func MiddlewareFactory(params map[string]any) request.HandlerFunc {
 	defConfig := DefaultConfig()
 	if params == nil {
@@ -88,6 +124,9 @@ func MiddlewareFactory(params map[string]any) request.HandlerFunc {
 		EnableLogging:    utils.GetValueFromMap(params, PARAMS_ENABLE_LOGGING, defConfig.EnableLogging),
 		CustomHandler:    nil, // Cannot be set via params
 	}
+	if metricsName := utils.GetValueFromMap(params, PARAMS_METRICS_SERVICE, ""); metricsName != "" {
+		cfg.Metrics = lokstra_registry.GetService[serviceapi.Metrics](metricsName)
+	}
 	return Middleware(cfg)
 }
 
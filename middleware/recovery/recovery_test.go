@@ -7,8 +7,10 @@ import (
 
 	"github.com/primadi/lokstra/core/request"
 	"github.com/primadi/lokstra/core/response/api_formatter"
+	"github.com/primadi/lokstra/core/route"
 	"github.com/primadi/lokstra/core/router"
 	"github.com/primadi/lokstra/middleware/recovery"
+	"github.com/primadi/lokstra/serviceapi"
 )
 
 func TestRecovery(t *testing.T) {
@@ -180,3 +182,79 @@ func TestRecoveryFactory(t *testing.T) {
 		t.Error("Expected middleware with custom params")
 	}
 }
+
+type panicMetrics struct {
+	serviceapi.Metrics
+	counts map[string]int
+}
+
+func (m *panicMetrics) IncCounter(name string, labels serviceapi.Labels) {
+	m.counts[name+"/"+labels["route"]]++
+}
+
+func TestRecoveryMetricAndOnPanic(t *testing.T) {
+	metrics := &panicMetrics{counts: map[string]int{}}
+	w := httptest.NewRecorder()
+
+	var hookCalls int
+	var hookRecovered any
+	var statusAtHook, bodyAtHook int
+	var stackAtHook []byte
+	hookDone := make(chan struct{})
+	hookRelease := make(chan struct{})
+
+	r := router.New("api")
+	r.Use(recovery.Middleware(&recovery.Config{
+		Metrics: metrics,
+		OnPanic: func(c *request.Context, recovered any, stack []byte) {
+			hookCalls++
+			hookRecovered = recovered
+			stackAtHook = stack
+			// Response already written
+			statusAtHook, bodyAtHook = w.Code, w.Body.Len()
+			close(hookDone)
+			<-hookRelease // a slow alerting call
+		},
+	}))
+	r.GET("/boom", func(c *request.Context) error {
+		panic("boom")
+	}, route.WithNameOption("boom"))
+
+	// The request completes while the hook is still running
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+	close(hookRelease)
+	<-hookDone
+
+	if w.Code != 500 {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if n := metrics.counts[recovery.METRIC_PANICS+"/api.boom"]; n != 1 {
+		t.Errorf("Expected %s{route=api.boom} = 1, got %v", recovery.METRIC_PANICS, metrics.counts)
+	}
+	if hookCalls != 1 || hookRecovered != "boom" || len(stackAtHook) == 0 {
+		t.Errorf("Expected hook called once with panic value and stack, got %d calls, %v", hookCalls, hookRecovered)
+	}
+	if statusAtHook != 500 || bodyAtHook == 0 {
+		t.Errorf("Expected hook to run after the 500 was sent, saw status %d and %d body bytes", statusAtHook, bodyAtHook)
+	}
+}
+
+func TestRecoveryOnPanicHookPanicIsContained(t *testing.T) {
+	r := router.New("api")
+	r.Use(recovery.Middleware(&recovery.Config{
+		EnableLogging: false,
+		OnPanic: func(c *request.Context, recovered any, stack []byte) {
+			panic("alerting is down")
+		},
+	}))
+	r.GET("/boom", func(c *request.Context) error {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+
+	if w.Code != 500 || !strings.Contains(w.Body.String(), "boom") {
+		t.Errorf("Expected 500 for the original panic, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,158 @@
+package request_decompression
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const REQUEST_DECOMPRESSION_TYPE = "request_decompression"
+const PARAMS_MAX_DECOMPRESSED_SIZE = "max_decompressed_size"
+
+type Config struct {
+	// MaxDecompressedSize is the maximum size of the decompressed body (in bytes),
+	// and of the compressed body as read from the client. Larger bodies are
+	// rejected with 413 (protects against decompression bombs)
+	MaxDecompressedSize int64
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		MaxDecompressedSize: 10 * 1024 * 1024, // 10MB
+	}
+}
+
+var errTooLarge = errors.New("request body too large")
+
+// middleware to transparently decompress gzip/deflate request bodies,
+// so BindBody and friends always see the plain payload
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.MaxDecompressedSize <= 0 {
+		cfg.MaxDecompressedSize = defConfig.MaxDecompressedSize
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		encoding := strings.ToLower(strings.TrimSpace(c.R.Header.Get("Content-Encoding")))
+		if encoding == "" || encoding == "identity" || c.R.Body == nil || c.R.Body == http.NoBody {
+			return c.Next()
+		}
+
+		var newReader func(io.Reader) (io.ReadCloser, error)
+		switch encoding {
+		case "gzip", "x-gzip":
+			newReader = func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+		case "deflate":
+			newReader = newDeflateReader
+		default:
+			return c.Api.Error(http.StatusUnsupportedMediaType, "UNSUPPORTED_ENCODING",
+				"Unsupported Content-Encoding: "+encoding)
+		}
+
+		body, err := decompress(c.R.Body, newReader, cfg.MaxDecompressedSize)
+		c.R.Body.Close()
+		if err != nil {
+			if errors.Is(err, errTooLarge) {
+				return c.Api.Error(http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE",
+					"Decompressed request body exceeds "+strconv.FormatInt(cfg.MaxDecompressedSize, 10)+" bytes")
+			}
+			return c.Api.BadRequest("INVALID_ENCODING", "Malformed "+encoding+" request body")
+		}
+
+		// Replace body with the decompressed payload
+		c.R.Body = io.NopCloser(bytes.NewReader(body))
+		c.R.ContentLength = int64(len(body))
+		c.R.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		c.R.Header.Del("Content-Encoding")
+
+		return c.Next()
+	})
+}
+
+// decompress reads at most limit bytes of compressed and of decompressed
+// data, so neither a huge upload nor a decompression bomb is buffered
+func decompress(src io.Reader, newReader func(io.Reader) (io.ReadCloser, error), limit int64) ([]byte, error) {
+	zr, err := newReader(&limitedReader{r: src, n: limit + 1})
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	body, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, errTooLarge
+	}
+	return body, nil
+}
+
+// limitedReader fails with errTooLarge once more than n bytes are read
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, errTooLarge
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n <= 0 && err == nil {
+		err = errTooLarge
+	}
+	return n, err
+}
+
+// newDeflateReader accepts zlib-wrapped deflate (RFC 1950, the HTTP standard)
+// and falls back to raw deflate streams sent by some clients. Only the
+// 2-byte zlib header is peeked, the stream itself is never buffered.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil && len(header) < 2 {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	// CM = 8 (deflate) and the header checksum of RFC 1950
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		MaxDecompressedSize: int64(utils.GetValueFromMap(params, PARAMS_MAX_DECOMPRESSED_SIZE,
+			int(defConfig.MaxDecompressedSize))),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(REQUEST_DECOMPRESSION_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,152 @@
+package request_decompression_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/request_decompression"
+)
+
+type createOrder struct {
+	Item string `json:"item"`
+	Qty  int    `json:"qty"`
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func serve(t *testing.T, cfg *request_decompression.Config, encoding string, body []byte) (*httptest.ResponseRecorder, *createOrder) {
+	t.Helper()
+	var got createOrder
+
+	r := router.New("test-router")
+	r.Use(request_decompression.Middleware(cfg))
+	r.POST("/orders", func(c *request.Context) error {
+		if err := c.Req.BindBody(&got); err != nil {
+			return err
+		}
+		return c.Api.Ok(got)
+	})
+
+	req := httptest.NewRequest("POST", "/orders", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, &got
+}
+
+func TestRequestDecompression_GzipJSON(t *testing.T) {
+	body := gzipBytes(t, []byte(`{"item":"book","qty":3}`))
+
+	w, got := serve(t, &request_decompression.Config{}, "gzip", body)
+	if w.Code != 200 {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.Item != "book" || got.Qty != 3 {
+		t.Errorf("Expected bound {book 3}, got %+v", got)
+	}
+}
+
+func TestRequestDecompression_Deflate(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte(`{"item":"pen","qty":1}`))
+	zw.Close()
+
+	w, got := serve(t, &request_decompression.Config{}, "deflate", buf.Bytes())
+	if w.Code != 200 {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.Item != "pen" {
+		t.Errorf("Expected bound item pen, got %+v", got)
+	}
+}
+
+func TestRequestDecompression_BombGuard(t *testing.T) {
+	// 1MB of zeros compresses to ~1KB
+	payload := `{"item":"` + strings.Repeat("0", 1024*1024) + `"}`
+	body := gzipBytes(t, []byte(payload))
+	if len(body) > 16*1024 {
+		t.Fatalf("Expected highly compressible payload, got %d bytes", len(body))
+	}
+
+	w, _ := serve(t, &request_decompression.Config{MaxDecompressedSize: 64 * 1024}, "gzip", body)
+	if w.Code != 413 {
+		t.Errorf("Expected 413 for decompression bomb, got %d", w.Code)
+	}
+}
+
+func TestRequestDecompression_Malformed(t *testing.T) {
+	w, _ := serve(t, &request_decompression.Config{}, "gzip", []byte("definitely not gzip"))
+	if w.Code != 400 {
+		t.Errorf("Expected 400 for malformed gzip, got %d", w.Code)
+	}
+}
+
+func TestRequestDecompression_PlainBodyUntouched(t *testing.T) {
+	w, got := serve(t, &request_decompression.Config{}, "", []byte(`{"item":"cup","qty":2}`))
+	if w.Code != 200 || got.Item != "cup" {
+		t.Errorf("Expected plain body to pass through, got %d %+v", w.Code, got)
+	}
+}
+
+func TestRequestDecompression_RawDeflate(t *testing.T) {
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	fw.Write([]byte(`{"item":"ink","qty":4}`))
+	fw.Close()
+
+	w, got := serve(t, &request_decompression.Config{}, "deflate", buf.Bytes())
+	if w.Code != 200 || got.Item != "ink" {
+		t.Errorf("Expected raw deflate to be accepted, got %d %+v", w.Code, got)
+	}
+}
+
+func TestRequestDecompression_DeflateBombGuard(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte(`{"item":"` + strings.Repeat("0", 1024*1024) + `"}`))
+	zw.Close()
+
+	w, _ := serve(t, &request_decompression.Config{MaxDecompressedSize: 64 * 1024}, "deflate", buf.Bytes())
+	if w.Code != 413 {
+		t.Errorf("Expected 413 for deflate bomb, got %d", w.Code)
+	}
+}
+
+func TestRequestDecompression_CompressedBodyTooLarge(t *testing.T) {
+	// Random data doesn't compress: the compressed body itself is over the limit
+	data := make([]byte, 256*1024)
+	rand.Read(data)
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	zw.Write(data)
+	zw.Close()
+
+	bodies := map[string][]byte{"gzip": gzipBytes(t, data), "deflate": deflated.Bytes()}
+	for encoding, body := range bodies {
+		w, _ := serve(t, &request_decompression.Config{MaxDecompressedSize: 64 * 1024}, encoding, body)
+		if w.Code != 413 {
+			t.Errorf("%s: expected 413 for oversized compressed body, got %d", encoding, w.Code)
+		}
+	}
+}
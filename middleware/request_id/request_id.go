@@ -0,0 +1,153 @@
+package request_id
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/primadi/lokstra/common/id"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const REQUEST_ID_TYPE = "request_id"
+const PARAMS_HEADER = "header"
+const PARAMS_GENERATOR = "generator"
+const PARAMS_TRUST_INCOMING = "trust_incoming"
+
+// Built-in generator names for MiddlewareFactory
+const (
+	GENERATOR_UUIDV4 = "uuidv4"
+	GENERATOR_UUIDV7 = "uuidv7"
+	GENERATOR_ULID   = "ulid"
+)
+
+// HeaderRequestID is the default request id header, see request.HeaderRequestID
+const HeaderRequestID = request.HeaderRequestID
+
+// maxIncomingLength bounds incoming ids, longer ones are replaced
+const maxIncomingLength = 128
+
+// Generator returns a new request id
+type Generator func() string
+
+// UUIDv4 generates random UUIDs, e.g. "0b5c8a9e-3f3d-4b8e-9a1c-6f2e1d7c4a90"
+func UUIDv4() string {
+	return uuid.New().String()
+}
+
+// UUIDv7 generates time-ordered UUIDs (RFC 9562): ids created later sort
+// later, which keeps B-tree indexes compact when request ids are stored
+func UUIDv7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// ULID generates time-ordered ULIDs, e.g. "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+func ULID() string {
+	return id.NewULID().String()
+}
+
+// Generators maps the built-in generator names to their functions
+var Generators = map[string]Generator{
+	GENERATOR_UUIDV4: UUIDv4,
+	GENERATOR_UUIDV7: UUIDv7,
+	GENERATOR_ULID:   ULID,
+}
+
+type Config struct {
+	// Header carries the request id on the request and the response
+	// (default X-Request-ID)
+	Header string
+
+	// Generator creates ids for requests without one (default UUIDv4)
+	Generator Generator
+
+	// TrustIncoming keeps the id sent by the client or an upstream proxy,
+	// so one id follows the request across services. Ids longer than 128
+	// characters or with non-printable characters are replaced.
+	TrustIncoming bool
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Header:        HeaderRequestID,
+		Generator:     UUIDv4,
+		TrustIncoming: true,
+	}
+}
+
+// middleware that assigns each request an id, read with ctx.RequestID()
+// or request.RequestIDFromContext. The id is echoed in the response
+// header and set on the request header, so proxied calls forward it:
+//
+//	r.Use(request_id.Middleware(&request_id.Config{Generator: request_id.UUIDv7}))
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.Header == "" {
+		cfg.Header = defConfig.Header
+	}
+	if cfg.Generator == nil {
+		cfg.Generator = defConfig.Generator
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		id := ""
+		if cfg.TrustIncoming {
+			id = c.R.Header.Get(cfg.Header)
+			if !validIncoming(id) {
+				id = ""
+			}
+		}
+		if id == "" {
+			id = cfg.Generator()
+			c.R.Header.Set(cfg.Header, id)
+		}
+
+		c.SetRequestID(id)
+		c.W.Header().Set(cfg.Header, id)
+		return c.Next()
+	})
+}
+
+// validIncoming rejects empty, oversized and non-printable ids, which
+// would otherwise end up in logs and response headers
+func validIncoming(id string) bool {
+	if id == "" || len(id) > maxIncomingLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] < 0x21 || id[i] > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	name := utils.GetValueFromMap(params, PARAMS_GENERATOR, GENERATOR_UUIDV4)
+	generator, ok := Generators[name]
+	if !ok {
+		// A typo must not silently fall back to another id format
+		panic(fmt.Sprintf("%s: unknown generator %q (want one of %v)",
+			REQUEST_ID_TYPE, name, slices.Sorted(maps.Keys(Generators))))
+	}
+
+	cfg := &Config{
+		Header:        utils.GetValueFromMap(params, PARAMS_HEADER, defConfig.Header),
+		Generator:     generator,
+		TrustIncoming: utils.GetValueFromMap(params, PARAMS_TRUST_INCOMING, defConfig.TrustIncoming),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(REQUEST_ID_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,187 @@
+package request_id_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/request_id"
+)
+
+var (
+	uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidPattern   = regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+)
+
+func newRouter(cfg *request_id.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(request_id.Middleware(cfg))
+	r.GET("/ping", func(c *request.Context) error {
+		return c.Resp.Text(c.RequestID())
+	})
+	return r
+}
+
+func serve(r router.Router, incoming string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/ping", nil)
+	if incoming != "" {
+		req.Header.Set(request_id.HeaderRequestID, incoming)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestID_Formats(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator request_id.Generator
+		pattern   *regexp.Regexp
+	}{
+		{name: "default", generator: nil, pattern: uuidV4Pattern},
+		{name: "uuidv4", generator: request_id.UUIDv4, pattern: uuidV4Pattern},
+		{name: "uuidv7", generator: request_id.UUIDv7, pattern: uuidV7Pattern},
+		{name: "ulid", generator: request_id.ULID, pattern: ulidPattern},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newRouter(&request_id.Config{Generator: tt.generator}), "")
+
+			id := w.Header().Get(request_id.HeaderRequestID)
+			if !tt.pattern.MatchString(id) {
+				t.Errorf("unexpected id format %q", id)
+			}
+			if w.Body.String() != id {
+				t.Errorf("ctx.RequestID() = %q, header = %q", w.Body.String(), id)
+			}
+		})
+	}
+}
+
+func TestRequestID_CustomGenerator(t *testing.T) {
+	w := serve(newRouter(&request_id.Config{
+		Generator: func() string { return "req-42" },
+	}), "")
+
+	if got := w.Header().Get(request_id.HeaderRequestID); got != "req-42" {
+		t.Errorf("expected custom id, got %q", got)
+	}
+}
+
+func TestRequestID_PreservesIncoming(t *testing.T) {
+	calls := 0
+	r := newRouter(&request_id.Config{
+		Generator:     func() string { calls++; return "generated" },
+		TrustIncoming: true,
+	})
+
+	w := serve(r, "upstream-123")
+
+	if got := w.Header().Get(request_id.HeaderRequestID); got != "upstream-123" {
+		t.Errorf("incoming id not preserved, got %q", got)
+	}
+	if w.Body.String() != "upstream-123" {
+		t.Errorf("ctx.RequestID() = %q", w.Body.String())
+	}
+	if calls != 0 {
+		t.Errorf("generator called %d times for a request with an id", calls)
+	}
+}
+
+func TestRequestID_RejectsInvalidIncoming(t *testing.T) {
+	r := newRouter(&request_id.Config{
+		Generator:     func() string { return "generated" },
+		TrustIncoming: true,
+	})
+
+	for _, incoming := range []string{
+		"has space",
+		"tab\tinside",
+		string(make([]byte, 129)),
+	} {
+		if got := serve(r, incoming).Header().Get(request_id.HeaderRequestID); got != "generated" {
+			t.Errorf("incoming %q: expected a generated id, got %q", incoming, got)
+		}
+	}
+}
+
+func TestRequestID_UntrustedIncoming(t *testing.T) {
+	r := newRouter(&request_id.Config{Generator: func() string { return "generated" }})
+
+	if got := serve(r, "upstream-123").Header().Get(request_id.HeaderRequestID); got != "generated" {
+		t.Errorf("expected incoming id to be replaced, got %q", got)
+	}
+}
+
+func TestRequestID_Unique(t *testing.T) {
+	for name, gen := range request_id.Generators {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[string]struct{}, 10000)
+			for range 10000 {
+				id := gen()
+				if _, dup := seen[id]; dup {
+					t.Fatalf("duplicate id %q", id)
+				}
+				seen[id] = struct{}{}
+			}
+		})
+	}
+}
+
+func TestRequestID_TimeOrdered(t *testing.T) {
+	for _, gen := range []request_id.Generator{request_id.UUIDv7, request_id.ULID} {
+		first := gen()
+		// ids are ordered by their millisecond timestamp
+		time.Sleep(2 * time.Millisecond)
+		later := gen()
+		ids := []string{later, first}
+		sort.Strings(ids)
+		if ids[0] != first {
+			t.Errorf("expected %q to sort before %q", first, later)
+		}
+	}
+}
+
+func TestRequestID_Factory(t *testing.T) {
+	r := router.New("test-router")
+	r.Use(request_id.MiddlewareFactory(map[string]any{
+		request_id.PARAMS_HEADER:    "X-Correlation-ID",
+		request_id.PARAMS_GENERATOR: request_id.GENERATOR_ULID,
+	}))
+	r.GET("/ping", func(c *request.Context) error { return nil })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+
+	if id := w.Header().Get("X-Correlation-ID"); !ulidPattern.MatchString(id) {
+		t.Errorf("expected ULID in X-Correlation-ID, got %q", id)
+	}
+}
+
+func TestRequestID_FactoryUnknownGenerator(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), `"uuid7"`) {
+			t.Errorf("Expected a panic naming the unknown generator, got %v", r)
+		}
+	}()
+	request_id.MiddlewareFactory(map[string]any{request_id.PARAMS_GENERATOR: "uuid7"})
+}
+
+func BenchmarkGenerators(b *testing.B) {
+	for name, gen := range request_id.Generators {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = gen()
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package request_logger_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/request_id"
+	"github.com/primadi/lokstra/middleware/request_logger"
+	"github.com/primadi/lokstra/middleware/tenant"
+)
+
+func TestRequestLogger_Correlation(t *testing.T) {
+	var logged string
+	var got request.Correlation
+
+	r := router.New("test-router")
+	r.Use(request_logger.Middleware(&request_logger.Config{
+		CustomLogger: func(format string, args ...any) {
+			logged = fmt.Sprintf(format, args...)
+		},
+	}))
+	r.Use(request_id.Middleware(&request_id.Config{
+		Generator: func() string { return "req-1" },
+	}))
+	r.Use(tenant.Middleware(&tenant.Config{}))
+	r.Use(func(c *request.Context) error {
+		c.SetPrincipal(&request.Principal{ID: "user-7"})
+		c.SetTrace("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+		return c.Next()
+	})
+	r.GET("/orders", func(c *request.Context) error {
+		got = c.Correlation()
+		return c.Api.Ok(nil)
+	})
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := request.Correlation{
+		RequestID: "req-1",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:    "00f067aa0ba902b7",
+		TenantID:  "acme",
+		UserID:    "user-7",
+	}
+	if got != want {
+		t.Errorf("Correlation() = %+v, want %+v", got, want)
+	}
+
+	// The logger runs first but logs after the chain, with every id
+	wantLog := "[GET] /orders - Status: 200"
+	if !strings.HasPrefix(logged, wantLog) {
+		t.Errorf("Unexpected log line %q", logged)
+	}
+	if suffix := " " + want.String(); !strings.HasSuffix(logged, suffix) {
+		t.Errorf("Expected log line to end with %q, got %q", suffix, logged)
+	}
+}
@@ -14,6 +14,7 @@ import (
 const REQUEST_LOGGER_TYPE = "request_logger"
 const PARAMS_ENABLE_COLORS = "enable_colors"
 const PARAMS_SKIP_PATHS = "skip_paths"
+const PARAMS_SLOW_THRESHOLD = "slow_threshold"
 
 type Config struct {
 	// EnableColors enables colored output for terminal
@@ -26,6 +27,15 @@ type Config struct {
 	// CustomLogger is a custom logging function
 	// If nil, uses default logger.LogInfo
 	CustomLogger func(format string, args ...any)
+
+	// SlowThreshold is a soft limit: requests taking at least this long are
+	// additionally logged as a warning with route and elapsed time.
+	// The request itself is not affected. 0 disables the warning.
+	SlowThreshold time.Duration
+
+	// WarnLogger logs slow request warnings
+	// If nil, uses default logger.LogWarn
+	WarnLogger func(format string, args ...any)
 }
 
 func DefaultConfig() *Config {
@@ -56,6 +66,9 @@ func Middleware(cfg *Config) request.HandlerFunc {
 	if cfg.CustomLogger == nil {
 		cfg.CustomLogger = logger.LogInfo
 	}
+	if cfg.WarnLogger == nil {
+		cfg.WarnLogger = logger.LogWarn
+	}
 
 	return request.HandlerFunc(func(c *request.Context) error {
 		// Check if path should be skipped
@@ -90,7 +103,7 @@ func Middleware(cfg *Config) request.HandlerFunc {
 				internal.FormatDuration(duration),
 				colorReset,
 			)
-			cfg.CustomLogger("%s", msg)
+			cfg.CustomLogger("%s", c.Correlation().AppendTo(msg))
 		} else {
 			msg := fmt.Sprintf("[%s] %s - Status: %d - Duration: %s",
 				c.R.Method,
@@ -98,23 +111,65 @@ func Middleware(cfg *Config) request.HandlerFunc {
 				statusCode,
 				internal.FormatDuration(duration),
 			)
-			cfg.CustomLogger("%s", msg)
+			cfg.CustomLogger("%s", c.Correlation().AppendTo(msg))
+		}
+
+		if cfg.SlowThreshold > 0 && duration >= cfg.SlowThreshold {
+			logSlowRequest(cfg, c, statusCode, duration)
 		}
 
 		return err
 	})
 }
 
+// logSlowRequest warns about a request that exceeded the soft threshold.
+// The route pattern is used when available so slow endpoints group together.
+func logSlowRequest(cfg *Config, c *request.Context, statusCode int, duration time.Duration) {
+	route := c.R.URL.Path
+	if ri := c.Route(); ri != nil {
+		route = ri.Path
+		if ri.Name != "" {
+			route += " (" + ri.Name + ")"
+		}
+	}
+	cfg.WarnLogger("%s", c.Correlation().AppendTo(fmt.Sprintf(
+		"[SLOW REQUEST] [%s] %s - Status: %d - Duration: %s (threshold: %s)",
+		c.R.Method,
+		route,
+		statusCode,
+		internal.FormatDuration(duration),
+		internal.FormatDuration(cfg.SlowThreshold),
+	)))
+}
+
 func MiddlewareFactory(params map[string]any) request.HandlerFunc {
 	defConfig := DefaultConfig()
 	if params == nil {
 		return Middleware(defConfig)
 	}
 
+	// Handle slow threshold - could be int (milliseconds) or duration string
+	var slowThreshold time.Duration
+	if thresholdVal, ok := params[PARAMS_SLOW_THRESHOLD]; ok {
+		switch v := thresholdVal.(type) {
+		case int:
+			slowThreshold = time.Duration(v) * time.Millisecond
+		case int64:
+			slowThreshold = time.Duration(v) * time.Millisecond
+		case time.Duration:
+			slowThreshold = v
+		case string:
+			if d, err := time.ParseDuration(v); err == nil {
+				slowThreshold = d
+			}
+		}
+	}
+
 	cfg := &Config{
-		EnableColors: utils.GetValueFromMap(params, PARAMS_ENABLE_COLORS, defConfig.EnableColors),
-		SkipPaths:    utils.GetValueFromMap(params, PARAMS_SKIP_PATHS, defConfig.SkipPaths),
-		CustomLogger: nil, // Cannot be set via params
+		EnableColors:  utils.GetValueFromMap(params, PARAMS_ENABLE_COLORS, defConfig.EnableColors),
+		SkipPaths:     utils.GetValueFromMap(params, PARAMS_SKIP_PATHS, defConfig.SkipPaths),
+		CustomLogger:  nil, // Cannot be set via params
+		SlowThreshold: slowThreshold,
 	}
 	return Middleware(cfg)
 }
@@ -192,6 +192,61 @@ func TestRequestLoggerDuration(t *testing.T) {
 	t.Logf("Log with duration: %s", logLine)
 }
 
+func TestRequestLoggerSlowThreshold(t *testing.T) {
+	api_formatter.SetGlobalFormatter(api_formatter.NewApiResponseFormatter())
+
+	var logOutput, warnOutput []string
+	cfg := &request_logger.Config{
+		EnableColors:  false,
+		SlowThreshold: 20 * time.Millisecond,
+		CustomLogger: func(format string, args ...any) {
+			logOutput = append(logOutput, fmt.Sprintf(format, args...))
+		},
+		WarnLogger: func(format string, args ...any) {
+			warnOutput = append(warnOutput, fmt.Sprintf(format, args...))
+		},
+	}
+
+	r := router.New("test-router")
+	r.Use(request_logger.Middleware(cfg))
+
+	r.GET("/reports/{id}", func(c *request.Context) error {
+		time.Sleep(30 * time.Millisecond) // Slower than the soft threshold
+		return c.Api.Ok("success")
+	})
+	r.GET("/fast", func(c *request.Context) error {
+		return c.Api.Ok("success")
+	})
+
+	req := httptest.NewRequest("GET", "/reports/42", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Errorf("Expected slow request to succeed, got status %d", w.Code)
+	}
+	if len(logOutput) != 1 {
+		t.Errorf("Expected access log line, got %v", logOutput)
+	}
+	if len(warnOutput) != 1 {
+		t.Fatalf("Expected one slow request warning, got %v", warnOutput)
+	}
+	warn := warnOutput[0]
+	if !strings.Contains(warn, "/reports/{id}") {
+		t.Errorf("Warning should contain route pattern, got %s", warn)
+	}
+	if !strings.Contains(warn, "threshold: 20") {
+		t.Errorf("Warning should contain threshold, got %s", warn)
+	}
+
+	warnOutput = nil
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	if len(warnOutput) != 0 {
+		t.Errorf("Expected no warning for fast request, got %v", warnOutput)
+	}
+}
+
 func TestRequestLoggerFactory(t *testing.T) {
 	// Test with nil params
 	middleware1 := request_logger.MiddlewareFactory(nil)
@@ -201,8 +256,9 @@ func TestRequestLoggerFactory(t *testing.T) {
 
 	// Test with custom params
 	params := map[string]any{
-		request_logger.PARAMS_ENABLE_COLORS: false,
-		request_logger.PARAMS_SKIP_PATHS:    []string{"/health"},
+		request_logger.PARAMS_ENABLE_COLORS:  false,
+		request_logger.PARAMS_SKIP_PATHS:     []string{"/health"},
+		request_logger.PARAMS_SLOW_THRESHOLD: "2s",
 	}
 	middleware2 := request_logger.MiddlewareFactory(params)
 	if middleware2 == nil {
@@ -0,0 +1,308 @@
+package request_recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const REQUEST_RECORDER_TYPE = "request_recorder"
+const PARAMS_FILE = "file"
+const PARAMS_PATHS = "paths"
+const PARAMS_MAX_BODY_SIZE = "max_body_size"
+const PARAMS_REDACT_HEADERS = "redact_headers"
+const PARAMS_REDACT_FIELDS = "redact_fields"
+
+// Store persists recordings
+type Store interface {
+	Save(rec *router.Recording) error
+}
+
+type Config struct {
+	// Store receives the recordings (default: FileStore on File)
+	Store Store
+
+	// File is the record file (JSON lines) used when Store is nil
+	File string
+
+	// Filter selects which requests are recorded (nil records all matching Paths)
+	Filter func(c *request.Context) bool
+
+	// Paths limits recording to these paths (supports "/prefix/**"), empty means all
+	Paths []string
+
+	// MaxBodySize is the max recorded size of each body (larger bodies are truncated)
+	MaxBodySize int
+
+	// RedactHeaders are replaced by router.REDACTED (case-insensitive)
+	RedactHeaders []string
+
+	// RedactFields are JSON body fields and query params replaced by router.REDACTED
+	RedactFields []string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		File:        "requests.rec",
+		MaxBodySize: 64 * 1024, // 64KB
+		RedactHeaders: []string{
+			"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key",
+		},
+		RedactFields: []string{
+			"password", "token", "access_token", "refresh_token", "secret", "api_key",
+		},
+	}
+}
+
+// FileStore appends recordings as JSON lines to a file
+type FileStore struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileStore opens (or creates) the record file for appending
+func NewFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{file: f}, nil
+}
+
+// Save implements Store.
+func (s *FileStore) Save(rec *router.Recording) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the record file
+func (s *FileStore) Close() error {
+	return s.file.Close()
+}
+
+// middleware that records selected requests and their responses for later replay
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defConfig.MaxBodySize
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = defConfig.RedactHeaders
+	}
+	if cfg.RedactFields == nil {
+		cfg.RedactFields = defConfig.RedactFields
+	}
+	if cfg.Store == nil {
+		if cfg.File == "" {
+			cfg.File = defConfig.File
+		}
+		store, err := NewFileStore(cfg.File)
+		if err != nil {
+			logger.LogPanic("request_recorder: cannot open record file %s: %v", cfg.File, err)
+		}
+		cfg.Store = store
+	}
+
+	red := newRedactor(cfg.RedactHeaders, cfg.RedactFields)
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		if !matchPath(c.R.URL.Path, cfg.Paths) || (cfg.Filter != nil && !cfg.Filter(c)) {
+			return c.Next()
+		}
+
+		rec := &router.Recording{Time: time.Now()}
+		rec.Request.Method = c.R.Method
+		rec.Request.Header = red.header(c.R.Header)
+		rec.Request.URL = red.url(c.R.URL)
+
+		if c.R.Body != nil && c.R.Body != http.NoBody {
+			// Buffered and re-exposed as c.R.Body for the handler
+			body, err := c.Req.RawRequestBody()
+			if err != nil {
+				return err
+			}
+			setBody(&rec.Request.RecordedBody, red.body(body), cfg.MaxBodySize)
+		}
+
+		orig := c.W.ResponseWriter
+		tee := &teeWriter{ResponseWriter: orig}
+		c.W.ResponseWriter = response.WrapWriter(orig, tee)
+		err := c.Next()
+		// Write the final response now so it can be captured
+		c.WriteResponse(err)
+		c.W.ResponseWriter = orig
+
+		rec.Response.Status = tee.status
+		if rec.Response.Status == 0 {
+			rec.Response.Status = http.StatusOK
+		}
+		rec.Response.Header = red.header(orig.Header())
+		setBody(&rec.Response.RecordedBody, red.body(tee.body.Bytes()), cfg.MaxBodySize)
+
+		if saveErr := cfg.Store.Save(rec); saveErr != nil {
+			logger.LogWarning("request_recorder: failed to save recording: %v", saveErr)
+		}
+		return err
+	})
+}
+
+func setBody(b *router.RecordedBody, body []byte, maxSize int) {
+	if len(body) > maxSize {
+		body = body[:maxSize]
+		b.Truncated = true
+	}
+	b.SetBody(body)
+}
+
+func matchPath(requestPath string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if requestPath == pattern {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "**"); ok && strings.HasPrefix(requestPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// teeWriter passes writes through while keeping a copy of the body
+type teeWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *teeWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+type redactor struct {
+	headers map[string]bool
+	fields  map[string]bool
+}
+
+func newRedactor(headers, fields []string) *redactor {
+	r := &redactor{headers: map[string]bool{}, fields: map[string]bool{}}
+	for _, h := range headers {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, f := range fields {
+		r.fields[strings.ToLower(f)] = true
+	}
+	return r
+}
+
+func (r *redactor) header(h http.Header) http.Header {
+	out := h.Clone()
+	for k := range out {
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			out[k] = []string{router.REDACTED}
+		}
+	}
+	return out
+}
+
+func (r *redactor) url(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+	q := u.Query()
+	for k := range q {
+		if r.fields[strings.ToLower(k)] {
+			q[k] = []string{router.REDACTED}
+		}
+	}
+	return u.EscapedPath() + "?" + q.Encode()
+}
+
+// body redacts sensitive fields of JSON bodies; other bodies are returned as-is
+func (r *redactor) body(b []byte) []byte {
+	var v any
+	if len(b) == 0 || json.Unmarshal(b, &v) != nil {
+		return b
+	}
+	if !r.redactValue(v) {
+		return b
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return out
+}
+
+func (r *redactor) redactValue(v any) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]any:
+		for k, inner := range val {
+			if r.fields[strings.ToLower(k)] {
+				val[k] = router.REDACTED
+				changed = true
+			} else if r.redactValue(inner) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, inner := range val {
+			if r.redactValue(inner) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		File:          utils.GetValueFromMap(params, PARAMS_FILE, defConfig.File),
+		Paths:         utils.GetValueFromMap(params, PARAMS_PATHS, []string{}),
+		MaxBodySize:   utils.GetValueFromMap(params, PARAMS_MAX_BODY_SIZE, defConfig.MaxBodySize),
+		RedactHeaders: utils.GetValueFromMap(params, PARAMS_REDACT_HEADERS, defConfig.RedactHeaders),
+		RedactFields:  utils.GetValueFromMap(params, PARAMS_REDACT_FIELDS, defConfig.RedactFields),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(REQUEST_RECORDER_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
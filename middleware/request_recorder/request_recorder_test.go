@@ -0,0 +1,113 @@
+package request_recorder_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/request_recorder"
+)
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func newAppRouter(greeting string, mw ...any) router.Router {
+	r := router.New("test-router")
+	r.Use(mw...)
+	r.POST("/login", func(c *request.Context, req *loginRequest) error {
+		return c.Api.Ok(map[string]any{
+			"greeting": greeting + " " + req.Username,
+			"token":    "tok-" + req.Password,
+		})
+	})
+	r.GET("/users/{id}", func(c *request.Context) error {
+		return c.Api.Ok(map[string]string{"id": c.Req.PathParam("id", "")})
+	})
+	return r
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "requests.rec")
+	store, err := request_recorder.NewFileStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newAppRouter("hello", request_recorder.Middleware(&request_recorder.Config{Store: store}))
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username":"alice","password":"s3cret"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "tok-s3cret") {
+		t.Fatalf("Handler response altered by recorder: %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/42?api_key=xyz", nil))
+	store.Close()
+
+	data, _ := os.ReadFile(file)
+	for _, secret := range []string{"s3cret", "Bearer abc", "xyz"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("Record file contains unredacted %q:\n%s", secret, data)
+		}
+	}
+
+	recs, err := router.LoadRecordings(file)
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("Expected 2 recordings, got %d (%v)", len(recs), err)
+	}
+	if recs[0].Request.Method != "POST" || recs[0].Response.Status != 200 {
+		t.Errorf("Unexpected recording: %+v", recs[0])
+	}
+
+	// Replay against a router without the recorder
+	results, err := router.Replay(newAppRouter("hello"), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range results {
+		if !res.Match {
+			t.Errorf("%s %s: %s", res.Recording.Request.Method, res.Recording.Request.URL, res.Mismatch)
+		}
+	}
+
+	// A behavior change is detected
+	results, _ = router.Replay(newAppRouter("hi"), file)
+	if results[0].Match {
+		t.Error("Expected mismatch after changing the response")
+	}
+	if !results[1].Match {
+		t.Errorf("Unchanged route should still match: %s", results[1].Mismatch)
+	}
+}
+
+func TestRecorder_PathFilter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "requests.rec")
+	r := newAppRouter("hello", request_recorder.Middleware(&request_recorder.Config{
+		File:  file,
+		Paths: []string{"/users/**"},
+	}))
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username":"bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/7", nil))
+
+	recs, err := router.LoadRecordings(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Request.URL != "/users/7" {
+		t.Errorf("Expected only /users/7 to be recorded, got %d recording(s)", len(recs))
+	}
+}
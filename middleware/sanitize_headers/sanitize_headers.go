@@ -0,0 +1,211 @@
+package sanitize_headers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const SANITIZE_HEADERS_TYPE = "sanitize_headers"
+const PARAMS_REMOVE = "remove"
+const PARAMS_REWRITE = "rewrite"
+const PARAMS_ALLOW = "allow"
+
+// Header names are case-insensitive. A name ending in "*" matches all
+// headers with that prefix, e.g. "X-Internal-*".
+type Config struct {
+	// Remove lists headers stripped from responses
+	// (default: Server, X-Powered-By, X-AspNet-Version, X-AspNetMvc-Version)
+	Remove []string
+
+	// Rewrite replaces the value of headers present in the response,
+	// e.g. {"Server": "web"}
+	Rewrite map[string]string
+
+	// Allow, if set, is an allowlist: any other header is removed, except
+	// the ones net/http needs to frame the response (Content-Length,
+	// Transfer-Encoding, Connection, Trailer, Date)
+	Allow []string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Remove:  []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"},
+		Rewrite: map[string]string{},
+		Allow:   []string{},
+	}
+}
+
+// framingHeaders are never removed by the allowlist
+var framingHeaders = []string{"Content-Length", "Transfer-Encoding", "Connection", "Trailer", "Date"}
+
+// middleware that strips or rewrites response headers right before the
+// status is sent, so headers set by handlers, other middleware and the
+// response writer are all covered:
+//
+//	r.Use(sanitize_headers.Middleware(&sanitize_headers.Config{
+//	    Remove:  []string{"X-Powered-By", "X-Internal-*"},
+//	    Rewrite: map[string]string{"Server": "web"},
+//	}))
+func Middleware(cfg *Config) request.HandlerFunc {
+	s := &sanitizer{
+		remove:  newMatcher(cfg.Remove),
+		allow:   newMatcher(slices.Concat(cfg.Allow, framingHeaders)),
+		rewrite: make(map[string]string, len(cfg.Rewrite)),
+		allowed: len(cfg.Allow) > 0,
+	}
+	for name, value := range cfg.Rewrite {
+		s.rewrite[http.CanonicalHeaderKey(name)] = value
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		orig := c.W.ResponseWriter
+		c.W.ResponseWriter = response.WrapWriter(orig, &sanitizeWriter{ResponseWriter: orig, s: s})
+		err := c.Next()
+		// Write the response while the headers can still be changed
+		c.WriteResponse(err)
+		c.W.ResponseWriter = orig
+		return err
+	})
+}
+
+type sanitizer struct {
+	remove  *matcher
+	allow   *matcher
+	rewrite map[string]string
+	allowed bool
+}
+
+func (s *sanitizer) apply(h http.Header) {
+	for name := range h {
+		if s.remove.match(name) || (s.allowed && !s.allow.match(name)) {
+			delete(h, name)
+		}
+	}
+	for name, value := range s.rewrite {
+		if _, ok := h[name]; ok {
+			h.Set(name, value)
+		}
+	}
+}
+
+// matcher matches canonical header names exactly or by prefix
+type matcher struct {
+	names    map[string]struct{}
+	prefixes []string
+}
+
+func newMatcher(names []string) *matcher {
+	m := &matcher{names: make(map[string]struct{}, len(names))}
+	for _, name := range names {
+		if prefix, ok := strings.CutSuffix(name, "*"); ok {
+			m.prefixes = append(m.prefixes, strings.ToLower(prefix))
+			continue
+		}
+		m.names[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+	return m
+}
+
+func (m *matcher) match(name string) bool {
+	if _, ok := m.names[name]; ok {
+		return true
+	}
+	if len(m.prefixes) == 0 {
+		return false
+	}
+	lower := strings.ToLower(name)
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeWriter sanitizes the headers right before the status is sent
+type sanitizeWriter struct {
+	http.ResponseWriter
+	s           *sanitizer
+	wroteHeader bool
+}
+
+func (w *sanitizeWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.s.apply(w.Header())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sanitizeWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sanitizeWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Remove:  stringsFromParam(params[PARAMS_REMOVE], defConfig.Remove),
+		Rewrite: defConfig.Rewrite,
+		Allow:   stringsFromParam(params[PARAMS_ALLOW], defConfig.Allow),
+	}
+	switch v := params[PARAMS_REWRITE].(type) {
+	case map[string]string:
+		cfg.Rewrite = v
+	case map[string]any:
+		for name, value := range v {
+			if s, ok := value.(string); ok {
+				cfg.Rewrite[name] = s
+			}
+		}
+	}
+	return Middleware(cfg)
+}
+
+// stringsFromParam accepts a []string, a YAML list or a comma separated string
+func stringsFromParam(v any, def []string) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []any:
+		names := make([]string, 0, len(v))
+		for _, name := range v {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	case string:
+		var names []string
+		for name := range strings.SplitSeq(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return def
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(SANITIZE_HEADERS_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,106 @@
+package sanitize_headers_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/sanitize_headers"
+)
+
+func newRouter(mw request.HandlerFunc) router.Router {
+	r := router.New("test-router")
+	r.Use(mw)
+	r.GET("/orders", func(c *request.Context) error {
+		h := c.Resp.RespHeaders
+		if h == nil {
+			h = map[string][]string{}
+			c.Resp.RespHeaders = h
+		}
+		h["Server"] = []string{"nginx/1.25"}
+		h["X-Powered-By"] = []string{"Go"}
+		h["X-Internal-Node"] = []string{"node-7"}
+		h["X-Request-Id"] = []string{"abc"}
+		return c.Api.Ok("ok")
+	})
+	r.GET("/direct", func(c *request.Context) error {
+		c.W.Header().Set("X-Powered-By", "Go")
+		c.W.Header().Set("X-Internal-Node", "node-7")
+		c.W.Write([]byte("ok"))
+		return nil
+	})
+	return r
+}
+
+func get(r router.Router, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestSanitizeHeaders_Defaults(t *testing.T) {
+	w := get(newRouter(sanitize_headers.Middleware(sanitize_headers.DefaultConfig())), "/orders")
+
+	if w.Code != 200 {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	for _, name := range []string{"Server", "X-Powered-By"} {
+		if got := w.Header().Get(name); got != "" {
+			t.Errorf("Expected %s removed, got %q", name, got)
+		}
+	}
+	for _, name := range []string{"X-Internal-Node", "X-Request-Id", "Content-Type"} {
+		if got := w.Header().Get(name); got == "" {
+			t.Errorf("Expected %s to pass through", name)
+		}
+	}
+}
+
+func TestSanitizeHeaders_PrefixAndRewrite(t *testing.T) {
+	r := newRouter(sanitize_headers.Middleware(&sanitize_headers.Config{
+		Remove:  []string{"x-powered-by", "X-Internal-*"},
+		Rewrite: map[string]string{"server": "web"},
+	}))
+
+	w := get(r, "/orders")
+	if got := w.Header().Get("Server"); got != "web" {
+		t.Errorf("Expected Server rewritten to web, got %q", got)
+	}
+	if got := w.Header().Get("X-Internal-Node"); got != "" {
+		t.Errorf("Expected X-Internal-Node removed by prefix, got %q", got)
+	}
+	if got := w.Header().Get("X-Request-Id"); got != "abc" {
+		t.Errorf("Expected X-Request-Id to pass through, got %q", got)
+	}
+
+	// Headers set on the writer directly are covered too
+	w = get(r, "/direct")
+	if w.Header().Get("X-Powered-By") != "" || w.Header().Get("X-Internal-Node") != "" {
+		t.Errorf("Expected direct headers removed, got %v", w.Header())
+	}
+	if got := w.Header().Get("Server"); got != "" {
+		t.Errorf("Rewrite must not add an absent header, got %q", got)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
+func TestSanitizeHeaders_Allowlist(t *testing.T) {
+	r := newRouter(sanitize_headers.MiddlewareFactory(map[string]any{
+		"allow": []any{"Content-Type", "X-Request-ID"},
+	}))
+
+	w := get(r, "/orders")
+	for _, name := range []string{"Server", "X-Powered-By", "X-Internal-Node"} {
+		if got := w.Header().Get(name); got != "" {
+			t.Errorf("Expected %s removed by allowlist, got %q", name, got)
+		}
+	}
+	for _, name := range []string{"Content-Type", "X-Request-Id"} {
+		if got := w.Header().Get(name); got == "" {
+			t.Errorf("Expected allowed %s to pass through", name)
+		}
+	}
+}
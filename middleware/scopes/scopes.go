@@ -0,0 +1,126 @@
+package scopes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const REQUIRE_SCOPES_TYPE = "require_scopes"
+const PARAMS_SCOPES = "scopes"
+const PARAMS_MODE = "mode"
+
+// Scope matching modes
+const (
+	MODE_ALL = "all" // every scope is required
+	MODE_ANY = "any" // at least one scope is required
+)
+
+type Config struct {
+	// Scopes (or roles/permissions) required from ctx.Principal()
+	Scopes []string
+
+	// Mode is MODE_ALL (default) or MODE_ANY
+	Mode string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Mode: MODE_ALL,
+	}
+}
+
+// RequireScopes requires all of the given scopes
+func RequireScopes(scopes ...string) request.HandlerFunc {
+	return Middleware(&Config{Scopes: scopes, Mode: MODE_ALL})
+}
+
+// RequireAnyScope requires at least one of the given scopes
+func RequireAnyScope(scopes ...string) request.HandlerFunc {
+	return Middleware(&Config{Scopes: scopes, Mode: MODE_ANY})
+}
+
+// middleware that checks the scopes of the authenticated principal.
+// Must run after an authentication middleware that calls ctx.SetPrincipal.
+// Requests without a principal get 401, insufficient scopes get 403
+// naming the missing scope.
+//
+// Panics when Scopes is empty or Mode is unknown: a misconfigured
+// authorization check must fail at startup, not let every request through.
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.Mode == "" {
+		cfg.Mode = defConfig.Mode
+	}
+	if cfg.Mode != MODE_ALL && cfg.Mode != MODE_ANY {
+		panic(fmt.Sprintf("%s: unknown mode %q (want %q or %q)",
+			REQUIRE_SCOPES_TYPE, cfg.Mode, MODE_ALL, MODE_ANY))
+	}
+	if len(cfg.Scopes) == 0 {
+		panic(REQUIRE_SCOPES_TYPE + ": no scopes configured")
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		p := c.Principal()
+		if p == nil {
+			return c.Api.Unauthorized("Authentication required")
+		}
+
+		if cfg.Mode == MODE_ANY {
+			for _, scope := range cfg.Scopes {
+				if p.HasScope(scope) {
+					return c.Next()
+				}
+			}
+			return c.Api.ErrorWithDetails(http.StatusForbidden, "INSUFFICIENT_SCOPE",
+				"Requires any of scopes: "+strings.Join(cfg.Scopes, ", "),
+				map[string]any{"required_any": cfg.Scopes})
+		}
+
+		for _, scope := range cfg.Scopes {
+			if !p.HasScope(scope) {
+				return c.Api.ErrorWithDetails(http.StatusForbidden, "INSUFFICIENT_SCOPE",
+					"Missing required scope: "+scope,
+					map[string]any{"missing_scope": scope})
+			}
+		}
+		return c.Next()
+	})
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Mode: utils.GetValueFromMap(params, PARAMS_MODE, defConfig.Mode),
+	}
+	switch v := params[PARAMS_SCOPES].(type) {
+	case []string:
+		cfg.Scopes = v
+	case []any:
+		for _, s := range v {
+			if scope, ok := s.(string); ok {
+				cfg.Scopes = append(cfg.Scopes, scope)
+			}
+		}
+	case string:
+		for scope := range strings.SplitSeq(v, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				cfg.Scopes = append(cfg.Scopes, scope)
+			}
+		}
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(REQUIRE_SCOPES_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
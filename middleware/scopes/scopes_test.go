@@ -0,0 +1,118 @@
+package scopes_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/scopes"
+)
+
+// auth middleware that takes the principal scopes from a header
+func fakeAuth(c *request.Context) error {
+	if h := c.R.Header.Get("X-Scopes"); h != "" {
+		c.SetPrincipal(&request.Principal{ID: "user-1", Scopes: strings.Split(h, ",")})
+	}
+	return c.Next()
+}
+
+func newRouter(mw request.HandlerFunc) router.Router {
+	r := router.New("test-router")
+	r.Use(fakeAuth)
+	r.GET("/admin", func(c *request.Context) error {
+		return c.Resp.Text(c.Principal().ID)
+	}, mw)
+	return r
+}
+
+func serve(r router.Router, scopeHeader string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/admin", nil)
+	if scopeHeader != "" {
+		req.Header.Set("X-Scopes", scopeHeader)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireScopes_Sufficient(t *testing.T) {
+	tests := []struct {
+		name   string
+		mw     request.HandlerFunc
+		scopes string
+	}{
+		{name: "all", mw: scopes.RequireScopes("orders:read", "orders:write"), scopes: "orders:read,orders:write,admin"},
+		{name: "any", mw: scopes.RequireAnyScope("admin", "orders:write"), scopes: "orders:write"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newRouter(tt.mw), tt.scopes)
+			if w.Code != 200 || w.Body.String() != "user-1" {
+				t.Errorf("Expected 200, got %d %q", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireScopes_Insufficient(t *testing.T) {
+	w := serve(newRouter(scopes.RequireScopes("orders:read", "orders:write")), "orders:read")
+	if w.Code != 403 {
+		t.Fatalf("Expected 403, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "INSUFFICIENT_SCOPE") || !strings.Contains(body, "orders:write") {
+		t.Errorf("Expected missing scope orders:write in response, got %s", body)
+	}
+
+	w = serve(newRouter(scopes.RequireAnyScope("admin", "orders:write")), "orders:read")
+	if w.Code != 403 {
+		t.Errorf("Expected 403 for any-of, got %d", w.Code)
+	}
+}
+
+func TestRequireScopes_MissingPrincipal(t *testing.T) {
+	w := serve(newRouter(scopes.RequireScopes("admin")), "")
+	if w.Code != 401 {
+		t.Errorf("Expected 401 without principal, got %d", w.Code)
+	}
+}
+
+func TestMiddlewareFactory(t *testing.T) {
+	mw := scopes.MiddlewareFactory(map[string]any{
+		scopes.PARAMS_SCOPES: []any{"admin", "support"},
+		scopes.PARAMS_MODE:   scopes.MODE_ANY,
+	})
+	if w := serve(newRouter(mw), "support"); w.Code != 200 {
+		t.Errorf("Expected 200 for any-of from params, got %d", w.Code)
+	}
+	if w := serve(newRouter(mw), "guest"); w.Code != 403 {
+		t.Errorf("Expected 403 for any-of from params, got %d", w.Code)
+	}
+}
+
+func TestMiddleware_RejectsMisconfiguration(t *testing.T) {
+	tests := map[string]*scopes.Config{
+		"no scopes":    {Mode: scopes.MODE_ALL},
+		"empty any":    {Scopes: []string{}, Mode: scopes.MODE_ANY},
+		"unknown mode": {Scopes: []string{"admin"}, Mode: "some"},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic at construction")
+				}
+			}()
+			scopes.Middleware(cfg)
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a factory without scopes")
+		}
+	}()
+	scopes.MiddlewareFactory(map[string]any{scopes.PARAMS_MODE: scopes.MODE_ANY})
+}
@@ -0,0 +1,158 @@
+package secure_headers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const SECURE_HEADERS_TYPE = "secure_headers"
+const PARAMS_HSTS_MAX_AGE = "hsts_max_age"
+const PARAMS_HSTS_INCLUDE_SUBDOMAINS = "hsts_include_subdomains"
+const PARAMS_HSTS_PRELOAD = "hsts_preload"
+const PARAMS_CONTENT_TYPE_OPTIONS = "content_type_options"
+const PARAMS_FRAME_OPTIONS = "frame_options"
+const PARAMS_FRAME_ANCESTORS = "frame_ancestors"
+const PARAMS_REFERRER_POLICY = "referrer_policy"
+const PARAMS_REDIRECT_HTTPS = "redirect_https"
+const PARAMS_TRUSTED_PROXIES = "trusted_proxies"
+
+// Header fields left empty are not sent, so start from DefaultConfig()
+// and clear what the application sets itself.
+type Config struct {
+	// HSTSMaxAge enables Strict-Transport-Security on HTTPS responses.
+	// 0 (default) disables it: once sent, browsers refuse plain HTTP
+	// for the whole max-age.
+	HSTSMaxAge time.Duration
+
+	// HSTSIncludeSubdomains adds includeSubDomains to the HSTS header
+	HSTSIncludeSubdomains bool
+
+	// HSTSPreload adds preload to the HSTS header
+	HSTSPreload bool
+
+	// ContentTypeOptions is sent as X-Content-Type-Options (default "nosniff")
+	ContentTypeOptions string
+
+	// FrameOptions is sent as X-Frame-Options (default "SAMEORIGIN")
+	FrameOptions string
+
+	// FrameAncestors, if set, is sent as Content-Security-Policy
+	// "frame-ancestors <value>", e.g. "'self'" or "'none'".
+	// Off by default since it would override an application CSP.
+	FrameAncestors string
+
+	// ReferrerPolicy is sent as Referrer-Policy
+	// (default "strict-origin-when-cross-origin")
+	ReferrerPolicy string
+
+	// RedirectHTTPS redirects plain HTTP requests to HTTPS (308).
+	// Off by default.
+	RedirectHTTPS bool
+
+	// TrustedProxies whose X-Forwarded-Proto header is honored when
+	// detecting HTTPS (IPs or CIDRs, e.g. "10.0.0.0/8"). They are added to
+	// the shared list of request.SetTrustedProxies, which is honored too.
+	TrustedProxies []string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		ContentTypeOptions: "nosniff",
+		FrameOptions:       "SAMEORIGIN",
+		ReferrerPolicy:     "strict-origin-when-cross-origin",
+		TrustedProxies:     []string{},
+	}
+}
+
+// middleware that sets security headers and optionally enforces HTTPS
+func Middleware(cfg *Config) request.HandlerFunc {
+	if len(cfg.TrustedProxies) > 0 {
+		request.AddTrustedProxies(cfg.TrustedProxies...)
+	}
+
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge/time.Second))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		secure := isHTTPS(c)
+
+		if cfg.RedirectHTTPS && !secure {
+			target := "https://" + c.R.Host + c.R.URL.RequestURI()
+			http.Redirect(c.W, c.R, target, http.StatusPermanentRedirect)
+			return nil
+		}
+
+		h := c.W.Header()
+		// HSTS is ignored by browsers over plain HTTP (RFC 6797)
+		if hsts != "" && secure {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.ContentTypeOptions != "" {
+			h.Set("X-Content-Type-Options", cfg.ContentTypeOptions)
+		}
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.FrameAncestors != "" {
+			h.Set("Content-Security-Policy", "frame-ancestors "+cfg.FrameAncestors)
+		}
+		if cfg.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		return c.Next()
+	})
+}
+
+// isHTTPS reports whether the request reached the client over HTTPS,
+// trusting X-Forwarded-Proto only from trusted proxies
+func isHTTPS(c *request.Context) bool {
+	if c.R.TLS != nil {
+		return true
+	}
+	proto := c.R.Header.Get("X-Forwarded-Proto")
+	if proto == "" || !request.IsTrustedProxy(c.PeerIP()) {
+		return false
+	}
+	// Proxy chains append values; the first one is the client-facing scheme
+	first, _, _ := strings.Cut(proto, ",")
+	return strings.EqualFold(strings.TrimSpace(first), "https")
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		HSTSMaxAge:            utils.GetValueFromMap(params, PARAMS_HSTS_MAX_AGE, defConfig.HSTSMaxAge),
+		HSTSIncludeSubdomains: utils.GetValueFromMap(params, PARAMS_HSTS_INCLUDE_SUBDOMAINS, defConfig.HSTSIncludeSubdomains),
+		HSTSPreload:           utils.GetValueFromMap(params, PARAMS_HSTS_PRELOAD, defConfig.HSTSPreload),
+		ContentTypeOptions:    utils.GetValueFromMap(params, PARAMS_CONTENT_TYPE_OPTIONS, defConfig.ContentTypeOptions),
+		FrameOptions:          utils.GetValueFromMap(params, PARAMS_FRAME_OPTIONS, defConfig.FrameOptions),
+		FrameAncestors:        utils.GetValueFromMap(params, PARAMS_FRAME_ANCESTORS, defConfig.FrameAncestors),
+		ReferrerPolicy:        utils.GetValueFromMap(params, PARAMS_REFERRER_POLICY, defConfig.ReferrerPolicy),
+		RedirectHTTPS:         utils.GetValueFromMap(params, PARAMS_REDIRECT_HTTPS, defConfig.RedirectHTTPS),
+		TrustedProxies:        utils.GetValueFromMap(params, PARAMS_TRUSTED_PROXIES, defConfig.TrustedProxies),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(SECURE_HEADERS_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
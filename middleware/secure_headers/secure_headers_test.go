@@ -0,0 +1,132 @@
+package secure_headers_test
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/secure_headers"
+)
+
+func newRouter(cfg *secure_headers.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(secure_headers.Middleware(cfg))
+	r.GET("/orders", func(c *request.Context) error {
+		return c.Resp.Text("ok")
+	})
+	return r
+}
+
+func TestSecureHeaders_Defaults(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRouter(secure_headers.DefaultConfig()).ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+
+	expected := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "SAMEORIGIN",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Strict-Transport-Security": "",
+		"Content-Security-Policy":   "",
+	}
+	for name, value := range expected {
+		if got := w.Header().Get(name); got != value {
+			t.Errorf("%s: expected %q, got %q", name, value, got)
+		}
+	}
+	if w.Code != 200 {
+		t.Errorf("Expected 200 without redirect, got %d", w.Code)
+	}
+}
+
+func TestSecureHeaders_HSTS(t *testing.T) {
+	cfg := secure_headers.DefaultConfig()
+	cfg.HSTSMaxAge = 365 * 24 * time.Hour
+	cfg.HSTSIncludeSubdomains = true
+	cfg.FrameAncestors = "'none'"
+	cfg.FrameOptions = ""
+	r := newRouter(cfg)
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.TLS = &tls.ConnectionState{}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("Unexpected HSTS header %q", got)
+	}
+	if got := w.Header().Get("Content-Security-Policy"); got != "frame-ancestors 'none'" {
+		t.Errorf("Unexpected CSP header %q", got)
+	}
+	if got := w.Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("Expected X-Frame-Options omitted, got %q", got)
+	}
+
+	// Not sent over plain HTTP
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Expected no HSTS over HTTP, got %q", got)
+	}
+}
+
+func TestSecureHeaders_RedirectBehindProxy(t *testing.T) {
+	cfg := secure_headers.DefaultConfig()
+	cfg.RedirectHTTPS = true
+	cfg.TrustedProxies = []string{"10.0.0.0/8"}
+	r := newRouter(cfg)
+	t.Cleanup(func() { request.SetTrustedProxies() })
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		proto      string
+		code       int
+	}{
+		{name: "plain http", remoteAddr: "10.1.2.3:4000", code: 308},
+		{name: "https via trusted proxy", remoteAddr: "10.1.2.3:4000", proto: "https", code: 200},
+		{name: "http via trusted proxy", remoteAddr: "10.1.2.3:4000", proto: "http", code: 308},
+		{name: "spoofed header", remoteAddr: "203.0.113.9:4000", proto: "https", code: 308},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://api.example.com/orders?page=2", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Fatalf("Expected %d, got %d", tt.code, w.Code)
+			}
+			if tt.code == 308 {
+				if loc := w.Header().Get("Location"); loc != "https://api.example.com/orders?page=2" {
+					t.Errorf("Unexpected redirect location %q", loc)
+				}
+			}
+		})
+	}
+}
+
+func TestSecureHeaders_SharedTrustedProxies(t *testing.T) {
+	request.SetTrustedProxies("172.16.0.1")
+	t.Cleanup(func() { request.SetTrustedProxies() })
+
+	cfg := secure_headers.DefaultConfig()
+	cfg.RedirectHTTPS = true
+	r := newRouter(cfg)
+
+	req := httptest.NewRequest("GET", "http://api.example.com/orders", nil)
+	req.RemoteAddr = "172.16.0.1:4000"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Errorf("Expected X-Forwarded-Proto honored from a proxy set with request.SetTrustedProxies, got %d", w.Code)
+	}
+}
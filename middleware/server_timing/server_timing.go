@@ -0,0 +1,125 @@
+package server_timing
+
+import (
+	"net/http"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const SERVER_TIMING_TYPE = "server_timing"
+const PARAMS_HEADER = "header"
+const PARAMS_LOG = "log"
+
+const HeaderServerTiming = "Server-Timing"
+
+type Config struct {
+	// Header emits the spans as a Server-Timing response header
+	// (shown by browser devtools). Spans recorded after the response
+	// started, e.g. while streaming, are only logged.
+	Header bool
+
+	// Log logs the spans of each request
+	Log bool
+
+	// CustomLogger is a custom logging function
+	// If nil, uses default logger.LogInfo
+	CustomLogger func(format string, args ...any)
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Header: true,
+		Log:    false,
+	}
+}
+
+// middleware that emits the spans recorded with ctx.Timing() as a
+// Server-Timing header and/or logs them:
+//
+//	r.Use(server_timing.Middleware(server_timing.DefaultConfig()))
+//
+//	r.GET("/users", func(c *request.Context) error {
+//	    users, err := repo.FindAll(c)
+//	    c.Timing().Mark("db")
+//	    ...
+//	})
+//	// Server-Timing: db;dur=12.4, total;dur=13.0
+func Middleware(cfg *Config) request.HandlerFunc {
+	if cfg.CustomLogger == nil {
+		cfg.CustomLogger = logger.LogInfo
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		timing := c.Timing()
+		if !cfg.Header {
+			err := c.Next()
+			if cfg.Log {
+				cfg.CustomLogger("[TIMING] %s %s - %s", c.R.Method, c.R.URL.Path, timing)
+			}
+			return err
+		}
+
+		orig := c.W.ResponseWriter
+		c.W.ResponseWriter = response.WrapWriter(orig, &timingWriter{ResponseWriter: orig, timing: timing})
+		err := c.Next()
+		// Write the response while the header can still be added
+		c.WriteResponse(err)
+		c.W.ResponseWriter = orig
+
+		if cfg.Log {
+			cfg.CustomLogger("[TIMING] %s %s - %s", c.R.Method, c.R.URL.Path, timing)
+		}
+		return err
+	})
+}
+
+// timingWriter adds the Server-Timing header right before the status is sent
+type timingWriter struct {
+	http.ResponseWriter
+	timing      *request.Timing
+	wroteHeader bool
+}
+
+func (w *timingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set(HeaderServerTiming, w.timing.ServerTiming())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *timingWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Header: utils.GetValueFromMap(params, PARAMS_HEADER, defConfig.Header),
+		Log:    utils.GetValueFromMap(params, PARAMS_LOG, defConfig.Log),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(SERVER_TIMING_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
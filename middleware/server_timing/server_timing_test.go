@@ -0,0 +1,103 @@
+package server_timing_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/server_timing"
+)
+
+func newRouter(cfg *server_timing.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(server_timing.Middleware(cfg))
+	r.GET("/users", func(c *request.Context) error {
+		time.Sleep(2 * time.Millisecond)
+		c.Timing().Mark("db")
+		if err := c.Timing().Measure("cache", func() error {
+			time.Sleep(time.Millisecond)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return c.Api.Ok([]string{"alice"})
+	})
+	r.GET("/direct", func(c *request.Context) error {
+		c.Timing().Mark("render")
+		c.W.Write([]byte("ok"))
+		return nil
+	})
+	return r
+}
+
+func get(r router.Router, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestServerTiming_Header(t *testing.T) {
+	w := get(newRouter(server_timing.DefaultConfig()), "/users")
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	header := w.Header().Get(server_timing.HeaderServerTiming)
+	for _, name := range []string{"db;dur=", "cache;dur=", "total;dur="} {
+		if !strings.Contains(header, name) {
+			t.Errorf("expected %q in Server-Timing, got %q", name, header)
+		}
+	}
+	if strings.Index(header, "db;") > strings.Index(header, "cache;") {
+		t.Errorf("expected spans in recording order, got %q", header)
+	}
+	if !strings.Contains(w.Body.String(), "alice") {
+		t.Errorf("expected body to be written, got %q", w.Body.String())
+	}
+}
+
+func TestServerTiming_DirectWrite(t *testing.T) {
+	w := get(newRouter(server_timing.DefaultConfig()), "/direct")
+	if header := w.Header().Get(server_timing.HeaderServerTiming); !strings.Contains(header, "render;dur=") {
+		t.Errorf("expected render span, got %q", header)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("expected body ok, got %q", w.Body.String())
+	}
+}
+
+func TestServerTiming_LogOnly(t *testing.T) {
+	var logged string
+	w := get(newRouter(&server_timing.Config{
+		Log: true,
+		CustomLogger: func(format string, args ...any) {
+			logged = fmt.Sprintf(format, args...)
+		},
+	}), "/users")
+
+	if header := w.Header().Get(server_timing.HeaderServerTiming); header != "" {
+		t.Errorf("expected no Server-Timing header, got %q", header)
+	}
+	for _, part := range []string{"GET /users", "db=", "cache=", "total="} {
+		if !strings.Contains(logged, part) {
+			t.Errorf("expected %q in log, got %q", part, logged)
+		}
+	}
+}
+
+func TestTiming_ServerTimingFormat(t *testing.T) {
+	c := request.NewContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	c.Timing().Mark("db query")
+
+	spans := c.Timing().Spans()
+	if len(spans) != 1 || spans[0].Name != "db query" {
+		t.Fatalf("unexpected spans %+v", spans)
+	}
+	if header := c.Timing().ServerTiming(); !strings.HasPrefix(header, "db_query;dur=") {
+		t.Errorf("expected metric name to be sanitized, got %q", header)
+	}
+}
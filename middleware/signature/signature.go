@@ -0,0 +1,244 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const SIGNATURE_TYPE = "verify_signature"
+const PARAMS_SECRETS = "secrets"
+const PARAMS_HEADER = "header"
+const PARAMS_PREFIX = "prefix"
+const PARAMS_ENCODING = "encoding"
+const PARAMS_TIMESTAMP_HEADER = "timestamp_header"
+const PARAMS_TOLERANCE = "tolerance"
+
+// Signature encodings
+const (
+	ENCODING_HEX    = "hex"
+	ENCODING_BASE64 = "base64"
+)
+
+type Config struct {
+	// Secrets used to verify the HMAC-SHA256 signature. A signature matching
+	// any of them is accepted, so a new secret can be added before the old
+	// one is removed (rotation).
+	Secrets []string
+
+	// Header carrying the signature (default: "X-Signature")
+	Header string
+
+	// Prefix stripped from the header value, e.g. "sha256=" (GitHub style)
+	Prefix string
+
+	// Encoding of the signature: "hex" (default) or "base64"
+	Encoding string
+
+	// TimestampHeader, if set, carries the unix time (seconds) the request was
+	// signed at. The signed payload is then "<timestamp>.<body>", and requests
+	// outside Tolerance are rejected to prevent replay.
+	TimestampHeader string
+
+	// Tolerance is the maximum age (and clock skew) of a signed timestamp
+	Tolerance time.Duration
+
+	// Now returns the current time (for testing)
+	Now func() time.Time
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Secrets:   []string{},
+		Header:    "X-Signature",
+		Encoding:  ENCODING_HEX,
+		Tolerance: 5 * time.Minute,
+		Now:       time.Now,
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 signature of body, as expected by the
+// middleware with default encoding. Pass an empty timestamp when
+// TimestampHeader is not used.
+func Sign(secret string, timestamp string, body []byte) string {
+	return hex.EncodeToString(computeMAC([]byte(secret), timestamp, body))
+}
+
+func computeMAC(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	if timestamp != "" {
+		mac.Write([]byte(timestamp))
+		mac.Write([]byte("."))
+	}
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Verification errors returned by Verify
+var (
+	ErrMissingSignature = errors.New("missing or malformed signature")
+	ErrMissingTimestamp = errors.New("missing or malformed signature timestamp")
+	ErrStaleSignature   = errors.New("signature timestamp is outside the allowed window")
+	ErrInvalidSignature = errors.New("signature verification failed")
+)
+
+// Verify checks the signature (and timestamp, if configured) carried by
+// header against body, for handlers verifying signatures themselves (see
+// lokstra_handler/webhook). Unset cfg fields use the defaults.
+func Verify(cfg *Config, header http.Header, body []byte) error {
+	cfg = withDefaults(cfg)
+	secrets := make([][]byte, len(cfg.Secrets))
+	for i, s := range cfg.Secrets {
+		secrets[i] = []byte(s)
+	}
+	return verify(cfg, secrets, header, body)
+}
+
+func verify(cfg *Config, secrets [][]byte, header http.Header, body []byte) error {
+	provided, ok := decodeSignature(header.Get(cfg.Header), cfg)
+	if !ok {
+		return ErrMissingSignature
+	}
+
+	timestamp := ""
+	if cfg.TimestampHeader != "" {
+		timestamp = header.Get(cfg.TimestampHeader)
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return ErrMissingTimestamp
+		}
+		age := cfg.Now().Sub(time.Unix(unix, 0))
+		if age > cfg.Tolerance || age < -cfg.Tolerance {
+			return ErrStaleSignature
+		}
+	}
+
+	for _, secret := range secrets {
+		if hmac.Equal(provided, computeMAC(secret, timestamp, body)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// withDefaults returns a copy of cfg with unset fields defaulted
+func withDefaults(cfg *Config) *Config {
+	defConfig := DefaultConfig()
+	if cfg == nil {
+		return defConfig
+	}
+	c := *cfg
+	if c.Header == "" {
+		c.Header = defConfig.Header
+	}
+	if c.Encoding == "" {
+		c.Encoding = defConfig.Encoding
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = defConfig.Tolerance
+	}
+	if c.Now == nil {
+		c.Now = defConfig.Now
+	}
+	return &c
+}
+
+// middleware that rejects requests without a valid HMAC signature (401)
+func Middleware(cfg *Config) request.HandlerFunc {
+	cfg = withDefaults(cfg)
+	if len(cfg.Secrets) == 0 {
+		logger.LogWarn("verify_signature: no secrets configured, all requests will be rejected")
+	}
+
+	secrets := make([][]byte, len(cfg.Secrets))
+	for i, s := range cfg.Secrets {
+		secrets[i] = []byte(s)
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		// Unsigned requests are rejected before their body is read
+		if _, ok := decodeSignature(c.R.Header.Get(cfg.Header), cfg); !ok {
+			return WriteError(c, ErrMissingSignature)
+		}
+
+		// Buffered by the request helper, so binding (or anything reading
+		// c.R.Body) still sees the whole body
+		body, err := c.Req.RawRequestBody()
+		if err != nil {
+			return c.Api.BadRequest("INVALID_BODY", "Failed to read request body")
+		}
+
+		if err := verify(cfg, secrets, c.R.Header, body); err != nil {
+			return WriteError(c, err)
+		}
+		return c.Next()
+	})
+}
+
+// WriteError writes the 401 response for a Verify error
+func WriteError(c *request.Context, err error) error {
+	switch {
+	case errors.Is(err, ErrMissingSignature):
+		return c.Api.Unauthorized("Missing or malformed signature")
+	case errors.Is(err, ErrMissingTimestamp):
+		return c.Api.Unauthorized("Missing or malformed signature timestamp")
+	case errors.Is(err, ErrStaleSignature):
+		return c.Api.Error(http.StatusUnauthorized, "STALE_SIGNATURE", "Signature timestamp is outside the allowed window")
+	default:
+		return c.Api.Error(http.StatusUnauthorized, "INVALID_SIGNATURE", "Signature verification failed")
+	}
+}
+
+func decodeSignature(value string, cfg *Config) ([]byte, bool) {
+	value, ok := strings.CutPrefix(strings.TrimSpace(value), cfg.Prefix)
+	if !ok || value == "" {
+		return nil, false
+	}
+
+	var sig []byte
+	var err error
+	if cfg.Encoding == ENCODING_BASE64 {
+		sig, err = base64.StdEncoding.DecodeString(value)
+	} else {
+		sig, err = hex.DecodeString(value)
+	}
+	return sig, err == nil
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	secrets := utils.GetValueFromMap(params, PARAMS_SECRETS, defConfig.Secrets)
+	if secret := utils.GetValueFromMap(params, PARAMS_SECRETS, ""); secret != "" {
+		secrets = []string{secret}
+	}
+
+	cfg := &Config{
+		Secrets:         secrets,
+		Header:          utils.GetValueFromMap(params, PARAMS_HEADER, defConfig.Header),
+		Prefix:          utils.GetValueFromMap(params, PARAMS_PREFIX, ""),
+		Encoding:        utils.GetValueFromMap(params, PARAMS_ENCODING, defConfig.Encoding),
+		TimestampHeader: utils.GetValueFromMap(params, PARAMS_TIMESTAMP_HEADER, ""),
+		Tolerance:       utils.GetValueFromMap(params, PARAMS_TOLERANCE, defConfig.Tolerance),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(SIGNATURE_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,147 @@
+package signature_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/signature"
+)
+
+type Event struct {
+	Type string `json:"type" validate:"required"`
+}
+
+var now = time.Unix(1_700_000_000, 0)
+
+func newRouter(cfg *signature.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(signature.Middleware(cfg))
+	// Binding still works after the middleware read the raw body
+	r.POST("/webhook", func(c *request.Context, e *Event) error {
+		return c.Api.Ok(e.Type)
+	})
+	return r
+}
+
+func post(r router.Router, body, sig, timestamp string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", sig)
+	if timestamp != "" {
+		req.Header.Set("X-Timestamp", timestamp)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSignature_ValidAndInvalid(t *testing.T) {
+	body := `{"type":"payment.succeeded"}`
+	r := newRouter(&signature.Config{
+		Secrets: []string{"new-secret", "old-secret"},
+		Prefix:  "sha256=",
+	})
+
+	// Both current and rotated-out secret are accepted
+	for _, secret := range []string{"new-secret", "old-secret"} {
+		w := post(r, body, "sha256="+signature.Sign(secret, "", []byte(body)), "")
+		if w.Code != 200 || !strings.Contains(w.Body.String(), "payment.succeeded") {
+			t.Errorf("Secret %s: expected 200 with bound body, got %d %s", secret, w.Code, w.Body.String())
+		}
+	}
+
+	tests := map[string]string{
+		"wrong secret":  "sha256=" + signature.Sign("other", "", []byte(body)),
+		"tampered body": "sha256=" + signature.Sign("new-secret", "", []byte(`{"type":"x"}`)),
+		"missing":       "",
+		"malformed":     "sha256=not-hex",
+		"no prefix":     signature.Sign("new-secret", "", []byte(body)),
+	}
+	for name, sig := range tests {
+		if w := post(r, body, sig, ""); w.Code != 401 {
+			t.Errorf("%s: expected 401, got %d", name, w.Code)
+		}
+	}
+}
+
+func TestSignature_Timestamp(t *testing.T) {
+	body := `{"type":"invoice.paid"}`
+	r := newRouter(&signature.Config{
+		Secrets:         []string{"secret"},
+		TimestampHeader: "X-Timestamp",
+		Tolerance:       5 * time.Minute,
+		Now:             func() time.Time { return now },
+	})
+
+	fresh := strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)
+	if w := post(r, body, signature.Sign("secret", fresh, []byte(body)), fresh); w.Code != 200 {
+		t.Errorf("Expected 200 for fresh signature, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Replayed request: valid signature, but signed too long ago
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	w := post(r, body, signature.Sign("secret", stale, []byte(body)), stale)
+	if w.Code != 401 || !strings.Contains(w.Body.String(), "STALE_SIGNATURE") {
+		t.Errorf("Expected 401 STALE_SIGNATURE, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Timestamp is part of the signed payload: it cannot be swapped
+	if w := post(r, body, signature.Sign("secret", stale, []byte(body)), fresh); w.Code != 401 {
+		t.Errorf("Expected 401 for swapped timestamp, got %d", w.Code)
+	}
+}
+
+func TestSignature_BodyReusableByLaterReaders(t *testing.T) {
+	body := `{"type":"invoice.paid"}`
+	var audited []string
+	// Audit-style middleware reading c.R.Body directly, twice in a row
+	audit := func(c *request.Context) error {
+		b, _ := io.ReadAll(c.R.Body)
+		audited = append(audited, string(b))
+		return c.Next()
+	}
+
+	r := router.New("test-router")
+	r.Use(signature.Middleware(&signature.Config{Secrets: []string{"secret"}}), audit, audit)
+	r.POST("/webhook", func(c *request.Context, e *Event) error {
+		raw, err := c.Req.RawRequestBody()
+		if err != nil {
+			return err
+		}
+		return c.Api.Ok(map[string]string{"type": e.Type, "raw": string(raw)})
+	})
+
+	w := post(r, body, signature.Sign("secret", "", []byte(body)), "")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"type":"invoice.paid"`) {
+		t.Fatalf("Expected 200 with bound body, got %d %s", w.Code, w.Body.String())
+	}
+	if len(audited) != 2 || audited[0] != body || audited[1] != body {
+		t.Errorf("Expected both readers to see the whole body, got %q", audited)
+	}
+}
+
+// failingReader fails the test if the body is read
+type failingReader struct{ t *testing.T }
+
+func (r failingReader) Read([]byte) (int, error) {
+	r.t.Error("Body of an unsigned request must not be read")
+	return 0, io.EOF
+}
+
+func TestSignature_MissingHeaderSkipsBody(t *testing.T) {
+	r := newRouter(&signature.Config{Secrets: []string{"s1"}})
+
+	req := httptest.NewRequest("POST", "/webhook", failingReader{t})
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != 401 {
+		t.Errorf("Expected 401, got %d %s", w.Code, w.Body.String())
+	}
+}
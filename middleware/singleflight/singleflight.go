@@ -0,0 +1,270 @@
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const SINGLE_FLIGHT_TYPE = "single_flight"
+const PARAMS_METHODS = "methods"
+const PARAMS_INCLUDE_HEADERS = "include_headers"
+
+type Config struct {
+	// KeyFunc returns the coalescing key for a request.
+	// Requests with the same key that arrive while a handler is running
+	// share that handler's result. Return "" to bypass coalescing.
+	// If nil, the key is built from method, URL and IncludeHeaders.
+	KeyFunc func(c *request.Context) string
+
+	// Methods that are eligible for coalescing when using the default key
+	// Example: ["GET", "HEAD"]
+	Methods []string
+
+	// IncludeHeaders are request headers added to the default key
+	// (e.g. "Accept-Language" when responses differ per header).
+	// The credential headers (Authorization, Cookie) are always part of
+	// the default key, so different callers never share a response.
+	IncludeHeaders []string
+}
+
+// credentialHeaders are always part of the default key
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Methods:        []string{http.MethodGet, http.MethodHead},
+		IncludeHeaders: []string{},
+	}
+}
+
+// call is an in-flight or completed handler execution
+type call struct {
+	done chan struct{}
+	res  *result
+}
+
+// result is the shared outcome of the leader's execution
+type result struct {
+	err      error
+	status   int
+	header   http.Header
+	body     []byte
+	canceled bool // leader's own request was canceled, waiters must retry
+}
+
+// group tracks in-flight calls per key (scoped to one middleware instance)
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// collapses identical concurrent requests into a single handler execution.
+// All waiters receive the leader's response (or its error).
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.Methods == nil {
+		cfg.Methods = defConfig.Methods
+	}
+	if cfg.IncludeHeaders == nil {
+		cfg.IncludeHeaders = defConfig.IncludeHeaders
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = defaultKeyFunc(cfg)
+	}
+
+	g := &group{calls: make(map[string]*call)}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		key := cfg.KeyFunc(c)
+		if key == "" {
+			return c.Next()
+		}
+
+		for {
+			g.mu.Lock()
+			cl, inFlight := g.calls[key]
+			if !inFlight {
+				cl = &call{done: make(chan struct{})}
+				g.calls[key] = cl
+				g.mu.Unlock()
+				return g.lead(c, key, cl)
+			}
+			g.mu.Unlock()
+
+			select {
+			case <-cl.done:
+			case <-c.R.Context().Done():
+				return c.R.Context().Err()
+			}
+
+			if cl.res.canceled {
+				// Leader gave up because its client went away; try again
+				continue
+			}
+			return replay(c, cl.res)
+		}
+	})
+}
+
+// lead runs the handler chain and publishes the result to waiters
+func (g *group) lead(c *request.Context, key string, cl *call) (err error) {
+	res := &result{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			res.err = errors.New("single flight: leader panicked")
+			g.finish(key, cl, res)
+			panic(rec)
+		}
+		g.finish(key, cl, res)
+	}()
+
+	orig := c.W.ResponseWriter
+	rec := newRecorder()
+	c.W.ResponseWriter = rec
+	err = c.Next()
+	c.W.ResponseWriter = orig
+
+	if err != nil {
+		res.err = err
+		res.canceled = isCanceled(c, err)
+		return err
+	}
+
+	if !c.W.ManualWritten() {
+		// Materialize the response once so it can be shared
+		c.Resp.WriteHttp(rec)
+		res.status, res.header, res.body = rec.status(), rec.header, rec.body
+		return replay(c, res)
+	}
+
+	// Handler wrote directly; the wrapper already tracked the status,
+	// so replay into the original writer
+	res.status, res.header, res.body = rec.status(), rec.header, rec.body
+	copyHeader(orig.Header(), res.header)
+	orig.WriteHeader(res.status)
+	_, err = orig.Write(res.body)
+	return err
+}
+
+func (g *group) finish(key string, cl *call, res *result) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	cl.res = res
+	close(cl.done)
+}
+
+// replay sets a shared result as the pending response of the current
+// request; it is written when the request is finalized
+func replay(c *request.Context, res *result) error {
+	if res.err != nil {
+		return res.err
+	}
+	copyHeader(c.W.Header(), res.header)
+	ct := c.W.Header().Get("Content-Type")
+	c.W.Header().Del("Content-Type")
+
+	*c.Resp = *response.NewResponse().WithStatus(res.status)
+	if len(res.body) > 0 || ct != "" {
+		return c.Resp.Raw(ct, res.body)
+	}
+	return nil
+}
+
+func isCanceled(c *request.Context, err error) bool {
+	if c.R.Context().Err() == nil {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, values := range src {
+		dst[k] = slices.Clone(values)
+	}
+}
+
+func defaultKeyFunc(cfg *Config) func(c *request.Context) string {
+	return func(c *request.Context) string {
+		if !slices.Contains(cfg.Methods, c.R.Method) {
+			return ""
+		}
+		var sb strings.Builder
+		sb.WriteString(c.R.Method)
+		sb.WriteByte(' ')
+		sb.WriteString(c.R.URL.RequestURI())
+		for _, h := range credentialHeaders {
+			sb.WriteByte('|')
+			sb.WriteString(c.R.Header.Get(h))
+		}
+		for _, h := range cfg.IncludeHeaders {
+			sb.WriteByte('|')
+			sb.WriteString(c.R.Header.Get(h))
+		}
+		return sb.String()
+	}
+}
+
+// recorder captures a response so it can be shared with waiters
+type recorder struct {
+	header http.Header
+	code   int
+	body   []byte
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: make(http.Header)}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	r.body = append(r.body, b...)
+	return len(b), nil
+}
+
+func (r *recorder) status() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Methods:        utils.GetValueFromMap(params, PARAMS_METHODS, defConfig.Methods),
+		IncludeHeaders: utils.GetValueFromMap(params, PARAMS_INCLUDE_HEADERS, defConfig.IncludeHeaders),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(SINGLE_FLIGHT_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,180 @@
+package singleflight_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/singleflight"
+)
+
+// fireConcurrent sends n identical requests and releases the handler
+// once every request has reached the single flight middleware
+func fireConcurrent(t *testing.T, n int, handler func(c *request.Context) error) []*httptest.ResponseRecorder {
+	t.Helper()
+
+	reqs := make([]*http.Request, n)
+	for i := range reqs {
+		reqs[i] = httptest.NewRequest("GET", "/report?range=7d", nil)
+	}
+	return fireRequests(t, reqs, handler)
+}
+
+// fireRequests sends reqs concurrently and releases the handler once every
+// request has reached the single flight middleware
+func fireRequests(t *testing.T, reqs []*http.Request, handler func(c *request.Context) error) []*httptest.ResponseRecorder {
+	t.Helper()
+
+	n := len(reqs)
+	var arrived atomic.Int32
+	allArrived := make(chan struct{})
+
+	r := router.New("test-router")
+	r.Use(func(c *request.Context) error {
+		if arrived.Add(1) == int32(n) {
+			close(allArrived)
+		}
+		err := c.Next()
+		if c.ResponseStarted() {
+			// Shared results must go through c.Resp, not direct writes
+			t.Errorf("Response written directly to the writer")
+		}
+		return err
+	})
+	r.Use(singleflight.Middleware(&singleflight.Config{}))
+	r.GET("/report", func(c *request.Context) error {
+		<-allArrived
+		// Give waiters time to join the in-flight call
+		time.Sleep(50 * time.Millisecond)
+		return handler(c)
+	})
+
+	recorders := make([]*httptest.ResponseRecorder, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recorders[i] = httptest.NewRecorder()
+			r.ServeHTTP(recorders[i], reqs[i])
+		}(i)
+	}
+	wg.Wait()
+	return recorders
+}
+
+func TestSingleFlight_HandlerRunsOnce(t *testing.T) {
+	var executions atomic.Int32
+
+	recorders := fireConcurrent(t, 10, func(c *request.Context) error {
+		executions.Add(1)
+		c.W.Header().Set("X-Report", "weekly")
+		return c.Api.Ok(map[string]any{"total": 42})
+	})
+
+	if got := executions.Load(); got != 1 {
+		t.Fatalf("Expected handler to run once, ran %d times", got)
+	}
+
+	expected := recorders[0].Body.String()
+	for i, w := range recorders {
+		if w.Code != 200 {
+			t.Errorf("Request %d: expected status 200, got %d", i, w.Code)
+		}
+		if w.Body.String() != expected {
+			t.Errorf("Request %d: body mismatch: %s", i, w.Body.String())
+		}
+		if w.Header().Get("X-Report") != "weekly" {
+			t.Errorf("Request %d: expected shared header", i)
+		}
+	}
+}
+
+func TestSingleFlight_ErrorSharedWithWaiters(t *testing.T) {
+	var executions atomic.Int32
+
+	recorders := fireConcurrent(t, 5, func(c *request.Context) error {
+		executions.Add(1)
+		return errors.New("backend unavailable")
+	})
+
+	if got := executions.Load(); got != 1 {
+		t.Fatalf("Expected handler to run once, ran %d times", got)
+	}
+	for i, w := range recorders {
+		if w.Code != 500 {
+			t.Errorf("Request %d: expected status 500, got %d", i, w.Code)
+		}
+	}
+}
+
+func TestSingleFlight_DifferentCredentialsNotCoalesced(t *testing.T) {
+	var executions atomic.Int32
+
+	reqs := make([]*http.Request, 2)
+	for i, token := range []string{"alice-token", "bob-token"} {
+		reqs[i] = httptest.NewRequest("GET", "/report?range=7d", nil)
+		reqs[i].Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorders := fireRequests(t, reqs, func(c *request.Context) error {
+		executions.Add(1)
+		return c.Api.Ok(c.R.Header.Get("Authorization"))
+	})
+
+	if got := executions.Load(); got != 2 {
+		t.Fatalf("Expected 2 executions, got %d", got)
+	}
+	for i, token := range []string{"alice-token", "bob-token"} {
+		if !strings.Contains(recorders[i].Body.String(), token) {
+			t.Errorf("Request %d: expected own response, got %s", i, recorders[i].Body.String())
+		}
+	}
+}
+
+func TestSingleFlight_DifferentKeysNotCoalesced(t *testing.T) {
+	var executions atomic.Int32
+
+	r := router.New("test-router")
+	r.Use(singleflight.Middleware(&singleflight.Config{}))
+	r.GET("/report", func(c *request.Context) error {
+		executions.Add(1)
+		return c.Api.Ok("ok")
+	})
+
+	for _, q := range []string{"a", "b", "c"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/report?q="+q, nil))
+	}
+
+	if got := executions.Load(); got != 3 {
+		t.Errorf("Expected 3 executions, got %d", got)
+	}
+}
+
+func TestSingleFlight_BypassNonEligibleMethods(t *testing.T) {
+	var executions atomic.Int32
+
+	r := router.New("test-router")
+	r.Use(singleflight.Middleware(&singleflight.Config{}))
+	r.POST("/report", func(c *request.Context) error {
+		executions.Add(1)
+		return c.Api.Ok("ok")
+	})
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/report", nil))
+	}
+
+	if got := executions.Load(); got != 2 {
+		t.Errorf("Expected 2 executions, got %d", got)
+	}
+}
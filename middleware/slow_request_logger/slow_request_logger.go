@@ -110,7 +110,7 @@ func Middleware(cfg *Config) request.HandlerFunc {
 					colorReset,
 					internal.FormatDuration(cfg.Threshold),
 				)
-				cfg.CustomLogger("%s", msg)
+				cfg.CustomLogger("%s", c.Correlation().AppendTo(msg))
 			} else {
 				msg := fmt.Sprintf("[SLOW REQUEST] [%s] %s - Status: %d - Duration: %s (threshold: %s)",
 					c.R.Method,
@@ -119,7 +119,7 @@ func Middleware(cfg *Config) request.HandlerFunc {
 					internal.FormatDuration(duration),
 					internal.FormatDuration(cfg.Threshold),
 				)
-				cfg.CustomLogger("%s", msg)
+				cfg.CustomLogger("%s", c.Correlation().AppendTo(msg))
 			}
 		}
 
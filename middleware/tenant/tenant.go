@@ -0,0 +1,154 @@
+package tenant
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const TENANT_TYPE = "tenant"
+const PARAMS_SOURCE = "source"
+const PARAMS_HEADER = "header"
+const PARAMS_BASE_DOMAIN = "base_domain"
+const PARAMS_PATH_PREFIX = "path_prefix"
+const PARAMS_OPTIONAL = "optional"
+
+// Tenant sources for MiddlewareFactory
+const (
+	SOURCE_HEADER    = "header"
+	SOURCE_SUBDOMAIN = "subdomain"
+	SOURCE_PATH      = "path"
+)
+
+// Resolver extracts the tenant id from a request ("" if not present)
+type Resolver func(r *http.Request) string
+
+type Config struct {
+	// Resolver extracts the tenant id (default: FromHeader("X-Tenant-ID"))
+	Resolver Resolver
+
+	// Optional lets requests without a tenant pass through with an empty
+	// tenant. By default they are rejected with 400.
+	Optional bool
+
+	// Allowed, if set, restricts tenant ids (others get 404)
+	Allowed func(tenant string) bool
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Resolver: FromHeader("X-Tenant-ID"),
+		Optional: false,
+	}
+}
+
+// FromHeader resolves the tenant from a request header
+func FromHeader(name string) Resolver {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(name))
+	}
+}
+
+// FromSubdomain resolves the tenant from the first label of the host
+// below baseDomain, e.g. "acme.example.com" → "acme" for "example.com"
+func FromSubdomain(baseDomain string) Resolver {
+	suffix := "." + strings.ToLower(strings.Trim(baseDomain, "."))
+	return func(r *http.Request) string {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		sub, ok := strings.CutSuffix(host, suffix)
+		if !ok || sub == "" {
+			return ""
+		}
+		// "eu.acme.example.com" → "acme"
+		return sub[strings.LastIndex(sub, ".")+1:]
+	}
+}
+
+// FromPathPrefix resolves the tenant from the path segment following
+// prefix, e.g. "/t/acme/orders" → "acme" for "/t/"
+func FromPathPrefix(prefix string) Resolver {
+	prefix = "/" + strings.Trim(prefix, "/") + "/"
+	if prefix == "//" {
+		prefix = "/"
+	}
+	return func(r *http.Request) string {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok {
+			return ""
+		}
+		tenant, _, _ := strings.Cut(rest, "/")
+		return tenant
+	}
+}
+
+// FirstOf tries resolvers in order and returns the first tenant found
+func FirstOf(resolvers ...Resolver) Resolver {
+	return func(r *http.Request) string {
+		for _, resolve := range resolvers {
+			if tenant := resolve(r); tenant != "" {
+				return tenant
+			}
+		}
+		return ""
+	}
+}
+
+// middleware that resolves the tenant and stores it on the context
+// (read with ctx.Tenant() or request.TenantFromContext)
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.Resolver == nil {
+		cfg.Resolver = defConfig.Resolver
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		tenant := cfg.Resolver(c.R)
+		if tenant == "" {
+			if cfg.Optional {
+				return c.Next()
+			}
+			return c.Api.BadRequest("TENANT_REQUIRED", "Tenant could not be determined")
+		}
+		if cfg.Allowed != nil && !cfg.Allowed(tenant) {
+			return c.Api.NotFound("Unknown tenant")
+		}
+
+		c.SetTenant(tenant)
+		return c.Next()
+	})
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	var resolver Resolver
+	switch utils.GetValueFromMap(params, PARAMS_SOURCE, SOURCE_HEADER) {
+	case SOURCE_SUBDOMAIN:
+		resolver = FromSubdomain(utils.GetValueFromMap(params, PARAMS_BASE_DOMAIN, ""))
+	case SOURCE_PATH:
+		resolver = FromPathPrefix(utils.GetValueFromMap(params, PARAMS_PATH_PREFIX, "/"))
+	default:
+		resolver = FromHeader(utils.GetValueFromMap(params, PARAMS_HEADER, "X-Tenant-ID"))
+	}
+
+	cfg := &Config{
+		Resolver: resolver,
+		Optional: utils.GetValueFromMap(params, PARAMS_OPTIONAL, defConfig.Optional),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(TENANT_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
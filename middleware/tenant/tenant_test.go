@@ -0,0 +1,109 @@
+package tenant_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/middleware/tenant"
+)
+
+func newRouter(cfg *tenant.Config) router.Router {
+	r := router.New("test-router")
+	r.Use(tenant.Middleware(cfg))
+	handler := func(c *request.Context) error {
+		return c.Resp.Text(c.Tenant())
+	}
+	r.GET("/orders", handler)
+	r.GET("/t/{tenant}/orders", handler)
+	return r
+}
+
+func TestTenant_Sources(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver tenant.Resolver
+		path     string
+		host     string
+		header   string
+	}{
+		{name: "header", resolver: tenant.FromHeader("X-Tenant-ID"), path: "/orders", header: "acme"},
+		{name: "subdomain", resolver: tenant.FromSubdomain("example.com"), path: "/orders", host: "acme.example.com:8080"},
+		{name: "path", resolver: tenant.FromPathPrefix("/t/"), path: "/t/acme/orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRouter(&tenant.Config{Resolver: tt.resolver})
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != 200 || w.Body.String() != "acme" {
+				t.Errorf("Expected tenant acme, got %d %q", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTenant_Missing(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRouter(&tenant.Config{}).ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+	if w.Code != 400 {
+		t.Errorf("Expected 400 without tenant, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	newRouter(&tenant.Config{Optional: true}).ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+	if w.Code != 200 || w.Body.String() != "" {
+		t.Errorf("Expected pass-through without tenant, got %d %q", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.Header.Set("X-Tenant-ID", "evil")
+	w = httptest.NewRecorder()
+	newRouter(&tenant.Config{Allowed: func(id string) bool { return id == "acme" }}).ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Errorf("Expected 404 for unknown tenant, got %d", w.Code)
+	}
+}
+
+type Store struct{ Name string }
+
+// repository-style code that only receives a context.Context
+func storeName(ctx context.Context) string {
+	store, _ := lokstra_registry.GetTenantService[*Store](ctx, "tenant-test-store")
+	return store.Name
+}
+
+func TestTenant_ScopedService(t *testing.T) {
+	lokstra_registry.RegisterService("tenant-test-store", &Store{Name: "shared"})
+	lokstra_registry.RegisterService(lokstra_registry.TenantServiceName("tenant-test-store", "acme"),
+		&Store{Name: "acme-db"})
+
+	r := router.New("test-router")
+	r.Use(tenant.Middleware(&tenant.Config{}))
+	r.GET("/store", func(c *request.Context) error {
+		return c.Resp.Text(storeName(c))
+	})
+
+	for tenantID, expected := range map[string]string{"acme": "acme-db", "globex": "shared"} {
+		req := httptest.NewRequest("GET", "/store", nil)
+		req.Header.Set("X-Tenant-ID", tenantID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Body.String() != expected {
+			t.Errorf("Tenant %s: expected store %q, got %q", tenantID, expected, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package timeout
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+)
+
+const TIMEOUT_TYPE = "timeout"
+const PARAMS_TIMEOUT = "timeout"
+const PARAMS_HEADER = "header"
+
+// HeaderTimeout carries the time budget of the request in milliseconds
+const HeaderTimeout = "X-Request-Timeout-Ms"
+
+type Config struct {
+	// Timeout is the time budget of the request (see request.Context.SetTimeout).
+	// With nested timeouts (app and route), the earlier deadline wins.
+	Timeout time.Duration
+
+	// Header sends the effective budget in X-Request-Timeout-Ms, so
+	// clients can size their own timeouts. Off by default to not expose limits.
+	Header bool
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout: 30 * time.Second,
+		Header:  false,
+	}
+}
+
+// middleware that gives each request a deadline: downstream calls made
+// with the request context share the budget, and an expired deadline is
+// answered with 504
+//
+//	app.Use(timeout.Middleware(&timeout.Config{Timeout: 10 * time.Second, Header: true}))
+//	r.GET("/search", search, timeout.Middleware(&timeout.Config{Timeout: 2 * time.Second, Header: true}))
+func Middleware(cfg *Config) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defConfig.Timeout
+	}
+
+	return request.HandlerFunc(func(c *request.Context) error {
+		// Cancel after the response is written: streamed responses
+		// (c.Resp.StreamTo) run after the handler chain returns
+		c.OnFinalize(c.SetTimeout(cfg.Timeout))
+
+		if cfg.Header {
+			if remaining, ok := c.Remaining(); ok {
+				c.W.Header().Set(HeaderTimeout, strconv.FormatInt(remaining.Milliseconds(), 10))
+			}
+		}
+		return c.Next()
+	})
+}
+
+func MiddlewareFactory(params map[string]any) request.HandlerFunc {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return Middleware(defConfig)
+	}
+
+	cfg := &Config{
+		Timeout: utils.GetValueFromMap(params, PARAMS_TIMEOUT, defConfig.Timeout),
+		Header:  utils.GetValueFromMap(params, PARAMS_HEADER, defConfig.Header),
+	}
+	return Middleware(cfg)
+}
+
+func Register() {
+	lokstra_registry.RegisterMiddlewareFactory(TIMEOUT_TYPE, MiddlewareFactory,
+		lokstra_registry.AllowOverride(true))
+}
@@ -0,0 +1,86 @@
+package timeout_test
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/core/response"
+	"github.com/primadi/lokstra/core/router"
+	"github.com/primadi/lokstra/middleware/timeout"
+)
+
+func budget(t *testing.T, mw ...any) (string, bool) {
+	t.Helper()
+	var hasDeadline bool
+	r := router.New("test-router")
+	r.GET("/ping", func(c *request.Context) error {
+		_, hasDeadline = c.Deadline()
+		return c.Api.Ok("pong")
+	}, mw...)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	return w.Header().Get(timeout.HeaderTimeout), hasDeadline
+}
+
+func TestTimeout_Header(t *testing.T) {
+	got, hasDeadline := budget(t, timeout.Middleware(&timeout.Config{Timeout: 2 * time.Second, Header: true}))
+	if !hasDeadline {
+		t.Error("handler has no deadline")
+	}
+	ms, err := strconv.Atoi(got)
+	if err != nil || ms <= 1900 || ms > 2000 {
+		t.Errorf("budget header = %q, want about 2000", got)
+	}
+}
+
+func TestTimeout_NestedReportsEffectiveBudget(t *testing.T) {
+	app := timeout.Middleware(&timeout.Config{Timeout: 10 * time.Second, Header: true})
+
+	// Tighter route timeout wins
+	got, _ := budget(t, app, timeout.Middleware(&timeout.Config{Timeout: time.Second, Header: true}))
+	if ms, _ := strconv.Atoi(got); ms <= 900 || ms > 1000 {
+		t.Errorf("budget header = %q, want about 1000", got)
+	}
+
+	// A looser route timeout cannot extend the app budget
+	got, _ = budget(t, app, timeout.Middleware(&timeout.Config{Timeout: time.Minute, Header: true}))
+	if ms, _ := strconv.Atoi(got); ms <= 9900 || ms > 10000 {
+		t.Errorf("budget header = %q, want about 10000", got)
+	}
+}
+
+func TestTimeout_HeaderOffByDefault(t *testing.T) {
+	got, hasDeadline := budget(t, timeout.Middleware(&timeout.Config{Timeout: time.Second}))
+	if got != "" {
+		t.Errorf("budget header sent with header disabled: %q", got)
+	}
+	if !hasDeadline {
+		t.Error("handler has no deadline")
+	}
+}
+
+func TestTimeout_StreamedResponse(t *testing.T) {
+	r := router.New("test-router")
+	r.GET("/events", func(c *request.Context) error {
+		return c.Resp.StreamTo("text/event-stream", func(s *response.StreamWriter) error {
+			_, err := s.WriteString("data: hello\n\n")
+			return err
+		})
+	}, timeout.Middleware(&timeout.Config{Timeout: time.Second}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "data: hello\n\n" {
+		t.Errorf("body = %q, want the streamed event", got)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/core/request"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -154,7 +153,7 @@ func (s *AuthService) Login(ctx *request.Context, params *LoginRequest) (*LoginR
 	session := &Session{
 		UserID:    user.ID,
 		TenantID:  user.TenantID,
-		IPAddress: utils.ClientIP(ctx.R),
+		IPAddress: ctx.ClientIP(),
 		UserAgent: ctx.Req.HeaderParam("User-Agent", ""),
 	}
 	s.repo.CreateSession(ctx, session)
@@ -0,0 +1,28 @@
+package serviceapi
+
+import (
+	"context"
+	"time"
+)
+
+// DeadLetter is an async event delivery that still failed after all
+// retries, kept for inspection and replay
+type DeadLetter struct {
+	ID    string
+	Event Event
+	// Subscription is the handler that failed; 0 means the event was never
+	// delivered (e.g. published to a closed bus) and is meant for all handlers
+	Subscription SubscriptionID
+	Error        string
+	Attempts     int
+	FailedAt     time.Time
+}
+
+// DeadLetterStore persists dead letters. Implementations storing outside
+// the process must serialize Event.Payload themselves.
+type DeadLetterStore interface {
+	Add(ctx context.Context, dl DeadLetter) error
+	// List returns up to limit dead letters, oldest first (limit <= 0: all)
+	List(ctx context.Context, limit int) ([]DeadLetter, error)
+	Remove(ctx context.Context, id string) error
+}
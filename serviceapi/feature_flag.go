@@ -0,0 +1,36 @@
+package serviceapi
+
+import "context"
+
+// FlagAttributes holds the attributes used to evaluate rollouts
+// (e.g. "user_id", "tenant", "region")
+type FlagAttributes = map[string]string
+
+// FeatureFlag evaluates runtime feature flags
+type FeatureFlag interface {
+	// IsEnabled reports whether the flag is enabled for the given context.
+	// Rollout attributes are taken from the context (see featureflag.WithAttributes),
+	// the authenticated principal, or request context values; never from
+	// client-supplied headers or query parameters.
+	IsEnabled(flag string, ctx context.Context) bool
+}
+
+// FeatureFlagProvider supplies flag definitions to a FeatureFlag service.
+// Implement this interface to plug in external flag systems.
+type FeatureFlagProvider interface {
+	// GetFlag returns the flag definition, or false if the flag is unknown
+	GetFlag(name string) (*FlagDefinition, bool)
+}
+
+// FlagDefinition describes a single feature flag
+type FlagDefinition struct {
+	// Enabled is the master switch. A disabled flag is always off.
+	Enabled bool `json:"enabled"`
+	// Percentage of subjects (0-100) that get the flag. 0 means no percentage
+	// rollout (flag is on for everyone matching the segments).
+	Percentage int `json:"percentage"`
+	// Attribute is the key used for rollouts (default: "user_id")
+	Attribute string `json:"attribute"`
+	// Segments restricts the flag to these attribute values (empty = all)
+	Segments []string `json:"segments"`
+}
@@ -0,0 +1,44 @@
+package serviceapi
+
+import (
+	"context"
+	"time"
+)
+
+// HealthChecker is implemented by services that can report their own health
+// (DB pools, caches, redis clients, ...). The health service discovers
+// registered services implementing it and checks them by service name.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthStatus is the status of a check or of the whole report
+type HealthStatus string
+
+const (
+	HEALTH_UP       HealthStatus = "up"
+	HEALTH_DEGRADED HealthStatus = "degraded" // a non-critical check is down
+	HEALTH_DOWN     HealthStatus = "down"     // a critical check is down
+)
+
+// HealthCheckResult is the outcome of a single check
+type HealthCheckResult struct {
+	Name      string        `json:"name"`
+	Status    HealthStatus  `json:"status"`
+	Critical  bool          `json:"critical"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// HealthReport aggregates all checks
+type HealthReport struct {
+	Status HealthStatus        `json:"status"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+// Health aggregates health checks of an application
+type Health interface {
+	// Check runs (or reuses, within their interval) all checks
+	Check(ctx context.Context) *HealthReport
+}
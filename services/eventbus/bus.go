@@ -2,30 +2,133 @@ package eventbus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/primadi/lokstra/common/logger"
 	"github.com/primadi/lokstra/serviceapi"
 )
 
+// ErrBusClosed is returned when publishing to a bus that was shut down
+var ErrBusClosed = errors.New("event bus is closed")
+
+// ErrorPolicy controls synchronous delivery when a handler fails
+type ErrorPolicy string
+
+const (
+	// StopOnError stops at the first failing handler and returns its error
+	StopOnError ErrorPolicy = "stop"
+	// ContinueOnError runs all handlers and returns the joined errors
+	ContinueOnError ErrorPolicy = "continue"
+)
+
+// Config configures delivery of a Bus
+type Config struct {
+	// Workers is the number of goroutines delivering async events
+	Workers int
+	// BufferSize is the async queue capacity; PublishAsync blocks when full
+	BufferSize int
+	// ErrorPolicy applies to synchronous Publish
+	ErrorPolicy ErrorPolicy
+	// DrainTimeout bounds how long Shutdown waits for queued events
+	DrainTimeout time.Duration
+	// OnError receives async handler errors, after retries (default: log)
+	OnError func(ctx context.Context, event serviceapi.Event, err error)
+	// MaxRetries is the number of times a failing async handler is retried
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled on each
+	// following retry (default 100ms)
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps the retry delay (default 10s)
+	MaxRetryBackoff time.Duration
+	// DeadLetters stores async deliveries still failing after the retries,
+	// for ReplayDeadLetters (optional, failures are only reported otherwise)
+	DeadLetters serviceapi.DeadLetterStore
+	// Metrics receives METRIC_RETRIES and METRIC_DEAD_LETTERS counters (optional)
+	Metrics serviceapi.Metrics
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Workers:         4,
+		BufferSize:      1024,
+		ErrorPolicy:     StopOnError,
+		DrainTimeout:    30 * time.Second,
+		RetryBackoff:    100 * time.Millisecond,
+		MaxRetryBackoff: 10 * time.Second,
+	}
+}
+
 // subscription represents a registered handler with its ID
 type subscription struct {
 	id      serviceapi.SubscriptionID
 	handler serviceapi.EventHandler
 }
 
+// asyncJob is a queued async delivery
+type asyncJob struct {
+	ctx   context.Context
+	event serviceapi.Event
+	subs  []subscription
+}
+
 // Bus is a simple in-memory event bus
 type Bus struct {
+	cfg       *Config
 	handlers  map[serviceapi.EventType][]subscription
 	mu        sync.RWMutex
 	nextSubID serviceapi.SubscriptionID
+
+	startOnce sync.Once
+	queue     chan asyncJob
+	workers   sync.WaitGroup
+	closed    bool
+
+	stop    chan struct{}  // closed by Shutdown, unblocks waiting publishers
+	sending sync.WaitGroup // PublishAsync calls that may still send to queue
+	abort   chan struct{}  // closed when DrainTimeout expires, ends retries
 }
 
-// NewBus creates a new event bus
+// NewBus creates a new event bus with default config
 func NewBus() *Bus {
+	return NewBusWithConfig(DefaultConfig())
+}
+
+// NewBusWithConfig creates a new event bus
+func NewBusWithConfig(cfg *Config) *Bus {
+	defConfig := DefaultConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = defConfig.Workers
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defConfig.BufferSize
+	}
+	if cfg.ErrorPolicy == "" {
+		cfg.ErrorPolicy = defConfig.ErrorPolicy
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = defConfig.DrainTimeout
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defConfig.RetryBackoff
+	}
+	if cfg.MaxRetryBackoff <= 0 {
+		cfg.MaxRetryBackoff = defConfig.MaxRetryBackoff
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(_ context.Context, event serviceapi.Event, err error) {
+			logger.LogError("eventbus: async handler for event %s failed: %v", event.Type, err)
+		}
+	}
+
 	return &Bus{
+		cfg:      cfg,
 		handlers: make(map[serviceapi.EventType][]subscription),
+		stop:     make(chan struct{}),
+		abort:    make(chan struct{}),
 	}
 }
 
@@ -48,43 +151,171 @@ func (b *Bus) Subscribe(eventType serviceapi.EventType, handler serviceapi.Event
 }
 
 // Publish publishes an event to all registered handlers
-// Executes handlers synchronously in order they were registered
+// Executes handlers synchronously in order they were registered.
+// Failures are handled according to Config.ErrorPolicy.
 func (b *Bus) Publish(ctx context.Context, event serviceapi.Event) error {
 	b.mu.RLock()
 	subs := b.handlers[event.Type]
+	closed := b.closed
 	b.mu.RUnlock()
 
+	if closed {
+		return ErrBusClosed
+	}
+
+	var errs []error
 	for i, sub := range subs {
-		if err := sub.handler(ctx, event); err != nil {
-			return fmt.Errorf("handler %d (id=%d) for event %s failed: %w", i, sub.id, event.Type, err)
+		if err := callHandler(ctx, sub, event); err != nil {
+			err = fmt.Errorf("handler %d (id=%d) for event %s failed: %w", i, sub.id, event.Type, err)
+			if b.cfg.ErrorPolicy == StopOnError {
+				return err
+			}
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// PublishAsync publishes an event asynchronously to all registered handlers
-// Each handler runs in its own goroutine, errors are logged but don't block
+// PublishAsync queues an event for delivery by the worker pool and returns
+// immediately (fire-and-forget). Blocks only when the queue is full.
+// Failing handlers are retried (Config.MaxRetries), then reported to
+// Config.OnError and stored in Config.DeadLetters.
 func (b *Bus) PublishAsync(ctx context.Context, event serviceapi.Event) {
+	b.startOnce.Do(b.startWorkers)
+
 	b.mu.RLock()
+	closed := b.closed
 	subs := b.handlers[event.Type]
+	if !closed && len(subs) > 0 {
+		// Registered under the lock, so Shutdown waits for this send
+		// before closing the queue
+		b.sending.Add(1)
+	}
 	b.mu.RUnlock()
 
-	var wg sync.WaitGroup
-	for i, sub := range subs {
-		wg.Add(1)
-		go func(idx int, s subscription) {
-			defer wg.Done()
-			if err := s.handler(ctx, event); err != nil {
-				// TODO: Use proper logger
-				fmt.Printf("async handler %d (id=%d) for event %s failed: %v\n", idx, s.id, event.Type, err)
+	if closed {
+		b.cfg.OnError(ctx, event, ErrBusClosed)
+		b.deadLetter(ctx, event, 0, ErrBusClosed, 0)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+	defer b.sending.Done()
+
+	// Detach from request cancellation: the event outlives the publisher.
+	// The lock is not held while waiting for queue space, so Subscribe and
+	// Shutdown are never blocked by a full queue.
+	job := asyncJob{ctx: context.WithoutCancel(ctx), event: event, subs: subs}
+	select {
+	case b.queue <- job:
+	case <-b.stop:
+		b.cfg.OnError(ctx, event, ErrBusClosed)
+		b.deadLetter(job.ctx, event, 0, ErrBusClosed, 0)
+	case <-ctx.Done():
+		b.cfg.OnError(ctx, event, ctx.Err())
+		b.deadLetter(job.ctx, event, 0, ctx.Err(), 0)
+	}
+}
+
+func (b *Bus) startWorkers() {
+	b.queue = make(chan asyncJob, b.cfg.BufferSize)
+	for range b.cfg.Workers {
+		b.workers.Add(1)
+		go func() {
+			defer b.workers.Done()
+			for job := range b.queue {
+				for _, sub := range job.subs {
+					b.deliverAsync(job.ctx, sub, job.event)
+				}
 			}
-		}(i, sub)
+		}()
 	}
+}
+
+// deliverAsync runs a handler with retries, dead-lettering the event if
+// it still fails. Retries left when the Shutdown drain times out are
+// skipped, dead-lettering the event.
+func (b *Bus) deliverAsync(ctx context.Context, sub subscription, event serviceapi.Event) {
+	// Retries must not fail because the publisher's context ended
+	ctx = context.WithoutCancel(ctx)
+
+	backoff := b.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := callHandler(ctx, sub, event)
+		if err == nil {
+			return
+		}
+		if attempt > b.cfg.MaxRetries {
+			b.cfg.OnError(ctx, event, err)
+			b.deadLetter(ctx, event, sub.id, err, attempt)
+			return
+		}
+		b.incCounter(METRIC_RETRIES, event)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-b.abort:
+			timer.Stop()
+			err = errors.Join(err, ErrBusClosed)
+			b.cfg.OnError(ctx, event, err)
+			b.deadLetter(ctx, event, sub.id, err, attempt)
+			return
+		}
+		backoff = min(backoff*2, b.cfg.MaxRetryBackoff)
+	}
+}
+
+// callHandler runs a handler, converting panics into errors
+func callHandler(ctx context.Context, sub subscription, event serviceapi.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler (id=%d) panicked: %v", sub.id, r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
+
+// Shutdown stops accepting events and waits (up to Config.DrainTimeout)
+// for queued async events to be delivered
+func (b *Bus) Shutdown() error {
+	b.startOnce.Do(b.startWorkers)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.stop)
+	b.mu.Unlock()
+
+	// Publishers blocked on a full queue give up on stop; close the queue
+	// once none can send anymore
+	b.sending.Wait()
+	close(b.queue)
+
+	done := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(b.cfg.DrainTimeout):
+		close(b.abort)
+		return fmt.Errorf("eventbus: %d event(s) not drained within %s", len(b.queue), b.cfg.DrainTimeout)
+	}
+}
 
-	// Optional: wait for all handlers to complete
-	// Remove this if you want fire-and-forget behavior
-	wg.Wait()
+// Pending returns the number of queued async events
+func (b *Bus) Pending() int {
+	b.startOnce.Do(b.startWorkers)
+	return len(b.queue)
 }
 
 // Unsubscribe removes a specific handler by its subscription ID
@@ -97,8 +328,8 @@ func (b *Bus) Unsubscribe(subID serviceapi.SubscriptionID) bool {
 	for eventType, subs := range b.handlers {
 		for i, sub := range subs {
 			if sub.id == subID {
-				// Remove this subscription by slicing it out
-				b.handlers[eventType] = append(subs[:i], subs[i+1:]...)
+				// Remove this subscription (copy, queued jobs may hold the old slice)
+				b.handlers[eventType] = append(subs[:i:i], subs[i+1:]...)
 
 				// Clean up empty handler lists
 				if len(b.handlers[eventType]) == 0 {
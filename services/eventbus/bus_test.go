@@ -0,0 +1,289 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/eventbus"
+)
+
+type OrderCreated struct {
+	OrderID string
+	Total   float64
+}
+
+type UserSignedUp struct{ Email string }
+
+func (UserSignedUp) EventType() serviceapi.EventType { return "user.signed_up" }
+
+func TestBus_SyncDelivery(t *testing.T) {
+	bus := eventbus.NewBus()
+
+	var got []string
+	eventbus.On(bus, func(_ context.Context, e OrderCreated) error {
+		got = append(got, "analytics:"+e.OrderID)
+		return nil
+	})
+	eventbus.On(bus, func(_ context.Context, e OrderCreated) error {
+		got = append(got, "mailer:"+e.OrderID)
+		return nil
+	})
+
+	if err := eventbus.Emit(context.Background(), bus, OrderCreated{OrderID: "o-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "analytics:o-1" || got[1] != "mailer:o-1" {
+		t.Errorf("Unexpected delivery order: %v", got)
+	}
+
+	if topic := eventbus.TopicOf[UserSignedUp](); topic != "user.signed_up" {
+		t.Errorf("Expected custom topic, got %q", topic)
+	}
+	if topic := eventbus.TopicOf[OrderCreated](); topic != "eventbus_test.OrderCreated" {
+		t.Errorf("Expected type-name topic, got %q", topic)
+	}
+}
+
+func TestBus_ErrorPolicy(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	for _, tc := range []struct {
+		policy eventbus.ErrorPolicy
+		calls  int32
+	}{
+		{policy: eventbus.StopOnError, calls: 1},
+		{policy: eventbus.ContinueOnError, calls: 2},
+	} {
+		bus := eventbus.NewBusWithConfig(&eventbus.Config{ErrorPolicy: tc.policy})
+		var calls atomic.Int32
+		for range 2 {
+			eventbus.On(bus, func(context.Context, OrderCreated) error {
+				calls.Add(1)
+				return errBoom
+			})
+		}
+
+		err := eventbus.Emit(context.Background(), bus, OrderCreated{})
+		if !errors.Is(err, errBoom) {
+			t.Errorf("%s: expected boom error, got %v", tc.policy, err)
+		}
+		if calls.Load() != tc.calls {
+			t.Errorf("%s: expected %d calls, got %d", tc.policy, tc.calls, calls.Load())
+		}
+	}
+}
+
+func TestBus_AsyncDeliveryAndErrors(t *testing.T) {
+	var mu sync.Mutex
+	var asyncErrs []error
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		Workers: 2,
+		OnError: func(_ context.Context, _ serviceapi.Event, err error) {
+			mu.Lock()
+			asyncErrs = append(asyncErrs, err)
+			mu.Unlock()
+		},
+	})
+
+	release := make(chan struct{})
+	var delivered atomic.Int32
+	eventbus.On(bus, func(_ context.Context, e OrderCreated) error {
+		<-release
+		delivered.Add(1)
+		if e.OrderID == "bad" {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+
+	// Publisher is not blocked by slow handlers, even with a cancelled request context
+	ctx, cancel := context.WithCancel(context.Background())
+	eventbus.EmitAsync(ctx, bus, OrderCreated{OrderID: "a"})
+	eventbus.EmitAsync(ctx, bus, OrderCreated{OrderID: "bad"})
+	cancel()
+	if delivered.Load() != 0 {
+		t.Fatal("Async publish should return before delivery")
+	}
+
+	close(release)
+	if err := bus.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if delivered.Load() != 2 {
+		t.Errorf("Expected 2 deliveries, got %d", delivered.Load())
+	}
+	if len(asyncErrs) != 1 {
+		t.Errorf("Expected 1 async error, got %v", asyncErrs)
+	}
+}
+
+func TestBus_ShutdownDrains(t *testing.T) {
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{Workers: 1, BufferSize: 100})
+
+	var delivered atomic.Int32
+	eventbus.On(bus, func(context.Context, OrderCreated) error {
+		time.Sleep(time.Millisecond)
+		delivered.Add(1)
+		return nil
+	})
+
+	for range 20 {
+		eventbus.EmitAsync(context.Background(), bus, OrderCreated{})
+	}
+	if err := bus.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if delivered.Load() != 20 {
+		t.Errorf("Expected all 20 queued events drained, got %d", delivered.Load())
+	}
+
+	if err := eventbus.Emit(context.Background(), bus, OrderCreated{}); !errors.Is(err, eventbus.ErrBusClosed) {
+		t.Errorf("Expected ErrBusClosed after shutdown, got %v", err)
+	}
+}
+
+func TestBus_ShutdownTimeout(t *testing.T) {
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{Workers: 1, DrainTimeout: 20 * time.Millisecond})
+
+	block := make(chan struct{})
+	defer close(block)
+	eventbus.On(bus, func(context.Context, OrderCreated) error {
+		<-block
+		return nil
+	})
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{})
+
+	if err := bus.Shutdown(); err == nil {
+		t.Error("Expected drain timeout error")
+	}
+}
+
+func TestBus_FullQueueDoesNotBlockShutdown(t *testing.T) {
+	var failed atomic.Int32
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		Workers:      1,
+		BufferSize:   1,
+		DrainTimeout: time.Second,
+		OnError: func(context.Context, serviceapi.Event, error) {
+			failed.Add(1)
+		},
+	})
+
+	release := make(chan struct{})
+	eventbus.On(bus, func(context.Context, OrderCreated) error {
+		<-release
+		return nil
+	})
+
+	// One event held by the worker, one queued, one publisher blocked
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{})
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{})
+	published := make(chan struct{})
+	go func() {
+		eventbus.EmitAsync(context.Background(), bus, OrderCreated{})
+		close(published)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Subscribe must not wait for the blocked publisher
+	subscribed := make(chan struct{})
+	go func() {
+		eventbus.On(bus, func(context.Context, UserSignedUp) error { return nil })
+		close(subscribed)
+	}()
+	select {
+	case <-subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe blocked by a publisher waiting on a full queue")
+	}
+
+	shutdown := make(chan error)
+	go func() { shutdown <- bus.Shutdown() }()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("blocked publisher not released by Shutdown")
+	}
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Fatal(err)
+	}
+	if failed.Load() != 1 {
+		t.Errorf("Expected the blocked event reported as failed, got %d", failed.Load())
+	}
+}
+
+func TestBus_DrainTimeoutStopsRetryBackoff(t *testing.T) {
+	reported := make(chan error, 1)
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		Workers:      1,
+		MaxRetries:   5,
+		RetryBackoff: time.Hour,
+		DrainTimeout: 50 * time.Millisecond,
+		OnError: func(_ context.Context, _ serviceapi.Event, err error) {
+			reported <- err
+		},
+	})
+
+	attempted := make(chan struct{}, 1)
+	eventbus.On(bus, func(context.Context, OrderCreated) error {
+		attempted <- struct{}{}
+		return errors.New("downstream unavailable")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	eventbus.EmitAsync(ctx, bus, OrderCreated{})
+	cancel()
+	<-attempted
+
+	start := time.Now()
+	if err := bus.Shutdown(); err == nil {
+		t.Error("Expected drain timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Shutdown waited %v for the retry backoff", elapsed)
+	}
+	select {
+	case err := <-reported:
+		if !errors.Is(err, eventbus.ErrBusClosed) {
+			t.Errorf("Expected the skipped retry reported with ErrBusClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("worker still waiting for the retry backoff after the drain timeout")
+	}
+}
+
+func TestBus_RetryBackoffCapped(t *testing.T) {
+	var mu sync.Mutex
+	var attempts []time.Time
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		Workers:         1,
+		MaxRetries:      4,
+		RetryBackoff:    5 * time.Millisecond,
+		MaxRetryBackoff: 10 * time.Millisecond,
+		OnError:         func(context.Context, serviceapi.Event, error) {},
+	})
+	eventbus.On(bus, func(context.Context, OrderCreated) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, time.Now())
+		return errors.New("still failing")
+	})
+
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{})
+	if err := bus.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 5 {
+		t.Fatalf("Expected 5 attempts, got %d", len(attempts))
+	}
+	// Uncapped, the last delay would be 40ms
+	if last := attempts[4].Sub(attempts[3]); last > 30*time.Millisecond {
+		t.Errorf("Expected the backoff capped at 10ms, last delay %v", last)
+	}
+}
@@ -0,0 +1,163 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// METRIC_DEAD_LETTERS counts async deliveries moved to the dead-letter store
+const METRIC_DEAD_LETTERS = "eventbus_dead_letters_total"
+
+// METRIC_RETRIES counts retried async deliveries
+const METRIC_RETRIES = "eventbus_retries_total"
+
+// ErrSubscriptionGone is returned when replaying a dead letter whose
+// handler was unsubscribed
+var ErrSubscriptionGone = errors.New("subscription no longer exists")
+
+// MemoryDeadLetterStore keeps dead letters in memory, up to a maximum
+// (oldest are dropped first). Use a persistent store to keep them across
+// restarts.
+type MemoryDeadLetterStore struct {
+	max     int
+	mu      sync.Mutex
+	letters []serviceapi.DeadLetter
+}
+
+// NewMemoryDeadLetterStore creates a store keeping at most max dead
+// letters (max <= 0: 10000)
+func NewMemoryDeadLetterStore(max int) *MemoryDeadLetterStore {
+	if max <= 0 {
+		max = 10000
+	}
+	return &MemoryDeadLetterStore{max: max}
+}
+
+func (s *MemoryDeadLetterStore) Add(_ context.Context, dl serviceapi.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.letters) >= s.max {
+		s.letters = slices.Delete(s.letters, 0, len(s.letters)-s.max+1)
+	}
+	s.letters = append(s.letters, dl)
+	return nil
+}
+
+func (s *MemoryDeadLetterStore) List(_ context.Context, limit int) ([]serviceapi.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit <= 0 || limit > len(s.letters) {
+		limit = len(s.letters)
+	}
+	return slices.Clone(s.letters[:limit]), nil
+}
+
+func (s *MemoryDeadLetterStore) Remove(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = slices.DeleteFunc(s.letters, func(dl serviceapi.DeadLetter) bool {
+		return dl.ID == id
+	})
+	return nil
+}
+
+// Len returns the number of stored dead letters
+func (s *MemoryDeadLetterStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.letters)
+}
+
+var _ serviceapi.DeadLetterStore = (*MemoryDeadLetterStore)(nil)
+
+// deadLetter stores a failed async delivery, if a store is configured
+func (b *Bus) deadLetter(ctx context.Context, event serviceapi.Event, subID serviceapi.SubscriptionID,
+	err error, attempts int) {
+	if b.cfg.DeadLetters == nil {
+		return
+	}
+
+	dl := serviceapi.DeadLetter{
+		ID:           uuid.NewString(),
+		Event:        event,
+		Subscription: subID,
+		Error:        err.Error(),
+		Attempts:     attempts,
+		FailedAt:     time.Now(),
+	}
+	if err := b.cfg.DeadLetters.Add(ctx, dl); err != nil {
+		logger.LogError("eventbus: cannot store dead letter for event %s: %v", event.Type, err)
+		return
+	}
+	b.incCounter(METRIC_DEAD_LETTERS, event)
+}
+
+func (b *Bus) incCounter(name string, event serviceapi.Event) {
+	if b.cfg.Metrics != nil {
+		b.cfg.Metrics.IncCounter(name, serviceapi.Labels{"event": string(event.Type)})
+	}
+}
+
+// ReplayDeadLetters redelivers up to limit dead letters (limit <= 0: all),
+// oldest first, synchronously. Each goes to the handler that failed, or to
+// all current handlers if it was never delivered. Delivered letters are
+// removed from the store; failing ones are kept and their errors returned.
+//
+// Example:
+//
+//	// after fixing the analytics backend
+//	n, err := bus.ReplayDeadLetters(ctx, 0)
+func (b *Bus) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	if b.cfg.DeadLetters == nil {
+		return 0, nil
+	}
+
+	letters, err := b.cfg.DeadLetters.List(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	var errs []error
+	for _, dl := range letters {
+		if err := b.replay(ctx, dl); err != nil {
+			errs = append(errs, fmt.Errorf("dead letter %s (event %s): %w", dl.ID, dl.Event.Type, err))
+			continue
+		}
+		if err := b.cfg.DeadLetters.Remove(ctx, dl.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		replayed++
+	}
+	return replayed, errors.Join(errs...)
+}
+
+func (b *Bus) replay(ctx context.Context, dl serviceapi.DeadLetter) error {
+	b.mu.RLock()
+	subs := b.handlers[dl.Event.Type]
+	b.mu.RUnlock()
+
+	if dl.Subscription == 0 {
+		var errs []error
+		for _, sub := range subs {
+			errs = append(errs, callHandler(ctx, sub, dl.Event))
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, sub := range subs {
+		if sub.id == dl.Subscription {
+			return callHandler(ctx, sub, dl.Event)
+		}
+	}
+	return ErrSubscriptionGone
+}
@@ -0,0 +1,168 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/eventbus"
+)
+
+type countingMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func (m *countingMetrics) IncCounter(name string, _ serviceapi.Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counters == nil {
+		m.counters = make(map[string]int)
+	}
+	m.counters[name]++
+}
+func (m *countingMetrics) ObserveHistogram(string, float64, serviceapi.Labels) {}
+func (m *countingMetrics) SetGauge(string, float64, serviceapi.Labels)         {}
+
+func (m *countingMetrics) count(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[name]
+}
+
+func TestBus_DeadLetterAndReplay(t *testing.T) {
+	store := eventbus.NewMemoryDeadLetterStore(0)
+	metrics := &countingMetrics{}
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+		DeadLetters:  store,
+		Metrics:      metrics,
+		OnError:      func(context.Context, serviceapi.Event, error) {},
+	})
+
+	// Analytics backend is down
+	var down atomic.Bool
+	down.Store(true)
+	var attempts atomic.Int32
+	var tracked []string
+	eventbus.On(bus, func(_ context.Context, e OrderCreated) error {
+		attempts.Add(1)
+		if down.Load() {
+			return errors.New("analytics unavailable")
+		}
+		tracked = append(tracked, e.OrderID)
+		return nil
+	})
+	// A healthy handler of the same event is not dead-lettered
+	eventbus.On(bus, func(context.Context, OrderCreated) error { return nil })
+
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{OrderID: "o-1"})
+	if err := bus.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 1 attempt + 2 retries, got %d", got)
+	}
+	letters, _ := store.List(context.Background(), 0)
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	dl := letters[0]
+	if dl.Attempts != 3 || dl.Error != "analytics unavailable" || dl.Event.Payload.(OrderCreated).OrderID != "o-1" {
+		t.Errorf("unexpected dead letter %+v", dl)
+	}
+	if metrics.count(eventbus.METRIC_DEAD_LETTERS) != 1 || metrics.count(eventbus.METRIC_RETRIES) != 2 {
+		t.Errorf("unexpected metrics %v", metrics.counters)
+	}
+
+	// Replay while still down keeps the letter
+	if n, err := bus.ReplayDeadLetters(context.Background(), 0); n != 0 || err == nil {
+		t.Errorf("replay while down = %d, %v", n, err)
+	}
+	if store.Len() != 1 {
+		t.Fatal("failed replay removed the dead letter")
+	}
+
+	// Backend recovered: replay delivers to the failed handler only
+	down.Store(false)
+	n, err := bus.ReplayDeadLetters(context.Background(), 0)
+	if n != 1 || err != nil {
+		t.Fatalf("replay = %d, %v", n, err)
+	}
+	if len(tracked) != 1 || tracked[0] != "o-1" {
+		t.Errorf("tracked %v", tracked)
+	}
+	if store.Len() != 0 {
+		t.Error("replayed dead letter was not removed")
+	}
+}
+
+func TestBus_DeadLetterWhenClosed(t *testing.T) {
+	store := eventbus.NewMemoryDeadLetterStore(0)
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		DeadLetters: store,
+		OnError:     func(context.Context, serviceapi.Event, error) {},
+	})
+	var delivered atomic.Int32
+	eventbus.On(bus, func(context.Context, OrderCreated) error {
+		delivered.Add(1)
+		return nil
+	})
+	_ = bus.Shutdown()
+
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{OrderID: "late"})
+
+	letters, _ := store.List(context.Background(), 0)
+	if len(letters) != 1 || letters[0].Subscription != 0 {
+		t.Fatalf("expected an undelivered dead letter, got %+v", letters)
+	}
+
+	// Replayed to all current handlers
+	if n, err := bus.ReplayDeadLetters(context.Background(), 0); n != 1 || err != nil {
+		t.Fatalf("replay = %d, %v", n, err)
+	}
+	if delivered.Load() != 1 {
+		t.Errorf("expected 1 delivery, got %d", delivered.Load())
+	}
+}
+
+func TestBus_ReplayUnsubscribed(t *testing.T) {
+	store := eventbus.NewMemoryDeadLetterStore(0)
+	bus := eventbus.NewBusWithConfig(&eventbus.Config{
+		DeadLetters: store,
+		OnError:     func(context.Context, serviceapi.Event, error) {},
+	})
+	id := eventbus.On(bus, func(context.Context, OrderCreated) error { return errors.New("fail") })
+	eventbus.EmitAsync(context.Background(), bus, OrderCreated{OrderID: "x"})
+	_ = bus.Shutdown()
+
+	bus.Unsubscribe(id)
+	if _, err := bus.ReplayDeadLetters(context.Background(), 0); !errors.Is(err, eventbus.ErrSubscriptionGone) {
+		t.Errorf("expected ErrSubscriptionGone, got %v", err)
+	}
+	if store.Len() != 1 {
+		t.Error("dead letter of a removed handler must be kept")
+	}
+}
+
+func TestMemoryDeadLetterStore_Max(t *testing.T) {
+	store := eventbus.NewMemoryDeadLetterStore(2)
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		_ = store.Add(ctx, serviceapi.DeadLetter{ID: id})
+	}
+
+	letters, _ := store.List(ctx, 0)
+	if len(letters) != 2 || letters[0].ID != "b" || letters[1].ID != "c" {
+		t.Errorf("expected oldest dropped, got %+v", letters)
+	}
+	if limited, _ := store.List(ctx, 1); len(limited) != 1 || limited[0].ID != "b" {
+		t.Errorf("List(1) = %+v", limited)
+	}
+}
@@ -1,18 +1,49 @@
 package eventbus
 
 import (
+	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/lokstra_registry"
 	"github.com/primadi/lokstra/serviceapi"
 )
 
 const SERVICE_TYPE = "map-event-bus"
 
+const PARAMS_WORKERS = "workers"
+const PARAMS_BUFFER_SIZE = "buffer_size"
+const PARAMS_ERROR_POLICY = "error_policy"
+const PARAMS_DRAIN_TIMEOUT = "drain_timeout"
+const PARAMS_MAX_RETRIES = "max_retries"
+const PARAMS_RETRY_BACKOFF = "retry_backoff"
+const PARAMS_MAX_RETRY_BACKOFF = "max_retry_backoff"
+const PARAMS_DEAD_LETTER_SERVICE = "dead_letter_service"
+const PARAMS_METRICS_SERVICE = "metrics_service"
+
 func Service() serviceapi.EventBus {
 	return NewBus()
 }
 
-func ServiceFactory(_ map[string]any) any {
-	return NewBus()
+func ServiceFactory(params map[string]any) any {
+	defConfig := DefaultConfig()
+	if params == nil {
+		return NewBusWithConfig(defConfig)
+	}
+
+	cfg := &Config{
+		Workers:         utils.GetValueFromMap(params, PARAMS_WORKERS, defConfig.Workers),
+		BufferSize:      utils.GetValueFromMap(params, PARAMS_BUFFER_SIZE, defConfig.BufferSize),
+		ErrorPolicy:     ErrorPolicy(utils.GetValueFromMap(params, PARAMS_ERROR_POLICY, string(defConfig.ErrorPolicy))),
+		DrainTimeout:    utils.GetValueFromMap(params, PARAMS_DRAIN_TIMEOUT, defConfig.DrainTimeout),
+		MaxRetries:      utils.GetValueFromMap(params, PARAMS_MAX_RETRIES, defConfig.MaxRetries),
+		RetryBackoff:    utils.GetValueFromMap(params, PARAMS_RETRY_BACKOFF, defConfig.RetryBackoff),
+		MaxRetryBackoff: utils.GetValueFromMap(params, PARAMS_MAX_RETRY_BACKOFF, defConfig.MaxRetryBackoff),
+	}
+	if name := utils.GetValueFromMap(params, PARAMS_DEAD_LETTER_SERVICE, ""); name != "" {
+		cfg.DeadLetters = lokstra_registry.GetService[serviceapi.DeadLetterStore](name)
+	}
+	if name := utils.GetValueFromMap(params, PARAMS_METRICS_SERVICE, ""); name != "" {
+		cfg.Metrics = lokstra_registry.GetService[serviceapi.Metrics](name)
+	}
+	return NewBusWithConfig(cfg)
 }
 
 func Register() {
@@ -0,0 +1,56 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// Named can be implemented by event payload types to choose their topic.
+// Without it, the topic is the Go type name (e.g. "order.OrderCreated").
+type Named interface {
+	EventType() serviceapi.EventType
+}
+
+// TopicOf returns the topic used for payloads of type T
+func TopicOf[T any]() serviceapi.EventType {
+	var zero T
+	if n, ok := any(zero).(Named); ok {
+		return n.EventType()
+	}
+	return serviceapi.EventType(reflect.TypeFor[T]().String())
+}
+
+// On subscribes a typed handler to events of type T.
+//
+// Example:
+//
+//	// analytics
+//	eventbus.On(bus, func(ctx context.Context, e order.OrderCreated) error {
+//	    return track(ctx, "order_created", e.OrderID)
+//	})
+//
+//	// order service
+//	eventbus.Emit(ctx, bus, order.OrderCreated{OrderID: id})
+func On[T any](bus serviceapi.EventBus, handler func(ctx context.Context, event T) error) serviceapi.SubscriptionID {
+	return bus.Subscribe(TopicOf[T](), func(ctx context.Context, event serviceapi.Event) error {
+		payload, ok := event.Payload.(T)
+		if !ok {
+			return fmt.Errorf("eventbus: payload for %s is %T, expected %s",
+				event.Type, event.Payload, reflect.TypeFor[T]())
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// Emit publishes a typed event synchronously
+func Emit[T any](ctx context.Context, bus serviceapi.EventBus, event T) error {
+	return bus.Publish(ctx, serviceapi.Event{Type: TopicOf[T](), Payload: event})
+}
+
+// EmitAsync publishes a typed event asynchronously (fire-and-forget)
+func EmitAsync[T any](ctx context.Context, bus serviceapi.EventBus, event T) {
+	bus.PublishAsync(ctx, serviceapi.Event{Type: TopicOf[T](), Payload: event})
+}
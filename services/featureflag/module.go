@@ -0,0 +1,210 @@
+package featureflag
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	"github.com/primadi/lokstra/common/logger"
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+const SERVICE_TYPE = "featureflag"
+
+const DEFAULT_ATTRIBUTE = "user_id"
+
+type attributesKey struct{}
+
+// WithAttributes returns a context carrying rollout attributes for IsEnabled
+func WithAttributes(ctx context.Context, attrs serviceapi.FlagAttributes) context.Context {
+	return context.WithValue(ctx, attributesKey{}, attrs)
+}
+
+// FeatureFlags evaluates flags from a pluggable provider
+type FeatureFlags struct {
+	provider serviceapi.FeatureFlagProvider
+}
+
+var _ serviceapi.FeatureFlag = (*FeatureFlags)(nil)
+
+// IsEnabled implements [serviceapi.FeatureFlag].
+func (s *FeatureFlags) IsEnabled(flag string, ctx context.Context) bool {
+	def, ok := s.provider.GetFlag(flag)
+	if !ok || def == nil || !def.Enabled {
+		return false
+	}
+
+	// Simple on/off flag
+	if len(def.Segments) == 0 && def.Percentage <= 0 {
+		return true
+	}
+
+	attr := def.Attribute
+	if attr == "" {
+		attr = DEFAULT_ATTRIBUTE
+	}
+	value, found := lookupAttribute(ctx, attr)
+	if !found {
+		return false
+	}
+
+	if len(def.Segments) > 0 && !slices.Contains(def.Segments, value) {
+		return false
+	}
+	if def.Percentage <= 0 || def.Percentage >= 100 {
+		return true
+	}
+	return bucket(flag, value) < uint32(def.Percentage)
+}
+
+// IsEnabledFor evaluates the flag using explicit attributes
+func (s *FeatureFlags) IsEnabledFor(flag string, attrs serviceapi.FlagAttributes) bool {
+	return s.IsEnabled(flag, WithAttributes(context.Background(), attrs))
+}
+
+// Provider returns the underlying flag provider
+func (s *FeatureFlags) Provider() serviceapi.FeatureFlagProvider {
+	return s.provider
+}
+
+// Middleware gates the rest of the chain behind a flag.
+// Requests for which the flag is disabled get 404 Not Found.
+func (s *FeatureFlags) Middleware(flag string) request.HandlerFunc {
+	return func(c *request.Context) error {
+		if !s.IsEnabled(flag, c) {
+			return c.Api.NotFound("Not found")
+		}
+		return c.Next()
+	}
+}
+
+// WatchSyncConfig hot-reloads flags from a SyncConfig key.
+// The key value must be a map in the same format accepted by ParseFlags.
+// Only works when the provider is a *StaticProvider.
+// Returns the subscription ID (empty if the provider is not reloadable).
+func (s *FeatureFlags) WatchSyncConfig(sc serviceapi.SyncConfig, key string) string {
+	sp, ok := s.provider.(*StaticProvider)
+	if !ok {
+		logger.LogWarning("featureflag: provider %T does not support hot reload", s.provider)
+		return ""
+	}
+
+	if val, err := sc.Get(context.Background(), key); err == nil {
+		reload(sp, key, val)
+	}
+
+	return sc.Subscribe(func(changedKey string, value any) {
+		if changedKey == key {
+			reload(sp, key, value)
+		}
+	})
+}
+
+func reload(sp *StaticProvider, key string, value any) {
+	if value == nil {
+		sp.Update(nil)
+		return
+	}
+	raw, ok := value.(map[string]any)
+	if !ok {
+		logger.LogWarning("featureflag: config %q must be a map, got %T", key, value)
+		return
+	}
+	flags, err := ParseFlags(raw)
+	if err != nil {
+		logger.LogWarning("featureflag: ignoring invalid config %q: %v", key, err)
+		return
+	}
+	sp.Update(flags)
+	logger.LogDebug("🚩 Reloaded %d feature flags from '%s'", len(flags), key)
+}
+
+// lookupAttribute resolves a rollout attribute from explicit attributes,
+// the authenticated principal, or request context values (in that order).
+// Headers and query parameters are never used: clients must not be able
+// to pick their own rollout bucket or segment.
+func lookupAttribute(ctx context.Context, name string) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	if attrs, ok := ctx.Value(attributesKey{}).(serviceapi.FlagAttributes); ok {
+		if v, ok := attrs[name]; ok && v != "" {
+			return v, true
+		}
+	}
+
+	if p := request.PrincipalFromContext(ctx); p != nil {
+		if name == DEFAULT_ATTRIBUTE && p.ID != "" {
+			return p.ID, true
+		}
+		if v, ok := attributeString(p.Claims[name]); ok {
+			return v, true
+		}
+	}
+
+	if c, ok := ctx.(*request.Context); ok {
+		return attributeString(c.Get(name))
+	}
+	return "", false
+}
+
+// attributeString converts a context or claim value to an attribute
+func attributeString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, v != ""
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		// JSON numbers (e.g. JWT claims)
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// bucket maps flag+value deterministically into [0, 100)
+func bucket(flag, value string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte{':'})
+	h.Write([]byte(value))
+	return h.Sum32() % 100
+}
+
+// Service creates a feature flag service backed by the given provider
+func Service(provider serviceapi.FeatureFlagProvider) *FeatureFlags {
+	if provider == nil {
+		provider = NewStaticProvider(nil)
+	}
+	return &FeatureFlags{provider: provider}
+}
+
+// ServiceFactory creates a static-config feature flag service.
+//
+// Params:
+//   - flags: map of flag definitions (see ParseFlags)
+//   - sync_config: optional SyncConfig service name for hot reload
+//   - sync_key: SyncConfig key holding the flags (default: "feature_flags")
+func ServiceFactory(params map[string]any) any {
+	flags, err := ParseFlags(utils.GetValueFromMap(params, "flags", map[string]any{}))
+	if err != nil {
+		panic("featureflag: " + err.Error())
+	}
+	svc := Service(NewStaticProvider(flags))
+
+	if scName := utils.GetValueFromMap(params, "sync_config", ""); scName != "" {
+		sc := lokstra_registry.GetService[serviceapi.SyncConfig](scName)
+		svc.WatchSyncConfig(sc, utils.GetValueFromMap(params, "sync_key", "feature_flags"))
+	}
+	return svc
+}
+
+func Register() {
+	lokstra_registry.RegisterServiceType(SERVICE_TYPE, ServiceFactory)
+}
@@ -0,0 +1,147 @@
+package featureflag_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/featureflag"
+)
+
+func newService(t *testing.T, raw map[string]any) *featureflag.FeatureFlags {
+	t.Helper()
+	flags, err := featureflag.ParseFlags(raw)
+	if err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	return featureflag.Service(featureflag.NewStaticProvider(flags))
+}
+
+func TestIsEnabled_Boolean(t *testing.T) {
+	svc := newService(t, map[string]any{
+		"on":  true,
+		"off": false,
+	})
+	ctx := context.Background()
+
+	if !svc.IsEnabled("on", ctx) {
+		t.Error("expected 'on' to be enabled")
+	}
+	if svc.IsEnabled("off", ctx) {
+		t.Error("expected 'off' to be disabled")
+	}
+	if svc.IsEnabled("unknown", ctx) {
+		t.Error("expected unknown flag to be disabled")
+	}
+}
+
+func TestIsEnabled_PercentageDeterministic(t *testing.T) {
+	svc := newService(t, map[string]any{
+		"beta": map[string]any{"percentage": 30, "attribute": "user_id"},
+	})
+
+	enabled := 0
+	for i := range 1000 {
+		attrs := serviceapi.FlagAttributes{"user_id": fmt.Sprintf("user-%d", i)}
+		first := svc.IsEnabledFor("beta", attrs)
+		for range 5 {
+			if svc.IsEnabledFor("beta", attrs) != first {
+				t.Fatalf("non-deterministic result for user-%d", i)
+			}
+		}
+		if first {
+			enabled++
+		}
+	}
+
+	// Expect roughly 30% (allow generous tolerance)
+	if enabled < 220 || enabled > 380 {
+		t.Errorf("expected ~300 of 1000 users enabled, got %d", enabled)
+	}
+
+	// Missing attribute never matches a percentage rollout
+	if svc.IsEnabled("beta", context.Background()) {
+		t.Error("expected rollout without attribute to be disabled")
+	}
+}
+
+func TestIsEnabled_Segments(t *testing.T) {
+	svc := newService(t, map[string]any{
+		"eu": map[string]any{"attribute": "region", "segments": []any{"eu-west", "eu-central"}},
+	})
+
+	if !svc.IsEnabledFor("eu", serviceapi.FlagAttributes{"region": "eu-west"}) {
+		t.Error("expected eu-west to be enabled")
+	}
+	if svc.IsEnabledFor("eu", serviceapi.FlagAttributes{"region": "us-east"}) {
+		t.Error("expected us-east to be disabled")
+	}
+}
+
+func TestIsEnabled_RequestContext(t *testing.T) {
+	svc := newService(t, map[string]any{
+		"tenant-feature": map[string]any{"attribute": "tenant", "segments": []any{"acme"}},
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	ctx := request.NewContext(httptest.NewRecorder(), req, nil)
+	ctx.Set("tenant", "acme")
+
+	if !svc.IsEnabled("tenant-feature", ctx) {
+		t.Error("expected flag enabled for tenant from context value")
+	}
+}
+
+func TestIsEnabled_Principal(t *testing.T) {
+	svc := newService(t, map[string]any{
+		"staff": map[string]any{"segments": []any{"u-1"}},
+		"eu":    map[string]any{"attribute": "region", "segments": []any{"eu"}},
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	ctx := request.NewContext(httptest.NewRecorder(), req, nil)
+	ctx.SetPrincipal(&request.Principal{ID: "u-1", Claims: map[string]any{"region": "eu"}})
+
+	if !svc.IsEnabled("staff", ctx) {
+		t.Error("expected flag enabled for principal ID")
+	}
+	if !svc.IsEnabled("eu", ctx) {
+		t.Error("expected flag enabled for principal claim")
+	}
+}
+
+func TestIsEnabled_IgnoresClientInput(t *testing.T) {
+	svc := newService(t, map[string]any{
+		"beta": map[string]any{"segments": []any{"vip"}},
+	})
+
+	req := httptest.NewRequest("GET", "/?user_id=vip", nil)
+	req.Header.Set("user_id", "vip")
+	ctx := request.NewContext(httptest.NewRecorder(), req, nil)
+
+	if svc.IsEnabled("beta", ctx) {
+		t.Error("expected query/header not to select a segment")
+	}
+
+	ctx.SetPrincipal(&request.Principal{ID: "regular"})
+	if svc.IsEnabled("beta", ctx) {
+		t.Error("expected query/header not to override the principal")
+	}
+}
+
+func TestStaticProvider_Update(t *testing.T) {
+	provider := featureflag.NewStaticProvider(nil)
+	svc := featureflag.Service(provider)
+
+	if svc.IsEnabled("late", context.Background()) {
+		t.Fatal("expected flag disabled before update")
+	}
+
+	provider.Update(map[string]*serviceapi.FlagDefinition{"late": {Enabled: true}})
+	if !svc.IsEnabled("late", context.Background()) {
+		t.Error("expected flag enabled after update")
+	}
+}
@@ -0,0 +1,107 @@
+package featureflag
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/primadi/lokstra/common/cast"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// StaticProvider serves flags from an in-memory map.
+// Flags can be replaced at runtime with Update (used for hot reload).
+type StaticProvider struct {
+	mu    sync.RWMutex
+	flags map[string]*serviceapi.FlagDefinition
+}
+
+var _ serviceapi.FeatureFlagProvider = (*StaticProvider)(nil)
+
+// NewStaticProvider creates a provider from the given flag definitions
+func NewStaticProvider(flags map[string]*serviceapi.FlagDefinition) *StaticProvider {
+	p := &StaticProvider{}
+	p.Update(flags)
+	return p
+}
+
+// GetFlag implements [serviceapi.FeatureFlagProvider].
+func (p *StaticProvider) GetFlag(name string) (*serviceapi.FlagDefinition, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	f, ok := p.flags[name]
+	return f, ok
+}
+
+// Update replaces all flag definitions atomically
+func (p *StaticProvider) Update(flags map[string]*serviceapi.FlagDefinition) {
+	cloned := make(map[string]*serviceapi.FlagDefinition, len(flags))
+	for name, f := range flags {
+		if f != nil {
+			cloned[name] = f
+		}
+	}
+
+	p.mu.Lock()
+	p.flags = cloned
+	p.mu.Unlock()
+}
+
+// Set adds or replaces a single flag definition
+func (p *StaticProvider) Set(name string, flag *serviceapi.FlagDefinition) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.flags == nil {
+		p.flags = make(map[string]*serviceapi.FlagDefinition)
+	}
+	p.flags[name] = flag
+}
+
+// ParseFlags converts a config map into flag definitions.
+// Each value is either a bool (simple on/off flag) or a map with
+// enabled, percentage, attribute and segments keys.
+//
+// Example (YAML):
+//
+//	flags:
+//	  new-dashboard: true
+//	  beta-search:
+//	    percentage: 20
+//	    attribute: user_id
+//	  eu-checkout:
+//	    attribute: region
+//	    segments: [eu-west, eu-central]
+func ParseFlags(raw map[string]any) (map[string]*serviceapi.FlagDefinition, error) {
+	flags := make(map[string]*serviceapi.FlagDefinition, len(raw))
+	for name, val := range raw {
+		f, err := parseFlag(val)
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %w", name, err)
+		}
+		flags[name] = f
+	}
+	return flags, nil
+}
+
+func parseFlag(val any) (*serviceapi.FlagDefinition, error) {
+	switch v := val.(type) {
+	case bool:
+		return &serviceapi.FlagDefinition{Enabled: v}, nil
+	case *serviceapi.FlagDefinition:
+		return v, nil
+	case serviceapi.FlagDefinition:
+		return &v, nil
+	case map[string]any:
+		f := &serviceapi.FlagDefinition{Enabled: true}
+		if err := cast.ToStruct(v, f, false); err != nil {
+			return nil, err
+		}
+		if f.Percentage < 0 || f.Percentage > 100 {
+			return nil, fmt.Errorf("percentage must be between 0 and 100, got %d", f.Percentage)
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("unsupported flag value type %T", val)
+	}
+}
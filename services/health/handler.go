@@ -0,0 +1,59 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+// Mode selects what a health endpoint reports
+type Mode string
+
+const (
+	// MODE_OVERALL runs all checks and responds with the full report
+	MODE_OVERALL Mode = "overall"
+	// MODE_LIVENESS reports whether the process is alive, without running
+	// checks, so a failing dependency never gets the process restarted
+	MODE_LIVENESS Mode = "liveness"
+	// MODE_READINESS runs all checks and responds with the status only,
+	// for load balancer probes that should not see check details
+	MODE_READINESS Mode = "readiness"
+)
+
+// HTTPStatusFor maps a report to its HTTP status: 200 when up or degraded
+// (the application still serves), 503 Service Unavailable when down
+func HTTPStatusFor(report *serviceapi.HealthReport) int {
+	if report == nil || report.Status == serviceapi.HEALTH_DOWN {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Handler serves the health of svc for mode. It panics on an unknown mode.
+//
+//	r.GET("/health", health.Handler(svc, health.MODE_OVERALL))
+//	r.GET("/livez", health.Handler(svc, health.MODE_LIVENESS))
+//	r.GET("/readyz", health.Handler(svc, health.MODE_READINESS))
+func Handler(svc serviceapi.Health, mode Mode) request.HandlerFunc {
+	switch mode {
+	case MODE_LIVENESS:
+		return func(c *request.Context) error {
+			return c.Resp.WithStatus(http.StatusOK).
+				Json(map[string]any{"status": serviceapi.HEALTH_UP})
+		}
+	case MODE_READINESS:
+		return func(c *request.Context) error {
+			report := svc.Check(c)
+			return c.Resp.WithStatus(HTTPStatusFor(report)).
+				Json(map[string]any{"status": report.Status})
+		}
+	case MODE_OVERALL, "":
+		return func(c *request.Context) error {
+			report := svc.Check(c)
+			return c.Resp.WithStatus(HTTPStatusFor(report)).Json(report)
+		}
+	}
+	panic(fmt.Sprintf("health: unknown handler mode %q", mode))
+}
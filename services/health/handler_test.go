@@ -0,0 +1,92 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/health"
+)
+
+func TestHTTPStatusFor(t *testing.T) {
+	tests := map[serviceapi.HealthStatus]int{
+		serviceapi.HEALTH_UP:       http.StatusOK,
+		serviceapi.HEALTH_DEGRADED: http.StatusOK,
+		serviceapi.HEALTH_DOWN:     http.StatusServiceUnavailable,
+	}
+	for status, want := range tests {
+		if got := health.HTTPStatusFor(&serviceapi.HealthReport{Status: status}); got != want {
+			t.Errorf("HTTPStatusFor(%s) = %d, want %d", status, got, want)
+		}
+	}
+}
+
+// healthWith returns a health service whose report has the given status
+func healthWith(status serviceapi.HealthStatus) *health.Health {
+	svc := health.Service(&health.Config{})
+	var err error
+	if status != serviceapi.HEALTH_UP {
+		err = errors.New("connection refused")
+	}
+	svc.AddCheck("db", func(ctx context.Context) error { return err },
+		health.CheckOptions{Critical: status == serviceapi.HEALTH_DOWN})
+	return svc
+}
+
+func TestHandler_Modes(t *testing.T) {
+	tests := []struct {
+		mode   health.Mode
+		health serviceapi.HealthStatus
+		status int
+		checks bool
+	}{
+		{health.MODE_OVERALL, serviceapi.HEALTH_UP, http.StatusOK, true},
+		{health.MODE_OVERALL, serviceapi.HEALTH_DEGRADED, http.StatusOK, true},
+		{health.MODE_OVERALL, serviceapi.HEALTH_DOWN, http.StatusServiceUnavailable, true},
+		{health.MODE_READINESS, serviceapi.HEALTH_UP, http.StatusOK, false},
+		{health.MODE_READINESS, serviceapi.HEALTH_DEGRADED, http.StatusOK, false},
+		{health.MODE_READINESS, serviceapi.HEALTH_DOWN, http.StatusServiceUnavailable, false},
+		{health.MODE_LIVENESS, serviceapi.HEALTH_UP, http.StatusOK, false},
+		{health.MODE_LIVENESS, serviceapi.HEALTH_DEGRADED, http.StatusOK, false},
+		{health.MODE_LIVENESS, serviceapi.HEALTH_DOWN, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+"/"+string(tt.health), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			health.Handler(healthWith(tt.health), tt.mode).
+				ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid body %q: %v", w.Body.String(), err)
+			}
+			if _, ok := body["checks"]; ok != tt.checks {
+				t.Errorf("checks in body = %v, want %v (body %s)", ok, tt.checks, w.Body.String())
+			}
+			want := string(tt.health)
+			if tt.mode == health.MODE_LIVENESS {
+				want = string(serviceapi.HEALTH_UP)
+			}
+			if body["status"] != want {
+				t.Errorf("body status = %v, want %s", body["status"], want)
+			}
+		})
+	}
+}
+
+func TestHandler_UnknownModePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an unknown mode")
+		}
+	}()
+	health.Handler(health.Service(nil), "startup")
+}
@@ -0,0 +1,241 @@
+package health
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/primadi/lokstra/common/utils"
+	"github.com/primadi/lokstra/core/request"
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
+)
+
+const SERVICE_TYPE = "health"
+
+const DEFAULT_TIMEOUT = 5 * time.Second
+
+// CheckOptions configures a single health check
+type CheckOptions struct {
+	// Critical checks turn the report down when failing,
+	// non-critical checks only degrade it
+	Critical bool
+	// Interval between runs, the last result is reused in between
+	// (0 = run on every Check)
+	Interval time.Duration
+	// Timeout of a single run (default: DEFAULT_TIMEOUT)
+	Timeout time.Duration
+}
+
+type Config struct {
+	// AutoDiscover adds a check for every instantiated service implementing
+	// serviceapi.HealthChecker, named after the service
+	AutoDiscover bool
+	// Default options of discovered checks
+	Default CheckOptions
+	// Options of specific discovered services (by service name)
+	Services map[string]CheckOptions
+	// Exclude these services from discovery
+	Exclude []string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		AutoDiscover: true,
+		Default:      CheckOptions{Critical: true, Timeout: DEFAULT_TIMEOUT},
+	}
+}
+
+type check struct {
+	name string
+	fn   func(ctx context.Context) error
+	opts CheckOptions
+
+	mu   sync.Mutex
+	last *serviceapi.HealthCheckResult
+}
+
+// Health aggregates explicit and discovered health checks
+type Health struct {
+	cfg *Config
+
+	mu     sync.RWMutex
+	checks map[string]*check
+}
+
+var _ serviceapi.Health = (*Health)(nil)
+
+// AddCheck registers (or replaces) a named check
+func (h *Health) AddCheck(name string, fn func(ctx context.Context) error, opts CheckOptions) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DEFAULT_TIMEOUT
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = &check{name: name, fn: fn, opts: opts}
+}
+
+// Check implements [serviceapi.Health].
+// Checks run concurrently; a failing critical check makes the report down,
+// a failing non-critical check makes it degraded.
+func (h *Health) Check(ctx context.Context) *serviceapi.HealthReport {
+	if h.cfg.AutoDiscover {
+		h.discover()
+	}
+
+	h.mu.RLock()
+	checks := make([]*check, 0, len(h.checks))
+	for _, c := range h.checks {
+		checks = append(checks, c)
+	}
+	h.mu.RUnlock()
+
+	results := make([]serviceapi.HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Go(func() { results[i] = c.run(ctx) })
+	}
+	wg.Wait()
+
+	slices.SortFunc(results, func(a, b serviceapi.HealthCheckResult) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	report := &serviceapi.HealthReport{Status: serviceapi.HEALTH_UP, Checks: results}
+	for _, r := range results {
+		if r.Status != serviceapi.HEALTH_DOWN {
+			continue
+		}
+		if r.Critical {
+			report.Status = serviceapi.HEALTH_DOWN
+			break
+		}
+		report.Status = serviceapi.HEALTH_DEGRADED
+	}
+	return report
+}
+
+// Handler serves the full health report, see [Handler] for other modes
+func (h *Health) Handler() request.HandlerFunc {
+	return Handler(h, MODE_OVERALL)
+}
+
+// discover adds checks for instantiated services implementing
+// serviceapi.HealthChecker (lazy services appear after their first access)
+func (h *Health) discover() {
+	for _, name := range lokstra_registry.GetServiceNames() {
+		if slices.Contains(h.cfg.Exclude, name) {
+			continue
+		}
+		h.mu.RLock()
+		_, exists := h.checks[name]
+		h.mu.RUnlock()
+		if exists {
+			continue
+		}
+
+		svc, ok := lokstra_registry.GetServiceAny(name)
+		if !ok || svc == h {
+			continue
+		}
+		checker, ok := svc.(serviceapi.HealthChecker)
+		if !ok {
+			continue
+		}
+
+		opts, ok := h.cfg.Services[name]
+		if !ok {
+			opts = h.cfg.Default
+		}
+		h.AddCheck(name, checker.HealthCheck, opts)
+	}
+}
+
+func (c *check) run(ctx context.Context) serviceapi.HealthCheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && c.opts.Interval > 0 && time.Since(c.last.CheckedAt) < c.opts.Interval {
+		return *c.last
+	}
+
+	start := time.Now()
+	err := c.call(ctx)
+	result := serviceapi.HealthCheckResult{
+		Name:      c.name,
+		Status:    serviceapi.HEALTH_UP,
+		Critical:  c.opts.Critical,
+		Duration:  time.Since(start),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = serviceapi.HEALTH_DOWN
+		result.Error = err.Error()
+	}
+	c.last = &result
+	return result
+}
+
+// call runs the check with its timeout, converting a panic into an error
+func (c *check) call(ctx context.Context) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("health check panicked: %v", r)
+		}
+	}()
+	return c.fn(ctx)
+}
+
+// Service creates a health service
+func Service(cfg *Config) *Health {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return &Health{cfg: cfg, checks: make(map[string]*check)}
+}
+
+// ServiceFactory creates a health service.
+//
+// Params:
+//   - auto_discover: check services implementing HealthCheck(ctx) error (default: true)
+//   - critical: default criticality of discovered checks (default: true)
+//   - interval: default interval between runs (default: 0, every Check)
+//   - timeout: default timeout of a run (default: 5s)
+//   - services: per-service options, e.g. {cache: {critical: false, interval: 30s}}
+//   - exclude: service names excluded from discovery
+func ServiceFactory(params map[string]any) any {
+	def := DefaultConfig()
+	cfg := &Config{
+		AutoDiscover: utils.GetValueFromMap(params, "auto_discover", def.AutoDiscover),
+		Default:      parseCheckOptions(params, def.Default),
+		Services:     make(map[string]CheckOptions),
+	}
+	for name, raw := range utils.GetValueFromMap(params, "services", map[string]any{}) {
+		if opts, ok := raw.(map[string]any); ok {
+			cfg.Services[name] = parseCheckOptions(opts, cfg.Default)
+		}
+	}
+	for _, name := range utils.GetValueFromMap(params, "exclude", []any{}) {
+		if s, ok := name.(string); ok {
+			cfg.Exclude = append(cfg.Exclude, s)
+		}
+	}
+	return Service(cfg)
+}
+
+func parseCheckOptions(params map[string]any, def CheckOptions) CheckOptions {
+	return CheckOptions{
+		Critical: utils.GetValueFromMap(params, "critical", def.Critical),
+		Interval: utils.GetValueFromMap(params, "interval", def.Interval),
+		Timeout:  utils.GetValueFromMap(params, "timeout", def.Timeout),
+	}
+}
+
+func Register() {
+	lokstra_registry.RegisterServiceType(SERVICE_TYPE, ServiceFactory)
+}
@@ -0,0 +1,105 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/lokstra_registry"
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/health"
+)
+
+type fakeDB struct {
+	err   error
+	calls atomic.Int32
+}
+
+func (d *fakeDB) HealthCheck(ctx context.Context) error {
+	d.calls.Add(1)
+	return d.err
+}
+
+func TestCheck_DiscoversServices(t *testing.T) {
+	db := &fakeDB{}
+	cache := &fakeDB{err: errors.New("connection refused")}
+	lokstra_registry.RegisterService("health-test-db", db)
+	lokstra_registry.RegisterService("health-test-cache", cache)
+	lokstra_registry.RegisterService("health-test-plain", struct{}{})
+
+	svc := health.ServiceFactory(map[string]any{
+		"services": map[string]any{
+			"health-test-cache": map[string]any{"critical": false},
+		},
+	}).(*health.Health)
+
+	report := svc.Check(context.Background())
+
+	results := map[string]serviceapi.HealthCheckResult{}
+	for _, r := range report.Checks {
+		results[r.Name] = r
+	}
+	if r, ok := results["health-test-db"]; !ok || r.Status != serviceapi.HEALTH_UP || !r.Critical {
+		t.Errorf("Expected critical db check up, got %+v", r)
+	}
+	if r, ok := results["health-test-cache"]; !ok || r.Status != serviceapi.HEALTH_DOWN || r.Critical ||
+		r.Error != "connection refused" {
+		t.Errorf("Expected non-critical cache check down, got %+v", r)
+	}
+	if _, ok := results["health-test-plain"]; ok {
+		t.Errorf("Service without HealthCheck must not be discovered")
+	}
+	if report.Status != serviceapi.HEALTH_DEGRADED {
+		t.Errorf("Expected degraded (non-critical failure), got %s", report.Status)
+	}
+
+	// A critical failure takes the report down
+	db.err = errors.New("timeout")
+	if report := svc.Check(context.Background()); report.Status != serviceapi.HEALTH_DOWN {
+		t.Errorf("Expected down (critical failure), got %s", report.Status)
+	}
+
+	w := httptest.NewRecorder()
+	svc.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 from handler, got %d", w.Code)
+	}
+}
+
+func TestCheck_Interval(t *testing.T) {
+	svc := health.Service(&health.Config{})
+	db := &fakeDB{}
+	svc.AddCheck("db", db.HealthCheck, health.CheckOptions{Critical: true, Interval: time.Hour})
+
+	for range 3 {
+		svc.Check(context.Background())
+	}
+	if calls := db.calls.Load(); calls != 1 {
+		t.Errorf("Expected cached result within interval (1 call), got %d calls", calls)
+	}
+}
+
+func TestCheck_TimeoutAndPanic(t *testing.T) {
+	svc := health.Service(&health.Config{})
+	svc.AddCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, health.CheckOptions{Timeout: 10 * time.Millisecond})
+	svc.AddCheck("broken", func(ctx context.Context) error {
+		panic("boom")
+	}, health.CheckOptions{})
+
+	report := svc.Check(context.Background())
+	for _, r := range report.Checks {
+		if r.Status != serviceapi.HEALTH_DOWN {
+			t.Errorf("Expected %s down, got %+v", r.Name, r)
+		}
+	}
+	if report.Status != serviceapi.HEALTH_DEGRADED {
+		t.Errorf("Expected degraded, got %s", report.Status)
+	}
+}
@@ -3,9 +3,11 @@ package kvstore_inmemory
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/primadi/lokstra/common/codec"
 	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/lokstra_registry"
 	"github.com/primadi/lokstra/serviceapi"
@@ -24,12 +26,13 @@ var (
 )
 
 type kvEntry struct {
-	value     any
+	value     []byte
 	expiresAt *time.Time
 }
 
 type kvRepositoryInMemory struct {
 	prefix string
+	codec  codec.Codec
 }
 
 func checkCleanUp() {
@@ -94,7 +97,9 @@ func (k *kvRepositoryInMemory) Get(ctx context.Context, key string, dest any) er
 	if entry.expiresAt != nil && time.Now().After(*entry.expiresAt) {
 		return ErrKeyNotFound
 	}
-	dest = entry.value
+	if err := k.codec.Unmarshal(entry.value, dest); err != nil {
+		return fmt.Errorf("unmarshal key %q: %w", key, err)
+	}
 	return nil
 }
 
@@ -137,6 +142,12 @@ func (k *kvRepositoryInMemory) Keys(ctx context.Context, pattern string) ([]stri
 
 // Set implements [serviceapi.KvRepository].
 func (k *kvRepositoryInMemory) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	// Values are stored encoded, so callers never share mutable state
+	b, err := k.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal key %q: %w", key, err)
+	}
+
 	mu.Lock()
 	var expiresAt *time.Time
 	if ttl > 0 {
@@ -144,7 +155,7 @@ func (k *kvRepositoryInMemory) Set(ctx context.Context, key string, value any, t
 		expiresAt = &t
 	}
 	data[k.prefixKey(key)] = kvEntry{
-		value:     value,
+		value:     b,
 		expiresAt: expiresAt,
 	}
 	mu.Unlock()
@@ -160,17 +171,28 @@ func (k *kvRepositoryInMemory) SetPrefix(prefix string) {
 
 var _ serviceapi.KvRepository = (*kvRepositoryInMemory)(nil)
 
-// creates a new instance of kvRepositoryInMemory service.
+// creates a new instance of kvRepositoryInMemory service (gob codec: values
+// never leave the Go process, so exact Go types win over portability).
 func Service(prefix string) *kvRepositoryInMemory {
+	return ServiceWithCodec(prefix, codec.Gob)
+}
+
+// creates a new instance of kvRepositoryInMemory service using c for values.
+func ServiceWithCodec(prefix string, c codec.Codec) *kvRepositoryInMemory {
 	return &kvRepositoryInMemory{
 		prefix: prefix,
+		codec:  c,
 	}
 }
 
 // the factory function for kvRepositoryInMemory service.
 func ServiceFactory(config map[string]any) any {
 	prefix := utils.GetValueFromMap(config, "prefix", "")
-	return Service(prefix)
+	c, err := codec.Get(utils.GetValueFromMap(config, "codec", codec.Gob.Name()))
+	if err != nil {
+		panic("kvstore_inmemory: " + err.Error())
+	}
+	return ServiceWithCodec(prefix, c)
 }
 
 // registers the kvRepositoryInMemory service type.
@@ -0,0 +1,122 @@
+package kvstore_inmemory_test
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primadi/lokstra/common/codec"
+	"github.com/primadi/lokstra/serviceapi"
+	"github.com/primadi/lokstra/services/kvstore/kvstore_inmemory"
+)
+
+type Address struct {
+	City string
+	Zip  string
+}
+
+type Session struct {
+	ID        string
+	UserID    int64
+	Roles     []string
+	Address   *Address
+	Attrs     map[string]string
+	ExpiresAt time.Time
+}
+
+func TestKvStore_CodecRoundTrip(t *testing.T) {
+	// Any third-party format plugs in by name (cbor, xml, ...)
+	codec.Register(codec.New("xml", xml.Marshal, xml.Unmarshal))
+
+	in := Session{
+		ID:        "s-1",
+		UserID:    42,
+		Roles:     []string{"admin", "support"},
+		Address:   &Address{City: "Jakarta", Zip: "10110"},
+		Attrs:     map[string]string{"theme": "dark"},
+		ExpiresAt: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	for _, name := range []string{"gob", "json", "msgpack"} {
+		t.Run(name, func(t *testing.T) {
+			var store serviceapi.KvRepository = kvstore_inmemory.ServiceFactory(map[string]any{
+				"prefix": "codec-" + name,
+				"codec":  name,
+			}).(serviceapi.KvRepository)
+
+			ctx := context.Background()
+			if err := store.Set(ctx, "session", in, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+
+			var out Session
+			if err := store.Get(ctx, "session", &out); err != nil {
+				t.Fatal(err)
+			}
+			// Same instant; codecs may restore UTC as a distinct *Location
+			if !out.ExpiresAt.Equal(in.ExpiresAt) {
+				t.Errorf("Expected ExpiresAt %v, got %v", in.ExpiresAt, out.ExpiresAt)
+			}
+			out.ExpiresAt = in.ExpiresAt
+			if !reflect.DeepEqual(in, out) {
+				t.Errorf("Expected %+v, got %+v", in, out)
+			}
+
+			// Stored values are copies
+			out.Roles[0] = "guest"
+			var again Session
+			_ = store.Get(ctx, "session", &again)
+			if again.Roles[0] != "admin" {
+				t.Errorf("Stored value was mutated through a previous Get")
+			}
+		})
+	}
+
+	t.Run("registered", func(t *testing.T) {
+		c, err := codec.Get("xml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := kvstore_inmemory.ServiceWithCodec("codec-xml", c)
+
+		in := Address{City: "Bandung", Zip: "40111"}
+		if err := store.Set(context.Background(), "addr", in, 0); err != nil {
+			t.Fatal(err)
+		}
+		var out Address
+		if err := store.Get(context.Background(), "addr", &out); err != nil || out != in {
+			t.Errorf("Expected %+v, got %+v (%v)", in, out, err)
+		}
+	})
+}
+
+func TestKvStore_UnknownCodec(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), `codec "bogus" is not registered`) {
+			t.Errorf("Expected the factory to fail on an unknown codec, got %v", r)
+		}
+	}()
+	kvstore_inmemory.ServiceFactory(map[string]any{"prefix": "codec-bogus", "codec": "bogus"})
+}
+
+func TestKvStore_DefaultCodecIsGob(t *testing.T) {
+	for name, store := range map[string]serviceapi.KvRepository{
+		"Service":        kvstore_inmemory.Service("codec-default"),
+		"ServiceFactory": kvstore_inmemory.ServiceFactory(map[string]any{"prefix": "codec-default"}).(serviceapi.KvRepository),
+	} {
+		ctx := context.Background()
+		if err := store.Set(ctx, "item", map[string]any{"count": 3}, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		// gob keeps Go types; json would decode the number as float64
+		var out map[string]any
+		if err := store.Get(ctx, "item", &out); err != nil || out["count"] != 3 {
+			t.Errorf("%s: expected the gob codec, got %#v (%v)", name, out, err)
+		}
+	}
+}
@@ -2,12 +2,12 @@ package kvstore_redis
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/primadi/lokstra/common/codec"
 	"github.com/primadi/lokstra/common/utils"
 	"github.com/primadi/lokstra/lokstra_registry"
 	"github.com/primadi/lokstra/serviceapi"
@@ -30,11 +30,15 @@ type Config struct {
 	DB       int    `json:"db" yaml:"db"`             // database number
 	PoolSize int    `json:"pool_size" yaml:"pool_size"`
 	Prefix   string `json:"prefix" yaml:"prefix"` // key prefix for namespacing
+	// value serialization: "json" (default, cross-language), "gob" (Go-only)
+	// or a name registered with codec.Register
+	Codec string `json:"codec" yaml:"codec"`
 }
 
 type kvRepositoryRedis struct {
 	client *redis.Client
 	prefix string
+	codec  codec.Codec
 }
 
 var _ serviceapi.KvRepository = (*kvRepositoryRedis)(nil)
@@ -57,9 +61,9 @@ func (k *kvRepositoryRedis) prefixKey(key string) string {
 }
 
 func (k *kvRepositoryRedis) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
-	data, err := json.Marshal(value)
+	data, err := k.codec.Marshal(value)
 	if err != nil {
-		return err
+		return fmt.Errorf("marshal key %q: %w", key, err)
 	}
 	return k.client.Set(ctx, k.prefixKey(key), data, ttl).Err()
 }
@@ -72,7 +76,7 @@ func (k *kvRepositoryRedis) Get(ctx context.Context, key string, dest any) error
 		}
 		return fmt.Errorf("redis get %q: %w", key, err)
 	}
-	if err := json.Unmarshal(data, dest); err != nil {
+	if err := k.codec.Unmarshal(data, dest); err != nil {
 		return fmt.Errorf("unmarshal key %q: %w", key, err)
 	}
 	return nil
@@ -122,8 +126,21 @@ func (k *kvRepositoryRedis) Shutdown() error {
 }
 
 func Service(cfg *Config) *kvRepositoryRedis {
+	codecName := cfg.Codec
+	if codecName == "" {
+		codecName = codec.JSON.Name()
+	}
+	c, err := codec.Get(codecName)
+	if err != nil {
+		panic("kvstore_redis: " + err.Error())
+	}
+
+	// Stores with different codecs share the connection pool
+	poolKey := *cfg
+	poolKey.Codec = ""
+
 	mu.Lock()
-	client, exists := poolClient[*cfg]
+	client, exists := poolClient[poolKey]
 	if !exists {
 		client = redis.NewClient(&redis.Options{
 			Addr:     cfg.Addr,
@@ -131,13 +148,14 @@ func Service(cfg *Config) *kvRepositoryRedis {
 			DB:       cfg.DB,
 			PoolSize: cfg.PoolSize,
 		})
-		poolClient[*cfg] = client
+		poolClient[poolKey] = client
 	}
 	mu.Unlock()
 
 	return &kvRepositoryRedis{
 		client: client,
 		prefix: cfg.Prefix,
+		codec:  c,
 	}
 }
 
@@ -148,6 +166,7 @@ func ServiceFactory(params map[string]any) any {
 		DB:       utils.GetValueFromMap(params, "db", 0),
 		PoolSize: utils.GetValueFromMap(params, "pool_size", 10),
 		Prefix:   utils.GetValueFromMap(params, "prefix", "kv"),
+		Codec:    utils.GetValueFromMap(params, "codec", codec.JSON.Name()),
 	}
 	return Service(cfg)
 }
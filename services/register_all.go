@@ -8,6 +8,8 @@ import (
 
 	"github.com/primadi/lokstra/services/dbpool_pg"
 	"github.com/primadi/lokstra/services/email_smtp"
+	"github.com/primadi/lokstra/services/featureflag"
+	"github.com/primadi/lokstra/services/health"
 	"github.com/primadi/lokstra/services/kvstore/kvstore_inmemory"
 	"github.com/primadi/lokstra/services/kvstore/kvstore_redis"
 	"github.com/primadi/lokstra/services/metrics_prometheus"
@@ -23,5 +25,7 @@ func RegisterAllServices() {
 	metrics_prometheus.Register()
 	dbpool_pg.Register()
 	email_smtp.Register()
+	featureflag.Register()
+	health.Register()
 	sync_config_pg.Register("db_main", 5*time.Minute, 5*time.Second)
 }